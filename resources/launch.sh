@@ -8,6 +8,20 @@ ENABLE_LOGGING=0
 PAK_DIR="$(dirname "$0")"
 cd "$PAK_DIR" || exit 1
 
+# Install an update downloaded by Update Theme Manager. If copying fails halfway the
+# update stays staged and is tried again on the next launch. The new launch.sh must not
+# be written over this one while sh is reading it, so it is renamed into place (sh keeps
+# the old file open) and started afresh once everything else is copied
+if [ -f .update/theme-manager ]; then
+    if [ -f .update/launch.sh ]; then
+        mv -f .update/launch.sh .launch.sh.new
+    fi
+    if cp -R .update/. . && rm -rf .update && [ -f .launch.sh.new ]; then
+        mv -f .launch.sh.new launch.sh
+        exec sh ./launch.sh "$@"
+    fi
+fi
+
 if [ "$ENABLE_LOGGING" -eq 1 ]; then
     # Log environment
     echo "PAK directory: $PAK_DIR" > launch.log
@@ -0,0 +1,150 @@
+// src/cmd/theme-manager/cli.go
+// Commands that run a single operation without the UI, for scripts, SSH and testing on device
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// commandUsage lists the commands runCommand understands
+const commandUsage = `Usage: theme-manager [flags] <command>
+
+Commands:
+  apply <name>   apply an installed theme, e.g. "apply Retro.theme"
+  apply --dry-run <name>
+                 list the files applying the theme would create, overwrite or remove
+  export         export the current setup as a theme and print where it went
+  export --allow-restricted
+                 export even if applied components' licenses forbid sharing them
+  sync           sync the theme catalog
+  convert <package> json|yaml
+                 write a package's manifest as manifest.json or manifest.yaml
+`
+
+// runCommand runs the command in args and returns the exit status: 0 when it worked,
+// 1 when it failed and 2 when the command line is wrong. Ctrl+C stops an apply or export
+// between files, the same as the cancel button.
+func runCommand(args []string) int {
+	if err := ui.SetFrontend(ui.HeadlessFrontend); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	logging.LogDebug("Running command: %v", args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := themes.EnsureThemeDirectoryStructure(); err != nil {
+		logging.LogDebug("Warning: Could not create theme directories: %v", err)
+	}
+
+	var err error
+	switch args[0] {
+	case "apply":
+		params := args[1:]
+		dryRun := len(params) > 0 && params[0] == "--dry-run"
+		if dryRun {
+			params = params[1:]
+		}
+		if len(params) != 1 {
+			fmt.Fprint(os.Stderr, commandUsage)
+			return 2
+		}
+		themeName := params[0]
+
+		if dryRun {
+			var preview *themes.ApplyPreview
+			preview, err = themes.PreviewApply(filepath.Join("Themes", themeName))
+			if err == nil {
+				fmt.Println(themes.FormatApplyPreview(preview))
+			}
+			break
+		}
+
+		err = themes.RunApplyWithProgressContext(ctx, fmt.Sprintf("Applying theme '%s'...", themeName), func(ctx context.Context) error {
+			return themes.RunStrict(func() error {
+				return themes.ImportThemeContext(ctx, themeName)
+			})
+		})
+		if err == nil {
+			fmt.Printf("Theme '%s' applied successfully!\n", themeName)
+		}
+
+	case "export":
+		allowRestricted := len(args) == 2 && args[1] == "--allow-restricted"
+		if len(args) != 1 && !allowRestricted {
+			fmt.Fprint(os.Stderr, commandUsage)
+			return 2
+		}
+
+		// There's no one to ask, so restricted components need the flag before anything is written
+		if restricted := themes.RestrictedExportLicenses(); len(restricted) > 0 && !allowRestricted {
+			fmt.Fprintf(os.Stderr, "These components may not be redistributed:\n  %s\nRun \"export --allow-restricted\" to export anyway\n",
+				strings.Join(restricted, "\n  "))
+			return 1
+		}
+
+		var packagePath string
+		err = themes.RunStrict(func() error {
+			var exportErr error
+			packagePath, exportErr = themes.ExportThemeContext(ctx)
+			return exportErr
+		})
+		if err == nil {
+			fmt.Println(packagePath)
+		}
+
+	case "sync":
+		if len(args) != 1 {
+			fmt.Fprint(os.Stderr, commandUsage)
+			return 2
+		}
+		options := themes.GetDefaultSyncOptions()
+		options.UI = false
+		err = themes.SyncThemeCatalogContext(ctx, options)
+		if err == nil {
+			fmt.Println("Theme catalog sync completed successfully!")
+		}
+
+	case "convert":
+		if len(args) != 3 {
+			fmt.Fprint(os.Stderr, commandUsage)
+			return 2
+		}
+		err = themes.ConvertManifest(args[1], args[2])
+		if err == nil {
+			fmt.Printf("Converted the manifest of %s to %s\n", args[1], args[2])
+		}
+
+	case "help":
+		fmt.Print(commandUsage)
+		return 0
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command '%s'\n\n%s", args[0], commandUsage)
+		return 2
+	}
+
+	if err != nil {
+		logging.LogDebug("Command %s failed: %v", args[0], err)
+		if errors.Is(err, context.Canceled) || errors.Is(err, ui.ErrCancelled) {
+			fmt.Fprintln(os.Stderr, "Cancelled.")
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return 1
+	}
+	return 0
+}
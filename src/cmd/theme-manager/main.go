@@ -4,17 +4,24 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	_ "github.com/UncleJunVIP/certifiable"
 	"nextui-themes/internal/app"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
 	"nextui-themes/internal/ui/screens"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 )
 
+// startProfiling starts the profiles requested on the command line. It does nothing
+// unless the binary was built with -tags pprof.
+var startProfiling = func() {}
+
 func main() {
 	// Recover from panics
 	defer func() {
@@ -43,6 +50,120 @@ func main() {
 	logging.LogDebug("Application started")
 	logging.SetLoggerInitialized() // Explicitly mark logger as initialized
 
+	// --strict lets theme authors certify packages without changing the saved setting
+	strict := flag.Bool("strict", false, "treat import and export warnings as errors")
+	lint := flag.String("lint", "", "check a package's manifest, print a fix list and exit")
+	lintFormat := flag.String("lint-format", "text", "format of the --lint report: text, or json for CI")
+	timings := flag.Bool("timings", false, "log how long each phase of an apply takes")
+	logFormat := flag.String("log-format", "", "log format for this session: text, json or both")
+	rotateSlideshows := flag.Bool("rotate-slideshows", false, "show the next wallpaper of per-boot slideshows and exit")
+	checkSeasonal := flag.Bool("check-seasonal", false, "apply or revert seasonal themes for today and exit")
+	batteryLEDs := flag.Bool("battery-leds", false, "set the applied LED pack's colors for the battery level and exit")
+	frontend := flag.String("ui", os.Getenv("THEME_MANAGER_UI"), "front-end drawing the screens: "+strings.Join(ui.Frontends(), ", "))
+	watchCharging := flag.Bool("watch-charging", false, "switch the applied LED pack's charging profile on and off as the device is plugged in")
+	flag.Parse()
+	startProfiling()
+	if *timings {
+		themes.EnableApplyTimings()
+	}
+	if *strict {
+		logging.LogDebug("Strict mode enabled from the command line")
+		themes.EnableStrictMode()
+	}
+
+	// JSON log lines are for analysis tools, the flag overrides the saved setting
+	if *logFormat != "" {
+		if err := logging.SetLogFormat(*logFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	} else {
+		themes.ApplyLogFormatSetting()
+	}
+	themes.ApplyAccessibleUISetting()
+
+	// Another front-end can be picked for this session, e.g. while one is being developed
+	if *frontend != "" {
+		if err := ui.SetFrontend(*frontend); err != nil {
+			logging.LogDebug("Error picking front-end: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	// --lint runs without the UI so authors can check packages from a shell
+	if *lint != "" {
+		report, err := themes.BuildLintReport(*lint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(2)
+		}
+		switch *lintFormat {
+		case "text":
+			fmt.Print(themes.FormatLintReport(report.Issues))
+		case "json":
+			output, err := themes.FormatLintReportJSON(report)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(2)
+			}
+			fmt.Print(output)
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown lint format %q, use text or json\n", *lintFormat)
+			os.Exit(2)
+		}
+		if !report.Passed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --rotate-slideshows runs from NextUI's auto.sh at boot, before any UI is up
+	if *rotateSlideshows {
+		if err := themes.RotateSlideshowsOnBoot(); err != nil {
+			logging.LogDebug("Error rotating slideshows: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --check-seasonal also runs from auto.sh, so seasonal themes change without opening the pak
+	if *checkSeasonal {
+		if err := themes.CheckSeasonalThemes(); err != nil {
+			logging.LogDebug("Error checking seasonal themes: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --battery-leds runs from auto.sh so NextUI starts with the LED colors for the charge left
+	if *batteryLEDs {
+		if err := themes.UpdateBatteryLEDs(); err != nil {
+			logging.LogDebug("Error updating battery LEDs: %v", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// --watch-charging runs in the background from auto.sh for packs with a charging profile
+	if *watchCharging {
+		if err := themes.WatchChargingLEDs(); err != nil {
+			logging.LogDebug("Error watching charging state: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// A command on the command line runs without the UI, e.g. "theme-manager sync" over SSH
+	if flag.NArg() > 0 {
+		status := runCommand(flag.Args())
+		logging.CloseLogger()
+		os.Exit(status)
+	}
+
 	// Get current directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -77,6 +198,15 @@ func main() {
 		logging.LogDebug("Warning: Could not create theme directories: %v", err)
 	}
 
+	// Undo an accent preview that was interrupted by a crash or power loss
+	themes.RestoreAccentPreview()
+
+	// Apply or revert seasonal themes whose dates came up since the last boot
+	screens.RunSeasonalThemes()
+
+	// NextUI updates often overwrite fonts and settings, offer to put them back
+	screens.OfferReapplyAfterSystemUpdate()
+
 	logging.LogDebug("Starting main loop")
 
 	// Main application loop
@@ -90,7 +220,7 @@ func main() {
 		logging.LogDebug("Current screen: %d", currentScreen)
 
 		// New check:
-		if currentScreen < app.Screens.MainMenu || currentScreen > app.Screens.OverlaySystemSelection {
+		if currentScreen < app.Screens.MainMenu || currentScreen > app.Screens.CatalogSearch {
 			logging.LogDebug("CRITICAL ERROR: Invalid screen value: %d, resetting to MainMenu", currentScreen)
 			app.SetCurrentScreen(app.Screens.MainMenu)
 			continue
@@ -182,6 +312,171 @@ func main() {
 			selection, exitCode = screens.OverlaySystemSelectionScreen()
 			nextScreen = screens.HandleOverlaySystemSelection(selection, exitCode)
 
+		case app.Screens.ThemeTags:
+			logging.LogDebug("Showing theme tags screen")
+			selection, exitCode = screens.ThemeTagsScreen()
+			nextScreen = screens.HandleThemeTags(selection, exitCode)
+
+		case app.Screens.ThemesByTag:
+			logging.LogDebug("Showing themes by tag screen")
+			selection, exitCode = screens.ThemesByTagScreen()
+			nextScreen = screens.HandleThemesByTag(selection, exitCode)
+
+		case app.Screens.ThemeTagEdit:
+			logging.LogDebug("Showing theme tag edit screen")
+			selection, exitCode = screens.ThemeTagEditScreen()
+			nextScreen = screens.HandleThemeTagEdit(selection, exitCode)
+
+		case app.Screens.SettingsMenu:
+			logging.LogDebug("Showing settings menu screen")
+			selection, exitCode = screens.SettingsMenuScreen()
+			nextScreen = screens.HandleSettingsMenu(selection, exitCode)
+
+		case app.Screens.PinnedFiles:
+			logging.LogDebug("Showing pinned files screen")
+			selection, exitCode = screens.PinnedFilesScreen()
+			nextScreen = screens.HandlePinnedFiles(selection, exitCode)
+
+		case app.Screens.ExcludedSystems:
+			logging.LogDebug("Showing excluded systems screen")
+			selection, exitCode = screens.ExcludedSystemsScreen()
+			nextScreen = screens.HandleExcludedSystems(selection, exitCode)
+
+		case app.Screens.ListScrim:
+			logging.LogDebug("Showing list dimming screen")
+			selection, exitCode = screens.ListScrimScreen()
+			nextScreen = screens.HandleListScrim(selection, exitCode)
+
+		case app.Screens.LintPackages:
+			logging.LogDebug("Showing lint packages screen")
+			selection, exitCode = screens.LintPackagesScreen()
+			nextScreen = screens.HandleLintPackages(selection, exitCode)
+
+		case app.Screens.ImportFolder:
+			logging.LogDebug("Showing import from folder screen")
+			selection, exitCode = screens.ImportFolderScreen()
+			nextScreen = screens.HandleImportFolder(selection, exitCode)
+
+		case app.Screens.ThemeStats:
+			logging.LogDebug("Showing theme stats screen")
+			selection, exitCode = screens.ThemeStatsScreen()
+			nextScreen = screens.HandleThemeStats(selection, exitCode)
+
+		case app.Screens.OverlayExportSystems:
+			logging.LogDebug("Showing overlay export systems screen")
+			selection, exitCode = screens.OverlayExportSystemsScreen()
+			nextScreen = screens.HandleOverlayExportSystems(selection, exitCode)
+
+		case app.Screens.OverlayVariants:
+			logging.LogDebug("Showing overlay variants screen")
+			selection, exitCode = screens.OverlayVariantsScreen()
+			nextScreen = screens.HandleOverlayVariants(selection, exitCode)
+
+		case app.Screens.SubmitPackage:
+			logging.LogDebug("Showing submit package screen")
+			selection, exitCode = screens.SubmitPackageScreen()
+			nextScreen = screens.HandleSubmitPackage(selection, exitCode)
+
+		case app.Screens.ImportURL:
+			logging.LogDebug("Showing Import packages from links in urls.txt screen")
+			selection, exitCode = screens.ImportURLScreen()
+			nextScreen = screens.HandleImportURL(selection, exitCode)
+
+		case app.Screens.WorkspaceMenu:
+			logging.LogDebug("Showing theme workspace menu screen")
+			selection, exitCode = screens.WorkspaceMenuScreen()
+			nextScreen = screens.HandleWorkspaceMenu(selection, exitCode)
+
+		case app.Screens.WorkspaceSlots:
+			logging.LogDebug("Showing workspace file selection screen")
+			selection, exitCode = screens.WorkspaceSlotsScreen()
+			nextScreen = screens.HandleWorkspaceSlots(selection, exitCode)
+
+		case app.Screens.WorkspaceSource:
+			logging.LogDebug("Showing workspace swap source selection screen")
+			selection, exitCode = screens.WorkspaceSourceScreen()
+			nextScreen = screens.HandleWorkspaceSource(selection, exitCode)
+
+		case app.Screens.PackageVersions:
+			logging.LogDebug("Showing package versions screen")
+			selection, exitCode = screens.PackageVersionsScreen()
+			nextScreen = screens.HandlePackageVersions(selection, exitCode)
+
+		case app.Screens.VersionList:
+			logging.LogDebug("Showing version list screen")
+			selection, exitCode = screens.VersionListScreen()
+			nextScreen = screens.HandleVersionList(selection, exitCode)
+
+		case app.Screens.SettingsSnapshot:
+			logging.LogDebug("Showing settings snapshot screen")
+			selection, exitCode = screens.SettingsSnapshotScreen()
+			nextScreen = screens.HandleSettingsSnapshot(selection, exitCode)
+
+		case app.Screens.About:
+			logging.LogDebug("Showing about screen")
+			selection, exitCode = screens.AboutScreen()
+			nextScreen = screens.HandleAbout(selection, exitCode)
+
+		case app.Screens.AuditTrail:
+			logging.LogDebug("Showing audit trail screen")
+			selection, exitCode = screens.AuditTrailScreen()
+			nextScreen = screens.HandleAuditTrail(selection, exitCode)
+
+		case app.Screens.CollectionSelection:
+			logging.LogDebug("Showing collection selection screen")
+			selection, exitCode = screens.CollectionSelectionScreen()
+			nextScreen = screens.HandleCollectionSelection(selection, exitCode)
+
+		case app.Screens.SeasonalThemes:
+			logging.LogDebug("Showing seasonal themes screen")
+			selection, exitCode = screens.SeasonalThemesScreen()
+			nextScreen = screens.HandleSeasonalThemes(selection, exitCode)
+
+		case app.Screens.IconSubset:
+			logging.LogDebug("Showing icon subset screen")
+			selection, exitCode = screens.IconSubsetScreen()
+			nextScreen = screens.HandleIconSubset(selection, exitCode)
+
+		case app.Screens.IconRemap:
+			logging.LogDebug("Showing icon remap screen")
+			selection, exitCode = screens.IconRemapScreen()
+			nextScreen = screens.HandleIconRemap(selection, exitCode)
+
+		case app.Screens.FontSubset:
+			logging.LogDebug("Showing font subset screen")
+			selection, exitCode = screens.FontSubsetScreen()
+			nextScreen = screens.HandleFontSubset(selection, exitCode)
+
+		case app.Screens.Systems:
+			logging.LogDebug("Showing systems screen")
+			selection, exitCode = screens.SystemsScreen()
+			nextScreen = screens.HandleSystems(selection, exitCode)
+
+		case app.Screens.StorageRoots:
+			logging.LogDebug("Showing storage roots screen")
+			selection, exitCode = screens.StorageRootsScreen()
+			nextScreen = screens.HandleStorageRoots(selection, exitCode)
+
+		case app.Screens.QuickWallpapers:
+			logging.LogDebug("Showing quick wallpapers screen")
+			selection, exitCode = screens.QuickWallpapersScreen()
+			nextScreen = screens.HandleQuickWallpapers(selection, exitCode)
+
+		case app.Screens.QuickWallpaperSource:
+			logging.LogDebug("Showing quick wallpaper source screen")
+			selection, exitCode = screens.QuickWallpaperSourceScreen()
+			nextScreen = screens.HandleQuickWallpaperSource(selection, exitCode)
+
+		case app.Screens.ImageEffects:
+			logging.LogDebug("Showing image effects screen")
+			selection, exitCode = screens.ImageEffectsScreen()
+			nextScreen = screens.HandleImageEffects(selection, exitCode)
+
+		case app.Screens.CatalogSearch:
+			logging.LogDebug("Showing catalog search screen")
+			selection, exitCode = screens.CatalogSearchScreen()
+			nextScreen = screens.HandleCatalogSearch(selection, exitCode)
+
 		default:
 			logging.LogDebug("Unknown screen type: %d, defaulting to MainMenu", currentScreen)
 			nextScreen = app.Screens.MainMenu
@@ -191,7 +486,7 @@ func main() {
 		logging.LogDebug("Current screen: %d, Next screen: %d", currentScreen, nextScreen)
 
 		// New validation logic that includes OverlaySystemSelection:
-		if nextScreen < app.Screens.MainMenu || nextScreen > app.Screens.OverlaySystemSelection {
+		if nextScreen < app.Screens.MainMenu || nextScreen > app.Screens.CatalogSearch {
 			logging.LogDebug("ERROR: Invalid next screen value: %d, defaulting to MainMenu", nextScreen)
 			nextScreen = app.Screens.MainMenu
 		}
@@ -0,0 +1,57 @@
+// src/cmd/theme-manager/pprof.go
+// CPU and heap profiling for chasing slowness on real devices, only in -tags pprof builds
+
+//go:build pprof
+
+package main
+
+import (
+	"flag"
+	"os"
+	"runtime"
+	"runtime/pprof"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+)
+
+var (
+	cpuProfile = flag.String("cpuprofile", "", "write a CPU profile of the session to this file")
+	memProfile = flag.String("memprofile", "", "write a heap profile to this file on exit")
+)
+
+func init() {
+	startProfiling = func() {
+		if *cpuProfile != "" {
+			file, err := os.Create(*cpuProfile)
+			if err != nil {
+				logging.LogDebug("Warning: Could not create CPU profile: %v", err)
+			} else if err := pprof.StartCPUProfile(file); err != nil {
+				logging.LogDebug("Warning: Could not start CPU profile: %v", err)
+				file.Close()
+			} else {
+				logging.LogDebug("Writing CPU profile to %s", *cpuProfile)
+				app.OnExit(func() {
+					pprof.StopCPUProfile()
+					file.Close()
+				})
+			}
+		}
+
+		if *memProfile != "" {
+			app.OnExit(func() {
+				file, err := os.Create(*memProfile)
+				if err != nil {
+					logging.LogDebug("Warning: Could not create heap profile: %v", err)
+					return
+				}
+				defer file.Close()
+
+				runtime.GC()
+				if err := pprof.WriteHeapProfile(file); err != nil {
+					logging.LogDebug("Warning: Could not write heap profile: %v", err)
+				}
+			})
+		}
+	}
+}
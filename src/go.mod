@@ -5,6 +5,8 @@ go 1.24.2
 require (
 	github.com/UncleJunVIP/certifiable v1.0.0
 	github.com/go-git/go-git/v5 v5.11.0
+	golang.org/x/text v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -11,6 +11,22 @@ import (
 	"nextui-themes/internal/themes"
 )
 
+// exitHooks run right before the application exits
+var exitHooks []func()
+
+// OnExit registers a function to run when the application exits through Exit
+func OnExit(hook func()) {
+	exitHooks = append(exitHooks, hook)
+}
+
+// Exit runs the registered exit hooks and ends the process
+func Exit(code int) {
+	for _, hook := range exitHooks {
+		hook()
+	}
+	os.Exit(code)
+}
+
 // Initialize sets up the application
 func Initialize() error {
 	// Initialize app state
@@ -26,8 +42,13 @@ func Initialize() error {
 	// Set up environment variables for the TrimUI brick
 	logging.LogDebug("Setting environment variables")
 
-	_ = os.Setenv("DEVICE", "brick")
-	_ = os.Setenv("PLATFORM", "tg5040")
+	// Keep the device and platform NextUI launched us with
+	if os.Getenv("DEVICE") == "" {
+		_ = os.Setenv("DEVICE", "brick")
+	}
+	if os.Getenv("PLATFORM") == "" {
+		_ = os.Setenv("PLATFORM", "tg5040")
+	}
 
 	// Add current directory to PATH instead of replacing it
 	existingPath := os.Getenv("PATH")
@@ -28,6 +28,39 @@ const (
 	Deconstruction
 	DeconstructConfirm
 	OverlaySystemSelection // New screen for system tag selection
+	ThemeTags              // Browse themes by tag
+	ThemesByTag
+	ThemeTagEdit
+	SettingsMenu
+	PinnedFiles
+	ExcludedSystems
+	ListScrim
+	LintPackages
+	ImportFolder
+	ThemeStats
+	OverlayExportSystems
+	OverlayVariants
+	SubmitPackage
+	ImportURL
+	WorkspaceMenu
+	WorkspaceSlots
+	WorkspaceSource
+	PackageVersions
+	VersionList
+	SettingsSnapshot
+	About
+	AuditTrail
+	CollectionSelection
+	SeasonalThemes
+	IconSubset
+	IconRemap
+	FontSubset
+	Systems
+	StorageRoots
+	QuickWallpapers
+	QuickWallpaperSource
+	ImageEffects
+	CatalogSearch
 )
 
 // ScreenEnum holds all available screens
@@ -48,16 +81,61 @@ type ScreenEnum struct {
 	Deconstruction         Screen
 	DeconstructConfirm     Screen
 	OverlaySystemSelection Screen // New screen for system tag selection
-
+	ThemeTags              Screen
+	ThemesByTag            Screen
+	ThemeTagEdit           Screen
+	SettingsMenu           Screen
+	PinnedFiles            Screen
+	ExcludedSystems        Screen
+	ListScrim              Screen
+	LintPackages           Screen
+	ImportFolder           Screen
+	ThemeStats             Screen
+	OverlayExportSystems   Screen
+	OverlayVariants        Screen
+	SubmitPackage          Screen
+	ImportURL              Screen
+	WorkspaceMenu          Screen
+	WorkspaceSlots         Screen
+	WorkspaceSource        Screen
+	PackageVersions        Screen
+	VersionList            Screen
+	SettingsSnapshot       Screen
+	About                  Screen
+	AuditTrail             Screen
+	CollectionSelection    Screen
+	SeasonalThemes         Screen
+	IconSubset             Screen
+	IconRemap              Screen
+	FontSubset             Screen
+	Systems                Screen
+	StorageRoots           Screen
+	QuickWallpapers        Screen
+	QuickWallpaperSource   Screen
+	ImageEffects           Screen
+	CatalogSearch          Screen
 }
 
 // AppState holds the current state of the application
 type appState struct {
 	CurrentScreen           Screen
-	SelectedTheme           string // For theme import/export
-	SelectedComponentType   string // For component operations
-	SelectedComponentOption string // For component operations
-	SelectedSystemTag       string // New field for system tag selection
+	SelectedTheme           string   // For theme import/export
+	SelectedComponentType   string   // For component operations
+	SelectedComponentOption string   // For component operations
+	SelectedSystemTag       string   // New field for system tag selection
+	SelectedTag             string   // Theme tag used for tag browsing
+	SelectedExportSystems   []string // System tags picked for an overlay export
+	SelectedWorkspaceSlot   string   // Workspace file picked for swapping
+	SelectedVersionPackage  string   // Library path of the package whose versions are shown
+	SelectedCollection      string   // Collection picked for a collection bundle export
+	SelectedIconPack        string   // Icon pack being extracted from or remapped
+	SelectedSubsetIcons     []string // System icons picked for extraction
+	SelectedWallpaperSlot   string   // Wallpaper slot picked for a quick swap
+	SelectedEffectsPack     string   // Component package whose image effects are edited
+	CatalogFilter           string   // Which catalog themes the download gallery shows
+	CatalogQuery            string   // Text the download gallery is narrowed to
+	CatalogContent          string   // Content filter the download gallery is narrowed to
+	GenerateListWallpapers  bool     // Whether the next apply makes the list wallpapers a theme lacks
 }
 
 // Global variables
@@ -79,6 +157,39 @@ var (
 		Deconstruction:         Deconstruction,
 		DeconstructConfirm:     DeconstructConfirm,
 		OverlaySystemSelection: OverlaySystemSelection, // Add new screen
+		ThemeTags:              ThemeTags,
+		ThemesByTag:            ThemesByTag,
+		ThemeTagEdit:           ThemeTagEdit,
+		SettingsMenu:           SettingsMenu,
+		PinnedFiles:            PinnedFiles,
+		ExcludedSystems:        ExcludedSystems,
+		ListScrim:              ListScrim,
+		LintPackages:           LintPackages,
+		ImportFolder:           ImportFolder,
+		ThemeStats:             ThemeStats,
+		OverlayExportSystems:   OverlayExportSystems,
+		OverlayVariants:        OverlayVariants,
+		SubmitPackage:          SubmitPackage,
+		ImportURL:              ImportURL,
+		WorkspaceMenu:          WorkspaceMenu,
+		WorkspaceSlots:         WorkspaceSlots,
+		WorkspaceSource:        WorkspaceSource,
+		PackageVersions:        PackageVersions,
+		VersionList:            VersionList,
+		SettingsSnapshot:       SettingsSnapshot,
+		About:                  About,
+		AuditTrail:             AuditTrail,
+		CollectionSelection:    CollectionSelection,
+		SeasonalThemes:         SeasonalThemes,
+		IconSubset:             IconSubset,
+		IconRemap:              IconRemap,
+		FontSubset:             FontSubset,
+		Systems:                Systems,
+		StorageRoots:           StorageRoots,
+		QuickWallpapers:        QuickWallpapers,
+		QuickWallpaperSource:   QuickWallpaperSource,
+		ImageEffects:           ImageEffects,
+		CatalogSearch:          CatalogSearch,
 	}
 
 	state appState
@@ -87,7 +198,7 @@ var (
 // Replace with:
 func GetCurrentScreen() Screen {
 	// Ensure we never return an invalid screen value
-	if state.CurrentScreen < MainMenu || state.CurrentScreen > OverlaySystemSelection {
+	if state.CurrentScreen < MainMenu || state.CurrentScreen > CatalogSearch {
 		logging.LogDebug("WARNING: Invalid current screen value: %d, defaulting to MainMenu", state.CurrentScreen)
 		state.CurrentScreen = MainMenu
 	}
@@ -97,7 +208,7 @@ func GetCurrentScreen() Screen {
 // Replace with:
 func SetCurrentScreen(screen Screen) {
 	// Validate screen value before setting
-	if screen < MainMenu || screen > OverlaySystemSelection {
+	if screen < MainMenu || screen > CatalogSearch {
 		logging.LogDebug("WARNING: Attempted to set invalid screen value: %d, using MainMenu instead", screen)
 		screen = MainMenu
 	}
@@ -152,3 +263,151 @@ func GetSelectedSystemTag() string {
 func SetSelectedSystemTag(tag string) {
 	state.SelectedSystemTag = tag
 }
+
+// GetSelectedTag returns the theme tag selected for browsing
+func GetSelectedTag() string {
+	return state.SelectedTag
+}
+
+// SetSelectedTag sets the theme tag selected for browsing
+func SetSelectedTag(tag string) {
+	state.SelectedTag = tag
+}
+
+// GetSelectedExportSystems returns the system tags picked for an overlay export
+func GetSelectedExportSystems() []string {
+	return state.SelectedExportSystems
+}
+
+// ToggleSelectedExportSystem adds or removes a system tag from the overlay export selection
+func ToggleSelectedExportSystem(tag string) {
+	for i, selected := range state.SelectedExportSystems {
+		if selected == tag {
+			state.SelectedExportSystems = append(state.SelectedExportSystems[:i], state.SelectedExportSystems[i+1:]...)
+			return
+		}
+	}
+	state.SelectedExportSystems = append(state.SelectedExportSystems, tag)
+}
+
+// ClearSelectedExportSystems empties the overlay export selection
+func ClearSelectedExportSystems() {
+	state.SelectedExportSystems = nil
+}
+
+// GetSelectedWorkspaceSlot returns the workspace file picked for swapping
+func GetSelectedWorkspaceSlot() string {
+	return state.SelectedWorkspaceSlot
+}
+
+// SetSelectedWorkspaceSlot sets the workspace file picked for swapping
+func SetSelectedWorkspaceSlot(slot string) {
+	state.SelectedWorkspaceSlot = slot
+}
+
+// GetSelectedVersionPackage returns the library path of the package whose versions are shown
+func GetSelectedVersionPackage() string {
+	return state.SelectedVersionPackage
+}
+
+// SetSelectedVersionPackage sets the package whose versions are shown, e.g. "Themes/Retro.theme"
+func SetSelectedVersionPackage(packagePath string) {
+	state.SelectedVersionPackage = packagePath
+}
+
+// GetSelectedCollection returns the collection picked for a collection bundle export
+func GetSelectedCollection() string {
+	return state.SelectedCollection
+}
+
+// SetSelectedCollection sets the collection picked for a collection bundle export
+func SetSelectedCollection(collection string) {
+	state.SelectedCollection = collection
+}
+
+// GetSelectedIconPack returns the icon pack being extracted from or remapped
+func GetSelectedIconPack() string {
+	return state.SelectedIconPack
+}
+
+// SetSelectedIconPack sets the icon pack to extract from or remap and clears the icon selection
+func SetSelectedIconPack(pack string) {
+	state.SelectedIconPack = pack
+	state.SelectedSubsetIcons = nil
+}
+
+// GetSelectedSubsetIcons returns the system icons picked for extraction
+func GetSelectedSubsetIcons() []string {
+	return state.SelectedSubsetIcons
+}
+
+// ToggleSelectedSubsetIcon adds or removes a system icon from the extraction selection
+func ToggleSelectedSubsetIcon(icon string) {
+	for i, selected := range state.SelectedSubsetIcons {
+		if selected == icon {
+			state.SelectedSubsetIcons = append(state.SelectedSubsetIcons[:i], state.SelectedSubsetIcons[i+1:]...)
+			return
+		}
+	}
+	state.SelectedSubsetIcons = append(state.SelectedSubsetIcons, icon)
+}
+
+// GetSelectedWallpaperSlot returns the wallpaper slot picked for a quick swap
+func GetSelectedWallpaperSlot() string {
+	return state.SelectedWallpaperSlot
+}
+
+// SetSelectedWallpaperSlot sets the wallpaper slot picked for a quick swap
+func SetSelectedWallpaperSlot(slot string) {
+	state.SelectedWallpaperSlot = slot
+}
+
+// GetSelectedEffectsPack returns the component package whose image effects are edited
+func GetSelectedEffectsPack() string {
+	return state.SelectedEffectsPack
+}
+
+// SetSelectedEffectsPack sets the component package whose image effects are edited
+func SetSelectedEffectsPack(pack string) {
+	state.SelectedEffectsPack = pack
+}
+
+// GetCatalogFilter returns which catalog themes the download gallery shows
+func GetCatalogFilter() string {
+	return state.CatalogFilter
+}
+
+// SetCatalogFilter sets which catalog themes the download gallery shows
+func SetCatalogFilter(filter string) {
+	state.CatalogFilter = filter
+}
+
+// GetCatalogQuery returns the text the download gallery is narrowed to
+func GetCatalogQuery() string {
+	return state.CatalogQuery
+}
+
+// SetCatalogQuery sets the text the download gallery is narrowed to, "" for no search
+func SetCatalogQuery(query string) {
+	state.CatalogQuery = query
+}
+
+// GetCatalogContent returns the content filter the download gallery is narrowed to
+func GetCatalogContent() string {
+	return state.CatalogContent
+}
+
+// SetCatalogContent sets the content filter the download gallery is narrowed to
+func SetCatalogContent(filter string) {
+	state.CatalogContent = filter
+}
+
+// GetGenerateListWallpapers returns whether the next apply makes the list wallpapers a theme lacks
+func GetGenerateListWallpapers() bool {
+	return state.GenerateListWallpapers
+}
+
+// SetGenerateListWallpapers sets whether the next apply makes the list wallpapers a theme lacks
+func SetGenerateListWallpapers(generate bool) {
+	state.GenerateListWallpapers = generate
+}
@@ -12,6 +12,9 @@ import (
 
 var logFile *os.File
 
+// logsDir is the folder the log files are written to
+var logsDir string
+
 // Enables or disables logging functionality
 var LoggingEnabled bool = true // Set to false to disable all logging
 
@@ -30,7 +33,7 @@ func init() {
 	}
 
 	// Create Logs directory if it doesn't exist
-	logsDir := filepath.Join(cwd, "Logs")
+	logsDir = filepath.Join(cwd, "Logs")
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating Logs directory: %v\n", err)
 		return
@@ -62,17 +65,28 @@ func CloseLogger() {
 
 	LogDebug("=== Theme Manager Closed ===")
 	logFile.Close()
+	closeJSONLog()
 }
 
 // LogDebug logs a debug message
 func LogDebug(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+
+	// Strict mode needs warnings even when file logging is off
+	captureWarning(message)
+
 	// If logging is disabled or log file isn't initialized, return immediately
 	if !LoggingEnabled || logFile == nil {
 		return
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	message := fmt.Sprintf(format, args...)
+	now := time.Now()
+	writeJSONLog(now, message, args)
+	if !textLogEnabled() {
+		return
+	}
+
+	timestamp := now.Format("2006-01-02 15:04:05")
 	logLine := fmt.Sprintf("[%s] %s\n", timestamp, message)
 
 	logFile.WriteString(logLine)
@@ -0,0 +1,38 @@
+// src/internal/logging/memory.go
+// Memory usage logging for large file operations
+
+package logging
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// procStatusValue returns a field such as "VmHWM" from /proc/self/status, or "unknown"
+func procStatusValue(field string) string {
+	file, err := os.Open("/proc/self/status")
+	if err != nil {
+		return "unknown"
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), field+":"); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return "unknown"
+}
+
+// LogMemoryUsage logs the heap in use and the peak resident memory of the process,
+// so operations that come close to the device's memory limit show up in the log
+func LogMemoryUsage(operation string) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	LogDebug("Memory after %s: heap in use %d KB, peak RSS %s",
+		operation, stats.HeapInuse/1024, procStatusValue("VmHWM"))
+}
@@ -0,0 +1,161 @@
+// src/internal/logging/structured.go
+// Optional JSON log lines with the operation, package, file and error of each message
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Log formats
+const (
+	LogFormatText = "text" // Only the printf-style theme_manager.log (the default)
+	LogFormatJSON = "json" // Only JSON lines in theme_manager.jsonl
+	LogFormatBoth = "both" // Both files
+)
+
+// LogFormats are the formats offered in settings, in cycling order
+var LogFormats = []string{LogFormatText, LogFormatJSON, LogFormatBoth}
+
+// jsonLogName is the JSON lines file written next to theme_manager.log
+const jsonLogName = "theme_manager.jsonl"
+
+// structuredLog holds the JSON log file and what the app is working on, added to every line
+var structuredLog struct {
+	mu        sync.Mutex
+	format    string
+	file      *os.File
+	operation string
+	pkg       string
+	current   string // File being processed
+}
+
+// jsonLogLine is one line of theme_manager.jsonl
+type jsonLogLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Operation string `json:"operation,omitempty"`
+	Package   string `json:"package,omitempty"`
+	File      string `json:"file,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Message   string `json:"message"`
+}
+
+// SetLogFormat switches the log format for this session, opening the JSON log when needed
+func SetLogFormat(format string) error {
+	if format != LogFormatText && format != LogFormatJSON && format != LogFormatBoth {
+		return fmt.Errorf("unknown log format: %s", format)
+	}
+
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+
+	if format != LogFormatText && structuredLog.file == nil && logsDir != "" {
+		path := filepath.Join(logsDir, jsonLogName)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("error opening JSON log: %w", err)
+		}
+		structuredLog.file = f
+	}
+
+	structuredLog.format = format
+	return nil
+}
+
+// textLogEnabled reports whether messages go to theme_manager.log
+func textLogEnabled() bool {
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+	return structuredLog.format != LogFormatJSON
+}
+
+// BeginOperation tags the following log lines with an operation, such as "apply theme", and
+// the package it works on. The returned function ends the operation.
+func BeginOperation(operation, pkg string) func() {
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+
+	previousOperation, previousPkg := structuredLog.operation, structuredLog.pkg
+	structuredLog.operation = operation
+	structuredLog.pkg = pkg
+	structuredLog.current = ""
+
+	return func() {
+		structuredLog.mu.Lock()
+		defer structuredLog.mu.Unlock()
+
+		structuredLog.operation = previousOperation
+		structuredLog.pkg = previousPkg
+		structuredLog.current = ""
+	}
+}
+
+// SetCurrentFile tags the following log lines with the file being processed
+func SetCurrentFile(file string) {
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+	structuredLog.current = file
+}
+
+// logLevel classifies a message by the prefixes used throughout the app
+func logLevel(message string) string {
+	switch {
+	case strings.HasPrefix(message, "Warning"):
+		return "warning"
+	case strings.HasPrefix(message, "Error"),
+		strings.HasPrefix(message, "CRITICAL"),
+		strings.HasPrefix(message, "PANIC"):
+		return "error"
+	default:
+		return "debug"
+	}
+}
+
+// writeJSONLog writes a message to the JSON log, taking the error from the message arguments
+func writeJSONLog(now time.Time, message string, args []interface{}) {
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+
+	if structuredLog.file == nil || structuredLog.format == LogFormatText {
+		return
+	}
+
+	line := jsonLogLine{
+		Time:      now.Format(time.RFC3339),
+		Level:     logLevel(message),
+		Operation: structuredLog.operation,
+		Package:   structuredLog.pkg,
+		File:      structuredLog.current,
+		Message:   message,
+	}
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && err != nil {
+			line.Error = err.Error()
+			break
+		}
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return
+	}
+	structuredLog.file.Write(append(data, '\n'))
+}
+
+// closeJSONLog closes the JSON log if it was opened
+func closeJSONLog() {
+	structuredLog.mu.Lock()
+	defer structuredLog.mu.Unlock()
+
+	if structuredLog.file != nil {
+		structuredLog.file.Close()
+		structuredLog.file = nil
+	}
+}
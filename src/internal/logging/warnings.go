@@ -0,0 +1,77 @@
+// src/internal/logging/warnings.go
+// Captures warnings logged during an operation so strict mode can fail on them
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// warningCapture holds the warnings logged since BeginWarningCapture
+var warningCapture struct {
+	mu       sync.Mutex
+	active   bool
+	warnings []string
+}
+
+// BeginWarningCapture starts recording every "Warning:" message passed to LogDebug
+func BeginWarningCapture() {
+	warningCapture.mu.Lock()
+	defer warningCapture.mu.Unlock()
+
+	warningCapture.active = true
+	warningCapture.warnings = nil
+}
+
+// EndWarningCapture stops recording and returns the captured warnings, each prefixed with
+// the package and file being processed when it was logged
+func EndWarningCapture() []string {
+	warningCapture.mu.Lock()
+	defer warningCapture.mu.Unlock()
+
+	warnings := warningCapture.warnings
+	warningCapture.active = false
+	warningCapture.warnings = nil
+	return warnings
+}
+
+// FirstCapturedWarning returns the first warning recorded by the running capture, or "" when
+// nothing has warned yet, so an operation can stop before writing anything more
+func FirstCapturedWarning() string {
+	warningCapture.mu.Lock()
+	defer warningCapture.mu.Unlock()
+
+	if len(warningCapture.warnings) == 0 {
+		return ""
+	}
+	return warningCapture.warnings[0]
+}
+
+// captureWarning records a message if it is a warning and a capture is running
+func captureWarning(message string) {
+	if !strings.HasPrefix(message, "Warning") {
+		return
+	}
+
+	// Name what the theme author can fix: the package and the file in it
+	structuredLog.mu.Lock()
+	pkg, file := structuredLog.pkg, structuredLog.current
+	structuredLog.mu.Unlock()
+
+	warningCapture.mu.Lock()
+	defer warningCapture.mu.Unlock()
+
+	if !warningCapture.active {
+		return
+	}
+
+	switch {
+	case pkg != "" && file != "":
+		message = fmt.Sprintf("%s: %s: %s", pkg, file, message)
+	case pkg != "" || file != "":
+		message = fmt.Sprintf("%s%s: %s", pkg, file, message)
+	}
+	warningCapture.warnings = append(warningCapture.warnings, message)
+}
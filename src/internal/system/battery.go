@@ -0,0 +1,58 @@
+// src/internal/system/battery.go
+// Battery level detection from the power supply sysfs
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// powerSupplyPath is where the kernel exposes batteries and chargers
+const powerSupplyPath = "/sys/class/power_supply"
+
+// BatteryStatus is the charge level and charging state of the device's battery
+type BatteryStatus struct {
+	Percent  int
+	Charging bool
+}
+
+// readSysfsValue reads a single trimmed sysfs attribute
+func readSysfsValue(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// GetBatteryStatus returns the state of the first battery found in sysfs
+func GetBatteryStatus() (*BatteryStatus, error) {
+	supplies, err := os.ReadDir(powerSupplyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading power supplies: %w", err)
+	}
+
+	for _, supply := range supplies {
+		supplyPath := filepath.Join(powerSupplyPath, supply.Name())
+		if readSysfsValue(filepath.Join(supplyPath, "type")) != "Battery" {
+			continue
+		}
+
+		percent, err := strconv.Atoi(readSysfsValue(filepath.Join(supplyPath, "capacity")))
+		if err != nil {
+			continue
+		}
+
+		status := readSysfsValue(filepath.Join(supplyPath, "status"))
+		return &BatteryStatus{
+			Percent:  percent,
+			Charging: status == "Charging" || status == "Full",
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no battery found in %s", powerSupplyPath)
+}
@@ -16,35 +16,134 @@ type SystemInfo struct {
 	Tag       string // Just the tag (e.g., "GBA")
 	Path      string // Full path to the system directory
 	MediaPath string // Path to the .media directory
+	IconPath  string // Path to the system's icon, in the .media directory of its Roms folder
+	Root      string // Storage root the system is on
+}
+
+// MediaFolder is a folder outside the standard locations that has its own .media directory,
+// such as a Tools subfolder or a custom top-level category
+type MediaFolder struct {
+	Name      string // Path relative to the SD card root, e.g. "Tools/tg5040/Emulators"
+	Path      string // Full path to the folder
+	MediaPath string // Path to the .media directory
 }
 
 // SystemPaths contains paths for standard system directories
 type SystemPaths struct {
 	Root           string
+	Platform       string // Platform folder name, e.g. "tg5040"
 	RecentlyPlayed string
-	Tools          string
+	Tools          string // Tools/<platform>, which may not exist yet on a fresh card
 	Roms           string
+	RomsDirs       []string // Roms folder of every storage root, the SD card's first
 	Systems        []SystemInfo
+	MediaFolders   []MediaFolder
+}
+
+// mediaFolderDepth is how deep below the SD card root media folders are looked for
+const mediaFolderDepth = 3
+
+// storageRoots are extra roots with their own Roms folder, such as a second SD card
+var storageRoots []string
+
+// SetStorageRoots sets the extra storage roots scanned for ROM systems besides the SD card
+func SetStorageRoots(roots []string) {
+	storageRoots = append([]string(nil), roots...)
+}
+
+// StorageRoots returns the extra storage roots scanned for ROM systems
+func StorageRoots() []string {
+	return append([]string(nil), storageRoots...)
+}
+
+// DefaultPlatform is the platform folder of the TrimUI Brick and Smart Pro, used when nothing
+// on the card points to another one
+const DefaultPlatform = "tg5040"
+
+// DetectPlatform returns the device's platform folder name, as in Tools/<platform>. It is taken
+// from the Tools folder Theme Manager runs from, then the PLATFORM variable NextUI sets, then
+// the only folder in Tools, and is DefaultPlatform on a card without any of these.
+func DetectPlatform(rootPath string) string {
+	toolsRoot := filepath.Join(rootPath, "Tools")
+
+	// Theme Manager runs from Tools/<platform>/Theme-Manager.pak
+	if cwd, err := os.Getwd(); err == nil {
+		platformDir := filepath.Dir(cwd)
+		if filepath.Dir(platformDir) == toolsRoot {
+			return filepath.Base(platformDir)
+		}
+	}
+
+	if platform := os.Getenv("PLATFORM"); platform != "" && !strings.ContainsAny(platform, `/\`) {
+		return platform
+	}
+
+	entries, err := os.ReadDir(toolsRoot)
+	if err == nil {
+		var folders []string
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				folders = append(folders, entry.Name())
+			}
+		}
+		if len(folders) == 1 {
+			return folders[0]
+		}
+	}
+
+	return DefaultPlatform
 }
 
 // GetSystemPaths returns the paths to all system directories
 func GetSystemPaths() (*SystemPaths, error) {
 	// Define base paths
 	rootPath := "/mnt/SDCARD"
+	platform := DetectPlatform(rootPath)
 	recentlyPath := filepath.Join(rootPath, "Recently Played")
-	toolsPath := filepath.Join(rootPath, "Tools/tg5040")
+	toolsPath := filepath.Join(rootPath, "Tools", platform)
 	romsPath := filepath.Join(rootPath, "Roms")
 
 	// Create the result structure
 	systemPaths := &SystemPaths{
 		Root:           rootPath,
+		Platform:       platform,
 		RecentlyPlayed: recentlyPath,
 		Tools:          toolsPath,
 		Roms:           romsPath,
+		RomsDirs:       []string{romsPath},
 		Systems:        []SystemInfo{},
 	}
 
 	// Scan for ROM system directories
+	systems, err := scanRomsDir(rootPath)
+	if err != nil {
+		return nil, err
+	}
+	systemPaths.Systems = append(systemPaths.Systems, systems...)
+
+	// Systems on other storage roots follow, so the SD card's folder wins for a shared tag
+	for _, root := range storageRoots {
+		root = filepath.Clean(root)
+		if root == rootPath {
+			continue
+		}
+		systems, err := scanRomsDir(root)
+		if err != nil {
+			// The card may simply not be inserted
+			continue
+		}
+		systemPaths.RomsDirs = append(systemPaths.RomsDirs, filepath.Join(root, "Roms"))
+		systemPaths.Systems = append(systemPaths.Systems, systems...)
+	}
+
+	systemPaths.MediaFolders = discoverMediaFolders(systemPaths)
+
+	return systemPaths, nil
+}
+
+// scanRomsDir returns the ROM systems in the Roms folder of a storage root
+func scanRomsDir(root string) ([]SystemInfo, error) {
+	romsPath := filepath.Join(root, "Roms")
 	romsDirs, err := os.ReadDir(romsPath)
 	if err != nil {
 		return nil, err
@@ -53,6 +152,7 @@ func GetSystemPaths() (*SystemPaths, error) {
 	// Regular expression to extract system tag from directory name
 	re := regexp.MustCompile(`\((.*?)\)`)
 
+	var systems []SystemInfo
 	for _, dir := range romsDirs {
 		if dir.IsDir() && dir.Name() != ".media" && !strings.HasPrefix(dir.Name(), ".") {
 			systemPath := filepath.Join(romsPath, dir.Name())
@@ -65,17 +165,71 @@ func GetSystemPaths() (*SystemPaths, error) {
 				tag = matches[1]
 			}
 
-			// Add to our list of systems
-			systemPaths.Systems = append(systemPaths.Systems, SystemInfo{
+			systems = append(systems, SystemInfo{
 				Name:      dir.Name(),
 				Tag:       tag,
 				Path:      systemPath,
 				MediaPath: mediaPath,
+				IconPath:  filepath.Join(romsPath, ".media", dir.Name()+".png"),
+				Root:      root,
 			})
 		}
 	}
+	return systems, nil
+}
 
-	return systemPaths, nil
+// discoverMediaFolders walks the SD card for folders with a .media directory that aren't one of
+// the standard locations. ROM systems and collections are skipped since they have their own
+// handling, as are hidden folders and paks.
+func discoverMediaFolders(paths *SystemPaths) []MediaFolder {
+	// Folders with their own wallpaper handling
+	standard := map[string]bool{
+		paths.RecentlyPlayed: true,
+		paths.Tools:          true,
+	}
+	skipped := map[string]bool{
+		paths.Roms:                               true,
+		filepath.Join(paths.Root, "Collections"): true,
+	}
+
+	var folders []MediaFolder
+	var walk func(dir string, depth int)
+	walk = func(dir string, depth int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".pak") {
+				continue
+			}
+
+			path := filepath.Join(dir, name)
+			if skipped[path] {
+				continue
+			}
+
+			if !standard[path] {
+				if info, err := os.Stat(filepath.Join(path, ".media")); err == nil && info.IsDir() {
+					rel, _ := filepath.Rel(paths.Root, path)
+					folders = append(folders, MediaFolder{
+						Name:      filepath.ToSlash(rel),
+						Path:      path,
+						MediaPath: filepath.Join(path, ".media"),
+					})
+				}
+			}
+
+			if depth < mediaFolderDepth {
+				walk(path, depth+1)
+			}
+		}
+	}
+	walk(paths.Root, 1)
+
+	return folders
 }
 
 // EnsureMediaDirectories ensures that all necessary .media directories exist
@@ -92,7 +246,7 @@ func EnsureMediaDirectories(paths *SystemPaths) error {
 		return err
 	}
 
-	// Ensure Tools .media directory
+	// Ensure Tools .media directory, which also creates the platform folder on a fresh card
 	toolsMediaPath := filepath.Join(paths.Tools, ".media")
 	if err := os.MkdirAll(toolsMediaPath, 0755); err != nil {
 		return err
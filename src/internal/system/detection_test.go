@@ -0,0 +1,67 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// makeDirs creates folders below root
+func makeDirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectPlatform(t *testing.T) {
+	tests := []struct {
+		name     string
+		dirs     []string
+		files    []string
+		platform string
+		want     string
+	}{
+		{"no Tools folder", nil, nil, "", DefaultPlatform},
+		{"empty Tools folder", []string{"Tools"}, nil, "", DefaultPlatform},
+		{"PLATFORM set", []string{"Tools/tg5040", "Tools/my355"}, nil, "my355", "my355"},
+		{"PLATFORM set without Tools", nil, nil, "rg35xxplus", "rg35xxplus"},
+		{"PLATFORM with a path", []string{"Tools/my355"}, nil, "../my355", "my355"},
+		{"one platform folder", []string{"Tools/my355"}, nil, "", "my355"},
+		{"hidden folders ignored", []string{"Tools/my355", "Tools/.cache"}, nil, "", "my355"},
+		{"files ignored", []string{"Tools/my355"}, []string{"Tools/notes.txt"}, "", "my355"},
+		{"several platform folders", []string{"Tools/tg5040", "Tools/my355"}, nil, "", DefaultPlatform},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			makeDirs(t, root, tt.dirs...)
+			for _, file := range tt.files {
+				if err := os.WriteFile(filepath.Join(root, file), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("PLATFORM", tt.platform)
+
+			if got := DetectPlatform(root); got != tt.want {
+				t.Errorf("DetectPlatform() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectPlatformFromWorkingDir(t *testing.T) {
+	root := t.TempDir()
+	makeDirs(t, root, "Tools/tg5040", "Tools/my355/Theme-Manager.pak")
+
+	// The folder Theme Manager runs from wins over PLATFORM
+	t.Setenv("PLATFORM", "tg5040")
+	t.Chdir(filepath.Join(root, "Tools", "my355", "Theme-Manager.pak"))
+
+	if got := DetectPlatform(root); got != "my355" {
+		t.Errorf("DetectPlatform() = %q, want %q", got, "my355")
+	}
+}
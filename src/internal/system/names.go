@@ -0,0 +1,65 @@
+// src/internal/system/names.go
+// Unicode-safe, case-insensitive matching of system folder names and tags
+
+package system
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeName returns name in Unicode normalization form C, so folder names written on
+// different systems compare equal byte for byte. macOS and some archivers write names
+// decomposed (e + U+0301 instead of é, ホ + U+3099 instead of ボ).
+func NormalizeName(name string) string {
+	// Nothing to compose in plain ASCII, which is nearly every name
+	if isASCII(name) {
+		return name
+	}
+	return norm.NFC.String(name)
+}
+
+// isASCII reports whether s contains only ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// NameKey returns the normalized, lower-cased form of a name for use as a lookup key
+func NameKey(name string) string {
+	return strings.ToLower(NormalizeName(name))
+}
+
+// SameName reports whether two folder names or system tags refer to the same thing,
+// ignoring case and Unicode composition
+func SameName(a, b string) bool {
+	return a == b || strings.EqualFold(NormalizeName(a), NormalizeName(b))
+}
+
+// SystemForTag returns the installed system whose folder carries the given tag
+func (p *SystemPaths) SystemForTag(tag string) (SystemInfo, bool) {
+	if tag == "" {
+		return SystemInfo{}, false
+	}
+
+	// Prefer an exact match in case two folders differ only in case
+	for _, system := range p.Systems {
+		if system.Tag == tag {
+			return system, true
+		}
+	}
+
+	for _, system := range p.Systems {
+		if SameName(system.Tag, tag) {
+			return system, true
+		}
+	}
+
+	return SystemInfo{}, false
+}
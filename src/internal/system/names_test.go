@@ -0,0 +1,126 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Folder names as written by Linux (composed) and by macOS or some archivers (decomposed)
+const (
+	pokemonComposed   = "Pokémon mini (PKM)"
+	pokemonDecomposed = "Poke\u0301mon mini (PKM)"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"ascii", "Game Boy Advance (GBA)", "Game Boy Advance (GBA)"},
+		{"composed", pokemonComposed, pokemonComposed},
+		{"decomposed", pokemonDecomposed, pokemonComposed},
+		{"uppercase base", "E\u0301CRAN", "ÉCRAN"},
+		{"several marks", "Sa\u0303o Jose\u0301", "São José"},
+		{"mark at start", "\u0301abc", "\u0301abc"},
+		{"mark without a composed form", "q\u0301", "q\u0301"},
+		{"marks out of order", "a\u0302\u0323", "ậ"},
+		{"vietnamese stacked marks", "Vie\u0302\u0323t Nam", "Việt Nam"},
+		{"decomposed kana", "\u30db\u3099\u30f3\u30cf\u3099\u30fc\u30de\u30f3 (FC)", "ボンバーマン (FC)"},
+		{"hangul jamo", "\u1100\u1161\u11a8", "각"},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeName(tt.in); got != tt.want {
+				t.Errorf("NormalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameKey(t *testing.T) {
+	want := "pokémon mini (pkm)"
+	for _, in := range []string{
+		pokemonComposed,
+		pokemonDecomposed,
+		"POKÉMON MINI (PKM)",
+		"POKE\u0301MON MINI (PKM)",
+	} {
+		if got := NameKey(in); got != want {
+			t.Errorf("NameKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSameName(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{pokemonComposed, pokemonDecomposed, true},
+		{pokemonComposed, "pokémon MINI (pkm)", true},
+		{pokemonDecomposed, "POKÉMON MINI (PKM)", true},
+		{"GBA", "gba", true},
+		{"GBA", "GBC", false},
+		{pokemonComposed, "Pokemon mini (PKM)", false},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := SameName(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameName(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSystemForTagNonASCII(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{
+		"Game Boy Advance (GBA)",
+		pokemonDecomposed,
+		"Jeux Vide\u0301o (VIDE\u0301O)",
+		"Famicom (fc)",
+		"Famicom Disk (FC)",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, "Roms", name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	systems, err := scanRomsDir(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := &SystemPaths{Systems: systems}
+
+	tests := []struct {
+		tag, wantName string
+	}{
+		{"PKM", pokemonDecomposed},
+		{"pkm", pokemonDecomposed},
+		{"VIDÉO", "Jeux Vide\u0301o (VIDE\u0301O)"},
+		{"vidéo", "Jeux Vide\u0301o (VIDE\u0301O)"},
+		{"VIDE\u0301O", "Jeux Vide\u0301o (VIDE\u0301O)"},
+		// An exact match wins over one that only differs in case
+		{"FC", "Famicom Disk (FC)"},
+		{"fc", "Famicom (fc)"},
+	}
+
+	for _, tt := range tests {
+		system, ok := paths.SystemForTag(tt.tag)
+		if !ok {
+			t.Errorf("SystemForTag(%q) found nothing, want %q", tt.tag, tt.wantName)
+			continue
+		}
+		if system.Name != tt.wantName {
+			t.Errorf("SystemForTag(%q) = %q, want %q", tt.tag, system.Name, tt.wantName)
+		}
+	}
+
+	for _, tag := range []string{"", "VIDEO", "GBC"} {
+		if system, ok := paths.SystemForTag(tag); ok {
+			t.Errorf("SystemForTag(%q) = %q, want no match", tag, system.Name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// src/internal/system/screen.go
+// Resolution of the device's screen, which wallpapers and overlays are made for
+
+package system
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Screen of the TrimUI Brick, used when the device can't be told apart
+const (
+	DefaultScreenWidth  = 1024
+	DefaultScreenHeight = 768
+)
+
+// deviceScreens are the screens of the devices NextUI runs on, by the DEVICE variable it sets
+var deviceScreens = map[string][2]int{
+	"brick":    {1024, 768},
+	"smartpro": {1280, 720},
+}
+
+// framebufferModes lists the video modes of the first framebuffer, e.g. "U:1280x720p-60"
+const framebufferModes = "/sys/class/graphics/fb0/modes"
+
+// modeSize matches the resolution in a framebuffer mode line
+var modeSize = regexp.MustCompile(`(\d+)x(\d+)`)
+
+var screenSize struct {
+	once          sync.Once
+	width, height int
+}
+
+// ScreenSize returns the resolution of the device's screen. It is read from the framebuffer,
+// then from the DEVICE variable NextUI sets, and is the Brick's on a computer or a device
+// that reports neither.
+func ScreenSize() (int, int) {
+	screenSize.once.Do(func() {
+		screenSize.width, screenSize.height = detectScreenSize(framebufferModes, os.Getenv("DEVICE"))
+	})
+	return screenSize.width, screenSize.height
+}
+
+// detectScreenSize works out the screen from a framebuffer modes file and a device name
+func detectScreenSize(modesPath, device string) (int, int) {
+	if data, err := os.ReadFile(modesPath); err == nil {
+		line, _, _ := strings.Cut(string(data), "\n")
+		if match := modeSize.FindStringSubmatch(line); match != nil {
+			width, _ := strconv.Atoi(match[1])
+			height, _ := strconv.Atoi(match[2])
+			if width > 0 && height > 0 {
+				return width, height
+			}
+		}
+	}
+
+	if size, ok := deviceScreens[strings.ToLower(device)]; ok {
+		return size[0], size[1]
+	}
+	return DefaultScreenWidth, DefaultScreenHeight
+}
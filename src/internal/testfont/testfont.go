@@ -0,0 +1,486 @@
+// src/internal/testfont/testfont.go
+// Small TrueType fonts built in code for tests, as no font files ship with the source
+
+package testfont
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// Point is a point of a glyph outline in font units
+type Point struct {
+	X, Y    int16
+	OnCurve bool
+}
+
+// Box returns a rectangular contour of on-curve points
+func Box(x0, y0, x1, y1 int16) []Point {
+	return []Point{{x0, y0, true}, {x0, y1, true}, {x1, y1, true}, {x1, y0, true}}
+}
+
+// Component is a part of a composite glyph: another glyph, moved and optionally scaled
+type Component struct {
+	Glyph  uint16
+	DX, DY int16
+	Scale  float64 // 0 leaves the glyph at its size
+}
+
+// Glyph is a simple glyph when it has contours and a composite one when it has components.
+// A glyph with neither is empty, like a space.
+type Glyph struct {
+	Advance    uint16
+	Contours   [][]Point
+	Components []Component
+}
+
+// Font describes a font to build
+type Font struct {
+	Glyphs     []Glyph
+	Chars      map[rune]uint16
+	UnitsPerEm uint16
+	Ascent     int16
+	Descent    int16
+
+	LongLoca     bool // Write a long-format loca table
+	RangeOffsets bool // Map format 4 segments through the glyph id array instead of deltas
+	Format12     bool // Add a format 12 character map, the only one covering characters past U+FFFF
+
+	Extra map[string][]byte // Other tables, written as they are
+}
+
+// Glyphs of the Sample font
+const (
+	NotDef = iota
+	Space
+	LetterA
+	LetterO
+	AccentAcute
+	LetterAAcute
+	LetterEAcute
+	Gamepad
+	NumGlyphs
+)
+
+// Sample returns a font with a few glyphs covering the outline features fonts use: a glyph
+// with a hole, curves made only of off-curve points, composites and an empty glyph. It maps
+// ASCII, Latin-1, Cyrillic and an emoji past the BMP.
+func Sample() *Font {
+	return &Font{
+		UnitsPerEm: 1000,
+		Ascent:     800,
+		Descent:    -200,
+		Format12:   true,
+		Glyphs: []Glyph{
+			NotDef: {Advance: 500, Contours: [][]Point{
+				Box(50, 0, 450, 700),
+				{{100, 50, true}, {400, 50, true}, {400, 650, true}, {100, 650, true}},
+			}},
+			Space: {Advance: 250},
+			LetterA: {Advance: 600, Contours: [][]Point{
+				{{0, 0, true}, {300, 700, true}, {600, 0, true}, {450, 0, true}, {400, 150, true}, {200, 150, true}, {150, 0, true}},
+				{{350, 250, true}, {300, 400, false}, {250, 250, true}},
+			}},
+			LetterO: {Advance: 600, Contours: [][]Point{
+				{{300, 0, false}, {0, 350, false}, {300, 700, false}, {600, 350, false}},
+			}},
+			AccentAcute: {Advance: 0, Contours: [][]Point{
+				{{0, 0, true}, {100, 150, true}, {150, 150, true}, {50, 0, true}},
+			}},
+			LetterAAcute: {Advance: 600, Components: []Component{
+				{Glyph: LetterA},
+				{Glyph: AccentAcute, DX: 250, DY: 750},
+			}},
+			LetterEAcute: {Advance: 500, Components: []Component{
+				{Glyph: LetterO, Scale: 0.75},
+				{Glyph: AccentAcute, DX: 200, DY: 600},
+			}},
+			Gamepad: {Advance: 1000, Contours: [][]Point{Box(0, 100, 1000, 600)}},
+		},
+		Chars: map[rune]uint16{
+			' ':     Space,
+			'A':     LetterA,
+			'B':     LetterA,
+			'O':     LetterO,
+			'Á':     LetterAAcute,
+			'é':     LetterEAcute,
+			'Ж':     LetterA,
+			0x1F3AE: Gamepad,
+		},
+	}
+}
+
+// Bytes assembles the font file
+func (f *Font) Bytes() []byte {
+	tables := map[string][]byte{
+		"head": f.head(),
+		"hhea": f.hhea(),
+		"maxp": f.maxp(),
+		"hmtx": f.hmtx(),
+		"cmap": f.cmap(),
+	}
+	tables["glyf"], tables["loca"] = f.glyf()
+	for tag, table := range f.Extra {
+		tables[tag] = table
+	}
+	return Assemble(tables)
+}
+
+func (f *Font) head() []byte {
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint32(head, 0x00010000)
+	binary.BigEndian.PutUint32(head[12:], 0x5F0F3CF5)
+	binary.BigEndian.PutUint16(head[18:], f.UnitsPerEm)
+	binary.BigEndian.PutUint16(head[40:], uint16(f.Ascent))
+	if f.LongLoca {
+		binary.BigEndian.PutUint16(head[50:], 1)
+	}
+	return head
+}
+
+func (f *Font) hhea() []byte {
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint32(hhea, 0x00010000)
+	binary.BigEndian.PutUint16(hhea[4:], uint16(f.Ascent))
+	binary.BigEndian.PutUint16(hhea[6:], uint16(f.Descent))
+	binary.BigEndian.PutUint16(hhea[34:], uint16(len(f.Glyphs)))
+	return hhea
+}
+
+func (f *Font) maxp() []byte {
+	maxp := make([]byte, 6)
+	binary.BigEndian.PutUint32(maxp, 0x00005000)
+	binary.BigEndian.PutUint16(maxp[4:], uint16(len(f.Glyphs)))
+	return maxp
+}
+
+func (f *Font) hmtx() []byte {
+	hmtx := make([]byte, len(f.Glyphs)*4)
+	for i, g := range f.Glyphs {
+		binary.BigEndian.PutUint16(hmtx[i*4:], g.Advance)
+	}
+	return hmtx
+}
+
+// glyf returns the glyf and loca tables
+func (f *Font) glyf() ([]byte, []byte) {
+	var glyf []byte
+	offsets := make([]int, 0, len(f.Glyphs)+1)
+	for _, g := range f.Glyphs {
+		offsets = append(offsets, len(glyf))
+		glyf = append(glyf, EncodeGlyph(g)...)
+	}
+	offsets = append(offsets, len(glyf))
+
+	var loca []byte
+	for _, offset := range offsets {
+		if f.LongLoca {
+			loca = binary.BigEndian.AppendUint32(loca, uint32(offset))
+		} else {
+			loca = binary.BigEndian.AppendUint16(loca, uint16(offset/2))
+		}
+	}
+	return glyf, loca
+}
+
+// EncodeGlyph writes a glyph's glyf table entry, padded to 4 bytes. Coordinates use the
+// short and repeated forms where they fit, as font tools write them.
+func EncodeGlyph(g Glyph) []byte {
+	if len(g.Contours) == 0 && len(g.Components) == 0 {
+		return nil
+	}
+
+	var points []Point
+	for _, contour := range g.Contours {
+		points = append(points, contour...)
+	}
+	minX, minY, maxX, maxY := int16(math.MaxInt16), int16(math.MaxInt16), int16(math.MinInt16), int16(math.MinInt16)
+	for _, p := range points {
+		minX, minY = min(minX, p.X), min(minY, p.Y)
+		maxX, maxY = max(maxX, p.X), max(maxY, p.Y)
+	}
+	if len(points) == 0 {
+		minX, minY, maxX, maxY = 0, 0, 0, 0
+	}
+
+	numContours := int16(len(g.Contours))
+	if len(g.Components) > 0 {
+		numContours = -1
+	}
+	data := binary.BigEndian.AppendUint16(nil, uint16(numContours))
+	for _, v := range []int16{minX, minY, maxX, maxY} {
+		data = binary.BigEndian.AppendUint16(data, uint16(v))
+	}
+
+	if len(g.Components) > 0 {
+		data = append(data, encodeComponents(g.Components)...)
+	} else {
+		data = append(data, encodeContours(g.Contours, points)...)
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+	return data
+}
+
+func encodeContours(contours [][]Point, points []Point) []byte {
+	var data []byte
+	end := -1
+	for _, contour := range contours {
+		end += len(contour)
+		data = binary.BigEndian.AppendUint16(data, uint16(end))
+	}
+	data = binary.BigEndian.AppendUint16(data, 0) // No instructions
+
+	const (
+		onCurve    = 0x01
+		xShort     = 0x02
+		yShort     = 0x04
+		repeat     = 0x08
+		xSameOrPos = 0x10
+		ySameOrPos = 0x20
+	)
+	coordFlag := func(delta int, short, same byte) byte {
+		switch {
+		case delta == 0:
+			return same
+		case delta > 0 && delta < 256:
+			return short | same
+		case delta < 0 && delta > -256:
+			return short
+		}
+		return 0
+	}
+
+	flags := make([]byte, len(points))
+	var xs, ys []byte
+	prevX, prevY := 0, 0
+	for i, p := range points {
+		dx, dy := int(p.X)-prevX, int(p.Y)-prevY
+		prevX, prevY = int(p.X), int(p.Y)
+
+		flag := coordFlag(dx, xShort, xSameOrPos) | coordFlag(dy, yShort, ySameOrPos)
+		if p.OnCurve {
+			flag |= onCurve
+		}
+		flags[i] = flag
+
+		for _, c := range []struct {
+			delta int
+			short byte
+			out   *[]byte
+		}{{dx, xShort, &xs}, {dy, yShort, &ys}} {
+			switch {
+			case flag&c.short != 0:
+				*c.out = append(*c.out, byte(max(c.delta, -c.delta)))
+			case c.delta != 0:
+				*c.out = binary.BigEndian.AppendUint16(*c.out, uint16(int16(c.delta)))
+			}
+		}
+	}
+
+	// Runs of the same flag are written once with a repeat count
+	for i := 0; i < len(flags); {
+		run := 1
+		for i+run < len(flags) && flags[i+run] == flags[i] && run < 256 {
+			run++
+		}
+		if run > 1 {
+			data = append(data, flags[i]|repeat, byte(run-1))
+		} else {
+			data = append(data, flags[i])
+		}
+		i += run
+	}
+	data = append(data, xs...)
+	return append(data, ys...)
+}
+
+func encodeComponents(components []Component) []byte {
+	const (
+		argsAreWords    = 0x0001
+		argsAreXYValues = 0x0002
+		haveScale       = 0x0008
+		moreComponents  = 0x0020
+	)
+
+	var data []byte
+	for i, c := range components {
+		flags := uint16(argsAreXYValues)
+		wide := c.DX < -128 || c.DX > 127 || c.DY < -128 || c.DY > 127
+		if wide {
+			flags |= argsAreWords
+		}
+		if c.Scale != 0 {
+			flags |= haveScale
+		}
+		if i < len(components)-1 {
+			flags |= moreComponents
+		}
+
+		data = binary.BigEndian.AppendUint16(data, flags)
+		data = binary.BigEndian.AppendUint16(data, c.Glyph)
+		if wide {
+			data = binary.BigEndian.AppendUint16(data, uint16(c.DX))
+			data = binary.BigEndian.AppendUint16(data, uint16(c.DY))
+		} else {
+			data = append(data, byte(int8(c.DX)), byte(int8(c.DY)))
+		}
+		if c.Scale != 0 {
+			data = binary.BigEndian.AppendUint16(data, uint16(int16(math.Round(c.Scale*16384))))
+		}
+	}
+	return data
+}
+
+// cmap writes a format 4 subtable for the BMP, listed as Unicode and Windows BMP, and a
+// format 12 one for Windows full Unicode when the font has it
+func (f *Font) cmap() []byte {
+	numRecords := 2
+	if f.Format12 {
+		numRecords = 3
+	}
+	format4 := f.format4()
+	format4At := uint32(4 + numRecords*8)
+
+	data := binary.BigEndian.AppendUint16(nil, 0)
+	data = binary.BigEndian.AppendUint16(data, uint16(numRecords))
+	for _, encoding := range [][2]uint16{{0, 3}, {3, 1}} {
+		data = binary.BigEndian.AppendUint16(data, encoding[0])
+		data = binary.BigEndian.AppendUint16(data, encoding[1])
+		data = binary.BigEndian.AppendUint32(data, format4At)
+	}
+	if f.Format12 {
+		data = binary.BigEndian.AppendUint16(data, 3)
+		data = binary.BigEndian.AppendUint16(data, 10)
+		data = binary.BigEndian.AppendUint32(data, format4At+uint32(len(format4)))
+	}
+
+	data = append(data, format4...)
+	if f.Format12 {
+		data = append(data, f.format12()...)
+	}
+	return data
+}
+
+// sortedChars returns the mapped characters in order
+func (f *Font) sortedChars() []rune {
+	chars := make([]rune, 0, len(f.Chars))
+	for r := range f.Chars {
+		chars = append(chars, r)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	return chars
+}
+
+func (f *Font) format4() []byte {
+	// Runs of consecutive characters, then the 0xFFFF segment closing the table
+	type segment struct {
+		first, last rune
+	}
+	var segments []segment
+	for _, r := range f.sortedChars() {
+		if r > 0xFFFE {
+			break
+		}
+		if n := len(segments); n > 0 && segments[n-1].last+1 == r &&
+			(f.RangeOffsets || int(f.Chars[r])-int(r) == int(f.Chars[segments[n-1].first])-int(segments[n-1].first)) {
+			segments[n-1].last = r
+			continue
+		}
+		segments = append(segments, segment{r, r})
+	}
+	segments = append(segments, segment{0xFFFF, 0xFFFF})
+
+	segCount := len(segments)
+	ends, starts, deltas, rangeOffsets := make([]uint16, segCount), make([]uint16, segCount), make([]uint16, segCount), make([]uint16, segCount)
+	var glyphIDs []uint16
+	for i, s := range segments {
+		ends[i], starts[i] = uint16(s.last), uint16(s.first)
+		switch {
+		case s.first == 0xFFFF:
+			deltas[i] = 1
+		case f.RangeOffsets:
+			// Offset from this entry of idRangeOffset to the segment's first glyph id
+			rangeOffsets[i] = uint16((segCount-i)*2 + len(glyphIDs)*2)
+			for r := s.first; r <= s.last; r++ {
+				glyphIDs = append(glyphIDs, f.Chars[r])
+			}
+		default:
+			deltas[i] = f.Chars[s.first] - uint16(s.first)
+		}
+	}
+
+	data := binary.BigEndian.AppendUint16(nil, 4)
+	data = binary.BigEndian.AppendUint16(data, 0) // Length, set below
+	data = binary.BigEndian.AppendUint16(data, 0)
+	data = binary.BigEndian.AppendUint16(data, uint16(segCount*2))
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= segCount {
+		entrySelector++
+	}
+	data = binary.BigEndian.AppendUint16(data, uint16(2<<entrySelector))
+	data = binary.BigEndian.AppendUint16(data, uint16(entrySelector))
+	data = binary.BigEndian.AppendUint16(data, uint16(segCount*2-2<<entrySelector))
+	for _, v := range ends {
+		data = binary.BigEndian.AppendUint16(data, v)
+	}
+	data = binary.BigEndian.AppendUint16(data, 0)
+	for _, array := range [][]uint16{starts, deltas, rangeOffsets, glyphIDs} {
+		for _, v := range array {
+			data = binary.BigEndian.AppendUint16(data, v)
+		}
+	}
+	binary.BigEndian.PutUint16(data[2:], uint16(len(data)))
+	return data
+}
+
+func (f *Font) format12() []byte {
+	var groups [][3]uint32
+	for _, r := range f.sortedChars() {
+		g := uint32(f.Chars[r])
+		if n := len(groups); n > 0 && groups[n-1][1]+1 == uint32(r) && groups[n-1][2]+uint32(r)-groups[n-1][0] == g {
+			groups[n-1][1] = uint32(r)
+			continue
+		}
+		groups = append(groups, [3]uint32{uint32(r), uint32(r), g})
+	}
+
+	data := binary.BigEndian.AppendUint16(nil, 12)
+	data = binary.BigEndian.AppendUint16(data, 0)
+	data = binary.BigEndian.AppendUint32(data, uint32(16+len(groups)*12))
+	data = binary.BigEndian.AppendUint32(data, 0)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(groups)))
+	for _, group := range groups {
+		for _, v := range group {
+			data = binary.BigEndian.AppendUint32(data, v)
+		}
+	}
+	return data
+}
+
+// Assemble writes a font file from its tables, sorted by tag and aligned to 4 bytes
+func Assemble(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	data := make([]byte, 12+len(tags)*16)
+	binary.BigEndian.PutUint32(data, 0x00010000)
+	binary.BigEndian.PutUint16(data[4:], uint16(len(tags)))
+	for i, tag := range tags {
+		record := data[12+i*16:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(tables[tag])))
+
+		data = append(data, tables[tag]...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+	return data
+}
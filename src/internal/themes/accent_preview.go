@@ -0,0 +1,135 @@
+// src/internal/themes/accent_preview.go
+// Live accent preview: swaps in a candidate palette and always restores the original
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// accentSettingsPath is the NextUI settings file holding the accent colors
+const accentSettingsPath = "/mnt/SDCARD/.userdata/shared/minuisettings.txt"
+
+// getAccentPreviewBackupPath returns the on-disk copy of the settings taken before a preview.
+// Keeping it on disk means a crash mid-preview is undone on the next launch.
+func getAccentPreviewBackupPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, ".accent_preview_backup.txt"), nil
+}
+
+// BeginAccentPreview backs up the current settings and writes the accent component's palette
+func BeginAccentPreview(componentPath string) error {
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return fmt.Errorf("error loading accent manifest: %w", err)
+	}
+
+	manifest, ok := manifestObj.(*AccentManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for accent component")
+	}
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	backupPath, err := getAccentPreviewBackupPath()
+	if err != nil {
+		return err
+	}
+
+	original, err := os.ReadFile(accentSettingsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading accent settings: %w", err)
+	}
+
+	// Only back up once, so a second preview never overwrites the true original
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		if err := os.WriteFile(backupPath, original, 0644); err != nil {
+			return fmt.Errorf("error backing up accent settings: %w", err)
+		}
+	}
+
+	colorValues := map[string]string{
+		"color1": settingsColor(manifest.AccentColors.Color1, logger),
+		"color2": settingsColor(manifest.AccentColors.Color2, logger),
+		"color3": settingsColor(manifest.AccentColors.Color3, logger),
+		"color4": settingsColor(manifest.AccentColors.Color4, logger),
+		"color5": settingsColor(manifest.AccentColors.Color5, logger),
+		"color6": settingsColor(manifest.AccentColors.Color6, logger),
+	}
+
+	// Replace color keys in place and keep every other setting untouched
+	var lines []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(original), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if value, isColorKey := colorValues[key]; isColorKey && len(parts) == 2 {
+			line = fmt.Sprintf("%s=%s", key, value)
+			seen[key] = true
+		}
+		lines = append(lines, line)
+	}
+
+	for i := 1; i <= 6; i++ {
+		key := fmt.Sprintf("color%d", i)
+		if !seen[key] {
+			lines = append(lines, fmt.Sprintf("%s=%s", key, colorValues[key]))
+		}
+	}
+
+	if err := os.WriteFile(accentSettingsPath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		RestoreAccentPreview()
+		return fmt.Errorf("error writing preview accent settings: %w", err)
+	}
+
+	logging.LogDebug("Previewing accent colors from %s", componentPath)
+	return nil
+}
+
+// RestoreAccentPreview puts back the settings saved by BeginAccentPreview, if any
+func RestoreAccentPreview() {
+	backupPath, err := getAccentPreviewBackupPath()
+	if err != nil {
+		logging.LogDebug("Warning: Could not locate accent preview backup: %v", err)
+		return
+	}
+
+	original, err := os.ReadFile(backupPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logging.LogDebug("Warning: Could not read accent preview backup: %v", err)
+		}
+		return
+	}
+
+	if err := os.WriteFile(accentSettingsPath, original, 0644); err != nil {
+		logging.LogDebug("Warning: Could not restore accent settings: %v", err)
+		return
+	}
+
+	os.Remove(backupPath)
+	logging.LogDebug("Restored accent settings after preview")
+}
+
+// CommitAccentPreview discards the backup once the previewed palette is kept
+func CommitAccentPreview() {
+	backupPath, err := getAccentPreviewBackupPath()
+	if err != nil {
+		return
+	}
+	os.Remove(backupPath)
+}
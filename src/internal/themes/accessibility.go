@@ -0,0 +1,106 @@
+// src/internal/themes/accessibility.go
+// Accessible mode setting, which keeps the manager readable whatever accents are applied
+
+package themes
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/ui"
+)
+
+// listTextColorKey is the accent setting NextUI draws list text with
+const listTextColorKey = "color4"
+
+// GetAccessibleUISetting reports whether accessible mode is on
+func GetAccessibleUISetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load accessible mode setting: %v", err)
+		return false
+	}
+	return config.AccessibleUI
+}
+
+// SetAccessibleUISetting stores the accessible mode setting and switches the UI to it
+func SetAccessibleUISetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.AccessibleUI = enabled
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	ApplyAccessibleUISetting()
+	return nil
+}
+
+// ApplyAccessibleUISetting switches the UI to the saved accessible mode, called once on startup
+func ApplyAccessibleUISetting() {
+	if !GetAccessibleUISetting() {
+		ui.SetAccessibleUI(nil)
+		return
+	}
+
+	logging.LogDebug("Accessible mode enabled")
+	ui.SetAccessibleUI(accessibleBackgroundColor)
+}
+
+// accessibleBackgroundColor returns black or white, whichever contrasts more with the list
+// text color of the applied accents, so the manager stays navigable with low-contrast themes
+func accessibleBackgroundColor() string {
+	textColor := currentAccentColor(listTextColorKey)
+	luminance, ok := relativeLuminance(textColor)
+	if !ok {
+		// NextUI's default list text is white
+		return "#000000"
+	}
+
+	// WCAG contrast ratios of the text against black and against white
+	againstBlack := (luminance + 0.05) / 0.05
+	againstWhite := 1.05 / (luminance + 0.05)
+	if againstBlack >= againstWhite {
+		return "#000000"
+	}
+	return "#FFFFFF"
+}
+
+// currentAccentColor returns an accent color from the NextUI settings, empty if not set
+func currentAccentColor(key string) string {
+	data, err := os.ReadFile(accentSettingsPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 && strings.TrimSpace(parts[0]) == key {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// relativeLuminance returns the WCAG relative luminance of a "0xRRGGBB" or "#RRGGBB" color
+func relativeLuminance(color string) (float64, bool) {
+	rgb, err := ParseColor(color)
+	if err != nil {
+		return 0, false
+	}
+
+	channel := func(value uint8) float64 {
+		c := float64(value) / 255
+		if c <= 0.03928 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(rgb.R) + 0.7152*channel(rgb.G) + 0.0722*channel(rgb.B), true
+}
@@ -0,0 +1,184 @@
+// src/internal/themes/animated_wallpapers.go
+// Detects animated wallpapers, which NextUI can't show, and turns them into still PNGs or refuses them
+
+package themes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// What applies do with animated wallpapers
+const (
+	AnimatedConvert = ""       // Apply a representative frame as a still PNG (the default)
+	AnimatedReject  = "reject" // Leave the wallpaper out and say why
+)
+
+// AnimatedPolicies are the policies offered in settings, in cycling order
+var AnimatedPolicies = []string{AnimatedConvert, AnimatedReject}
+
+// errAnimatedWallpaper marks a wallpaper left out because it is animated
+var errAnimatedWallpaper = errors.New("animated wallpaper")
+
+// GetAnimatedPolicy returns what applies do with animated wallpapers
+func GetAnimatedPolicy() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load animated wallpaper policy: %v", err)
+		return AnimatedConvert
+	}
+
+	if config.AnimatedPolicy == AnimatedReject {
+		return AnimatedReject
+	}
+	return AnimatedConvert
+}
+
+// SetAnimatedPolicy stores what applies do with animated wallpapers
+func SetAnimatedPolicy(policy string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.AnimatedPolicy = policy
+	return SaveConfig(config)
+}
+
+// sniffAnimatedFormat returns "GIF" or "WebP" when a file holds one of those formats,
+// whatever its extension, or "" for anything else. Packs often contain GIFs renamed to .png.
+func sniffAnimatedFormat(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	header := make([]byte, 12)
+	if n, _ := file.Read(header); n < len(header) {
+		return ""
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("GIF87a")), bytes.HasPrefix(header, []byte("GIF89a")):
+		return "GIF"
+	case bytes.HasPrefix(header, []byte("RIFF")) && bytes.Equal(header[8:12], []byte("WEBP")):
+		return "WebP"
+	}
+	return ""
+}
+
+// representativeGIFFrame composes a GIF's frames up to the middle one, which is a better
+// likeness than the first frame of animations that fade in from black
+func representativeGIFFrame(anim *gif.GIF) *image.NRGBA {
+	bounds := image.Rect(0, 0, anim.Config.Width, anim.Config.Height)
+	if bounds.Empty() {
+		bounds = anim.Image[0].Bounds()
+	}
+	canvas := image.NewNRGBA(bounds)
+
+	target := len(anim.Image) / 2
+	for i := 0; i <= target; i++ {
+		frame := anim.Image[i]
+
+		var previous *image.NRGBA
+		if i < len(anim.Disposal) && anim.Disposal[i] == gif.DisposalPrevious {
+			previous = image.NewNRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, bounds.Min, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if i == target {
+			break
+		}
+
+		// Undo the frame the way the animation would before the next one
+		if i < len(anim.Disposal) {
+			switch anim.Disposal[i] {
+			case gif.DisposalBackground:
+				draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+			case gif.DisposalPrevious:
+				canvas = previous
+			}
+		}
+	}
+	return canvas
+}
+
+// stillWallpaperSource returns the file to apply for a wallpaper. Ordinary wallpapers are
+// returned as they are. An animated GIF becomes a temporary still PNG, removed by the
+// returned cleanup, unless the policy refuses animated wallpapers. Animated WebP can't be
+// decoded and is always refused.
+func stillWallpaperSource(srcPath string, logger *Logger) (string, func(), error) {
+	noop := func() {}
+
+	format := sniffAnimatedFormat(srcPath)
+	if format == "" {
+		return srcPath, noop, nil
+	}
+
+	name := filepath.Base(srcPath)
+	refuse := func() (string, func(), error) {
+		applyReport.rejected = append(applyReport.rejected, name)
+		return "", noop, fmt.Errorf("%s is a %s, NextUI only shows PNG wallpapers: %w", name, format, errAnimatedWallpaper)
+	}
+	if format != "GIF" || GetAnimatedPolicy() == AnimatedReject {
+		return refuse()
+	}
+
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return "", noop, fmt.Errorf("error reading %s: %w", name, err)
+	}
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil || len(anim.Image) == 0 {
+		logger.DebugFn("Could not decode GIF wallpaper %s: %v", name, err)
+		return refuse()
+	}
+
+	tmp, err := os.CreateTemp("", "wallpaper-*.png")
+	if err != nil {
+		return "", noop, fmt.Errorf("error creating still wallpaper: %w", err)
+	}
+	cleanup := func() { os.Remove(tmp.Name()) }
+
+	if err := png.Encode(tmp, representativeGIFFrame(anim)); err != nil {
+		tmp.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("error encoding still wallpaper: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("error writing still wallpaper: %w", err)
+	}
+
+	applyReport.converted = append(applyReport.converted, name)
+	logger.DebugFn("Converted animated wallpaper %s (%d frames) to a still PNG", name, len(anim.Image))
+	return tmp.Name(), cleanup, nil
+}
+
+// animatedSummary returns lines for apply messages about animated wallpapers
+func animatedSummary() string {
+	var summary string
+	if count := len(applyReport.converted); count > 0 {
+		summary += fmt.Sprintf("\n%d animated wallpaper(s) applied as a still frame", count)
+	}
+	if count := len(applyReport.rejected); count > 0 {
+		names := applyReport.rejected
+		if len(names) > 3 {
+			names = append(names[:3:3], "...")
+		}
+		summary += fmt.Sprintf("\n%d animated wallpaper(s) left out, NextUI needs PNG: %s", count, strings.Join(names, ", "))
+	}
+	return summary
+}
@@ -0,0 +1,106 @@
+// src/internal/themes/apply_parts.go
+// Applying only some parts of a theme
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Parts of a theme that can be applied on their own
+const (
+	PartWallpapers = "Wallpapers"
+	PartIcons      = "Icons"
+	PartFonts      = "Fonts"
+	PartGameArt    = "Game Art"
+	PartAccents    = "Accent Colors"
+	PartSettings   = "Settings"
+)
+
+// ThemeParts lists the parts of a theme in the order they're offered
+var ThemeParts = []string{PartWallpapers, PartIcons, PartFonts, PartGameArt, PartAccents, PartSettings}
+
+// applyParts are the parts the current apply is limited to, nil while applying everything
+var applyParts map[string]bool
+
+// ImportThemeParts applies only the given parts of a theme. Parts left out keep what the
+// device has now, their old files aren't removed either.
+func ImportThemeParts(themeName string, parts []string) error {
+	return ImportThemePartsContext(context.Background(), themeName, parts)
+}
+
+// ImportThemePartsContext applies only the given parts of a theme, stopping between files
+// once ctx is done
+func ImportThemePartsContext(ctx context.Context, themeName string, parts []string) error {
+	if len(parts) == 0 {
+		return fmt.Errorf("no parts of the theme were picked")
+	}
+
+	applyParts = make(map[string]bool)
+	for _, part := range parts {
+		applyParts[part] = true
+	}
+	defer func() { applyParts = nil }()
+
+	return ImportThemeContext(ctx, themeName)
+}
+
+// isPartApplied reports whether the current apply includes a part
+func isPartApplied(part string) bool {
+	return applyParts == nil || applyParts[part]
+}
+
+// filterThemeParts drops the mappings of the parts left out of a partial apply
+func filterThemeParts(manifest *ThemeManifest, logger *Logger) {
+	if applyParts == nil {
+		return
+	}
+
+	var skipped []string
+	for _, part := range ThemeParts {
+		if !applyParts[part] {
+			skipped = append(skipped, part)
+		}
+	}
+	logger.DebugFn("Partial apply, leaving out: %s", strings.Join(skipped, ", "))
+
+	if !isPartApplied(PartWallpapers) {
+		manifest.PathMappings.Wallpapers = nil
+		manifest.Content.Wallpapers.Count = 0
+		manifest.Content.Wallpapers.Sleep = ""
+	}
+	if !isPartApplied(PartIcons) {
+		manifest.PathMappings.Icons = nil
+		manifest.Content.Icons.SystemCount = 0
+		manifest.Content.Icons.ToolCount = 0
+		manifest.Content.Icons.CollectionCount = 0
+	}
+	if !isPartApplied(PartFonts) {
+		manifest.PathMappings.Fonts = nil
+	}
+	if !isPartApplied(PartGameArt) {
+		manifest.PathMappings.GameArt = nil
+	}
+	if !isPartApplied(PartAccents) {
+		manifest.Content.Settings.AccentsIncluded = false
+	}
+	if !isPartApplied(PartSettings) {
+		manifest.PathMappings.Settings = nil
+	}
+}
+
+// partialCleanupTypes returns the cleanup types of the parts being applied
+func partialCleanupTypes(types []string) []string {
+	var kept []string
+	for _, componentType := range types {
+		switch {
+		case componentType == ComponentWallpaper && !isPartApplied(PartWallpapers):
+		case componentType == ComponentIcon && !isPartApplied(PartIcons):
+		default:
+			kept = append(kept, componentType)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,225 @@
+// src/internal/themes/apply_preview.go
+// Dry run of an apply: which files on the device would be created, overwritten or removed
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// ApplyPreview lists the device files an apply would change, relative to the SD card
+type ApplyPreview struct {
+	Created     []string // Files the package adds
+	Overwritten []string // Files the package replaces
+	Removed     []string // Files the cleanup moves to the trash and the package doesn't put back
+	Skipped     int      // Files left out for missing or excluded systems, or pinned destinations
+	Settings    bool     // The package also changes settings, such as accent colors or LEDs
+}
+
+// Total returns how many device files the apply would change
+func (p *ApplyPreview) Total() int {
+	return len(p.Created) + len(p.Overwritten) + len(p.Removed)
+}
+
+// cleanupPreview collects the files removeThemeFile would trash while a preview runs a
+// cleanup; nil during a real apply
+var cleanupPreview map[string]bool
+
+// previewCleanup runs a cleanup without touching the device and returns what it would remove
+func previewCleanup(cleanup func() error) map[string]bool {
+	cleanupPreview = make(map[string]bool)
+	defer func() { cleanupPreview = nil }()
+
+	if err := cleanup(); err != nil {
+		logging.LogDebug("Warning: Error previewing cleanup: %v", err)
+	}
+	return cleanupPreview
+}
+
+// PreviewApply works out what applying an installed theme or component would do to the
+// device, without changing anything on it. Like CheckCompatibility, the package's manifest
+// is brought up to date with its content first.
+func PreviewApply(packagePath string) (*ApplyPreview, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	var manifest interface{}
+	var cleanup func() error
+	var generatedLists []PathMapping
+	if IsThemeArchive(filepath.Base(packagePath)) || strings.HasSuffix(packagePath, ".theme") {
+		// Zipped themes are looked at from a temporary copy, as they are applied
+		themePath := packagePath
+		if IsThemeArchive(filepath.Base(packagePath)) {
+			unpacked, remove, err := unpackThemeArchive(packagePath, logger)
+			if err != nil {
+				return nil, err
+			}
+			defer remove()
+			themePath = unpacked
+		}
+
+		themeManifest, err := ValidateTheme(themePath, logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := UpdateManifestFromThemeContent(themePath, themeManifest, systemPaths, logger); err != nil {
+			logger.DebugFn("Warning: Error updating manifest from content: %v", err)
+		}
+		if err := resolveExtends(themePath, themeManifest, systemPaths, logger); err != nil {
+			return nil, err
+		}
+		filterThemeParts(themeManifest, logger)
+
+		// Themes don't apply their overlays
+		themeManifest.PathMappings.Overlays = nil
+		manifest = themeManifest
+
+		// The apply makes the list wallpapers the theme lacks when asked to
+		if generateListWallpapers {
+			for _, mapping := range missingListWallpapers(themeManifest.PathMappings.Wallpapers) {
+				mapping.SystemPath = filepath.Join(filepath.Dir(mapping.SystemPath), "bglist.png")
+				generatedLists = append(generatedLists, mapping)
+			}
+		}
+
+		cleanup = func() error {
+			for _, componentType := range partialCleanupTypes(themeCleanupTypes) {
+				kind, _ := LookupComponentKind(componentType)
+				if err := kind.Cleanup(systemPaths, logger); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	} else {
+		kind, ok := ComponentKindForExtension(filepath.Ext(packagePath))
+		if !ok {
+			return nil, fmt.Errorf("unknown component type for extension: %s", filepath.Ext(packagePath))
+		}
+		if err := UpdateComponentManifest(packagePath); err != nil {
+			logger.DebugFn("Warning: Error updating component manifest: %v", err)
+		}
+		manifest, err = LoadComponentManifest(packagePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", filepath.Base(packagePath), err)
+		}
+
+		cleanup = func() error {
+			return kind.Cleanup(systemPaths, logger)
+		}
+		if overlays, ok := manifest.(*OverlayManifest); ok && GetCleanupPolicy() == CleanupMerge {
+			cleanup = func() error {
+				return cleanupOverlaysForSystems(systemPaths, overlayPackageSystems(overlays), logger)
+			}
+		}
+	}
+
+	beginPinnedApply()
+	removed := previewCleanup(cleanup)
+
+	mappings, settings := packageMappings(manifest)
+	preview := &ApplyPreview{Settings: settings}
+	excluded := loadExcludedSystems()
+	written := make(map[string]bool)
+
+	for i, mapping := range append(append([]PathMapping(nil), mappings...), generatedLists...) {
+		if _, ok := mappingTarget(mapping, systemPaths); !ok ||
+			isMappingExcluded(mapping, excluded) || isFolderMappingMissing(mapping) {
+			// A generated list wallpaper is skipped along with its wallpaper, which is counted
+			if i < len(mappings) {
+				preview.Skipped++
+			}
+			continue
+		}
+
+		// System icons are renamed after the device's ROM folder, as the apply does
+		dstPath := mapping.SystemPath
+		if mapping.Metadata["IconType"] == "System" {
+			if renamed, err := GetSystemIconDestination(mapping.ThemePath, filepath.Base(mapping.ThemePath), dstPath, systemPaths, logger); err == nil {
+				dstPath = renamed
+			}
+		}
+		dstPath = filepath.Clean(dstPath)
+
+		if written[dstPath] {
+			continue
+		}
+		if isPinnedDestination(dstPath) {
+			preview.Skipped++
+			continue
+		}
+		written[dstPath] = true
+
+		// Files the cleanup removes first are overwritten as far as the user can tell
+		if _, err := os.Stat(dstPath); err == nil {
+			preview.Overwritten = append(preview.Overwritten, previewPath(dstPath, systemPaths))
+		} else {
+			preview.Created = append(preview.Created, previewPath(dstPath, systemPaths))
+		}
+	}
+
+	for path := range removed {
+		if !written[filepath.Clean(path)] {
+			preview.Removed = append(preview.Removed, previewPath(path, systemPaths))
+		}
+	}
+
+	sort.Strings(preview.Created)
+	sort.Strings(preview.Overwritten)
+	sort.Strings(preview.Removed)
+
+	logger.DebugFn("Preview of %s: %d created, %d overwritten, %d removed, %d skipped",
+		filepath.Base(packagePath), len(preview.Created), len(preview.Overwritten), len(preview.Removed), preview.Skipped)
+	return preview, nil
+}
+
+// previewPath shortens a device path to where it is on the SD card
+func previewPath(path string, systemPaths *system.SystemPaths) string {
+	if rel, err := filepath.Rel(systemPaths.Root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// FormatApplyPreview renders a preview as one line per file, for a scrollable list
+func FormatApplyPreview(preview *ApplyPreview) string {
+	var lines []string
+
+	if preview.Total() == 0 {
+		lines = append(lines, "No files on the device would change")
+	} else {
+		lines = append(lines, fmt.Sprintf("%d created, %d overwritten, %d removed",
+			len(preview.Created), len(preview.Overwritten), len(preview.Removed)))
+	}
+	if preview.Settings {
+		lines = append(lines, "Settings are changed too")
+	}
+	if preview.Skipped > 0 {
+		lines = append(lines, fmt.Sprintf("%d files skipped (missing or excluded systems, pinned files)", preview.Skipped))
+	}
+
+	for _, path := range preview.Created {
+		lines = append(lines, "+ "+path)
+	}
+	for _, path := range preview.Overwritten {
+		lines = append(lines, "~ "+path)
+	}
+	for _, path := range preview.Removed {
+		lines = append(lines, "- "+path)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,108 @@
+// src/internal/themes/apply_progress.go
+// Runs applies in the background and feeds their progress to the progress screen
+
+package themes
+
+import (
+	"context"
+	"errors"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/ui"
+)
+
+// applyProgress connects the apply in progress to a progress screen; the feed is nil
+// when the apply was started without one
+var applyProgress struct {
+	feed  chan<- ui.Progress
+	done  int
+	total int
+}
+
+// RunApplyWithProgress runs an apply in the background behind an animated progress screen
+// that shows how many files are done and lets the user cancel. The apply is given a context
+// that is cancelled when the user cancels from the screen.
+func RunApplyWithProgress(message string, apply func(ctx context.Context) error) error {
+	return RunApplyWithProgressContext(context.Background(), message, apply)
+}
+
+// RunApplyWithProgressContext is RunApplyWithProgress for an apply that also stops once the
+// caller's ctx is done
+func RunApplyWithProgressContext(parent context.Context, message string, apply func(ctx context.Context) error) error {
+	return ui.ShowProgress(message, func(progress chan<- ui.Progress, cancel <-chan struct{}) error {
+		// The cancel button cancels the apply's context, with ui.ErrCancelled as the cause
+		ctx, cancelApply := context.WithCancelCause(parent)
+		defer cancelApply(nil)
+		go func() {
+			select {
+			case <-cancel:
+				cancelApply(ui.ErrCancelled)
+			case <-ctx.Done():
+			}
+		}()
+
+		applyProgress.feed = progress
+		defer func() { applyProgress.feed = nil }()
+
+		return apply(ctx)
+	})
+}
+
+// IsCancelled reports whether an error comes from a cancelled or timed out operation
+func IsCancelled(err error) bool {
+	return errors.Is(err, ui.ErrCancelled) ||
+		errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// beginApplyProgress resets the progress counters for an apply of total files
+func beginApplyProgress(total int) {
+	applyProgress.done = 0
+	applyProgress.total = total
+}
+
+// reportApplyStep tells the progress screen what the apply is doing
+func reportApplyStep(step string) {
+	if applyProgress.feed == nil {
+		return
+	}
+
+	update := ui.Progress{Done: applyProgress.done, Total: applyProgress.total, Step: step}
+	select {
+	case applyProgress.feed <- update:
+	default:
+		// The screen only shows the latest update, dropping one when it lags is fine
+	}
+}
+
+// applyCancelled returns why an apply's context ended, or nil if it hasn't: ui.ErrCancelled
+// when the user cancelled, or the context's error when it timed out. In strict mode an apply
+// also stops once it has logged a warning.
+func applyCancelled(ctx context.Context) error {
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+	return strictStopped()
+}
+
+// nextApplyFile reports the file the apply is about to copy. It returns an error once ctx
+// is done, so the apply stops between files rather than halfway through one.
+func nextApplyFile(ctx context.Context, name string) error {
+	if err := applyCancelled(ctx); err != nil {
+		return err
+	}
+
+	logging.SetCurrentFile(name)
+	reportApplyStep(name)
+	applyProgress.done++
+	return nil
+}
+
+// countThemeMappings returns the number of files a theme apply copies
+func countThemeMappings(manifest *ThemeManifest) int {
+	return len(manifest.PathMappings.Wallpapers) +
+		len(manifest.PathMappings.Icons) +
+		len(manifest.PathMappings.Fonts) +
+		len(manifest.PathMappings.GameArt) +
+		len(manifest.PathMappings.Settings)
+}
@@ -0,0 +1,107 @@
+// src/internal/themes/apply_timing.go
+// Per-phase timing of applies, to track down slowness reported on real devices
+
+package themes
+
+import (
+	"fmt"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// timingsFlag is set for the session by the --timings command line flag
+var timingsFlag bool
+
+// EnableApplyTimings logs how long each phase of every apply takes for this session
+func EnableApplyTimings() {
+	timingsFlag = true
+}
+
+// phaseTiming is one finished phase of an apply
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// applyTiming times the apply in progress; start is zero when no apply is being timed
+var applyTiming struct {
+	start      time.Time
+	phase      string
+	phaseStart time.Time
+	phases     []phaseTiming
+}
+
+// beginApplyTiming starts timing an apply
+func beginApplyTiming() {
+	applyTiming.start = time.Now()
+	applyTiming.phase = ""
+	applyTiming.phases = nil
+}
+
+// startApplyPhase ends the current phase and starts the next one, e.g. "cleanup" or "copy"
+func startApplyPhase(name string) {
+	if applyTiming.start.IsZero() {
+		return
+	}
+
+	endApplyPhase()
+	applyTiming.phase = name
+	applyTiming.phaseStart = time.Now()
+}
+
+// endApplyPhase records the current phase, if any
+func endApplyPhase() {
+	if applyTiming.phase == "" {
+		return
+	}
+
+	applyTiming.phases = append(applyTiming.phases, phaseTiming{
+		name:     applyTiming.phase,
+		duration: time.Since(applyTiming.phaseStart),
+	})
+	applyTiming.phase = ""
+}
+
+// finishApplyTiming ends the last phase, logs the breakdown when timings are enabled
+// and stores the total as the last apply duration
+func finishApplyTiming(logger *Logger) {
+	if applyTiming.start.IsZero() {
+		return
+	}
+
+	endApplyPhase()
+	total := time.Since(applyTiming.start)
+	applyTiming.start = time.Time{}
+
+	if timingsFlag {
+		for _, phase := range applyTiming.phases {
+			logger.DebugFn("Timing: %-10s %v", phase.name, phase.duration.Round(time.Millisecond))
+		}
+		logger.DebugFn("Timing: %-10s %v", "total", total.Round(time.Millisecond))
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		logger.DebugFn("Warning: Could not save apply duration: %v", err)
+		return
+	}
+	config.LastApplyMillis = total.Milliseconds()
+	if err := SaveConfig(config); err != nil {
+		logger.DebugFn("Warning: Could not save apply duration: %v", err)
+	}
+}
+
+// LastApplySummary returns "last apply took Xs", or an empty string before the first apply
+func LastApplySummary() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load apply duration: %v", err)
+		return ""
+	}
+	if config.LastApplyMillis <= 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("last apply took %.1fs", float64(config.LastApplyMillis)/1000)
+}
@@ -0,0 +1,127 @@
+// src/internal/themes/audit.go
+// Audit trail of the last operations and how they ended, shown under About
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// auditLimit is the number of operations kept in the audit trail
+const auditLimit = 100
+
+// Audit outcomes
+const (
+	AuditOK        = "OK"
+	AuditCancelled = "Cancelled"
+	AuditFailed    = "Failed"
+)
+
+// AuditEntry is one operation in the audit trail
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Operation string    `json:"operation"`         // e.g. "Applied theme"
+	Package   string    `json:"package,omitempty"` // Theme or component it worked on
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// getAuditPath returns the path of the audit trail file
+func getAuditPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, "audit_log.json"), nil
+}
+
+// LoadAuditLog returns the audit trail, oldest operation first
+func LoadAuditLog() ([]AuditEntry, error) {
+	auditPath, err := getAuditPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(auditPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading audit log: %w", err)
+	}
+
+	var entries []AuditEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// recordOperation adds an operation and its outcome to the audit trail, dropping the oldest
+// entries past auditLimit. Called deferred from every operation that changes the device
+// or the installed packages.
+func recordOperation(operation, pkg string, err error) {
+	entry := AuditEntry{
+		Time:      time.Now(),
+		Operation: operation,
+		Package:   pkg,
+		Outcome:   AuditOK,
+	}
+	switch {
+	case err == nil:
+	case IsCancelled(err):
+		entry.Outcome = AuditCancelled
+	default:
+		entry.Outcome = AuditFailed
+		entry.Error = err.Error()
+	}
+
+	entries, loadErr := LoadAuditLog()
+	if loadErr != nil {
+		// Start over rather than lose every operation from now on
+		logging.LogDebug("Warning: Could not load audit log, starting a new one: %v", loadErr)
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > auditLimit {
+		entries = entries[len(entries)-auditLimit:]
+	}
+
+	auditPath, pathErr := getAuditPath()
+	if pathErr != nil {
+		logging.LogDebug("Warning: Could not locate audit log: %v", pathErr)
+		return
+	}
+
+	data, marshalErr := json.MarshalIndent(entries, "", "  ")
+	if marshalErr != nil {
+		logging.LogDebug("Warning: Could not encode audit log: %v", marshalErr)
+		return
+	}
+	if writeErr := os.WriteFile(auditPath, data, 0644); writeErr != nil {
+		logging.LogDebug("Warning: Could not save audit log: %v", writeErr)
+	}
+}
+
+// exportName returns the name of an exported package for the audit trail, if there is one
+func exportName(packagePath string) string {
+	if packagePath == "" {
+		return ""
+	}
+	return filepath.Base(packagePath)
+}
+
+// FormatAuditEntry renders an entry as one line of the audit trail screen
+func FormatAuditEntry(entry AuditEntry) string {
+	line := entry.Time.Local().Format("Mon 2006-01-02 15:04") + "  " + entry.Operation
+	if entry.Package != "" {
+		line += " " + entry.Package
+	}
+	return line + ": " + entry.Outcome
+}
@@ -0,0 +1,112 @@
+// src/internal/themes/auto_deconstruct.go
+// Optionally breaks every downloaded theme into component packages in the library, so its
+// pieces can be mixed and matched straight away
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+)
+
+// GetAutoDeconstructSetting reports whether downloaded themes are also deconstructed
+func GetAutoDeconstructSetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load auto deconstruct setting: %v", err)
+		return false
+	}
+	return config.AutoDeconstruct
+}
+
+// SetAutoDeconstructSetting stores whether downloaded themes are also deconstructed
+func SetAutoDeconstructSetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.AutoDeconstruct = enabled
+	return SaveConfig(config)
+}
+
+// deconstructDownloadedTheme deconstructs the theme folder at themePath into the
+// Components library when the setting is on, and returns how many packages were added.
+// The packages are written to a staging folder in .cache instead of the exports folder,
+// then moved into the library; a component already in the library is left alone. A
+// failure here never fails the download, it is only logged.
+func deconstructDownloadedTheme(themePath string, themeName string) int {
+	if !GetAutoDeconstructSetting() {
+		return 0
+	}
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+	logger.DebugFn("Deconstructing downloaded theme: %s", themeName)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		logger.DebugFn("Warning: Could not deconstruct downloaded theme: %v", err)
+		return 0
+	}
+
+	stagingDir := filepath.Join(cwd, ".cache", "deconstructed")
+	os.RemoveAll(stagingDir)
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		logger.DebugFn("Warning: Could not create %s: %v", stagingDir, err)
+		return 0
+	}
+	defer os.RemoveAll(stagingDir)
+
+	// The user's last export stays the one they can copy to external storage
+	previousExport := lastExportPath
+	stagedExportsDir = stagingDir
+	_, _, err = deconstructThemeFolder(themePath, themeName, logger)
+	stagedExportsDir = ""
+	lastExportPath = previousExport
+	if err != nil {
+		logger.DebugFn("Warning: Could not deconstruct downloaded theme %s: %v", themeName, err)
+		return 0
+	}
+
+	entries, err := os.ReadDir(stagingDir)
+	if err != nil {
+		logger.DebugFn("Warning: Could not read deconstructed components: %v", err)
+		return 0
+	}
+
+	added := 0
+	for _, entry := range entries {
+		packagePath := filepath.Join(stagingDir, entry.Name())
+
+		packageType, err := DetectPackageType(packagePath)
+		if err != nil || packageType == PackageTheme {
+			logger.DebugFn("Warning: Skipping unexpected package %s", entry.Name())
+			continue
+		}
+
+		libraryPath, err := libraryPathForPackage(packagePath, packageType)
+		if err != nil {
+			logger.DebugFn("Warning: Could not place %s: %v", entry.Name(), err)
+			continue
+		}
+		if _, err := os.Stat(libraryPath); err == nil {
+			logger.DebugFn("Component %s is already in the library, keeping it", entry.Name())
+			continue
+		}
+
+		if err := movePackage(packagePath, libraryPath); err != nil {
+			logger.DebugFn("Warning: Could not add %s to the library: %v", entry.Name(), err)
+			continue
+		}
+		logger.DebugFn("Added deconstructed component %s", libraryPath)
+		added++
+	}
+
+	logger.DebugFn("Added %d components from downloaded theme %s", added, themeName)
+	return added
+}
@@ -0,0 +1,79 @@
+// src/internal/themes/battery_guard.go
+// Refuses long operations on a low battery so power loss can't interrupt them
+
+package themes
+
+import (
+	"fmt"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// Battery guard values stored in the config; positive values are a percentage
+const (
+	BatteryGuardDefault = 0  // Use defaultBatteryGuardPercent
+	BatteryGuardOff     = -1 // Never block on battery level
+)
+
+// defaultBatteryGuardPercent is the minimum charge when the user hasn't picked one
+const defaultBatteryGuardPercent = 15
+
+// BatteryGuardPresets are the thresholds offered in settings
+var BatteryGuardPresets = []int{BatteryGuardDefault, 25, 40, BatteryGuardOff}
+
+// GetBatteryGuardSetting returns the user's battery guard choice
+func GetBatteryGuardSetting() int {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load battery guard setting: %v", err)
+		return BatteryGuardDefault
+	}
+	return config.BatteryGuardPercent
+}
+
+// SetBatteryGuardSetting stores the user's battery guard choice
+func SetBatteryGuardSetting(percent int) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.BatteryGuardPercent = percent
+	return SaveConfig(config)
+}
+
+// BatteryGuardThreshold resolves a battery guard setting to a percentage, 0 meaning off
+func BatteryGuardThreshold(setting int) int {
+	switch setting {
+	case BatteryGuardDefault:
+		return defaultBatteryGuardPercent
+	case BatteryGuardOff:
+		return 0
+	default:
+		return setting
+	}
+}
+
+// CheckBatteryForOperation returns an error if the battery is too low to start
+// a long operation. Charging devices and devices without a readable battery pass.
+func CheckBatteryForOperation(operation string) error {
+	threshold := BatteryGuardThreshold(GetBatteryGuardSetting())
+	if threshold == 0 {
+		return nil
+	}
+
+	status, err := system.GetBatteryStatus()
+	if err != nil {
+		logging.LogDebug("Could not read battery level, not guarding %s: %v", operation, err)
+		return nil
+	}
+
+	if status.Charging || status.Percent >= threshold {
+		return nil
+	}
+
+	logging.LogDebug("Refusing %s at %d%% battery (minimum %d%%)", operation, status.Percent, threshold)
+	return fmt.Errorf("battery is at %d%%. Charge to at least %d%% or plug in before %s",
+		status.Percent, threshold, operation)
+}
@@ -0,0 +1,92 @@
+// src/internal/themes/boot_hook.go
+// Lines Theme Manager adds to NextUI's auto.sh to run headless tasks at every boot
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// autoStartPath returns the script NextUI runs at every boot, in the platform's userdata folder
+func autoStartPath() string {
+	return filepath.Join(storageRoot, ".userdata", system.DetectPlatform(storageRoot), "auto.sh")
+}
+
+// bootHookMarker returns the comment ending a hook's auto.sh line, so it can be found and removed
+func bootHookMarker(name string) string {
+	return "# theme-manager " + name
+}
+
+// setBootHook adds a line running Theme Manager with flag to auto.sh, or removes it when
+// enabled is false. Each hook is known by its name; the rest of auto.sh is left as it was.
+func setBootHook(name, flag string, enabled bool, logger *Logger) {
+	writeBootHook(name, flag, false, enabled, logger)
+}
+
+// setBackgroundBootHook works like setBootHook for tasks that keep running, so boot continues
+// without waiting for them
+func setBackgroundBootHook(name, flag string, enabled bool, logger *Logger) {
+	writeBootHook(name, flag, true, enabled, logger)
+}
+
+// writeBootHook adds or removes a hook line in auto.sh
+func writeBootHook(name, flag string, background, enabled bool, logger *Logger) {
+	marker := bootHookMarker(name)
+	scriptPath := autoStartPath()
+
+	content, err := os.ReadFile(scriptPath)
+	if err != nil && !os.IsNotExist(err) {
+		logger.DebugFn("Warning: Could not read %s: %v", scriptPath, err)
+		return
+	}
+
+	var lines []string
+	removed := false
+	for _, line := range strings.Split(strings.TrimRight(string(content), "\n"), "\n") {
+		if strings.HasSuffix(line, marker) {
+			removed = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		lines = nil
+	}
+
+	if enabled {
+		cwd, err := os.Getwd()
+		if err != nil {
+			logger.DebugFn("Warning: Could not add %s boot hook: %v", name, err)
+			return
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "#!/bin/sh")
+		}
+		command := fmt.Sprintf(`(cd "%s" && ./theme-manager %s >/dev/null 2>&1)`, cwd, flag)
+		if background {
+			command += " &"
+		}
+		lines = append(lines, command+" "+marker)
+	} else if !removed {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(scriptPath), 0755); err != nil {
+		logger.DebugFn("Warning: Could not create %s: %v", filepath.Dir(scriptPath), err)
+		return
+	}
+	if err := os.WriteFile(scriptPath, []byte(strings.Join(lines, "\n")+"\n"), 0755); err != nil {
+		logger.DebugFn("Warning: Could not update %s: %v", scriptPath, err)
+		return
+	}
+	if enabled {
+		logger.DebugFn("Installed %s boot hook in %s", name, scriptPath)
+	} else {
+		logger.DebugFn("Removed %s boot hook from %s", name, scriptPath)
+	}
+}
@@ -0,0 +1,268 @@
+// src/internal/themes/bulk_import.go
+// Imports every theme and component package found in a drop folder in one batch
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// PackageTheme is the package type returned by DetectPackageType for .theme packages;
+// components use their component type constant
+const PackageTheme = "theme"
+
+// BulkImportResult describes the outcome of a drop folder import
+type BulkImportResult struct {
+	Imported []string // Package names moved into the library
+	Skipped  []string // "name: reason" for everything left in the drop folder
+}
+
+// GetDropFolder returns the default drop folder inside the Theme Manager directory
+func GetDropFolder() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, "Imports"), nil
+}
+
+// DetectPackageType works out what kind of package a folder is, first by its
+// extension and otherwise by sniffing its manifest
+func DetectPackageType(packagePath string) (string, error) {
+	ext := filepath.Ext(packagePath)
+	if ext == ".theme" {
+		return PackageTheme, nil
+	}
+	for componentType, componentExt := range ComponentExtension {
+		if ext == componentExt {
+			return componentType, nil
+		}
+	}
+
+	if err := ensureJSONManifest(packagePath, &Logger{DebugFn: logging.LogDebug}); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(packagePath, "manifest.json"))
+	if err != nil {
+		return "", fmt.Errorf("no recognizable extension and no manifest.json or manifest.yaml")
+	}
+
+	var sniff struct {
+		ThemeInfo     *json.RawMessage `json:"theme_info"`
+		ComponentInfo *struct {
+			Type string `json:"type"`
+		} `json:"component_info"`
+	}
+	if err := json.Unmarshal(data, &sniff); err != nil {
+		return "", fmt.Errorf("error parsing manifest: %w", err)
+	}
+
+	switch {
+	case sniff.ThemeInfo != nil:
+		return PackageTheme, nil
+	case sniff.ComponentInfo != nil && ComponentExtension[sniff.ComponentInfo.Type] != "":
+		return sniff.ComponentInfo.Type, nil
+	}
+
+	return "", fmt.Errorf("manifest does not describe a theme or component")
+}
+
+// libraryPathForPackage returns where a package of the given type belongs in the library
+func libraryPathForPackage(packagePath string, packageType string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	name := filepath.Base(packagePath)
+
+	if packageType == PackageTheme {
+		if !strings.HasSuffix(name, ".theme") {
+			name = strings.TrimSuffix(name, filepath.Ext(name)) + ".theme"
+		}
+		return filepath.Join(cwd, "Themes", name), nil
+	}
+
+	ext := ComponentExtension[packageType]
+	if !strings.HasSuffix(name, ext) {
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + ext
+	}
+	return filepath.Join(cwd, "Components", ComponentDirectory[packageType], name), nil
+}
+
+// validatePackage checks that a package's manifest loads for its detected type
+func validatePackage(packagePath string, packageType string, logger *Logger) error {
+	if packageType == PackageTheme {
+		_, err := ValidateTheme(packagePath, logger)
+		return err
+	}
+
+	manifestObj, err := LoadComponentManifest(packagePath)
+	if err != nil {
+		return err
+	}
+
+	if info := GetComponentInfo(manifestObj); info != nil && info.Type != packageType {
+		return fmt.Errorf("extension says %s but manifest says %s", packageType, info.Type)
+	}
+	return nil
+}
+
+// movePackage moves a package folder, copying it when a rename isn't possible
+func movePackage(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return CopyFile(path, target)
+	})
+	if err != nil {
+		os.RemoveAll(dst)
+		return fmt.Errorf("error copying package: %w", err)
+	}
+
+	return os.RemoveAll(src)
+}
+
+// ImportFromFolder validates every package in a folder and moves the valid ones into the library
+func ImportFromFolder(folder string) (*BulkImportResult, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting bulk import from: %s", folder)
+	defer logging.LogMemoryUsage("bulk import")
+
+	result := &BulkImportResult{}
+
+	// Join split archives first so their packages are picked up below
+	for _, firstVolume := range findVolumeSets(folder) {
+		name := strings.TrimSuffix(filepath.Base(firstVolume), volumeSuffix)
+		if _, err := ReassembleVolumes(firstVolume, logger); err != nil {
+			logger.DebugFn("Warning: Could not reassemble %s: %v", name, err)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", name, err))
+		}
+	}
+
+	// Zipped themes are unpacked the same way
+	unzipThemeArchives(folder, result, logger)
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, fmt.Errorf("error reading folder %s: %w", folder, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		name := entry.Name()
+		packagePath := filepath.Join(folder, name)
+
+		packageType, err := DetectPackageType(packagePath)
+		if err != nil {
+			// Plain folders are common in a drop folder, only log them
+			logger.DebugFn("Skipping %s: %v", name, err)
+			continue
+		}
+
+		if err := validatePackage(packagePath, packageType, logger); err != nil {
+			logger.DebugFn("Warning: Invalid package %s: %v", name, err)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: invalid manifest", name))
+			continue
+		}
+
+		dstPath, err := libraryPathForPackage(packagePath, packageType)
+		if err != nil {
+			return result, err
+		}
+
+		// Patches update the installed theme instead of being installed next to it
+		if packageType == PackageTheme && IsPatchPackage(packagePath) {
+			if _, err := os.Stat(dstPath); err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: patch needs the theme installed", name))
+				continue
+			}
+			if err := ApplyThemePatch(packagePath, dstPath, logger); err != nil {
+				logger.DebugFn("Warning: Could not apply patch %s: %v", name, err)
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+
+			os.RemoveAll(packagePath)
+			result.Imported = append(result.Imported, filepath.Base(dstPath)+" (patched)")
+			continue
+		}
+
+		if _, err := os.Stat(dstPath); err == nil {
+			logger.DebugFn("Warning: %s is already installed at %s", name, dstPath)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: already installed", name))
+			continue
+		}
+
+		if err := movePackage(packagePath, dstPath); err != nil {
+			logger.DebugFn("Warning: Could not move %s: %v", name, err)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: could not be moved", name))
+			continue
+		}
+
+		logger.DebugFn("Imported %s package %s -> %s", packageType, name, dstPath)
+		result.Imported = append(result.Imported, filepath.Base(dstPath))
+	}
+
+	logger.DebugFn("Bulk import finished: %d imported, %d skipped", len(result.Imported), len(result.Skipped))
+	return result, nil
+}
+
+// ListDropFolderCandidates returns folders offered for bulk import: the Imports drop
+// folder first, then the SD card root and its top-level folders
+func ListDropFolderCandidates() []string {
+	var folders []string
+
+	if dropFolder, err := GetDropFolder(); err == nil {
+		folders = append(folders, dropFolder)
+	}
+
+	const sdcardRoot = "/mnt/SDCARD"
+	if _, err := os.Stat(sdcardRoot); err != nil {
+		return folders
+	}
+	folders = append(folders, sdcardRoot)
+
+	entries, err := os.ReadDir(sdcardRoot)
+	if err != nil {
+		return folders
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && entry.Name() != "Roms" {
+			folders = append(folders, filepath.Join(sdcardRoot, entry.Name()))
+		}
+	}
+
+	return folders
+}
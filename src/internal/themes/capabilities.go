@@ -0,0 +1,63 @@
+// src/internal/themes/capabilities.go
+// Detects which settings hardware the device has, so LED and accent settings are only written where they do something
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ledDevice is the DEVICE value NextUI sets on the Brick, the only device that reads
+// ledsettings_brick.txt
+const ledDevice = "brick"
+
+// DeviceCapabilities says which settings this device can use
+type DeviceCapabilities struct {
+	LEDs    bool // The Brick's LED zones, driven through the LED driver
+	Accents bool // NextUI's accent colors in minuisettings.txt
+}
+
+// GetDeviceCapabilities probes the device. LEDs need the LED driver, and, when NextUI says
+// which device it runs on, the Brick. Accents need NextUI's shared settings folder.
+func GetDeviceCapabilities() DeviceCapabilities {
+	var caps DeviceCapabilities
+
+	if _, err := os.Stat(ledAnimPath); err == nil {
+		device := os.Getenv("DEVICE")
+		caps.LEDs = device == "" || device == ledDevice
+	}
+
+	if info, err := os.Stat(sharedSettingsDir); err == nil && info.IsDir() {
+		caps.Accents = true
+	}
+
+	logging.LogDebug("Device capabilities: LEDs %v, accents %v", caps.LEDs, caps.Accents)
+	return caps
+}
+
+// isSettingsFileSupported reports whether a settings file does anything on this device.
+// Only the LED settings file depends on hardware.
+func isSettingsFileSupported(path string, caps DeviceCapabilities) bool {
+	if filepath.Base(path) == filepath.Base(ledSettingsPath) {
+		return caps.LEDs
+	}
+	return true
+}
+
+// reportUnsupported notes settings an apply left out because the device can't use them
+func reportUnsupported(what string) {
+	applyReport.unsupported = append(applyReport.unsupported, what)
+}
+
+// unsupportedSummary returns a line for apply messages naming the settings left out
+func unsupportedSummary() string {
+	if len(applyReport.unsupported) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\nNot supported on this device, skipped: %s", strings.Join(applyReport.unsupported, ", "))
+}
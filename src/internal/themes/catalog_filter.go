@@ -0,0 +1,130 @@
+// src/internal/themes/catalog_filter.go
+// Narrowing the catalog to themes with certain content, read from their synced manifests
+
+package themes
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// Content filters for browsing the catalog
+const (
+	ContentFilterAny            = "Any"
+	ContentFilterIcons          = "Has Icons"
+	ContentFilterOverlays       = "Has Overlays"
+	ContentFilterFonts          = "Has Fonts"
+	ContentFilterAccents        = "Has Accents"
+	ContentFilterWallpapersOnly = "Wallpapers Only"
+)
+
+// ContentFilters lists the content filters in the order they're cycled through
+var ContentFilters = []string{
+	ContentFilterAny,
+	ContentFilterIcons,
+	ContentFilterOverlays,
+	ContentFilterFonts,
+	ContentFilterAccents,
+	ContentFilterWallpapersOnly,
+}
+
+// catalogManifests caches the synced manifests of catalog themes by path, dropped once the
+// file changes with the next sync
+var catalogManifests = struct {
+	sync.Mutex
+	entries map[string]catalogManifestEntry
+}{entries: make(map[string]catalogManifestEntry)}
+
+type catalogManifestEntry struct {
+	modTime  time.Time
+	manifest *ThemeManifest // nil when the manifest couldn't be read
+}
+
+// readCatalogManifest returns the synced manifest of a catalog theme, or nil when the
+// catalog has none for it
+func readCatalogManifest(info CatalogItemInfo) *ThemeManifest {
+	if info.ManifestPath == "" {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(cwd, info.ManifestPath)
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	catalogManifests.Lock()
+	defer catalogManifests.Unlock()
+
+	if entry, ok := catalogManifests.entries[path]; ok && entry.modTime.Equal(stat.ModTime()) {
+		return entry.manifest
+	}
+
+	var manifest *ThemeManifest
+	if data, err := os.ReadFile(path); err == nil {
+		manifest = &ThemeManifest{}
+		if err := json.Unmarshal(data, manifest); err != nil {
+			logging.LogDebug("Warning: Could not parse catalog manifest %s: %v", path, err)
+			manifest = nil
+		}
+	}
+
+	catalogManifests.entries[path] = catalogManifestEntry{modTime: stat.ModTime(), manifest: manifest}
+	return manifest
+}
+
+// CatalogThemeHasContent reports whether a catalog theme passes a content filter. Themes
+// without a synced manifest only pass ContentFilterAny, their content is unknown.
+func CatalogThemeHasContent(info CatalogItemInfo, filter string) bool {
+	if filter == "" || filter == ContentFilterAny {
+		return true
+	}
+
+	manifest := readCatalogManifest(info)
+	if manifest == nil {
+		return false
+	}
+	content := manifest.Content
+
+	hasIcons := content.Icons.Present ||
+		content.Icons.SystemCount+content.Icons.ToolCount+content.Icons.CollectionCount > 0
+	hasWallpapers := content.Wallpapers.Present || content.Wallpapers.Count > 0
+
+	switch filter {
+	case ContentFilterIcons:
+		return hasIcons
+	case ContentFilterOverlays:
+		return content.Overlays.Present
+	case ContentFilterFonts:
+		return content.Fonts.Present
+	case ContentFilterAccents:
+		return content.Settings.AccentsIncluded
+	case ContentFilterWallpapersOnly:
+		return hasWallpapers && !hasIcons && !content.Overlays.Present && !content.Fonts.Present &&
+			!content.GameArt.Present && !content.Settings.AccentsIncluded && !content.Settings.LEDsIncluded
+	}
+	return true
+}
+
+// NextContentFilter returns the content filter after filter when cycling through them
+func NextContentFilter(filter string) string {
+	if filter == "" {
+		filter = ContentFilterAny
+	}
+	for i, f := range ContentFilters {
+		if f == filter {
+			return ContentFilters[(i+1)%len(ContentFilters)]
+		}
+	}
+	return ContentFilters[0]
+}
@@ -0,0 +1,120 @@
+// src/internal/themes/catalog_index.go
+// The catalog index, which lets a sync download only the previews and manifests that
+// changed instead of fetching the whole catalog again
+
+package themes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"nextui-themes/internal/logging"
+)
+
+// catalogIndexPath is where the catalog repository publishes its index
+const catalogIndexPath = "Catalog/index.json"
+
+// syncedCatalogIndexFile records, inside Catalog/, the version of each file as of the
+// last sync
+const syncedCatalogIndexFile = ".synced.json"
+
+// catalogIndex maps the path of each preview and manifest, relative to the repository,
+// to a version of its contents such as an ETag or a hash. Any value works as long as it
+// changes whenever the file does.
+type catalogIndex struct {
+	Files map[string]string `json:"files"`
+}
+
+// version returns the version of a file in the index, empty when the index doesn't list it
+func (index *catalogIndex) version(relPath string) string {
+	if index == nil {
+		return ""
+	}
+	return index.Files[relPath]
+}
+
+// fetchCatalogIndex downloads the remote catalog index. A catalog without one returns an
+// error, and the sync downloads every file as before.
+func fetchCatalogIndex(ctx context.Context, baseURL string, localDirPath string) (*catalogIndex, error) {
+	indexURL := fmt.Sprintf("%s/%s", baseURL, catalogIndexPath)
+	tempPath := filepath.Join(localDirPath, ".cache", "catalog-index.json")
+	defer os.Remove(tempPath)
+
+	if err := downloadFileContext(ctx, indexURL, tempPath); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(tempPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var index catalogIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("error parsing catalog index: %w", err)
+	}
+	if index.Files == nil {
+		return nil, fmt.Errorf("catalog index lists no files")
+	}
+	return &index, nil
+}
+
+// loadSyncedCatalogIndex reads the versions recorded by the last sync, empty when there
+// are none
+func loadSyncedCatalogIndex(localDirPath string) *catalogIndex {
+	synced := &catalogIndex{Files: make(map[string]string)}
+
+	data, err := os.ReadFile(filepath.Join(localDirPath, "Catalog", syncedCatalogIndexFile))
+	if err != nil {
+		return synced
+	}
+	if err := json.Unmarshal(data, synced); err != nil || synced.Files == nil {
+		logging.LogDebug("Warning: Ignoring unreadable synced catalog index: %v", err)
+		return &catalogIndex{Files: make(map[string]string)}
+	}
+	return synced
+}
+
+// saveSyncedCatalogIndex records the versions of the files synced so far
+func saveSyncedCatalogIndex(localDirPath string, synced *catalogIndex) {
+	data, err := json.MarshalIndent(synced, "", "  ")
+	if err != nil {
+		logging.LogDebug("Warning: Could not encode synced catalog index: %v", err)
+		return
+	}
+
+	path := filepath.Join(localDirPath, "Catalog", syncedCatalogIndexFile)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		logging.LogDebug("Warning: Could not save synced catalog index: %v", err)
+	}
+}
+
+// catalogFilePaths returns the preview and manifest of every theme and component in the
+// catalog, sorted so syncs run in the same order
+func catalogFilePaths(catalog *CatalogData) []string {
+	var paths []string
+	add := func(item CatalogItemInfo) {
+		if item.PreviewPath != "" {
+			paths = append(paths, item.PreviewPath)
+		}
+		if item.ManifestPath != "" {
+			paths = append(paths, item.ManifestPath)
+		}
+	}
+
+	for _, item := range catalog.Themes {
+		add(item)
+	}
+	for _, items := range catalog.Components {
+		for _, item := range items {
+			add(item)
+		}
+	}
+
+	sort.Strings(paths)
+	return paths
+}
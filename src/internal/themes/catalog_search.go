@@ -0,0 +1,73 @@
+// src/internal/themes/catalog_search.go
+// Finding catalog themes by part of their name or author
+
+package themes
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CatalogThemeMatches reports whether a catalog theme's name (without .theme) or author
+// contains query, ignoring case. An empty query matches every theme.
+func CatalogThemeMatches(name string, info CatalogItemInfo, query string) bool {
+	query = strings.ToLower(query)
+	return strings.Contains(strings.ToLower(strings.TrimSuffix(name, ".theme")), query) ||
+		strings.Contains(strings.ToLower(info.Author), query)
+}
+
+// SearchCatalogThemes returns the names of the catalog themes matching query and the
+// content filter, sorted
+func SearchCatalogThemes(catalog *CatalogData, query string, content string) []string {
+	var names []string
+	for name, info := range catalog.Themes {
+		if CatalogThemeMatches(name, info, query) && CatalogThemeHasContent(info, content) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CatalogSearchNextChars returns the characters that can follow query and still match a
+// catalog theme passing the content filter, lowercased and sorted. Offering only these
+// keeps typing on a gamepad short.
+func CatalogSearchNextChars(catalog *CatalogData, query string, content string) []string {
+	query = strings.ToLower(query)
+	seen := make(map[rune]bool)
+
+	for name, info := range catalog.Themes {
+		if !CatalogThemeHasContent(info, content) {
+			continue
+		}
+		for _, text := range []string{strings.TrimSuffix(name, ".theme"), info.Author} {
+			text = strings.ToLower(text)
+			if query == "" {
+				for _, r := range text {
+					seen[r] = true
+				}
+				continue
+			}
+
+			// The character after every place query occurs
+			for i := 0; i < len(text); {
+				at := strings.Index(text[i:], query)
+				if at < 0 {
+					break
+				}
+				if r, size := utf8.DecodeRuneInString(text[i+at+len(query):]); size > 0 {
+					seen[r] = true
+				}
+				i += at + 1
+			}
+		}
+	}
+
+	chars := make([]string, 0, len(seen))
+	for r := range seen {
+		chars = append(chars, string(r))
+	}
+	sort.Strings(chars)
+	return chars
+}
@@ -0,0 +1,164 @@
+// src/internal/themes/changelog.go
+// Versioned re-exports: version bumps, generated changelog entries and archived previous exports
+
+package themes
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// versionsDirName holds archived previous exports inside the export folder
+const versionsDirName = ".versions"
+
+// maxChangelogFiles caps how many file names one changelog line lists
+const maxChangelogFiles = 5
+
+// ChangelogEntry records what changed in one version of a theme
+type ChangelogEntry struct {
+	Version string    `json:"version"`
+	Date    time.Time `json:"date"`
+	Changes []string  `json:"changes"`
+}
+
+// packageFileHashes returns the hash of every content file in a package, keyed by its
+// path inside the package. The manifest and preview are left out.
+func packageFileHashes(packagePath string) map[string]string {
+	hashes := make(map[string]string)
+	filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(packagePath, path)
+		if err != nil || rel == "manifest.json" || rel == "preview.png" {
+			return nil
+		}
+
+		if sum, err := hashFile(path); err == nil {
+			hashes[filepath.ToSlash(rel)] = hex.EncodeToString(sum)
+		}
+		return nil
+	})
+	return hashes
+}
+
+// summarizeFiles turns a list of changed files into one changelog line
+func summarizeFiles(verb string, files []string) string {
+	sort.Strings(files)
+
+	names := make([]string, 0, maxChangelogFiles)
+	for i, file := range files {
+		if i == maxChangelogFiles {
+			break
+		}
+		names = append(names, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	}
+
+	line := fmt.Sprintf("%s %d files: %s", verb, len(files), strings.Join(names, ", "))
+	if len(files) == 1 {
+		line = fmt.Sprintf("%s %s", verb, files[0])
+	} else if len(files) > maxChangelogFiles {
+		line += fmt.Sprintf(" and %d more", len(files)-maxChangelogFiles)
+	}
+	return line
+}
+
+// DescribeChanges compares two versions of a theme and returns a changelog line for each kind of change
+func DescribeChanges(oldPath string, oldManifest *ThemeManifest, newPath string, newManifest *ThemeManifest) []string {
+	oldFiles := packageFileHashes(oldPath)
+	newFiles := packageFileHashes(newPath)
+
+	var added, changed, removed []string
+	for file, hash := range newFiles {
+		oldHash, ok := oldFiles[file]
+		switch {
+		case !ok:
+			added = append(added, file)
+		case oldHash != hash:
+			changed = append(changed, file)
+		}
+	}
+	for file := range oldFiles {
+		if _, ok := newFiles[file]; !ok {
+			removed = append(removed, file)
+		}
+	}
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, summarizeFiles("Added", added))
+	}
+	if len(changed) > 0 {
+		changes = append(changes, summarizeFiles("Changed", changed))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, summarizeFiles("Removed", removed))
+	}
+
+	if oldManifest.AccentColors != newManifest.AccentColors {
+		changes = append(changes, "Changed accent colors")
+	}
+	if oldManifest.LEDSettings != newManifest.LEDSettings {
+		changes = append(changes, "Changed LED settings")
+	}
+
+	if len(changes) == 0 {
+		changes = append(changes, "No content changes")
+	}
+	return changes
+}
+
+// addChangelogEntry makes manifest the next version after the theme at previousPath and
+// appends a changelog entry describing what changed since it
+func addChangelogEntry(previousPath, themePath string, manifest *ThemeManifest, logger *Logger) error {
+	previous, err := ValidateTheme(previousPath, logger)
+	if err != nil {
+		return fmt.Errorf("error reading previous version: %w", err)
+	}
+
+	manifest.ThemeInfo.Version = BumpVersion(previous.ThemeInfo.Version)
+	manifest.Changelog = append(previous.Changelog, ChangelogEntry{
+		Version: manifest.ThemeInfo.Version,
+		Date:    time.Now(),
+		Changes: DescribeChanges(previousPath, previous, themePath, manifest),
+	})
+
+	logger.DebugFn("%s goes from version %s to %s", filepath.Base(previousPath), previous.ThemeInfo.Version, manifest.ThemeInfo.Version)
+	return nil
+}
+
+// archiveExport moves an export, and any split volumes next to it, into the export
+// folder's .versions/<name>/<version> so the new version can take its place
+func archiveExport(exportPath string, logger *Logger) error {
+	version := "unknown"
+	if manifest, err := ValidateTheme(exportPath, logger); err == nil && manifest.ThemeInfo.Version != "" {
+		version = manifest.ThemeInfo.Version
+	}
+
+	name := filepath.Base(exportPath)
+	archiveDir := filepath.Join(filepath.Dir(exportPath), versionsDirName, name, version)
+	os.RemoveAll(archiveDir)
+	if err := os.MkdirAll(archiveDir, 0755); err != nil {
+		return fmt.Errorf("error creating archive folder: %w", err)
+	}
+
+	if err := movePackage(exportPath, filepath.Join(archiveDir, name)); err != nil {
+		return fmt.Errorf("error archiving %s: %w", name, err)
+	}
+
+	volumes, _ := filepath.Glob(exportPath + ".zip.*")
+	for _, volume := range volumes {
+		if err := os.Rename(volume, filepath.Join(archiveDir, filepath.Base(volume))); err != nil {
+			logger.DebugFn("Warning: Could not archive volume %s: %v", volume, err)
+		}
+	}
+
+	logger.DebugFn("Archived %s version %s to %s", name, version, archiveDir)
+	return nil
+}
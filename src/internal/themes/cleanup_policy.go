@@ -0,0 +1,62 @@
+// src/internal/themes/cleanup_policy.go
+// Global policy for what a component apply removes before copying its files
+
+package themes
+
+import (
+	"fmt"
+
+	"nextui-themes/internal/logging"
+)
+
+// Cleanup policies
+const (
+	CleanupReplace = "replace" // Remove the files of every system before applying (the default)
+	CleanupMerge   = "merge"   // Only replace systems the package includes, leave the rest alone
+)
+
+// CleanupPolicies are the policies offered in settings, in cycling order
+var CleanupPolicies = []string{CleanupReplace, CleanupMerge}
+
+// GetCleanupPolicy returns the cleanup policy, CleanupReplace unless the user chose otherwise
+func GetCleanupPolicy() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load cleanup policy: %v", err)
+		return CleanupReplace
+	}
+
+	if config.CleanupPolicy == CleanupMerge {
+		return CleanupMerge
+	}
+	return CleanupReplace
+}
+
+// SetCleanupPolicy stores the cleanup policy
+func SetCleanupPolicy(policy string) error {
+	if policy != CleanupReplace && policy != CleanupMerge {
+		return fmt.Errorf("unknown cleanup policy: %s", policy)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.CleanupPolicy = policy
+	return SaveConfig(config)
+}
+
+// overlayPackageSystems returns the system tags an overlay package has overlays for
+func overlayPackageSystems(manifest *OverlayManifest) map[string]bool {
+	systems := make(map[string]bool)
+	for _, tag := range manifest.Content.Systems {
+		systems[tag] = true
+	}
+	for _, mapping := range manifest.PathMappings {
+		if tag := mapping.Metadata["SystemTag"]; tag != "" {
+			systems[tag] = true
+		}
+	}
+	return systems
+}
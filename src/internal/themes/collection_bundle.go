@@ -0,0 +1,334 @@
+// src/internal/themes/collection_bundle.go
+// Collection bundle component: the icon and backgrounds of one collection, applied to any collection name
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+	"nextui-themes/internal/ui"
+)
+
+// collectionAssets lists the asset names of a collection bundle in the order they are processed
+var collectionAssets = []string{
+	"icon",
+	"bg",
+	"bglist",
+}
+
+// CollectionManifest for .col component packages
+type CollectionManifest struct {
+	ComponentInfo ComponentInfo `json:"component_info"`
+	Content       struct {
+		Collection    string `json:"collection"` // Collection the bundle was exported from, offered first on apply
+		Icon          bool   `json:"icon"`
+		Wallpaper     bool   `json:"wallpaper"`
+		ListWallpaper bool   `json:"list_wallpaper"`
+	} `json:"content"`
+	PathMappings map[string]PathMapping `json:"path_mappings"` // Asset name -> mapping
+}
+
+// collectionTarget is the collection the next collection bundle apply writes to; empty uses
+// the collection recorded in the bundle
+var collectionTarget string
+
+// collectionAssetPath returns where an asset of a collection bundle goes on the device
+func collectionAssetPath(systemPaths *system.SystemPaths, collection, asset string) string {
+	mediaDir := filepath.Join(systemPaths.Root, "Collections", collection, ".media")
+	if asset == "icon" {
+		return filepath.Join(mediaDir, collection+".png")
+	}
+	return filepath.Join(mediaDir, asset+".png")
+}
+
+// setCollectionFlag marks an asset as present in a collection bundle's content section
+func setCollectionFlag(manifest *CollectionManifest, asset string) {
+	switch asset {
+	case "icon":
+		manifest.Content.Icon = true
+	case "bg":
+		manifest.Content.Wallpaper = true
+	case "bglist":
+		manifest.Content.ListWallpaper = true
+	}
+}
+
+// ListDeviceCollections returns the names of the collections on the device, sorted
+func ListDeviceCollections() ([]string, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(systemPaths.Root, "Collections"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading collections directory: %w", err)
+	}
+
+	// A collection is a .txt list, usually with a folder of the same name for its media
+	seen := make(map[string]bool)
+	var collections []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if !entry.IsDir() {
+			if !strings.HasSuffix(name, ".txt") {
+				continue
+			}
+			name = strings.TrimSuffix(name, ".txt")
+		}
+		if !seen[name] {
+			seen[name] = true
+			collections = append(collections, name)
+		}
+	}
+
+	sort.Strings(collections)
+	return collections, nil
+}
+
+// GetCollectionBundleDefault returns the collection a bundle was exported from
+func GetCollectionBundleDefault(componentPath string) string {
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return ""
+	}
+	if manifest, ok := manifestObj.(*CollectionManifest); ok {
+		return manifest.Content.Collection
+	}
+	return ""
+}
+
+// ImportCollectionTo applies a collection bundle to the named collection instead of the one
+// recorded in the bundle
+func ImportCollectionTo(ctx context.Context, componentPath, collection string) error {
+	collectionTarget = collection
+	defer func() { collectionTarget = "" }()
+	return ImportComponentContext(ctx, componentPath)
+}
+
+// ImportCollection imports a collection bundle into one collection's .media folder, stopping
+// between files once ctx is done
+func ImportCollection(ctx context.Context, componentPath string) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting collection bundle import: %s", componentPath)
+
+	// Load the component manifest
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return fmt.Errorf("error loading collection manifest: %w", err)
+	}
+
+	// Ensure it's the right type
+	manifest, ok := manifestObj.(*CollectionManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for collection component")
+	}
+
+	collection := collectionTarget
+	if collection == "" {
+		collection = manifest.Content.Collection
+	}
+	if collection == "" {
+		return fmt.Errorf("no collection chosen for %s", manifest.ComponentInfo.Name)
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	beginApplyProgress(len(collectionAssets))
+
+	// Clear what a previous bundle left in this collection, so a bundle without a list
+	// background doesn't leave the old one behind
+	startApplyPhase("cleanup")
+	for _, asset := range collectionAssets {
+		path := collectionAssetPath(systemPaths, collection, asset)
+		if err := removeThemeFile(path); err != nil && !os.IsNotExist(err) {
+			logger.DebugFn("Warning: Could not remove %s: %v", path, err)
+		}
+	}
+
+	startApplyPhase("copy")
+	applied := 0
+	for _, asset := range collectionAssets {
+		if err := nextApplyFile(ctx, asset+".png"); err != nil {
+			return err
+		}
+
+		mapping, ok := manifest.PathMappings[asset]
+		if !ok {
+			continue
+		}
+
+		srcPath := filepath.Join(componentPath, mapping.ThemePath)
+		dstPath := collectionAssetPath(systemPaths, collection, asset)
+		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+			logger.DebugFn("Warning: Failed to copy collection %s: %v", asset, err)
+			continue
+		}
+		applied++
+	}
+
+	// The target differs per apply, so bundles aren't recorded in the global manifest
+	// and aren't replayed by Reapply Current Setup
+	logger.DebugFn("Collection bundle import completed: %s -> %s", componentPath, collection)
+
+	ui.ShowMessage(fmt.Sprintf("'%s' applied to collection %s (%d files)%s", manifest.ComponentInfo.Name, collection, applied, pinnedSummary()), "3")
+
+	return nil
+}
+
+// ExportCollection exports the icon and backgrounds of one collection as a .col component package
+func ExportCollection(name, collection string) (err error) {
+	defer func() { recordOperation("Exported collection", name, err) }()
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting collection bundle export: %s (%s)", name, collection)
+
+	// Create export directory path with .col extension
+	if !strings.HasSuffix(name, ComponentExtension[ComponentCollection]) {
+		name = name + ComponentExtension[ComponentCollection]
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", exportPath, err)
+	}
+
+	manifestObj, err := CreateMinimalComponentManifest(ComponentCollection, name, "")
+	if err != nil {
+		return fmt.Errorf("error creating collection manifest: %w", err)
+	}
+
+	colManifest := manifestObj.(*CollectionManifest)
+	colManifest.Content.Collection = collection
+
+	for _, asset := range collectionAssets {
+		sourcePath := collectionAssetPath(systemPaths, collection, asset)
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			logger.DebugFn("Collection file not found: %s", sourcePath)
+			continue
+		}
+
+		if err := CopyFile(sourcePath, filepath.Join(exportPath, asset+".png")); err != nil {
+			logger.DebugFn("Warning: Could not copy collection %s: %v", asset, err)
+			continue
+		}
+
+		colManifest.PathMappings[asset] = PathMapping{
+			ThemePath:  asset + ".png",
+			SystemPath: sourcePath,
+		}
+		setCollectionFlag(colManifest, asset)
+	}
+
+	if len(colManifest.PathMappings) == 0 {
+		os.RemoveAll(exportPath)
+		return fmt.Errorf("no icon or background found for collection %s", collection)
+	}
+
+	// Use the background as the preview, or the icon if there is none
+	previewPath := filepath.Join(exportPath, "preview.png")
+	previewSource := ""
+	if colManifest.Content.Wallpaper {
+		previewSource = "bg.png"
+	} else if colManifest.Content.Icon {
+		previewSource = "icon.png"
+	}
+	if previewSource != "" {
+		if err := CopyFile(filepath.Join(exportPath, previewSource), previewPath); err != nil {
+			logger.DebugFn("Warning: Could not create preview: %v", err)
+		}
+	} else if err := CreateDefaultPreviewImage(previewPath, ComponentCollection); err != nil {
+		logger.DebugFn("Warning: Could not create default preview: %v", err)
+	}
+
+	// Stamp author credit into the preview
+	stampPreview(exportPath, colManifest.ComponentInfo.Name, colManifest.ComponentInfo.Author, logger)
+
+	if err := WriteComponentManifest(exportPath, colManifest); err != nil {
+		return fmt.Errorf("error writing collection manifest: %w", err)
+	}
+
+	logger.DebugFn("Collection bundle export completed: %s", name)
+
+	ui.ShowMessage(fmt.Sprintf("Collection %s exported to '%s'", collection, name), "3")
+
+	return nil
+}
+
+// UpdateCollectionManifest updates a collection bundle's manifest based on its content
+func UpdateCollectionManifest(componentPath string, systemPaths *system.SystemPaths, logger *Logger) error {
+	logger.DebugFn("Updating collection manifest for: %s", componentPath)
+
+	componentName := filepath.Base(componentPath)
+
+	// Load existing manifest to preserve component_info and the default collection
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		manifestObj, err = CreateComponentManifest(ComponentCollection, componentName)
+		if err != nil {
+			return fmt.Errorf("error creating collection manifest: %w", err)
+		}
+	}
+
+	colManifest, ok := manifestObj.(*CollectionManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for collection component")
+	}
+
+	// Always update component name to match the directory name
+	colManifest.ComponentInfo.Name = componentName
+
+	// Clear existing content data (but preserve component_info)
+	colManifest.Content.Icon = false
+	colManifest.Content.Wallpaper = false
+	colManifest.Content.ListWallpaper = false
+	colManifest.PathMappings = make(map[string]PathMapping)
+
+	// System paths point at the default collection; the apply resolves them again for
+	// the collection the user picks
+	for _, asset := range collectionAssets {
+		if _, err := os.Stat(filepath.Join(componentPath, asset+".png")); err != nil {
+			continue
+		}
+
+		mapping := PathMapping{ThemePath: asset + ".png"}
+		if colManifest.Content.Collection != "" && systemPaths != nil {
+			mapping.SystemPath = collectionAssetPath(systemPaths, colManifest.Content.Collection, asset)
+		}
+		colManifest.PathMappings[asset] = mapping
+		setCollectionFlag(colManifest, asset)
+
+		logger.DebugFn("Added collection asset to manifest: %s", asset)
+	}
+
+	return WriteComponentManifest(componentPath, colManifest)
+}
@@ -0,0 +1,361 @@
+// src/internal/themes/collection_structure.go
+// Audits the Collections folder against NextUI's layout and repairs misnamed or misplaced media
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// NextUI lists a collection for every Collections/<Name>.txt and reads its media from
+// Collections/<Name>/.media: <Name>.png for the icon, bg.png and bglist.png for the
+// backgrounds. A folder that doesn't match a list exactly shows up as a second, empty entry.
+
+// collectionMediaFiles are the file names NextUI reads from a collection's .media folder,
+// besides the icon
+var collectionMediaFiles = []string{"bg.png", "bglist.png"}
+
+// CollectionIssue is one problem found in the Collections folder
+type CollectionIssue struct {
+	Collection string // Collection or folder the problem is in
+	Problem    string // What is wrong
+	Fix        string // What the repair does, or what the user should do when it can't
+	repair     func() error
+}
+
+// CanRepair reports whether the issue is fixed by RepairCollectionStructure
+func (i CollectionIssue) CanRepair() bool {
+	return i.repair != nil
+}
+
+// collectionAudit collects issues while the Collections folder is checked
+type collectionAudit struct {
+	dir    string
+	issues []CollectionIssue
+}
+
+// add records an issue; repair is nil when it needs the user
+func (a *collectionAudit) add(collection, problem, fix string, repair func() error) {
+	a.issues = append(a.issues, CollectionIssue{
+		Collection: collection,
+		Problem:    problem,
+		Fix:        fix,
+		repair:     repair,
+	})
+}
+
+// AuditCollectionStructure checks every collection's folder layout without changing anything
+func AuditCollectionStructure() ([]CollectionIssue, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	audit := &collectionAudit{dir: filepath.Join(systemPaths.Root, "Collections")}
+
+	entries, err := os.ReadDir(audit.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading collections directory: %w", err)
+	}
+
+	// Compare exact names from the listing; the SD card's filesystem ignores case on lookups
+	var lists []string
+	folders := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.IsDir() {
+			folders[name] = true
+		} else if strings.HasSuffix(name, ".txt") {
+			lists = append(lists, strings.TrimSuffix(name, ".txt"))
+		}
+	}
+	sort.Strings(lists)
+
+	for _, collection := range lists {
+		if !folders[collection] {
+			if folder := findCollectionFolder(collection, folders); folder != "" {
+				// Misnamed folder, e.g. "favorites" or "Favorites.txt" for Favorites.txt
+				delete(folders, folder)
+				from := filepath.Join(audit.dir, folder)
+				to := filepath.Join(audit.dir, collection)
+				audit.add(collection,
+					fmt.Sprintf("Folder '%s' doesn't match the collection name", folder),
+					fmt.Sprintf("Rename it to '%s'", collection),
+					func() error { return renameCollectionPath(from, to) })
+				// Its contents are checked on the next pass, once it has the right name
+				continue
+			}
+
+			mediaDir := filepath.Join(audit.dir, collection, ".media")
+			audit.add(collection, "No media folder",
+				fmt.Sprintf("Create %s/.media", collection),
+				func() error { return os.MkdirAll(mediaDir, 0755) })
+			continue
+		}
+
+		delete(folders, collection)
+		audit.checkMedia(collection)
+	}
+
+	// Folders left over belong to no collection and show up as empty duplicates in NextUI
+	var orphans []string
+	for folder := range folders {
+		orphans = append(orphans, folder)
+	}
+	sort.Strings(orphans)
+	for _, folder := range orphans {
+		audit.add(folder, "Folder has no collection list",
+			fmt.Sprintf("Delete the folder, or create %s.txt if it should be a collection", folder), nil)
+	}
+
+	return audit.issues, nil
+}
+
+// findCollectionFolder returns a folder that was meant for a collection but is named differently
+func findCollectionFolder(collection string, folders map[string]bool) string {
+	want := strings.ToLower(strings.TrimSpace(collection))
+	for folder := range folders {
+		name := strings.TrimSuffix(strings.TrimSpace(folder), ".txt")
+		if strings.ToLower(strings.TrimSpace(name)) == want {
+			return folder
+		}
+	}
+	return ""
+}
+
+// checkMedia checks the inside of a collection's folder
+func (a *collectionAudit) checkMedia(collection string) {
+	collectionDir := filepath.Join(a.dir, collection)
+	mediaDir := filepath.Join(collectionDir, ".media")
+
+	entries, err := os.ReadDir(collectionDir)
+	if err != nil {
+		logging.LogDebug("Warning: Could not read %s: %v", collectionDir, err)
+		return
+	}
+
+	hasMedia := false
+	for _, entry := range entries {
+		name := entry.Name()
+		from := filepath.Join(collectionDir, name)
+
+		switch {
+		case name == ".media":
+			hasMedia = entry.IsDir()
+
+		case entry.IsDir() && strings.EqualFold(strings.TrimPrefix(name, "."), "media"):
+			// "media" or ".Media" instead of ".media"
+			a.add(collection,
+				fmt.Sprintf("Media folder is named '%s'", name),
+				"Move its files into .media",
+				func() error { return mergeCollectionMedia(from, mediaDir) })
+
+		case entry.IsDir() && strings.EqualFold(name, collection):
+			// A copy of the collection folder inside itself, usually from a drag and drop
+			nested := filepath.Join(from, ".media")
+			if info, err := os.Stat(nested); err == nil && info.IsDir() {
+				a.add(collection,
+					fmt.Sprintf("Media is in a nested '%s/%s' folder", collection, name),
+					"Move its files up into .media",
+					func() error {
+						if err := mergeCollectionMedia(nested, mediaDir); err != nil {
+							return err
+						}
+						// Only goes away when nothing else was left inside
+						os.Remove(from)
+						return nil
+					})
+			}
+
+		case !entry.IsDir() && isCollectionMediaName(collection, name):
+			// Media files dropped next to .media instead of inside it
+			a.add(collection,
+				fmt.Sprintf("%s is outside the .media folder", name),
+				"Move it into .media",
+				func() error {
+					return moveCollectionFile(from, filepath.Join(mediaDir, collectionMediaTarget(collection, name)))
+				})
+		}
+	}
+
+	if !hasMedia {
+		a.add(collection, "No .media folder",
+			fmt.Sprintf("Create %s/.media", collection),
+			func() error { return os.MkdirAll(mediaDir, 0755) })
+		return
+	}
+
+	// The icon must be named exactly after the collection
+	mediaEntries, err := os.ReadDir(mediaDir)
+	if err != nil {
+		return
+	}
+	iconName := collection + ".png"
+	hasIcon := false
+	for _, entry := range mediaEntries {
+		if entry.Name() == iconName {
+			hasIcon = true
+		}
+	}
+	if hasIcon {
+		return
+	}
+	for _, entry := range mediaEntries {
+		name := entry.Name()
+		if entry.IsDir() || name == iconName || collectionMediaTarget(collection, name) != iconName {
+			continue
+		}
+		from := filepath.Join(mediaDir, name)
+		a.add(collection,
+			fmt.Sprintf("Icon is named '%s'", name),
+			fmt.Sprintf("Rename it to '%s'", iconName),
+			func() error { return renameCollectionPath(from, filepath.Join(mediaDir, iconName)) })
+		break
+	}
+}
+
+// isCollectionMediaName reports whether a file name is one of the media files of a collection
+func isCollectionMediaName(collection, name string) bool {
+	return collectionMediaTarget(collection, name) != ""
+}
+
+// collectionMediaTarget returns the name NextUI expects for a collection media file, or ""
+// when the file isn't collection media. Names are matched ignoring case, and "icon.png"
+// is taken to be the icon.
+func collectionMediaTarget(collection, name string) string {
+	for _, mediaFile := range collectionMediaFiles {
+		if strings.EqualFold(name, mediaFile) {
+			return mediaFile
+		}
+	}
+	if strings.EqualFold(name, collection+".png") || strings.EqualFold(name, "icon.png") {
+		return collection + ".png"
+	}
+	return ""
+}
+
+// renameCollectionPath renames a file or folder, going through a temporary name when only
+// the case changes since FAT32 treats both names as the same file
+func renameCollectionPath(from, to string) error {
+	if strings.EqualFold(from, to) {
+		temp := to + ".rename"
+		if err := os.Rename(from, temp); err != nil {
+			return fmt.Errorf("error renaming %s: %w", from, err)
+		}
+		from = temp
+	} else if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists", to)
+	}
+
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("error renaming %s: %w", from, err)
+	}
+	logging.LogDebug("Renamed %s to %s", from, to)
+	return nil
+}
+
+// moveCollectionFile moves a media file into place, never replacing one that is already there
+func moveCollectionFile(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("%s already exists, remove one of the two copies", to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("error creating %s: %w", filepath.Dir(to), err)
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("error moving %s: %w", from, err)
+	}
+	forgetManagedFile(from)
+	logging.LogDebug("Moved %s to %s", from, to)
+	return nil
+}
+
+// mergeCollectionMedia moves the media files of a misplaced folder into the collection's
+// .media folder and removes the folder once it is empty
+func mergeCollectionMedia(from, mediaDir string) error {
+	collection := filepath.Base(filepath.Dir(mediaDir))
+
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", from, err)
+	}
+
+	var failed []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			failed = append(failed, entry.Name())
+			continue
+		}
+		target := collectionMediaTarget(collection, entry.Name())
+		if target == "" {
+			target = entry.Name()
+		}
+		if err := moveCollectionFile(filepath.Join(from, entry.Name()), filepath.Join(mediaDir, target)); err != nil {
+			logging.LogDebug("Warning: %v", err)
+			failed = append(failed, entry.Name())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not move %s out of %s", strings.Join(failed, ", "), from)
+	}
+	return os.Remove(from)
+}
+
+// collectionRepairPasses bounds how often the audit is rerun; a renamed folder is only
+// checked inside on the pass after the rename
+const collectionRepairPasses = 3
+
+// RepairCollectionStructure fixes every issue that can be fixed automatically, auditing again
+// after each pass until nothing more can be fixed. It returns how many repairs were made and
+// the issues left for the user.
+func RepairCollectionStructure() (int, []CollectionIssue, error) {
+	if err := CheckStorageWritable(); err != nil {
+		return 0, nil, err
+	}
+
+	fixed := 0
+	for pass := 0; pass < collectionRepairPasses; pass++ {
+		issues, err := AuditCollectionStructure()
+		if err != nil {
+			return fixed, nil, err
+		}
+
+		var remaining []CollectionIssue
+		for _, issue := range issues {
+			if issue.repair == nil {
+				remaining = append(remaining, issue)
+				continue
+			}
+			if err := issue.repair(); err != nil {
+				logging.LogDebug("Warning: Could not fix %s (%s): %v", issue.Collection, issue.Problem, err)
+				issue.Fix = fmt.Sprintf("Couldn't fix: %v", err)
+				issue.repair = nil
+				remaining = append(remaining, issue)
+				continue
+			}
+			logging.LogDebug("Fixed %s: %s", issue.Collection, issue.Problem)
+			fixed++
+		}
+
+		if len(remaining) == len(issues) {
+			return fixed, remaining, nil
+		}
+	}
+
+	issues, err := AuditCollectionStructure()
+	return fixed, issues, err
+}
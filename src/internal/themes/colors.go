@@ -0,0 +1,97 @@
+// src/internal/themes/colors.go
+// Parses the color formats found in manifests and writes the one each consumer expects
+
+package themes
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// ParseColor reads an RGB color written as 0xRRGGBB, #RRGGBB, RRGGBB or the short #RGB,
+// in any case and with surrounding spaces
+func ParseColor(value string) (color.NRGBA, error) {
+	hex := strings.TrimSpace(value)
+	switch {
+	case strings.HasPrefix(hex, "0x"), strings.HasPrefix(hex, "0X"):
+		hex = hex[2:]
+	case strings.HasPrefix(hex, "#"):
+		hex = hex[1:]
+	}
+
+	// #RGB is shorthand for #RRGGBB
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color '%s', expected 0xRRGGBB or #RRGGBB", value)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color '%s', expected 0xRRGGBB or #RRGGBB", value)
+	}
+	return color.NRGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 255}, nil
+}
+
+// settingsColor returns a color in the 0xRRGGBB form NextUI reads from minuisettings.txt and
+// the LED settings files. Values that don't parse are written unchanged, as before, and left
+// for the linter to report; empty values stay empty.
+func settingsColor(value string, logger *Logger) string {
+	if strings.TrimSpace(value) == "" {
+		return value
+	}
+
+	c, err := ParseColor(value)
+	if err != nil {
+		logger.DebugFn("Warning: %v", err)
+		return value
+	}
+	return fmt.Sprintf("0x%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// sysfsColor returns a color in the bare RRGGBB form the LED driver takes
+func sysfsColor(value string) (string, error) {
+	c, err := ParseColor(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02X%02X%02X", c.R, c.G, c.B), nil
+}
+
+// checkColor reports a manifest color that doesn't parse. Empty colors are allowed, they
+// leave the device's own setting in place.
+func (l *linter) checkColor(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+	if _, err := ParseColor(value); err != nil {
+		l.add(fmt.Sprintf("%s: %v", field, err),
+			"Write colors as 0xRRGGBB, e.g. '0x9B2257'; '#9B2257' is accepted too")
+	}
+}
+
+// checkAccentColors checks the six accent colors of a manifest
+func (l *linter) checkAccentColors(colors [6]string) {
+	for i, value := range colors {
+		l.checkColor(fmt.Sprintf("accent_colors.color%d", i+1), value)
+	}
+}
+
+// checkLEDColors checks both colors of every LED zone of a manifest
+func (l *linter) checkLEDColors(section string, zones LEDZones) {
+	for _, zone := range []struct {
+		name    string
+		setting LEDSetting
+	}{
+		{"f1_key", zones.F1Key},
+		{"f2_key", zones.F2Key},
+		{"top_bar", zones.TopBar},
+		{"lr_triggers", zones.LRTriggers},
+	} {
+		l.checkColor(fmt.Sprintf("%s.%s.color1", section, zone.name), zone.setting.Color1)
+		l.checkColor(fmt.Sprintf("%s.%s.color2", section, zone.name), zone.setting.Color2)
+	}
+}
@@ -5,13 +5,11 @@ package themes
 
 import (
 	"fmt"
-	"io"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system" // Add this import
 	"os"
 	"path/filepath"
 	"regexp"
-	"strings"
 )
 
 // CopyFile copies a file from src to dst
@@ -39,7 +37,7 @@ func CopyFile(src, dst string) error {
 	}
 	defer dstFile.Close()
 
-	bytes, err := io.Copy(dstFile, srcFile)
+	bytes, err := streamCopy(dstFile, srcFile)
 	if err != nil {
 		logging.LogDebug("Error copying file: %v", err)
 		return fmt.Errorf("failed to copy file: %w", err)
@@ -62,6 +60,7 @@ func EnsureThemeDirectoryStructure() error {
 	directories := []string{
 		filepath.Join(cwd, "Themes"),
 		filepath.Join(cwd, "Exports"),
+		filepath.Join(cwd, "Imports"),
 		filepath.Join(cwd, "Logs"),
 	}
 
@@ -146,39 +145,20 @@ func GetSystemIconDestination(iconSrcPath, iconName, dstPath string, systemPaths
 	logger.DebugFn("Found system tag in icon: %s - Tag: %s", iconName, systemTag)
 
 	// Handle special system icons
-	if iconName == "Recently Played.png" ||
-		iconName == "Collections.png" ||
-		iconName == "Tools.png" {
+	if _, isSpecial := findSpecialDestination(SpecialIcons(), iconName); isSpecial {
 		// These special icons don't need tag-based renaming
 		logger.DebugFn("Special system icon, no renaming needed: %s", iconName)
 		return dstPath, nil
 	}
 
-	// Look for exact ROM directory matching this tag
+	// Look for the ROM directory matching this tag, ignoring case and Unicode composition
 	var exactSystemName string
 	var matchFound bool
 
-	// First pass: look for exact matches
-	for _, system := range systemPaths.Systems {
-		if system.Tag == systemTag {
-			exactSystemName = system.Name
-			matchFound = true
-			logger.DebugFn("Found exact ROM directory match for tag '%s': %s", systemTag, exactSystemName)
-			break
-		}
-	}
-
-	// If no match found, try case-insensitive matching
-	if !matchFound {
-		systemTagLower := strings.ToLower(systemTag)
-		for _, system := range systemPaths.Systems {
-			if strings.ToLower(system.Tag) == systemTagLower {
-				exactSystemName = system.Name
-				matchFound = true
-				logger.DebugFn("Found case-insensitive ROM directory match for tag '%s': %s", systemTag, exactSystemName)
-				break
-			}
-		}
+	if system, ok := systemPaths.SystemForTag(systemTag); ok {
+		exactSystemName = system.Name
+		matchFound = true
+		logger.DebugFn("Found ROM directory match for tag '%s': %s", systemTag, exactSystemName)
 	}
 
 	// If we found a matching ROM directory, rename the icon to match it exactly
@@ -0,0 +1,227 @@
+// src/internal/themes/compatibility.go
+// Checks how much of a package would land on this device, without applying it
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// compatibilityListed is how many ignored files the report names
+const compatibilityListed = 10
+
+// CompatibilityReport counts where a package's files would go if it were applied now
+type CompatibilityReport struct {
+	Landed   int      // Files written to a system, collection or tool that exists
+	Missing  []string // Files for systems, collections or tools this device doesn't have
+	Excluded int      // Files for systems excluded from theming
+	Pinned   int      // Files whose destination is pinned
+	Settings bool     // The package also carries settings, such as accent colors or LEDs
+
+	Unsupported []string // Settings this device can't use, which the apply skips
+}
+
+// Total returns how many files the package maps to the device
+func (r *CompatibilityReport) Total() int {
+	return r.Landed + len(r.Missing) + r.Excluded + r.Pinned
+}
+
+// mappingTarget returns what a mapping decorates and whether it exists on this device. Files
+// for the root menu, fonts and other fixed places always land.
+func mappingTarget(mapping PathMapping, systemPaths *system.SystemPaths) (string, bool) {
+	if tag := mapping.Metadata["SystemTag"]; tag != "" {
+		_, ok := systemPaths.SystemForTag(tag)
+		return fmt.Sprintf("system %s", tag), ok
+	}
+	if name := mapping.Metadata["CollectionName"]; name != "" {
+		_, err := os.Stat(filepath.Join(systemPaths.Root, "Collections", name+".txt"))
+		return fmt.Sprintf("collection %s", name), err == nil
+	}
+	if name := mapping.Metadata["ToolName"]; name != "" {
+		for _, candidate := range []string{name + ".pak", name} {
+			if _, err := os.Stat(filepath.Join(systemPaths.Tools, candidate)); err == nil {
+				return fmt.Sprintf("tool %s", name), true
+			}
+		}
+		return fmt.Sprintf("tool %s", name), false
+	}
+	return "", true
+}
+
+// packageMappings returns every file mapping of a loaded theme or component manifest, and
+// whether the package also carries settings that are applied without files
+func packageMappings(manifest interface{}) ([]PathMapping, bool) {
+	var mappings []PathMapping
+	addKeyed := func(keyed map[string]PathMapping) {
+		for _, mapping := range keyed {
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	switch m := manifest.(type) {
+	case *ThemeManifest:
+		mappings = append(mappings, m.PathMappings.Wallpapers...)
+		mappings = append(mappings, m.PathMappings.Icons...)
+		mappings = append(mappings, m.PathMappings.Overlays...)
+		addKeyed(m.PathMappings.Fonts)
+		addKeyed(m.PathMappings.GameArt)
+		addKeyed(m.PathMappings.Settings)
+		return mappings, m.Content.Settings.AccentsIncluded || m.Content.Settings.LEDsIncluded
+	case *WallpaperManifest:
+		return m.PathMappings, false
+	case *IconManifest:
+		return m.PathMappings, false
+	case *OverlayManifest:
+		return m.PathMappings, false
+	case *FontManifest:
+		addKeyed(m.PathMappings)
+	case *GameArtManifest:
+		addKeyed(m.PathMappings)
+	case *CollectionManifest:
+		addKeyed(m.PathMappings)
+	default:
+		// Accents, LEDs and shaders only change settings
+		return nil, true
+	}
+	return mappings, false
+}
+
+// CheckCompatibility reports how many of an installed package's files would land somewhere on
+// this device and which would be ignored, without applying anything. The package's manifest
+// is brought up to date with its content first, as an apply would.
+func CheckCompatibility(packagePath string) (*CompatibilityReport, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	var manifest interface{}
+	if strings.HasSuffix(packagePath, ".theme") {
+		themeManifest, err := ValidateTheme(packagePath, logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := UpdateManifestFromThemeContent(packagePath, themeManifest, systemPaths, logger); err != nil {
+			logger.DebugFn("Warning: Error updating manifest from content: %v", err)
+		}
+		if err := resolveExtends(packagePath, themeManifest, systemPaths, logger); err != nil {
+			return nil, err
+		}
+		manifest = themeManifest
+	} else {
+		if err := UpdateComponentManifest(packagePath); err != nil {
+			logger.DebugFn("Warning: Error updating component manifest: %v", err)
+		}
+		manifest, err = LoadComponentManifest(packagePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", filepath.Base(packagePath), err)
+		}
+	}
+
+	excluded := loadExcludedSystems()
+	pinned := make(map[string]bool)
+	for _, path := range GetPinnedPaths() {
+		pinned[filepath.Clean(path)] = true
+	}
+
+	mappings, settings := packageMappings(manifest)
+	report := &CompatibilityReport{Settings: settings}
+
+	caps := GetDeviceCapabilities()
+	switch m := manifest.(type) {
+	case *ThemeManifest:
+		if m.Content.Settings.AccentsIncluded && !caps.Accents {
+			report.Unsupported = append(report.Unsupported, "accent colors")
+		}
+		if m.Content.Settings.LEDsIncluded && !caps.LEDs {
+			report.Unsupported = append(report.Unsupported, "LED settings")
+		}
+	case *AccentManifest:
+		if !caps.Accents {
+			report.Unsupported = append(report.Unsupported, "accent colors")
+		}
+	case *LEDManifest:
+		if !caps.LEDs {
+			report.Unsupported = append(report.Unsupported, "LED settings")
+		}
+	}
+	for _, mapping := range mappings {
+		target, ok := mappingTarget(mapping, systemPaths)
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, fmt.Sprintf("%s (no %s)", filepath.ToSlash(mapping.ThemePath), target))
+		case isMappingExcluded(mapping, excluded):
+			report.Excluded++
+		case pinned[filepath.Clean(mapping.SystemPath)]:
+			report.Pinned++
+		default:
+			report.Landed++
+		}
+	}
+	sort.Strings(report.Missing)
+
+	logger.DebugFn("Compatibility of %s: %d of %d files land, %d missing, %d excluded, %d pinned",
+		filepath.Base(packagePath), report.Landed, report.Total(), len(report.Missing), report.Excluded, report.Pinned)
+	return report, nil
+}
+
+// FormatCompatibilityReport renders a compatibility report for display
+func FormatCompatibilityReport(report *CompatibilityReport) string {
+	var b strings.Builder
+
+	unsupported := ""
+	if len(report.Unsupported) > 0 {
+		unsupported = fmt.Sprintf("\nNot supported on this device, skipped: %s", strings.Join(report.Unsupported, ", "))
+	}
+
+	if report.Total() == 0 {
+		switch {
+		case report.Settings && unsupported != "":
+			b.WriteString("Only changes settings" + unsupported)
+		case report.Settings:
+			b.WriteString("Only changes settings, applies fully on this device")
+		default:
+			b.WriteString("Nothing in this package maps to the device")
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "%d of %d files will be applied", report.Landed, report.Total())
+	if report.Settings {
+		b.WriteString(", plus settings")
+	}
+	if len(report.Missing) > 0 {
+		fmt.Fprintf(&b, "\n%d for systems, collections or tools not on this device", len(report.Missing))
+	}
+	if report.Excluded > 0 {
+		fmt.Fprintf(&b, "\n%d for excluded systems", report.Excluded)
+	}
+	if report.Pinned > 0 {
+		fmt.Fprintf(&b, "\n%d for pinned files", report.Pinned)
+	}
+	b.WriteString(unsupported)
+
+	if len(report.Missing) > 0 {
+		b.WriteString("\n\nIgnored:")
+		for i, missing := range report.Missing {
+			if i == compatibilityListed {
+				fmt.Fprintf(&b, "\n...and %d more", len(report.Missing)-compatibilityListed)
+				break
+			}
+			b.WriteString("\n" + missing)
+		}
+	}
+
+	return b.String()
+}
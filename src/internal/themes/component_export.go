@@ -4,6 +4,7 @@
 package themes
 
 import (
+	"context"
 	"fmt"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system"
@@ -15,6 +16,43 @@ import (
 	"strings"
 )
 
+// ExportComponentContext exports the current setup of one component type and returns the
+// package it wrote. Component exports are small, so ctx is checked before and after the
+// export rather than between files; an export that only finished after ctx was done is removed.
+func ExportComponentContext(ctx context.Context, componentType, name string) (string, error) {
+	kind, ok := LookupComponentKind(componentType)
+	if !ok {
+		return "", fmt.Errorf("unknown component type: %s", componentType)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("export stopped: %w", err)
+	}
+
+	defer logging.BeginOperation("export "+componentType, name)()
+
+	// Exporters add the kind's extension to name the same way
+	packageName := name
+	if !strings.HasSuffix(packageName, kind.Extension()) {
+		packageName += kind.Extension()
+	}
+	packagePath, err := exportPackagePath(packageName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := kind.Export(name); err != nil {
+		return "", err
+	}
+
+	logger := &Logger{DebugFn: logging.LogDebug}
+	if err := exportStopped(ctx, packagePath, logger); err != nil {
+		return "", err
+	}
+	recordProvenance(packagePath, Provenance{Origin: ProvenanceExported}, logger)
+	return packagePath, nil
+}
+
 // CreateDefaultPreviewImage creates a default preview image with text
 func CreateDefaultPreviewImage(outputPath string, componentType string) error {
 	// Instead of looking for a placeholder image that doesn't exist,
@@ -34,7 +72,8 @@ func CreateDefaultPreviewImage(outputPath string, componentType string) error {
 	return nil
 }
 
-func ExportWallpapers(name string) error {
+func ExportWallpapers(name string) (err error) {
+	defer func() { recordOperation("Exported wallpapers", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -52,7 +91,10 @@ func ExportWallpapers(name string) error {
 		name = name + ComponentExtension[ComponentWallpaper]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create directories, including the new ListWallpapers directory
 	dirPaths := []string{
@@ -94,6 +136,7 @@ func ExportWallpapers(name string) error {
 	}
 
 	wallpaperManifest := manifestObj.(*WallpaperManifest)
+	wallpaperManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentWallpaper)
 
 	// Get system paths for copying wallpapers
 	systemPaths, err := system.GetSystemPaths()
@@ -104,50 +147,12 @@ func ExportWallpapers(name string) error {
 	// Copy wallpapers to the component package
 	// (Export the actual files but don't add to manifest content or path_mappings)
 
-	// Export root wallpaper
-	rootBg := filepath.Join(systemPaths.Root, "bg.png")
-	if _, err := os.Stat(rootBg); err == nil {
-		destPath := filepath.Join(exportPath, "SystemWallpapers", "Root.png")
-		if err := CopyFile(rootBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy root wallpaper: %v", err)
-		}
-	}
-
-	// Export root media wallpaper
-	rootMediaBg := filepath.Join(systemPaths.Root, ".media", "bg.png")
-	if _, err := os.Stat(rootMediaBg); err == nil {
-		destPath := filepath.Join(exportPath, "SystemWallpapers", "Root-Media.png")
-		if err := CopyFile(rootMediaBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy root media wallpaper: %v", err)
-		}
-	}
-
-	// Export Recently Played wallpaper
-	rpBg := filepath.Join(systemPaths.RecentlyPlayed, ".media", "bg.png")
-	if _, err := os.Stat(rpBg); err == nil {
-		destPath := filepath.Join(exportPath, "SystemWallpapers", "Recently Played.png")
-		if err := CopyFile(rpBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Recently Played wallpaper: %v", err)
-		}
-	}
-
-	// Export Tools wallpaper
-	toolsBg := filepath.Join(systemPaths.Tools, ".media", "bg.png")
-	if _, err := os.Stat(toolsBg); err == nil {
-		destPath := filepath.Join(exportPath, "SystemWallpapers", "Tools.png")
-		if err := CopyFile(toolsBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Tools wallpaper: %v", err)
-		}
-	}
+	// Export special wallpapers such as Root and Tools
+	exportSpecialDestinations(SpecialWallpapers(), exportPath, "SystemWallpapers", "WallpaperType", systemPaths, logger)
+	exportSleepWallpaper(exportPath, sleepWallpaperFile, systemPaths, logger)
 
-	// Export Collections wallpaper
-	collectionsBg := filepath.Join(systemPaths.Root, "Collections", ".media", "bg.png")
-	if _, err := os.Stat(collectionsBg); err == nil {
-		destPath := filepath.Join(exportPath, "SystemWallpapers", "Collections.png")
-		if err := CopyFile(collectionsBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Collections wallpaper: %v", err)
-		}
-	}
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
 
 	// Export system wallpapers and list wallpapers
 	for _, system := range systemPaths.Systems {
@@ -155,6 +160,10 @@ func ExportWallpapers(name string) error {
 			continue // Skip systems without tags
 		}
 
+		if isTagExcluded(system.Tag, excluded) {
+			continue // Skip systems excluded from theming
+		}
+
 		// Main system wallpaper (bg.png)
 		systemBg := filepath.Join(system.MediaPath, "bg.png")
 		if _, err := os.Stat(systemBg); err == nil {
@@ -166,7 +175,8 @@ func ExportWallpapers(name string) error {
 				fileName = fmt.Sprintf("%s (%s).png", system.Name, system.Tag)
 			}
 
-			destPath := filepath.Join(exportPath, "SystemWallpapers", fileName)
+			packageFile := safePackagePath(&wallpaperManifest.ComponentInfo.FileNames, "SystemWallpapers", fileName)
+			destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 			if err := CopyFile(systemBg, destPath); err != nil {
 				logger.DebugFn("Warning: Could not copy system wallpaper for %s: %v", system.Name, err)
 			}
@@ -186,7 +196,8 @@ func ExportWallpapers(name string) error {
 			// Add -list suffix
 			fileName := fmt.Sprintf("%s-list.png", baseFileName)
 
-			destPath := filepath.Join(exportPath, "ListWallpapers", fileName)
+			packageFile := safePackagePath(&wallpaperManifest.ComponentInfo.FileNames, "ListWallpapers", fileName)
+			destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 			if err := CopyFile(systemListBg, destPath); err != nil {
 				logger.DebugFn("Warning: Could not copy system list wallpaper for %s: %v", system.Name, err)
 			} else {
@@ -207,8 +218,8 @@ func ExportWallpapers(name string) error {
 			collectionName := entry.Name()
 			collectionBg := filepath.Join(collectionsDir, collectionName, ".media", "bg.png")
 			if _, err := os.Stat(collectionBg); err == nil {
-				filename := fmt.Sprintf("%s.png", collectionName)
-				destPath := filepath.Join(exportPath, "CollectionWallpapers", filename)
+				packageFile := safePackagePath(&wallpaperManifest.ComponentInfo.FileNames, "CollectionWallpapers", collectionName+".png")
+				destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 				if err := CopyFile(collectionBg, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy collection wallpaper for %s: %v", collectionName, err)
 				}
@@ -216,8 +227,12 @@ func ExportWallpapers(name string) error {
 		}
 	}
 
+	// Export wallpapers of Tools subfolders and custom categories, mapped again on import
+	exportFolderWallpapers(exportPath, "", systemPaths, logger)
+
 	// Create preview image (use Recently Played bg or a default)
 	previewPath := filepath.Join(exportPath, "preview.png")
+	rpBg := filepath.Join(exportPath, "SystemWallpapers", "Recently Played.png")
 	if _, err := os.Stat(rpBg); err == nil {
 		// Use Recently Played bg as preview
 		if err := CopyFile(rpBg, previewPath); err != nil {
@@ -234,6 +249,9 @@ func ExportWallpapers(name string) error {
 		}
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, wallpaperManifest.ComponentInfo.Name, wallpaperManifest.ComponentInfo.Author, logger)
+
 	// Write manifest
 	if err := WriteComponentManifest(exportPath, wallpaperManifest); err != nil {
 		return fmt.Errorf("error writing wallpaper manifest: %w", err)
@@ -247,7 +265,8 @@ func ExportWallpapers(name string) error {
 	return nil
 }
 
-func ExportIcons(name string) error {
+func ExportIcons(name string) (err error) {
+	defer func() { recordOperation("Exported icons", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -265,7 +284,10 @@ func ExportIcons(name string) error {
 		name = name + ComponentExtension[ComponentIcon]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create directories
 	dirPaths := []string{
@@ -306,6 +328,7 @@ func ExportIcons(name string) error {
 	}
 
 	iconManifest := manifestObj.(*IconManifest)
+	iconManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentIcon)
 
 	// Get system paths for copying icons
 	systemPaths, err := system.GetSystemPaths()
@@ -316,37 +339,20 @@ func ExportIcons(name string) error {
 	// Copy icons to the component package
 	// (Export the actual files but don't add to manifest content or path_mappings)
 
-	// Export Recently Played icon
-	rpIcon := filepath.Join(systemPaths.Root, ".media", "Recently Played.png")
-	if _, err := os.Stat(rpIcon); err == nil {
-		destPath := filepath.Join(exportPath, "SystemIcons", "Recently Played.png")
-		if err := CopyFile(rpIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Recently Played icon: %v", err)
-		}
-	}
+	// Export special icons such as Recently Played and Tools
+	exportSpecialDestinations(SpecialIcons(), exportPath, "SystemIcons", "IconType", systemPaths, logger)
 
-	// Export Tools icon
-	toolsParentDir := filepath.Dir(systemPaths.Tools)
-	toolsIcon := filepath.Join(toolsParentDir, ".media", "tg5040.png")
-	if _, err := os.Stat(toolsIcon); err == nil {
-		destPath := filepath.Join(exportPath, "SystemIcons", "Tools.png")
-		if err := CopyFile(toolsIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Tools icon: %v", err)
-		}
-	}
-
-	// Export Collections icon
-	collectionsIcon := filepath.Join(systemPaths.Root, ".media", "Collections.png")
-	if _, err := os.Stat(collectionsIcon); err == nil {
-		destPath := filepath.Join(exportPath, "SystemIcons", "Collections.png")
-		if err := CopyFile(collectionsIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Collections icon: %v", err)
-		}
-	}
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
 
 	// Export system icons
-	systemIconsDir := filepath.Join(systemPaths.Roms, ".media")
-	if _, err := os.Stat(systemIconsDir); err == nil {
+	// Every storage root has its own Roms/.media; a system on several roots is exported once
+	exportedIcons := make(map[string]bool)
+	for _, romsDir := range systemPaths.RomsDirs {
+		systemIconsDir := filepath.Join(romsDir, ".media")
+		if _, err := os.Stat(systemIconsDir); err != nil {
+			continue
+		}
 		entries, err := os.ReadDir(systemIconsDir)
 		if err == nil {
 			for _, entry := range entries {
@@ -359,9 +365,7 @@ func ExportIcons(name string) error {
 				}
 
 				// Skip special icons we already handled
-				if entry.Name() == "Recently Played.png" ||
-					entry.Name() == "Collections.png" ||
-					entry.Name() == "tg5040.png" {
+				if isSpecialIconFile(entry.Name(), systemPaths) {
 					continue
 				}
 
@@ -371,8 +375,19 @@ func ExportIcons(name string) error {
 					continue
 				}
 
+				// Skip icons of systems excluded from theming
+				if isNameExcluded(entry.Name(), excluded) {
+					continue
+				}
+
+				if exportedIcons[entry.Name()] {
+					continue
+				}
+				exportedIcons[entry.Name()] = true
+
 				systemIconPath := filepath.Join(systemIconsDir, entry.Name())
-				destPath := filepath.Join(exportPath, "SystemIcons", entry.Name())
+				packageFile := safePackagePath(&iconManifest.ComponentInfo.FileNames, "SystemIcons", entry.Name())
+				destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 				if err := CopyFile(systemIconPath, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy system icon: %v", err)
 				}
@@ -392,7 +407,8 @@ func ExportIcons(name string) error {
 			toolName := entry.Name()
 			toolIcon := filepath.Join(toolsDir, toolName, ".media", toolName+".png")
 			if _, err := os.Stat(toolIcon); err == nil {
-				destPath := filepath.Join(exportPath, "ToolIcons", fmt.Sprintf("%s.png", toolName))
+				packageFile := safePackagePath(&iconManifest.ComponentInfo.FileNames, "ToolIcons", toolName+".png")
+				destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 				if err := CopyFile(toolIcon, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy tool icon: %v", err)
 				}
@@ -412,7 +428,8 @@ func ExportIcons(name string) error {
 			collectionName := entry.Name()
 			collectionIcon := filepath.Join(collectionsDir, collectionName, ".media", collectionName+".png")
 			if _, err := os.Stat(collectionIcon); err == nil {
-				destPath := filepath.Join(exportPath, "CollectionIcons", fmt.Sprintf("%s.png", collectionName))
+				packageFile := safePackagePath(&iconManifest.ComponentInfo.FileNames, "CollectionIcons", collectionName+".png")
+				destPath := filepath.Join(exportPath, filepath.FromSlash(packageFile))
 				if err := CopyFile(collectionIcon, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy collection icon: %v", err)
 				}
@@ -422,6 +439,7 @@ func ExportIcons(name string) error {
 
 	// Create preview image (use a system icon or default)
 	previewPath := filepath.Join(exportPath, "preview.png")
+	collectionsIcon := filepath.Join(exportPath, "SystemIcons", "Collections.png")
 	if _, err := os.Stat(collectionsIcon); err == nil {
 		// Use Collections icon as preview
 		if err := CopyFile(collectionsIcon, previewPath); err != nil {
@@ -436,6 +454,9 @@ func ExportIcons(name string) error {
 		}
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, iconManifest.ComponentInfo.Name, iconManifest.ComponentInfo.Author, logger)
+
 	// Write manifest
 	if err := WriteComponentManifest(exportPath, iconManifest); err != nil {
 		return fmt.Errorf("error writing icon manifest: %w", err)
@@ -450,7 +471,8 @@ func ExportIcons(name string) error {
 }
 
 // ExportAccents exports current accent settings as a .acc component package
-func ExportAccents(name string) error {
+func ExportAccents(name string) (err error) {
+	defer func() { recordOperation("Exported accents", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -468,7 +490,10 @@ func ExportAccents(name string) error {
 		name = name + ComponentExtension[ComponentAccent]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create export directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -500,6 +525,7 @@ func ExportAccents(name string) error {
 	}
 
 	accentManifest := manifestObj.(*AccentManifest)
+	accentManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentAccent)
 
 	// Read current accent settings
 	settingsPath := "/mnt/SDCARD/.userdata/shared/minuisettings.txt"
@@ -552,6 +578,9 @@ func ExportAccents(name string) error {
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, accentManifest.ComponentInfo.Name, accentManifest.ComponentInfo.Author, logger)
+
 	// Write manifest
 	if err := WriteComponentManifest(exportPath, accentManifest); err != nil {
 		return fmt.Errorf("error writing accent manifest: %w", err)
@@ -566,7 +595,8 @@ func ExportAccents(name string) error {
 }
 
 // ExportLEDs exports current LED settings as a .led component package
-func ExportLEDs(name string) error {
+func ExportLEDs(name string) (err error) {
+	defer func() { recordOperation("Exported LEDs", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -584,7 +614,10 @@ func ExportLEDs(name string) error {
 		name = name + ComponentExtension[ComponentLED]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create export directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -616,6 +649,7 @@ func ExportLEDs(name string) error {
 	}
 
 	ledManifest := manifestObj.(*LEDManifest)
+	ledManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentLED)
 
 	// Read current LED settings
 	settingsPath := "/mnt/SDCARD/.userdata/shared/ledsettings_brick.txt"
@@ -709,7 +743,8 @@ func ExportLEDs(name string) error {
 }
 
 // ExportFonts exports current fonts as a .font component package
-func ExportFonts(name string) error {
+func ExportFonts(name string) (err error) {
+	defer func() { recordOperation("Exported fonts", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -727,7 +762,10 @@ func ExportFonts(name string) error {
 		name = name + ComponentExtension[ComponentFont]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create export directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -759,6 +797,7 @@ func ExportFonts(name string) error {
 	}
 
 	fontManifest := manifestObj.(*FontManifest)
+	fontManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentFont)
 
 	// Define font paths
 	fontPaths := map[string]string{
@@ -777,9 +816,21 @@ func ExportFonts(name string) error {
 
 		dstPath := filepath.Join(exportPath, fontName+".ttf")
 
-		if err := CopyFile(sourcePath, dstPath); err != nil {
-			logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
-			continue
+		// Backups of the stock fonts are kept whole so they can be restored
+		if strings.Contains(fontName, "backup") {
+			if err := CopyFile(sourcePath, dstPath); err != nil {
+				logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
+				continue
+			}
+		} else {
+			subset, err := exportFontFile(sourcePath, dstPath, logger)
+			if err != nil {
+				logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
+				continue
+			}
+			if subset != nil {
+				fontManifest.Subset = subset
+			}
 		}
 
 		logger.DebugFn("Exported font: %s", dstPath)
@@ -791,6 +842,9 @@ func ExportFonts(name string) error {
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, fontManifest.ComponentInfo.Name, fontManifest.ComponentInfo.Author, logger)
+
 	// Write manifest
 	if err := WriteComponentManifest(exportPath, fontManifest); err != nil {
 		return fmt.Errorf("error writing font manifest: %w", err)
@@ -806,11 +860,27 @@ func ExportFonts(name string) error {
 
 // ExportOverlays exports current overlays as a .over component package
 func ExportOverlays(name string) error {
+	return ExportOverlaysForSystems(name, nil)
+}
+
+// ExportOverlaysForSystems exports the overlays of the given system tags as a .over
+// component package; nil exports every system
+func ExportOverlaysForSystems(name string, systemTags []string) (err error) {
+	defer func() { recordOperation("Exported overlays", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
 
-	logger.DebugFn("Starting overlay export: %s", name)
+	logger.DebugFn("Starting overlay export: %s (systems: %v)", name, systemTags)
+
+	// Only the chosen systems, when the user picked some
+	var selected map[string]bool
+	if systemTags != nil {
+		selected = make(map[string]bool)
+		for _, tag := range systemTags {
+			selected[tag] = true
+		}
+	}
 
 	// Get the current directory
 	cwd, err := os.Getwd()
@@ -823,7 +893,10 @@ func ExportOverlays(name string) error {
 		name = name + ComponentExtension[ComponentOverlay]
 	}
 
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -861,6 +934,7 @@ func ExportOverlays(name string) error {
 	}
 
 	overlayManifest := manifestObj.(*OverlayManifest)
+	overlayManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentOverlay)
 
 	// Get system paths
 	systemPaths, err := system.GetSystemPaths()
@@ -882,6 +956,9 @@ func ExportOverlays(name string) error {
 		return fmt.Errorf("error reading overlays directory: %w", err)
 	}
 
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
+
 	// Process each system's overlays
 	hasOverlays := false
 	for _, entry := range entries {
@@ -893,6 +970,17 @@ func ExportOverlays(name string) error {
 		systemOverlaysPath := filepath.Join(overlaysDir, systemTag)
 		exportSystemDir := filepath.Join(systemsDir, systemTag)
 
+		// Skip systems the user didn't pick
+		if selected != nil && !selected[systemTag] {
+			continue
+		}
+
+		// Skip systems excluded from theming
+		if isTagExcluded(systemTag, excluded) {
+			logger.DebugFn("Skipping excluded system overlays: %s", systemTag)
+			continue
+		}
+
 		// Create system directory in export
 		if err := os.MkdirAll(exportSystemDir, 0755); err != nil {
 			logger.DebugFn("Error creating system overlay directory: %v", err)
@@ -938,7 +1026,7 @@ func ExportOverlays(name string) error {
 			// Check if system is already in the list
 			var systemExists bool
 			for _, sys := range overlayManifest.Content.Systems {
-				if sys == systemTag {
+				if system.SameName(sys, systemTag) {
 					systemExists = true
 					break
 				}
@@ -947,6 +1035,9 @@ func ExportOverlays(name string) error {
 			if !systemExists {
 				overlayManifest.Content.Systems = append(overlayManifest.Content.Systems, systemTag)
 			}
+		} else {
+			// Don't ship empty folders for systems without overlays
+			os.Remove(exportSystemDir)
 		}
 	}
 
@@ -960,6 +1051,9 @@ func ExportOverlays(name string) error {
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, overlayManifest.ComponentInfo.Name, overlayManifest.ComponentInfo.Author, logger)
+
 	// Write manifest
 	if err := WriteComponentManifest(exportPath, overlayManifest); err != nil {
 		return fmt.Errorf("error writing overlay manifest: %w", err)
@@ -973,27 +1067,56 @@ func ExportOverlays(name string) error {
 	return nil
 }
 
+// ListOverlaySystems returns the tags of systems that have overlays installed on the device
+func ListOverlaySystems() ([]string, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	overlaysDir := filepath.Join(systemPaths.Root, "Overlays")
+	entries, err := os.ReadDir(overlaysDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading overlays directory: %w", err)
+	}
+
+	var tags []string
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		pngs, _ := filepath.Glob(filepath.Join(overlaysDir, entry.Name(), "*.png"))
+		if len(pngs) > 0 {
+			tags = append(tags, entry.Name())
+		}
+	}
+
+	return tags, nil
+}
+
 // ExportOverlaysForSystem exports overlays for a specific system tag
-func ExportOverlaysForSystem(name string, systemTag string) error {
+func ExportOverlaysForSystem(name string, systemTag string) (err error) {
+	defer func() { recordOperation("Exported overlays", name, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
 
 	logger.DebugFn("Starting overlay export for system %s: %s", systemTag, name)
 
-	// Get the current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .over extension
 	if !strings.HasSuffix(name, ComponentExtension[ComponentOverlay]) {
 		name = name + ComponentExtension[ComponentOverlay]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", name)
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -1013,6 +1136,7 @@ func ExportOverlaysForSystem(name string, systemTag string) error {
 	}
 
 	overlayManifest := manifestObj.(*OverlayManifest)
+	overlayManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentOverlay)
 
 	// Initialize Content.Systems with just this system tag
 	overlayManifest.Content.Systems = []string{systemTag}
@@ -1074,10 +1198,7 @@ func ExportOverlaysForSystem(name string, systemTag string) error {
 			PathMapping{
 				ThemePath:  themePath,
 				SystemPath: srcPath,
-				Metadata: map[string]string{
-					"SystemTag":   systemTag,
-					"OverlayName": file.Name(),
-				},
+				Metadata:   overlayMetadata(systemTag, file.Name(), nil),
 			},
 		)
 
@@ -1095,6 +1216,9 @@ func ExportOverlaysForSystem(name string, systemTag string) error {
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, overlayManifest.ComponentInfo.Name, overlayManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, overlayManifest); err != nil {
 		return fmt.Errorf("error writing overlay manifest: %w", err)
@@ -1115,17 +1239,13 @@ func EnsureComponentDirectories() error {
 	// Create main Components directory
 	componentsDir := filepath.Join(cwd, "Components")
 	if err := os.MkdirAll(componentsDir, 0755); err != nil {
-		return fmt.Errorf("error creating Components directory: %w", componentsDir, err)
+		return fmt.Errorf("error creating Components directory %s: %w", componentsDir, err)
 	}
 
 	// Component subdirectories to create
-	directories := []string{
-		filepath.Join(componentsDir, "Wallpapers"),
-		filepath.Join(componentsDir, "Icons"),
-		filepath.Join(componentsDir, "Accents"),
-		filepath.Join(componentsDir, "Overlays"),
-		filepath.Join(componentsDir, "LEDs"),
-		filepath.Join(componentsDir, "Fonts"),
+	var directories []string
+	for _, kind := range ComponentKinds() {
+		directories = append(directories, filepath.Join(componentsDir, kind.Directory()))
 	}
 
 	// Create each directory
@@ -4,6 +4,7 @@
 package themes
 
 import (
+	"context"
 	"fmt"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system"
@@ -16,21 +17,46 @@ import (
 
 // ImportComponent dispatches to the appropriate import function based on component type
 func ImportComponent(componentPath string) error {
+	return ImportComponentContext(context.Background(), componentPath)
+}
+
+// ImportComponentContext imports a component package, stopping between files once ctx is done
+func ImportComponentContext(ctx context.Context, componentPath string) (err error) {
+	defer func() { recordOperation("Applied component", filepath.Base(componentPath), err) }()
+	defer logging.BeginOperation("apply component", filepath.Base(componentPath))()
+	defer logging.LogMemoryUsage("component apply")
+
 	// First, determine the component type from the extension
 	ext := filepath.Ext(componentPath)
 
-	var componentType string
-	for cType, cExt := range ComponentExtension {
-		if cExt == ext {
-			componentType = cType
-			break
-		}
+	kind, ok := ComponentKindForExtension(ext)
+	if !ok {
+		return fmt.Errorf("unknown component type for extension: %s", ext)
 	}
 
-	if componentType == "" {
-		return fmt.Errorf("unknown component type for extension: %s", ext)
+	if err := CheckKioskMode("applying a component"); err != nil {
+		return err
+	}
+
+	// Fail once with a clear message instead of on every file
+	if err := CheckStorageWritable(); err != nil {
+		logging.LogDebug("Storage check failed: %v", err)
+		return err
 	}
 
+	// Load the user's pinned files for this apply
+	beginPinnedApply()
+	beginTrashBatch()
+	beginApplyReport()
+	beginLinkApply()
+	beginStorageMirrors()
+	beginWriteVerify()
+
+	logger := &Logger{DebugFn: logging.LogDebug}
+	beginApplyTiming()
+	defer finishApplyTiming(logger)
+	startApplyPhase("validation")
+
 	// Update the component's manifest based on its actual content
 	// This is critical for minimal manifests to work properly
 	if err := UpdateComponentManifest(componentPath); err != nil {
@@ -38,27 +64,26 @@ func ImportComponent(componentPath string) error {
 		// Continue anyway, as we can still try to import with the existing manifest
 	}
 
+	// Don't start removing files for an apply that was cancelled while validating
+	if err := applyCancelled(ctx); err != nil {
+		return err
+	}
+
 	// Dispatch to specific import function
-	switch componentType {
-	case ComponentWallpaper:
-		return ImportWallpapers(componentPath)
-	case ComponentIcon:
-		return ImportIcons(componentPath)
-	case ComponentAccent:
-		return ImportAccents(componentPath)
-	case ComponentLED:
-		return ImportLEDs(componentPath)
-	case ComponentFont:
-		return ImportFonts(componentPath)
-	case ComponentOverlay:
-		return ImportOverlays(componentPath)
-	default:
-		return fmt.Errorf("unhandled component type: %s", componentType)
+	startApplyPhase("apply")
+	err = kind.Apply(ctx, componentPath)
+
+	// Persist the files written by this apply, even if it only partially succeeded
+	if saveErr := saveManagedLedger(); saveErr != nil {
+		logging.LogDebug("Warning: Could not save managed files ledger: %v", saveErr)
 	}
+	pruneTrash(logger)
+
+	return err
 }
 
-// ImportWallpapers imports a wallpaper component package
-func ImportWallpapers(componentPath string) error {
+// ImportWallpapers imports a wallpaper component package, stopping between files once ctx is done
+func ImportWallpapers(ctx context.Context, componentPath string) error {
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -88,24 +113,68 @@ func ImportWallpapers(componentPath string) error {
 		logger.DebugFn("Warning: Error ensuring media directories: %v", err)
 	}
 
+	beginApplyProgress(len(manifest.PathMappings))
+	reportApplyStep("Removing old wallpapers")
+	startApplyPhase("cleanup")
+
 	// IMPORTANT: Always clean up existing wallpapers, even if the component has no wallpapers
 	// This allows for "default" packages that clear wallpapers
 	if err := cleanupExistingWallpapers(systemPaths, logger); err != nil {
 		logger.DebugFn("Warning: Error cleaning up existing wallpapers: %v", err)
 	}
 
-	// Import wallpapers based on path mappings
+	// Import wallpapers based on path mappings, skipping excluded systems
+	startApplyPhase("copy")
+	excluded := loadExcludedSystems()
+	effects := GetImageEffects(filepath.Base(componentPath))
 	for _, mapping := range manifest.PathMappings {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		// Leave systems the user excluded from theming alone
+		if isMappingExcluded(mapping, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", mapping.SystemPath)
+			reportSkipped(reportWallpapers)
+			continue
+		}
+
+		// Don't create folders that only existed on the exporter's device
+		if isFolderMappingMissing(mapping) {
+			logger.DebugFn("Skipping wallpaper for missing folder: %s", mapping.Metadata["FolderPath"])
+			continue
+		}
+
 		srcPath := filepath.Join(componentPath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
+		// NextUI can't show animated wallpapers, so apply a still frame or leave them out
+		srcPath, cleanup, err := stillWallpaperSource(srcPath, logger)
+		if err != nil {
+			logger.DebugFn("Warning: Skipping wallpaper: %v", err)
+			reportSkipped(reportWallpapers)
+			continue
+		}
+
 		// Copy the file
-		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+		err = copyMappedFile(srcPath, dstPath, logger)
+		cleanup()
+		reportCopyResult(reportWallpapers, err)
+		if err != nil {
 			logger.DebugFn("Warning: Failed to copy wallpaper: %v", err)
 			// Continue with other files
+			continue
 		}
+
+		dimListWallpaper(dstPath, manifest.Content.ListScrimOpacity, logger)
+		applyComponentEffects(dstPath, effects, logger)
 	}
 
+	// Slideshows replace the system wallpaper with their next one
+	applySlideshows(componentPath, manifest, systemPaths, logger)
+
+	applySleepWallpaper(componentPath, manifest.Content.SleepWallpaper, systemPaths, logger)
+
 	// Update global manifest to track this component
 	componentName := filepath.Base(componentPath)
 	if err := UpdateAppliedComponent(ComponentWallpaper, componentName); err != nil {
@@ -115,13 +184,17 @@ func ImportWallpapers(componentPath string) error {
 	logger.DebugFn("Wallpaper import completed: %s", componentPath)
 
 	// Show success message
-	ui.ShowMessage(fmt.Sprintf("Wallpapers from '%s' applied successfully!", manifest.ComponentInfo.Name), "3")
+	discrepancies := verifyApplyCounts(map[string]int{
+		reportWallpapers: manifest.Content.Count,
+	}, logger)
+
+	ui.ShowMessage(fmt.Sprintf("Wallpapers from '%s' applied successfully!%s", manifest.ComponentInfo.Name, pinnedSummary()+animatedSummary()+verificationSummary(discrepancies)), "3")
 
 	return nil
 }
 
-// ImportIcons imports an icon component package
-func ImportIcons(componentPath string) error {
+// ImportIcons imports an icon component package, stopping between files once ctx is done
+func ImportIcons(ctx context.Context, componentPath string) error {
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -151,14 +224,32 @@ func ImportIcons(componentPath string) error {
 		logger.DebugFn("Warning: Error ensuring media directories: %v", err)
 	}
 
+	beginApplyProgress(len(manifest.PathMappings))
+	reportApplyStep("Removing old icons")
+	startApplyPhase("cleanup")
+
 	// IMPORTANT: Always clean up existing icons, even if the component has no icons
 	// This allows for "default" packages that clear icons
 	if err := cleanupExistingIcons(systemPaths, logger); err != nil {
 		logger.DebugFn("Warning: Error cleaning up existing icons: %v", err)
 	}
 
-	// Import icons based on path mappings
+	// Import icons based on path mappings, skipping excluded systems
+	startApplyPhase("copy")
+	excluded := loadExcludedSystems()
+	effects := GetImageEffects(filepath.Base(componentPath))
 	for _, mapping := range manifest.PathMappings {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		// Leave systems the user excluded from theming alone
+		if isMappingExcluded(mapping, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", mapping.SystemPath)
+			reportSkipped(reportIcons)
+			continue
+		}
+
 		srcPath := filepath.Join(componentPath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -179,10 +270,16 @@ func ImportIcons(componentPath string) error {
 		}
 
 		// Copy the file to the (possibly renamed) destination
-		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+		err := copyMappedFile(srcPath, dstPath, logger)
+		reportCopyResult(reportIcons, err)
+		if err != nil {
 			logger.DebugFn("Warning: Failed to copy icon: %v", err)
 			// Continue with other files
+			continue
 		}
+
+		maskAppliedIcon(dstPath, logger)
+		applyComponentEffects(dstPath, effects, logger)
 	}
 
 	// Update global manifest to track this component
@@ -194,7 +291,11 @@ func ImportIcons(componentPath string) error {
 	logger.DebugFn("Icon import completed: %s", componentPath)
 
 	// Show success message to user
-	ui.ShowMessage(fmt.Sprintf("Icons from '%s' applied successfully!", manifest.ComponentInfo.Name), "3")
+	discrepancies := verifyApplyCounts(map[string]int{
+		reportIcons: manifest.Content.SystemCount + manifest.Content.ToolCount + manifest.Content.CollectionCount,
+	}, logger)
+
+	ui.ShowMessage(fmt.Sprintf("Icons from '%s' applied successfully!%s", manifest.ComponentInfo.Name, pinnedSummary()+verificationSummary(discrepancies)), "3")
 
 	return nil
 }
@@ -219,17 +320,24 @@ func ImportAccents(componentPath string) error {
 		return fmt.Errorf("invalid manifest type for accent component")
 	}
 
+	// Accent colors live in NextUI's shared settings, which this device must have
+	if !GetDeviceCapabilities().Accents {
+		logger.DebugFn("Skipping accent pack %s, NextUI's settings folder was not found", componentPath)
+		ui.ShowMessage(fmt.Sprintf("'%s' was not applied, this device doesn't support NextUI accent colors", manifest.ComponentInfo.Name), "3")
+		return nil
+	}
+
 	// Apply accent settings directly from manifest
 	settingsPath := "/mnt/SDCARD/.userdata/shared/minuisettings.txt"
 
 	// Map of color keys to their values from the manifest
 	colorValues := map[string]string{
-		"color1": manifest.AccentColors.Color1,
-		"color2": manifest.AccentColors.Color2,
-		"color3": manifest.AccentColors.Color3,
-		"color4": manifest.AccentColors.Color4,
-		"color5": manifest.AccentColors.Color5,
-		"color6": manifest.AccentColors.Color6,
+		"color1": settingsColor(manifest.AccentColors.Color1, logger),
+		"color2": settingsColor(manifest.AccentColors.Color2, logger),
+		"color3": settingsColor(manifest.AccentColors.Color3, logger),
+		"color4": settingsColor(manifest.AccentColors.Color4, logger),
+		"color5": settingsColor(manifest.AccentColors.Color5, logger),
+		"color6": settingsColor(manifest.AccentColors.Color6, logger),
 	}
 
 	// Track which color keys we've seen
@@ -352,60 +460,20 @@ func ImportLEDs(componentPath string) error {
 		return fmt.Errorf("invalid manifest type for LED component")
 	}
 
-	// Apply LED settings from manifest
-	settingsPath := "/mnt/SDCARD/.userdata/shared/ledsettings_brick.txt"
-
-	// Create content for ledsettings_brick.txt
-	var content strings.Builder
+	// Without the LED hardware the settings file is never read, so leave it alone
+	if !GetDeviceCapabilities().LEDs {
+		logger.DebugFn("Skipping LED pack %s, this device has no LED hardware", componentPath)
+		ui.ShowMessage(fmt.Sprintf("'%s' was not applied, this device has no LED hardware", manifest.ComponentInfo.Name), "3")
+		return nil
+	}
 
-	// F1 Key
-	content.WriteString("[F1 key]\n")
-	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.F1Key.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.F1Key.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.F1Key.Color2))
-	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.F1Key.Speed))
-	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.F1Key.Brightness))
-	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.F1Key.Trigger))
-	content.WriteString(fmt.Sprintf("inbrightness=%d\n", manifest.LEDSettings.F1Key.InBrightness))
-	content.WriteString("\n")
-
-	// F2 Key
-	content.WriteString("[F2 key]\n")
-	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.F2Key.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.F2Key.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.F2Key.Color2))
-	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.F2Key.Speed))
-	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.F2Key.Brightness))
-	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.F2Key.Trigger))
-	content.WriteString(fmt.Sprintf("inbrightness=%d\n", manifest.LEDSettings.F2Key.InBrightness))
-	content.WriteString("\n")
-
-	// Top bar
-	content.WriteString("[Top bar]\n")
-	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.TopBar.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.TopBar.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.TopBar.Color2))
-	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.TopBar.Speed))
-	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.TopBar.Brightness))
-	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.TopBar.Trigger))
-	content.WriteString(fmt.Sprintf("inbrightness=%d\n", manifest.LEDSettings.TopBar.InBrightness))
-	content.WriteString("\n")
-
-	// L&R triggers
-	content.WriteString("[L&R triggers]\n")
-	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.LRTriggers.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.LRTriggers.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.LRTriggers.Color2))
-	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.LRTriggers.Speed))
-	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.LRTriggers.Brightness))
-	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.LRTriggers.Trigger))
-	content.WriteString(fmt.Sprintf("inbrightness=%d\n", manifest.LEDSettings.LRTriggers.InBrightness))
-	content.WriteString("\n")
-
-	// Write settings to file
-	if err := os.WriteFile(settingsPath, []byte(content.String()), 0644); err != nil {
-		return fmt.Errorf("error writing LED settings: %w", err)
+	// Battery bands and the charging profile replace the settings for the current battery
+	// state, from now and at every boot
+	if err := writeLEDSettingsFile(ledZonesForBattery(manifest)); err != nil {
+		return err
 	}
+	setBootHook("battery-leds", "--battery-leds", isPowerReactive(manifest), logger)
+	setBackgroundBootHook("charging-leds", "--watch-charging", manifest.Charging != nil, logger)
 
 	// Update global manifest to track this component
 	componentName := filepath.Base(componentPath)
@@ -421,8 +489,8 @@ func ImportLEDs(componentPath string) error {
 	return nil
 }
 
-// ImportFonts imports a font component package
-func ImportFonts(componentPath string) error {
+// ImportFonts imports a font component package, stopping between files once ctx is done
+func ImportFonts(ctx context.Context, componentPath string) error {
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -441,8 +509,19 @@ func ImportFonts(componentPath string) error {
 		return fmt.Errorf("invalid manifest type for font component")
 	}
 
+	// Fallback fonts depend on what the firmware supports and the names on the device
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		logger.DebugFn("Warning: Error getting system paths, fallback fonts not used: %v", err)
+		systemPaths = nil
+	}
+
 	// Import fonts based on path mappings
 	for fontName, mapping := range manifest.PathMappings {
+		if err := applyCancelled(ctx); err != nil {
+			return err
+		}
+
 		srcPath := filepath.Join(componentPath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -452,6 +531,10 @@ func ImportFonts(componentPath string) error {
 			continue
 		}
 
+		if !strings.Contains(fontName, "backup") {
+			srcPath = fontSourceForDevice(componentPath, srcPath, manifest, systemPaths, logger)
+		}
+
 		// Only create backups for the main font files, not for backup files
 		if !strings.Contains(fontName, "backup") && !strings.Contains(dstPath, "backup") {
 			// If destination exists and we don't have a backup, create one
@@ -484,6 +567,8 @@ func ImportFonts(componentPath string) error {
 		}
 	}
 
+	applyFontFallbacks(componentPath, manifest, systemPaths, logger)
+
 	// Update global manifest to track this component
 	componentName := filepath.Base(componentPath)
 	if err := UpdateAppliedComponent(ComponentFont, componentName); err != nil {
@@ -498,8 +583,8 @@ func ImportFonts(componentPath string) error {
 	return nil
 }
 
-// ImportOverlays imports an overlay component package
-func ImportOverlays(componentPath string) error {
+// ImportOverlays imports an overlay component package, stopping between files once ctx is done
+func ImportOverlays(ctx context.Context, componentPath string) error {
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
@@ -531,8 +616,15 @@ func ImportOverlays(componentPath string) error {
 	}
 
 	// IMPORTANT: Always clean up existing overlays, even if the component has no overlays
-	// This allows for "default" packages that clear overlays
-	if err := cleanupExistingOverlays(systemPaths, logger); err != nil {
+	// This allows for "default" packages that clear overlays. In merge mode only the
+	// systems the package has overlays for are replaced.
+	if GetCleanupPolicy() == CleanupMerge {
+		systems := overlayPackageSystems(manifest)
+		logger.DebugFn("Merge cleanup: replacing overlays for %d systems only", len(systems))
+		if err := cleanupOverlaysForSystems(systemPaths, systems, logger); err != nil {
+			logger.DebugFn("Warning: Error cleaning up existing overlays: %v", err)
+		}
+	} else if err := cleanupExistingOverlays(systemPaths, logger); err != nil {
 		logger.DebugFn("Warning: Error cleaning up existing overlays: %v", err)
 	}
 
@@ -545,8 +637,19 @@ func ImportOverlays(componentPath string) error {
 		}
 	}
 
-	// Import overlays based on path mappings
+	// Import overlays based on path mappings, skipping excluded systems
+	excluded := loadExcludedSystems()
 	for _, mapping := range manifest.PathMappings {
+		if err := applyCancelled(ctx); err != nil {
+			return err
+		}
+
+		// Leave systems the user excluded from theming alone
+		if isMappingExcluded(mapping, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", mapping.SystemPath)
+			continue
+		}
+
 		srcPath := filepath.Join(componentPath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -566,7 +669,7 @@ func ImportOverlays(componentPath string) error {
 	logger.DebugFn("Overlay import completed: %s", componentPath)
 
 	// Show success message
-	ui.ShowMessage(fmt.Sprintf("Overlays from '%s' applied successfully!", manifest.ComponentInfo.Name), "3")
+	ui.ShowMessage(fmt.Sprintf("Overlays from '%s' applied successfully!%s", manifest.ComponentInfo.Name, pinnedSummary()), "3")
 
 	return nil
 }
@@ -576,52 +679,32 @@ func ImportOverlays(componentPath string) error {
 func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger) error {
 	logger.DebugFn("Cleaning up existing wallpapers")
 
-	// Root wallpaper
-	rootBg := filepath.Join(systemPaths.Root, "bg.png")
-	if err := os.Remove(rootBg); err != nil && !os.IsNotExist(err) {
-		logger.DebugFn("Warning: Could not remove root wallpaper: %v", err)
-	} else if err == nil {
-		logger.DebugFn("Removed root wallpaper: %s", rootBg)
-	}
-
-	// Root media wallpaper
-	rootMediaBg := filepath.Join(systemPaths.Root, ".media", "bg.png")
-	if err := os.Remove(rootMediaBg); err != nil && !os.IsNotExist(err) {
-		logger.DebugFn("Warning: Could not remove root media wallpaper: %v", err)
-	} else if err == nil {
-		logger.DebugFn("Removed root media wallpaper: %s", rootMediaBg)
-	}
-
-	// Recently Played wallpaper
-	rpBg := filepath.Join(systemPaths.RecentlyPlayed, ".media", "bg.png")
-	if err := os.Remove(rpBg); err != nil && !os.IsNotExist(err) {
-		logger.DebugFn("Warning: Could not remove Recently Played wallpaper: %v", err)
-	} else if err == nil {
-		logger.DebugFn("Removed Recently Played wallpaper: %s", rpBg)
+	// A boot must not bring back a slideshow wallpaper once something else is applied. A
+	// preview only asks which files would go, so it leaves slideshows and sleep image alone.
+	if cleanupPreview == nil {
+		stopSlideshows(logger)
 	}
 
-	// Tools wallpaper
-	toolsBg := filepath.Join(systemPaths.Tools, ".media", "bg.png")
-	if err := os.Remove(toolsBg); err != nil && !os.IsNotExist(err) {
-		logger.DebugFn("Warning: Could not remove Tools wallpaper: %v", err)
-	} else if err == nil {
-		logger.DebugFn("Removed Tools wallpaper: %s", toolsBg)
+	// Special wallpapers such as Root and Tools
+	cleanupSpecialDestinations(SpecialWallpapers(), systemPaths, logger)
+	if cleanupPreview == nil {
+		restoreSleepWallpaper(systemPaths, logger)
 	}
 
-	// Collections wallpaper
-	collectionsBg := filepath.Join(systemPaths.Root, "Collections", ".media", "bg.png")
-	if err := os.Remove(collectionsBg); err != nil && !os.IsNotExist(err) {
-		logger.DebugFn("Warning: Could not remove Collections wallpaper: %v", err)
-	} else if err == nil {
-		logger.DebugFn("Removed Collections wallpaper: %s", collectionsBg)
-	}
+	// Systems the user excluded from theming keep their media
+	excluded := loadExcludedSystems()
 
 	// System wallpapers - clean up both bg.png and bglist.png files
 	systemCleanupCount := 0
 	for _, system := range systemPaths.Systems {
+		if isTagExcluded(system.Tag, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", system.Name)
+			continue
+		}
+
 		// Main system background (bg.png)
 		systemBg := filepath.Join(system.MediaPath, "bg.png")
-		if err := os.Remove(systemBg); err != nil && !os.IsNotExist(err) {
+		if err := removeThemeFile(systemBg); err != nil && !os.IsNotExist(err) {
 			logger.DebugFn("Warning: Could not remove %s wallpaper: %v", system.Name, err)
 		} else if err == nil {
 			logger.DebugFn("Removed %s wallpaper: %s", system.Name, systemBg)
@@ -630,7 +713,7 @@ func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger)
 
 		// List background (bglist.png) - ensure this is properly cleaned up
 		systemListBg := filepath.Join(system.MediaPath, "bglist.png")
-		if err := os.Remove(systemListBg); err != nil && !os.IsNotExist(err) {
+		if err := removeThemeFile(systemListBg); err != nil && !os.IsNotExist(err) {
 			logger.DebugFn("Warning: Could not remove %s list wallpaper: %v", system.Name, err)
 		} else if err == nil {
 			logger.DebugFn("Removed %s list wallpaper: %s", system.Name, systemListBg)
@@ -647,13 +730,17 @@ func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger)
 				continue
 			}
 
+			if isNameExcluded(romEntry.Name(), excluded) {
+				continue
+			}
+
 			// Check each ROM system directory
 			romSystemDir := filepath.Join(romsDir, romEntry.Name())
 			mediaDir := filepath.Join(romSystemDir, ".media")
 
 			// Try to clean up any bglist.png files that might be here
 			bglistFile := filepath.Join(mediaDir, "bglist.png")
-			if err := os.Remove(bglistFile); err != nil && !os.IsNotExist(err) {
+			if err := removeThemeFile(bglistFile); err != nil && !os.IsNotExist(err) {
 				logger.DebugFn("Warning: Could not remove potential bglist.png in %s: %v", romEntry.Name(), err)
 			} else if err == nil {
 				logger.DebugFn("Removed additional bglist.png in %s: %s", romEntry.Name(), bglistFile)
@@ -675,7 +762,7 @@ func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger)
 
 			collectionName := entry.Name()
 			collectionBg := filepath.Join(collectionsDir, collectionName, ".media", "bg.png")
-			if err := os.Remove(collectionBg); err != nil && !os.IsNotExist(err) {
+			if err := removeThemeFile(collectionBg); err != nil && !os.IsNotExist(err) {
 				logger.DebugFn("Warning: Could not remove %s collection wallpaper: %v", collectionName, err)
 			} else if err == nil {
 				logger.DebugFn("Removed %s collection wallpaper: %s", collectionName, collectionBg)
@@ -683,6 +770,9 @@ func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger)
 		}
 	}
 
+	// Tools subfolders and custom categories
+	cleanupFolderWallpapers(systemPaths, logger)
+
 	return nil
 }
 
@@ -690,9 +780,15 @@ func cleanupExistingWallpapers(systemPaths *system.SystemPaths, logger *Logger)
 func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error {
 	logger.DebugFn("Cleaning up existing icons")
 
-	// System icons in Roms/.media directory
-	romsMediaDir := filepath.Join(systemPaths.Roms, ".media")
-	if _, err := os.Stat(romsMediaDir); !os.IsNotExist(err) {
+	// Systems the user excluded from theming keep their icons
+	excluded := loadExcludedSystems()
+
+	// System icons in the Roms/.media directory of every storage root
+	for _, romsDir := range systemPaths.RomsDirs {
+		romsMediaDir := filepath.Join(romsDir, ".media")
+		if _, err := os.Stat(romsMediaDir); os.IsNotExist(err) {
+			continue
+		}
 		entries, err := os.ReadDir(romsMediaDir)
 		if err == nil {
 			for _, entry := range entries {
@@ -706,15 +802,17 @@ func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error
 
 				// Skip non-system icons
 				tagRegex := regexp.MustCompile(`\((.*?)\)`)
-				if !tagRegex.MatchString(entry.Name()) &&
-					entry.Name() != "Recently Played.png" &&
-					entry.Name() != "Collections.png" &&
-					entry.Name() != "tg5040.png" {
+				if !tagRegex.MatchString(entry.Name()) && !isSpecialIconFile(entry.Name(), systemPaths) {
+					continue
+				}
+
+				if isNameExcluded(entry.Name(), excluded) {
+					logger.DebugFn("Skipping excluded system icon: %s", entry.Name())
 					continue
 				}
 
 				systemIcon := filepath.Join(romsMediaDir, entry.Name())
-				if err := os.Remove(systemIcon); err != nil && !os.IsNotExist(err) {
+				if err := removeThemeFile(systemIcon); err != nil && !os.IsNotExist(err) {
 					logger.DebugFn("Warning: Could not remove system icon %s: %v", entry.Name(), err)
 				} else if err == nil {
 					logger.DebugFn("Removed system icon: %s", systemIcon)
@@ -723,37 +821,8 @@ func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error
 		}
 	}
 
-	// Root media directory for special icons
-	rootMediaDir := filepath.Join(systemPaths.Root, ".media")
-	if _, err := os.Stat(rootMediaDir); !os.IsNotExist(err) {
-		// Recently Played icon
-		rpIcon := filepath.Join(rootMediaDir, "Recently Played.png")
-		if err := os.Remove(rpIcon); err != nil && !os.IsNotExist(err) {
-			logger.DebugFn("Warning: Could not remove Recently Played icon: %v", err)
-		} else if err == nil {
-			logger.DebugFn("Removed Recently Played icon: %s", rpIcon)
-		}
-
-		// Collections icon
-		collectionsIcon := filepath.Join(rootMediaDir, "Collections.png")
-		if err := os.Remove(collectionsIcon); err != nil && !os.IsNotExist(err) {
-			logger.DebugFn("Warning: Could not remove Collections icon: %v", err)
-		} else if err == nil {
-			logger.DebugFn("Removed Collections icon: %s", collectionsIcon)
-		}
-	}
-
-	// Tools icon - use parent path of Tools since Tools path includes tg5040
-	toolsParentDir := filepath.Dir(systemPaths.Tools) // Gets /mnt/SDCARD/Tools
-	toolsMediaDir := filepath.Join(toolsParentDir, ".media")
-	if _, err := os.Stat(toolsMediaDir); !os.IsNotExist(err) {
-		toolsIcon := filepath.Join(toolsMediaDir, "tg5040.png")
-		if err := os.Remove(toolsIcon); err != nil && !os.IsNotExist(err) {
-			logger.DebugFn("Warning: Could not remove Tools icon: %v", err)
-		} else if err == nil {
-			logger.DebugFn("Removed Tools icon: %s", toolsIcon)
-		}
-	}
+	// Special icons such as Recently Played and Tools
+	cleanupSpecialDestinations(SpecialIcons(), systemPaths, logger)
 
 	// Tool icons
 	toolsDir := filepath.Join(systemPaths.Tools)
@@ -772,7 +841,7 @@ func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error
 			}
 
 			toolIcon := filepath.Join(toolMediaDir, toolName+".png")
-			if err := os.Remove(toolIcon); err != nil && !os.IsNotExist(err) {
+			if err := removeThemeFile(toolIcon); err != nil && !os.IsNotExist(err) {
 				logger.DebugFn("Warning: Could not remove %s tool icon: %v", toolName, err)
 			} else if err == nil {
 				logger.DebugFn("Removed %s tool icon: %s", toolName, toolIcon)
@@ -797,7 +866,7 @@ func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error
 			}
 
 			collectionIcon := filepath.Join(collectionMediaDir, collectionName+".png")
-			if err := os.Remove(collectionIcon); err != nil && !os.IsNotExist(err) {
+			if err := removeThemeFile(collectionIcon); err != nil && !os.IsNotExist(err) {
 				logger.DebugFn("Warning: Could not remove %s collection icon: %v", collectionName, err)
 			} else if err == nil {
 				logger.DebugFn("Removed %s collection icon: %s", collectionName, collectionIcon)
@@ -810,6 +879,12 @@ func cleanupExistingIcons(systemPaths *system.SystemPaths, logger *Logger) error
 
 // cleanupExistingOverlays removes existing overlays before applying new ones
 func cleanupExistingOverlays(systemPaths *system.SystemPaths, logger *Logger) error {
+	return cleanupOverlaysForSystems(systemPaths, nil, logger)
+}
+
+// cleanupOverlaysForSystems removes existing overlays of the given system tags; nil
+// removes the overlays of every system
+func cleanupOverlaysForSystems(systemPaths *system.SystemPaths, systems map[string]bool, logger *Logger) error {
 	logger.DebugFn("Cleaning up existing overlays")
 
 	// Check for overlays directory
@@ -826,6 +901,9 @@ func cleanupExistingOverlays(systemPaths *system.SystemPaths, logger *Logger) er
 		return err
 	}
 
+	// Systems the user excluded from theming keep their overlays
+	excluded := loadExcludedSystems()
+
 	// Process each system's overlays
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -835,6 +913,16 @@ func cleanupExistingOverlays(systemPaths *system.SystemPaths, logger *Logger) er
 		systemTag := entry.Name()
 		systemOverlaysPath := filepath.Join(overlaysDir, systemTag)
 
+		// Leave systems outside the requested set alone
+		if systems != nil && !systems[systemTag] {
+			continue
+		}
+
+		if isTagExcluded(systemTag, excluded) {
+			logger.DebugFn("Skipping excluded system overlays: %s", systemTag)
+			continue
+		}
+
 		// List overlay files for this system
 		overlayFiles, err := os.ReadDir(systemOverlaysPath)
 		if err != nil {
@@ -854,7 +942,7 @@ func cleanupExistingOverlays(systemPaths *system.SystemPaths, logger *Logger) er
 			}
 
 			overlayPath := filepath.Join(systemOverlaysPath, file.Name())
-			if err := os.Remove(overlayPath); err != nil && !os.IsNotExist(err) {
+			if err := removeThemeFile(overlayPath); err != nil && !os.IsNotExist(err) {
 				logger.DebugFn("Warning: Could not remove overlay %s: %v", file.Name(), err)
 			} else if err == nil {
 				logger.DebugFn("Removed overlay: %s", overlayPath)
@@ -863,7 +951,7 @@ func cleanupExistingOverlays(systemPaths *system.SystemPaths, logger *Logger) er
 
 		// Check if system directory is now empty and remove if so
 		remainingFiles, _ := os.ReadDir(systemOverlaysPath)
-		if len(remainingFiles) == 0 {
+		if len(remainingFiles) == 0 && cleanupPreview == nil {
 			if err := os.Remove(systemOverlaysPath); err != nil {
 				logger.DebugFn("Warning: Could not remove empty system overlay directory %s: %v", systemTag, err)
 			} else {
@@ -15,24 +15,17 @@ import (
 
 // ComponentType constants
 const (
-	ComponentWallpaper = "wallpaper"
-	ComponentIcon      = "icon"
-	ComponentAccent    = "accent"
-	ComponentLED       = "led"
-	ComponentFont      = "font"
-	ComponentOverlay   = "overlay"
+	ComponentWallpaper  = "wallpaper"
+	ComponentIcon       = "icon"
+	ComponentAccent     = "accent"
+	ComponentLED        = "led"
+	ComponentFont       = "font"
+	ComponentOverlay    = "overlay"
+	ComponentGameArt    = "gameart"
+	ComponentShader     = "shader"
+	ComponentCollection = "collection"
 )
 
-// ComponentExtension maps component types to their file extensions
-var ComponentExtension = map[string]string{
-	ComponentWallpaper: ".bg",
-	ComponentIcon:      ".icon",
-	ComponentAccent:    ".acc",
-	ComponentLED:       ".led",
-	ComponentFont:      ".font",
-	ComponentOverlay:   ".over",
-}
-
 // ComponentInfo holds common metadata for all component types
 type ComponentInfo struct {
 	Name         string    `json:"name"`
@@ -41,6 +34,14 @@ type ComponentInfo struct {
 	Author       string    `json:"author"`
 	CreationDate time.Time `json:"creation_date"`
 	ExportedBy   string    `json:"exported_by"`
+	License      string    `json:"license,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+
+	// Original names of files renamed to be FAT32-safe on export, keyed by package path
+	FileNames map[string]string `json:"file_names,omitempty"`
+
+	// Where this copy of the package came from, nil when not known
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 // BaseComponentManifest contains the shared structure for all component manifests
@@ -56,6 +57,15 @@ type WallpaperManifest struct {
 		SystemWallpapers     []string `json:"system_wallpapers"`
 		ListWallpapers       []string `json:"list_wallpapers"` // New field for list wallpapers
 		CollectionWallpapers []string `json:"collection_wallpapers"`
+		FolderWallpapers     []string `json:"folder_wallpapers,omitempty"`  // Tools subfolders and custom categories
+		ListScrimOpacity     int      `json:"list_scrim_opacity,omitempty"` // Dims list wallpapers for legibility
+
+		// Several wallpapers per system tag, shown one at a time from Slideshows/<system>/
+		Slideshows      map[string][]string `json:"slideshows,omitempty"`
+		SlideshowPolicy string              `json:"slideshow_policy,omitempty"` // "apply" (default) or "boot"
+
+		// Suspend screen image, applied only on platforms that show one
+		SleepWallpaper string `json:"sleep_wallpaper,omitempty"`
 	} `json:"content"`
 	PathMappings []PathMapping `json:"path_mappings"`
 }
@@ -89,13 +99,10 @@ type AccentManifest struct {
 
 // LEDManifest for .led component packages
 type LEDManifest struct {
-	ComponentInfo ComponentInfo `json:"component_info"`
-	LEDSettings   struct {
-		F1Key      LEDSetting `json:"f1_key"`
-		F2Key      LEDSetting `json:"f2_key"`
-		TopBar     LEDSetting `json:"top_bar"`
-		LRTriggers LEDSetting `json:"lr_triggers"`
-	} `json:"led_settings"`
+	ComponentInfo ComponentInfo    `json:"component_info"`
+	LEDSettings   LEDZones         `json:"led_settings"`
+	BatteryBands  []LEDBatteryBand `json:"battery_bands,omitempty"` // Settings used instead while the battery runs low
+	Charging      *LEDZones        `json:"charging,omitempty"`      // Settings used instead while plugged in
 }
 
 // FontManifest for .font component packages
@@ -106,6 +113,23 @@ type FontManifest struct {
 		NextReplaced bool `json:"next_replaced"`
 	} `json:"content"`
 	PathMappings map[string]PathMapping `json:"path_mappings"`
+
+	// Font files in the pack's Fallbacks folder, tried in this order for characters the
+	// main fonts lack, e.g. CJK glyphs for Japanese ROM names
+	Fallbacks []string `json:"fallbacks,omitempty"`
+
+	// Character ranges the fonts were subset to on export, see FontSubsetRanges
+	Subset []string `json:"subset,omitempty"`
+}
+
+// GameArtManifest for .art component packages
+type GameArtManifest struct {
+	ComponentInfo ComponentInfo `json:"component_info"`
+	Content       struct {
+		PlaceholderReplaced bool `json:"placeholder_replaced"`
+		FrameReplaced       bool `json:"frame_replaced"`
+	} `json:"content"`
+	PathMappings map[string]PathMapping `json:"path_mappings"`
 }
 
 // OverlayManifest for .over component packages
@@ -129,51 +153,22 @@ func CreateMinimalComponentManifest(componentType string, name string, author st
 		ExportedBy:   GetVersionString(),
 	}
 
-	// Create appropriate struct based on component type
-	switch componentType {
-	// Part of CreateMinimalComponentManifest function - just the relevant case:
-	case ComponentWallpaper:
-		var manifest WallpaperManifest
-		manifest.ComponentInfo = info
-		// Initialize content section with empty values
-		manifest.Content.Count = 0
-		manifest.Content.SystemWallpapers = []string{}
-		manifest.Content.ListWallpapers = []string{} // Initialize the new field
-		manifest.Content.CollectionWallpapers = []string{}
-		// Leave path_mappings empty
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	case ComponentIcon:
-		var manifest IconManifest
-		manifest.ComponentInfo = info
-		// Initialize content section with empty values
-		manifest.Content.SystemCount = 0
-		manifest.Content.ToolCount = 0
-		manifest.Content.CollectionCount = 0
-		manifest.Content.SystemIcons = []string{}
-		manifest.Content.ToolIcons = []string{}
-		manifest.Content.CollectionIcons = []string{}
-		// Leave path_mappings empty
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	case ComponentAccent:
-		var manifest AccentManifest
-		manifest.ComponentInfo = info
-		// Initialize with default values
-		manifest.AccentColors.Color1 = "0xFFFFFF"
-		manifest.AccentColors.Color2 = "0x9B2257"
-		manifest.AccentColors.Color3 = "0x1E2329"
-		manifest.AccentColors.Color4 = "0xFFFFFF"
-		manifest.AccentColors.Color5 = "0x000000"
-		manifest.AccentColors.Color6 = "0xFFFFFF"
-		return &manifest, nil
-
-	case ComponentLED:
-		var manifest LEDManifest
-		manifest.ComponentInfo = info
-		// Initialize with default values
+	kind, ok := LookupComponentKind(componentType)
+	if !ok {
+		return nil, fmt.Errorf("unknown component type: %s", componentType)
+	}
+	manifest := kind.NewManifest(info)
+
+	// Start settings-only packages from the stock NextUI values
+	switch m := manifest.(type) {
+	case *AccentManifest:
+		m.AccentColors.Color1 = "0xFFFFFF"
+		m.AccentColors.Color2 = "0x9B2257"
+		m.AccentColors.Color3 = "0x1E2329"
+		m.AccentColors.Color4 = "0xFFFFFF"
+		m.AccentColors.Color5 = "0x000000"
+		m.AccentColors.Color6 = "0xFFFFFF"
+	case *LEDManifest:
 		initLEDSetting := func(led *LEDSetting) {
 			led.Effect = 1
 			led.Color1 = "0xFFFFFF"
@@ -183,34 +178,12 @@ func CreateMinimalComponentManifest(componentType string, name string, author st
 			led.Trigger = 1
 			led.InBrightness = 100
 		}
-		initLEDSetting(&manifest.LEDSettings.F1Key)
-		initLEDSetting(&manifest.LEDSettings.F2Key)
-		initLEDSetting(&manifest.LEDSettings.TopBar)
-		initLEDSetting(&manifest.LEDSettings.LRTriggers)
-		return &manifest, nil
-
-	case ComponentFont:
-		var manifest FontManifest
-		manifest.ComponentInfo = info
-		// Initialize content flags
-		manifest.Content.OGReplaced = false
-		manifest.Content.NextReplaced = false
-		// Initialize path_mappings
-		manifest.PathMappings = make(map[string]PathMapping)
-		return &manifest, nil
-
-	case ComponentOverlay:
-		var manifest OverlayManifest
-		manifest.ComponentInfo = info
-		// Initialize content
-		manifest.Content.Systems = []string{}
-		// Leave path_mappings empty
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	default:
-		return nil, fmt.Errorf("unknown component type: %s", componentType)
+		initLEDSetting(&m.LEDSettings.F1Key)
+		initLEDSetting(&m.LEDSettings.F2Key)
+		initLEDSetting(&m.LEDSettings.TopBar)
+		initLEDSetting(&m.LEDSettings.LRTriggers)
 	}
+	return manifest, nil
 }
 
 // CreateMinimalThemeManifest creates a minimal theme manifest with just essential information
@@ -240,6 +213,8 @@ func CreateMinimalThemeManifest(themeName string, author string) *ThemeManifest
 	manifest.Content.Fonts.OGReplaced = false
 	manifest.Content.Fonts.NextReplaced = false
 
+	manifest.Content.GameArt.Present = false
+
 	manifest.Content.Settings.AccentsIncluded = false
 	manifest.Content.Settings.LEDsIncluded = false
 
@@ -248,6 +223,7 @@ func CreateMinimalThemeManifest(themeName string, author string) *ThemeManifest
 	manifest.PathMappings.Icons = []PathMapping{}
 	manifest.PathMappings.Overlays = []PathMapping{}
 	manifest.PathMappings.Fonts = make(map[string]PathMapping)
+	manifest.PathMappings.GameArt = make(map[string]PathMapping)
 	manifest.PathMappings.Settings = make(map[string]PathMapping)
 
 	// Initialize default accent colors
@@ -291,57 +267,11 @@ func CreateComponentManifest(componentType string, name string) (interface{}, er
 	// Instead of hardcoding "User" here
 	info.Author = ""
 
-	// Create manifest based on component type
-	switch componentType {
-	case ComponentWallpaper:
-		var manifest WallpaperManifest
-		manifest.ComponentInfo = info
-		manifest.Content.Count = 0
-		manifest.Content.SystemWallpapers = []string{}
-		manifest.Content.CollectionWallpapers = []string{}
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	case ComponentIcon:
-		var manifest IconManifest
-		manifest.ComponentInfo = info
-		manifest.Content.SystemCount = 0
-		manifest.Content.ToolCount = 0
-		manifest.Content.CollectionCount = 0
-		manifest.Content.SystemIcons = []string{}
-		manifest.Content.ToolIcons = []string{}
-		manifest.Content.CollectionIcons = []string{}
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	case ComponentAccent:
-		var manifest AccentManifest
-		manifest.ComponentInfo = info
-		return &manifest, nil
-
-	case ComponentLED:
-		var manifest LEDManifest
-		manifest.ComponentInfo = info
-		return &manifest, nil
-
-	case ComponentFont:
-		var manifest FontManifest
-		manifest.ComponentInfo = info
-		manifest.Content.OGReplaced = false
-		manifest.Content.NextReplaced = false
-		manifest.PathMappings = make(map[string]PathMapping)
-		return &manifest, nil
-
-	case ComponentOverlay:
-		var manifest OverlayManifest
-		manifest.ComponentInfo = info
-		manifest.Content.Systems = []string{}
-		manifest.PathMappings = []PathMapping{}
-		return &manifest, nil
-
-	default:
+	kind, ok := LookupComponentKind(componentType)
+	if !ok {
 		return nil, fmt.Errorf("unknown component type: %s", componentType)
 	}
+	return kind.NewManifest(info), nil
 }
 
 // WriteComponentManifest writes a component manifest to the specified directory
@@ -363,8 +293,34 @@ func WriteComponentManifest(componentPath string, manifest interface{}) error {
 	return nil
 }
 
+// componentManifest is implemented by every component manifest type
+type componentManifest interface {
+	componentInfo() *ComponentInfo
+}
+
+func (m *WallpaperManifest) componentInfo() *ComponentInfo  { return &m.ComponentInfo }
+func (m *IconManifest) componentInfo() *ComponentInfo       { return &m.ComponentInfo }
+func (m *AccentManifest) componentInfo() *ComponentInfo     { return &m.ComponentInfo }
+func (m *LEDManifest) componentInfo() *ComponentInfo        { return &m.ComponentInfo }
+func (m *FontManifest) componentInfo() *ComponentInfo       { return &m.ComponentInfo }
+func (m *OverlayManifest) componentInfo() *ComponentInfo    { return &m.ComponentInfo }
+func (m *GameArtManifest) componentInfo() *ComponentInfo    { return &m.ComponentInfo }
+func (m *ShaderManifest) componentInfo() *ComponentInfo     { return &m.ComponentInfo }
+func (m *CollectionManifest) componentInfo() *ComponentInfo { return &m.ComponentInfo }
+
+// GetComponentInfo returns the shared component_info block of any loaded component manifest
+func GetComponentInfo(manifest interface{}) *ComponentInfo {
+	if m, ok := manifest.(componentManifest); ok {
+		return m.componentInfo()
+	}
+	return nil
+}
+
 // LoadComponentManifest loads a component manifest from the specified directory
 func LoadComponentManifest(componentPath string) (interface{}, error) {
+	if err := ensureJSONManifest(componentPath, &Logger{DebugFn: logging.LogDebug}); err != nil {
+		return nil, err
+	}
 	manifestPath := filepath.Join(componentPath, "manifest.json")
 
 	// Check if manifest exists
@@ -387,51 +343,14 @@ func LoadComponentManifest(componentPath string) (interface{}, error) {
 		return nil, fmt.Errorf("error parsing component manifest: %w", err)
 	}
 
-	// Based on the component type, unmarshal into the appropriate struct
-	switch baseManifest.ComponentInfo.Type {
-	case ComponentWallpaper:
-		var manifest WallpaperManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing wallpaper manifest: %w", err)
-		}
-		return &manifest, nil
-
-	case ComponentIcon:
-		var manifest IconManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing icon manifest: %w", err)
-		}
-		return &manifest, nil
-
-	case ComponentAccent:
-		var manifest AccentManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing accent manifest: %w", err)
-		}
-		return &manifest, nil
-
-	case ComponentLED:
-		var manifest LEDManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing LED manifest: %w", err)
-		}
-		return &manifest, nil
-
-	case ComponentFont:
-		var manifest FontManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing font manifest: %w", err)
-		}
-		return &manifest, nil
-
-	case ComponentOverlay:
-		var manifest OverlayManifest
-		if err := json.Unmarshal(data, &manifest); err != nil {
-			return nil, fmt.Errorf("error parsing overlay manifest: %w", err)
-		}
-		return &manifest, nil
-
-	default:
+	// Unmarshal into the manifest struct of the component's kind
+	kind, ok := LookupComponentKind(baseManifest.ComponentInfo.Type)
+	if !ok {
 		return nil, fmt.Errorf("unknown component type: %s", baseManifest.ComponentInfo.Type)
 	}
+	manifest := kind.NewManifest(ComponentInfo{})
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("error parsing %s manifest: %w", kind.Type(), err)
+	}
+	return manifest, nil
 }
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -17,18 +16,11 @@ import (
 func UpdateComponentManifest(componentPath string) error {
 	// Determine component type from file extension
 	ext := filepath.Ext(componentPath)
-
-	var componentType string
-	for cType, cExt := range ComponentExtension {
-		if cExt == ext {
-			componentType = cType
-			break
-		}
-	}
-
-	if componentType == "" {
+	kind, ok := ComponentKindForExtension(ext)
+	if !ok {
 		return fmt.Errorf("unknown component type for extension: %s", ext)
 	}
+	componentType := kind.Type()
 
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
@@ -36,8 +28,10 @@ func UpdateComponentManifest(componentPath string) error {
 
 	logger.DebugFn("Updating manifest for component: %s (type: %s)", componentPath, componentType)
 
+	defer beginScanPass()()
+
 	// Create a system paths instance for reference
-	systemPaths, err := system.GetSystemPaths()
+	systemPaths, err := scanSystemPaths()
 	if err != nil {
 		logger.DebugFn("Warning: Error getting system paths: %v", err)
 		// Continue anyway, as we can still update most of the manifest
@@ -47,53 +41,13 @@ func UpdateComponentManifest(componentPath string) error {
 	var existingAuthor string
 	manifestObj, err := LoadComponentManifest(componentPath)
 	if err == nil {
-		// Extract author from existing manifest based on component type
-		switch componentType {
-		case ComponentWallpaper:
-			if m, ok := manifestObj.(*WallpaperManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
-		case ComponentIcon:
-			if m, ok := manifestObj.(*IconManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
-		case ComponentOverlay:
-			if m, ok := manifestObj.(*OverlayManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
-		case ComponentFont:
-			if m, ok := manifestObj.(*FontManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
-		case ComponentAccent:
-			if m, ok := manifestObj.(*AccentManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
-		case ComponentLED:
-			if m, ok := manifestObj.(*LEDManifest); ok && m.ComponentInfo.Author != "" {
-				existingAuthor = m.ComponentInfo.Author
-			}
+		if info := GetComponentInfo(manifestObj); info != nil {
+			existingAuthor = info.Author
 		}
 	}
 
-	// Dispatch to specific update function based on component type
-	var updateErr error
-	switch componentType {
-	case ComponentWallpaper:
-		updateErr = UpdateWallpaperManifest(componentPath, systemPaths, logger)
-	case ComponentIcon:
-		updateErr = UpdateIconManifest(componentPath, systemPaths, logger)
-	case ComponentOverlay:
-		updateErr = UpdateOverlayManifest(componentPath, systemPaths, logger)
-	case ComponentFont:
-		updateErr = UpdateFontManifest(componentPath, logger)
-	case ComponentAccent:
-		updateErr = UpdateAccentManifest(componentPath, logger)
-	case ComponentLED:
-		updateErr = UpdateLEDManifest(componentPath, logger)
-	default:
-		return fmt.Errorf("unhandled component type: %s", componentType)
-	}
+	// Let the component's kind rescan its content
+	updateErr := kind.Scan(componentPath, systemPaths, logger)
 
 	// If we had an existing author, restore it after the update
 	if existingAuthor != "" && updateErr == nil {
@@ -101,38 +55,9 @@ func UpdateComponentManifest(componentPath string) error {
 		updatedManifest, err := LoadComponentManifest(componentPath)
 		if err == nil {
 			// Set the author back to the original value
-			switch componentType {
-			case ComponentWallpaper:
-				if m, ok := updatedManifest.(*WallpaperManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					// Write the manifest back
-					WriteComponentManifest(componentPath, m)
-				}
-			case ComponentIcon:
-				if m, ok := updatedManifest.(*IconManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					WriteComponentManifest(componentPath, m)
-				}
-			case ComponentOverlay:
-				if m, ok := updatedManifest.(*OverlayManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					WriteComponentManifest(componentPath, m)
-				}
-			case ComponentFont:
-				if m, ok := updatedManifest.(*FontManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					WriteComponentManifest(componentPath, m)
-				}
-			case ComponentAccent:
-				if m, ok := updatedManifest.(*AccentManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					WriteComponentManifest(componentPath, m)
-				}
-			case ComponentLED:
-				if m, ok := updatedManifest.(*LEDManifest); ok {
-					m.ComponentInfo.Author = existingAuthor
-					WriteComponentManifest(componentPath, m)
-				}
+			if info := GetComponentInfo(updatedManifest); info != nil {
+				info.Author = existingAuthor
+				WriteComponentManifest(componentPath, updatedManifest)
 			}
 		}
 	}
@@ -169,12 +94,24 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 	wallpaperManifest.Content.SystemWallpapers = []string{}
 	wallpaperManifest.Content.ListWallpapers = []string{} // Clear the list wallpapers array
 	wallpaperManifest.Content.CollectionWallpapers = []string{}
+	wallpaperManifest.Content.Slideshows = nil
 	wallpaperManifest.PathMappings = []PathMapping{}
 
+	wallpaperManifest.Content.SleepWallpaper = ""
+	if _, err := scanStat(filepath.Join(componentPath, sleepWallpaperFile)); err == nil {
+		wallpaperManifest.Content.SleepWallpaper = sleepWallpaperFile
+	}
+
+	// Slideshows show one wallpaper per system at a time, chosen when applied
+	if slideshows := scanSlideshows(componentPath, logger); len(slideshows) > 0 {
+		wallpaperManifest.Content.Slideshows = slideshows
+		wallpaperManifest.Content.Count += len(slideshows)
+	}
+
 	// Check for wallpapers in SystemWallpapers directory
 	systemWallpapersDir := filepath.Join(componentPath, "SystemWallpapers")
-	if _, err := os.Stat(systemWallpapersDir); err == nil {
-		entries, err := os.ReadDir(systemWallpapersDir)
+	if _, err := scanStat(systemWallpapersDir); err == nil {
+		entries, err := scanReadDir(systemWallpapersDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -201,42 +138,12 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 				var systemPath string
 				var metadata map[string]string
 
-				// Special case handling for predefined names
-				switch strings.TrimSuffix(fileName, ".png") {
-				case "Root":
-					systemPath = filepath.Join(systemPaths.Root, "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Main",
-					}
-
-				case "Root-Media":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Media",
-					}
-
-				case "Recently Played":
-					systemPath = filepath.Join(systemPaths.RecentlyPlayed, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Recently Played",
-						"WallpaperType": "Media",
-					}
-
-				case "Tools":
-					systemPath = filepath.Join(systemPaths.Tools, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Tools",
-						"WallpaperType": "Media",
-					}
-
-				case "Collections":
-					systemPath = filepath.Join(systemPaths.Root, "Collections", ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Collections",
-						"WallpaperType": "Media",
-					}
+				// Special destinations such as Root and Tools come from the registry
+				special, isSpecial := findSpecialDestination(SpecialWallpapers(), fileName)
+				switch {
+				case isSpecial:
+					systemPath = special.SystemPath(systemPaths)
+					metadata = special.Metadata("WallpaperType")
 
 				default:
 					// Check for system tag in filename
@@ -248,17 +155,14 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 
 						// Find matching system by tag
 						var systemFound bool
-						for _, system := range systemPaths.Systems {
-							if system.Tag == systemTag {
-								systemPath = filepath.Join(system.MediaPath, "bg.png")
-								metadata = map[string]string{
-									"SystemName":    system.Name,
-									"SystemTag":     systemTag,
-									"WallpaperType": "System",
-								}
-								systemFound = true
-								break
+						if system, ok := systemPaths.SystemForTag(systemTag); ok {
+							systemPath = filepath.Join(system.MediaPath, "bg.png")
+							metadata = map[string]string{
+								"SystemName":    system.Name,
+								"SystemTag":     systemTag,
+								"WallpaperType": "System",
 							}
+							systemFound = true
 						}
 
 						// If system not found, create a default path
@@ -297,8 +201,8 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 
 	// NEW: Check for wallpapers in ListWallpapers directory
 	listWallpapersDir := filepath.Join(componentPath, "ListWallpapers")
-	if _, err := os.Stat(listWallpapersDir); err == nil {
-		entries, err := os.ReadDir(listWallpapersDir)
+	if _, err := scanStat(listWallpapersDir); err == nil {
+		entries, err := scanReadDir(listWallpapersDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -335,28 +239,25 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 
 					// Find matching system by tag
 					var systemFound bool
-					for _, system := range systemPaths.Systems {
-						if system.Tag == systemTag {
-							systemPath := filepath.Join(system.MediaPath, "bglist.png")
-							metadata := map[string]string{
-								"SystemName":    system.Name,
-								"SystemTag":     systemTag,
-								"WallpaperType": "List",
-							}
-
-							wallpaperManifest.PathMappings = append(
-								wallpaperManifest.PathMappings,
-								PathMapping{
-									ThemePath:  filePath,
-									SystemPath: systemPath,
-									Metadata:   metadata,
-								},
-							)
-							wallpaperManifest.Content.Count++
-							logger.DebugFn("Added list wallpaper to manifest: %s", fileName)
-							systemFound = true
-							break
+					if system, ok := systemPaths.SystemForTag(systemTag); ok {
+						systemPath := filepath.Join(system.MediaPath, "bglist.png")
+						metadata := map[string]string{
+							"SystemName":    system.Name,
+							"SystemTag":     systemTag,
+							"WallpaperType": "List",
 						}
+
+						wallpaperManifest.PathMappings = append(
+							wallpaperManifest.PathMappings,
+							PathMapping{
+								ThemePath:  filePath,
+								SystemPath: systemPath,
+								Metadata:   metadata,
+							},
+						)
+						wallpaperManifest.Content.Count++
+						logger.DebugFn("Added list wallpaper to manifest: %s", fileName)
+						systemFound = true
 					}
 
 					// If system not found, create a default path
@@ -390,8 +291,8 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 	// Rest of function (for collection wallpapers) remains unchanged...
 	// Check for wallpapers in CollectionWallpapers directory
 	collectionWallpapersDir := filepath.Join(componentPath, "CollectionWallpapers")
-	if _, err := os.Stat(collectionWallpapersDir); err == nil {
-		entries, err := os.ReadDir(collectionWallpapersDir)
+	if _, err := scanStat(collectionWallpapersDir); err == nil {
+		entries, err := scanReadDir(collectionWallpapersDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -413,7 +314,7 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 				)
 
 				// Determine collection name and system path
-				collectionName := strings.TrimSuffix(fileName, ".png")
+				collectionName := strings.TrimSuffix(originalFileName(wallpaperManifest.ComponentInfo.FileNames, filePath), ".png")
 				systemPath := filepath.Join(systemPaths.Root, "Collections", collectionName, ".media", "bg.png")
 
 				metadata := map[string]string{
@@ -436,6 +337,12 @@ func UpdateWallpaperManifest(componentPath string, systemPaths *system.SystemPat
 		}
 	}
 
+	// Wallpapers for Tools subfolders and custom categories
+	folderMappings := scanFolderWallpapers(componentPath, "", nil, systemPaths, logger)
+	wallpaperManifest.Content.FolderWallpapers = folderWallpaperNames(folderMappings)
+	wallpaperManifest.PathMappings = append(wallpaperManifest.PathMappings, folderMappings...)
+	wallpaperManifest.Content.Count += len(folderMappings)
+
 	// Write updated manifest
 	return WriteComponentManifest(componentPath, wallpaperManifest)
 }
@@ -478,8 +385,8 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 
 	// Check for icons in SystemIcons directory
 	systemIconsDir := filepath.Join(componentPath, "SystemIcons")
-	if _, err := os.Stat(systemIconsDir); err == nil {
-		entries, err := os.ReadDir(systemIconsDir)
+	if _, err := scanStat(systemIconsDir); err == nil {
+		entries, err := scanReadDir(systemIconsDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -504,30 +411,12 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 				var systemPath string
 				var metadata map[string]string
 
-				// Special case handling for predefined names
-				switch fileName {
-				case "Recently Played.png":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "Recently Played.png")
-					metadata = map[string]string{
-						"SystemName": "Recently Played",
-						"IconType":   "System",
-					}
-
-				case "Tools.png":
-					// Get parent directory of Tools since Tools path includes tg5040
-					toolsParentDir := filepath.Dir(systemPaths.Tools)
-					systemPath = filepath.Join(toolsParentDir, ".media", "tg5040.png")
-					metadata = map[string]string{
-						"SystemName": "Tools",
-						"IconType":   "System",
-					}
-
-				case "Collections.png":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "Collections.png")
-					metadata = map[string]string{
-						"SystemName": "Collections",
-						"IconType":   "System",
-					}
+				// Special destinations such as Root and Tools come from the registry
+				special, isSpecial := findSpecialDestination(SpecialIcons(), fileName)
+				switch {
+				case isSpecial:
+					systemPath = special.SystemPath(systemPaths)
+					metadata = special.Metadata("IconType")
 
 				default:
 					// Check for system tag in filename
@@ -539,22 +428,20 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 
 						// First, try to find the exact ROM directory by tag
 						// This ensures that we use the actual directory name
-						var exactSystemName string
+						var exactSystemName, exactIconPath string
 						var exactMatch bool
 
-						for _, system := range systemPaths.Systems {
-							if system.Tag == systemTag {
-								// Use actual ROM directory name instead of icon file name
-								exactSystemName = system.Name
-								exactMatch = true
-								break
-							}
+						if system, ok := systemPaths.SystemForTag(systemTag); ok {
+							// Use actual ROM directory name instead of icon file name
+							exactSystemName = system.Name
+							exactIconPath = system.IconPath
+							exactMatch = true
 						}
 
 						if exactMatch {
-							// Use exact ROM directory name for the icon
-							// This ensures the icon will be displayed correctly
-							systemPath = filepath.Join(systemPaths.Roms, ".media", exactSystemName+".png")
+							// Use exact ROM directory name for the icon, in the Roms folder of the
+							// storage root the system is on
+							systemPath = exactIconPath
 							metadata = map[string]string{
 								"SystemName":     exactSystemName,
 								"SystemTag":      systemTag,
@@ -595,8 +482,8 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 
 	// Check for icons in ToolIcons directory
 	toolIconsDir := filepath.Join(componentPath, "ToolIcons")
-	if _, err := os.Stat(toolIconsDir); err == nil {
-		entries, err := os.ReadDir(toolIconsDir)
+	if _, err := scanStat(toolIconsDir); err == nil {
+		entries, err := scanReadDir(toolIconsDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -618,7 +505,7 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 				)
 
 				// Determine tool name and system path
-				toolName := strings.TrimSuffix(fileName, ".png")
+				toolName := strings.TrimSuffix(originalFileName(iconManifest.ComponentInfo.FileNames, filePath), ".png")
 				systemPath := filepath.Join(systemPaths.Tools, toolName, ".media", toolName+".png")
 
 				metadata := map[string]string{
@@ -643,8 +530,8 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 
 	// Check for icons in CollectionIcons directory
 	collectionIconsDir := filepath.Join(componentPath, "CollectionIcons")
-	if _, err := os.Stat(collectionIconsDir); err == nil {
-		entries, err := os.ReadDir(collectionIconsDir)
+	if _, err := scanStat(collectionIconsDir); err == nil {
+		entries, err := scanReadDir(collectionIconsDir)
 		if err == nil {
 			for _, entry := range entries {
 				if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -666,7 +553,7 @@ func UpdateIconManifest(componentPath string, systemPaths *system.SystemPaths, l
 				)
 
 				// Determine collection name and system path
-				collectionName := strings.TrimSuffix(fileName, ".png")
+				collectionName := strings.TrimSuffix(originalFileName(iconManifest.ComponentInfo.FileNames, filePath), ".png")
 				systemPath := filepath.Join(systemPaths.Root, "Collections", collectionName, ".media", collectionName+".png")
 
 				metadata := map[string]string{
@@ -718,14 +605,20 @@ func UpdateOverlayManifest(componentPath string, systemPaths *system.SystemPaths
 	// Always update component name to match the directory name
 	overlayManifest.ComponentInfo.Name = componentName
 
+	// Keep variant details the author added to existing mappings
+	previousMetadata := make(map[string]map[string]string)
+	for _, mapping := range overlayManifest.PathMappings {
+		previousMetadata[mapping.ThemePath] = mapping.Metadata
+	}
+
 	// Clear existing content data (but preserve component_info)
 	overlayManifest.Content.Systems = []string{}
 	overlayManifest.PathMappings = []PathMapping{}
 
 	// Check for overlays in Systems directory
 	systemsDir := filepath.Join(componentPath, "Systems")
-	if _, err := os.Stat(systemsDir); err == nil {
-		entries, err := os.ReadDir(systemsDir)
+	if _, err := scanStat(systemsDir); err == nil {
+		entries, err := scanReadDir(systemsDir)
 		if err == nil {
 			for _, entry := range entries {
 				if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -736,7 +629,7 @@ func UpdateOverlayManifest(componentPath string, systemPaths *system.SystemPaths
 				systemOverlaysPath := filepath.Join(systemsDir, systemTag)
 
 				// List overlay files for this system
-				overlayFiles, err := os.ReadDir(systemOverlaysPath)
+				overlayFiles, err := scanReadDir(systemOverlaysPath)
 				if err != nil {
 					logger.DebugFn("Error reading system overlays directory %s: %v", systemTag, err)
 					continue
@@ -766,10 +659,7 @@ func UpdateOverlayManifest(componentPath string, systemPaths *system.SystemPaths
 						PathMapping{
 							ThemePath:  filePath,
 							SystemPath: systemPath,
-							Metadata: map[string]string{
-								"SystemTag":   systemTag,
-								"OverlayName": file.Name(),
-							},
+							Metadata:   overlayMetadata(systemTag, file.Name(), previousMetadata[filePath]),
 						},
 					)
 
@@ -782,7 +672,7 @@ func UpdateOverlayManifest(componentPath string, systemPaths *system.SystemPaths
 					// Check if system is already in the list
 					var systemExists bool
 					for _, sys := range overlayManifest.Content.Systems {
-						if sys == systemTag {
+						if system.SameName(sys, systemTag) {
 							systemExists = true
 							break
 						}
@@ -848,7 +738,7 @@ func UpdateFontManifest(componentPath string, logger *Logger) error {
 
 	for _, fontFile := range fontFiles {
 		fontPath := filepath.Join(componentPath, fontFile)
-		if _, err := os.Stat(fontPath); err == nil {
+		if _, err := scanStat(fontPath); err == nil {
 			// Font file exists
 			fontName := strings.TrimSuffix(fontFile, ".ttf")
 
@@ -869,6 +759,9 @@ func UpdateFontManifest(componentPath string, logger *Logger) error {
 		}
 	}
 
+	// Keep the author's fallback order, adding fonts dropped into Fallbacks since
+	fontManifest.Fallbacks = scanFontFallbacks(componentPath, fontManifest.Fallbacks)
+
 	// Write updated manifest
 	return WriteComponentManifest(componentPath, fontManifest)
 }
@@ -982,6 +875,22 @@ func UpdateLEDManifest(componentPath string, logger *Logger) error {
 	initLEDSetting(&ledManifest.LEDSettings.TopBar)
 	initLEDSetting(&ledManifest.LEDSettings.LRTriggers)
 
+	// Battery bands are matched from the lowest charge up
+	sortLEDBatteryBands(ledManifest.BatteryBands)
+	for i := range ledManifest.BatteryBands {
+		band := &ledManifest.BatteryBands[i].LEDSettings
+		initLEDSetting(&band.F1Key)
+		initLEDSetting(&band.F2Key)
+		initLEDSetting(&band.TopBar)
+		initLEDSetting(&band.LRTriggers)
+	}
+	if ledManifest.Charging != nil {
+		initLEDSetting(&ledManifest.Charging.F1Key)
+		initLEDSetting(&ledManifest.Charging.F2Key)
+		initLEDSetting(&ledManifest.Charging.TopBar)
+		initLEDSetting(&ledManifest.Charging.LRTriggers)
+	}
+
 	// Write updated manifest
 	return WriteComponentManifest(componentPath, ledManifest)
 }
@@ -0,0 +1,269 @@
+// src/internal/themes/component_registry.go
+// Registry of component package types, so a new type is added in one place
+
+package themes
+
+import (
+	"context"
+	"fmt"
+
+	"nextui-themes/internal/system"
+)
+
+// ComponentKind is one type of component package. Registering a kind is all it takes for
+// imports, exports, manifest updates and the Components folder to handle its packages.
+type ComponentKind interface {
+	Type() string      // Type name stored in component_info, e.g. "wallpaper"
+	Extension() string // Package folder extension, e.g. ".bg"
+	Directory() string // Folder under Components/, e.g. "Wallpapers"
+
+	// NewManifest returns an empty manifest of this kind with info filled in
+	NewManifest(info ComponentInfo) interface{}
+
+	// Scan brings a package's manifest up to date with the files in it
+	Scan(componentPath string, systemPaths *system.SystemPaths, logger *Logger) error
+
+	// Apply applies an installed package to the device, stopping between files once ctx is done
+	Apply(ctx context.Context, componentPath string) error
+
+	// Export packages the device's current setup of this kind, or returns an error for kinds
+	// that can't be exported without further choices
+	Export(name string) error
+
+	// Cleanup removes the files a previously applied package of this kind left on the device
+	Cleanup(systemPaths *system.SystemPaths, logger *Logger) error
+}
+
+// componentKind is a ComponentKind made of functions. Apply, Export and Cleanup are optional.
+type componentKind struct {
+	typ, ext, dir string
+	newManifest   func(info ComponentInfo) interface{}
+	scan          func(componentPath string, systemPaths *system.SystemPaths, logger *Logger) error
+	apply         func(ctx context.Context, componentPath string) error
+	export        func(name string) error
+	cleanup       func(systemPaths *system.SystemPaths, logger *Logger) error
+}
+
+func (k *componentKind) Type() string      { return k.typ }
+func (k *componentKind) Extension() string { return k.ext }
+func (k *componentKind) Directory() string { return k.dir }
+
+func (k *componentKind) NewManifest(info ComponentInfo) interface{} {
+	return k.newManifest(info)
+}
+
+func (k *componentKind) Scan(componentPath string, systemPaths *system.SystemPaths, logger *Logger) error {
+	return k.scan(componentPath, systemPaths, logger)
+}
+
+func (k *componentKind) Apply(ctx context.Context, componentPath string) error {
+	if k.apply == nil {
+		return fmt.Errorf("%s packages can't be applied", k.typ)
+	}
+	return k.apply(ctx, componentPath)
+}
+
+func (k *componentKind) Export(name string) error {
+	if k.export == nil {
+		return fmt.Errorf("%s packages can't be exported this way", k.typ)
+	}
+	return k.export(name)
+}
+
+func (k *componentKind) Cleanup(systemPaths *system.SystemPaths, logger *Logger) error {
+	if k.cleanup == nil {
+		return nil
+	}
+	return k.cleanup(systemPaths, logger)
+}
+
+// componentKinds holds the registered kinds in registration order
+var componentKinds []ComponentKind
+
+// ComponentExtension maps component types to their file extensions
+var ComponentExtension = make(map[string]string)
+
+// ComponentDirectory maps component types to their folder under Components/
+var ComponentDirectory = make(map[string]string)
+
+// RegisterComponentKind adds a component type. It panics when the type or its extension is
+// already taken, since that is a programming error.
+func RegisterComponentKind(kind ComponentKind) {
+	if _, ok := ComponentExtension[kind.Type()]; ok {
+		panic(fmt.Sprintf("component type %s registered twice", kind.Type()))
+	}
+	if _, ok := ComponentKindForExtension(kind.Extension()); ok {
+		panic(fmt.Sprintf("component extension %s registered twice", kind.Extension()))
+	}
+
+	componentKinds = append(componentKinds, kind)
+	ComponentExtension[kind.Type()] = kind.Extension()
+	ComponentDirectory[kind.Type()] = kind.Directory()
+}
+
+// ComponentKinds returns every registered kind in registration order
+func ComponentKinds() []ComponentKind {
+	return append([]ComponentKind(nil), componentKinds...)
+}
+
+// LookupComponentKind returns the kind of a component type
+func LookupComponentKind(componentType string) (ComponentKind, bool) {
+	for _, kind := range componentKinds {
+		if kind.Type() == componentType {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// ComponentKindForExtension returns the kind whose packages use an extension such as ".bg"
+func ComponentKindForExtension(ext string) (ComponentKind, bool) {
+	for _, kind := range componentKinds {
+		if kind.Extension() == ext {
+			return kind, true
+		}
+	}
+	return nil, false
+}
+
+// ComponentTypeForDirectory returns the component type stored in a folder under Components/,
+// or "" when no kind uses it
+func ComponentTypeForDirectory(dir string) string {
+	for _, kind := range componentKinds {
+		if kind.Directory() == dir {
+			return kind.Type()
+		}
+	}
+	return ""
+}
+
+// Register the built-in component types, in the order the Components menu lists them
+func init() {
+	RegisterComponentKind(&componentKind{
+		typ: ComponentWallpaper, ext: ".bg", dir: "Wallpapers",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &WallpaperManifest{ComponentInfo: info}
+			manifest.Content.SystemWallpapers = []string{}
+			manifest.Content.ListWallpapers = []string{}
+			manifest.Content.CollectionWallpapers = []string{}
+			manifest.PathMappings = []PathMapping{}
+			return manifest
+		},
+		scan:    UpdateWallpaperManifest,
+		apply:   ImportWallpapers,
+		export:  ExportWallpapers,
+		cleanup: cleanupExistingWallpapers,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentIcon, ext: ".icon", dir: "Icons",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &IconManifest{ComponentInfo: info}
+			manifest.Content.SystemIcons = []string{}
+			manifest.Content.ToolIcons = []string{}
+			manifest.Content.CollectionIcons = []string{}
+			manifest.PathMappings = []PathMapping{}
+			return manifest
+		},
+		scan:    UpdateIconManifest,
+		apply:   ImportIcons,
+		export:  ExportIcons,
+		cleanup: cleanupExistingIcons,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentAccent, ext: ".acc", dir: "Accents",
+		newManifest: func(info ComponentInfo) interface{} {
+			return &AccentManifest{ComponentInfo: info}
+		},
+		scan: func(componentPath string, _ *system.SystemPaths, logger *Logger) error {
+			return UpdateAccentManifest(componentPath, logger)
+		},
+		apply: func(_ context.Context, componentPath string) error {
+			return ImportAccents(componentPath)
+		},
+		export: ExportAccents,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentOverlay, ext: ".over", dir: "Overlays",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &OverlayManifest{ComponentInfo: info}
+			manifest.Content.Systems = []string{}
+			manifest.PathMappings = []PathMapping{}
+			return manifest
+		},
+		scan:    UpdateOverlayManifest,
+		apply:   ImportOverlays,
+		export:  ExportOverlays,
+		cleanup: cleanupExistingOverlays,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentLED, ext: ".led", dir: "LEDs",
+		newManifest: func(info ComponentInfo) interface{} {
+			return &LEDManifest{ComponentInfo: info}
+		},
+		scan: func(componentPath string, _ *system.SystemPaths, logger *Logger) error {
+			return UpdateLEDManifest(componentPath, logger)
+		},
+		apply: func(_ context.Context, componentPath string) error {
+			return ImportLEDs(componentPath)
+		},
+		export: ExportLEDs,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentFont, ext: ".font", dir: "Fonts",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &FontManifest{ComponentInfo: info}
+			manifest.PathMappings = make(map[string]PathMapping)
+			return manifest
+		},
+		scan: func(componentPath string, _ *system.SystemPaths, logger *Logger) error {
+			return UpdateFontManifest(componentPath, logger)
+		},
+		apply:  ImportFonts,
+		export: ExportFonts,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentGameArt, ext: ".art", dir: "GameArt",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &GameArtManifest{ComponentInfo: info}
+			manifest.PathMappings = make(map[string]PathMapping)
+			return manifest
+		},
+		scan: func(componentPath string, _ *system.SystemPaths, logger *Logger) error {
+			return UpdateGameArtManifest(componentPath, logger)
+		},
+		apply:  ImportGameArt,
+		export: ExportGameArt,
+	})
+
+	RegisterComponentKind(&componentKind{
+		typ: ComponentShader, ext: ".shd", dir: "Shaders",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &ShaderManifest{ComponentInfo: info}
+			manifest.Systems = make(map[string]map[string]string)
+			return manifest
+		},
+		scan: func(componentPath string, _ *system.SystemPaths, logger *Logger) error {
+			return UpdateShaderManifest(componentPath, logger)
+		},
+		apply:  ImportShaders,
+		export: ExportShaders,
+	})
+
+	// Collections are exported per collection, from the collection picker
+	RegisterComponentKind(&componentKind{
+		typ: ComponentCollection, ext: ".col", dir: "Collections",
+		newManifest: func(info ComponentInfo) interface{} {
+			manifest := &CollectionManifest{ComponentInfo: info}
+			manifest.PathMappings = make(map[string]PathMapping)
+			return manifest
+		},
+		scan:  UpdateCollectionManifest,
+		apply: ImportCollection,
+	})
+}
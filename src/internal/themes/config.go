@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
 )
 
 // ConfigData represents the configuration file structure
@@ -18,6 +19,95 @@ type ConfigData struct {
 	Branch   string `json:"branch"`
 	Version  string `json:"version"`
 	DeviceID string `json:"device_id,omitempty"`
+
+	// Files that theme and component applies must never overwrite or remove
+	PinnedPaths []string `json:"pinned_paths,omitempty"`
+
+	// Tags of systems whose media is never themed, e.g. when managed by a scraper
+	ExcludedSystems []string `json:"excluded_systems,omitempty"`
+
+	// Other storage roots with their own Roms folder, such as a second SD card, themed with the SD card
+	StorageRoots []string `json:"storage_roots,omitempty"`
+
+	// List wallpaper dimming: 0 follows the theme, -1 is off, otherwise an opacity percentage
+	ListScrimOpacity int `json:"list_scrim_opacity,omitempty"`
+
+	// Shape every applied icon is cut to: "circle", "squircle" or "rounded"; empty keeps the pack's shapes
+	IconMask string `json:"icon_mask,omitempty"`
+
+	// Shadow, outline and dimming added to a component's images when applied, by package name
+	ImageEffects map[string]ImageEffects `json:"image_effects,omitempty"`
+
+	// Strict mode fails imports and exports on any logged warning
+	StrictMode bool `json:"strict_mode,omitempty"`
+
+	// Folder exports are written to, e.g. on a USB drive; empty uses Exports inside the pak
+	ExportDir string `json:"export_dir,omitempty"`
+
+	// Theme exports larger than this many MB are also split into zip volumes, 0 never splits
+	VolumeSizeMB int `json:"volume_size_mb,omitempty"`
+
+	// Keep exported and downloaded themes as single .theme.zip archives instead of folders
+	ZipThemes bool `json:"zip_themes,omitempty"`
+
+	// Also deconstruct every downloaded theme into component packages in the library
+	AutoDeconstruct bool `json:"auto_deconstruct,omitempty"`
+
+	// Apply images as hard links to the package instead of copies, where supported
+	HardLinkApply bool `json:"hard_link_apply,omitempty"`
+
+	// Read back every copied file and compare hashes with the package
+	VerifyWrites bool `json:"verify_writes,omitempty"`
+
+	// Minimum battery for long operations: 0 uses the default, -1 is off, otherwise a percentage
+	BatteryGuardPercent int `json:"battery_guard_percent,omitempty"`
+
+	// Previous versions kept of replaced packages: 0 uses the default, -1 is off, otherwise a count
+	KeepVersions int `json:"keep_versions,omitempty"`
+
+	// Files in .userdata/shared exported and restored with themes, besides accents and LEDs
+	SettingsAllowlist []string `json:"settings_allowlist,omitempty"`
+
+	// What component applies remove first: "replace" (every system, the default) or "merge"
+	CleanupPolicy string `json:"cleanup_policy,omitempty"`
+
+	// What applies do with animated wallpapers: "" applies a still frame, "reject" leaves them out
+	AnimatedPolicy string `json:"animated_policy,omitempty"`
+
+	// Overlay variant and opacity picked per system tag
+	OverlayVariants map[string]OverlayVariantChoice `json:"overlay_variants,omitempty"`
+
+	// Community catalog intake for Submit to Catalog; without an endpoint a pre-filled link is shown
+	SubmitEndpoint string `json:"submit_endpoint,omitempty"`
+	SubmitToken    string `json:"submit_token,omitempty"`
+
+	// Draw a small "by author" watermark on exported previews, on top of the PNG credit metadata
+	PreviewWatermark bool `json:"preview_watermark,omitempty"`
+
+	// Large-text, high-contrast mode for Theme Manager's own screens
+	AccessibleUI bool `json:"accessible_ui,omitempty"`
+
+	// Log format: "text" (the default), "json" or "both", see logging.LogFormats
+	LogFormat string `json:"log_format,omitempty"`
+
+	// Character ranges exported fonts are subset to, see FontSubsetRanges; empty exports fonts whole
+	FontSubset []string `json:"font_subset,omitempty"`
+
+	// Kiosk mode only allows browsing and previews, see CheckKioskMode
+	KioskMode bool `json:"kiosk_mode,omitempty"`
+
+	// Hash of the button sequence that leaves kiosk mode; empty when it isn't PIN protected
+	KioskPIN string `json:"kiosk_pin,omitempty"`
+
+	// Hash of the button sequence asked for before rollbacks, restores and discarding edits;
+	// empty when they aren't PIN protected
+	ProtectPIN string `json:"protect_pin,omitempty"`
+
+	// Themes applied automatically between two dates each year, then reverted
+	SeasonalThemes []SeasonalSchedule `json:"seasonal_themes,omitempty"`
+
+	// How long the last theme or component apply took, shown in settings
+	LastApplyMillis int64 `json:"last_apply_millis,omitempty"`
 }
 
 // Default configuration values
@@ -76,6 +166,8 @@ func LoadConfig() (*ConfigData, error) {
 		SetRepoBranch(config.Branch)
 	}
 
+	system.SetStorageRoots(config.StorageRoots)
+
 	return &config, nil
 }
 
@@ -101,6 +193,8 @@ func SaveConfig(config *ConfigData) error {
 		return fmt.Errorf("error writing config file: %w", err)
 	}
 
+	system.SetStorageRoots(config.StorageRoots)
+
 	logging.LogDebug("Saved configuration to %s", configPath)
 	return nil
 }
@@ -6,6 +6,7 @@ package themes
 import (
 	"fmt"
 	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
 	"nextui-themes/internal/ui"
 	"os"
 	"path/filepath"
@@ -13,12 +14,14 @@ import (
 )
 
 // DeconstructTheme breaks down a theme package into individual component packages
-func DeconstructTheme(themeName string) error {
+func DeconstructTheme(themeName string) (err error) {
+	defer func() { recordOperation("Deconstructed theme", themeName, err) }()
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
 
 	logger.DebugFn("Starting theme deconstruction for: %s", themeName)
+	defer logging.LogMemoryUsage("theme deconstruction")
 
 	// Get current directory
 	cwd, err := os.Getwd()
@@ -29,15 +32,36 @@ func DeconstructTheme(themeName string) error {
 	// Full path to theme - in the Themes directory
 	themePath := filepath.Join(cwd, "Themes", themeName)
 
+	manifest, componentsDeconstructed, err := deconstructThemeFolder(themePath, themeName, logger)
+	if err != nil {
+		return err
+	}
+
+	// Show success message to user
+	ui.ShowMessage(fmt.Sprintf("Theme '%s' deconstructed into %d component packages!",
+		manifest.ThemeInfo.Name, componentsDeconstructed), "3")
+
+	return nil
+}
+
+// deconstructThemeFolder writes a component package to the exports folder for each kind
+// of content in the theme at themePath, returning the theme's manifest and how many
+// packages were written
+func deconstructThemeFolder(themePath string, themeName string, logger *Logger) (*ThemeManifest, int, error) {
 	// Validate theme
 	manifest, err := ValidateTheme(themePath, logger)
 	if err != nil {
 		logger.DebugFn("Theme validation failed: %v", err)
-		return fmt.Errorf("theme validation failed: %w", err)
+		return nil, 0, fmt.Errorf("theme validation failed: %w", err)
 	}
 
-	// Track how many components were successfully deconstructed
+	// Track how many components were successfully deconstructed, each remembering the
+	// theme it came from
 	componentsDeconstructed := 0
+	deconstructed := func() {
+		componentsDeconstructed++
+		recordProvenance(LastExportPath(), Provenance{Origin: ProvenanceDeconstructed, Theme: themeName}, logger)
+	}
 
 	// Generate export name base from theme name (remove .theme extension)
 	exportBaseName := themeName
@@ -53,7 +77,7 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructWallpapers(themePath, manifest, wallpaperName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct wallpapers: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
 		}
 	}
 
@@ -68,7 +92,7 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructIcons(themePath, manifest, iconName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct icons: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
 		}
 	}
 
@@ -80,7 +104,7 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructOverlays(themePath, manifest, overlayName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct overlays: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
 		}
 	}
 
@@ -92,7 +116,19 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructFonts(themePath, manifest, fontName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct fonts: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
+		}
+	}
+
+	// Deconstruct game art if present
+	if manifest.Content.GameArt.Present && len(manifest.PathMappings.GameArt) > 0 {
+		logger.DebugFn("Deconstructing game art")
+		artName := exportBaseName + ComponentExtension[ComponentGameArt]
+
+		if err := DeconstructGameArt(themePath, manifest, artName, logger); err != nil {
+			logger.DebugFn("Warning: Failed to deconstruct game art: %v", err)
+		} else {
+			deconstructed()
 		}
 	}
 
@@ -104,7 +140,7 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructAccents(themePath, manifest, accentName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct accent settings: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
 		}
 	}
 
@@ -116,39 +152,31 @@ func DeconstructTheme(themeName string) error {
 		if err := DeconstructLEDs(themePath, manifest, ledName, logger); err != nil {
 			logger.DebugFn("Warning: Failed to deconstruct LED settings: %v", err)
 		} else {
-			componentsDeconstructed++
+			deconstructed()
 		}
 	}
 
 	if componentsDeconstructed == 0 {
-		return fmt.Errorf("no components were successfully deconstructed from theme: %s", themeName)
+		return nil, 0, fmt.Errorf("no components were successfully deconstructed from theme: %s", themeName)
 	}
 
 	logger.DebugFn("Theme deconstruction completed successfully. %d components extracted.", componentsDeconstructed)
-
-	// Show success message to user
-	ui.ShowMessage(fmt.Sprintf("Theme '%s' deconstructed into %d component packages!",
-		manifest.ThemeInfo.Name, componentsDeconstructed), "3")
-
-	return nil
+	return manifest, componentsDeconstructed, nil
 }
 
 func DeconstructWallpapers(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting wallpapers from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .bg extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentWallpaper]) {
 		componentName = componentName + ComponentExtension[ComponentWallpaper]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create directories for the wallpaper component, including the new ListWallpapers directory
 	dirPaths := []string{
@@ -171,6 +199,8 @@ func DeconstructWallpapers(themePath string, manifest *ThemeManifest, componentN
 	}
 
 	wallpaperManifest := manifestObj.(*WallpaperManifest)
+	wallpaperManifest.ComponentInfo.License = manifest.ThemeInfo.License
+	wallpaperManifest.Content.ListScrimOpacity = manifest.Content.Settings.ListScrimOpacity
 
 	// Process each wallpaper mapping from the theme manifest
 	// Copy the files but don't populate the component manifest with mappings
@@ -230,6 +260,15 @@ func DeconstructWallpapers(themePath string, manifest *ThemeManifest, componentN
 		logger.DebugFn("Copied wallpaper: %s", relativePath)
 	}
 
+	// The sleep wallpaper sits next to the other folders in both packages
+	if manifest.Content.Wallpapers.Sleep != "" {
+		if err := CopyFile(filepath.Join(themePath, manifest.Content.Wallpapers.Sleep), filepath.Join(exportPath, sleepWallpaperFile)); err != nil {
+			logger.DebugFn("Warning: Could not copy sleep wallpaper: %v", err)
+		} else {
+			wallpaperManifest.Content.SleepWallpaper = sleepWallpaperFile
+		}
+	}
+
 	// Create a preview image - try to use a system wallpaper as preview
 	previewPath := filepath.Join(exportPath, "preview.png")
 
@@ -269,6 +308,9 @@ func DeconstructWallpapers(themePath string, manifest *ThemeManifest, componentN
 		}
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, wallpaperManifest.ComponentInfo.Name, wallpaperManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, wallpaperManifest); err != nil {
 		return fmt.Errorf("error writing wallpaper manifest: %w", err)
@@ -282,19 +324,16 @@ func DeconstructWallpapers(themePath string, manifest *ThemeManifest, componentN
 func DeconstructIcons(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting icons from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .icon extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentIcon]) {
 		componentName = componentName + ComponentExtension[ComponentIcon]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create directories for the icon component
 	dirPaths := []string{
@@ -317,6 +356,7 @@ func DeconstructIcons(themePath string, manifest *ThemeManifest, componentName s
 	}
 
 	iconManifest := manifestObj.(*IconManifest)
+	iconManifest.ComponentInfo.License = manifest.ThemeInfo.License
 
 	// Process each icon mapping from the theme manifest
 	// Copy the files but don't populate the component manifest with mappings
@@ -395,6 +435,9 @@ func DeconstructIcons(themePath string, manifest *ThemeManifest, componentName s
 		}
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, iconManifest.ComponentInfo.Name, iconManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, iconManifest); err != nil {
 		return fmt.Errorf("error writing icon manifest: %w", err)
@@ -408,19 +451,16 @@ func DeconstructIcons(themePath string, manifest *ThemeManifest, componentName s
 func DeconstructOverlays(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting overlays from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .over extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentOverlay]) {
 		componentName = componentName + ComponentExtension[ComponentOverlay]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -440,6 +480,7 @@ func DeconstructOverlays(themePath string, manifest *ThemeManifest, componentNam
 	}
 
 	overlayManifest := manifestObj.(*OverlayManifest)
+	overlayManifest.ComponentInfo.License = manifest.ThemeInfo.License
 
 	// Copy over the systems list but as a blank list - will be populated during import
 	overlayManifest.Content.Systems = []string{}
@@ -487,7 +528,7 @@ func DeconstructOverlays(themePath string, manifest *ThemeManifest, componentNam
 			// Add system to the list if not already present
 			systemFound := false
 			for _, tag := range overlayManifest.Content.Systems {
-				if tag == systemTag {
+				if system.SameName(tag, systemTag) {
 					systemFound = true
 					break
 				}
@@ -505,6 +546,9 @@ func DeconstructOverlays(themePath string, manifest *ThemeManifest, componentNam
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, overlayManifest.ComponentInfo.Name, overlayManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, overlayManifest); err != nil {
 		return fmt.Errorf("error writing overlay manifest: %w", err)
@@ -518,19 +562,16 @@ func DeconstructOverlays(themePath string, manifest *ThemeManifest, componentNam
 func DeconstructFonts(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting fonts from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .font extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentFont]) {
 		componentName = componentName + ComponentExtension[ComponentFont]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -544,6 +585,7 @@ func DeconstructFonts(themePath string, manifest *ThemeManifest, componentName s
 	}
 
 	fontManifest := manifestObj.(*FontManifest)
+	fontManifest.ComponentInfo.License = manifest.ThemeInfo.License
 
 	// Process fonts from the theme
 	fontPaths := []string{
@@ -587,6 +629,9 @@ func DeconstructFonts(themePath string, manifest *ThemeManifest, componentName s
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, fontManifest.ComponentInfo.Name, fontManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, fontManifest); err != nil {
 		return fmt.Errorf("error writing font manifest: %w", err)
@@ -600,19 +645,16 @@ func DeconstructFonts(themePath string, manifest *ThemeManifest, componentName s
 func DeconstructAccents(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting accent settings from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .acc extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentAccent]) {
 		componentName = componentName + ComponentExtension[ComponentAccent]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -626,6 +668,7 @@ func DeconstructAccents(themePath string, manifest *ThemeManifest, componentName
 	}
 
 	accentManifest := manifestObj.(*AccentManifest)
+	accentManifest.ComponentInfo.License = manifest.ThemeInfo.License
 
 	// Copy accent colors from theme manifest
 	accentManifest.AccentColors.Color1 = manifest.AccentColors.Color1
@@ -641,6 +684,9 @@ func DeconstructAccents(themePath string, manifest *ThemeManifest, componentName
 		logger.DebugFn("Warning: Could not create default preview: %v", err)
 	}
 
+	// Stamp author credit into the preview
+	stampPreview(exportPath, accentManifest.ComponentInfo.Name, accentManifest.ComponentInfo.Author, logger)
+
 	// Write the component manifest
 	if err := WriteComponentManifest(exportPath, accentManifest); err != nil {
 		return fmt.Errorf("error writing accent manifest: %w", err)
@@ -654,19 +700,16 @@ func DeconstructAccents(themePath string, manifest *ThemeManifest, componentName
 func DeconstructLEDs(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
 	logger.DebugFn("Extracting LED settings from theme to component: %s", componentName)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("error getting current directory: %w", err)
-	}
-
 	// Create export directory path with .led extension
 	if !strings.HasSuffix(componentName, ComponentExtension[ComponentLED]) {
 		componentName = componentName + ComponentExtension[ComponentLED]
 	}
 
 	// Path where component will be created (in Exports directory)
-	exportPath := filepath.Join(cwd, "Exports", componentName)
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
 
 	// Create the root directory
 	if err := os.MkdirAll(exportPath, 0755); err != nil {
@@ -680,6 +723,7 @@ func DeconstructLEDs(themePath string, manifest *ThemeManifest, componentName st
 	}
 
 	ledManifest := manifestObj.(*LEDManifest)
+	ledManifest.ComponentInfo.License = manifest.ThemeInfo.License
 
 	// Copy LED settings from theme manifest
 	ledManifest.LEDSettings.F1Key = manifest.LEDSettings.F1Key
@@ -0,0 +1,269 @@
+// src/internal/themes/diff_export.go
+// Diff themes: exports only what the device changed on top of an installed base theme
+
+package themes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+	"nextui-themes/internal/ui"
+)
+
+// DiffThemeName returns the name of the diff theme exported against a base, e.g.
+// "Retro-personal.theme" for Retro.theme
+func DiffThemeName(baseName string) string {
+	return strings.TrimSuffix(baseName, ".theme") + "-personal.theme"
+}
+
+// ExportDiffTheme exports what differs on the device from an installed base theme, such as
+// changed wallpapers and extra icons, as a variant theme that extends the base. Applying it
+// after reinstalling the base brings the personal changes back. Files the base has but the
+// device no longer does can't be expressed by a variant and are left out.
+func ExportDiffTheme(baseName string) (err error) {
+	name := DiffThemeName(baseName)
+	defer func() { recordOperation("Exported diff theme", name, err) }()
+	defer logging.BeginOperation("export diff theme", baseName)()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting diff theme export against %s", baseName)
+
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	basePath := filepath.Join(cwd, "Themes", baseName)
+	if _, err := os.Stat(basePath); err != nil {
+		return fmt.Errorf("base theme %s is not installed", baseName)
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	// Compare against the base as it applies, including what it inherits itself
+	baseManifest, err := ValidateTheme(basePath, logger)
+	if err != nil {
+		return fmt.Errorf("error reading base theme %s: %w", baseName, err)
+	}
+	if err := UpdateManifestFromThemeContent(basePath, baseManifest, systemPaths, logger); err != nil {
+		logger.DebugFn("Warning: Error updating manifest of base theme %s: %v", baseName, err)
+	}
+	if err := resolveExtends(basePath, baseManifest, systemPaths, logger); err != nil {
+		return err
+	}
+
+	// Export in full under a temporary name, then strip what the base already has
+	themePath, err := CreateThemeExportDirectory()
+	if err != nil {
+		return fmt.Errorf("error creating theme directory: %w", err)
+	}
+
+	manifest := CreateMinimalThemeManifest(name, baseManifest.ThemeInfo.Author)
+	manifest.ThemeInfo.License = baseManifest.ThemeInfo.License
+	if err := exportThemeContent(context.Background(), themePath, manifest, systemPaths, logger); err != nil {
+		return err
+	}
+
+	kept := dropBaseContent(themePath, basePath, manifest, baseManifest, logger)
+	if kept == 0 {
+		os.RemoveAll(themePath)
+		return fmt.Errorf("nothing on the device differs from %s", baseName)
+	}
+
+	// A new diff against the same base is the next version of the previous one
+	releasePath := filepath.Join(filepath.Dir(themePath), name)
+	_, statErr := os.Stat(releasePath)
+	hasPrevious := statErr == nil
+	if hasPrevious {
+		if err := addChangelogEntry(releasePath, themePath, manifest, logger); err != nil {
+			logger.DebugFn("Warning: Could not compare with previous diff theme: %v", err)
+		}
+	}
+
+	manifest.Extends = baseName
+	if err := WriteManifest(themePath, manifest, logger); err != nil {
+		return fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	// The previous diff is archived like a previous theme export rather than deleted
+	if hasPrevious {
+		if err := archiveExport(releasePath, logger); err != nil {
+			return fmt.Errorf("error archiving previous diff theme: %w", err)
+		}
+	}
+	if err := os.Rename(themePath, releasePath); err != nil {
+		return fmt.Errorf("error naming export: %w", err)
+	}
+	lastExportPath = releasePath
+
+	logger.DebugFn("Diff theme export completed: %s (%d changes)", releasePath, kept)
+
+	ui.ShowMessage(fmt.Sprintf("Exported %d changes from %s as '%s'", kept, baseName, name), "3")
+
+	return nil
+}
+
+// dropBaseContent removes every exported file and setting that is identical in the base
+// theme, updates the manifest's content section to match, and returns how many are left
+func dropBaseContent(themePath, basePath string, manifest, base *ThemeManifest, logger *Logger) int {
+	// Base files by where they go on the device
+	baseFiles := make(map[string]string)
+	addBase := func(mappings ...PathMapping) {
+		for _, mapping := range mappings {
+			baseFiles[filepath.Clean(mapping.SystemPath)] = filepath.Join(basePath, filepath.FromSlash(mapping.ThemePath))
+		}
+	}
+	addBase(base.PathMappings.Wallpapers...)
+	addBase(base.PathMappings.Icons...)
+	addBase(base.PathMappings.Overlays...)
+	for _, keyed := range []map[string]PathMapping{base.PathMappings.Fonts, base.PathMappings.GameArt, base.PathMappings.Settings} {
+		for _, mapping := range keyed {
+			addBase(mapping)
+		}
+	}
+
+	kept := 0
+
+	// inBase removes an exported file when the base writes the same bytes to the same place
+	inBase := func(mapping PathMapping) bool {
+		baseFile, ok := baseFiles[filepath.Clean(mapping.SystemPath)]
+		if !ok {
+			kept++
+			return false
+		}
+
+		exported := filepath.Join(themePath, filepath.FromSlash(mapping.ThemePath))
+		want, err := hashFile(baseFile)
+		if err != nil {
+			kept++
+			return false
+		}
+		got, err := hashFile(exported)
+		if err != nil || !bytes.Equal(got, want) {
+			kept++
+			return false
+		}
+
+		if err := os.Remove(exported); err != nil {
+			logger.DebugFn("Warning: Could not remove %s: %v", exported, err)
+		}
+		return true
+	}
+
+	filter := func(mappings []PathMapping) []PathMapping {
+		var remaining []PathMapping
+		for _, mapping := range mappings {
+			if !inBase(mapping) {
+				remaining = append(remaining, mapping)
+			}
+		}
+		return remaining
+	}
+	filterKeyed := func(mappings map[string]PathMapping) map[string]PathMapping {
+		remaining := make(map[string]PathMapping)
+		for key, mapping := range mappings {
+			if !inBase(mapping) {
+				remaining[key] = mapping
+			}
+		}
+		return remaining
+	}
+
+	manifest.PathMappings.Wallpapers = filter(manifest.PathMappings.Wallpapers)
+	manifest.Content.Wallpapers.Count = len(manifest.PathMappings.Wallpapers)
+	manifest.Content.Wallpapers.Present = manifest.Content.Wallpapers.Count > 0
+
+	if manifest.Content.Wallpapers.Sleep != "" {
+		exported := filepath.Join(themePath, filepath.FromSlash(manifest.Content.Wallpapers.Sleep))
+		got, err := hashFile(exported)
+		want, baseErr := hashFile(filepath.Join(basePath, filepath.FromSlash(base.Content.Wallpapers.Sleep)))
+		if base.Content.Wallpapers.Sleep != "" && err == nil && baseErr == nil && bytes.Equal(got, want) {
+			os.Remove(exported)
+			manifest.Content.Wallpapers.Sleep = ""
+		} else {
+			kept++
+		}
+	}
+
+	manifest.PathMappings.Icons = filter(manifest.PathMappings.Icons)
+	manifest.Content.Icons.SystemCount = 0
+	manifest.Content.Icons.ToolCount = 0
+	manifest.Content.Icons.CollectionCount = 0
+	for _, mapping := range manifest.PathMappings.Icons {
+		switch mapping.Metadata["IconType"] {
+		case "Tool":
+			manifest.Content.Icons.ToolCount++
+		case "Collection":
+			manifest.Content.Icons.CollectionCount++
+		default:
+			manifest.Content.Icons.SystemCount++
+		}
+	}
+	manifest.Content.Icons.Present = len(manifest.PathMappings.Icons) > 0
+
+	manifest.PathMappings.Overlays = filter(manifest.PathMappings.Overlays)
+	manifest.Content.Overlays.Systems = []string{}
+	seenSystems := make(map[string]bool)
+	for _, mapping := range manifest.PathMappings.Overlays {
+		if tag := mapping.Metadata["SystemTag"]; tag != "" && !seenSystems[tag] {
+			seenSystems[tag] = true
+			manifest.Content.Overlays.Systems = append(manifest.Content.Overlays.Systems, tag)
+		}
+	}
+	manifest.Content.Overlays.Present = len(manifest.PathMappings.Overlays) > 0
+
+	manifest.PathMappings.Fonts = filterKeyed(manifest.PathMappings.Fonts)
+	_, manifest.Content.Fonts.OGReplaced = manifest.PathMappings.Fonts["OG"]
+	_, manifest.Content.Fonts.NextReplaced = manifest.PathMappings.Fonts["Next"]
+	manifest.Content.Fonts.Present = len(manifest.PathMappings.Fonts) > 0
+
+	manifest.PathMappings.GameArt = filterKeyed(manifest.PathMappings.GameArt)
+	manifest.Content.GameArt.PlaceholderReplaced = false
+	manifest.Content.GameArt.FrameReplaced = false
+	for asset := range manifest.PathMappings.GameArt {
+		setGameArtFlag(asset, &manifest.Content.GameArt.PlaceholderReplaced, &manifest.Content.GameArt.FrameReplaced)
+	}
+	manifest.Content.GameArt.Present = len(manifest.PathMappings.GameArt) > 0
+
+	manifest.PathMappings.Settings = filterKeyed(manifest.PathMappings.Settings)
+	manifest.Content.Settings.SnapshotFiles = nil
+	for name := range manifest.PathMappings.Settings {
+		manifest.Content.Settings.SnapshotFiles = append(manifest.Content.Settings.SnapshotFiles, name)
+	}
+	sort.Strings(manifest.Content.Settings.SnapshotFiles)
+
+	// Settings stored in the manifest are dropped when they match the base's
+	if manifest.Content.Settings.AccentsIncluded {
+		if base.Content.Settings.AccentsIncluded && manifest.AccentColors == base.AccentColors {
+			manifest.Content.Settings.AccentsIncluded = false
+		} else {
+			kept++
+		}
+	}
+	if manifest.Content.Settings.LEDsIncluded {
+		if base.Content.Settings.LEDsIncluded && manifest.LEDSettings == base.LEDSettings {
+			manifest.Content.Settings.LEDsIncluded = false
+		} else {
+			kept++
+		}
+	}
+
+	return kept
+}
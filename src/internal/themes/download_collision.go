@@ -0,0 +1,161 @@
+// src/internal/themes/download_collision.go
+// Detects catalog downloads whose name is already taken by a local theme
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ThemeCollision describes a catalog theme whose name is already used by a local theme
+type ThemeCollision struct {
+	LocalVersion   string
+	LocalAuthor    string
+	CatalogVersion string
+	CatalogAuthor  string
+}
+
+// SameRelease reports whether the local theme is the catalog release itself,
+// so there is nothing to decide
+func (c *ThemeCollision) SameRelease() bool {
+	return c.LocalAuthor == c.CatalogAuthor && CompareVersions(c.LocalVersion, c.CatalogVersion) == 0
+}
+
+// Describe summarizes both sides of the collision for a prompt
+func (c *ThemeCollision) Describe() string {
+	relation := "same version"
+	switch CompareVersions(c.CatalogVersion, c.LocalVersion) {
+	case 1:
+		relation = "catalog is newer"
+	case -1:
+		relation = "catalog is older"
+	}
+	if c.LocalAuthor != c.CatalogAuthor {
+		relation = "different author"
+	}
+
+	return fmt.Sprintf("Local: v%s by %s\nCatalog: v%s by %s (%s)",
+		c.LocalVersion, c.LocalAuthor, c.CatalogVersion, c.CatalogAuthor, relation)
+}
+
+// CompareVersions compares dotted version strings numerically, returning -1, 0 or 1.
+// Missing parts count as zero and non-numeric parts compare as text.
+func CompareVersions(a, b string) int {
+	partsA := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	partsB := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var partA, partB string
+		if i < len(partsA) {
+			partA = partsA[i]
+		}
+		if i < len(partsB) {
+			partB = partsB[i]
+		}
+
+		numA, errA := strconv.Atoi(defaultString(partA, "0"))
+		numB, errB := strconv.Atoi(defaultString(partB, "0"))
+		if errA == nil && errB == nil {
+			if numA != numB {
+				if numA < numB {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+
+		if cmp := strings.Compare(partA, partB); cmp != 0 {
+			return cmp
+		}
+	}
+
+	return 0
+}
+
+// defaultString returns fallback when value is empty
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// FindThemeCollision checks whether downloading a catalog theme would land on a local theme.
+// It returns nil when the name is free.
+func FindThemeCollision(themeName string) (*ThemeCollision, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	localThemePath := filepath.Join(cwd, "Themes", themeName)
+	if _, err := os.Stat(localThemePath); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	collision := &ThemeCollision{}
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+	if manifest, err := ValidateTheme(localThemePath, logger); err == nil {
+		collision.LocalVersion = manifest.ThemeInfo.Version
+		collision.LocalAuthor = manifest.ThemeInfo.Author
+	} else {
+		logger.DebugFn("Warning: Local theme '%s' has no readable manifest: %v", themeName, err)
+	}
+
+	catalog, err := LoadCatalog()
+	if err != nil {
+		return nil, err
+	}
+	themeInfo, exists := catalog.Themes[themeName]
+	if !exists {
+		return nil, fmt.Errorf("theme '%s' not found in catalog", themeName)
+	}
+	collision.CatalogAuthor = themeInfo.Author
+
+	// The catalog only lists authors; the version comes from the synced manifest
+	if themeInfo.ManifestPath != "" {
+		var manifest ThemeManifest
+		data, err := os.ReadFile(filepath.Join(cwd, themeInfo.ManifestPath))
+		if err == nil {
+			err = json.Unmarshal(data, &manifest)
+		}
+		if err != nil {
+			logger.DebugFn("Warning: Could not read catalog manifest for '%s': %v", themeName, err)
+		} else {
+			collision.CatalogVersion = manifest.ThemeInfo.Version
+		}
+	}
+
+	logger.DebugFn("Theme name collision for '%s': %s", themeName, strings.ReplaceAll(collision.Describe(), "\n", ", "))
+	return collision, nil
+}
+
+// FreeThemeName returns a local name for a catalog theme that doesn't collide,
+// e.g. "Retro (2).theme" when "Retro.theme" is taken
+func FreeThemeName(themeName string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	ext := filepath.Ext(themeName)
+	base := strings.TrimSuffix(themeName, ext)
+
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s (%d)%s", base, i, ext)
+		if _, err := os.Stat(filepath.Join(cwd, "Themes", candidate)); os.IsNotExist(err) {
+			return candidate, nil
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// src/internal/themes/duplicates.go
+// Finds identical files across installed packages and the space they waste
+
+package themes
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// DuplicateGroup is a set of identical files found in installed packages
+type DuplicateGroup struct {
+	Size  int64
+	Files []string // Relative to the Theme Manager directory, e.g. Themes/Retro.theme/Wallpapers/...
+}
+
+// Wasted returns the space taken by every copy but one
+func (g DuplicateGroup) Wasted() int64 {
+	return g.Size * int64(len(g.Files)-1)
+}
+
+// PackageOverlap counts the identical files two packages share
+type PackageOverlap struct {
+	First  string
+	Second string
+	Files  int
+	Size   int64 // Space one of the two could free by dropping its copies
+}
+
+// DuplicateReport is the result of a library duplicate scan
+type DuplicateReport struct {
+	Scanned  int // Files looked at
+	Groups   []DuplicateGroup
+	Overlaps []PackageOverlap
+	Wasted   int64
+}
+
+// libraryFile is a candidate file during a duplicate scan
+type libraryFile struct {
+	path string // Absolute
+	rel  string // Relative to the Theme Manager directory
+	pkg  string // Package folder name
+	size int64
+}
+
+// FindDuplicateAssets hashes the files of every installed package and groups the identical
+// ones. Only files sharing a size with another file are hashed. progress is called before
+// each package is read.
+func FindDuplicateAssets(progress func(current, total int, name string)) (*DuplicateReport, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	packages, err := ListInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DuplicateReport{}
+	bySize := make(map[int64][]libraryFile)
+
+	for i, pkg := range packages {
+		name := filepath.Base(pkg)
+		if progress != nil {
+			progress(i+1, len(packages), name)
+		}
+
+		err := filepath.Walk(pkg, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || strings.HasPrefix(info.Name(), ".") || info.Size() == 0 {
+				return nil
+			}
+			// Manifests and previews are unique to each package by design
+			if info.Name() == "manifest.json" || info.Name() == "preview.png" {
+				return nil
+			}
+
+			rel, err := filepath.Rel(cwd, path)
+			if err != nil {
+				return err
+			}
+
+			report.Scanned++
+			bySize[info.Size()] = append(bySize[info.Size()], libraryFile{
+				path: path,
+				rel:  rel,
+				pkg:  name,
+				size: info.Size(),
+			})
+			return nil
+		})
+		if err != nil {
+			logger.DebugFn("Warning: Could not read %s: %v", name, err)
+		}
+	}
+
+	type pairKey struct{ first, second string }
+	overlaps := make(map[pairKey]*PackageOverlap)
+
+	for _, files := range bySize {
+		if len(files) < 2 {
+			continue
+		}
+
+		byHash := make(map[string][]libraryFile)
+		for _, file := range files {
+			sum, err := hashFile(file.path)
+			if err != nil {
+				logger.DebugFn("Warning: Could not hash %s: %v", file.rel, err)
+				continue
+			}
+			key := hex.EncodeToString(sum)
+			byHash[key] = append(byHash[key], file)
+		}
+
+		for _, same := range byHash {
+			if len(same) < 2 {
+				continue
+			}
+
+			group := DuplicateGroup{Size: same[0].size}
+			for _, file := range same {
+				group.Files = append(group.Files, file.rel)
+			}
+			sort.Strings(group.Files)
+			report.Groups = append(report.Groups, group)
+			report.Wasted += group.Wasted()
+
+			// Count each pair of packages sharing the file once
+			pkgs := make(map[string]bool)
+			for _, file := range same {
+				pkgs[file.pkg] = true
+			}
+			names := make([]string, 0, len(pkgs))
+			for name := range pkgs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for a := 0; a < len(names); a++ {
+				for b := a + 1; b < len(names); b++ {
+					key := pairKey{names[a], names[b]}
+					overlap := overlaps[key]
+					if overlap == nil {
+						overlap = &PackageOverlap{First: names[a], Second: names[b]}
+						overlaps[key] = overlap
+					}
+					overlap.Files++
+					overlap.Size += group.Size
+				}
+			}
+		}
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].Wasted() != report.Groups[j].Wasted() {
+			return report.Groups[i].Wasted() > report.Groups[j].Wasted()
+		}
+		return report.Groups[i].Files[0] < report.Groups[j].Files[0]
+	})
+
+	for _, overlap := range overlaps {
+		report.Overlaps = append(report.Overlaps, *overlap)
+	}
+	sort.Slice(report.Overlaps, func(i, j int) bool {
+		if report.Overlaps[i].Size != report.Overlaps[j].Size {
+			return report.Overlaps[i].Size > report.Overlaps[j].Size
+		}
+		return report.Overlaps[i].First+report.Overlaps[i].Second < report.Overlaps[j].First+report.Overlaps[j].Second
+	})
+
+	logger.DebugFn("Duplicate scan: %d files, %d duplicate groups, %s wasted",
+		report.Scanned, len(report.Groups), FormatSize(report.Wasted))
+	return report, nil
+}
+
+// FormatDuplicateReport renders the consolidation suggestions followed by every duplicate group
+func FormatDuplicateReport(report *DuplicateReport) string {
+	if len(report.Groups) == 0 {
+		return fmt.Sprintf("No duplicates in %d files.\n", report.Scanned)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d duplicate files waste %s\n", len(report.Groups), FormatSize(report.Wasted))
+
+	for _, overlap := range report.Overlaps {
+		fmt.Fprintf(&b, "%s and %s share %d files (%s)\n",
+			overlap.First, overlap.Second, overlap.Files, FormatSize(overlap.Size))
+	}
+
+	for _, group := range report.Groups {
+		fmt.Fprintf(&b, "%d copies, %s each:\n", len(group.Files), FormatSize(group.Size))
+		for _, file := range group.Files {
+			fmt.Fprintf(&b, "   %s\n", file)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+// src/internal/themes/excluded_systems.go
+// Per-system opt-out list for systems that should never be themed
+
+package themes
+
+import (
+	"fmt"
+	"regexp"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// systemTagRegex extracts a system tag such as "GBA" from "Game Boy Advance (GBA)"
+var systemTagRegex = regexp.MustCompile(`\((.*?)\)`)
+
+// systemTagFromName returns the system tag found in a file or folder name, if any
+func systemTagFromName(name string) string {
+	matches := systemTagRegex.FindStringSubmatch(name)
+	if len(matches) >= 2 {
+		return matches[1]
+	}
+	return ""
+}
+
+// GetExcludedSystems returns the tags of systems excluded from theming
+func GetExcludedSystems() []string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load excluded systems: %v", err)
+		return nil
+	}
+	return config.ExcludedSystems
+}
+
+// loadExcludedSystems returns the excluded system tags as a lookup set keyed by system.NameKey
+func loadExcludedSystems() map[string]bool {
+	excluded := make(map[string]bool)
+	for _, tag := range GetExcludedSystems() {
+		excluded[system.NameKey(tag)] = true
+	}
+	return excluded
+}
+
+// isTagExcluded reports whether a system tag is in the excluded set, ignoring case and Unicode composition
+func isTagExcluded(tag string, excluded map[string]bool) bool {
+	return tag != "" && excluded[system.NameKey(tag)]
+}
+
+// isNameExcluded reports whether the system tag in a file or folder name is excluded
+func isNameExcluded(name string, excluded map[string]bool) bool {
+	return isTagExcluded(systemTagFromName(name), excluded)
+}
+
+// isMappingExcluded reports whether a path mapping targets an excluded system
+func isMappingExcluded(mapping PathMapping, excluded map[string]bool) bool {
+	if len(excluded) == 0 {
+		return false
+	}
+
+	if mapping.Metadata != nil {
+		if tag := mapping.Metadata["SystemTag"]; tag != "" {
+			return isTagExcluded(tag, excluded)
+		}
+	}
+
+	// Fall back to the tag in the destination path, e.g. Roms/Name (TAG)/.media/bg.png
+	return isNameExcluded(mapping.SystemPath, excluded)
+}
+
+// ToggleExcludedSystem excludes a system from theming, or includes it again if already excluded
+func ToggleExcludedSystem(tag string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	var updated []string
+	found := false
+	for _, excluded := range config.ExcludedSystems {
+		if system.SameName(excluded, tag) {
+			found = true
+			continue
+		}
+		updated = append(updated, excluded)
+	}
+
+	if !found {
+		updated = append(updated, tag)
+		logging.LogDebug("Excluded system from theming: %s", tag)
+	} else {
+		logging.LogDebug("Included system in theming: %s", tag)
+	}
+
+	config.ExcludedSystems = updated
+	return SaveConfig(config)
+}
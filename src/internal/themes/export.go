@@ -4,6 +4,7 @@
 package themes
 
 import (
+	"context"
 	"fmt"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system"
@@ -17,15 +18,12 @@ import (
 
 // CreateThemeExportDirectory creates a new theme directory with sequential naming
 func CreateThemeExportDirectory() (string, error) {
-	// Get the current directory
-	cwd, err := os.Getwd()
+	// Exports directory, in the pak unless set to another folder in Settings
+	exportsDir, err := GetExportsDir()
 	if err != nil {
-		return "", fmt.Errorf("error getting current directory: %w", err)
+		return "", err
 	}
 
-	// Path to Exports directory directly instead of Themes/Exports
-	exportsDir := filepath.Join(cwd, "Exports")
-
 	// Ensure directory exists
 	if err := os.MkdirAll(exportsDir, 0755); err != nil {
 		return "", fmt.Errorf("error creating exports directory: %w", err)
@@ -40,8 +38,10 @@ func CreateThemeExportDirectory() (string, error) {
 		themePath = filepath.Join(exportsDir, themeName)
 
 		if _, err := os.Stat(themePath); os.IsNotExist(err) {
-			// Theme directory doesn't exist, we can use this name
-			break
+			// Theme directory doesn't exist, we can use this name unless it was zipped
+			if _, err := os.Stat(themePath + ".zip"); os.IsNotExist(err) {
+				break
+			}
 		}
 
 		themeNumber++
@@ -56,6 +56,7 @@ func CreateThemeExportDirectory() (string, error) {
 		"Icons/CollectionIcons",
 		"Overlays",
 		"Fonts",
+		"GameArt",
 		// Removed "Settings" directory since we're storing settings directly in manifest.json
 	}
 
@@ -66,23 +67,45 @@ func CreateThemeExportDirectory() (string, error) {
 		}
 	}
 
+	lastExportPath = themePath
 	return themePath, nil
 }
 
-// ExportTheme exports the current theme settings
+// ExportTheme exports the current theme settings, remembering the package so it can be
+// copied elsewhere afterwards
 func ExportTheme() error {
+	packagePath, err := ExportThemeContext(context.Background())
+	lastExportPath = packagePath
+	return err
+}
+
+// ExportThemeContext exports the current theme settings and returns the package it wrote,
+// stopping between steps once ctx is done. A stopped export is removed rather than left half
+// written.
+func ExportThemeContext(ctx context.Context) (_ string, err error) {
+	var themePath string
+	defer func() { recordOperation("Exported theme", exportName(themePath), err) }()
+	defer logging.BeginOperation("export theme", "")()
+
 	// Create logger
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
 
 	logger.DebugFn("Starting theme export")
+	defer logging.LogMemoryUsage("theme export")
+
+	// Fail once with a clear message instead of on every file
+	if err := CheckStorageWritable(); err != nil {
+		logger.DebugFn("Storage check failed: %v", err)
+		return "", err
+	}
 
 	// Create theme directory
-	themePath, err := CreateThemeExportDirectory()
+	themePath, err = CreateThemeExportDirectory()
 	if err != nil {
 		logger.DebugFn("Error creating theme directory: %v", err)
-		return fmt.Errorf("error creating theme directory: %w", err)
+		return "", fmt.Errorf("error creating theme directory: %w", err)
 	}
 
 	logger.DebugFn("Created theme directory: %s", themePath)
@@ -92,43 +115,161 @@ func ExportTheme() error {
 
 	// Try to determine author from global manifest if available
 	author := "AuthorName" // Default
+	var currentTheme, license string
 	globalManifest, err := LoadGlobalManifest()
 	if err == nil && globalManifest != nil {
+		currentTheme = globalManifest.CurrentTheme
+
 		// Try to get author from current theme if it exists
-		if globalManifest.CurrentTheme != "" {
+		if currentTheme != "" {
 			// Try to load the theme to get author
-			currThemePath := filepath.Join(filepath.Dir(themePath), "..", "Themes", globalManifest.CurrentTheme)
+			cwd, _ := os.Getwd()
+			currThemePath := filepath.Join(cwd, "Themes", currentTheme)
 			currManifest, err := ValidateTheme(currThemePath, logger)
 			if err == nil && currManifest.ThemeInfo.Author != "" {
 				author = currManifest.ThemeInfo.Author
 			}
+			// The export is built on the theme, so it keeps its terms
+			if err == nil {
+				license = currManifest.ThemeInfo.License
+			}
+		}
+	}
+
+	// Exports of an applied theme are named after it, so exporting it again makes its next version
+	var previousPath string
+	if currentTheme != "" {
+		themeName = currentTheme
+		previousPath = filepath.Join(filepath.Dir(themePath), currentTheme)
+		unzipPreviousExport(previousPath, logger)
+		if _, err := os.Stat(previousPath); err != nil {
+			previousPath = ""
 		}
 	}
 
 	// Initialize minimal manifest
 	manifest := CreateMinimalThemeManifest(themeName, author)
+	manifest.ThemeInfo.License = license
 
 	// Get system paths
 	systemPaths, err := system.GetSystemPaths()
 	if err != nil {
 		logger.DebugFn("Error getting system paths: %v", err)
-		return fmt.Errorf("error getting system paths: %w", err)
+		return "", fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	if err := exportThemeContent(ctx, themePath, manifest, systemPaths, logger); err != nil {
+		return "", err
+	}
+
+	// A re-export bumps the version and records what changed since the previous export
+	if previousPath != "" {
+		if err := addChangelogEntry(previousPath, themePath, manifest, logger); err != nil {
+			logger.DebugFn("Warning: Could not compare with previous export: %v", err)
+		}
 	}
 
+	// Write manifest
+	if err := WriteManifest(themePath, manifest, logger); err != nil {
+		logger.DebugFn("Error writing manifest: %v", err)
+		return "", fmt.Errorf("error writing manifest: %w", err)
+	}
+
+	// The previous export stays as it is unless this one got this far
+	if err := exportStopped(ctx, themePath, logger); err != nil {
+		return "", err
+	}
+
+	// The previous export is archived and the new version takes its name
+	if currentTheme != "" {
+		releasePath := filepath.Join(filepath.Dir(themePath), currentTheme)
+		if previousPath != "" {
+			if err := archiveExport(previousPath, logger); err != nil {
+				logger.DebugFn("Error archiving previous export: %v", err)
+				return "", fmt.Errorf("error archiving previous export: %w", err)
+			}
+		}
+		if err := os.Rename(themePath, releasePath); err != nil {
+			logger.DebugFn("Error naming export: %v", err)
+			return "", fmt.Errorf("error naming export: %w", err)
+		}
+		themePath = releasePath
+	}
+
+	logger.DebugFn("Theme export completed successfully: %s", themePath)
+
+	// Split large exports so they can be shared over services with size limits
+	volumes, err := SplitPackage(themePath, GetVolumeSizeSetting(), logger)
+	if err != nil {
+		logger.DebugFn("Warning: Could not split theme into volumes: %v", err)
+	}
+
+	// Zipped exports are a single file that's easy to share
+	if GetZipThemesSetting() {
+		archivePath, err := archiveThemeFolder(themePath, logger)
+		if err != nil {
+			logger.DebugFn("Error zipping export: %v", err)
+			return "", fmt.Errorf("error zipping export: %w", err)
+		}
+		themePath = archivePath
+	}
+	lastExportPath = themePath
+
+	// Show success message to user
+	themeName = filepath.Base(themePath)
+	if previousPath != "" {
+		themeName = fmt.Sprintf("%s v%s", themeName, manifest.ThemeInfo.Version)
+	}
+	if len(volumes) > 0 {
+		ui.ShowMessage(fmt.Sprintf("Theme exported successfully: %s\nAlso split into %d volumes for sharing", themeName, len(volumes)), "3")
+	} else {
+		ui.ShowMessage(fmt.Sprintf("Theme exported successfully: %s", themeName), "3")
+	}
+
+	return themePath, nil
+}
+
+// exportThemeContent copies everything a theme carries from the device into the package at
+// themePath, stopping between steps once ctx is done
+func exportThemeContent(ctx context.Context, themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) error {
 	// Copy the actual files but don't add to manifest content or path_mappings
 
 	// Export wallpapers
 	exportWallpapers(themePath, manifest, systemPaths, logger)
 
+	// Export wallpapers of Tools subfolders and custom categories
+	folderMappings := exportFolderWallpapers(themePath, "Wallpapers", systemPaths, logger)
+	if len(folderMappings) > 0 {
+		manifest.PathMappings.Wallpapers = append(manifest.PathMappings.Wallpapers, folderMappings...)
+		manifest.Content.Wallpapers.Present = true
+		manifest.Content.Wallpapers.Count += len(folderMappings)
+	}
+
+	if err := exportStopped(ctx, themePath, logger); err != nil {
+		return err
+	}
+
 	// Export icons
 	exportIcons(themePath, manifest, systemPaths, logger)
+	if err := exportStopped(ctx, themePath, logger); err != nil {
+		return err
+	}
 
 	// Export overlays
 	exportOverlays(themePath, manifest, systemPaths, logger)
+	if err := exportStopped(ctx, themePath, logger); err != nil {
+		return err
+	}
 
 	// Export fonts
 	exportFonts(themePath, manifest, logger)
 
+	// Export game art
+	exportGameArt(themePath, manifest, logger)
+	if err := exportStopped(ctx, themePath, logger); err != nil {
+		return err
+	}
+
 	// Read and include accent settings directly in manifest
 	if err := readAccentSettingsFromSystem(manifest, logger); err != nil {
 		logger.DebugFn("Warning: Could not read accent settings: %v", err)
@@ -139,19 +280,28 @@ func ExportTheme() error {
 		logger.DebugFn("Warning: Could not read LED settings: %v", err)
 	}
 
-	// Write manifest
-	if err := WriteManifest(themePath, manifest, logger); err != nil {
-		logger.DebugFn("Error writing manifest: %v", err)
-		return fmt.Errorf("error writing manifest: %w", err)
-	}
+	// Include any other settings files the user allowlisted
+	exportSettingsSnapshot(themePath, manifest, logger)
 
-	logger.DebugFn("Theme export completed successfully: %s", themePath)
+	return exportStopped(ctx, themePath, logger)
+}
 
-	// Show success message to user
-	themeName = filepath.Base(themePath)
-	ui.ShowMessage(fmt.Sprintf("Theme exported successfully: %s", themeName), "3")
+// exportStopped removes a partial export once ctx is done or strict mode hit a warning, so
+// a timed out, cancelled or uncertified export never leaves a half-written package behind
+func exportStopped(ctx context.Context, packagePath string, logger *Logger) error {
+	err := ctx.Err()
+	if err == nil {
+		err = strictStopped()
+	}
+	if err == nil {
+		return nil
+	}
 
-	return nil
+	logger.DebugFn("Export stopped: %v", err)
+	if removeErr := os.RemoveAll(packagePath); removeErr != nil {
+		logger.DebugFn("Warning: Could not remove partial export %s: %v", packagePath, removeErr)
+	}
+	return fmt.Errorf("export stopped: %w", err)
 }
 
 func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) {
@@ -160,135 +310,15 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 	manifest.Content.Wallpapers.Count = 0
 	manifest.PathMappings.Wallpapers = []PathMapping{}
 
-	// Check for root wallpaper
-	rootBg := filepath.Join(systemPaths.Root, "bg.png")
-	if _, err := os.Stat(rootBg); err == nil {
-		// Copy to theme
-		destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", "Root.png")
-		if err := CopyFile(rootBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy root bg.png: %v", err)
-		} else {
-			// Add to manifest
-			manifest.PathMappings.Wallpapers = append(
-				manifest.PathMappings.Wallpapers,
-				PathMapping{
-					ThemePath:  "Wallpapers/SystemWallpapers/Root.png",
-					SystemPath: rootBg,
-					Metadata: map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Main",
-					},
-				},
-			)
-			manifest.Content.Wallpapers.Present = true
-			manifest.Content.Wallpapers.Count++
-			logger.DebugFn("Exported Root wallpaper to %s", destPath)
-		}
+	// Special wallpapers such as Root and Tools
+	specialMappings := exportSpecialDestinations(SpecialWallpapers(), themePath, "Wallpapers/SystemWallpapers", "WallpaperType", systemPaths, logger)
+	if len(specialMappings) > 0 {
+		manifest.PathMappings.Wallpapers = append(manifest.PathMappings.Wallpapers, specialMappings...)
+		manifest.Content.Wallpapers.Present = true
+		manifest.Content.Wallpapers.Count += len(specialMappings)
 	}
 
-	// Check for root media wallpaper
-	rootMediaBg := filepath.Join(systemPaths.Root, ".media", "bg.png")
-	if _, err := os.Stat(rootMediaBg); err == nil {
-		// Copy to theme
-		destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", "Root-Media.png")
-		if err := CopyFile(rootMediaBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy root media bg.png: %v", err)
-		} else {
-			// Add to manifest
-			manifest.PathMappings.Wallpapers = append(
-				manifest.PathMappings.Wallpapers,
-				PathMapping{
-					ThemePath:  "Wallpapers/SystemWallpapers/Root-Media.png",
-					SystemPath: rootMediaBg,
-					Metadata: map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Media",
-					},
-				},
-			)
-			manifest.Content.Wallpapers.Present = true
-			manifest.Content.Wallpapers.Count++
-			logger.DebugFn("Exported Root-Media wallpaper to %s", destPath)
-		}
-	}
-
-	// Check for Recently Played wallpaper
-	rpBg := filepath.Join(systemPaths.RecentlyPlayed, ".media", "bg.png")
-	if _, err := os.Stat(rpBg); err == nil {
-		// Copy to theme
-		destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", "Recently Played.png")
-		if err := CopyFile(rpBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Recently Played bg.png: %v", err)
-		} else {
-			// Add to manifest
-			manifest.PathMappings.Wallpapers = append(
-				manifest.PathMappings.Wallpapers,
-				PathMapping{
-					ThemePath:  "Wallpapers/SystemWallpapers/Recently Played.png",
-					SystemPath: rpBg,
-					Metadata: map[string]string{
-						"SystemName":    "Recently Played",
-						"WallpaperType": "Media",
-					},
-				},
-			)
-			manifest.Content.Wallpapers.Present = true
-			manifest.Content.Wallpapers.Count++
-			logger.DebugFn("Exported Recently Played wallpaper to %s", destPath)
-		}
-	}
-
-	// Check for Tools wallpaper
-	toolsBg := filepath.Join(systemPaths.Tools, ".media", "bg.png")
-	if _, err := os.Stat(toolsBg); err == nil {
-		// Copy to theme
-		destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", "Tools.png")
-		if err := CopyFile(toolsBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Tools bg.png: %v", err)
-		} else {
-			// Add to manifest
-			manifest.PathMappings.Wallpapers = append(
-				manifest.PathMappings.Wallpapers,
-				PathMapping{
-					ThemePath:  "Wallpapers/SystemWallpapers/Tools.png",
-					SystemPath: toolsBg,
-					Metadata: map[string]string{
-						"SystemName":    "Tools",
-						"WallpaperType": "Media",
-					},
-				},
-			)
-			manifest.Content.Wallpapers.Present = true
-			manifest.Content.Wallpapers.Count++
-			logger.DebugFn("Exported Tools wallpaper to %s", destPath)
-		}
-	}
-
-	// Check for Collections wallpaper
-	collectionsBg := filepath.Join(systemPaths.Root, "Collections", ".media", "bg.png")
-	if _, err := os.Stat(collectionsBg); err == nil {
-		// Copy to theme
-		destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", "Collections.png")
-		if err := CopyFile(collectionsBg, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Collections bg.png: %v", err)
-		} else {
-			// Add to manifest
-			manifest.PathMappings.Wallpapers = append(
-				manifest.PathMappings.Wallpapers,
-				PathMapping{
-					ThemePath:  "Wallpapers/SystemWallpapers/Collections.png",
-					SystemPath: collectionsBg,
-					Metadata: map[string]string{
-						"SystemName":    "Collections",
-						"WallpaperType": "Media",
-					},
-				},
-			)
-			manifest.Content.Wallpapers.Present = true
-			manifest.Content.Wallpapers.Count++
-			logger.DebugFn("Exported Collections wallpaper to %s", destPath)
-		}
-	}
+	manifest.Content.Wallpapers.Sleep = exportSleepWallpaper(themePath, "Wallpapers/"+sleepWallpaperFile, systemPaths, logger)
 
 	// Create the ListWallpapers directory if it doesn't exist yet
 	listWallpapersDir := filepath.Join(themePath, "Wallpapers", "ListWallpapers")
@@ -296,6 +326,9 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 		logger.DebugFn("Warning: Could not create ListWallpapers directory: %v", err)
 	}
 
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
+
 	// Check for system wallpapers and list wallpapers
 	for _, system := range systemPaths.Systems {
 		if system.Tag == "" {
@@ -303,6 +336,11 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 			continue
 		}
 
+		if isTagExcluded(system.Tag, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", system.Name)
+			continue
+		}
+
 		// Main System wallpaper (bg.png)
 		systemBg := filepath.Join(system.MediaPath, "bg.png")
 		if _, err := os.Stat(systemBg); err == nil {
@@ -318,7 +356,8 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 				logger.DebugFn("Adding tag to system name: %s (%s)", system.Name, system.Tag)
 			}
 
-			destPath := filepath.Join(themePath, "Wallpapers", "SystemWallpapers", fileName)
+			themeFile := safePackagePath(&manifest.FileNames, "Wallpapers/SystemWallpapers", fileName)
+			destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 			if err := CopyFile(systemBg, destPath); err != nil {
 				logger.DebugFn("Warning: Could not copy system %s bg.png: %v", system.Name, err)
@@ -327,7 +366,7 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 				manifest.PathMappings.Wallpapers = append(
 					manifest.PathMappings.Wallpapers,
 					PathMapping{
-						ThemePath:  "Wallpapers/SystemWallpapers/" + fileName,
+						ThemePath:  themeFile,
 						SystemPath: systemBg,
 						Metadata: map[string]string{
 							"SystemName":    system.Name,
@@ -358,7 +397,8 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 			// Add the -list suffix
 			fileName := fmt.Sprintf("%s-list.png", baseFileName)
 
-			destPath := filepath.Join(themePath, "Wallpapers", "ListWallpapers", fileName)
+			themeFile := safePackagePath(&manifest.FileNames, "Wallpapers/ListWallpapers", fileName)
+			destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 			if err := CopyFile(systemListBg, destPath); err != nil {
 				logger.DebugFn("Warning: Could not copy system %s bglist.png: %v", system.Name, err)
@@ -367,7 +407,7 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 				manifest.PathMappings.Wallpapers = append(
 					manifest.PathMappings.Wallpapers,
 					PathMapping{
-						ThemePath:  "Wallpapers/ListWallpapers/" + fileName,
+						ThemePath:  themeFile,
 						SystemPath: systemListBg,
 						Metadata: map[string]string{
 							"SystemName":    system.Name,
@@ -402,7 +442,8 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 		if _, err := os.Stat(collectionBg); err == nil {
 			// Create filename for collection
 			fileName := fmt.Sprintf("%s.png", collectionName)
-			destPath := filepath.Join(themePath, "Wallpapers", "CollectionWallpapers", fileName)
+			themeFile := safePackagePath(&manifest.FileNames, "Wallpapers/CollectionWallpapers", fileName)
+			destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 			if err := CopyFile(collectionBg, destPath); err != nil {
 				logger.DebugFn("Warning: Could not copy collection %s bg.png: %v", collectionName, err)
@@ -411,7 +452,7 @@ func exportWallpapers(themePath string, manifest *ThemeManifest, systemPaths *sy
 				manifest.PathMappings.Wallpapers = append(
 					manifest.PathMappings.Wallpapers,
 					PathMapping{
-						ThemePath:  "Wallpapers/CollectionWallpapers/" + fileName,
+						ThemePath:  themeFile,
 						SystemPath: collectionBg,
 						Metadata: map[string]string{
 							"CollectionName": collectionName,
@@ -436,84 +477,25 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 	manifest.Content.Icons.CollectionCount = 0
 	manifest.PathMappings.Icons = []PathMapping{}
 
-	// Export system icons
-
-	// Recently Played icon - in SD_CARD/.media/Recently Played.png
-	rpIcon := filepath.Join(systemPaths.Root, ".media", "Recently Played.png")
-	if _, err := os.Stat(rpIcon); err == nil {
-		destPath := filepath.Join(themePath, "Icons", "SystemIcons", "Recently Played.png")
-		if err := CopyFile(rpIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Recently Played icon: %v", err)
-		} else {
-			manifest.PathMappings.Icons = append(
-				manifest.PathMappings.Icons,
-				PathMapping{
-					ThemePath:  "Icons/SystemIcons/Recently Played.png",
-					SystemPath: rpIcon,
-					Metadata: map[string]string{
-						"SystemName": "Recently Played",
-						"IconType":   "System",
-					},
-				},
-			)
-			manifest.Content.Icons.Present = true
-			manifest.Content.Icons.SystemCount++
-			logger.DebugFn("Exported Recently Played icon to %s", destPath)
-		}
-	}
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
 
-	// Tools icon - use parent path of Tools since Tools path includes tg5040
-	toolsParentDir := filepath.Dir(systemPaths.Tools) // Gets /mnt/SDCARD/Tools
-	toolsIcon := filepath.Join(toolsParentDir, ".media", "tg5040.png")
-	if _, err := os.Stat(toolsIcon); err == nil {
-		destPath := filepath.Join(themePath, "Icons", "SystemIcons", "Tools.png")
-		if err := CopyFile(toolsIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Tools icon: %v", err)
-		} else {
-			manifest.PathMappings.Icons = append(
-				manifest.PathMappings.Icons,
-				PathMapping{
-					ThemePath:  "Icons/SystemIcons/Tools.png",
-					SystemPath: toolsIcon,
-					Metadata: map[string]string{
-						"SystemName": "Tools",
-						"IconType":   "System",
-					},
-				},
-			)
-			manifest.Content.Icons.Present = true
-			manifest.Content.Icons.SystemCount++
-			logger.DebugFn("Exported Tools icon to %s", destPath)
-		}
-	}
-
-	// Collections icon - in SD_CARD/.media/Collections.png
-	collectionsIcon := filepath.Join(systemPaths.Root, ".media", "Collections.png")
-	if _, err := os.Stat(collectionsIcon); err == nil {
-		destPath := filepath.Join(themePath, "Icons", "SystemIcons", "Collections.png")
-		if err := CopyFile(collectionsIcon, destPath); err != nil {
-			logger.DebugFn("Warning: Could not copy Collections icon: %v", err)
-		} else {
-			manifest.PathMappings.Icons = append(
-				manifest.PathMappings.Icons,
-				PathMapping{
-					ThemePath:  "Icons/SystemIcons/Collections.png",
-					SystemPath: collectionsIcon,
-					Metadata: map[string]string{
-						"SystemName": "Collections",
-						"IconType":   "System",
-					},
-				},
-			)
-			manifest.Content.Icons.Present = true
-			manifest.Content.Icons.SystemCount++
-			logger.DebugFn("Exported Collections icon to %s", destPath)
-		}
+	// Special icons such as Recently Played and Tools
+	specialMappings := exportSpecialDestinations(SpecialIcons(), themePath, "Icons/SystemIcons", "IconType", systemPaths, logger)
+	if len(specialMappings) > 0 {
+		manifest.PathMappings.Icons = append(manifest.PathMappings.Icons, specialMappings...)
+		manifest.Content.Icons.Present = true
+		manifest.Content.Icons.SystemCount += len(specialMappings)
 	}
 
 	// System-specific icons - each system has its own icon file in Roms/.media/ with system name and tag
-	systemIconsDir := filepath.Join(systemPaths.Roms, ".media")
-	if _, err := os.Stat(systemIconsDir); err == nil {
+	// Every storage root has its own Roms/.media; a system on several roots is exported once
+	exportedIcons := make(map[string]bool)
+	for _, romsDir := range systemPaths.RomsDirs {
+		systemIconsDir := filepath.Join(romsDir, ".media")
+		if _, err := os.Stat(systemIconsDir); err != nil {
+			continue
+		}
 		entries, err := os.ReadDir(systemIconsDir)
 		if err == nil {
 			for _, entry := range entries {
@@ -528,9 +510,7 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 
 				// Only process icons that match system naming pattern
 				// Skip other special icons like Recently Played that we handle separately
-				if entry.Name() == "Recently Played.png" ||
-					entry.Name() == "Collections.png" ||
-					entry.Name() == "tg5040.png" {
+				if isSpecialIconFile(entry.Name(), systemPaths) {
 					continue
 				}
 
@@ -541,8 +521,20 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 					continue
 				}
 
+				// Skip icons of systems excluded from theming
+				if isNameExcluded(entry.Name(), excluded) {
+					logger.DebugFn("Skipping excluded system icon: %s", entry.Name())
+					continue
+				}
+
+				if exportedIcons[entry.Name()] {
+					continue
+				}
+				exportedIcons[entry.Name()] = true
+
 				systemIconPath := filepath.Join(systemIconsDir, entry.Name())
-				destPath := filepath.Join(themePath, "Icons", "SystemIcons", entry.Name())
+				themeFile := safePackagePath(&manifest.FileNames, "Icons/SystemIcons", entry.Name())
+				destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 				if err := CopyFile(systemIconPath, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy system icon %s: %v", entry.Name(), err)
@@ -557,7 +549,7 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 					manifest.PathMappings.Icons = append(
 						manifest.PathMappings.Icons,
 						PathMapping{
-							ThemePath:  "Icons/SystemIcons/" + entry.Name(),
+							ThemePath:  themeFile,
 							SystemPath: systemIconPath,
 							Metadata: map[string]string{
 								"SystemName": strings.TrimSuffix(entry.Name(), ".png"),
@@ -587,7 +579,8 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 			toolIcon := filepath.Join(toolsDir, toolName, ".media", toolName+".png")
 
 			if _, err := os.Stat(toolIcon); err == nil {
-				destPath := filepath.Join(themePath, "Icons", "ToolIcons", fmt.Sprintf("%s.png", toolName))
+				themeFile := safePackagePath(&manifest.FileNames, "Icons/ToolIcons", toolName+".png")
+				destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 				if err := CopyFile(toolIcon, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy tool %s icon: %v", toolName, err)
@@ -595,7 +588,7 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 					manifest.PathMappings.Icons = append(
 						manifest.PathMappings.Icons,
 						PathMapping{
-							ThemePath:  fmt.Sprintf("Icons/ToolIcons/%s.png", toolName),
+							ThemePath:  themeFile,
 							SystemPath: toolIcon,
 							Metadata: map[string]string{
 								"ToolName": toolName,
@@ -624,7 +617,8 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 			collectionIcon := filepath.Join(collectionsDir, collectionName, ".media", collectionName+".png")
 
 			if _, err := os.Stat(collectionIcon); err == nil {
-				destPath := filepath.Join(themePath, "Icons", "CollectionIcons", fmt.Sprintf("%s.png", collectionName))
+				themeFile := safePackagePath(&manifest.FileNames, "Icons/CollectionIcons", collectionName+".png")
+				destPath := filepath.Join(themePath, filepath.FromSlash(themeFile))
 
 				if err := CopyFile(collectionIcon, destPath); err != nil {
 					logger.DebugFn("Warning: Could not copy collection %s icon: %v", collectionName, err)
@@ -632,7 +626,7 @@ func exportIcons(themePath string, manifest *ThemeManifest, systemPaths *system.
 					manifest.PathMappings.Icons = append(
 						manifest.PathMappings.Icons,
 						PathMapping{
-							ThemePath:  fmt.Sprintf("Icons/CollectionIcons/%s.png", collectionName),
+							ThemePath:  themeFile,
 							SystemPath: collectionIcon,
 							Metadata: map[string]string{
 								"CollectionName": collectionName,
@@ -670,6 +664,9 @@ func exportOverlays(themePath string, manifest *ThemeManifest, systemPaths *syst
 		return
 	}
 
+	// Systems the user excluded from theming are left out of exports
+	excluded := loadExcludedSystems()
+
 	// Process each system's overlays
 	for _, entry := range entries {
 		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
@@ -679,6 +676,11 @@ func exportOverlays(themePath string, manifest *ThemeManifest, systemPaths *syst
 		systemTag := entry.Name()
 		systemOverlaysPath := filepath.Join(overlaysDir, systemTag)
 
+		if isTagExcluded(systemTag, excluded) {
+			logger.DebugFn("Skipping excluded system overlays: %s", systemTag)
+			continue
+		}
+
 		// List overlay files for this system
 		overlayFiles, err := os.ReadDir(systemOverlaysPath)
 		if err != nil {
@@ -722,10 +724,7 @@ func exportOverlays(themePath string, manifest *ThemeManifest, systemPaths *syst
 					PathMapping{
 						ThemePath:  themePath,
 						SystemPath: srcPath,
-						Metadata: map[string]string{
-							"SystemTag":   systemTag,
-							"OverlayName": file.Name(),
-						},
+						Metadata:   overlayMetadata(systemTag, file.Name(), nil),
 					},
 				)
 
@@ -741,7 +740,7 @@ func exportOverlays(themePath string, manifest *ThemeManifest, systemPaths *syst
 			// Check if system is already in the list
 			var systemExists bool
 			for _, sys := range manifest.Content.Overlays.Systems {
-				if sys == systemTag {
+				if system.SameName(sys, systemTag) {
 					systemExists = true
 					break
 				}
@@ -770,6 +769,7 @@ func exportFonts(themePath string, manifest *ThemeManifest, logger *Logger) erro
 	manifest.Content.Fonts.Present = false
 	manifest.Content.Fonts.OGReplaced = false
 	manifest.Content.Fonts.NextReplaced = false
+	manifest.Content.Fonts.Subset = nil
 	manifest.PathMappings.Fonts = make(map[string]PathMapping)
 
 	// Define font paths to check - CORRECTED PATHS
@@ -789,9 +789,21 @@ func exportFonts(themePath string, manifest *ThemeManifest, logger *Logger) erro
 
 		dstPath := filepath.Join(fontsDir, fontName+".ttf")
 
-		if err := CopyFile(sourcePath, dstPath); err != nil {
-			logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
-			continue
+		// Backups of the stock fonts are kept whole so they can be restored
+		if strings.Contains(fontName, "backup") {
+			if err := CopyFile(sourcePath, dstPath); err != nil {
+				logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
+				continue
+			}
+		} else {
+			subset, err := exportFontFile(sourcePath, dstPath, logger)
+			if err != nil {
+				logger.DebugFn("Warning: Could not copy font %s: %v", fontName, err)
+				continue
+			}
+			if subset != nil {
+				manifest.Content.Fonts.Subset = subset
+			}
 		}
 
 		// Add to manifest
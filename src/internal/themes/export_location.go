@@ -0,0 +1,183 @@
+// src/internal/themes/export_location.go
+// Where exports are written, and copying finished exports to external storage
+
+package themes
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// externalExportDir is the folder exports go into on external storage
+const externalExportDir = "Theme Exports"
+
+// externalFilesystems are the mount types worth offering as export destinations
+var externalFilesystems = map[string]bool{
+	"vfat": true, "exfat": true, "ntfs": true, "ntfs3": true, "fuseblk": true, "ext4": true,
+}
+
+// lastExportPath is the package written by the most recent export
+var lastExportPath string
+
+// stagedExportsDir, when set, takes the place of the exports folder for packages the
+// manager writes for itself rather than for the user
+var stagedExportsDir string
+
+// GetExportDirSetting returns the export folder set in config.json, empty for the default
+func GetExportDirSetting() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load export folder setting: %v", err)
+		return ""
+	}
+	return config.ExportDir
+}
+
+// SetExportDirSetting sets the folder exports are written to, empty for the default
+func SetExportDirSetting(dir string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.ExportDir = dir
+	return SaveConfig(config)
+}
+
+// GetExportsDir returns the folder exports are written to: the configured folder, or
+// Exports inside the pak. A configured folder on storage that isn't mounted is an error
+// rather than silently falling back, so a big export never lands somewhere unexpected.
+func GetExportsDir() (string, error) {
+	if stagedExportsDir != "" {
+		return stagedExportsDir, nil
+	}
+
+	if dir := GetExportDirSetting(); dir != "" {
+		if mountPoint, _, err := mountOptions(dir); err == nil && mountPoint == "/" {
+			return "", fmt.Errorf("the export folder %s is not on mounted storage. Connect the drive or change Export Folder in Settings", dir)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("error creating export folder %s: %w", dir, err)
+		}
+		return dir, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, "Exports"), nil
+}
+
+// exportPackagePath returns where a package named name is exported, and remembers it
+// so the export can be copied elsewhere afterwards
+func exportPackagePath(name string) (string, error) {
+	exportsDir, err := GetExportsDir()
+	if err != nil {
+		return "", err
+	}
+
+	lastExportPath = filepath.Join(exportsDir, name)
+	return lastExportPath, nil
+}
+
+// LastExportPath returns the package written by the most recent export, if any
+func LastExportPath() string {
+	return lastExportPath
+}
+
+// ExternalStorageRoots returns mount points other than the SD card that exports can
+// be written or copied to, such as a USB drive
+func ExternalStorageRoots() []string {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		logging.LogDebug("Warning: Could not read mounts: %v", err)
+		return nil
+	}
+	defer file.Close()
+
+	var roots []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !externalFilesystems[fields[2]] {
+			continue
+		}
+
+		point := fields[1]
+		if point == storageRoot || strings.HasPrefix(point, storageRoot+"/") {
+			continue
+		}
+		if !strings.HasPrefix(point, "/mnt/") && !strings.HasPrefix(point, "/media/") {
+			continue
+		}
+
+		readOnly := false
+		for _, option := range strings.Split(fields[3], ",") {
+			if option == "ro" {
+				readOnly = true
+			}
+		}
+		if !readOnly {
+			roots = append(roots, point)
+		}
+	}
+
+	sort.Strings(roots)
+	return roots
+}
+
+// ExternalExportDir returns the export folder used on an external storage root
+func ExternalExportDir(root string) string {
+	return filepath.Join(root, externalExportDir)
+}
+
+// CopyExportTo copies an exported package, and any split volumes next to it, into the
+// export folder on an external storage root. Returns the folder it was copied to.
+func CopyExportTo(packagePath, root string) (string, error) {
+	if err := CheckBatteryForOperation("copying an export"); err != nil {
+		return "", err
+	}
+
+	destDir := ExternalExportDir(root)
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating %s: %w", destDir, err)
+	}
+
+	logging.LogDebug("Copying export %s to %s", packagePath, destDir)
+
+	parent := filepath.Dir(packagePath)
+	err := filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		return CopyFile(path, filepath.Join(destDir, relPath))
+	})
+	if err != nil {
+		return "", fmt.Errorf("error copying export: %w", err)
+	}
+
+	volumes, _ := filepath.Glob(packagePath + ".zip.*")
+	for _, volume := range volumes {
+		if err := CopyFile(volume, filepath.Join(destDir, filepath.Base(volume))); err != nil {
+			return "", fmt.Errorf("error copying volume: %w", err)
+		}
+	}
+
+	logging.LogDebug("Copied export %s and %d volumes to %s", filepath.Base(packagePath), len(volumes), destDir)
+	return destDir, nil
+}
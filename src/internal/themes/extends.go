@@ -0,0 +1,145 @@
+// src/internal/themes/extends.go
+// Variant themes: a theme that extends another installed theme and only carries the files it changes
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// maxExtendsDepth stops runaway chains of themes extending each other
+const maxExtendsDepth = 8
+
+// baseThemePath returns the installed theme a variant extends, accepting names with or without .theme
+func baseThemePath(themePath, base string) string {
+	if !strings.HasSuffix(base, ".theme") {
+		base += ".theme"
+	}
+	return filepath.Join(filepath.Dir(themePath), base)
+}
+
+// resolveExtends fills in everything a variant theme inherits from the theme it extends, and
+// recursively from that theme's own base. Files in the variant override base files written to
+// the same place. Inherited mappings point into the base package, so nothing is copied.
+// The merged manifest is only used for the apply in progress and is never saved.
+func resolveExtends(themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) error {
+	visited := map[string]bool{filepath.Base(themePath): true}
+
+	for depth := 0; manifest.Extends != ""; depth++ {
+		if depth >= maxExtendsDepth {
+			return fmt.Errorf("themes extend each other more than %d levels deep", maxExtendsDepth)
+		}
+
+		basePath := baseThemePath(themePath, manifest.Extends)
+		baseName := filepath.Base(basePath)
+		if visited[baseName] {
+			return fmt.Errorf("%s extends itself through %s", filepath.Base(themePath), baseName)
+		}
+		visited[baseName] = true
+
+		if _, err := os.Stat(basePath); err != nil {
+			return fmt.Errorf("base theme %s is not installed", baseName)
+		}
+
+		baseManifest, err := ValidateTheme(basePath, logger)
+		if err != nil {
+			return fmt.Errorf("error reading base theme %s: %w", baseName, err)
+		}
+		if err := UpdateManifestFromThemeContent(basePath, baseManifest, systemPaths, logger); err != nil {
+			logger.DebugFn("Warning: Error updating manifest of base theme %s: %v", baseName, err)
+		}
+
+		logger.DebugFn("%s extends %s", filepath.Base(themePath), baseName)
+		inheritFromBase(themePath, basePath, manifest, baseManifest, logger)
+
+		// Continue with whatever the base itself extends
+		manifest.Extends = baseManifest.Extends
+	}
+
+	return nil
+}
+
+// inheritFromBase merges the base manifest's mappings and settings into the variant's manifest
+func inheritFromBase(themePath, basePath string, manifest, base *ThemeManifest, logger *Logger) {
+	// Base files are addressed relative to the variant, e.g. "../Base.theme/Wallpapers/..."
+	relBase, err := filepath.Rel(themePath, basePath)
+	if err != nil {
+		logger.DebugFn("Warning: Could not resolve base theme path: %v", err)
+		return
+	}
+	rebase := func(mapping PathMapping) PathMapping {
+		mapping.ThemePath = filepath.ToSlash(filepath.Join(relBase, mapping.ThemePath))
+		return mapping
+	}
+
+	inheritMappings := func(own []PathMapping, inherited []PathMapping) ([]PathMapping, int) {
+		overridden := make(map[string]bool)
+		for _, mapping := range own {
+			overridden[mapping.SystemPath] = true
+		}
+
+		added := 0
+		for _, mapping := range inherited {
+			if !overridden[mapping.SystemPath] {
+				own = append(own, rebase(mapping))
+				added++
+			}
+		}
+		return own, added
+	}
+
+	var added int
+	manifest.PathMappings.Wallpapers, added = inheritMappings(manifest.PathMappings.Wallpapers, base.PathMappings.Wallpapers)
+	manifest.Content.Wallpapers.Count += added
+	manifest.Content.Wallpapers.Present = manifest.Content.Wallpapers.Count > 0
+
+	iconsBefore := len(manifest.PathMappings.Icons)
+	manifest.PathMappings.Icons, _ = inheritMappings(manifest.PathMappings.Icons, base.PathMappings.Icons)
+	for _, mapping := range manifest.PathMappings.Icons[iconsBefore:] {
+		switch mapping.Metadata["IconType"] {
+		case "Tool":
+			manifest.Content.Icons.ToolCount++
+		case "Collection":
+			manifest.Content.Icons.CollectionCount++
+		default:
+			manifest.Content.Icons.SystemCount++
+		}
+	}
+	manifest.Content.Icons.Present = len(manifest.PathMappings.Icons) > 0
+
+	manifest.PathMappings.Overlays, _ = inheritMappings(manifest.PathMappings.Overlays, base.PathMappings.Overlays)
+
+	inheritKeyed := func(own, inherited map[string]PathMapping) map[string]PathMapping {
+		for key, mapping := range inherited {
+			if _, ok := own[key]; ok {
+				continue
+			}
+			if own == nil {
+				own = make(map[string]PathMapping)
+			}
+			own[key] = rebase(mapping)
+		}
+		return own
+	}
+	manifest.PathMappings.Fonts = inheritKeyed(manifest.PathMappings.Fonts, base.PathMappings.Fonts)
+	manifest.PathMappings.GameArt = inheritKeyed(manifest.PathMappings.GameArt, base.PathMappings.GameArt)
+	manifest.PathMappings.Settings = inheritKeyed(manifest.PathMappings.Settings, base.PathMappings.Settings)
+
+	// Settings the variant doesn't set itself come from the base
+	if !manifest.Content.Settings.AccentsIncluded && base.Content.Settings.AccentsIncluded {
+		manifest.Content.Settings.AccentsIncluded = true
+		manifest.AccentColors = base.AccentColors
+	}
+	if !manifest.Content.Settings.LEDsIncluded && base.Content.Settings.LEDsIncluded {
+		manifest.Content.Settings.LEDsIncluded = true
+		manifest.LEDSettings = base.LEDSettings
+	}
+	if manifest.Content.Settings.ListScrimOpacity == 0 {
+		manifest.Content.Settings.ListScrimOpacity = base.Content.Settings.ListScrimOpacity
+	}
+}
@@ -0,0 +1,143 @@
+// src/internal/themes/folder_wallpapers.go
+// Wallpapers for any folder with a .media directory, beyond systems and collections
+
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// folderWallpapersDir mirrors the SD card layout inside a package, e.g.
+// FolderWallpapers/Tools/tg5040/Emulators/bg.png for Tools/tg5040/Emulators/.media/bg.png
+const folderWallpapersDir = "FolderWallpapers"
+
+// folderWallpaperFiles are the wallpapers a folder's .media can hold and their wallpaper type
+var folderWallpaperFiles = map[string]string{
+	"bg.png":     "Folder",
+	"bglist.png": "FolderList",
+}
+
+// exportFolderWallpapers copies the wallpapers of every discovered media folder into
+// the package under dir and returns their mappings
+func exportFolderWallpapers(packagePath, dir string, systemPaths *system.SystemPaths, logger *Logger) []PathMapping {
+	var mappings []PathMapping
+	for _, folder := range systemPaths.MediaFolders {
+		for fileName, wallpaperType := range folderWallpaperFiles {
+			srcPath := filepath.Join(folder.MediaPath, fileName)
+			if _, err := os.Stat(srcPath); err != nil {
+				continue
+			}
+
+			packageFile := filepath.ToSlash(filepath.Join(dir, folderWallpapersDir, folder.Name, fileName))
+			if err := CopyFile(srcPath, filepath.Join(packagePath, filepath.FromSlash(packageFile))); err != nil {
+				logger.DebugFn("Warning: Could not copy %s wallpaper for %s: %v", fileName, folder.Name, err)
+				continue
+			}
+
+			mappings = append(mappings, PathMapping{
+				ThemePath:  packageFile,
+				SystemPath: srcPath,
+				Metadata: map[string]string{
+					"FolderPath":    folder.Name,
+					"WallpaperType": wallpaperType,
+				},
+			})
+			logger.DebugFn("Exported %s wallpaper for folder %s", fileName, folder.Name)
+		}
+	}
+	return mappings
+}
+
+// scanFolderWallpapers maps the folder wallpapers in a package under dir to the matching folders
+// on the SD card. Folders that don't exist on this device are skipped rather than created, so
+// a theme never adds an empty category. Theme paths in skip are already mapped.
+func scanFolderWallpapers(packagePath, dir string, skip map[string]bool, systemPaths *system.SystemPaths, logger *Logger) []PathMapping {
+	root := filepath.Join(packagePath, filepath.FromSlash(dir), folderWallpapersDir)
+	if _, err := scanStat(root); err != nil {
+		return nil
+	}
+
+	var mappings []PathMapping
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		wallpaperType, ok := folderWallpaperFiles[info.Name()]
+		if !ok {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil || rel == "." {
+			return nil
+		}
+		folderName := filepath.ToSlash(rel)
+
+		packageFile := filepath.ToSlash(filepath.Join(dir, folderWallpapersDir, rel, info.Name()))
+		if skip[packageFile] {
+			return nil
+		}
+
+		folderPath, err := insidePackage(systemPaths.Root, folderName)
+		if err != nil {
+			logger.DebugFn("Warning: Skipping folder wallpaper: %v", err)
+			return nil
+		}
+		if _, err := os.Stat(folderPath); err != nil {
+			logger.DebugFn("Folder %s not found, skipping its wallpaper", folderName)
+			return nil
+		}
+
+		mappings = append(mappings, PathMapping{
+			ThemePath:  packageFile,
+			SystemPath: filepath.Join(folderPath, ".media", info.Name()),
+			Metadata: map[string]string{
+				"FolderPath":    folderName,
+				"WallpaperType": wallpaperType,
+			},
+		})
+		logger.DebugFn("Added mapping for folder wallpaper: %s", packageFile)
+		return nil
+	})
+	return mappings
+}
+
+// isFolderMappingMissing reports whether a folder wallpaper targets a folder this device doesn't
+// have, e.g. a custom category that only existed on the exporter's device
+func isFolderMappingMissing(mapping PathMapping) bool {
+	if mapping.Metadata == nil || mapping.Metadata["FolderPath"] == "" {
+		return false
+	}
+
+	folderPath := filepath.Dir(filepath.Dir(mapping.SystemPath))
+	_, err := os.Stat(folderPath)
+	return err != nil
+}
+
+// cleanupFolderWallpapers removes the wallpapers Theme Manager wrote to discovered media folders
+func cleanupFolderWallpapers(systemPaths *system.SystemPaths, logger *Logger) {
+	for _, folder := range systemPaths.MediaFolders {
+		for fileName := range folderWallpaperFiles {
+			wallpaper := filepath.Join(folder.MediaPath, fileName)
+			if err := removeThemeFile(wallpaper); err != nil && !os.IsNotExist(err) {
+				logger.DebugFn("Warning: Could not remove %s wallpaper: %v", folder.Name, err)
+			} else if err == nil {
+				logger.DebugFn("Removed %s wallpaper: %s", folder.Name, wallpaper)
+			}
+		}
+	}
+}
+
+// folderWallpaperNames lists the folder wallpapers in a set of mappings for a component manifest
+func folderWallpaperNames(mappings []PathMapping) []string {
+	var names []string
+	for _, mapping := range mappings {
+		names = append(names, strings.TrimPrefix(mapping.ThemePath, folderWallpapersDir+"/"))
+	}
+	return names
+}
@@ -0,0 +1,224 @@
+// src/internal/themes/font_fallback.go
+// Fallback fonts: extra font files a font pack tries, in order, for characters its main fonts lack
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// fontFallbackDir is the folder of a .font pack holding its fallback fonts
+const fontFallbackDir = "Fallbacks"
+
+// fontFallbackPrefix marks the fallback fonts Theme Manager put in the firmware's folder
+const fontFallbackPrefix = "theme-manager-"
+
+// fontFallbackCandidates are where firmware reads a chain of fallback fonts, relative to the
+// SD card root. NextUI reads a single font per style at the time of writing, so a location
+// only counts once the firmware ships the folder itself. Until then, each style gets the
+// first font of the chain that can draw the names on the device.
+var fontFallbackCandidates = []string{
+	".system/res/fallback",
+}
+
+// FontFallbackPath returns the folder this device reads fallback fonts from, and false when
+// the firmware supports one font per style only
+func FontFallbackPath(systemPaths *system.SystemPaths) (string, bool) {
+	for _, candidate := range fontFallbackCandidates {
+		path := filepath.Join(systemPaths.Root, filepath.FromSlash(candidate))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// isFontFile reports whether a file name is a font the firmware can load
+func isFontFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ttf", ".otf", ".ttc":
+		return true
+	}
+	return false
+}
+
+// scanFontFallbacks returns the fallback fonts of a pack in order: those already listed in
+// the manifest first, as the author ordered them, then any new files by name
+func scanFontFallbacks(componentPath string, listed []string) []string {
+	entries, err := scanReadDir(filepath.Join(componentPath, fontFallbackDir))
+	if err != nil {
+		return nil
+	}
+
+	present := make(map[string]bool)
+	var added []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !isFontFile(entry.Name()) {
+			continue
+		}
+		present[entry.Name()] = true
+		added = append(added, entry.Name())
+	}
+
+	var fallbacks []string
+	seen := make(map[string]bool)
+	for _, name := range listed {
+		if present[name] && !seen[name] {
+			fallbacks = append(fallbacks, name)
+			seen[name] = true
+		}
+	}
+	sort.Strings(added)
+	for _, name := range added {
+		if !seen[name] {
+			fallbacks = append(fallbacks, name)
+		}
+	}
+	return fallbacks
+}
+
+// deviceNameRunes returns the non-ASCII characters used in ROM, folder, collection and tool
+// names on the device, the text a menu font has to draw
+func deviceNameRunes(systemPaths *system.SystemPaths) []rune {
+	seen := make(map[rune]bool)
+	add := func(name string) {
+		for _, r := range name {
+			if r > 0x7F {
+				seen[r] = true
+			}
+		}
+	}
+
+	addDir := func(dir string) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), ".") {
+				add(entry.Name())
+			}
+		}
+	}
+
+	addDir(systemPaths.Roms)
+	for _, sys := range systemPaths.Systems {
+		addDir(sys.Path)
+	}
+	addDir(filepath.Join(systemPaths.Root, "Collections"))
+	addDir(systemPaths.Tools)
+
+	runes := make([]rune, 0, len(seen))
+	for r := range seen {
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+// pickFontForDevice returns the first font of a chain that has every character in needed, or
+// the one that has the most when none has them all. Fonts that can't be read are skipped.
+func pickFontForDevice(chain []string, needed []rune, logger *Logger) string {
+	if len(needed) == 0 || len(chain) == 0 {
+		return firstOf(chain)
+	}
+
+	best, bestCount := "", -1
+	for _, path := range chain {
+		coverage, err := readFontCoverage(path)
+		if err != nil {
+			logger.DebugFn("Warning: Could not read characters of %s: %v", filepath.Base(path), err)
+			continue
+		}
+
+		count := 0
+		for _, r := range needed {
+			if coverage.covers(r) {
+				count++
+			}
+		}
+		if count == len(needed) {
+			return path
+		}
+		if count > bestCount {
+			best, bestCount = path, count
+		}
+	}
+
+	if best == "" {
+		return firstOf(chain)
+	}
+	logger.DebugFn("No font draws all %d characters in the device's names, %s draws %d", len(needed), filepath.Base(best), bestCount)
+	return best
+}
+
+// firstOf returns the first entry of a list, or ""
+func firstOf(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return list[0]
+}
+
+// fontSourceForDevice returns the file to apply for one of a pack's font styles. On firmware
+// without fallback support that is the first font of the style's chain (its own font, then the
+// pack's fallbacks) that can draw every name on the device.
+func fontSourceForDevice(componentPath, srcPath string, manifest *FontManifest, systemPaths *system.SystemPaths, logger *Logger) string {
+	if len(manifest.Fallbacks) == 0 || systemPaths == nil {
+		return srcPath
+	}
+	if _, ok := FontFallbackPath(systemPaths); ok {
+		return srcPath
+	}
+
+	chain := []string{srcPath}
+	for _, name := range manifest.Fallbacks {
+		chain = append(chain, filepath.Join(componentPath, fontFallbackDir, name))
+	}
+
+	picked := pickFontForDevice(chain, deviceNameRunes(systemPaths), logger)
+	if picked != srcPath {
+		logger.DebugFn("Using fallback font %s in place of %s, it draws the names on this device", filepath.Base(picked), filepath.Base(srcPath))
+	}
+	return picked
+}
+
+// applyFontFallbacks copies a pack's fallback fonts, numbered in order, to the firmware's
+// fallback folder where there is one, replacing those of the previous pack
+func applyFontFallbacks(componentPath string, manifest *FontManifest, systemPaths *system.SystemPaths, logger *Logger) {
+	if systemPaths == nil {
+		return
+	}
+	dir, ok := FontFallbackPath(systemPaths)
+	if !ok {
+		return
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logger.DebugFn("Warning: Could not read %s: %v", dir, err)
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), fontFallbackPrefix) {
+			if err := removeThemeFile(filepath.Join(dir, entry.Name())); err != nil {
+				logger.DebugFn("Warning: Could not remove fallback font %s: %v", entry.Name(), err)
+			}
+		}
+	}
+
+	for i, name := range manifest.Fallbacks {
+		srcPath := filepath.Join(componentPath, fontFallbackDir, name)
+		dstPath := filepath.Join(dir, fmt.Sprintf("%s%02d-%s", fontFallbackPrefix, i+1, name))
+		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+			logger.DebugFn("Warning: Failed to copy fallback font %s: %v", name, err)
+			continue
+		}
+		logger.DebugFn("Applied fallback font %d: %s", i+1, name)
+	}
+}
@@ -0,0 +1,213 @@
+// src/internal/themes/font_sfnt.go
+// Minimal reader for TrueType and OpenType font files: the table directory and character map
+
+package themes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// sfntTable is an entry of a font's table directory
+type sfntTable struct {
+	Offset uint32
+	Length uint32
+}
+
+// sfntFont is a font file read into memory with its table directory
+type sfntFont struct {
+	data   []byte
+	tables map[string]sfntTable
+}
+
+// runeRange is an inclusive range of characters
+type runeRange struct {
+	First, Last rune
+}
+
+// fontCoverage is the sorted list of character ranges a font has glyphs for
+type fontCoverage []runeRange
+
+// covers reports whether a font has a glyph for r
+func (c fontCoverage) covers(r rune) bool {
+	i := sort.Search(len(c), func(i int) bool { return c[i].Last >= r })
+	return i < len(c) && c[i].First <= r
+}
+
+// readSfnt reads a .ttf or .otf file, or the first font of a .ttc collection
+func readSfnt(path string) (*sfntFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSfnt(data)
+}
+
+// parseSfnt reads the table directory of font data
+func parseSfnt(data []byte) (*sfntFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("not a font file")
+	}
+
+	offset := uint32(0)
+	if string(data[:4]) == "ttcf" {
+		if len(data) < 16 {
+			return nil, fmt.Errorf("truncated font collection")
+		}
+		offset = binary.BigEndian.Uint32(data[12:16])
+	}
+	if int(offset)+12 > len(data) {
+		return nil, fmt.Errorf("truncated font file")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	dir := data[offset+12:]
+	if len(dir) < numTables*16 {
+		return nil, fmt.Errorf("truncated font table directory")
+	}
+
+	font := &sfntFont{data: data, tables: make(map[string]sfntTable)}
+	for i := 0; i < numTables; i++ {
+		record := dir[i*16:]
+		table := sfntTable{
+			Offset: binary.BigEndian.Uint32(record[8:]),
+			Length: binary.BigEndian.Uint32(record[12:]),
+		}
+		if uint64(table.Offset)+uint64(table.Length) > uint64(len(data)) {
+			return nil, fmt.Errorf("font table %s runs past the end of the file", record[:4])
+		}
+		font.tables[string(record[:4])] = table
+	}
+	return font, nil
+}
+
+// table returns the bytes of a table, or nil when the font doesn't have it
+func (f *sfntFont) table(tag string) []byte {
+	t, ok := f.tables[tag]
+	if !ok {
+		return nil
+	}
+	return f.data[t.Offset : t.Offset+t.Length]
+}
+
+// unicodeCmap returns the font's best Unicode character map subtable and its format,
+// preferring full Unicode (format 12) subtables over BMP-only ones (format 4)
+func (f *sfntFont) unicodeCmap() ([]byte, uint16, error) {
+	cmap := f.table("cmap")
+	if len(cmap) < 4 {
+		return nil, 0, fmt.Errorf("font has no character map")
+	}
+
+	best, bestFormat := -1, uint16(0)
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+i*8+8 <= len(cmap); i++ {
+		record := cmap[4+i*8:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		if platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+
+		format := binary.BigEndian.Uint16(cmap[offset:])
+		if (format == 12 || format == 4) && format > bestFormat {
+			best, bestFormat = offset, format
+		}
+	}
+	if best < 0 {
+		return nil, 0, fmt.Errorf("font has no Unicode character map")
+	}
+
+	sub := cmap[best:]
+	switch bestFormat {
+	case 4:
+		if len(sub) < 14 || len(sub) < 16+int(binary.BigEndian.Uint16(sub[6:]))*4 {
+			return nil, 0, fmt.Errorf("truncated character map")
+		}
+	case 12:
+		if len(sub) < 16 || int64(len(sub)) < 16+int64(binary.BigEndian.Uint32(sub[12:]))*12 {
+			return nil, 0, fmt.Errorf("truncated character map")
+		}
+	}
+	return sub, bestFormat, nil
+}
+
+// cmapSegments calls fn for every range of characters in a Unicode cmap subtable, with a
+// function returning the glyph of a character in the range (0 when it has none)
+func cmapSegments(sub []byte, format uint16, fn func(r runeRange, glyph func(rune) uint16)) {
+	switch format {
+	case 4:
+		segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+		ends := 14
+		starts := 16 + segCount*2
+		deltas := starts + segCount*2
+		rangeOffsets := deltas + segCount*2
+		for i := 0; i < segCount; i++ {
+			first := rune(binary.BigEndian.Uint16(sub[starts+i*2:]))
+			last := rune(binary.BigEndian.Uint16(sub[ends+i*2:]))
+			// The last segment only marks the end of the table
+			if first == 0xFFFF {
+				continue
+			}
+
+			delta := binary.BigEndian.Uint16(sub[deltas+i*2:])
+			rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+i*2:]))
+			fn(runeRange{first, last}, func(c rune) uint16 {
+				if rangeOffset == 0 {
+					return uint16(c) + delta
+				}
+				at := rangeOffsets + i*2 + rangeOffset + int(c-first)*2
+				if at+2 > len(sub) {
+					return 0
+				}
+				g := binary.BigEndian.Uint16(sub[at:])
+				if g == 0 {
+					return 0
+				}
+				return g + delta
+			})
+		}
+	case 12:
+		groups := int(binary.BigEndian.Uint32(sub[12:]))
+		for i := 0; i < groups; i++ {
+			group := sub[16+i*12:]
+			first := rune(binary.BigEndian.Uint32(group))
+			startGlyph := binary.BigEndian.Uint32(group[8:])
+			fn(runeRange{first, rune(binary.BigEndian.Uint32(group[4:]))}, func(c rune) uint16 {
+				return uint16(startGlyph + uint32(c-first))
+			})
+		}
+	}
+}
+
+// coverage reads the characters a font maps to glyphs from its Unicode cmap subtable
+func (f *sfntFont) coverage() (fontCoverage, error) {
+	sub, format, err := f.unicodeCmap()
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges fontCoverage
+	cmapSegments(sub, format, func(r runeRange, _ func(rune) uint16) {
+		ranges = append(ranges, r)
+	})
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].First < ranges[j].First
+	})
+	return ranges, nil
+}
+
+// readFontCoverage returns the characters a font file has glyphs for
+func readFontCoverage(path string) (fontCoverage, error) {
+	font, err := readSfnt(path)
+	if err != nil {
+		return nil, err
+	}
+	return font.coverage()
+}
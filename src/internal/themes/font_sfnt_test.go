@@ -0,0 +1,224 @@
+package themes
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"nextui-themes/internal/testfont"
+)
+
+// sampleCoverage is what the sample font maps. A and B share a glyph, so they are separate
+// ranges of a character map that maps runs of characters to runs of glyphs.
+var sampleCoverage = fontCoverage{{' ', ' '}, {'A', 'A'}, {'B', 'B'}, {'O', 'O'}, {'Á', 'Á'}, {'é', 'é'}, {'Ж', 'Ж'}, {0x1F3AE, 0x1F3AE}}
+
+// sampleBMPCoverage is the part of sampleCoverage a format 4 character map can hold
+var sampleBMPCoverage = sampleCoverage[:len(sampleCoverage)-1]
+
+// fontCollection wraps a font in a .ttc collection, after a second font that isn't read
+func fontCollection(font []byte) []byte {
+	header := []byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x02")
+	header = binary.BigEndian.AppendUint32(header, 20)
+	header = binary.BigEndian.AppendUint32(header, 0)
+
+	// Table offsets are from the start of the file, so move the font's along
+	data := append(header, font...)
+	numTables := int(binary.BigEndian.Uint16(font[4:]))
+	for i := 0; i < numTables; i++ {
+		record := data[20+12+i*16:]
+		binary.BigEndian.PutUint32(record[8:], binary.BigEndian.Uint32(record[8:])+20)
+	}
+	return data
+}
+
+func TestFontCoverage(t *testing.T) {
+	tests := []struct {
+		name string
+		font func(*testfont.Font)
+		want fontCoverage
+	}{
+		{"format 12", func(*testfont.Font) {}, sampleCoverage},
+		{"format 4 deltas", func(f *testfont.Font) { f.Format12 = false }, sampleBMPCoverage},
+		{"format 4 range offsets", func(f *testfont.Font) { f.Format12, f.RangeOffsets = false, true }, fontCoverage{{' ', ' '}, {'A', 'B'}, {'O', 'O'}, {'Á', 'Á'}, {'é', 'é'}, {'Ж', 'Ж'}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := testfont.Sample()
+			tt.font(sample)
+
+			font, err := parseSfnt(sample.Bytes())
+			if err != nil {
+				t.Fatalf("parseSfnt: %v", err)
+			}
+			got, err := font.coverage()
+			if err != nil {
+				t.Fatalf("coverage: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("coverage = %v, want %v", got, tt.want)
+			}
+
+			// Every character maps to the glyph it was given
+			sub, format, _ := font.unicodeCmap()
+			cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+				for c := r.First; c <= r.Last; c++ {
+					if g := glyph(c); g != sample.Chars[c] {
+						t.Errorf("glyph of %U = %d, want %d", c, g, sample.Chars[c])
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestFontCoverageCovers(t *testing.T) {
+	for r, want := range map[rune]bool{
+		' ': true, 'A': true, 'B': true, 'C': false, 'O': true, 'é': true, 'è': false,
+		'Ж': true, 0x1F3AE: true, 0x1F3AF: false, 0: false, 0x10FFFF: false,
+	} {
+		if got := sampleCoverage.covers(r); got != want {
+			t.Errorf("covers(%U) = %v, want %v", r, got, want)
+		}
+	}
+	if (fontCoverage{}).covers('A') {
+		t.Error("an empty coverage covers A")
+	}
+}
+
+func TestReadFontCoverage(t *testing.T) {
+	dir := t.TempDir()
+	single := filepath.Join(dir, "font.ttf")
+	collection := filepath.Join(dir, "fonts.ttc")
+	data := testfont.Sample().Bytes()
+	if err := os.WriteFile(single, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(collection, fontCollection(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{single, collection} {
+		coverage, err := readFontCoverage(path)
+		if err != nil {
+			t.Fatalf("readFontCoverage(%s): %v", filepath.Base(path), err)
+		}
+		if !reflect.DeepEqual(coverage, sampleCoverage) {
+			t.Errorf("readFontCoverage(%s) = %v, want %v", filepath.Base(path), coverage, sampleCoverage)
+		}
+	}
+
+	if _, err := readFontCoverage(filepath.Join(dir, "missing.ttf")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestParseSfntMalformed(t *testing.T) {
+	valid := testfont.Sample().Bytes()
+	font, err := parseSfnt(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmapAt := font.tables["cmap"].Offset
+
+	// withCmap returns the sample font with its cmap table changed in place
+	withCmap := func(change func(cmap []byte)) []byte {
+		data := append([]byte(nil), valid...)
+		change(data[cmapAt:])
+		return data
+	}
+	format4At := int(binary.BigEndian.Uint32(valid[cmapAt+8:]))
+	format12At := int(binary.BigEndian.Uint32(valid[cmapAt+24:]))
+
+	tests := []struct {
+		name     string
+		data     []byte
+		parseErr bool
+		want     fontCoverage // nil when reading the coverage fails
+	}{
+		{"empty", nil, true, nil},
+		{"too short", valid[:11], true, nil},
+		{"truncated directory", valid[:40], true, nil},
+		{"truncated collection", []byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x01"), true, nil},
+		{"collection offset past the end", append([]byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x01\xff\xff\xff\xf0"), valid...), true, nil},
+		{"table past the end", valid[:len(valid)-8], true, nil},
+		{"no cmap", testfont.Assemble(map[string][]byte{"head": make([]byte, 54)}), false, nil},
+		{"no Unicode cmap", withCmap(func(cmap []byte) {
+			for i := 0; i < 3; i++ {
+				binary.BigEndian.PutUint16(cmap[4+i*8:], 1)
+			}
+		}), false, nil},
+		{"subtable offsets past the end", withCmap(func(cmap []byte) {
+			for i := 0; i < 3; i++ {
+				binary.BigEndian.PutUint32(cmap[8+i*8:], 0xFFFFFFF0)
+			}
+		}), false, nil},
+		{"too many groups", withCmap(func(cmap []byte) {
+			binary.BigEndian.PutUint32(cmap[format12At+12:], 0xFFFFFFFF)
+		}), false, nil},
+		{"too many segments", withCmap(func(cmap []byte) {
+			// Format 12 is unusable, so the format 4 subtable is read
+			binary.BigEndian.PutUint16(cmap[format12At:], 13)
+			binary.BigEndian.PutUint16(cmap[format4At+6:], 0xFFFE)
+		}), false, nil},
+		{"falls back to format 4", withCmap(func(cmap []byte) {
+			binary.BigEndian.PutUint16(cmap[format12At:], 13)
+		}), false, sampleBMPCoverage},
+		{"format 12 listed twice", withCmap(func(cmap []byte) {
+			binary.BigEndian.PutUint32(cmap[8:], uint32(format12At))
+		}), false, sampleCoverage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := parseSfnt(tt.data)
+			if tt.parseErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSfnt: %v", err)
+			}
+
+			coverage, err := font.coverage()
+			switch {
+			case tt.want == nil && err == nil:
+				t.Errorf("expected an error, got %v", coverage)
+			case tt.want != nil && err != nil:
+				t.Errorf("coverage: %v", err)
+			case tt.want != nil && !reflect.DeepEqual(coverage, tt.want):
+				t.Errorf("coverage = %v, want %v", coverage, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzFontCoverage(f *testing.F) {
+	sample := testfont.Sample()
+	f.Add(sample.Bytes())
+	f.Add(fontCollection(sample.Bytes()))
+	sample.Format12, sample.RangeOffsets = false, true
+	f.Add(sample.Bytes())
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		font, err := parseSfnt(data)
+		if err != nil {
+			return
+		}
+		if _, err := font.coverage(); err != nil {
+			return
+		}
+
+		// Every character the coverage lists can be looked up
+		sub, format, _ := font.unicodeCmap()
+		cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+			for c := r.First; c <= r.Last && c-r.First < 256; c++ {
+				glyph(c)
+			}
+		})
+	})
+}
@@ -0,0 +1,365 @@
+// src/internal/themes/font_subset.go
+// Font subsetting: exported fonts keep only the glyphs of the character ranges the author picked
+
+package themes
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"nextui-themes/internal/logging"
+)
+
+// FontRange is a group of Unicode blocks that can be kept when subsetting fonts
+type FontRange struct {
+	Key    string // Stored in config.json and manifests
+	Label  string
+	Ranges []runeRange
+}
+
+// FontSubsetRanges are the ranges an author can keep. Printable ASCII is always kept, since
+// Theme Manager and NextUI draw their own menus with it.
+var FontSubsetRanges = []FontRange{
+	{"latin", "Latin", []runeRange{{0x00A0, 0x024F}, {0x1E00, 0x1EFF}, {0x2000, 0x206F}, {0x20A0, 0x20CF}}},
+	{"greek", "Greek", []runeRange{{0x0370, 0x03FF}, {0x1F00, 0x1FFF}}},
+	{"cyrillic", "Cyrillic", []runeRange{{0x0400, 0x052F}}},
+	{"symbols", "Symbols", []runeRange{{0x2100, 0x27BF}, {0x2B00, 0x2BFF}}},
+	{"kana", "Japanese Kana", []runeRange{{0x3000, 0x30FF}, {0x31F0, 0x31FF}, {0xFF00, 0xFFEF}}},
+	{"cjk", "CJK Ideographs", []runeRange{{0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xF900, 0xFAFF}}},
+	{"hangul", "Korean Hangul", []runeRange{{0x1100, 0x11FF}, {0x3130, 0x318F}, {0xAC00, 0xD7AF}}},
+}
+
+// fontAlwaysKept is printable ASCII
+var fontAlwaysKept = runeRange{0x0020, 0x007E}
+
+// errFontNotSubsettable is returned for fonts without TrueType outlines, such as CFF-based
+// .otf files, which are exported whole
+var errFontNotSubsettable = errors.New("font has no TrueType outlines")
+
+// GetFontSubset returns the keys of the ranges kept when exporting fonts, empty when fonts
+// are exported whole
+func GetFontSubset() []string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load font subset setting: %v", err)
+		return nil
+	}
+	return config.FontSubset
+}
+
+// ToggleFontSubsetRange adds a range to the font subset if it isn't in it yet, otherwise
+// removes it
+func ToggleFontSubsetRange(key string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	for i, kept := range config.FontSubset {
+		if kept == key {
+			config.FontSubset = append(config.FontSubset[:i], config.FontSubset[i+1:]...)
+			return SaveConfig(config)
+		}
+	}
+
+	// Keep the list in the order ranges are offered
+	keys := append(config.FontSubset, key)
+	config.FontSubset = nil
+	for _, r := range FontSubsetRanges {
+		for _, k := range keys {
+			if k == r.Key {
+				config.FontSubset = append(config.FontSubset, k)
+			}
+		}
+	}
+	return SaveConfig(config)
+}
+
+// fontSubsetRanges returns the character ranges kept for a list of range keys
+func fontSubsetRanges(keys []string) fontCoverage {
+	ranges := fontCoverage{fontAlwaysKept}
+	for _, r := range FontSubsetRanges {
+		for _, key := range keys {
+			if key == r.Key {
+				ranges = append(ranges, r.Ranges...)
+			}
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].First < ranges[j].First
+	})
+	return ranges
+}
+
+// exportFontFile copies a font into a package, subset to the author's ranges when they picked
+// any. It returns the range keys the exported font was subset to, nil when it was copied whole.
+func exportFontFile(srcPath, dstPath string, logger *Logger) ([]string, error) {
+	keys := GetFontSubset()
+	if len(keys) == 0 {
+		return nil, CopyFile(srcPath, dstPath)
+	}
+
+	before, after, err := subsetFontFile(srcPath, dstPath, fontSubsetRanges(keys))
+	if err != nil {
+		logger.DebugFn("Warning: Could not subset %s, exporting it whole: %v", srcPath, err)
+		return nil, CopyFile(srcPath, dstPath)
+	}
+
+	logger.DebugFn("Subset font %s to %v: %s -> %s", srcPath, keys, FormatSize(before), FormatSize(after))
+	return keys, nil
+}
+
+// subsetFontFile writes a copy of a TrueType font that only has outlines for the characters in
+// keep, and returns the sizes before and after. Glyph numbers stay the same, so metrics, kerning
+// and layout tables remain valid; the outlines of the other glyphs are emptied and the
+// character map only lists kept characters, so fallback fonts are used for the rest.
+func subsetFontFile(srcPath, dstPath string, keep fontCoverage) (int64, int64, error) {
+	font, err := readSfnt(srcPath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	glyf, loca, head, maxp := font.table("glyf"), font.table("loca"), font.table("head"), font.table("maxp")
+	if glyf == nil || loca == nil {
+		return 0, 0, errFontNotSubsettable
+	}
+	if len(head) < 54 || len(maxp) < 6 {
+		return 0, 0, fmt.Errorf("font has no valid head or maxp table")
+	}
+
+	numGlyphs := int(binary.BigEndian.Uint16(maxp[4:]))
+	longLoca := binary.BigEndian.Uint16(head[50:]) == 1
+	offsets := make([]uint32, numGlyphs+1)
+	for i := range offsets {
+		if longLoca {
+			if len(loca) < (i+1)*4 {
+				return 0, 0, fmt.Errorf("truncated loca table")
+			}
+			offsets[i] = binary.BigEndian.Uint32(loca[i*4:])
+		} else {
+			if len(loca) < (i+1)*2 {
+				return 0, 0, fmt.Errorf("truncated loca table")
+			}
+			offsets[i] = uint32(binary.BigEndian.Uint16(loca[i*2:])) * 2
+		}
+	}
+	glyphData := func(g int) []byte {
+		if g >= numGlyphs || offsets[g] > offsets[g+1] || int(offsets[g+1]) > len(glyf) {
+			return nil
+		}
+		return glyf[offsets[g]:offsets[g+1]]
+	}
+
+	sub, format, err := font.unicodeCmap()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Kept characters and their glyphs, with .notdef always kept
+	type mapping struct {
+		char  rune
+		glyph uint16
+	}
+	var chars []mapping
+	kept := map[uint16]bool{0: true}
+	cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+		for _, k := range keep {
+			first, last := max(r.First, k.First), min(r.Last, k.Last)
+			for c := first; c <= last; c++ {
+				if g := glyph(c); g != 0 && int(g) < numGlyphs {
+					chars = append(chars, mapping{c, g})
+					kept[g] = true
+				}
+			}
+		}
+	})
+	sort.Slice(chars, func(i, j int) bool {
+		return chars[i].char < chars[j].char
+	})
+
+	// Composite glyphs are drawn from other glyphs, which have to stay too
+	queue := make([]uint16, 0, len(kept))
+	for g := range kept {
+		queue = append(queue, g)
+	}
+	for len(queue) > 0 {
+		g := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, component := range compositeComponents(glyphData(int(g))) {
+			if !kept[component] && int(component) < numGlyphs {
+				kept[component] = true
+				queue = append(queue, component)
+			}
+		}
+	}
+
+	// Outlines of the kept glyphs only, with a long-format loca pointing into them
+	var newGlyf []byte
+	newLoca := make([]byte, (numGlyphs+1)*4)
+	for g := 0; g < numGlyphs; g++ {
+		binary.BigEndian.PutUint32(newLoca[g*4:], uint32(len(newGlyf)))
+		if kept[uint16(g)] {
+			newGlyf = append(newGlyf, glyphData(g)...)
+			for len(newGlyf)%4 != 0 {
+				newGlyf = append(newGlyf, 0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[numGlyphs*4:], uint32(len(newGlyf)))
+
+	newHead := append([]byte(nil), head...)
+	binary.BigEndian.PutUint32(newHead[8:], 0) // checkSumAdjustment, set once the file is assembled
+	binary.BigEndian.PutUint16(newHead[50:], 1)
+
+	// A single Windows full Unicode (3, 10) format 12 subtable, which FreeType picks for Unicode
+	var groups [][3]uint32
+	for _, m := range chars {
+		if n := len(groups); n > 0 && uint32(m.char) <= groups[n-1][1] {
+			continue
+		}
+		if n := len(groups); n > 0 && groups[n-1][1]+1 == uint32(m.char) && groups[n-1][2]+(groups[n-1][1]-groups[n-1][0])+1 == uint32(m.glyph) {
+			groups[n-1][1] = uint32(m.char)
+			continue
+		}
+		groups = append(groups, [3]uint32{uint32(m.char), uint32(m.char), uint32(m.glyph)})
+	}
+	newCmap := make([]byte, 12+16+len(groups)*12)
+	binary.BigEndian.PutUint16(newCmap[2:], 1)
+	binary.BigEndian.PutUint16(newCmap[4:], 3)
+	binary.BigEndian.PutUint16(newCmap[6:], 10)
+	binary.BigEndian.PutUint32(newCmap[8:], 12)
+	subtable := newCmap[12:]
+	binary.BigEndian.PutUint16(subtable, 12)
+	binary.BigEndian.PutUint32(subtable[4:], uint32(len(subtable)))
+	binary.BigEndian.PutUint32(subtable[12:], uint32(len(groups)))
+	for i, group := range groups {
+		binary.BigEndian.PutUint32(subtable[16+i*12:], group[0])
+		binary.BigEndian.PutUint32(subtable[16+i*12+4:], group[1])
+		binary.BigEndian.PutUint32(subtable[16+i*12+8:], group[2])
+	}
+
+	tables := make(map[string][]byte)
+	for tag := range font.tables {
+		// A digital signature no longer matches a changed font
+		if tag != "DSIG" {
+			tables[tag] = font.table(tag)
+		}
+	}
+	tables["glyf"] = newGlyf
+	tables["loca"] = newLoca
+	tables["head"] = newHead
+	tables["cmap"] = newCmap
+
+	data := assembleSfnt(tables)
+	if err := os.WriteFile(dstPath, data, 0644); err != nil {
+		return 0, 0, fmt.Errorf("error writing subset font: %w", err)
+	}
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	return info.Size(), int64(len(data)), nil
+}
+
+// compositeComponents returns the glyphs a composite glyph is built from, or nil for a
+// simple glyph
+func compositeComponents(data []byte) []uint16 {
+	if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+		return nil
+	}
+
+	const (
+		argsAreWords   = 0x0001
+		haveScale      = 0x0008
+		moreComponents = 0x0020
+		haveXYScale    = 0x0040
+		haveTwoByTwo   = 0x0080
+	)
+
+	var components []uint16
+	for at := 10; at+4 <= len(data); {
+		flags := binary.BigEndian.Uint16(data[at:])
+		components = append(components, binary.BigEndian.Uint16(data[at+2:]))
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreComponents == 0 {
+			break
+		}
+	}
+	return components
+}
+
+// sfntChecksum sums a table as big-endian 32-bit words, zero padded
+func sfntChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
+
+// assembleSfnt writes a TrueType font file from its tables, with tables sorted by tag and
+// aligned to 4 bytes, and fills in the head table's checksum adjustment
+func assembleSfnt(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	numTables := len(tags)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= numTables {
+		entrySelector++
+	}
+	searchRange := (1 << entrySelector) * 16
+
+	header := make([]byte, 12+numTables*16)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(numTables))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(numTables*16-searchRange))
+
+	data := header
+	headOffset := -1
+	for i, tag := range tags {
+		table := tables[tag]
+		record := data[12+i*16:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], sfntChecksum(table))
+		binary.BigEndian.PutUint32(record[8:], uint32(len(data)))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(table)))
+		if tag == "head" {
+			headOffset = len(data)
+		}
+
+		data = append(data, table...)
+		for len(data)%4 != 0 {
+			data = append(data, 0)
+		}
+	}
+
+	if headOffset >= 0 {
+		binary.BigEndian.PutUint32(data[headOffset+8:], 0xB1B0AFBA-sfntChecksum(data))
+	}
+	return data
+}
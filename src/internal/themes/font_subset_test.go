@@ -0,0 +1,411 @@
+package themes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"nextui-themes/internal/testfont"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// NextUI's own fonts, subset too when the tests run on a device
+var deviceFonts = []string{
+	"/mnt/SDCARD/.system/res/font1.ttf",
+	"/mnt/SDCARD/.system/res/font2.ttf",
+}
+
+// describeFont lists what subsetting changes in a font: its tables, the characters it maps
+// and the size of each glyph's outline
+func describeFont(t *testing.T, data []byte) string {
+	t.Helper()
+
+	font, err := parseSfnt(data)
+	if err != nil {
+		t.Fatalf("parseSfnt: %v", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "tables:")
+	for _, tag := range sortedTags(font) {
+		fmt.Fprintf(&b, "  %s %d bytes, checksum %08x\n", tag, font.tables[tag].Length, sfntChecksum(font.table(tag)))
+	}
+
+	fmt.Fprintln(&b, "characters:")
+	sub, format, err := font.unicodeCmap()
+	if err != nil {
+		t.Fatalf("unicodeCmap: %v", err)
+	}
+	fmt.Fprintf(&b, "  format %d\n", format)
+	cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+		for c := r.First; c <= r.Last; c++ {
+			fmt.Fprintf(&b, "  %U %q -> glyph %d\n", c, c, glyph(c))
+		}
+	})
+
+	fmt.Fprintln(&b, "glyphs:")
+	for g, outline := range glyphOutlines(font) {
+		fmt.Fprintf(&b, "  %d: %d bytes\n", g, len(outline))
+	}
+	return b.String()
+}
+
+// checkSubset checks a subset font against the font it was made from: it re-parses, its
+// checksums are right, it maps exactly the kept characters it had, the outlines it keeps are
+// unchanged and the other tables are copied
+func checkSubset(t *testing.T, original, subset []byte, keep fontCoverage) {
+	t.Helper()
+
+	before, err := parseSfnt(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := parseSfnt(subset)
+	if err != nil {
+		t.Fatalf("subset font doesn't parse: %v", err)
+	}
+
+	tags := sortedTags(after)
+	for i, tag := range tags {
+		// The head table is summed with its checksum adjustment left at zero
+		data := append([]byte(nil), after.table(tag)...)
+		if tag == "head" {
+			binary.BigEndian.PutUint32(data[8:], 0)
+		}
+		if sum := binary.BigEndian.Uint32(subset[12+i*16+4:]); sum != sfntChecksum(data) {
+			t.Errorf("%s checksum in the directory is %08x, want %08x", tag, sum, sfntChecksum(data))
+		}
+		if table := after.tables[tag]; table.Offset%4 != 0 {
+			t.Errorf("%s table isn't aligned to 4 bytes", tag)
+		}
+	}
+	if sum := sfntChecksum(subset); sum != 0xB1B0AFBA {
+		t.Errorf("font checksum is %08x, want b1b0afba", sum)
+	}
+
+	for tag := range before.tables {
+		switch tag {
+		case "cmap", "glyf", "loca", "head":
+		case "DSIG":
+			if _, ok := after.tables[tag]; ok {
+				t.Error("the digital signature was kept")
+			}
+		default:
+			if !bytes.Equal(before.table(tag), after.table(tag)) {
+				t.Errorf("%s table changed", tag)
+			}
+		}
+	}
+
+	oldHead, newHead := before.table("head"), after.table("head")
+	if !bytes.Equal(oldHead[:8], newHead[:8]) || !bytes.Equal(oldHead[12:50], newHead[12:50]) {
+		t.Error("head table changed past its checksum and loca format")
+	}
+	if binary.BigEndian.Uint16(newHead[50:]) != 1 {
+		t.Error("subset font doesn't use a long loca table")
+	}
+
+	// Characters: those of the original in keep, mapped to the same glyphs
+	wantChars := make(map[rune]uint16)
+	sub, format, _ := before.unicodeCmap()
+	cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+		for c := r.First; c <= r.Last; c++ {
+			if keep.covers(c) && glyph(c) != 0 {
+				wantChars[c] = glyph(c)
+			}
+		}
+	})
+	gotChars := make(map[rune]uint16)
+	sub, format, err = after.unicodeCmap()
+	if err != nil {
+		t.Fatalf("subset font has no Unicode character map: %v", err)
+	}
+	cmapSegments(sub, format, func(r runeRange, glyph func(rune) uint16) {
+		for c := r.First; c <= r.Last; c++ {
+			gotChars[c] = glyph(c)
+		}
+	})
+	if !reflect.DeepEqual(gotChars, wantChars) {
+		t.Errorf("subset maps %v, want %v", gotChars, wantChars)
+	}
+
+	// Outlines: the kept glyphs and the parts of composites are unchanged, the rest are empty
+	oldGlyphs, newGlyphs := glyphOutlines(before), glyphOutlines(after)
+	if len(oldGlyphs) != len(newGlyphs) {
+		t.Fatalf("subset has %d glyphs, want %d", len(newGlyphs), len(oldGlyphs))
+	}
+	kept := map[uint16]bool{0: true}
+	for _, g := range wantChars {
+		kept[g] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for g := range kept {
+			for _, part := range compositeComponents(oldGlyphs[g]) {
+				if !kept[part] {
+					kept[part], changed = true, true
+				}
+			}
+		}
+	}
+	for g := range oldGlyphs {
+		want := oldGlyphs[g]
+		if !kept[uint16(g)] {
+			want = nil
+		}
+		if got := newGlyphs[g]; !bytes.Equal(bytes.TrimRight(got, "\x00"), bytes.TrimRight(want, "\x00")) {
+			t.Errorf("glyph %d outline is %d bytes, want %d", g, len(got), len(want))
+		}
+	}
+}
+
+// sortedTags returns a font's table tags in directory order
+func sortedTags(font *sfntFont) []string {
+	tags := make([]string, 0, len(font.tables))
+	for tag := range font.tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// glyphOutlines returns the glyf data of every glyph of a font
+func glyphOutlines(font *sfntFont) [][]byte {
+	head, loca, glyf := font.table("head"), font.table("loca"), font.table("glyf")
+	numGlyphs := int(binary.BigEndian.Uint16(font.table("maxp")[4:]))
+	glyphs := make([][]byte, numGlyphs)
+	for g := range glyphs {
+		var start, end uint32
+		if binary.BigEndian.Uint16(head[50:]) == 1 {
+			start, end = binary.BigEndian.Uint32(loca[g*4:]), binary.BigEndian.Uint32(loca[g*4+4:])
+		} else {
+			start, end = uint32(binary.BigEndian.Uint16(loca[g*2:]))*2, uint32(binary.BigEndian.Uint16(loca[g*2+2:]))*2
+		}
+		glyphs[g] = glyf[start:end]
+	}
+	return glyphs
+}
+
+func TestSubsetFontFileGolden(t *testing.T) {
+	withTables := testfont.Sample()
+	withTables.LongLoca = true
+	withTables.Extra = map[string][]byte{
+		"DSIG": {0, 0, 0, 1, 0, 0, 0, 0},
+		"name": []byte("\x00\x00\x00\x00\x00\x06"),
+		"post": make([]byte, 32),
+	}
+	bmpOnly := testfont.Sample()
+	bmpOnly.Format12, bmpOnly.RangeOffsets = false, true
+
+	tests := []struct {
+		golden string
+		font   *testfont.Font
+		keys   []string
+	}{
+		{"ascii", testfont.Sample(), nil},
+		{"latin", testfont.Sample(), []string{"latin"}},
+		{"cyrillic", testfont.Sample(), []string{"cyrillic"}},
+		{"latin_extra_tables", withTables, []string{"latin"}},
+		{"latin_range_offsets", bmpOnly, []string{"latin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			dir := t.TempDir()
+			srcPath := filepath.Join(dir, "font.ttf")
+			dstPath := filepath.Join(dir, "subset.ttf")
+			original := tt.font.Bytes()
+			if err := os.WriteFile(srcPath, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			keep := fontSubsetRanges(tt.keys)
+			before, after, err := subsetFontFile(srcPath, dstPath, keep)
+			if err != nil {
+				t.Fatalf("subsetFontFile: %v", err)
+			}
+			subset, err := os.ReadFile(dstPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if before != int64(len(original)) || after != int64(len(subset)) {
+				t.Errorf("sizes = %d, %d, want %d, %d", before, after, len(original), len(subset))
+			}
+
+			checkSubset(t, original, subset, keep)
+
+			got := describeFont(t, subset)
+			goldenPath := filepath.Join("testdata", "font_subset", tt.golden+".golden")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(goldenPath, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("subset font differs from %s:\n%s\nwant:\n%s", goldenPath, got, want)
+			}
+		})
+	}
+}
+
+func TestSubsetFontFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	font, err := parseSfnt(testfont.Sample().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables := func(drop string, change func(tables map[string][]byte)) []byte {
+		result := make(map[string][]byte)
+		for tag := range font.tables {
+			if tag != drop {
+				result[tag] = append([]byte(nil), font.table(tag)...)
+			}
+		}
+		if change != nil {
+			change(result)
+		}
+		return testfont.Assemble(result)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"not a font", []byte("not a font file at all"), nil},
+		{"CFF outlines", tables("glyf", func(t map[string][]byte) { t["CFF "] = []byte{1, 0, 4, 1} }), errFontNotSubsettable},
+		{"no maxp", tables("maxp", nil), nil},
+		{"short loca", tables("", func(t map[string][]byte) { t["loca"] = t["loca"][:6] }), nil},
+		{"too many glyphs for loca", tables("", func(t map[string][]byte) { binary.BigEndian.PutUint16(t["maxp"][4:], 0xFFFF) }), nil},
+		{"no cmap", tables("cmap", nil), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srcPath := write(tt.name+".ttf", tt.data)
+			dstPath := filepath.Join(dir, tt.name+".subset.ttf")
+			_, _, err := subsetFontFile(srcPath, dstPath, fontSubsetRanges(nil))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.want != nil && err != tt.want {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+			if _, err := os.Stat(dstPath); err == nil {
+				t.Error("a subset font was written")
+			}
+		})
+	}
+
+	// Glyphs past the end of glyf, or with offsets going backwards, are emptied
+	broken := tables("", func(t map[string][]byte) {
+		binary.BigEndian.PutUint16(t["loca"][testfont.LetterA*2+2:], 0xFFFF)
+		binary.BigEndian.PutUint16(t["loca"][testfont.LetterO*2+2:], 0)
+	})
+	srcPath := write("broken.ttf", broken)
+	dstPath := filepath.Join(dir, "broken.subset.ttf")
+	if _, _, err := subsetFontFile(srcPath, dstPath, fontSubsetRanges(nil)); err != nil {
+		t.Fatalf("subsetFontFile: %v", err)
+	}
+	subset, err := readSfnt(dstPath)
+	if err != nil {
+		t.Fatalf("subset font doesn't parse: %v", err)
+	}
+	if glyphs := glyphOutlines(subset); len(glyphs[testfont.LetterA]) != 0 || len(glyphs[testfont.LetterO]) != 0 {
+		t.Errorf("broken glyphs kept outlines of %d and %d bytes", len(glyphs[testfont.LetterA]), len(glyphs[testfont.LetterO]))
+	}
+}
+
+func TestExportFontFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	logger := &Logger{DebugFn: func(string, ...interface{}) {}}
+
+	original := testfont.Sample().Bytes()
+	if err := os.WriteFile("font.ttf", original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a subset the font is copied whole
+	keys, err := exportFontFile("font.ttf", "whole.ttf", logger)
+	if err != nil || keys != nil {
+		t.Fatalf("exportFontFile = %v, %v, want a whole copy", keys, err)
+	}
+	if data, _ := os.ReadFile("whole.ttf"); !bytes.Equal(data, original) {
+		t.Error("whole copy differs from the font")
+	}
+
+	if err := ToggleFontSubsetRange("cyrillic"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ToggleFontSubsetRange("latin"); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = exportFontFile("font.ttf", "subset.ttf", logger)
+	if err != nil {
+		t.Fatalf("exportFontFile: %v", err)
+	}
+	if want := []string{"latin", "cyrillic"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("subset to %v, want %v", keys, want)
+	}
+	subset, err := os.ReadFile("subset.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSubset(t, original, subset, fontSubsetRanges(keys))
+
+	// Fonts that can't be subset are copied whole
+	if err := os.WriteFile("broken.ttf", []byte("not a font file at all"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err = exportFontFile("broken.ttf", "broken-copy.ttf", logger)
+	if err != nil || keys != nil {
+		t.Errorf("exportFontFile = %v, %v, want a whole copy", keys, err)
+	}
+}
+
+func TestSubsetDeviceFonts(t *testing.T) {
+	for _, path := range deviceFonts {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			original, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dstPath := filepath.Join(t.TempDir(), "subset.ttf")
+			keep := fontSubsetRanges([]string{"latin"})
+			if _, _, err := subsetFontFile(path, dstPath, keep); err != nil {
+				t.Fatalf("subsetFontFile: %v", err)
+			}
+			subset, err := os.ReadFile(dstPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkSubset(t, original, subset, keep)
+		})
+	}
+}
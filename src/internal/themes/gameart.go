@@ -0,0 +1,350 @@
+// src/internal/themes/gameart.go
+// Game art component: the placeholder and frame NextUI draws around boxart
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/ui"
+)
+
+// GameArtSystemPaths maps game art asset names to where NextUI reads them
+var GameArtSystemPaths = map[string]string{
+	"placeholder": "/mnt/SDCARD/.system/res/gameart_placeholder.png",
+	"frame":       "/mnt/SDCARD/.system/res/gameart_frame.png",
+}
+
+// gameArtAssets lists the asset names in the order they are processed
+var gameArtAssets = []string{
+	"placeholder",
+	"frame",
+}
+
+// setGameArtFlag marks an asset as present in a game art content section
+func setGameArtFlag(asset string, placeholder *bool, frame *bool) {
+	switch asset {
+	case "placeholder":
+		*placeholder = true
+	case "frame":
+		*frame = true
+	}
+}
+
+// ImportGameArt imports a game art component package, stopping between files once ctx is done
+func ImportGameArt(ctx context.Context, componentPath string) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting game art import: %s", componentPath)
+
+	// Load the component manifest
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return fmt.Errorf("error loading game art manifest: %w", err)
+	}
+
+	// Ensure it's the right type
+	manifest, ok := manifestObj.(*GameArtManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for game art component")
+	}
+
+	// Import game art based on path mappings
+	for asset, mapping := range manifest.PathMappings {
+		if err := applyCancelled(ctx); err != nil {
+			return err
+		}
+
+		srcPath := filepath.Join(componentPath, mapping.ThemePath)
+		if err := copyMappedFile(srcPath, mapping.SystemPath, logger); err != nil {
+			logger.DebugFn("Warning: Failed to copy game art %s: %v", asset, err)
+		}
+	}
+
+	// Update global manifest to track this component
+	componentName := filepath.Base(componentPath)
+	if err := UpdateAppliedComponent(ComponentGameArt, componentName); err != nil {
+		logger.DebugFn("Warning: Failed to update global manifest: %v", err)
+	}
+
+	logger.DebugFn("Game art import completed: %s", componentPath)
+
+	// Show success message to user
+	ui.ShowMessage(fmt.Sprintf("Game art from '%s' applied successfully!%s", manifest.ComponentInfo.Name, pinnedSummary()), "3")
+
+	return nil
+}
+
+// ExportGameArt exports the current game art as a .art component package
+func ExportGameArt(name string) (err error) {
+	defer func() { recordOperation("Exported game art", name, err) }()
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting game art export: %s", name)
+
+	// Get the current directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	// Create export directory path with .art extension
+	if !strings.HasSuffix(name, ComponentExtension[ComponentGameArt]) {
+		name = name + ComponentExtension[ComponentGameArt]
+	}
+
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", exportPath, err)
+	}
+
+	// Preserve author from the applied game art component if available
+	author := ""
+	if artComp, err := GetAppliedComponent(ComponentGameArt); err == nil && artComp != "" {
+		compPath := filepath.Join(cwd, "Components", ComponentDirectory[ComponentGameArt], artComp)
+		if manifestObj, err := LoadComponentManifest(compPath); err == nil {
+			if info := GetComponentInfo(manifestObj); info != nil {
+				author = info.Author
+			}
+		}
+	}
+
+	manifestObj, err := CreateMinimalComponentManifest(ComponentGameArt, name, author)
+	if err != nil {
+		return fmt.Errorf("error creating game art manifest: %w", err)
+	}
+
+	artManifest := manifestObj.(*GameArtManifest)
+	artManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentGameArt)
+
+	exported := 0
+	for _, asset := range gameArtAssets {
+		sourcePath := GameArtSystemPaths[asset]
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			logger.DebugFn("Game art file not found: %s", sourcePath)
+			continue
+		}
+
+		if err := CopyFile(sourcePath, filepath.Join(exportPath, asset+".png")); err != nil {
+			logger.DebugFn("Warning: Could not copy game art %s: %v", asset, err)
+			continue
+		}
+
+		artManifest.PathMappings[asset] = PathMapping{
+			ThemePath:  asset + ".png",
+			SystemPath: sourcePath,
+		}
+		setGameArtFlag(asset, &artManifest.Content.PlaceholderReplaced, &artManifest.Content.FrameReplaced)
+		exported++
+	}
+
+	if exported == 0 {
+		os.RemoveAll(exportPath)
+		return fmt.Errorf("no game art found to export")
+	}
+
+	// Use the placeholder as the preview since it's the most recognizable asset
+	previewPath := filepath.Join(exportPath, "preview.png")
+	if artManifest.Content.PlaceholderReplaced {
+		if err := CopyFile(filepath.Join(exportPath, "placeholder.png"), previewPath); err != nil {
+			logger.DebugFn("Warning: Could not create preview: %v", err)
+		}
+	} else if err := CreateDefaultPreviewImage(previewPath, ComponentGameArt); err != nil {
+		logger.DebugFn("Warning: Could not create default preview: %v", err)
+	}
+
+	// Stamp author credit into the preview
+	stampPreview(exportPath, artManifest.ComponentInfo.Name, artManifest.ComponentInfo.Author, logger)
+
+	if err := WriteComponentManifest(exportPath, artManifest); err != nil {
+		return fmt.Errorf("error writing game art manifest: %w", err)
+	}
+
+	logger.DebugFn("Game art export completed: %s", name)
+
+	ui.ShowMessage(fmt.Sprintf("Game art exported to '%s'", name), "3")
+
+	return nil
+}
+
+// UpdateGameArtManifest updates a game art component's manifest based on its content
+func UpdateGameArtManifest(componentPath string, logger *Logger) error {
+	logger.DebugFn("Updating game art manifest for: %s", componentPath)
+
+	componentName := filepath.Base(componentPath)
+
+	// Load existing manifest to preserve component_info
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		manifestObj, err = CreateComponentManifest(ComponentGameArt, componentName)
+		if err != nil {
+			return fmt.Errorf("error creating game art manifest: %w", err)
+		}
+	}
+
+	artManifest, ok := manifestObj.(*GameArtManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for game art component")
+	}
+
+	// Always update component name to match the directory name
+	artManifest.ComponentInfo.Name = componentName
+
+	// Clear existing content data (but preserve component_info)
+	artManifest.Content.PlaceholderReplaced = false
+	artManifest.Content.FrameReplaced = false
+	artManifest.PathMappings = make(map[string]PathMapping)
+
+	for _, asset := range gameArtAssets {
+		if _, err := os.Stat(filepath.Join(componentPath, asset+".png")); err != nil {
+			continue
+		}
+
+		artManifest.PathMappings[asset] = PathMapping{
+			ThemePath:  asset + ".png",
+			SystemPath: GameArtSystemPaths[asset],
+		}
+		setGameArtFlag(asset, &artManifest.Content.PlaceholderReplaced, &artManifest.Content.FrameReplaced)
+
+		logger.DebugFn("Added game art to manifest: %s", asset)
+	}
+
+	return WriteComponentManifest(componentPath, artManifest)
+}
+
+// DeconstructGameArt extracts game art from a theme package into a standalone component
+func DeconstructGameArt(themePath string, manifest *ThemeManifest, componentName string, logger *Logger) error {
+	logger.DebugFn("Extracting game art from theme to component: %s", componentName)
+
+	if !strings.HasSuffix(componentName, ComponentExtension[ComponentGameArt]) {
+		componentName = componentName + ComponentExtension[ComponentGameArt]
+	}
+
+	exportPath, err := exportPackagePath(componentName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", exportPath, err)
+	}
+
+	manifestObj, err := CreateMinimalComponentManifest(ComponentGameArt, componentName, manifest.ThemeInfo.Author)
+	if err != nil {
+		return fmt.Errorf("error creating game art manifest: %w", err)
+	}
+
+	artManifest := manifestObj.(*GameArtManifest)
+	artManifest.ComponentInfo.License = manifest.ThemeInfo.License
+
+	for asset, mapping := range manifest.PathMappings.GameArt {
+		srcPath := filepath.Join(themePath, mapping.ThemePath)
+		if _, err := os.Stat(srcPath); os.IsNotExist(err) {
+			logger.DebugFn("Game art file does not exist in theme: %s", srcPath)
+			continue
+		}
+
+		if err := CopyFile(srcPath, filepath.Join(exportPath, asset+".png")); err != nil {
+			logger.DebugFn("Warning: Could not copy game art %s: %v", asset, err)
+			continue
+		}
+
+		artManifest.PathMappings[asset] = PathMapping{
+			ThemePath:  asset + ".png",
+			SystemPath: mapping.SystemPath,
+		}
+		setGameArtFlag(asset, &artManifest.Content.PlaceholderReplaced, &artManifest.Content.FrameReplaced)
+	}
+
+	previewPath := filepath.Join(exportPath, "preview.png")
+	if artManifest.Content.PlaceholderReplaced {
+		if err := CopyFile(filepath.Join(exportPath, "placeholder.png"), previewPath); err != nil {
+			logger.DebugFn("Warning: Could not create preview: %v", err)
+		}
+	} else if err := CreateDefaultPreviewImage(previewPath, ComponentGameArt); err != nil {
+		logger.DebugFn("Warning: Could not create default preview: %v", err)
+	}
+
+	// Stamp author credit into the preview
+	stampPreview(exportPath, artManifest.ComponentInfo.Name, artManifest.ComponentInfo.Author, logger)
+
+	if err := WriteComponentManifest(exportPath, artManifest); err != nil {
+		return fmt.Errorf("error writing game art manifest: %w", err)
+	}
+
+	logger.DebugFn("Game art component extraction completed")
+	return nil
+}
+
+// exportGameArt copies the device's game art into a theme being exported
+func exportGameArt(themePath string, manifest *ThemeManifest, logger *Logger) error {
+	logger.DebugFn("Exporting game art")
+
+	manifest.Content.GameArt.Present = false
+	manifest.Content.GameArt.PlaceholderReplaced = false
+	manifest.Content.GameArt.FrameReplaced = false
+	manifest.PathMappings.GameArt = make(map[string]PathMapping)
+
+	for _, asset := range gameArtAssets {
+		sourcePath := GameArtSystemPaths[asset]
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			continue
+		}
+
+		dstPath := filepath.Join(themePath, "GameArt", asset+".png")
+		if err := CopyFile(sourcePath, dstPath); err != nil {
+			logger.DebugFn("Warning: Could not copy game art %s: %v", asset, err)
+			continue
+		}
+
+		manifest.PathMappings.GameArt[asset] = PathMapping{
+			ThemePath:  "GameArt/" + asset + ".png",
+			SystemPath: sourcePath,
+		}
+		manifest.Content.GameArt.Present = true
+		setGameArtFlag(asset, &manifest.Content.GameArt.PlaceholderReplaced, &manifest.Content.GameArt.FrameReplaced)
+	}
+
+	return nil
+}
+
+// updateGameArtMappings scans game art in the theme and updates manifest mappings
+func updateGameArtMappings(themePath string, manifest *ThemeManifest, logger *Logger) error {
+	if manifest.PathMappings.GameArt == nil {
+		manifest.PathMappings.GameArt = make(map[string]PathMapping)
+	}
+
+	gameArtDir := filepath.Join(themePath, "GameArt")
+	if _, err := scanStat(gameArtDir); os.IsNotExist(err) {
+		logger.DebugFn("No GameArt directory found in theme")
+		return nil
+	}
+
+	for _, asset := range gameArtAssets {
+		if _, err := scanStat(filepath.Join(gameArtDir, asset+".png")); err != nil {
+			continue
+		}
+
+		manifest.PathMappings.GameArt[asset] = PathMapping{
+			ThemePath:  filepath.Join("GameArt", asset+".png"),
+			SystemPath: GameArtSystemPaths[asset],
+		}
+		manifest.Content.GameArt.Present = true
+		setGameArtFlag(asset, &manifest.Content.GameArt.PlaceholderReplaced, &manifest.Content.GameArt.FrameReplaced)
+
+		logger.DebugFn("Added game art to manifest: %s", asset)
+	}
+
+	return nil
+}
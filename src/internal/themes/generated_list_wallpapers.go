@@ -0,0 +1,201 @@
+// src/internal/themes/generated_list_wallpapers.go
+// Makes a list wallpaper (bglist.png) from a system's wallpaper (bg.png) when a theme only
+// has the one, so game lists show a blurred, dimmed copy instead of plain black
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Look of a generated list wallpaper
+const (
+	generatedListBlur = 0.02 // Blur radius as a share of the shorter side
+	generatedListDim  = 40   // Percentage the blurred wallpaper is darkened by
+)
+
+// generateListWallpapers is set while the current apply makes the missing list wallpapers
+var generateListWallpapers bool
+
+// WithGeneratedListWallpapers runs an apply that also makes a bglist.png for every folder
+// the theme gives a bg.png but no bglist.png
+func WithGeneratedListWallpapers(apply func() error) error {
+	generateListWallpapers = true
+	defer func() { generateListWallpapers = false }()
+	return apply()
+}
+
+// ThemeLacksListWallpapers reports whether a theme has wallpapers without a matching list
+// wallpaper, i.e. whether generating them would change anything
+func ThemeLacksListWallpapers(manifest *ThemeManifest) bool {
+	return len(missingListWallpapers(manifest.PathMappings.Wallpapers)) > 0
+}
+
+// missingListWallpapers returns the bg.png mappings in .media folders whose bglist.png
+// isn't mapped too
+func missingListWallpapers(mappings []PathMapping) []PathMapping {
+	listed := make(map[string]bool)
+	for _, mapping := range mappings {
+		if isListWallpaper(mapping.SystemPath) {
+			listed[filepath.Dir(mapping.SystemPath)] = true
+		}
+	}
+
+	var missing []PathMapping
+	for _, mapping := range mappings {
+		dir := filepath.Dir(mapping.SystemPath)
+		if filepath.Base(mapping.SystemPath) == "bg.png" && filepath.Base(dir) == ".media" && !listed[dir] {
+			missing = append(missing, mapping)
+		}
+	}
+	return missing
+}
+
+// applyGeneratedListWallpapers makes the list wallpapers the theme lacks when the current
+// apply asked for them. They are copied like the theme's own files, so pins are respected
+// and the next theme switch removes them.
+func applyGeneratedListWallpapers(ctx context.Context, themePath string, manifest *ThemeManifest, excluded map[string]bool, logger *Logger) {
+	if !generateListWallpapers {
+		return
+	}
+
+	cacheDir, err := os.MkdirTemp("", "bglist-")
+	if err != nil {
+		logger.DebugFn("Warning: Could not make list wallpapers: %v", err)
+		return
+	}
+	defer os.RemoveAll(cacheDir)
+
+	// Themes often use one wallpaper for many systems, it is only blurred once
+	generated := make(map[string]string)
+	count := 0
+
+	for _, mapping := range missingListWallpapers(manifest.PathMappings.Wallpapers) {
+		if ctx.Err() != nil {
+			return
+		}
+		if isMappingExcluded(mapping, excluded) || isFolderMappingMissing(mapping) {
+			continue
+		}
+
+		srcPath := filepath.Join(themePath, mapping.ThemePath)
+		listPath, ok := generated[srcPath]
+		if !ok {
+			listPath = filepath.Join(cacheDir, fmt.Sprintf("%d.png", len(generated)))
+			if err := makeListWallpaper(srcPath, listPath, logger); err != nil {
+				logger.DebugFn("Warning: Could not make a list wallpaper from %s: %v", mapping.ThemePath, err)
+				continue
+			}
+			generated[srcPath] = listPath
+		}
+
+		dstPath := filepath.Join(filepath.Dir(mapping.SystemPath), "bglist.png")
+		if err := copyMappedFile(listPath, dstPath, logger); err != nil {
+			logger.DebugFn("Warning: Failed to copy generated list wallpaper: %v", err)
+			continue
+		}
+		dimListWallpaper(dstPath, manifest.Content.Settings.ListScrimOpacity, logger)
+		count++
+	}
+
+	logger.DebugFn("Generated %d list wallpapers from %d wallpapers", count, len(generated))
+}
+
+// makeListWallpaper writes a blurred, dimmed copy of a wallpaper to dstPath. Animated
+// wallpapers are blurred from their still frame.
+func makeListWallpaper(srcPath, dstPath string, logger *Logger) error {
+	stillPath, cleanup, err := stillWallpaperSource(srcPath, logger)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	if !strings.EqualFold(filepath.Ext(stillPath), ".png") {
+		return fmt.Errorf("not a PNG: %s", filepath.Base(stillPath))
+	}
+
+	file, err := os.Open(stillPath)
+	if err != nil {
+		return fmt.Errorf("error opening wallpaper: %w", err)
+	}
+	src, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding wallpaper: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+
+	size := width
+	if height < size {
+		size = height
+	}
+	radius := effectPixels(generatedListBlur, size)
+
+	// Blurring each channel twice comes close to a gaussian blur
+	channel := make([]uint8, width*height)
+	keep := float64(100-generatedListDim) / 100
+	for c := 0; c < 3; c++ {
+		for i := range channel {
+			channel[i] = img.Pix[(i/width)*img.Stride+(i%width)*4+c]
+		}
+		channel = boxBlur(boxBlur(channel, width, height, radius), width, height, radius)
+		for i, v := range channel {
+			img.Pix[(i/width)*img.Stride+(i%width)*4+c] = uint8(float64(v) * keep)
+		}
+	}
+
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return fmt.Errorf("error writing list wallpaper: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, img); err != nil {
+		return fmt.Errorf("error encoding list wallpaper: %w", err)
+	}
+	return nil
+}
+
+// boxBlur blurs one channel horizontally then vertically with a running sum, so the cost
+// doesn't grow with the radius like blurAlpha's. Edges repeat the outermost pixels.
+func boxBlur(values []uint8, width, height, radius int) []uint8 {
+	pass := func(src []uint8, length, lines int, at func(line, i int) int) []uint8 {
+		dst := make([]uint8, len(src))
+		window := 2*radius + 1
+		for line := 0; line < lines; line++ {
+			clamp := func(i int) int {
+				if i < 0 {
+					return 0
+				}
+				if i >= length {
+					return length - 1
+				}
+				return i
+			}
+
+			sum := 0
+			for i := -radius; i <= radius; i++ {
+				sum += int(src[at(line, clamp(i))])
+			}
+			for i := 0; i < length; i++ {
+				dst[at(line, i)] = uint8(sum / window)
+				sum += int(src[at(line, clamp(i+radius+1))]) - int(src[at(line, clamp(i-radius))])
+			}
+		}
+		return dst
+	}
+
+	horizontal := pass(values, width, height, func(y, x int) int { return y*width + x })
+	return pass(horizontal, height, width, func(x, y int) int { return y*width + x })
+}
@@ -5,9 +5,11 @@ package themes
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"nextui-themes/internal/logging"
@@ -24,8 +26,18 @@ type GlobalManifest struct {
 		LEDs       string `json:"leds,omitempty"`       // Name of applied LED package
 		Fonts      string `json:"fonts,omitempty"`      // Name of applied font package
 		Overlays   string `json:"overlays,omitempty"`   // Name of applied overlay package
+		GameArt    string `json:"game_art,omitempty"`   // Name of applied game art package
+		Shaders    string `json:"shaders,omitempty"`    // Name of applied shader package
+
+		// Applied packages of registered component types without a field above, by type
+		Other map[string]string `json:"other,omitempty"`
 	} `json:"applied_components"`
-	ApplicationInfo struct {
+	AppliedSinceTheme  []string                  `json:"applied_since_theme,omitempty"` // Component types applied over the current theme, oldest first
+	Slideshows         map[string]SlideshowState `json:"slideshows,omitempty"`          // Running wallpaper slideshows by system tag
+	Seasonal           *SeasonalRun              `json:"seasonal,omitempty"`            // Seasonal theme applied by a schedule, until it is reverted
+	WallpaperOverrides map[string]string         `json:"wallpaper_overrides,omitempty"` // Quick wallpaper picked for a slot, as <pack>/<file>, until the next wallpaper apply
+	SystemVersionHash  string                    `json:"system_version_hash,omitempty"` // Hash of the NextUI version file seen on the last run
+	ApplicationInfo    struct {
 		Version   string `json:"version"`
 		BuildDate string `json:"build_date"`
 	} `json:"application_info"`
@@ -41,88 +53,161 @@ func GetGlobalManifestPath() (string, error) {
 	return filepath.Join(cwd, "manifest.json"), nil
 }
 
-// LoadGlobalManifest loads the global manifest from disk, or creates a new one if it doesn't exist
-func LoadGlobalManifest() (*GlobalManifest, error) {
-	manifestPath, err := GetGlobalManifestPath()
+// globalManifestMu serializes changes to the global manifest within this process
+var globalManifestMu sync.Mutex
+
+// errGlobalManifestUnchanged is returned by an UpdateGlobalManifest callback that decided
+// there is nothing to save
+var errGlobalManifestUnchanged = errors.New("global manifest unchanged")
+
+// lockGlobalManifest takes the global manifest lock, both in this process and, through a lock
+// file next to the manifest, against other Theme Manager processes such as the boot hooks.
+// The returned function releases it.
+func lockGlobalManifest(manifestPath string) (func(), error) {
+	globalManifestMu.Lock()
+
+	file, err := os.OpenFile(filepath.Join(filepath.Dir(manifestPath), ".manifest.lock"), os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
-		return nil, err
+		globalManifestMu.Unlock()
+		return nil, fmt.Errorf("error opening global manifest lock: %w", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		globalManifestMu.Unlock()
+		return nil, fmt.Errorf("error locking global manifest: %w", err)
 	}
 
-	// Check if manifest exists
-	_, err = os.Stat(manifestPath)
-	if os.IsNotExist(err) {
-		// Create a new manifest
-		manifest := &GlobalManifest{
-			LastUpdated: time.Now(),
-			ApplicationInfo: struct {
-				Version   string `json:"version"`
-				BuildDate string `json:"build_date"`
-			}{
-				Version:   GetVersionString(),
-				BuildDate: time.Now().Format("2006-01-02"),
-			},
-		}
-
-		// Save the new manifest
-		if err := SaveGlobalManifest(manifest); err != nil {
-			return nil, fmt.Errorf("error saving new global manifest: %w", err)
-		}
+	return func() {
+		unlockFile(file)
+		file.Close()
+		globalManifestMu.Unlock()
+	}, nil
+}
 
-		return manifest, nil
-	} else if err != nil {
-		return nil, fmt.Errorf("error checking global manifest: %w", err)
+// newGlobalManifest returns the manifest of a device Theme Manager hasn't changed yet
+func newGlobalManifest() *GlobalManifest {
+	return &GlobalManifest{
+		LastUpdated: time.Now(),
+		ApplicationInfo: struct {
+			Version   string `json:"version"`
+			BuildDate string `json:"build_date"`
+		}{
+			Version:   GetVersionString(),
+			BuildDate: time.Now().Format("2006-01-02"),
+		},
 	}
+}
 
-	// Load existing manifest
+// readGlobalManifest reads the global manifest, or returns a new one and false when there is
+// no manifest yet
+func readGlobalManifest(manifestPath string) (*GlobalManifest, bool, error) {
 	data, err := os.ReadFile(manifestPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading global manifest: %w", err)
+	if os.IsNotExist(err) {
+		return newGlobalManifest(), false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("error reading global manifest: %w", err)
 	}
 
 	var manifest GlobalManifest
 	if err := json.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("error parsing global manifest: %w", err)
+		return nil, false, fmt.Errorf("error parsing global manifest: %w", err)
 	}
-
-	return &manifest, nil
+	return &manifest, true, nil
 }
 
-// SaveGlobalManifest saves the global manifest to disk
-func SaveGlobalManifest(manifest *GlobalManifest) error {
-	manifestPath, err := GetGlobalManifestPath()
-	if err != nil {
-		return err
-	}
-
+// writeGlobalManifest saves the global manifest through a temporary file, so a reader never
+// sees a half-written manifest
+func writeGlobalManifest(manifestPath string, manifest *GlobalManifest) error {
 	// Update timestamp
 	manifest.LastUpdated = time.Now()
 
-	// Convert to JSON
 	data, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error marshaling global manifest: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(manifestPath, data, 0644); err != nil {
+	tmpPath := manifestPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("error writing global manifest: %w", err)
 	}
+	if err := os.Rename(tmpPath, manifestPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error replacing global manifest: %w", err)
+	}
 
 	logging.LogDebug("Saved global manifest to %s", manifestPath)
 	return nil
 }
 
-// UpdateAppliedComponent updates the global manifest with the newly applied component
-func UpdateAppliedComponent(componentType string, componentName string) error {
-	manifest, err := LoadGlobalManifest()
+// LoadGlobalManifest loads the global manifest from disk, or creates a new one if it doesn't
+// exist. Changes must go through UpdateGlobalManifest.
+func LoadGlobalManifest() (*GlobalManifest, error) {
+	manifestPath, err := GetGlobalManifestPath()
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, exists, err := readGlobalManifest(manifestPath)
+	if err != nil || exists {
+		return manifest, err
+	}
+
+	// Save the new manifest
+	if err := UpdateGlobalManifest(func(created *GlobalManifest) error {
+		manifest = created
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("error saving new global manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// UpdateGlobalManifest loads the global manifest, lets update change it and saves it, holding
+// the manifest lock throughout so concurrent or queued operations can't overwrite each other's
+// changes. Nothing is saved when update returns an error. update must not load or update the
+// global manifest itself.
+func UpdateGlobalManifest(update func(manifest *GlobalManifest) error) error {
+	manifestPath, err := GetGlobalManifestPath()
 	if err != nil {
 		return err
 	}
 
+	unlock, err := lockGlobalManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	manifest, _, err := readGlobalManifest(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := update(manifest); err != nil {
+		if errors.Is(err, errGlobalManifestUnchanged) {
+			return nil
+		}
+		return err
+	}
+	return writeGlobalManifest(manifestPath, manifest)
+}
+
+// UpdateAppliedComponent updates the global manifest with the newly applied component
+func UpdateAppliedComponent(componentType string, componentName string) error {
+	return UpdateGlobalManifest(func(manifest *GlobalManifest) error {
+		return setAppliedComponent(manifest, componentType, componentName)
+	})
+}
+
+// setAppliedComponent records an applied package in a loaded global manifest
+func setAppliedComponent(manifest *GlobalManifest, componentType string, componentName string) error {
 	// Update the appropriate component field
 	switch componentType {
 	case "wallpaper":
 		manifest.AppliedComponents.Wallpapers = componentName
+		// A wallpaper pack replaces any quick wallpapers
+		manifest.WallpaperOverrides = nil
 	case "icon":
 		manifest.AppliedComponents.Icons = componentName
 	case "accent":
@@ -133,15 +218,45 @@ func UpdateAppliedComponent(componentType string, componentName string) error {
 		manifest.AppliedComponents.Fonts = componentName
 	case "overlay":
 		manifest.AppliedComponents.Overlays = componentName
+	case "gameart":
+		manifest.AppliedComponents.GameArt = componentName
+	case "shader":
+		manifest.AppliedComponents.Shaders = componentName
 	case "theme":
 		manifest.CurrentTheme = componentName
+		manifest.WallpaperOverrides = nil
 		// Don't clear component fields when applying a full theme
 		// They serve as a record of the last specific component packages applied
+		manifest.AppliedSinceTheme = nil
 	default:
-		return fmt.Errorf("unknown component type: %s", componentType)
+		if _, ok := LookupComponentKind(componentType); !ok {
+			return fmt.Errorf("unknown component type: %s", componentType)
+		}
+		if manifest.AppliedComponents.Other == nil {
+			manifest.AppliedComponents.Other = make(map[string]string)
+		}
+		if componentName == "" {
+			delete(manifest.AppliedComponents.Other, componentType)
+		} else {
+			manifest.AppliedComponents.Other[componentType] = componentName
+		}
+	}
+
+	// Keep the order components were applied over the theme in, so it can be replayed
+	if componentType != "theme" {
+		var since []string
+		for _, applied := range manifest.AppliedSinceTheme {
+			if applied != componentType {
+				since = append(since, applied)
+			}
+		}
+		if componentName != "" {
+			since = append(since, componentType)
+		}
+		manifest.AppliedSinceTheme = since
 	}
 
-	return SaveGlobalManifest(manifest)
+	return nil
 }
 
 // GetAppliedComponent returns the name of the currently applied component of the specified type
@@ -164,9 +279,16 @@ func GetAppliedComponent(componentType string) (string, error) {
 		return manifest.AppliedComponents.Fonts, nil
 	case "overlay":
 		return manifest.AppliedComponents.Overlays, nil
+	case "gameart":
+		return manifest.AppliedComponents.GameArt, nil
+	case "shader":
+		return manifest.AppliedComponents.Shaders, nil
 	case "theme":
 		return manifest.CurrentTheme, nil
 	default:
-		return "", fmt.Errorf("unknown component type: %s", componentType)
+		if _, ok := LookupComponentKind(componentType); !ok {
+			return "", fmt.Errorf("unknown component type: %s", componentType)
+		}
+		return manifest.AppliedComponents.Other[componentType], nil
 	}
 }
@@ -0,0 +1,167 @@
+// src/internal/themes/icon_mask.go
+// Cuts applied icons to one shape so packs with mixed shapes look uniform
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// Icon mask shapes stored in the config
+const (
+	IconMaskOff      = ""         // Keep each icon's own shape
+	IconMaskCircle   = "circle"   // Circle touching the icon's edges
+	IconMaskSquircle = "squircle" // Superellipse between a circle and a square
+	IconMaskRounded  = "rounded"  // Square with rounded corners
+)
+
+// IconMasks are the shapes offered in settings, in cycling order
+var IconMasks = []string{IconMaskOff, IconMaskCircle, IconMaskSquircle, IconMaskRounded}
+
+// iconMaskSamples is the number of samples per pixel side, so mask edges are antialiased
+const iconMaskSamples = 4
+
+// roundedCornerRatio is the corner radius of the rounded square, as a share of the icon size
+const roundedCornerRatio = 0.22
+
+// GetIconMaskSetting returns the shape applied icons are cut to, IconMaskOff unless the user chose one
+func GetIconMaskSetting() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load icon mask setting: %v", err)
+		return IconMaskOff
+	}
+
+	for _, mask := range IconMasks {
+		if config.IconMask == mask {
+			return mask
+		}
+	}
+	return IconMaskOff
+}
+
+// SetIconMaskSetting stores the shape applied icons are cut to
+func SetIconMaskSetting(mask string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.IconMask = mask
+	return SaveConfig(config)
+}
+
+// insideIconMask reports whether a point is inside the shape. x and y run from -1 to 1
+// across the icon.
+func insideIconMask(mask string, x, y float64) bool {
+	if x < 0 {
+		x = -x
+	}
+	if y < 0 {
+		y = -y
+	}
+
+	switch mask {
+	case IconMaskCircle:
+		return x*x+y*y <= 1
+	case IconMaskSquircle:
+		// |x|^4 + |y|^4 <= 1
+		x2, y2 := x*x, y*y
+		return x2*x2+y2*y2 <= 1
+	case IconMaskRounded:
+		r := roundedCornerRatio * 2
+		if x <= 1-r || y <= 1-r {
+			return x <= 1 && y <= 1
+		}
+		dx, dy := x-(1-r), y-(1-r)
+		return dx*dx+dy*dy <= r*r
+	default:
+		return true
+	}
+}
+
+// iconMaskCoverage returns how much of a pixel lies inside the shape, from 0 to 1
+func iconMaskCoverage(mask string, px, py, width, height int) float64 {
+	inside := 0
+	for sy := 0; sy < iconMaskSamples; sy++ {
+		y := (float64(py)+(float64(sy)+0.5)/iconMaskSamples)/float64(height)*2 - 1
+		for sx := 0; sx < iconMaskSamples; sx++ {
+			x := (float64(px)+(float64(sx)+0.5)/iconMaskSamples)/float64(width)*2 - 1
+			if insideIconMask(mask, x, y) {
+				inside++
+			}
+		}
+	}
+	return float64(inside) / (iconMaskSamples * iconMaskSamples)
+}
+
+// applyIconMask makes everything outside the shape transparent in an icon, in place. The
+// mask is generated for the icon's own size, so icons of any resolution come out smooth.
+func applyIconMask(path, mask string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening icon: %w", err)
+	}
+
+	src, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding icon: %w", err)
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+	width, height := bounds.Dx(), bounds.Dy()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			coverage := iconMaskCoverage(mask, x, y, width, height)
+			if coverage >= 1 {
+				continue
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+3] = uint8(float64(dst.Pix[i+3])*coverage + 0.5)
+		}
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error writing icon: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, dst); err != nil {
+		return fmt.Errorf("error encoding icon: %w", err)
+	}
+	return nil
+}
+
+// maskAppliedIcon cuts a freshly copied icon to the shape the user picked
+func maskAppliedIcon(path string, logger *Logger) {
+	mask := GetIconMaskSetting()
+	if mask == IconMaskOff || !strings.EqualFold(filepath.Ext(path), ".png") {
+		return
+	}
+
+	// Masking rewrites the file, which must not reach the package through a hard link
+	if err := detachHardLink(path); err != nil {
+		logger.DebugFn("Warning: Could not mask icon %s: %v", path, err)
+		return
+	}
+
+	if err := applyIconMask(path, mask); err != nil {
+		logger.DebugFn("Warning: Could not mask icon %s: %v", path, err)
+		return
+	}
+
+	logger.DebugFn("Masked icon %s as %s", path, mask)
+}
@@ -0,0 +1,165 @@
+// src/internal/themes/icon_remap.go
+// Shows which ROM folder each icon of a pack maps to and renames icons to other folders
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// IconAssignment is a system icon of a pack and the ROM folder it is applied to
+type IconAssignment struct {
+	Icon   string // File name in the pack's SystemIcons folder
+	Target string // ROM folder or special destination name, "" when the icon maps to nothing on this device
+}
+
+// iconTagPattern finds the system tag in an icon's file name
+var iconTagPattern = regexp.MustCompile(`\((.*?)\)`)
+
+// iconPackPath returns where an installed icon pack lives
+func iconPackPath(packName string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	packPath := filepath.Join(cwd, "Components", ComponentDirectory[ComponentIcon], packName)
+	if _, err := os.Stat(packPath); err != nil {
+		return "", fmt.Errorf("icon pack %s is not installed", packName)
+	}
+	return packPath, nil
+}
+
+// iconTarget returns the ROM folder or special destination an icon file name maps to on
+// this device, the same way applying the pack decides, or "" when it maps to nothing
+func iconTarget(fileName string, systemPaths *system.SystemPaths) string {
+	if special, ok := findSpecialDestination(SpecialIcons(), fileName); ok {
+		if special.SystemName != "" {
+			return special.SystemName
+		}
+		return special.Name
+	}
+
+	matches := iconTagPattern.FindStringSubmatch(fileName)
+	if len(matches) < 2 {
+		return ""
+	}
+	if sys, ok := systemPaths.SystemForTag(matches[1]); ok {
+		return sys.Name
+	}
+	return ""
+}
+
+// ListIconAssignments returns every system icon of an installed icon pack with the ROM folder
+// it will be applied to, sorted by icon name
+func ListIconAssignments(packName string) ([]IconAssignment, error) {
+	packPath, err := iconPackPath(packName)
+	if err != nil {
+		return nil, err
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(packPath, "SystemIcons"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading icons of %s: %w", packName, err)
+	}
+
+	var assignments []IconAssignment
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !strings.HasSuffix(strings.ToLower(entry.Name()), ".png") {
+			continue
+		}
+		assignments = append(assignments, IconAssignment{
+			Icon:   entry.Name(),
+			Target: iconTarget(entry.Name(), systemPaths),
+		})
+	}
+
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].Icon < assignments[j].Icon
+	})
+	return assignments, nil
+}
+
+// ReassignIcon renames a system icon of an installed icon pack after a ROM folder on this
+// device, so it is applied to that system, and updates the pack's manifest. An icon the pack
+// already had for that folder takes the moved icon's old name, so nothing is lost.
+func ReassignIcon(packName, icon, romFolder string) (err error) {
+	defer func() { recordOperation("Remapped icon", packName, err) }()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	packPath, err := iconPackPath(packName)
+	if err != nil {
+		return err
+	}
+
+	iconsDir := filepath.Join(packPath, "SystemIcons")
+	newName := romFolder + ".png"
+	if newName == icon {
+		return nil
+	}
+
+	oldPath := filepath.Join(iconsDir, icon)
+	newPath := filepath.Join(iconsDir, newName)
+
+	// Swap with the icon already named after the folder, via a temporary name
+	swapped := false
+	if _, err := os.Stat(newPath); err == nil {
+		tempPath := filepath.Join(iconsDir, ".remap-"+icon)
+		if err := os.Rename(oldPath, tempPath); err != nil {
+			return fmt.Errorf("error renaming %s: %w", icon, err)
+		}
+		if err := os.Rename(newPath, oldPath); err != nil {
+			os.Rename(tempPath, oldPath)
+			return fmt.Errorf("error renaming %s: %w", newName, err)
+		}
+		oldPath = tempPath
+		swapped = true
+	}
+	if err := os.Rename(oldPath, newPath); err != nil {
+		return fmt.Errorf("error renaming %s: %w", icon, err)
+	}
+
+	// Original FAT32 names no longer apply to the renamed files
+	manifestObj, err := LoadComponentManifest(packPath)
+	if err == nil {
+		info := GetComponentInfo(manifestObj)
+		if info.FileNames != nil {
+			delete(info.FileNames, filepath.Join("SystemIcons", icon))
+			delete(info.FileNames, filepath.Join("SystemIcons", newName))
+			if err := WriteComponentManifest(packPath, manifestObj); err != nil {
+				logger.DebugFn("Warning: Could not update file names of %s: %v", packName, err)
+			}
+		}
+	}
+
+	if err := UpdateComponentManifest(packPath); err != nil {
+		return fmt.Errorf("error updating manifest of %s: %w", packName, err)
+	}
+
+	if swapped {
+		logger.DebugFn("Swapped icons %s and %s in %s", icon, newName, packName)
+	} else {
+		logger.DebugFn("Renamed icon %s to %s in %s", icon, newName, packName)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+// src/internal/themes/icon_subset.go
+// Extracts a few systems' icons from an installed icon pack into a smaller pack
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ListPackSystemIcons returns the system icons listed in an installed icon pack's manifest
+func ListPackSystemIcons(packName string) ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	manifestObj, err := LoadComponentManifest(filepath.Join(cwd, "Components", ComponentDirectory[ComponentIcon], packName))
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", packName, err)
+	}
+
+	manifest, ok := manifestObj.(*IconManifest)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an icon pack", packName)
+	}
+	return manifest.Content.SystemIcons, nil
+}
+
+// subsetPackName returns a free name for a subset of packName, e.g. "Retro-subset.icon",
+// then "Retro-subset-2.icon"
+func subsetPackName(componentsDir, packName string) string {
+	stem := strings.TrimSuffix(packName, ComponentExtension[ComponentIcon]) + "-subset"
+	name := stem + ComponentExtension[ComponentIcon]
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(componentsDir, name)); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d%s", stem, i, ComponentExtension[ComponentIcon])
+	}
+}
+
+// ExtractIconSubset copies the chosen system icons of an installed icon pack into a new
+// installed pack and returns its name. The new pack keeps the original's author and license.
+func ExtractIconSubset(packName string, icons []string) (name string, err error) {
+	defer func() { recordOperation("Extracted icon subset", name, err) }()
+	defer logging.BeginOperation("extract icon subset", packName)()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if len(icons) == 0 {
+		return "", fmt.Errorf("no icons selected")
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	componentsDir := filepath.Join(cwd, "Components", ComponentDirectory[ComponentIcon])
+	packPath := filepath.Join(componentsDir, packName)
+
+	manifestObj, err := LoadComponentManifest(packPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", packName, err)
+	}
+	source := GetComponentInfo(manifestObj)
+
+	name = subsetPackName(componentsDir, packName)
+	subsetPath := filepath.Join(componentsDir, name)
+
+	// Assemble under a hidden name, so an interrupted extraction never shows up as installed
+	tempPath := filepath.Join(componentsDir, ".subset-"+name)
+	if err := os.RemoveAll(tempPath); err != nil {
+		return "", fmt.Errorf("error clearing previous extraction: %w", err)
+	}
+	defer os.RemoveAll(tempPath)
+
+	if err := os.MkdirAll(filepath.Join(tempPath, "SystemIcons"), 0755); err != nil {
+		return "", fmt.Errorf("error creating directory: %w", err)
+	}
+
+	fileNames := make(map[string]string)
+	for _, icon := range icons {
+		rel := filepath.Join("SystemIcons", filepath.Base(icon))
+		if err := CopyFile(filepath.Join(packPath, rel), filepath.Join(tempPath, rel)); err != nil {
+			return "", fmt.Errorf("error copying %s: %w", icon, err)
+		}
+		if original, ok := source.FileNames[rel]; ok {
+			fileNames[rel] = original
+		}
+	}
+
+	newManifest, err := CreateComponentManifest(ComponentIcon, name)
+	if err != nil {
+		return "", err
+	}
+	info := GetComponentInfo(newManifest)
+	info.Author = source.Author
+	info.License = source.License
+	info.Tags = source.Tags
+	if len(fileNames) > 0 {
+		info.FileNames = fileNames
+	}
+	if err := WriteComponentManifest(tempPath, newManifest); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tempPath, subsetPath); err != nil {
+		return "", fmt.Errorf("error installing extracted pack: %w", err)
+	}
+	if err := UpdateComponentManifest(subsetPath); err != nil {
+		logger.DebugFn("Warning: Error updating manifest of %s: %v", name, err)
+	}
+
+	logger.DebugFn("Extracted %d icons from %s into %s", len(icons), packName, name)
+	return name, nil
+}
@@ -0,0 +1,301 @@
+// src/internal/themes/image_effects.go
+// Drop shadow, outline and dimming effects added to a component's images as they are applied
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// Effect strengths stored in the config
+const (
+	EffectOff    = ""       // No effect
+	EffectLight  = "light"  // Soft shadow or thin outline
+	EffectStrong = "strong" // Dark shadow or thick outline
+)
+
+// EffectLevels are the shadow and outline strengths offered, in cycling order
+var EffectLevels = []string{EffectOff, EffectLight, EffectStrong}
+
+// EffectDimPresets are the dimming percentages offered, in cycling order
+var EffectDimPresets = []int{0, 10, 20, 30}
+
+// ImageEffects are the effects picked for one component. Shadow and outline follow the
+// outline of the image's opaque parts, so they show on icons and on wallpapers with
+// transparent areas; dimming darkens the whole image.
+type ImageEffects struct {
+	Shadow  string `json:"shadow,omitempty"`
+	Outline string `json:"outline,omitempty"`
+	Dim     int    `json:"dim,omitempty"` // Percentage the image is darkened by
+}
+
+// effectParams are the sizes of an effect as a share of the image's shorter side, and the
+// opacity of the shadow
+type effectParams struct {
+	offset  float64
+	blur    float64
+	opacity float64
+	width   float64
+}
+
+// shadowParams and outlineParams scale with the image so icons of any resolution match
+var (
+	shadowParams = map[string]effectParams{
+		EffectLight:  {offset: 0.02, blur: 0.02, opacity: 0.5},
+		EffectStrong: {offset: 0.04, blur: 0.03, opacity: 0.8},
+	}
+	outlineParams = map[string]effectParams{
+		EffectLight:  {width: 0.01},
+		EffectStrong: {width: 0.025},
+	}
+)
+
+// IsEmpty reports whether no effect is picked
+func (e ImageEffects) IsEmpty() bool {
+	return e.Shadow == EffectOff && e.Outline == EffectOff && e.Dim <= 0
+}
+
+// GetImageEffects returns the effects picked for a component package, e.g. "Mono.icon"
+func GetImageEffects(packageName string) ImageEffects {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load image effects: %v", err)
+		return ImageEffects{}
+	}
+	return config.ImageEffects[packageName]
+}
+
+// SetImageEffects stores the effects for a component package; they take effect the next
+// time it is applied
+func SetImageEffects(packageName string, effects ImageEffects) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	if effects.IsEmpty() {
+		delete(config.ImageEffects, packageName)
+	} else {
+		if config.ImageEffects == nil {
+			config.ImageEffects = make(map[string]ImageEffects)
+		}
+		config.ImageEffects[packageName] = effects
+	}
+	return SaveConfig(config)
+}
+
+// effectPixels converts a share of the image size to pixels, never less than one
+func effectPixels(share float64, size int) int {
+	if pixels := int(share*float64(size) + 0.5); pixels > 1 {
+		return pixels
+	}
+	return 1
+}
+
+// dilateAlpha grows the opaque parts of an alpha mask by radius pixels in every direction.
+// Each row's running maximum is widened one pixel at a time, so a round brush costs a
+// pass per pixel of radius rather than per pixel of its area.
+func dilateAlpha(alpha []uint8, width, height, radius int) []uint8 {
+	// rowMax[w] holds the maximum over [x-w, x+w] on each row
+	rowMax := make([][]uint8, radius+1)
+	rowMax[0] = alpha
+	for w := 1; w <= radius; w++ {
+		prev := rowMax[w-1]
+		cur := make([]uint8, len(alpha))
+		for y := 0; y < height; y++ {
+			row := y * width
+			for x := 0; x < width; x++ {
+				best := prev[row+x]
+				if x-w >= 0 && alpha[row+x-w] > best {
+					best = alpha[row+x-w]
+				}
+				if x+w < width && alpha[row+x+w] > best {
+					best = alpha[row+x+w]
+				}
+				cur[row+x] = best
+			}
+		}
+		rowMax[w] = cur
+	}
+
+	// Half width of the brush on each row above and below the center
+	halfWidth := make([]int, radius+1)
+	for dy := 0; dy <= radius; dy++ {
+		w := 0
+		for (w+1)*(w+1)+dy*dy <= radius*radius {
+			w++
+		}
+		halfWidth[dy] = w
+	}
+
+	out := make([]uint8, len(alpha))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var best uint8
+			for dy := -radius; dy <= radius && best < 255; dy++ {
+				sy := y + dy
+				if sy < 0 || sy >= height {
+					continue
+				}
+				d := dy
+				if d < 0 {
+					d = -d
+				}
+				if a := rowMax[halfWidth[d]][sy*width+x]; a > best {
+					best = a
+				}
+			}
+			out[y*width+x] = best
+		}
+	}
+	return out
+}
+
+// blurAlpha softens an alpha mask with a box blur, run horizontally then vertically
+func blurAlpha(alpha []uint8, width, height, radius int) []uint8 {
+	pass := func(src []uint8, horizontal bool) []uint8 {
+		dst := make([]uint8, len(src))
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				sum, n := 0, 0
+				for d := -radius; d <= radius; d++ {
+					sx, sy := x, y
+					if horizontal {
+						sx += d
+					} else {
+						sy += d
+					}
+					if sx < 0 || sx >= width || sy < 0 || sy >= height {
+						n++
+						continue
+					}
+					sum += int(src[sy*width+sx])
+					n++
+				}
+				dst[y*width+x] = uint8(sum / n)
+			}
+		}
+		return dst
+	}
+	return pass(pass(alpha, true), false)
+}
+
+// compositeUnder draws a black layer with the given alpha mask beneath an image
+func compositeUnder(img *image.NRGBA, layer []uint8) {
+	width := img.Bounds().Dx()
+	for i := range layer {
+		la := float64(layer[i]) / 255
+		if la == 0 {
+			continue
+		}
+		p := (i/width)*img.Stride + (i%width)*4
+		fa := float64(img.Pix[p+3]) / 255
+		outA := fa + la*(1-fa)
+		if outA == 0 {
+			continue
+		}
+		// The layer is black, so only the image's own colors contribute
+		for c := 0; c < 3; c++ {
+			img.Pix[p+c] = uint8(float64(img.Pix[p+c])*fa/outA + 0.5)
+		}
+		img.Pix[p+3] = uint8(outA*255 + 0.5)
+	}
+}
+
+// applyImageEffects adds the effects to a PNG in place: the outline goes beneath the image,
+// the shadow beneath both, then the result is dimmed
+func applyImageEffects(path string, effects ImageEffects) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening image: %w", err)
+	}
+
+	src, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+
+	size := width
+	if height < size {
+		size = height
+	}
+
+	alpha := make([]uint8, width*height)
+	for i := range alpha {
+		alpha[i] = img.Pix[(i/width)*img.Stride+(i%width)*4+3]
+	}
+
+	// Layers are added beneath the image, nearest first. The shadow is cast by the image
+	// together with its outline.
+	caster := alpha
+	if params, ok := outlineParams[effects.Outline]; ok {
+		caster = dilateAlpha(alpha, width, height, effectPixels(params.width, size))
+		compositeUnder(img, caster)
+	}
+
+	if params, ok := shadowParams[effects.Shadow]; ok {
+		offset := effectPixels(params.offset, size)
+		shadow := make([]uint8, len(alpha))
+		for y := offset; y < height; y++ {
+			for x := offset; x < width; x++ {
+				shadow[y*width+x] = uint8(float64(caster[(y-offset)*width+x-offset]) * params.opacity)
+			}
+		}
+		compositeUnder(img, blurAlpha(shadow, width, height, effectPixels(params.blur, size)))
+	}
+
+	if effects.Dim > 0 {
+		keep := float64(100-effects.Dim) / 100
+		for i := 0; i < len(img.Pix); i += 4 {
+			for c := 0; c < 3; c++ {
+				img.Pix[i+c] = uint8(float64(img.Pix[i+c]) * keep)
+			}
+		}
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error writing image: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, img); err != nil {
+		return fmt.Errorf("error encoding image: %w", err)
+	}
+	return nil
+}
+
+// applyComponentEffects adds a component's picked effects to a freshly copied image
+func applyComponentEffects(path string, effects ImageEffects, logger *Logger) {
+	if effects.IsEmpty() || !strings.EqualFold(filepath.Ext(path), ".png") {
+		return
+	}
+
+	// Effects rewrite the file, which must not reach the package through a hard link
+	if err := detachHardLink(path); err != nil {
+		logger.DebugFn("Warning: Could not add image effects to %s: %v", path, err)
+		return
+	}
+
+	if err := applyImageEffects(path, effects); err != nil {
+		logger.DebugFn("Warning: Could not add image effects to %s: %v", path, err)
+		return
+	}
+
+	logger.DebugFn("Added image effects to %s (shadow %q, outline %q, dim %d%%)",
+		path, effects.Shadow, effects.Outline, effects.Dim)
+}
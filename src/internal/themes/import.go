@@ -4,6 +4,8 @@
 package themes
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"nextui-themes/internal/logging"
 	"nextui-themes/internal/system"
@@ -15,18 +17,59 @@ import (
 	"strings"
 )
 
+// themeCleanupTypes are the component types whose files are removed before every theme apply,
+// so a theme without them doesn't leave the previous ones behind
+var themeCleanupTypes = []string{ComponentWallpaper, ComponentIcon}
+
 // ImportTheme imports a theme package
 // Modify the ImportTheme function in src/internal/themes/import.go
 // This updates the ImportTheme function to always clean up existing components
 // before applying new ones from the theme pack, matching the behavior of individual component packs.
 
 func ImportTheme(themeName string) error {
+	return ImportThemeContext(context.Background(), themeName)
+}
+
+// ImportThemeContext imports a theme package, stopping between files once ctx is done.
+// Files already copied when it stops stay applied and are tracked like any other apply.
+func ImportThemeContext(ctx context.Context, themeName string) (err error) {
+	defer func() { recordOperation("Applied theme", themeName, err) }()
+	defer logging.BeginOperation("apply theme", themeName)()
+
 	// Create logger
 	logger := &Logger{
 		DebugFn: logging.LogDebug,
 	}
 
 	logger.DebugFn("Starting theme import for: %s", themeName)
+	defer logging.LogMemoryUsage("theme apply")
+
+	// Fail once with a clear message instead of on every file
+	if err := CheckStorageWritable(); err != nil {
+		logger.DebugFn("Storage check failed: %v", err)
+		return err
+	}
+
+	if err := CheckKioskMode("applying a theme"); err != nil {
+		return err
+	}
+
+	// A theme apply purges and rewrites a lot of files, don't start one on a dying battery
+	if err := CheckBatteryForOperation("applying a theme"); err != nil {
+		return err
+	}
+
+	// Load the user's pinned files for this apply
+	beginPinnedApply()
+	beginTrashBatch()
+	beginApplyReport()
+	beginLinkApply()
+	beginStorageMirrors()
+	beginWriteVerify()
+
+	beginApplyTiming()
+	defer finishApplyTiming(logger)
+	startApplyPhase("validation")
 
 	// Get current directory
 	cwd, err := os.Getwd()
@@ -37,6 +80,23 @@ func ImportTheme(themeName string) error {
 	// Full path to theme - look in Themes directory directly instead of Themes/Imports
 	themePath := filepath.Join(cwd, "Themes", themeName)
 
+	// Zipped themes are applied from a temporary copy
+	if IsThemeArchive(themeName) {
+		unpacked, cleanup, err := unpackThemeArchive(themePath, logger)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		themePath = unpacked
+	}
+
+	// Move legacy wallpaper folders into the current layout before reading the manifest
+	if HasLegacyLayout(themePath) {
+		if _, err := MigrateLegacyLayout(themePath, logger); err != nil {
+			logger.DebugFn("Warning: Could not migrate legacy theme layout: %v", err)
+		}
+	}
+
 	// Validate theme
 	manifest, err := ValidateTheme(themePath, logger)
 	if err != nil {
@@ -58,21 +118,36 @@ func ImportTheme(themeName string) error {
 		// Continue anyway with the original manifest
 	}
 
-	// IMPORTANT CHANGE: Always clean up existing components before applying new ones
-	// This ensures consistency with how individual component packs work
+	// Variant themes pick up everything they don't override from the theme they extend
+	if err := resolveExtends(themePath, manifest, systemPaths, logger); err != nil {
+		logger.DebugFn("Error resolving base theme: %v", err)
+		return fmt.Errorf("error resolving base theme: %w", err)
+	}
 
-	// Clean up existing wallpapers (regardless of whether the theme includes them)
-	logger.DebugFn("Cleaning up existing wallpapers before theme import")
-	if err := cleanupExistingWallpapers(systemPaths, logger); err != nil {
-		logger.DebugFn("Warning: Error cleaning up existing wallpapers: %v", err)
-		// Continue with import anyway
+	// Don't start removing files for an apply that was cancelled while validating
+	if err := applyCancelled(ctx); err != nil {
+		return err
 	}
 
-	// Clean up existing icons (regardless of whether the theme includes them)
-	logger.DebugFn("Cleaning up existing icons before theme import")
-	if err := cleanupExistingIcons(systemPaths, logger); err != nil {
-		logger.DebugFn("Warning: Error cleaning up existing icons: %v", err)
-		// Continue with import anyway
+	// Partial applies leave the other parts as they are
+	filterThemeParts(manifest, logger)
+
+	beginApplyProgress(countThemeMappings(manifest))
+	startApplyPhase("cleanup")
+
+	// IMPORTANT CHANGE: Always clean up existing components before applying new ones
+	// This ensures consistency with how individual component packs work
+
+	// Clean up existing wallpapers and icons (regardless of whether the theme includes them)
+	for _, componentType := range partialCleanupTypes(themeCleanupTypes) {
+		kind, _ := LookupComponentKind(componentType)
+		what := strings.ToLower(kind.Directory())
+		logger.DebugFn("Cleaning up existing %s before theme import", what)
+		reportApplyStep("Removing old " + what)
+		if err := kind.Cleanup(systemPaths, logger); err != nil {
+			logger.DebugFn("Warning: Error cleaning up existing %s: %v", what, err)
+			// Continue with import anyway
+		}
 	}
 
 	// Clean up existing overlays (regardless of whether the theme includes them)
@@ -90,13 +165,38 @@ func ImportTheme(themeName string) error {
 	// }
 
 	// Apply theme components based on the (now updated) manifest
-	if err := importThemeFiles(themePath, manifest, systemPaths, logger); err != nil {
+	startApplyPhase("copy")
+	if err := importThemeFiles(ctx, themePath, manifest, systemPaths, logger); err != nil {
 		logger.DebugFn("Error importing theme files: %v", err)
+
+		// Keep track of the files written before the user cancelled or strict mode stopped it
+		if IsCancelled(err) || errors.Is(err, errStrictWarning) {
+			if err := saveManagedLedger(); err != nil {
+				logger.DebugFn("Warning: Could not save managed files ledger: %v", err)
+			}
+		}
 		return fmt.Errorf("error importing theme files: %w", err)
 	}
 
+	// Make sure everything the manifest lists actually landed on the device
+	discrepancies := verifyApplyCounts(map[string]int{
+		reportWallpapers: manifest.Content.Wallpapers.Count,
+		reportIcons: manifest.Content.Icons.SystemCount +
+			manifest.Content.Icons.ToolCount +
+			manifest.Content.Icons.CollectionCount,
+	}, logger)
+
+	if err := saveManagedLedger(); err != nil {
+		logger.DebugFn("Warning: Could not save managed files ledger: %v", err)
+	}
+	pruneTrash(logger)
+
 	// Apply accent colors directly from manifest
-	if manifest.Content.Settings.AccentsIncluded {
+	startApplyPhase("settings")
+	if manifest.Content.Settings.AccentsIncluded && !GetDeviceCapabilities().Accents {
+		logger.DebugFn("Skipping accent colors, NextUI's settings folder was not found")
+		reportUnsupported("accent colors")
+	} else if manifest.Content.Settings.AccentsIncluded {
 		if err := applyAccentSettings(manifest, logger); err != nil {
 			logger.DebugFn("Warning: Error applying accent settings: %v", err)
 		}
@@ -109,11 +209,16 @@ func ImportTheme(themeName string) error {
 	// 	}
 	// }
 
+	// Remember the applied theme, exports of the device are named after it
+	if err := UpdateAppliedComponent("theme", themeName); err != nil {
+		logger.DebugFn("Warning: Could not update global manifest: %v", err)
+	}
+
 	logger.DebugFn("Theme import completed successfully: %s", themeName)
 
 	// Show success message to user
-	ui.ShowMessage(fmt.Sprintf("Theme '%s' by %s imported successfully!",
-		manifest.ThemeInfo.Name, manifest.ThemeInfo.Author), "3")
+	ui.ShowMessage(fmt.Sprintf("Theme '%s' by %s imported successfully!%s",
+		manifest.ThemeInfo.Name, manifest.ThemeInfo.Author, pinnedSummary()+animatedSummary()+unsupportedSummary()+verificationSummary(discrepancies)), "3")
 
 	return nil
 }
@@ -122,8 +227,9 @@ func ImportTheme(themeName string) error {
 // but it's no longer called from ImportTheme since we now always clean up components
 // regardless of whether the theme includes them or not.
 
-// importThemeFiles copies all files from the theme to the system based on path mappings
-func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) error {
+// importThemeFiles copies all files from the theme to the system based on path mappings,
+// stopping between files once ctx is done
+func importThemeFiles(ctx context.Context, themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) error {
 	// Ensure media directories exist
 	if systemPaths != nil {
 		if err := system.EnsureMediaDirectories(systemPaths); err != nil {
@@ -131,20 +237,70 @@ func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *sy
 		}
 	}
 
+	// Systems the user excluded from theming
+	excluded := loadExcludedSystems()
+
 	// Process wallpaper mappings
 	for _, mapping := range manifest.PathMappings.Wallpapers {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		// Leave systems the user excluded from theming alone
+		if isMappingExcluded(mapping, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", mapping.SystemPath)
+			reportSkipped(reportWallpapers)
+			continue
+		}
+
+		// Don't create folders that only existed on the exporter's device
+		if isFolderMappingMissing(mapping) {
+			logger.DebugFn("Skipping wallpaper for missing folder: %s", mapping.Metadata["FolderPath"])
+			continue
+		}
+
 		srcPath := filepath.Join(themePath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
+		// NextUI can't show animated wallpapers, so apply a still frame or leave them out
+		srcPath, cleanup, err := stillWallpaperSource(srcPath, logger)
+		if err != nil {
+			logger.DebugFn("Warning: Skipping wallpaper: %v", err)
+			reportSkipped(reportWallpapers)
+			continue
+		}
+
 		// Copy the file
-		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+		err = copyMappedFile(srcPath, dstPath, logger)
+		cleanup()
+		reportCopyResult(reportWallpapers, err)
+		if err != nil {
 			logger.DebugFn("Warning: Failed to copy wallpaper: %v", err)
 			// Continue with other files
+			continue
 		}
+
+		dimListWallpaper(dstPath, manifest.Content.Settings.ListScrimOpacity, logger)
 	}
 
+	// Fill in list wallpapers the theme left out, when the apply asked for them
+	applyGeneratedListWallpapers(ctx, themePath, manifest, excluded, logger)
+
+	applySleepWallpaper(themePath, manifest.Content.Wallpapers.Sleep, systemPaths, logger)
+
 	// Process icon mappings with special handling for system icons
 	for _, mapping := range manifest.PathMappings.Icons {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		// Leave systems the user excluded from theming alone
+		if isMappingExcluded(mapping, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", mapping.SystemPath)
+			reportSkipped(reportIcons)
+			continue
+		}
+
 		srcPath := filepath.Join(themePath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -165,10 +321,15 @@ func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *sy
 		}
 
 		// Copy the file to the (possibly renamed) destination
-		if err := copyMappedFile(srcPath, dstPath, logger); err != nil {
+		err := copyMappedFile(srcPath, dstPath, logger)
+		reportCopyResult(reportIcons, err)
+		if err != nil {
 			logger.DebugFn("Warning: Failed to copy icon: %v", err)
 			// Continue with other files
+			continue
 		}
+
+		maskAppliedIcon(dstPath, logger)
 	}
 
 	// Process overlay mappings
@@ -185,6 +346,10 @@ func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *sy
 
 	// Process font mappings
 	for fontType, mapping := range manifest.PathMappings.Fonts {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
 		srcPath := filepath.Join(themePath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -195,8 +360,38 @@ func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *sy
 		}
 	}
 
+	// Process game art mappings
+	for asset, mapping := range manifest.PathMappings.GameArt {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		srcPath := filepath.Join(themePath, mapping.ThemePath)
+		if err := copyMappedFile(srcPath, mapping.SystemPath, logger); err != nil {
+			logger.DebugFn("Warning: Failed to copy game art %s: %v", asset, err)
+		}
+	}
+
 	// Process settings mappings
+	caps := GetDeviceCapabilities()
 	for settingType, mapping := range manifest.PathMappings.Settings {
+		if err := nextApplyFile(ctx, filepath.Base(mapping.ThemePath)); err != nil {
+			return err
+		}
+
+		// Other NextUI settings are only restored for files the user allowlisted
+		if !isSnapshotRestoreAllowed(mapping) {
+			logger.DebugFn("Skipping settings file %s, it isn't on the settings allowlist", settingType)
+			continue
+		}
+
+		// LED settings mean nothing without the LED hardware
+		if !isSettingsFileSupported(mapping.SystemPath, caps) {
+			logger.DebugFn("Skipping settings file %s, this device has no LED hardware", settingType)
+			reportUnsupported("LED settings")
+			continue
+		}
+
 		srcPath := filepath.Join(themePath, mapping.ThemePath)
 		dstPath := mapping.SystemPath
 
@@ -212,6 +407,8 @@ func importThemeFiles(themePath string, manifest *ThemeManifest, systemPaths *sy
 
 // UpdateManifestFromThemeContent scans a theme directory and updates the manifest
 func UpdateManifestFromThemeContent(themePath string, manifest *ThemeManifest, systemPaths *system.SystemPaths, logger *Logger) error {
+	defer beginScanPass()()
+
 	// Update wallpapers
 	if err := updateWallpaperMappings(themePath, manifest, systemPaths, logger); err != nil {
 		logger.DebugFn("Warning: Error updating wallpaper mappings: %v", err)
@@ -232,6 +429,11 @@ func UpdateManifestFromThemeContent(themePath string, manifest *ThemeManifest, s
 		logger.DebugFn("Warning: Error updating font mappings: %v", err)
 	}
 
+	// Update game art
+	if err := updateGameArtMappings(themePath, manifest, logger); err != nil {
+		logger.DebugFn("Warning: Error updating game art mappings: %v", err)
+	}
+
 	// Write updated manifest back to file
 	return WriteManifest(themePath, manifest, logger)
 }
@@ -249,8 +451,8 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 
 	// Process system icons
 	systemIconsDir := filepath.Join(themePath, "Icons", "SystemIcons")
-	if _, err := os.Stat(systemIconsDir); err == nil {
-		entries, err := os.ReadDir(systemIconsDir)
+	if _, err := scanStat(systemIconsDir); err == nil {
+		entries, err := scanReadDir(systemIconsDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading system icons directory: %v", err)
 		} else {
@@ -279,30 +481,12 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 				var systemPath string
 				var metadata map[string]string
 
-				// Special case handling for predefined names
-				switch strings.TrimSuffix(entry.Name(), ".png") {
-				case "Recently Played":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "Recently Played.png")
-					metadata = map[string]string{
-						"SystemName": "Recently Played",
-						"IconType":   "System",
-					}
-
-				case "Tools":
-					// Get parent directory of Tools path since it includes tg5040
-					toolsParentDir := filepath.Dir(systemPaths.Tools)
-					systemPath = filepath.Join(toolsParentDir, ".media", "tg5040.png")
-					metadata = map[string]string{
-						"SystemName": "Tools",
-						"IconType":   "System",
-					}
-
-				case "Collections":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "Collections.png")
-					metadata = map[string]string{
-						"SystemName": "Collections",
-						"IconType":   "System",
-					}
+				// Special destinations such as Root and Tools come from the registry
+				special, isSpecial := findSpecialDestination(SpecialIcons(), entry.Name())
+				switch {
+				case isSpecial:
+					systemPath = special.SystemPath(systemPaths)
+					metadata = special.Metadata("IconType")
 
 				default:
 					// Check for system tag in filename
@@ -311,12 +495,12 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 						systemTag := matches[1]
 
 						// Skip if we've already processed an icon with this tag and priority handling is enabled
-						if processedSystemTags[systemTag] {
+						if processedSystemTags[system.NameKey(systemTag)] {
 							logger.DebugFn("Skipping duplicate system tag '%s' for icon: %s (already processed)",
 								systemTag, entry.Name())
 							continue
 						}
-						processedSystemTags[systemTag] = true
+						processedSystemTags[system.NameKey(systemTag)] = true
 
 						// Full system icon file name
 						iconName := entry.Name()
@@ -325,12 +509,9 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 						var exactSystemName string
 						var matchFound bool
 
-						for _, system := range systemPaths.Systems {
-							if system.Tag == systemTag {
-								exactSystemName = system.Name
-								matchFound = true
-								break
-							}
+						if system, ok := systemPaths.SystemForTag(systemTag); ok {
+							exactSystemName = system.Name
+							matchFound = true
 						}
 
 						// If no exact match found, use original name
@@ -373,8 +554,8 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 	// [Rest of function remains unchanged]
 	// Process tool icons
 	toolIconsDir := filepath.Join(themePath, "Icons", "ToolIcons")
-	if _, err := os.Stat(toolIconsDir); err == nil {
-		entries, err := os.ReadDir(toolIconsDir)
+	if _, err := scanStat(toolIconsDir); err == nil {
+		entries, err := scanReadDir(toolIconsDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading tool icons directory: %v", err)
 		} else {
@@ -396,7 +577,7 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 				}
 
 				// Extract tool name
-				toolName := strings.TrimSuffix(entry.Name(), ".png")
+				toolName := strings.TrimSuffix(originalFileName(manifest.FileNames, themePath), ".png")
 				systemPath := filepath.Join(systemPaths.Tools, toolName, ".media", toolName+".png")
 				metadata := map[string]string{
 					"ToolName": toolName,
@@ -419,8 +600,8 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 
 	// Process collection icons
 	collectionIconsDir := filepath.Join(themePath, "Icons", "CollectionIcons")
-	if _, err := os.Stat(collectionIconsDir); err == nil {
-		entries, err := os.ReadDir(collectionIconsDir)
+	if _, err := scanStat(collectionIconsDir); err == nil {
+		entries, err := scanReadDir(collectionIconsDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading collection icons directory: %v", err)
 		} else {
@@ -442,7 +623,7 @@ func updateIconMappings(themePath string, manifest *ThemeManifest, systemPaths *
 				}
 
 				// Extract collection name
-				collectionName := strings.TrimSuffix(entry.Name(), ".png")
+				collectionName := strings.TrimSuffix(originalFileName(manifest.FileNames, themePath), ".png")
 				systemPath := filepath.Join(systemPaths.Root, "Collections", collectionName, ".media", collectionName+".png")
 				metadata := map[string]string{
 					"CollectionName": collectionName,
@@ -483,13 +664,13 @@ func updateOverlayMappings(themePath string, manifest *ThemeManifest, systemPath
 
 	// Process overlay directories
 	overlaysDir := filepath.Join(themePath, "Overlays")
-	if _, err := os.Stat(overlaysDir); os.IsNotExist(err) {
+	if _, err := scanStat(overlaysDir); os.IsNotExist(err) {
 		logger.DebugFn("No Overlays directory found in theme")
 		return nil
 	}
 
 	// List system directories
-	entries, err := os.ReadDir(overlaysDir)
+	entries, err := scanReadDir(overlaysDir)
 	if err != nil {
 		logger.DebugFn("Error reading Overlays directory: %v", err)
 		return err
@@ -505,7 +686,7 @@ func updateOverlayMappings(themePath string, manifest *ThemeManifest, systemPath
 		systemOverlaysPath := filepath.Join(overlaysDir, systemTag)
 
 		// List overlay files for this system
-		overlayFiles, err := os.ReadDir(systemOverlaysPath)
+		overlayFiles, err := scanReadDir(systemOverlaysPath)
 		if err != nil {
 			logger.DebugFn("Error reading system overlays directory %s: %v", systemTag, err)
 			continue
@@ -540,10 +721,7 @@ func updateOverlayMappings(themePath string, manifest *ThemeManifest, systemPath
 				PathMapping{
 					ThemePath:  themePath,
 					SystemPath: systemPath,
-					Metadata: map[string]string{
-						"SystemTag":   systemTag,
-						"OverlayName": file.Name(),
-					},
+					Metadata:   overlayMetadata(systemTag, file.Name(), nil),
 				},
 			)
 
@@ -558,7 +736,7 @@ func updateOverlayMappings(themePath string, manifest *ThemeManifest, systemPath
 			// Check if system is already in the list
 			var systemExists bool
 			for _, sys := range manifest.Content.Overlays.Systems {
-				if sys == systemTag {
+				if system.SameName(sys, systemTag) {
 					systemExists = true
 					break
 				}
@@ -592,7 +770,7 @@ func updateFontMappings(themePath string, manifest *ThemeManifest, systemPaths *
 
 	// Check for fonts directory
 	fontsDir := filepath.Join(themePath, "Fonts")
-	if _, err := os.Stat(fontsDir); os.IsNotExist(err) {
+	if _, err := scanStat(fontsDir); os.IsNotExist(err) {
 		logger.DebugFn("No Fonts directory found in theme")
 		return nil
 	}
@@ -608,7 +786,7 @@ func updateFontMappings(themePath string, manifest *ThemeManifest, systemPaths *
 	// Check for each font file
 	for _, fontFile := range fontFiles {
 		fontPath := filepath.Join(fontsDir, fontFile)
-		if _, err := os.Stat(fontPath); err == nil {
+		if _, err := scanStat(fontPath); err == nil {
 			// Font file exists
 			fontName := strings.TrimSuffix(fontFile, ".ttf")
 
@@ -645,8 +823,8 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 
 	// Process system wallpapers
 	systemWallpapersDir := filepath.Join(themePath, "Wallpapers", "SystemWallpapers")
-	if _, err := os.Stat(systemWallpapersDir); err == nil {
-		entries, err := os.ReadDir(systemWallpapersDir)
+	if _, err := scanStat(systemWallpapersDir); err == nil {
+		entries, err := scanReadDir(systemWallpapersDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading system wallpapers directory: %v", err)
 		} else {
@@ -671,42 +849,12 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 				var systemPath string
 				var metadata map[string]string
 
-				// Special case handling for predefined names
-				switch strings.TrimSuffix(entry.Name(), ".png") {
-				case "Root":
-					systemPath = filepath.Join(systemPaths.Root, "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Main",
-					}
-
-				case "Root-Media":
-					systemPath = filepath.Join(systemPaths.Root, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Root",
-						"WallpaperType": "Media",
-					}
-
-				case "Recently Played":
-					systemPath = filepath.Join(systemPaths.RecentlyPlayed, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Recently Played",
-						"WallpaperType": "Media",
-					}
-
-				case "Tools":
-					systemPath = filepath.Join(systemPaths.Tools, ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Tools",
-						"WallpaperType": "Media",
-					}
-
-				case "Collections":
-					systemPath = filepath.Join(systemPaths.Root, "Collections", ".media", "bg.png")
-					metadata = map[string]string{
-						"SystemName":    "Collections",
-						"WallpaperType": "Media",
-					}
+				// Special destinations such as Root and Tools come from the registry
+				special, isSpecial := findSpecialDestination(SpecialWallpapers(), entry.Name())
+				switch {
+				case isSpecial:
+					systemPath = special.SystemPath(systemPaths)
+					metadata = special.Metadata("WallpaperType")
 
 				default:
 					// Check for system tag in filename
@@ -721,17 +869,14 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 
 						// Find matching system by tag
 						var systemFound bool
-						for _, system := range systemPaths.Systems {
-							if system.Tag == systemTag {
-								systemPath = filepath.Join(system.MediaPath, "bg.png")
-								metadata = map[string]string{
-									"SystemName":    systemName,
-									"SystemTag":     systemTag,
-									"WallpaperType": "System",
-								}
-								systemFound = true
-								break
+						if system, ok := systemPaths.SystemForTag(systemTag); ok {
+							systemPath = filepath.Join(system.MediaPath, "bg.png")
+							metadata = map[string]string{
+								"SystemName":    systemName,
+								"SystemTag":     systemTag,
+								"WallpaperType": "System",
 							}
+							systemFound = true
 						}
 
 						// If system not found in paths, create a default path
@@ -767,8 +912,8 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 
 	// NEW: Process list wallpapers
 	listWallpapersDir := filepath.Join(themePath, "Wallpapers", "ListWallpapers")
-	if _, err := os.Stat(listWallpapersDir); err == nil {
-		entries, err := os.ReadDir(listWallpapersDir)
+	if _, err := scanStat(listWallpapersDir); err == nil {
+		entries, err := scanReadDir(listWallpapersDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading list wallpapers directory: %v", err)
 		} else {
@@ -810,29 +955,26 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 
 					// Find matching system by tag
 					var systemFound bool
-					for _, system := range systemPaths.Systems {
-						if system.Tag == systemTag {
-							systemPath := filepath.Join(system.MediaPath, "bglist.png")
-							metadata := map[string]string{
-								"SystemName":    systemName,
-								"SystemTag":     systemTag,
-								"WallpaperType": "List",
-							}
-
-							manifest.PathMappings.Wallpapers = append(
-								manifest.PathMappings.Wallpapers,
-								PathMapping{
-									ThemePath:  themePath,
-									SystemPath: systemPath,
-									Metadata:   metadata,
-								},
-							)
-							manifest.Content.Wallpapers.Count++
-							manifest.Content.Wallpapers.Present = true
-							systemFound = true
-							logger.DebugFn("Added mapping for list wallpaper: %s -> %s", themePath, systemPath)
-							break
+					if system, ok := systemPaths.SystemForTag(systemTag); ok {
+						systemPath := filepath.Join(system.MediaPath, "bglist.png")
+						metadata := map[string]string{
+							"SystemName":    systemName,
+							"SystemTag":     systemTag,
+							"WallpaperType": "List",
 						}
+
+						manifest.PathMappings.Wallpapers = append(
+							manifest.PathMappings.Wallpapers,
+							PathMapping{
+								ThemePath:  themePath,
+								SystemPath: systemPath,
+								Metadata:   metadata,
+							},
+						)
+						manifest.Content.Wallpapers.Count++
+						manifest.Content.Wallpapers.Present = true
+						systemFound = true
+						logger.DebugFn("Added mapping for list wallpaper: %s -> %s", themePath, systemPath)
 					}
 
 					// If system not found in paths, create a default path
@@ -865,8 +1007,8 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 
 	// Process collection wallpapers
 	collectionWallpapersDir := filepath.Join(themePath, "Wallpapers", "CollectionWallpapers")
-	if _, err := os.Stat(collectionWallpapersDir); err == nil {
-		entries, err := os.ReadDir(collectionWallpapersDir)
+	if _, err := scanStat(collectionWallpapersDir); err == nil {
+		entries, err := scanReadDir(collectionWallpapersDir)
 		if err != nil {
 			logger.DebugFn("Warning: Error reading collection wallpapers directory: %v", err)
 		} else {
@@ -888,7 +1030,7 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 				}
 
 				// Determine collection name and system path
-				collectionName := strings.TrimSuffix(entry.Name(), ".png")
+				collectionName := strings.TrimSuffix(originalFileName(manifest.FileNames, themePath), ".png")
 				systemPath := filepath.Join(systemPaths.Root, "Collections", collectionName, ".media", "bg.png")
 
 				metadata := map[string]string{
@@ -911,6 +1053,11 @@ func updateWallpaperMappings(themePath string, manifest *ThemeManifest, systemPa
 		}
 	}
 
+	// Wallpapers for Tools subfolders and custom categories
+	folderMappings := scanFolderWallpapers(themePath, "Wallpapers", existingMappings, systemPaths, logger)
+	manifest.PathMappings.Wallpapers = append(manifest.PathMappings.Wallpapers, folderMappings...)
+	manifest.Content.Wallpapers.Count += len(folderMappings)
+
 	// If we found any wallpapers, mark wallpapers as present
 	if manifest.Content.Wallpapers.Count > 0 {
 		manifest.Content.Wallpapers.Present = true
@@ -924,7 +1071,7 @@ func updateAccentSettings(themePath string, manifest *ThemeManifest, logger *Log
 	settingsPath := filepath.Join(themePath, "Settings", "minuisettings.txt")
 
 	// Check if settings file exists
-	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+	if _, err := scanStat(settingsPath); os.IsNotExist(err) {
 		logger.DebugFn("Accent settings file not found: %s", settingsPath)
 		return nil
 	}
@@ -980,7 +1127,7 @@ func updateLEDSettings(themePath string, manifest *ThemeManifest, logger *Logger
 	settingsPath := filepath.Join(themePath, "Settings", "ledsettings_brick.txt")
 
 	// Check if settings file exists
-	if _, err := os.Stat(settingsPath); os.IsNotExist(err) {
+	if _, err := scanStat(settingsPath); os.IsNotExist(err) {
 		logger.DebugFn("LED settings file not found: %s", settingsPath)
 		return nil
 	}
@@ -1071,12 +1218,12 @@ func applyAccentSettings(manifest *ThemeManifest, logger *Logger) error {
 
 	// Map of color keys to their values from the manifest
 	colorValues := map[string]string{
-		"color1": manifest.AccentColors.Color1,
-		"color2": manifest.AccentColors.Color2,
-		"color3": manifest.AccentColors.Color3,
-		"color4": manifest.AccentColors.Color4,
-		"color5": manifest.AccentColors.Color5,
-		"color6": manifest.AccentColors.Color6,
+		"color1": settingsColor(manifest.AccentColors.Color1, logger),
+		"color2": settingsColor(manifest.AccentColors.Color2, logger),
+		"color3": settingsColor(manifest.AccentColors.Color3, logger),
+		"color4": settingsColor(manifest.AccentColors.Color4, logger),
+		"color5": settingsColor(manifest.AccentColors.Color5, logger),
+		"color6": settingsColor(manifest.AccentColors.Color6, logger),
 	}
 
 	// Track which color keys we've seen
@@ -1177,8 +1324,8 @@ func applyLEDSettings(manifest *ThemeManifest, logger *Logger) error {
 	// F1 Key
 	content.WriteString("[F1 key]\n")
 	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.F1Key.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.F1Key.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.F1Key.Color2))
+	content.WriteString(fmt.Sprintf("color1=%s\n", settingsColor(manifest.LEDSettings.F1Key.Color1, logger)))
+	content.WriteString(fmt.Sprintf("color2=%s\n", settingsColor(manifest.LEDSettings.F1Key.Color2, logger)))
 	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.F1Key.Speed))
 	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.F1Key.Brightness))
 	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.F1Key.Trigger))
@@ -1188,8 +1335,8 @@ func applyLEDSettings(manifest *ThemeManifest, logger *Logger) error {
 	// F2 Key
 	content.WriteString("[F2 key]\n")
 	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.F2Key.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.F2Key.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.F2Key.Color2))
+	content.WriteString(fmt.Sprintf("color1=%s\n", settingsColor(manifest.LEDSettings.F2Key.Color1, logger)))
+	content.WriteString(fmt.Sprintf("color2=%s\n", settingsColor(manifest.LEDSettings.F2Key.Color2, logger)))
 	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.F2Key.Speed))
 	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.F2Key.Brightness))
 	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.F2Key.Trigger))
@@ -1199,8 +1346,8 @@ func applyLEDSettings(manifest *ThemeManifest, logger *Logger) error {
 	// Top bar
 	content.WriteString("[Top bar]\n")
 	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.TopBar.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.TopBar.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.TopBar.Color2))
+	content.WriteString(fmt.Sprintf("color1=%s\n", settingsColor(manifest.LEDSettings.TopBar.Color1, logger)))
+	content.WriteString(fmt.Sprintf("color2=%s\n", settingsColor(manifest.LEDSettings.TopBar.Color2, logger)))
 	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.TopBar.Speed))
 	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.TopBar.Brightness))
 	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.TopBar.Trigger))
@@ -1210,8 +1357,8 @@ func applyLEDSettings(manifest *ThemeManifest, logger *Logger) error {
 	// L&R triggers
 	content.WriteString("[L&R triggers]\n")
 	content.WriteString(fmt.Sprintf("effect=%d\n", manifest.LEDSettings.LRTriggers.Effect))
-	content.WriteString(fmt.Sprintf("color1=%s\n", manifest.LEDSettings.LRTriggers.Color1))
-	content.WriteString(fmt.Sprintf("color2=%s\n", manifest.LEDSettings.LRTriggers.Color2))
+	content.WriteString(fmt.Sprintf("color1=%s\n", settingsColor(manifest.LEDSettings.LRTriggers.Color1, logger)))
+	content.WriteString(fmt.Sprintf("color2=%s\n", settingsColor(manifest.LEDSettings.LRTriggers.Color2, logger)))
 	content.WriteString(fmt.Sprintf("speed=%d\n", manifest.LEDSettings.LRTriggers.Speed))
 	content.WriteString(fmt.Sprintf("brightness=%d\n", manifest.LEDSettings.LRTriggers.Brightness))
 	content.WriteString(fmt.Sprintf("trigger=%d\n", manifest.LEDSettings.LRTriggers.Trigger))
@@ -1269,8 +1416,24 @@ func cleanupExistingComponents(manifest *ThemeManifest, systemPaths *system.Syst
 	return nil
 }
 
-// copyMappedFile copies a file from source to destination with appropriate checks
+// copyMappedFile copies a file from source to destination with appropriate checks, and to
+// the same system's folder on other storage roots
 func copyMappedFile(srcPath, dstPath string, logger *Logger) error {
+	if err := copyMappedFileOnce(srcPath, dstPath, logger); err != nil {
+		return err
+	}
+	mirrorToStorageRoots(srcPath, dstPath, logger)
+	return nil
+}
+
+// copyMappedFileOnce copies a file from source to a single destination
+func copyMappedFileOnce(srcPath, dstPath string, logger *Logger) error {
+	// Never overwrite a file the user pinned
+	if isPinnedDestination(dstPath) {
+		logger.DebugFn("Skipping pinned destination: %s", dstPath)
+		return fmt.Errorf("%s: %w", dstPath, errPinnedFile)
+	}
+
 	// Check if source file exists
 	if _, err := os.Stat(srcPath); os.IsNotExist(err) {
 		logger.DebugFn("Source file does not exist: %s", srcPath)
@@ -1284,12 +1447,34 @@ func copyMappedFile(srcPath, dstPath string, logger *Logger) error {
 		return fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
+	// Link instead of copying when the user opted in and the filesystem allows it
+	if canLinkFile(dstPath) {
+		if err := linkMappedFile(srcPath, dstPath, logger); err == nil {
+			logger.DebugFn("Linked file: %s -> %s", srcPath, dstPath)
+			return nil
+		}
+	}
+
+	// Never write through a hard link into the package it points at
+	if isLinkedFile(dstPath) {
+		os.Remove(dstPath)
+	}
+
 	// Copy the file
 	if err := CopyFile(srcPath, dstPath); err != nil {
 		logger.DebugFn("Failed to copy file: %v", err)
 		return fmt.Errorf("failed to copy file: %w", err)
 	}
 
+	// Remember the file so later cleanups know the manager wrote it
+	recordManagedFile(dstPath)
+
+	// Read the copy back when the user asked for verified writes
+	if err := verifyCopiedFile(srcPath, dstPath, logger); err != nil {
+		logger.DebugFn("Warning: Corrupt write: %v", err)
+		return fmt.Errorf("failed to verify file: %w", err)
+	}
+
 	logger.DebugFn("Copied file: %s -> %s", srcPath, dstPath)
 	return nil
 }
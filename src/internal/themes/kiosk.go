@@ -0,0 +1,80 @@
+// src/internal/themes/kiosk.go
+// Kiosk mode, which locks the manager to browsing and previews
+
+package themes
+
+import (
+	"fmt"
+
+	"nextui-themes/internal/logging"
+)
+
+// scheduledApply is set while an apply the owner scheduled, such as a seasonal theme, runs,
+// so kiosk mode doesn't stop it
+var scheduledApply bool
+
+// GetKioskMode reports whether kiosk mode is on
+func GetKioskMode() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load kiosk mode setting: %v", err)
+		return false
+	}
+	return config.KioskMode
+}
+
+// KioskHasPIN reports whether leaving kiosk mode asks for a PIN
+func KioskHasPIN() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load kiosk mode setting: %v", err)
+		return false
+	}
+	return config.KioskPIN != ""
+}
+
+// EnableKioskMode turns kiosk mode on. With a PIN, leaving it asks for the same button
+// sequence; without one, anyone can leave it from the main menu.
+func EnableKioskMode(pin []string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.KioskMode = true
+	config.KioskPIN = ""
+	if len(pin) > 0 {
+		config.KioskPIN = hashPIN(pin)
+	}
+	logging.LogDebug("Kiosk mode enabled (PIN: %t)", config.KioskPIN != "")
+	return SaveConfig(config)
+}
+
+// DisableKioskMode turns kiosk mode off, returning ErrWrongPIN if pin doesn't match
+func DisableKioskMode(pin []string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	if config.KioskPIN != "" && hashPIN(pin) != config.KioskPIN {
+		logging.LogDebug("Wrong PIN entered to leave kiosk mode")
+		return ErrWrongPIN
+	}
+
+	config.KioskMode = false
+	config.KioskPIN = ""
+	logging.LogDebug("Kiosk mode disabled")
+	return SaveConfig(config)
+}
+
+// CheckKioskMode returns an error if kiosk mode is on, so nothing is applied, restored or
+// deleted from a locked device. Scheduled applies still run.
+func CheckKioskMode(operation string) error {
+	if scheduledApply || !GetKioskMode() {
+		return nil
+	}
+
+	logging.LogDebug("Refusing %s in kiosk mode", operation)
+	return fmt.Errorf("%s is locked in kiosk mode. Leave kiosk mode from the main menu first", operation)
+}
@@ -0,0 +1,141 @@
+// src/internal/themes/led_battery.go
+// Battery-reactive LED packs: different colors and effects per charge band, updated at boot
+
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// LEDBatteryBand replaces a pack's LED settings while the battery is at or below MaxPercent
+type LEDBatteryBand struct {
+	MaxPercent  int      `json:"max_percent"`
+	LEDSettings LEDZones `json:"led_settings"`
+}
+
+// sortLEDBatteryBands orders bands from the lowest charge up, the order they are matched in
+func sortLEDBatteryBands(bands []LEDBatteryBand) {
+	sort.SliceStable(bands, func(i, j int) bool {
+		return bands[i].MaxPercent < bands[j].MaxPercent
+	})
+}
+
+// ledBatteryBand returns the band for a charge level, or nil when the pack's own settings apply
+func ledBatteryBand(bands []LEDBatteryBand, percent int) *LEDBatteryBand {
+	var match *LEDBatteryBand
+	for i := range bands {
+		if percent <= bands[i].MaxPercent && (match == nil || bands[i].MaxPercent < match.MaxPercent) {
+			match = &bands[i]
+		}
+	}
+	return match
+}
+
+// ledZonesForBattery returns the settings of an LED pack for the current battery state: the
+// charging profile while plugged in, otherwise the band for the charge left. A device without
+// a readable battery uses the pack's own settings.
+func ledZonesForBattery(manifest *LEDManifest) LEDZones {
+	if len(manifest.BatteryBands) == 0 && manifest.Charging == nil {
+		return manifest.LEDSettings
+	}
+
+	status, err := system.GetBatteryStatus()
+	if err != nil {
+		logging.LogDebug("Could not read battery level, using the pack's LED settings: %v", err)
+		return manifest.LEDSettings
+	}
+	if status.Charging {
+		if manifest.Charging != nil {
+			logging.LogDebug("Device is charging, using the pack's charging LEDs")
+			return *manifest.Charging
+		}
+		return manifest.LEDSettings
+	}
+
+	band := ledBatteryBand(manifest.BatteryBands, status.Percent)
+	if band == nil {
+		return manifest.LEDSettings
+	}
+
+	logging.LogDebug("Battery at %d%%, using LED band up to %d%%", status.Percent, band.MaxPercent)
+	return band.LEDSettings
+}
+
+// isPowerReactive reports whether an LED pack changes with the battery or charging state
+func isPowerReactive(manifest *LEDManifest) bool {
+	return len(manifest.BatteryBands) > 0 || manifest.Charging != nil
+}
+
+// appliedLEDPackage returns the LED package whose settings are on the device, or "" when
+// a theme's LEDs were applied over it
+func appliedLEDPackage(global *GlobalManifest) string {
+	if global.CurrentTheme == "" {
+		return global.AppliedComponents.LEDs
+	}
+	for _, componentType := range global.AppliedSinceTheme {
+		if componentType == ComponentLED {
+			return global.AppliedComponents.LEDs
+		}
+	}
+	return ""
+}
+
+// loadPowerReactiveLEDs returns the manifest of the applied LED pack when it changes with the
+// battery or charging state, or nil
+func loadPowerReactiveLEDs(logger *Logger) *LEDManifest {
+	global, err := LoadGlobalManifest()
+	if err != nil {
+		logger.DebugFn("Warning: Could not load global manifest: %v", err)
+		return nil
+	}
+
+	name := appliedLEDPackage(global)
+	if name == "" {
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		logger.DebugFn("Warning: Could not get current directory: %v", err)
+		return nil
+	}
+
+	manifestObj, err := LoadComponentManifest(filepath.Join(cwd, "Components", ComponentDirectory[ComponentLED], name))
+	if err != nil {
+		logger.DebugFn("Warning: Could not load applied LED pack %s: %v", name, err)
+		return nil
+	}
+
+	manifest, ok := manifestObj.(*LEDManifest)
+	if !ok || !isPowerReactive(manifest) {
+		return nil
+	}
+	return manifest
+}
+
+// UpdateBatteryLEDs writes the settings of the applied LED pack for the current battery level
+// and charging state. Run from NextUI's auto.sh with --battery-leds; the boot hook goes away
+// once no battery-reactive pack is applied.
+func UpdateBatteryLEDs() error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	manifest := loadPowerReactiveLEDs(logger)
+	if manifest == nil {
+		setBootHook("battery-leds", "--battery-leds", false, logger)
+		return nil
+	}
+
+	if err := writeLEDSettingsFile(ledZonesForBattery(manifest)); err != nil {
+		return err
+	}
+
+	logger.DebugFn("Updated battery LEDs from %s", manifest.ComponentInfo.Name)
+	return nil
+}
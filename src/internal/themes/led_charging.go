@@ -0,0 +1,82 @@
+// src/internal/themes/led_charging.go
+// Charging LED profiles: switches an LED pack to its charging animation when the device is plugged in
+
+package themes
+
+import (
+	"time"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// Device constraints on the Brick:
+//   - NextUI reads ledsettings_brick.txt only when it starts. Its trigger values 1-14 pick
+//     the button that plays a zone's effect; there is no trigger for charging, low battery or
+//     temperature. Charging and low battery are shown by NextUI itself on the top bar, at the
+//     zone's in_brightness.
+//   - The LED driver exposes no temperature reading, so packs can only react to the charging
+//     state and the battery level.
+//
+// Power profiles are therefore applied by Theme Manager: the settings file is rewritten with
+// the profile's trigger values, and pushed to /sys/class/led_anim so the change shows without
+// restarting NextUI. NextUI may take the LEDs over again for its own indicators.
+const (
+	ledTriggerMin = 1  // First button trigger
+	ledTriggerMax = 14 // Last button trigger
+)
+
+// chargingPollInterval is how often the charging watcher reads the battery state
+const chargingPollInterval = 10 * time.Second
+
+// ledTrigger returns a trigger value NextUI accepts, falling back to the first button
+func ledTrigger(trigger int) int {
+	if trigger < ledTriggerMin || trigger > ledTriggerMax {
+		return ledTriggerMin
+	}
+	return trigger
+}
+
+// WatchChargingLEDs switches the applied LED pack between its charging profile and its other
+// settings whenever the device is plugged in or unplugged. Started in the background from
+// NextUI's auto.sh with --watch-charging; it returns once the applied pack has no charging
+// profile, removing its boot hook.
+func WatchChargingLEDs() error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	charging := false
+	first := true
+	for {
+		status, err := system.GetBatteryStatus()
+		if err != nil {
+			// No battery to watch on this device
+			logger.DebugFn("Stopping charging LED watcher: %v", err)
+			return nil
+		}
+
+		if first || status.Charging != charging {
+			first = false
+			charging = status.Charging
+
+			manifest := loadPowerReactiveLEDs(logger)
+			if manifest == nil || manifest.Charging == nil {
+				setBackgroundBootHook("charging-leds", "--watch-charging", false, logger)
+				logger.DebugFn("Applied LED pack has no charging profile, stopping watcher")
+				return nil
+			}
+
+			zones := ledZonesForBattery(manifest)
+			if err := writeLEDSettingsFile(zones); err != nil {
+				logger.DebugFn("Warning: Could not write LED settings: %v", err)
+			}
+			if err := pushLEDSettings(&LEDManifest{LEDSettings: zones}); err != nil {
+				logger.DebugFn("Warning: Could not update LEDs: %v", err)
+			}
+			logger.DebugFn("Charging state changed (charging: %v), LEDs updated", charging)
+		}
+
+		time.Sleep(chargingPollInterval)
+	}
+}
@@ -0,0 +1,246 @@
+// src/internal/themes/led_preview.go
+// Live LED preview: pushes a pack's settings to the hardware without saving them
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ledAnimPath is the sysfs directory that drives the Brick's LEDs
+const ledAnimPath = "/sys/class/led_anim"
+
+// ledSettingsPath is the saved LED configuration read by NextUI on startup
+const ledSettingsPath = "/mnt/SDCARD/.userdata/shared/ledsettings_brick.txt"
+
+// ledPreview tracks the settings to restore when a preview is abandoned
+var ledPreview struct {
+	active   bool
+	original *LEDManifest
+}
+
+// loadLEDManifestFile reads an LED manifest from a manifest.json path
+func loadLEDManifestFile(manifestPath string) (*LEDManifest, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading LED manifest: %w", err)
+	}
+
+	var manifest LEDManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing LED manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// loadCurrentLEDSettings parses the saved LED settings file
+func loadCurrentLEDSettings() (*LEDManifest, error) {
+	content, err := os.ReadFile(ledSettingsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading LED settings file: %w", err)
+	}
+
+	manifest := &LEDManifest{}
+	var currentLED *LEDSetting
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		// Check for section header [X]
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			switch line[1 : len(line)-1] {
+			case "F1 key":
+				currentLED = &manifest.LEDSettings.F1Key
+			case "F2 key":
+				currentLED = &manifest.LEDSettings.F2Key
+			case "Top bar":
+				currentLED = &manifest.LEDSettings.TopBar
+			case "L&R triggers":
+				currentLED = &manifest.LEDSettings.LRTriggers
+			default:
+				currentLED = nil
+			}
+			continue
+		}
+
+		if currentLED == nil {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "effect":
+			currentLED.Effect, _ = strconv.Atoi(value)
+		case "color1":
+			currentLED.Color1 = value
+		case "color2":
+			currentLED.Color2 = value
+		case "speed":
+			currentLED.Speed, _ = strconv.Atoi(value)
+		case "brightness":
+			currentLED.Brightness, _ = strconv.Atoi(value)
+		case "trigger":
+			currentLED.Trigger, _ = strconv.Atoi(value)
+		case "inbrightness":
+			currentLED.InBrightness, _ = strconv.Atoi(value)
+		}
+	}
+
+	return manifest, nil
+}
+
+// formatLEDSettings renders LED zones in the format of the saved LED settings file
+func formatLEDSettings(zones LEDZones) string {
+	sections := []struct {
+		name    string
+		setting LEDSetting
+	}{
+		{"F1 key", zones.F1Key},
+		{"F2 key", zones.F2Key},
+		{"Top bar", zones.TopBar},
+		{"L&R triggers", zones.LRTriggers},
+	}
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	var content strings.Builder
+	for _, section := range sections {
+		content.WriteString(fmt.Sprintf("[%s]\n", section.name))
+		content.WriteString(fmt.Sprintf("effect=%d\n", section.setting.Effect))
+		content.WriteString(fmt.Sprintf("color1=%s\n", settingsColor(section.setting.Color1, logger)))
+		content.WriteString(fmt.Sprintf("color2=%s\n", settingsColor(section.setting.Color2, logger)))
+		content.WriteString(fmt.Sprintf("speed=%d\n", section.setting.Speed))
+		content.WriteString(fmt.Sprintf("brightness=%d\n", section.setting.Brightness))
+		content.WriteString(fmt.Sprintf("trigger=%d\n", ledTrigger(section.setting.Trigger)))
+		content.WriteString(fmt.Sprintf("inbrightness=%d\n", section.setting.InBrightness))
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+// writeLEDSettingsFile saves LED zones as the settings NextUI loads on startup
+func writeLEDSettingsFile(zones LEDZones) error {
+	if err := os.WriteFile(ledSettingsPath, []byte(formatLEDSettings(zones)), 0644); err != nil {
+		return fmt.Errorf("error writing LED settings: %w", err)
+	}
+	return nil
+}
+
+// writeLEDAttribute writes a single sysfs attribute
+func writeLEDAttribute(name, value string) error {
+	return os.WriteFile(filepath.Join(ledAnimPath, name), []byte(value), 0644)
+}
+
+// pushLEDSetting sends one LED zone's settings to the hardware
+func pushLEDSetting(zone, scaleAttr string, setting LEDSetting) error {
+	color, err := sysfsColor(setting.Color1)
+	if err != nil {
+		return err
+	}
+
+	attributes := []struct {
+		name  string
+		value string
+	}{
+		{scaleAttr, strconv.Itoa(setting.Brightness)},
+		{"effect_rgb_hex_" + zone, color},
+		{"effect_duration_" + zone, strconv.Itoa(setting.Speed)},
+		{"effect_cycles_" + zone, "-1"},
+		{"effect_" + zone, strconv.Itoa(setting.Effect)},
+	}
+
+	for _, attr := range attributes {
+		if err := writeLEDAttribute(attr.name, attr.value); err != nil {
+			return fmt.Errorf("error writing %s: %w", attr.name, err)
+		}
+	}
+
+	return nil
+}
+
+// pushLEDSettings sends every LED zone in the manifest to the hardware
+func pushLEDSettings(manifest *LEDManifest) error {
+	if _, err := os.Stat(ledAnimPath); err != nil {
+		return fmt.Errorf("LED hardware not available: %w", err)
+	}
+
+	zones := []struct {
+		zone      string
+		scaleAttr string
+		setting   LEDSetting
+	}{
+		{"f1", "max_scale_f1f2", manifest.LEDSettings.F1Key},
+		{"f2", "max_scale_f1f2", manifest.LEDSettings.F2Key},
+		{"m", "max_scale", manifest.LEDSettings.TopBar},
+		{"lr", "max_scale_lr", manifest.LEDSettings.LRTriggers},
+	}
+
+	for _, z := range zones {
+		if err := pushLEDSetting(z.zone, z.scaleAttr, z.setting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PreviewLEDManifest shows an LED pack on the hardware without saving it.
+// The first preview remembers the saved settings so RevertLEDPreview can restore them.
+func PreviewLEDManifest(manifestPath string) error {
+	if !ledPreview.active {
+		original, err := loadCurrentLEDSettings()
+		if err != nil {
+			return fmt.Errorf("error reading current LED settings: %w", err)
+		}
+		ledPreview.original = original
+		ledPreview.active = true
+	}
+
+	manifest, err := loadLEDManifestFile(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	logging.LogDebug("Previewing LED settings from %s", manifestPath)
+	return pushLEDSettings(manifest)
+}
+
+// RevertLEDPreview restores the saved LED settings on the hardware if a preview is active
+func RevertLEDPreview() {
+	if !ledPreview.active {
+		return
+	}
+
+	ledPreview.active = false
+	if err := pushLEDSettings(ledPreview.original); err != nil {
+		logging.LogDebug("Warning: Could not restore LED settings after preview: %v", err)
+		return
+	}
+
+	logging.LogDebug("Restored LED settings after preview")
+}
+
+// CommitLEDPreview ends a preview without restoring, used once the previewed pack is applied
+func CommitLEDPreview() {
+	ledPreview.active = false
+	ledPreview.original = nil
+}
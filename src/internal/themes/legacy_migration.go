@@ -0,0 +1,220 @@
+// src/internal/themes/legacy_migration.go
+// Converts themes using the legacy per-folder wallpaper layout to the current layout
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// Wallpaper folders used by the current theme layout
+var currentWallpaperDirs = map[string]bool{
+	"SystemWallpapers":     true,
+	"ListWallpapers":       true,
+	"CollectionWallpapers": true,
+	folderWallpapersDir:    true,
+}
+
+// legacyWallpaperTarget returns where a file from the legacy layout belongs in the current
+// layout, relative to the theme. The legacy layout mirrors the device, e.g.
+// Wallpapers/Root/bg.png or Wallpapers/Game Boy Advance (GBA)/bglist.png.
+func legacyWallpaperTarget(relPath string) string {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	if len(parts) < 3 || parts[0] != "Wallpapers" || currentWallpaperDirs[parts[1]] {
+		return ""
+	}
+
+	folder := parts[1]
+	rest := strings.Join(parts[2:], "/")
+
+	switch {
+	case folder == "Root" && rest == "bg.png":
+		return "Wallpapers/SystemWallpapers/Root.png"
+	case folder == "Root" && rest == ".media/bg.png":
+		return "Wallpapers/SystemWallpapers/Root-Media.png"
+	case folder == "Recently Played" && (rest == "bg.png" || rest == ".media/bg.png"):
+		return "Wallpapers/SystemWallpapers/Recently Played.png"
+	case folder == "Tools" && (rest == "bg.png" || rest == ".media/bg.png"):
+		return "Wallpapers/SystemWallpapers/Tools.png"
+	case folder == "Collections" && (rest == "bg.png" || rest == ".media/bg.png"):
+		return "Wallpapers/SystemWallpapers/Collections.png"
+	case folder == "Collections" && len(parts) >= 4:
+		// Collections/<Name>/bg.png or Collections/<Name>/.media/bg.png
+		if name := parts[2]; strings.TrimPrefix(strings.Join(parts[3:], "/"), ".media/") == "bg.png" {
+			return "Wallpapers/CollectionWallpapers/" + name + ".png"
+		}
+	case systemTagFromName(folder) != "":
+		switch strings.TrimPrefix(rest, ".media/") {
+		case "bg.png":
+			return "Wallpapers/SystemWallpapers/" + folder + ".png"
+		case "bglist.png":
+			return "Wallpapers/ListWallpapers/" + folder + "-list.png"
+		}
+	}
+
+	return ""
+}
+
+// HasLegacyLayout reports whether a theme still uses the legacy wallpaper layout
+func HasLegacyLayout(themePath string) bool {
+	found := false
+	filepath.Walk(filepath.Join(themePath, "Wallpapers"), func(path string, info os.FileInfo, err error) error {
+		if err != nil || found || info.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(themePath, path); err == nil && legacyWallpaperTarget(rel) != "" {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// MigrateLegacyLayout moves legacy wallpapers into the current layout on disk and
+// points the manifest's mappings at the new files. It returns the number of files moved.
+func MigrateLegacyLayout(themePath string, logger *Logger) (int, error) {
+	wallpapersDir := filepath.Join(themePath, "Wallpapers")
+	if _, err := os.Stat(wallpapersDir); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	// Collect the moves first so the walk never sees files it has already moved
+	renames := make(map[string]string)
+	err := filepath.Walk(wallpapersDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(themePath, path)
+		if err != nil {
+			return err
+		}
+		if target := legacyWallpaperTarget(rel); target != "" {
+			renames[filepath.ToSlash(rel)] = target
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error scanning wallpapers: %w", err)
+	}
+
+	if len(renames) == 0 {
+		return 0, nil
+	}
+
+	logger.DebugFn("Migrating %d legacy wallpapers in %s", len(renames), themePath)
+
+	moved := make(map[string]string)
+	for oldRel, newRel := range renames {
+		oldPath := filepath.Join(themePath, oldRel)
+		newPath := filepath.Join(themePath, newRel)
+
+		if _, err := os.Stat(newPath); err == nil {
+			logger.DebugFn("Warning: Not migrating %s, %s already exists", oldRel, newRel)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+			return len(moved), fmt.Errorf("error creating directory for %s: %w", newRel, err)
+		}
+
+		if err := os.Rename(oldPath, newPath); err != nil {
+			return len(moved), fmt.Errorf("error moving %s: %w", oldRel, err)
+		}
+
+		moved[oldRel] = newRel
+		logger.DebugFn("Migrated legacy wallpaper: %s -> %s", oldRel, newRel)
+	}
+
+	removeEmptyLegacyDirs(wallpapersDir, logger)
+
+	// Keep existing mappings and their destinations, just point them at the moved files
+	manifest, err := ValidateTheme(themePath, logger)
+	if err != nil {
+		logger.DebugFn("Warning: Migrated files but could not load manifest: %v", err)
+		return len(moved), nil
+	}
+
+	for i, mapping := range manifest.PathMappings.Wallpapers {
+		if newRel, ok := moved[filepath.ToSlash(mapping.ThemePath)]; ok {
+			manifest.PathMappings.Wallpapers[i].ThemePath = newRel
+		}
+	}
+
+	if err := WriteManifest(themePath, manifest, logger); err != nil {
+		return len(moved), fmt.Errorf("error writing migrated manifest: %w", err)
+	}
+
+	return len(moved), nil
+}
+
+// removeEmptyLegacyDirs deletes legacy wallpaper folders left empty by a migration
+func removeEmptyLegacyDirs(wallpapersDir string, logger *Logger) {
+	entries, err := os.ReadDir(wallpapersDir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || currentWallpaperDirs[entry.Name()] {
+			continue
+		}
+
+		dir := filepath.Join(wallpapersDir, entry.Name())
+		hasFiles := false
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && info.Name() != ".DS_Store" {
+				hasFiles = true
+			}
+			return nil
+		})
+
+		if hasFiles {
+			logger.DebugFn("Leaving legacy folder with unrecognized files: %s", dir)
+			continue
+		}
+
+		if err := os.RemoveAll(dir); err != nil {
+			logger.DebugFn("Warning: Could not remove empty legacy folder %s: %v", dir, err)
+		}
+	}
+}
+
+// MigrateAllLegacyThemes migrates every installed theme still using the legacy layout.
+// It returns the number of themes migrated and the names of any that failed.
+func MigrateAllLegacyThemes() (int, []string, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return 0, nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	themePaths, err := filepath.Glob(filepath.Join(cwd, "Themes", "*.theme"))
+	if err != nil {
+		return 0, nil, fmt.Errorf("error listing themes: %w", err)
+	}
+
+	migrated := 0
+	var failed []string
+	for _, themePath := range themePaths {
+		if !HasLegacyLayout(themePath) {
+			continue
+		}
+
+		if _, err := MigrateLegacyLayout(themePath, logger); err != nil {
+			logger.DebugFn("Warning: Could not migrate %s: %v", filepath.Base(themePath), err)
+			failed = append(failed, filepath.Base(themePath))
+			continue
+		}
+		migrated++
+	}
+
+	return migrated, failed, nil
+}
@@ -0,0 +1,159 @@
+// src/internal/themes/license.go
+// License helpers for theme and component packages
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// restrictedLicenseTerms are license values that forbid redistributing a package
+var restrictedLicenseTerms = []string{
+	"all rights reserved",
+	"proprietary",
+	"no redistribution",
+	"no-redistribution",
+	"personal use",
+	"private use",
+}
+
+// IsRedistributable reports whether a package with the given license may be shared.
+// Packages without a license are treated as redistributable.
+func IsRedistributable(license string) bool {
+	normalized := strings.ToLower(strings.TrimSpace(license))
+	if normalized == "" {
+		return true
+	}
+
+	for _, term := range restrictedLicenseTerms {
+		if strings.Contains(normalized, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetComponentLicense returns the license declared in a component package's manifest
+func GetComponentLicense(componentPath string) string {
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return ""
+	}
+
+	if info := GetComponentInfo(manifestObj); info != nil {
+		return info.License
+	}
+
+	return ""
+}
+
+// componentsOnDevice returns the component types whose applied package is still what's on the
+// device. Applying a theme replaces them all but keeps their records, so after a theme only the
+// types applied over it count.
+func componentsOnDevice(manifest *GlobalManifest) []string {
+	if manifest.CurrentTheme != "" {
+		return append([]string(nil), manifest.AppliedSinceTheme...)
+	}
+
+	var componentTypes []string
+	for _, kind := range ComponentKinds() {
+		componentTypes = append(componentTypes, kind.Type())
+	}
+	return componentTypes
+}
+
+// getCurrentThemeLicense returns the license of the applied theme
+func getCurrentThemeLicense(manifest *GlobalManifest) string {
+	if manifest.CurrentTheme == "" {
+		return ""
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	logger := &Logger{DebugFn: logging.LogDebug}
+	themeManifest, err := ValidateTheme(filepath.Join(cwd, "Themes", manifest.CurrentTheme), logger)
+	if err != nil {
+		return ""
+	}
+	return themeManifest.ThemeInfo.License
+}
+
+// getInstalledComponentLicense returns the license of a component package in Components/
+func getInstalledComponentLicense(componentType string, componentName string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	return GetComponentLicense(filepath.Join(cwd, "Components", ComponentDirectory[componentType], componentName))
+}
+
+// getAppliedComponentLicense returns the license of what's on the device for a component type:
+// the component applied since the current theme, or else the theme it came with
+func getAppliedComponentLicense(componentType string) string {
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		return ""
+	}
+
+	for _, onDevice := range componentsOnDevice(manifest) {
+		if onDevice != componentType {
+			continue
+		}
+
+		componentName, err := GetAppliedComponent(componentType)
+		if err != nil || componentName == "" {
+			break
+		}
+		return getInstalledComponentLicense(componentType, componentName)
+	}
+
+	return getCurrentThemeLicense(manifest)
+}
+
+// checkExportLicenses lists the applied theme and the components applied over it whose
+// license forbids redistribution
+func checkExportLicenses(logger *Logger) []string {
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		logger.DebugFn("Warning: Could not load global manifest to check licenses: %v", err)
+		return nil
+	}
+
+	var restricted []string
+	if license := getCurrentThemeLicense(manifest); !IsRedistributable(license) {
+		logger.DebugFn("Warning: Applied theme '%s' has a restrictive license: %s", manifest.CurrentTheme, license)
+		restricted = append(restricted, fmt.Sprintf("%s (%s)", manifest.CurrentTheme, license))
+	}
+
+	for _, componentType := range componentsOnDevice(manifest) {
+		componentName, err := GetAppliedComponent(componentType)
+		if err != nil || componentName == "" {
+			continue
+		}
+
+		license := getInstalledComponentLicense(componentType, componentName)
+		if !IsRedistributable(license) {
+			logger.DebugFn("Warning: Applied %s component '%s' has a restrictive license: %s",
+				componentType, componentName, license)
+			restricted = append(restricted, fmt.Sprintf("%s (%s)", componentName, license))
+		}
+	}
+
+	return restricted
+}
+
+// RestrictedExportLicenses lists the applied theme and components that may not be
+// redistributed, as "name (license)", so an export can be confirmed before anything is written
+func RestrictedExportLicenses() []string {
+	return checkExportLicenses(&Logger{DebugFn: logging.LogDebug})
+}
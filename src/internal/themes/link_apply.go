@@ -0,0 +1,89 @@
+// src/internal/themes/link_apply.go
+// Optional hard-link apply: media files point at the package instead of being copied
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// linkApply tracks whether the apply in progress may use hard links
+var linkApply struct {
+	enabled bool
+}
+
+// GetHardLinkSetting reports whether the user turned on hard-link apply
+func GetHardLinkSetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load hard link setting: %v", err)
+		return false
+	}
+	return config.HardLinkApply
+}
+
+// SetHardLinkSetting stores the hard-link apply setting
+func SetHardLinkSetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.HardLinkApply = enabled
+	return SaveConfig(config)
+}
+
+// beginLinkApply reads the hard-link setting for a new apply
+func beginLinkApply() {
+	linkApply.enabled = GetHardLinkSetting()
+}
+
+// canLinkFile reports whether a destination should be hard-linked rather than copied.
+// Only images are linked; fonts and settings files are always copied.
+func canLinkFile(dstPath string) bool {
+	return linkApply.enabled && strings.EqualFold(filepath.Ext(dstPath), ".png")
+}
+
+// linkMappedFile hard-links srcPath to dstPath. If the filesystem can't do it (the
+// SD card is usually FAT32 or exFAT), links are turned off for the rest of the apply.
+func linkMappedFile(srcPath, dstPath string, logger *Logger) error {
+	// os.Link refuses to replace an existing file
+	if err := os.Remove(dstPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error replacing %s: %w", dstPath, err)
+	}
+
+	if err := os.Link(srcPath, dstPath); err != nil {
+		logger.DebugFn("Hard links not supported here, copying instead: %v", err)
+		linkApply.enabled = false
+		return err
+	}
+
+	recordLinkedFile(dstPath)
+	return nil
+}
+
+// detachHardLink replaces a hard-linked file with its own copy, so it can be
+// modified without also changing the package it came from
+func detachHardLink(path string) error {
+	if !isLinkedFile(path) {
+		return nil
+	}
+
+	tmpPath := path + ".tmp"
+	if err := CopyFile(path, tmpPath); err != nil {
+		return fmt.Errorf("error copying linked file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error replacing linked file: %w", err)
+	}
+
+	recordManagedFile(path)
+	return nil
+}
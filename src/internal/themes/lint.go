@@ -0,0 +1,242 @@
+// src/internal/themes/lint.go
+// Manifest linter that turns common packaging mistakes into a numbered fix list
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// LintIssue is a single problem found in a package manifest
+type LintIssue struct {
+	Problem string `json:"problem"` // What is wrong
+	Fix     string `json:"fix"`     // What the author should do about it
+}
+
+// linter collects issues while a package is checked
+type linter struct {
+	packagePath string
+	systems     map[string]bool // Installed system tags, nil when unknown
+	issues      []LintIssue
+	images      []ImageCheck
+	coverage    []MappingCoverage
+}
+
+// add records an issue
+func (l *linter) add(problem, fix string) {
+	l.issues = append(l.issues, LintIssue{Problem: problem, Fix: fix})
+}
+
+// LintPackage checks a .theme or component package for common manifest mistakes
+func LintPackage(packagePath string) ([]LintIssue, error) {
+	report, err := BuildLintReport(packagePath)
+	if err != nil {
+		return nil, err
+	}
+	return report.Issues, nil
+}
+
+// BuildLintReport checks a package like LintPackage and also returns the size of every
+// image it checked and how well each manifest section covers the package's files
+func BuildLintReport(packagePath string) (*LintReport, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	// Packages that only come with a manifest.yaml are linted as the manifest.json made from it
+	if err := ensureJSONManifest(packagePath, logger); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(packagePath, "manifest.json")); err != nil {
+		return nil, fmt.Errorf("no manifest.json or manifest.yaml found in %s", packagePath)
+	}
+
+	l := &linter{packagePath: packagePath}
+
+	// System checks only make sense when the systems can be read, i.e. on the device
+	if systemPaths, err := system.GetSystemPaths(); err == nil && len(systemPaths.Systems) > 0 {
+		l.systems = make(map[string]bool)
+		for _, sys := range systemPaths.Systems {
+			l.systems[sys.Tag] = true
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(packagePath, "preview.png")); err != nil {
+		l.add("preview.png is missing",
+			"Add a preview.png to the root of the package so it shows up in the gallery")
+	} else {
+		l.checkImage("preview", "preview.png", imageScreen)
+	}
+
+	ext := filepath.Ext(packagePath)
+	if ext == ".theme" {
+		manifest, err := ValidateTheme(packagePath, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		l.checkMappings("wallpapers", manifest.PathMappings.Wallpapers, imageScreen)
+		l.checkMappings("icons", manifest.PathMappings.Icons, imageSquare)
+		l.checkMappings("overlays", manifest.PathMappings.Overlays, imageScreen)
+		l.checkMappings("fonts", sortedMappings(manifest.PathMappings.Fonts), imageAny)
+		l.checkMappings("game_art", sortedMappings(manifest.PathMappings.GameArt), imageAny)
+		l.checkMappings("settings", sortedMappings(manifest.PathMappings.Settings), imageAny)
+
+		colors := manifest.AccentColors
+		l.checkAccentColors([6]string{colors.Color1, colors.Color2, colors.Color3, colors.Color4, colors.Color5, colors.Color6})
+		l.checkLEDColors("led_settings", manifest.LEDSettings)
+
+		return l.report(PackageTheme), nil
+	}
+
+	manifestObj, err := LoadComponentManifest(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	packageType := ""
+	info := GetComponentInfo(manifestObj)
+	if info != nil {
+		packageType = info.Type
+		if want := ComponentExtension[info.Type]; want != "" && want != ext {
+			l.add(fmt.Sprintf("Package ends in '%s' but its manifest says it is a %s component", ext, info.Type),
+				fmt.Sprintf("Rename the package folder to end in '%s'", want))
+		}
+	}
+
+	switch manifest := manifestObj.(type) {
+	case *WallpaperManifest:
+		l.checkMappings("path_mappings", manifest.PathMappings, imageScreen)
+	case *IconManifest:
+		l.checkMappings("path_mappings", manifest.PathMappings, imageSquare)
+	case *OverlayManifest:
+		l.checkMappings("path_mappings", manifest.PathMappings, imageScreen)
+	case *FontManifest:
+		l.checkMappings("path_mappings", sortedMappings(manifest.PathMappings), imageAny)
+	case *GameArtManifest:
+		l.checkMappings("path_mappings", sortedMappings(manifest.PathMappings), imageAny)
+	case *AccentManifest:
+		colors := manifest.AccentColors
+		l.checkAccentColors([6]string{colors.Color1, colors.Color2, colors.Color3, colors.Color4, colors.Color5, colors.Color6})
+	case *LEDManifest:
+		l.checkLEDColors("led_settings", manifest.LEDSettings)
+		for i, band := range manifest.BatteryBands {
+			l.checkLEDColors(fmt.Sprintf("battery_bands[%d].led_settings", i), band.LEDSettings)
+		}
+		if manifest.Charging != nil {
+			l.checkLEDColors("charging", *manifest.Charging)
+		}
+	}
+
+	return l.report(packageType), nil
+}
+
+// sortedMappings flattens a keyed mapping section in a stable order
+func sortedMappings(mappings map[string]PathMapping) []PathMapping {
+	keys := make([]string, 0, len(mappings))
+	for key := range mappings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]PathMapping, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, mappings[key])
+	}
+	return result
+}
+
+// checkMappings runs the per-mapping checks over one section of a manifest, checking
+// the size of its images against rule
+func (l *linter) checkMappings(section string, mappings []PathMapping, rule imageRule) {
+	seen := make(map[string]bool)
+	defer func() { l.checkCoverage(section, mappings) }()
+
+	for _, mapping := range mappings {
+		themePath := mapping.ThemePath
+
+		if filepath.IsAbs(themePath) {
+			l.add(fmt.Sprintf("%s: theme_path '%s' is absolute", section, themePath),
+				"Make theme_path relative to the package folder, e.g. 'Wallpapers/SystemWallpapers/Root.png'")
+			continue
+		}
+
+		if key := strings.ToLower(filepath.Clean(themePath)); seen[key] {
+			l.add(fmt.Sprintf("%s: theme_path '%s' is listed more than once", section, themePath),
+				"Remove the duplicate entry, or point it at the file it was meant to use")
+		} else {
+			seen[key] = true
+		}
+
+		if _, err := os.Stat(filepath.Join(l.packagePath, themePath)); err != nil {
+			l.add(fmt.Sprintf("%s: '%s' does not exist in the package", section, themePath),
+				"Add the file, or remove the mapping")
+		} else {
+			l.checkImage(section, themePath, rule)
+		}
+
+		themeExt := strings.ToLower(filepath.Ext(themePath))
+		systemExt := strings.ToLower(filepath.Ext(mapping.SystemPath))
+		if themeExt != systemExt {
+			l.add(fmt.Sprintf("%s: '%s' is copied to '%s' with a different extension", section, themePath, mapping.SystemPath),
+				fmt.Sprintf("Convert the file to %s or fix the extension in the manifest", systemExt))
+		}
+
+		if l.systems == nil {
+			continue
+		}
+
+		tag := ""
+		if mapping.Metadata != nil {
+			tag = mapping.Metadata["SystemTag"]
+		}
+		if tag == "" && strings.Contains(mapping.SystemPath, "/Roms/") {
+			tag = systemTagFromName(mapping.SystemPath)
+		}
+		if tag != "" && !l.systems[tag] {
+			l.add(fmt.Sprintf("%s: '%s' targets system '%s', which is not installed", section, themePath, tag),
+				"Check the system tag for typos, e.g. 'GBA' rather than 'gba'")
+		}
+	}
+}
+
+// FormatLintReport renders issues as a numbered fix list
+func FormatLintReport(issues []LintIssue) string {
+	if len(issues) == 0 {
+		return "No problems found.\n"
+	}
+
+	var b strings.Builder
+	for i, issue := range issues {
+		fmt.Fprintf(&b, "%d. %s\n   Fix: %s\n", i+1, issue.Problem, issue.Fix)
+	}
+	return b.String()
+}
+
+// ListInstalledPackages returns every installed theme and component package
+func ListInstalledPackages() ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	var packages []string
+
+	themePackages, _ := filepath.Glob(filepath.Join(cwd, "Themes", "*.theme"))
+	packages = append(packages, themePackages...)
+
+	for componentType, dir := range ComponentDirectory {
+		matches, _ := filepath.Glob(filepath.Join(cwd, "Components", dir, "*"+ComponentExtension[componentType]))
+		packages = append(packages, matches...)
+	}
+
+	sort.Strings(packages)
+	return packages, nil
+}
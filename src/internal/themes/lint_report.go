@@ -0,0 +1,192 @@
+// src/internal/themes/lint_report.go
+// Machine-readable lint report, with image size checks and mapping coverage, for theme
+// authors to run on their computer or in CI before submitting to the catalog
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// minIconSize is the smallest icon the building guide recommends
+const minIconSize = 256
+
+// LintReport is everything the linter found out about a package
+type LintReport struct {
+	Package  string            `json:"package"`
+	Type     string            `json:"type,omitempty"`
+	Passed   bool              `json:"passed"`
+	Issues   []LintIssue       `json:"issues"`
+	Images   []ImageCheck      `json:"images"`
+	Coverage []MappingCoverage `json:"coverage"`
+}
+
+// ImageCheck is the size of one image and whether it suits what it is used for
+type ImageCheck struct {
+	Section  string `json:"section"`
+	Path     string `json:"path"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Expected string `json:"expected"`
+	OK       bool   `json:"ok"`
+}
+
+// MappingCoverage compares one manifest section with the files in the package: mapped
+// files that are missing, and files in the section's folders that no mapping uses
+type MappingCoverage struct {
+	Section  string   `json:"section"`
+	Mappings int      `json:"mappings"`
+	Present  int      `json:"present"`
+	Missing  []string `json:"missing,omitempty"`
+	Unmapped []string `json:"unmapped,omitempty"`
+}
+
+// imageRule is the size an image in a manifest section should have
+type imageRule int
+
+const (
+	imageAny    imageRule = iota // Not checked, e.g. fonts and game art
+	imageScreen                  // Exactly the device's screen, like wallpapers and overlays
+	imageSquare                  // Square and at least minIconSize, like icons
+)
+
+// checkImage records the size of an image in the package and adds an issue when it
+// doesn't follow rule. Files that aren't images are left alone.
+func (l *linter) checkImage(section, relPath string, rule imageRule) {
+	if rule == imageAny {
+		return
+	}
+	switch strings.ToLower(filepath.Ext(relPath)) {
+	case ".png", ".jpg", ".jpeg":
+	default:
+		return
+	}
+
+	screenWidth, screenHeight := system.ScreenSize()
+	check := ImageCheck{Section: section, Path: relPath}
+	switch rule {
+	case imageScreen:
+		check.Expected = fmt.Sprintf("%dx%d", screenWidth, screenHeight)
+	case imageSquare:
+		check.Expected = fmt.Sprintf("square, at least %dx%d", minIconSize, minIconSize)
+	}
+
+	file, err := os.Open(filepath.Join(l.packagePath, relPath))
+	if err != nil {
+		return
+	}
+	config, _, err := image.DecodeConfig(file)
+	file.Close()
+	if err != nil {
+		l.add(fmt.Sprintf("%s: '%s' is not a readable image", section, relPath),
+			"Re-save the image as a PNG")
+		l.images = append(l.images, check)
+		return
+	}
+	check.Width, check.Height = config.Width, config.Height
+
+	switch rule {
+	case imageScreen:
+		check.OK = check.Width == screenWidth && check.Height == screenHeight
+		if !check.OK {
+			l.add(fmt.Sprintf("%s: '%s' is %dx%d, not the screen's %s", section, relPath, check.Width, check.Height, check.Expected),
+				fmt.Sprintf("Resize the image to %s so it isn't stretched", check.Expected))
+		}
+	case imageSquare:
+		check.OK = check.Width == check.Height && check.Width >= minIconSize
+		if !check.OK {
+			l.add(fmt.Sprintf("%s: '%s' is %dx%d, icons should be %s", section, relPath, check.Width, check.Height, check.Expected),
+				fmt.Sprintf("Resize the icon to a square of at least %dx%d", minIconSize, minIconSize))
+		}
+	}
+
+	l.images = append(l.images, check)
+}
+
+// checkCoverage records which mapped files of a section are missing and which files in
+// the folders the section uses aren't mapped at all
+func (l *linter) checkCoverage(section string, mappings []PathMapping) {
+	coverage := MappingCoverage{Section: section, Mappings: len(mappings)}
+	mapped := make(map[string]bool)
+	folders := make(map[string]bool)
+
+	for _, mapping := range mappings {
+		relPath := filepath.ToSlash(filepath.Clean(mapping.ThemePath))
+		if filepath.IsAbs(mapping.ThemePath) || strings.HasPrefix(relPath, "../") {
+			continue
+		}
+		mapped[strings.ToLower(relPath)] = true
+		if top, _, found := strings.Cut(relPath, "/"); found {
+			folders[top] = true
+		}
+
+		if _, err := os.Stat(filepath.Join(l.packagePath, relPath)); err != nil {
+			coverage.Missing = append(coverage.Missing, relPath)
+		} else {
+			coverage.Present++
+		}
+	}
+
+	for folder := range folders {
+		filepath.Walk(filepath.Join(l.packagePath, folder), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+				return nil
+			}
+			rel, err := filepath.Rel(l.packagePath, path)
+			if err != nil {
+				return nil
+			}
+			rel = filepath.ToSlash(rel)
+			if !mapped[strings.ToLower(rel)] {
+				coverage.Unmapped = append(coverage.Unmapped, rel)
+			}
+			return nil
+		})
+	}
+	sort.Strings(coverage.Unmapped)
+
+	l.coverage = append(l.coverage, coverage)
+}
+
+// report gathers what the linter found
+func (l *linter) report(packageType string) *LintReport {
+	report := &LintReport{
+		Package:  filepath.Base(l.packagePath),
+		Type:     packageType,
+		Passed:   len(l.issues) == 0,
+		Issues:   l.issues,
+		Images:   l.images,
+		Coverage: l.coverage,
+	}
+
+	// Empty lists are easier on scripts than nulls
+	if report.Issues == nil {
+		report.Issues = []LintIssue{}
+	}
+	if report.Images == nil {
+		report.Images = []ImageCheck{}
+	}
+	if report.Coverage == nil {
+		report.Coverage = []MappingCoverage{}
+	}
+	return report
+}
+
+// FormatLintReportJSON renders a report as indented JSON
+func FormatLintReportJSON(report *LintReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("error encoding lint report: %w", err)
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,128 @@
+// src/internal/themes/list_scrim.go
+// Dims list wallpapers (bglist.png) so game list text stays readable
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+)
+
+// List dimming values stored in the config; positive values are an opacity percentage
+const (
+	ListScrimThemeDefault = 0  // Use whatever the theme or wallpaper pack asks for
+	ListScrimOff          = -1 // Never dim list wallpapers
+)
+
+// ListScrimPresets are the dimming levels offered in settings
+var ListScrimPresets = []int{ListScrimThemeDefault, ListScrimOff, 25, 40, 60}
+
+// GetListScrimSetting returns the user's list dimming choice
+func GetListScrimSetting() int {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load list dimming setting: %v", err)
+		return ListScrimThemeDefault
+	}
+	return config.ListScrimOpacity
+}
+
+// SetListScrimSetting stores the user's list dimming choice
+func SetListScrimSetting(opacity int) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.ListScrimOpacity = opacity
+	return SaveConfig(config)
+}
+
+// resolveListScrimOpacity combines the package's requested opacity with the user's setting
+func resolveListScrimOpacity(packageOpacity int) int {
+	switch setting := GetListScrimSetting(); setting {
+	case ListScrimThemeDefault:
+		return packageOpacity
+	case ListScrimOff:
+		return 0
+	default:
+		return setting
+	}
+}
+
+// isListWallpaper reports whether a destination is a list wallpaper
+func isListWallpaper(path string) bool {
+	return filepath.Base(path) == "bglist.png"
+}
+
+// applyListScrim composites a translucent black layer over an image in place
+func applyListScrim(path string, opacity int) error {
+	if opacity <= 0 {
+		return nil
+	}
+	if opacity > 100 {
+		opacity = 100
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening list wallpaper: %w", err)
+	}
+
+	src, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding list wallpaper: %w", err)
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(bounds)
+	draw.Draw(dst, bounds, src, bounds.Min, draw.Src)
+
+	scrim := image.NewUniform(color.NRGBA{A: uint8(opacity * 255 / 100)})
+	draw.Draw(dst, bounds, scrim, image.Point{}, draw.Over)
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error writing list wallpaper: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, dst); err != nil {
+		return fmt.Errorf("error encoding list wallpaper: %w", err)
+	}
+
+	return nil
+}
+
+// dimListWallpaper applies the resolved scrim to a freshly copied list wallpaper
+func dimListWallpaper(path string, packageOpacity int, logger *Logger) {
+	if !isListWallpaper(path) {
+		return
+	}
+
+	opacity := resolveListScrimOpacity(packageOpacity)
+	if opacity <= 0 {
+		return
+	}
+
+	// Dimming rewrites the file, which must not reach the package through a hard link
+	if err := detachHardLink(path); err != nil {
+		logger.DebugFn("Warning: Could not dim list wallpaper %s: %v", path, err)
+		return
+	}
+
+	if err := applyListScrim(path, opacity); err != nil {
+		logger.DebugFn("Warning: Could not dim list wallpaper %s: %v", path, err)
+		return
+	}
+
+	logger.DebugFn("Dimmed list wallpaper %s at %d%%", path, opacity)
+}
@@ -0,0 +1,11 @@
+// src/internal/themes/lock_other.go
+// File locks are only taken on Unix, elsewhere the in-process lock has to do
+
+//go:build !unix
+
+package themes
+
+import "os"
+
+func lockFile(file *os.File) error   { return nil }
+func unlockFile(file *os.File) error { return nil }
@@ -0,0 +1,21 @@
+// src/internal/themes/lock_unix.go
+// File locks that keep Theme Manager processes from writing shared files at the same time
+
+//go:build unix
+
+package themes
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile takes an exclusive lock on an open file, waiting for other processes to let go
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// unlockFile releases a lock taken with lockFile
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
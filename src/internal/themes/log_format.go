@@ -0,0 +1,53 @@
+// src/internal/themes/log_format.go
+// Setting for writing structured JSON log lines instead of, or next to, the text log
+
+package themes
+
+import (
+	"fmt"
+
+	"nextui-themes/internal/logging"
+)
+
+// GetLogFormatSetting returns the saved log format, logging.LogFormatText unless changed
+func GetLogFormatSetting() string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load log format setting: %v", err)
+		return logging.LogFormatText
+	}
+
+	for _, format := range logging.LogFormats {
+		if config.LogFormat == format {
+			return format
+		}
+	}
+	return logging.LogFormatText
+}
+
+// SetLogFormatSetting stores the log format and switches to it for this session
+func SetLogFormatSetting(format string) error {
+	if err := logging.SetLogFormat(format); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.LogFormat = format
+	return SaveConfig(config)
+}
+
+// ApplyLogFormatSetting switches to the saved log format, called once on startup
+func ApplyLogFormatSetting() {
+	format := GetLogFormatSetting()
+	if format == logging.LogFormatText {
+		return
+	}
+
+	if err := logging.SetLogFormat(format); err != nil {
+		logging.LogDebug("Warning: Could not switch to the %s log format: %v", format, err)
+	}
+}
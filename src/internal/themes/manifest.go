@@ -20,11 +20,14 @@ type ThemeManifest struct {
 		Author       string    `json:"author"`
 		CreationDate time.Time `json:"creation_date"`
 		ExportedBy   string    `json:"exported_by"`
+		License      string    `json:"license,omitempty"`
+		Tags         []string  `json:"tags,omitempty"`
 	} `json:"theme_info"`
 	Content struct {
 		Wallpapers struct {
-			Present bool `json:"present"`
-			Count   int  `json:"count"`
+			Present bool   `json:"present"`
+			Count   int    `json:"count"`
+			Sleep   string `json:"sleep,omitempty"` // Suspend screen image, applied only on platforms that show one
 		} `json:"wallpapers"`
 		Icons struct {
 			Present         bool `json:"present"`
@@ -40,10 +43,24 @@ type ThemeManifest struct {
 			Present      bool `json:"present"`
 			OGReplaced   bool `json:"og_replaced"`
 			NextReplaced bool `json:"next_replaced"`
+
+			// Character ranges the fonts were subset to on export, see FontSubsetRanges
+			Subset []string `json:"subset,omitempty"`
 		} `json:"fonts"`
+		GameArt struct {
+			Present             bool `json:"present"`
+			PlaceholderReplaced bool `json:"placeholder_replaced"`
+			FrameReplaced       bool `json:"frame_replaced"`
+		} `json:"game_art"`
 		Settings struct {
 			AccentsIncluded bool `json:"accents_included"`
 			LEDsIncluded    bool `json:"leds_included"`
+
+			// Opacity percentage of the dark layer composited onto list wallpapers
+			ListScrimOpacity int `json:"list_scrim_opacity,omitempty"`
+
+			// Other NextUI settings files captured from the exporter's device
+			SnapshotFiles []string `json:"snapshot_files,omitempty"`
 		} `json:"settings"`
 	} `json:"content"`
 	PathMappings struct {
@@ -51,6 +68,7 @@ type ThemeManifest struct {
 		Icons      []PathMapping          `json:"icons"`
 		Overlays   []PathMapping          `json:"overlays"`
 		Fonts      map[string]PathMapping `json:"fonts"`
+		GameArt    map[string]PathMapping `json:"game_art,omitempty"`
 		Settings   map[string]PathMapping `json:"settings"`
 	} `json:"path_mappings"`
 	AccentColors struct {
@@ -63,12 +81,19 @@ type ThemeManifest struct {
 	} `json:"accent_colors"`
 
 	// Add LED settings
-	LEDSettings struct {
-		F1Key      LEDSetting `json:"f1_key"`
-		F2Key      LEDSetting `json:"f2_key"`
-		TopBar     LEDSetting `json:"top_bar"`
-		LRTriggers LEDSetting `json:"lr_triggers"`
-	} `json:"led_settings"`
+	LEDSettings LEDZones `json:"led_settings"`
+
+	// Original names of files renamed to be FAT32-safe on export, keyed by theme path
+	FileNames map[string]string `json:"file_names,omitempty"`
+
+	// What changed in each version, newest last
+	Changelog []ChangelogEntry `json:"changelog,omitempty"`
+
+	// Installed theme this variant builds on; only the files it overrides are in the package
+	Extends string `json:"extends,omitempty"`
+
+	// Set on patch packages, which only hold the files changed since a base version
+	Patch *ThemePatch `json:"patch,omitempty"`
 }
 
 // PathMapping represents a mapping between theme and system paths
@@ -88,6 +113,14 @@ type LEDSetting struct {
 	InBrightness int    `json:"in_brightness"`
 }
 
+// LEDZones holds the settings of each LED zone of the Brick
+type LEDZones struct {
+	F1Key      LEDSetting `json:"f1_key"`
+	F2Key      LEDSetting `json:"f2_key"`
+	TopBar     LEDSetting `json:"top_bar"`
+	LRTriggers LEDSetting `json:"lr_triggers"`
+}
+
 // Logger is a simple wrapper for logging
 type Logger struct {
 	DebugFn func(format string, args ...interface{})
@@ -135,6 +168,11 @@ func WriteManifest(themePath string, manifest *ThemeManifest, logger *Logger) er
 		manifest.ThemeInfo.Name = themeName
 	}
 
+	return saveManifest(themePath, manifest, logger)
+}
+
+// saveManifest encodes the manifest into the theme directory without touching its metadata
+func saveManifest(themePath string, manifest *ThemeManifest, logger *Logger) error {
 	// Use an encoder that doesn't escape HTML characters
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
@@ -167,6 +205,12 @@ func ValidateTheme(themePath string, logger *Logger) (*ThemeManifest, error) {
 		return nil, fmt.Errorf("theme directory does not exist: %s", themePath)
 	}
 
+	// Packages that only come with a manifest.yaml get a manifest.json made from it
+	if err := ensureJSONManifest(themePath, logger); err != nil {
+		logger.DebugFn("Error converting YAML manifest: %v", err)
+		return nil, err
+	}
+
 	// Check for manifest.json
 	manifestPath := filepath.Join(themePath, "manifest.json")
 	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
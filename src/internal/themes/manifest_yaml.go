@@ -0,0 +1,350 @@
+// src/internal/themes/manifest_yaml.go
+// Converts package manifests between manifest.json and manifest.yaml. Community packages
+// come in both formats, with the same keys; a package with only a YAML manifest gets a
+// manifest.json generated the first time it is read. Keys the manifest types don't have
+// are logged as warnings, see checkManifestKeys.
+
+package themes
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"nextui-themes/internal/logging"
+)
+
+// Manifest file names, in the order they are looked for
+const (
+	ManifestJSON = "manifest.json"
+	ManifestYAML = "manifest.yaml"
+	manifestYML  = "manifest.yml"
+)
+
+// ensureJSONManifest converts a package's YAML manifest into manifest.json when the
+// package has no manifest.json yet
+func ensureJSONManifest(packagePath string, logger *Logger) error {
+	if _, err := os.Stat(filepath.Join(packagePath, ManifestJSON)); err == nil {
+		return nil
+	}
+
+	for _, name := range []string{ManifestYAML, manifestYML} {
+		if _, err := os.Stat(filepath.Join(packagePath, name)); err != nil {
+			continue
+		}
+		logger.DebugFn("No %s in %s, converting %s", ManifestJSON, filepath.Base(packagePath), name)
+		return ConvertManifest(packagePath, "json")
+	}
+	return nil
+}
+
+// ConvertManifest writes a package's manifest in another format: "json" writes
+// manifest.json from manifest.yaml (or manifest.yml), "yaml" writes manifest.yaml from
+// manifest.json. The source manifest is left in place.
+func ConvertManifest(packagePath string, format string) error {
+	var sourceNames []string
+	var targetName string
+	switch format {
+	case "json":
+		sourceNames, targetName = []string{ManifestYAML, manifestYML}, ManifestJSON
+	case "yaml":
+		sourceNames, targetName = []string{ManifestJSON}, ManifestYAML
+	default:
+		return fmt.Errorf("unknown manifest format '%s', expected json or yaml", format)
+	}
+
+	var data []byte
+	var sourceName string
+	for _, name := range sourceNames {
+		read, err := os.ReadFile(filepath.Join(packagePath, name))
+		if err == nil {
+			data, sourceName = read, name
+			break
+		}
+	}
+	if sourceName == "" {
+		return fmt.Errorf("no %s found in %s", strings.Join(sourceNames, " or "), packagePath)
+	}
+
+	var converted []byte
+	var err error
+	if format == "json" {
+		converted, err = manifestYAMLToJSON(data)
+	} else {
+		converted, err = manifestJSONToYAML(data)
+	}
+	if err != nil {
+		return fmt.Errorf("error converting %s: %w", sourceName, err)
+	}
+
+	if format == "json" {
+		if err := checkManifestKeys(converted); err != nil {
+			logging.LogDebug("Warning: %s in %s: %v", sourceName, filepath.Base(packagePath), err)
+		}
+	}
+
+	targetPath := filepath.Join(packagePath, targetName)
+	if err := os.WriteFile(targetPath, converted, 0644); err != nil {
+		return fmt.Errorf("error writing %s: %w", targetName, err)
+	}
+	return nil
+}
+
+// manifestYAMLToJSON converts a YAML manifest to indented JSON, keeping the order of its keys
+func manifestYAMLToJSON(data []byte) ([]byte, error) {
+	node, err := parseManifestYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	return marshalManifestJSON(node)
+}
+
+// manifestJSONToYAML converts a JSON manifest to block-style YAML, keeping the order of its keys
+func manifestJSONToYAML(data []byte) ([]byte, error) {
+	node, err := parseManifestJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return marshalManifestYAML(node)
+}
+
+// checkManifestKeys reports the first key of a JSON manifest that its manifest type doesn't
+// have. Both formats share the keys of manifest.json, so a YAML manifest written for another
+// schema converts without errors but loses those settings.
+func checkManifestKeys(data []byte) error {
+	var sniff struct {
+		ThemeInfo *json.RawMessage `json:"theme_info"`
+	}
+	if err := json.Unmarshal(data, &sniff); err != nil {
+		return err
+	}
+
+	var manifest interface{}
+	if sniff.ThemeInfo != nil {
+		manifest = &ThemeManifest{}
+	} else {
+		var base BaseComponentManifest
+		if err := json.Unmarshal(data, &base); err != nil {
+			return err
+		}
+		kind, ok := LookupComponentKind(base.ComponentInfo.Type)
+		if !ok {
+			return fmt.Errorf("no theme_info or component_info of a known type")
+		}
+		manifest = kind.NewManifest(ComponentInfo{})
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(manifest)
+}
+
+// parseManifestJSON reads a JSON document into a YAML node tree, keeping key order
+func parseManifestJSON(data []byte) (*yaml.Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return readJSONNode(decoder)
+}
+
+// readJSONNode reads the next value from decoder
+func readJSONNode(decoder *json.Decoder) (*yaml.Node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("unexpected end of JSON")
+		}
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if token == '[' {
+			node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		}
+		for decoder.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, yamlString(key.(string)))
+			}
+			value, err := readJSONNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		_, err := decoder.Token()
+		return node, err
+
+	case string:
+		return yamlString(token), nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(string(token), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(token)}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(token)}, nil
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// yaml11Bools are the words YAML 1.1 parsers read as booleans. YAML 1.2 reads them as
+// strings, so the encoder leaves them unquoted unless asked.
+var yaml11Bools = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true, "on": true, "off": true,
+}
+
+// yamlString returns a string node, quoted when needsYAMLQuotes says so
+func yamlString(value string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if needsYAMLQuotes(value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	return node
+}
+
+// needsYAMLQuotes reports whether a string must be quoted for every YAML parser to read it
+// back the same: YAML 1.1 booleans, and strings of only line breaks, which the encoder
+// can't write as a block
+func needsYAMLQuotes(value string) bool {
+	if yaml11Bools[strings.ToLower(value)] {
+		return true
+	}
+	return value != "" && strings.Trim(value, "\n") == ""
+}
+
+// parseManifestYAML reads a YAML document into a node tree
+func parseManifestYAML(data []byte) (*yaml.Node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil, errors.New("the YAML document is empty")
+	}
+	return document.Content[0], nil
+}
+
+// marshalManifestYAML writes a node tree as block-style YAML indented by two spaces
+func marshalManifestYAML(node *yaml.Node) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// marshalManifestJSON writes a node tree as indented JSON
+func marshalManifestJSON(node *yaml.Node) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeJSONNode(&compact, node); err != nil {
+		return nil, err
+	}
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteByte('\n')
+	return indented.Bytes(), nil
+}
+
+// writeJSONNode writes a node as compact JSON. Keys are always strings, as JSON needs.
+func writeJSONNode(b *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeJSONNode(b, node.Alias)
+
+	case yaml.MappingNode:
+		b.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: manifest keys must be plain values", key.Line)
+			}
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			encoded, _ := json.Marshal(key.Value)
+			b.Write(encoded)
+			b.WriteByte(':')
+			if err := writeJSONNode(b, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+
+	case yaml.SequenceNode:
+		b.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeJSONNode(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+
+	case yaml.ScalarNode:
+		encoded, err := jsonScalar(node)
+		if err != nil {
+			return err
+		}
+		b.Write(encoded)
+
+	default:
+		return fmt.Errorf("line %d: unexpected YAML node", node.Line)
+	}
+	return nil
+}
+
+// jsonScalar encodes a YAML scalar as the JSON value it resolves to. Numbers JSON can hold
+// as written, such as 1e5, are kept as written; others, such as 0x1F, are converted.
+func jsonScalar(node *yaml.Node) ([]byte, error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return []byte("null"), nil
+	case "!!bool":
+		var value bool
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return json.Marshal(value)
+	case "!!int", "!!float":
+		if json.Valid([]byte(node.Value)) && !strings.HasPrefix(node.Value, "+") {
+			return []byte(node.Value), nil
+		}
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("line %d: %s can't be written as JSON", node.Line, node.Value)
+		}
+		return json.Marshal(value)
+	default:
+		// Strings, and values JSON has no type for, such as timestamps, as they are written
+		return json.Marshal(node.Value)
+	}
+}
@@ -0,0 +1,341 @@
+package themes
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// edgeCaseManifest has the values that are easy to get wrong in YAML: strings that look
+// like other types, keys that need quoting, multi-line strings, empty maps and lists, and
+// non-ASCII text
+const edgeCaseManifest = `{
+  "component_info": {
+    "name": "Pokémon mini (PKM) ✨",
+    "version": "1.0",
+    "author": "山田 太郎",
+    "description": "First line\nSecond line\n\nAfter a blank line\n",
+    "license": ""
+  },
+  "strings": {
+    "looks_true": "true",
+    "looks_null": "null",
+    "looks_no": "no",
+    "looks_number": "0xFF0000",
+    "looks_float": "1.0",
+    "padded": "  padded  ",
+    "dash": "- not a list",
+    "colon": "key: value",
+    "hash": "#ffffff",
+    "inline_hash": "red #1",
+    "trailing_colon": "ends with:",
+    "quotes": "say \"hi\" and 'bye'",
+    "backslash": "C:\\Roms\\GBA",
+    "tab": "a\tb",
+    "only_newlines": "\n\n",
+    "emoji": "🎮",
+    "combining": "Poke\u0301mon"
+  },
+  "values": {
+    "int": 42,
+    "negative": -3,
+    "float": 0.5,
+    "exponent": 1e5,
+    "yes": true,
+    "no": false,
+    "nothing": null
+  },
+  "odd keys": {
+    "1": "number key",
+    "true": "bool key",
+    "key: colon": "colon key",
+    "": "empty key",
+    "Pokémon": "unicode key"
+  },
+  "empty_map": {},
+  "empty_list": [],
+  "nested": {
+    "empty_in_list": [{}, [], ""],
+    "lists": [[1, 2], ["a", ["b"]]],
+    "maps": [
+      {"theme_path": "Wallpapers/SystemWallpapers/Pokémon mini (PKM).png", "system_path": "/mnt/SDCARD/Roms/Pokémon mini (PKM)/.media/bg.png", "metadata": {}},
+      {"theme_path": "Icons/Tools.png", "system_path": "/mnt/SDCARD/Tools/.media/tg5040.png", "metadata": {"IconType": "Tool"}}
+    ]
+  }
+}`
+
+// packageManifests returns the manifests the package itself writes: a theme manifest and
+// the manifest of every registered component kind, filled in like an export would
+func packageManifests(t *testing.T) map[string][]byte {
+	t.Helper()
+
+	manifests := make(map[string][]byte)
+	add := func(name string, manifest interface{}) {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		manifests[name] = data
+	}
+
+	theme := CreateMinimalThemeManifest("Pokémon Night", "Jo \"JJ\" O'Neil")
+	theme.ThemeInfo.Tags = []string{"dark", "Pokémon", "yes", "1.0"}
+	theme.Changelog = []ChangelogEntry{{
+		Version: "1.0.1",
+		Changes: []string{"Fixed the GBA icon", "Two lines:\nsecond one"},
+	}}
+	theme.FileNames = map[string]string{"Icons/SystemIcons/Pokemon.png": "Icons/SystemIcons/Pokémon: mini?.png"}
+	theme.Content.Wallpapers.Present = true
+	theme.Content.Wallpapers.Count = 1
+	theme.PathMappings.Wallpapers = []PathMapping{{
+		ThemePath:  "Wallpapers/SystemWallpapers/Pokémon mini (PKM).png",
+		SystemPath: "/mnt/SDCARD/Roms/Pokémon mini (PKM)/.media/bg.png",
+		Metadata:   map[string]string{"SystemTag": "PKM", "WallpaperType": "System"},
+	}}
+	theme.AccentColors.Color2 = "#9B2257"
+	add("theme", theme)
+
+	for _, kind := range ComponentKinds() {
+		add(kind.Type(), kind.NewManifest(ComponentInfo{
+			Name:    "Sample" + kind.Extension(),
+			Type:    kind.Type(),
+			Version: "1.0.0",
+			Author:  "Zoë",
+			License: "CC BY 4.0\nAttribution required",
+			Tags:    []string{"null", "- dash"},
+		}))
+	}
+
+	manifests["edge cases"] = []byte(edgeCaseManifest)
+	return manifests
+}
+
+// decodeJSON decodes a document for comparing values regardless of layout
+func decodeJSON(t *testing.T, data []byte) interface{} {
+	t.Helper()
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, data)
+	}
+	return value
+}
+
+func TestManifestYAMLRoundTrip(t *testing.T) {
+	for name, original := range packageManifests(t) {
+		t.Run(name, func(t *testing.T) {
+			node, err := parseManifestJSON(original)
+			if err != nil {
+				t.Fatalf("parseManifestJSON: %v", err)
+			}
+			want, err := marshalManifestJSON(node)
+			if err != nil {
+				t.Fatalf("marshalManifestJSON: %v", err)
+			}
+
+			yaml, err := marshalManifestYAML(node)
+			if err != nil {
+				t.Fatalf("marshalManifestYAML: %v", err)
+			}
+			parsed, err := parseManifestYAML(yaml)
+			if err != nil {
+				t.Fatalf("parseManifestYAML: %v\n%s", err, yaml)
+			}
+			got, err := marshalManifestJSON(parsed)
+			if err != nil {
+				t.Fatalf("marshalManifestJSON: %v", err)
+			}
+
+			// Same values, and the same key order as the original
+			if !bytes.Equal(got, want) {
+				t.Errorf("round trip changed the manifest\nYAML:\n%s\ngot:\n%s\nwant:\n%s", yaml, got, want)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, got), decodeJSON(t, original)) {
+				t.Errorf("round trip changed the values\ngot:\n%s", got)
+			}
+
+			// Converting again gives the same YAML
+			again, err := manifestJSONToYAML(got)
+			if err != nil {
+				t.Fatalf("manifestJSONToYAML: %v", err)
+			}
+			if !bytes.Equal(again, yaml) {
+				t.Errorf("YAML changed on the second conversion\nfirst:\n%s\nsecond:\n%s", yaml, again)
+			}
+		})
+	}
+}
+
+func TestParseManifestYAMLStrings(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"plain", "description: Just text\n", "Just text"},
+		{"double quoted", "description: \"Tab\\there \\u00e9\"\n", "Tab\there é"},
+		{"single quoted", "description: 'It''s # not a comment'\n", "It's # not a comment"},
+		{"comment", "description: Text # a comment\n", "Text"},
+		{"literal", "description: |\n  One\n  Two\n\n  Three\nname: x\n", "One\nTwo\n\nThree\n"},
+		{"literal strip", "description: |-\n  One\n  Two\n", "One\nTwo"},
+		{"literal keep", "description: |+\n  One\n\nname: x\n", "One\n\n"},
+		{"literal keep at end", "description: |+\n  One\n", "One\n"},
+		{"folded", "description: >\n  One\n  two\n\n  Three\n", "One two\nThree\n"},
+		{"folded blank lines", "description: >\n  One\n\n\n  Two\n", "One\n\nTwo\n"},
+		{"folded strip", "description: >-\n  One\n  two\n", "One two"},
+		{"literal nested indent", "description: |\n  Code:\n    indented\n", "Code:\n  indented\n"},
+		{"unicode", "description: Pokémon ✨ 山田\n", "Pokémon ✨ 山田"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := manifestYAMLToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("manifestYAMLToJSON: %v", err)
+			}
+			var manifest struct {
+				Description string `json:"description"`
+			}
+			if err := json.Unmarshal(converted, &manifest); err != nil {
+				t.Fatalf("invalid JSON: %v\n%s", err, converted)
+			}
+			if manifest.Description != tt.want {
+				t.Errorf("description = %q, want %q", manifest.Description, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseManifestYAMLEmptyCollections(t *testing.T) {
+	yaml := "content:\n  systems: []\n  mappings: {}\n  tags: [GBA, \"SNES\", 'N64']\n  nested:\n    - []\n    - {}\n"
+	got, err := manifestYAMLToJSON([]byte(yaml))
+	if err != nil {
+		t.Fatalf("manifestYAMLToJSON: %v", err)
+	}
+
+	want := `{"content":{"systems":[],"mappings":{},"tags":["GBA","SNES","N64"],"nested":[[],{}]}}`
+	if !reflect.DeepEqual(decodeJSON(t, got), decodeJSON(t, []byte(want))) {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseManifestYAMLValues(t *testing.T) {
+	tests := []struct {
+		name, yaml, want string
+	}{
+		{"JSON numbers kept as written", "a: 1e5\nb: -3\nc: 0.5\n", `{"a":1e5,"b":-3,"c":0.5}`},
+		{"other numbers converted", "a: 0x1F\nb: +5\nc: .5\nd: 0o17\n", `{"a":31,"b":5,"c":0.5,"d":15}`},
+		{"bools and nulls", "a: true\nb: false\nc: null\nd: ~\ne:\n", `{"a":true,"b":false,"c":null,"d":null,"e":null}`},
+		{"YAML 1.1 words stay strings", "a: yes\nb: no\nc: on\n", `{"a":"yes","b":"no","c":"on"}`},
+		{"keys are strings", "1: one\ntrue: yes\n", `{"1":"one","true":"yes"}`},
+		{"timestamps are strings", "date: 2024-05-01\n", `{"date":"2024-05-01"}`},
+		{"anchors and aliases", "a: &color \"#FF0000\"\nb: *color\n", `{"a":"#FF0000","b":"#FF0000"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manifestYAMLToJSON([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("manifestYAMLToJSON: %v", err)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, got), decodeJSON(t, []byte(tt.want))) {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManifestJSONToYAMLQuoting(t *testing.T) {
+	got, err := manifestJSONToYAML([]byte(`{"yes": "no", "on": "\n\n", "plain": "text", "looks_true": "true"}`))
+	if err != nil {
+		t.Fatalf("manifestJSONToYAML: %v", err)
+	}
+
+	// YAML 1.1 parsers read yes, no and on as booleans
+	want := "\"yes\": \"no\"\n\"on\": \"\\n\\n\"\nplain: text\nlooks_true: \"true\"\n"
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseManifestYAMLErrors(t *testing.T) {
+	for name, yaml := range map[string]string{
+		"empty":           "# only a comment\n",
+		"tab indent":      "a:\n\tb: 1\n",
+		"bad indent":      "a: 1\n  b: 2\n",
+		"unterminated":    "a: \"open\n",
+		"unclosed flow":   "a: [1, 2\n",
+		"bad escape":      "a: \"\\q\"\n",
+		"missing mapping": "a: 1\njust text\n",
+		"infinity":        "a: .inf\n",
+		"map as key":      "? {a: 1}\n: b\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := manifestYAMLToJSON([]byte(yaml)); err == nil {
+				t.Errorf("expected an error for %q", yaml)
+			}
+		})
+	}
+}
+
+func TestConvertManifest(t *testing.T) {
+	for name, original := range packageManifests(t) {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			jsonPath := filepath.Join(dir, ManifestJSON)
+			if err := os.WriteFile(jsonPath, original, 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ConvertManifest(dir, "yaml"); err != nil {
+				t.Fatalf("ConvertManifest yaml: %v", err)
+			}
+			if err := os.Remove(jsonPath); err != nil {
+				t.Fatal(err)
+			}
+			if err := ConvertManifest(dir, "json"); err != nil {
+				t.Fatalf("ConvertManifest json: %v", err)
+			}
+
+			converted, err := os.ReadFile(jsonPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(decodeJSON(t, converted), decodeJSON(t, original)) {
+				t.Errorf("manifest changed going through YAML\ngot:\n%s\nwant:\n%s", converted, original)
+			}
+		})
+	}
+
+	if err := ConvertManifest(t.TempDir(), "toml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if err := ConvertManifest(t.TempDir(), "json"); err == nil {
+		t.Error("expected an error without a YAML manifest")
+	}
+}
+
+func TestCheckManifestKeys(t *testing.T) {
+	manifests := packageManifests(t)
+	delete(manifests, "edge cases")
+	for name, manifest := range manifests {
+		if err := checkManifestKeys(manifest); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	for name, manifest := range map[string]string{
+		"unknown theme key":     `{"theme_info": {"name": "x"}, "themeInfo": {}}`,
+		"unknown nested key":    `{"theme_info": {"name": "x", "authorName": "y"}}`,
+		"unknown component key": `{"component_info": {"type": "wallpaper"}, "wallpapers": []}`,
+		"unknown type":          `{"component_info": {"type": "sticker"}}`,
+		"neither":               `{"name": "x"}`,
+	} {
+		if err := checkManifestKeys([]byte(manifest)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// src/internal/themes/media_classify.go
+// Classifies .media contents so cleanups only remove files the manager wrote
+
+package themes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// MediaClass describes who owns a file inside a .media folder
+type MediaClass int
+
+const (
+	MediaUnknown MediaClass = iota // Not written by the manager and not recognized as scraped
+	MediaManaged                   // Written by a previous theme or component apply
+	MediaScraped                   // Artwork that belongs to a ROM, e.g. scraped boxart
+)
+
+// String returns a readable name for the media class
+func (c MediaClass) String() string {
+	switch c {
+	case MediaManaged:
+		return "managed"
+	case MediaScraped:
+		return "scraped"
+	default:
+		return "unknown"
+	}
+}
+
+// errNotManaged is returned when a cleanup skips a file the manager didn't write
+var errNotManaged = errors.New("file was not written by Theme Manager")
+
+// managedLedger tracks every file the manager has written to the device
+var managedLedger struct {
+	loaded bool
+	exists bool // False until the first apply after upgrading, see canCleanupFile
+	paths  map[string]bool
+	linked map[string]bool // Files applied as hard links to a package, see link_apply.go
+}
+
+// managedLedgerFile is the on-disk ledger format
+type managedLedgerFile struct {
+	Files  []string `json:"files"`
+	Linked []string `json:"linked,omitempty"`
+}
+
+// getManagedLedgerPath returns the path of the managed files ledger
+func getManagedLedgerPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, "managed_files.json"), nil
+}
+
+// loadManagedLedger reads the ledger from disk once per run
+func loadManagedLedger() {
+	if managedLedger.loaded {
+		return
+	}
+
+	managedLedger.loaded = true
+	managedLedger.paths = make(map[string]bool)
+	managedLedger.linked = make(map[string]bool)
+
+	ledgerPath, err := getManagedLedgerPath()
+	if err != nil {
+		logging.LogDebug("Warning: Could not locate managed files ledger: %v", err)
+		return
+	}
+
+	data, err := os.ReadFile(ledgerPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logging.LogDebug("Warning: Could not read managed files ledger: %v", err)
+		}
+		return
+	}
+
+	// Older ledgers are a plain list of files
+	var ledger managedLedgerFile
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		if err := json.Unmarshal(data, &ledger.Files); err != nil {
+			logging.LogDebug("Warning: Could not parse managed files ledger: %v", err)
+			return
+		}
+	}
+
+	managedLedger.exists = true
+	for _, path := range ledger.Files {
+		managedLedger.paths[filepath.Clean(path)] = true
+	}
+	for _, path := range ledger.Linked {
+		managedLedger.linked[filepath.Clean(path)] = true
+	}
+}
+
+// recordManagedFile marks a file as written by the manager
+func recordManagedFile(path string) {
+	loadManagedLedger()
+	managedLedger.paths[filepath.Clean(path)] = true
+	delete(managedLedger.linked, filepath.Clean(path))
+}
+
+// recordLinkedFile marks a file as written by the manager as a hard link to a package
+func recordLinkedFile(path string) {
+	loadManagedLedger()
+	managedLedger.paths[filepath.Clean(path)] = true
+	managedLedger.linked[filepath.Clean(path)] = true
+}
+
+// isLinkedFile reports whether a file was applied as a hard link
+func isLinkedFile(path string) bool {
+	loadManagedLedger()
+	return managedLedger.linked[filepath.Clean(path)]
+}
+
+// forgetManagedFile removes a file from the ledger after it was deleted
+func forgetManagedFile(path string) {
+	loadManagedLedger()
+	delete(managedLedger.paths, filepath.Clean(path))
+	delete(managedLedger.linked, filepath.Clean(path))
+}
+
+// saveManagedLedger writes the ledger back to disk
+func saveManagedLedger() error {
+	loadManagedLedger()
+
+	ledgerPath, err := getManagedLedgerPath()
+	if err != nil {
+		return err
+	}
+
+	if !managedLedger.exists {
+		seedManagedLedger()
+	}
+
+	var ledger managedLedgerFile
+	for path := range managedLedger.paths {
+		ledger.Files = append(ledger.Files, path)
+	}
+	for path := range managedLedger.linked {
+		ledger.Linked = append(ledger.Linked, path)
+	}
+	sort.Strings(ledger.Files)
+	sort.Strings(ledger.Linked)
+
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling managed files ledger: %w", err)
+	}
+
+	if err := os.WriteFile(ledgerPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing managed files ledger: %w", err)
+	}
+
+	managedLedger.exists = true
+	return nil
+}
+
+// seedManagedLedger records the files earlier versions wrote before there was a ledger. Once
+// the ledger exists unknown files are left alone, so without this the wallpapers and icons of
+// every category the first apply didn't touch could never be cleaned up again. Every kind's
+// cleanup is previewed while unknown files still count as managed, which finds them.
+func seedManagedLedger() {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		logging.LogDebug("Warning: Could not seed managed files ledger: %v", err)
+		return
+	}
+
+	// Pinned files the previews come across aren't skips of the apply in progress
+	if pinnedState.paths == nil {
+		beginPinnedApply()
+	}
+	skipped := make(map[string]bool)
+	for path := range pinnedState.skipped {
+		skipped[path] = true
+	}
+	defer func() { pinnedState.skipped = skipped }()
+
+	logger := &Logger{DebugFn: logging.LogDebug}
+	seeded := 0
+	for _, kind := range ComponentKinds() {
+		for path := range previewCleanup(func() error { return kind.Cleanup(systemPaths, logger) }) {
+			if !managedLedger.paths[path] {
+				managedLedger.paths[path] = true
+				seeded++
+			}
+		}
+	}
+	logging.LogDebug("Seeded managed files ledger with %d files written before it existed", seeded)
+}
+
+// ClassifyMediaFile reports whether a file was written by the manager, scraped, or unknown
+func ClassifyMediaFile(path string) MediaClass {
+	loadManagedLedger()
+
+	path = filepath.Clean(path)
+	if managedLedger.paths[path] {
+		return MediaManaged
+	}
+
+	if isScrapedMedia(path) {
+		return MediaScraped
+	}
+
+	return MediaUnknown
+}
+
+// isScrapedMedia reports whether a .media file shares its name with a ROM next to the .media folder
+func isScrapedMedia(path string) bool {
+	mediaDir := filepath.Dir(path)
+	if filepath.Base(mediaDir) != ".media" {
+		return false
+	}
+
+	baseName := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	entries, err := os.ReadDir(filepath.Dir(mediaDir))
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		romBase := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if romBase == baseName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canCleanupFile reports whether a cleanup routine may remove the file.
+// Before the ledger exists (first apply after upgrading) every non-scraped file is
+// treated as managed, matching the previous behavior; afterwards only managed files are removed.
+// The first save seeds the ledger with those files, see seedManagedLedger.
+func canCleanupFile(path string) bool {
+	switch ClassifyMediaFile(path) {
+	case MediaManaged:
+		return true
+	case MediaScraped:
+		return false
+	default:
+		return !managedLedger.exists
+	}
+}
@@ -0,0 +1,161 @@
+// src/internal/themes/night_variant.go
+// Generates a darker, desaturated night variant of an installed wallpaper pack
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// Night variant look: colors keep this share of their saturation, then this share of their brightness
+const (
+	nightSaturation = 0.45
+	nightBrightness = 0.55
+)
+
+// nightTag marks generated night variants, so they can be told apart from the pack they came from
+const nightTag = "night"
+
+// nightPackName returns a free name for the night variant of packName, e.g. "Retro-night.bg",
+// then "Retro-night-2.bg"
+func nightPackName(componentsDir, packName string) string {
+	ext := ComponentExtension[ComponentWallpaper]
+	stem := strings.TrimSuffix(packName, ext) + "-night"
+	name := stem + ext
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(componentsDir, name)); os.IsNotExist(err) {
+			return name
+		}
+		name = fmt.Sprintf("%s-%d%s", stem, i, ext)
+	}
+}
+
+// darkenForNight rewrites a PNG in place with its colors desaturated and darkened.
+// Transparency is kept as it is.
+func darkenForNight(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening image: %w", err)
+	}
+
+	src, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	img := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(img, img.Bounds(), src, bounds.Min, draw.Src)
+
+	for i := 0; i < len(img.Pix); i += 4 {
+		r, g, b := float64(img.Pix[i]), float64(img.Pix[i+1]), float64(img.Pix[i+2])
+		lum := 0.299*r + 0.587*g + 0.114*b
+		img.Pix[i] = uint8((lum + (r-lum)*nightSaturation) * nightBrightness)
+		img.Pix[i+1] = uint8((lum + (g-lum)*nightSaturation) * nightBrightness)
+		img.Pix[i+2] = uint8((lum + (b-lum)*nightSaturation) * nightBrightness)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error writing image: %w", err)
+	}
+	defer out.Close()
+
+	if err := png.Encode(out, img); err != nil {
+		return fmt.Errorf("error encoding image: %w", err)
+	}
+	return nil
+}
+
+// GenerateNightVariant copies an installed wallpaper pack into a new installed pack with every
+// image darkened and desaturated, and returns its name. The manifest is carried over, so the
+// variant keeps the original's author, license, slideshows and sleep wallpaper.
+func GenerateNightVariant(packName string) (name string, err error) {
+	defer func() { recordOperation("Generated night variant", name, err) }()
+	defer logging.BeginOperation("generate night variant", packName)()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	componentsDir := filepath.Join(cwd, "Components", ComponentDirectory[ComponentWallpaper])
+	packPath := filepath.Join(componentsDir, packName)
+
+	name = nightPackName(componentsDir, packName)
+	nightPath := filepath.Join(componentsDir, name)
+
+	// Assemble under a hidden name, so an interrupted generation never shows up as installed
+	tempPath := filepath.Join(componentsDir, ".night-"+name)
+	if err := os.RemoveAll(tempPath); err != nil {
+		return "", fmt.Errorf("error clearing previous generation: %w", err)
+	}
+	defer os.RemoveAll(tempPath)
+
+	if err := copyTree(packPath, tempPath); err != nil {
+		return "", fmt.Errorf("error copying %s: %w", packName, err)
+	}
+
+	darkened := 0
+	err = filepath.Walk(tempPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".png") {
+			return nil
+		}
+		if err := darkenForNight(path); err != nil {
+			rel, _ := filepath.Rel(tempPath, path)
+			return fmt.Errorf("error darkening %s: %w", rel, err)
+		}
+		darkened++
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	manifestObj, err := LoadComponentManifest(tempPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", packName, err)
+	}
+	info := GetComponentInfo(manifestObj)
+	info.Name = name
+	tagged := false
+	for _, tag := range info.Tags {
+		tagged = tagged || tag == nightTag
+	}
+	if !tagged {
+		info.Tags = append(info.Tags, nightTag)
+	}
+	if err := WriteComponentManifest(tempPath, manifestObj); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tempPath, nightPath); err != nil {
+		return "", fmt.Errorf("error installing night variant: %w", err)
+	}
+	if err := UpdateComponentManifest(nightPath); err != nil {
+		logger.DebugFn("Warning: Error updating manifest of %s: %v", name, err)
+	}
+
+	logger.DebugFn("Generated night variant %s from %s (%d images darkened)", name, packName, darkened)
+	return name, nil
+}
@@ -0,0 +1,66 @@
+// src/internal/themes/os_update.go
+// Detects NextUI updates between runs, since they tend to overwrite fonts and settings
+
+package themes
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// systemVersionPath is the version file NextUI rewrites on every update
+const systemVersionPath = "/mnt/SDCARD/.system/version.txt"
+
+// SystemUpdate describes a NextUI update found on startup
+type SystemUpdate struct {
+	Version string // First line of the new version file
+	hash    string
+}
+
+// CheckSystemUpdate compares the NextUI version file with the one seen on the last run.
+// It returns nil when nothing changed, on the first run, or when the version can't be read.
+func CheckSystemUpdate() *SystemUpdate {
+	sum, err := hashFile(systemVersionPath)
+	if err != nil {
+		logging.LogDebug("Could not read system version: %v", err)
+		return nil
+	}
+	hash := hex.EncodeToString(sum)
+
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load global manifest: %v", err)
+		return nil
+	}
+
+	update := &SystemUpdate{hash: hash}
+	if data, err := os.ReadFile(systemVersionPath); err == nil {
+		update.Version = strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+	}
+
+	// Nothing to compare with yet, remember this version
+	if manifest.SystemVersionHash == "" {
+		AcknowledgeSystemUpdate(update)
+		return nil
+	}
+
+	if manifest.SystemVersionHash == hash {
+		return nil
+	}
+
+	logging.LogDebug("System version changed since the last run: %s", update.Version)
+	return update
+}
+
+// AcknowledgeSystemUpdate records the new version so the update isn't reported again
+func AcknowledgeSystemUpdate(update *SystemUpdate) {
+	if err := UpdateGlobalManifest(func(manifest *GlobalManifest) error {
+		manifest.SystemVersionHash = update.hash
+		return nil
+	}); err != nil {
+		logging.LogDebug("Warning: Could not save system version: %v", err)
+	}
+}
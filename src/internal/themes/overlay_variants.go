@@ -0,0 +1,290 @@
+// src/internal/themes/overlay_variants.go
+// Per-system overlay variants: pick one of a system's overlays and an opacity for the active slot
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// overlayActiveSlot is the file in a system's overlay folder that the variant picker
+// writes; select it once in the emulator's overlay option and switch variants from here
+const overlayActiveSlot = "overlay.png"
+
+// OverlayOpacityPresets are the opacities offered by the variant picker, in percent
+var OverlayOpacityPresets = []int{100, 75, 50, 25}
+
+// OverlayVariant is one overlay file a system can use
+type OverlayVariant struct {
+	File    string // File name in the system's overlay folder
+	Label   string // Name shown in the picker
+	Opacity string // Opacity the author declared for the file, if any
+}
+
+// OverlayVariantChoice is the variant and opacity picked for a system
+type OverlayVariantChoice struct {
+	File    string `json:"file"`
+	Opacity int    `json:"opacity"`
+}
+
+// overlayVariantLabel derives a readable variant name from a file name, e.g. "grid_strong.png" -> "grid strong"
+func overlayVariantLabel(fileName string) string {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-'
+	}), " ")
+}
+
+// overlayMetadata builds the metadata of an overlay mapping. Variant details the author
+// already set in previous are kept; otherwise the variant is named after the file.
+func overlayMetadata(systemTag, fileName string, previous map[string]string) map[string]string {
+	metadata := map[string]string{
+		"SystemTag":   systemTag,
+		"OverlayName": fileName,
+		"Variant":     overlayVariantLabel(fileName),
+	}
+
+	if previous != nil {
+		if variant := previous["Variant"]; variant != "" {
+			metadata["Variant"] = variant
+		}
+		if opacity := previous["Opacity"]; opacity != "" {
+			metadata["Opacity"] = opacity
+		}
+	}
+
+	return metadata
+}
+
+// overlaySystemDir returns a system's overlay folder on the device
+func overlaySystemDir(systemTag string) (string, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return "", fmt.Errorf("error getting system paths: %w", err)
+	}
+	return filepath.Join(systemPaths.Root, "Overlays", systemTag), nil
+}
+
+// appliedOverlayMetadata returns the variant metadata of the applied overlay package
+// for a system, keyed by overlay file name
+func appliedOverlayMetadata(systemTag string) map[string]map[string]string {
+	result := make(map[string]map[string]string)
+
+	componentName, err := GetAppliedComponent(ComponentOverlay)
+	if err != nil || componentName == "" {
+		return result
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return result
+	}
+
+	manifestObj, err := LoadComponentManifest(filepath.Join(cwd, "Components", ComponentDirectory[ComponentOverlay], componentName))
+	if err != nil {
+		return result
+	}
+
+	if manifest, ok := manifestObj.(*OverlayManifest); ok {
+		for _, mapping := range manifest.PathMappings {
+			if mapping.Metadata != nil && system.SameName(mapping.Metadata["SystemTag"], systemTag) {
+				result[mapping.Metadata["OverlayName"]] = mapping.Metadata
+			}
+		}
+	}
+
+	return result
+}
+
+// ListOverlayVariants returns the overlays installed for a system, labelled with the
+// variant names from the applied overlay package where it has them
+func ListOverlayVariants(systemTag string) ([]OverlayVariant, error) {
+	dir, err := overlaySystemDir(systemTag)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading overlays for %s: %w", systemTag, err)
+	}
+
+	metadata := appliedOverlayMetadata(systemTag)
+
+	var variants []OverlayVariant
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || name == overlayActiveSlot ||
+			!strings.HasSuffix(strings.ToLower(name), ".png") {
+			continue
+		}
+
+		variant := OverlayVariant{File: name, Label: overlayVariantLabel(name)}
+		if meta := metadata[name]; meta != nil {
+			if meta["Variant"] != "" {
+				variant.Label = meta["Variant"]
+			}
+			variant.Opacity = meta["Opacity"]
+		}
+		variants = append(variants, variant)
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].Label < variants[j].Label
+	})
+
+	return variants, nil
+}
+
+// GetOverlayVariantChoice returns the variant picked for a system; Opacity is 100 when never set
+func GetOverlayVariantChoice(systemTag string) OverlayVariantChoice {
+	choice := OverlayVariantChoice{Opacity: 100}
+
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load overlay variants: %v", err)
+		return choice
+	}
+
+	if saved, ok := config.OverlayVariants[systemTag]; ok {
+		choice.File = saved.File
+		if saved.Opacity > 0 {
+			choice.Opacity = saved.Opacity
+		}
+	}
+	return choice
+}
+
+// writeOverlayWithOpacity writes src to dst with its alpha channel scaled to opacity percent
+func writeOverlayWithOpacity(src, dst string, opacity int) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening overlay: %w", err)
+	}
+
+	img, err := png.Decode(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("error decoding overlay: %w", err)
+	}
+
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Src)
+
+	for i := 3; i < len(out.Pix); i += 4 {
+		out.Pix[i] = uint8(int(out.Pix[i]) * opacity / 100)
+	}
+
+	dstFile, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error writing overlay: %w", err)
+	}
+	defer dstFile.Close()
+
+	if err := png.Encode(dstFile, out); err != nil {
+		return fmt.Errorf("error encoding overlay: %w", err)
+	}
+	return nil
+}
+
+// SelectOverlayVariant writes a system's chosen overlay variant into its active slot at
+// the given opacity and remembers the choice
+func SelectOverlayVariant(systemTag string, fileName string, opacity int) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	dir, err := overlaySystemDir(systemTag)
+	if err != nil {
+		return err
+	}
+
+	if fileName == overlayActiveSlot {
+		return fmt.Errorf("%s is the active slot, not a variant", overlayActiveSlot)
+	}
+
+	srcPath := filepath.Join(dir, fileName)
+	slotPath := filepath.Join(dir, overlayActiveSlot)
+
+	if _, err := os.Stat(srcPath); err != nil {
+		return fmt.Errorf("overlay variant not found: %s", fileName)
+	}
+
+	beginPinnedApply()
+	if isPinnedDestination(slotPath) {
+		return fmt.Errorf("%s: %w", slotPath, errPinnedFile)
+	}
+
+	// Never write through a hard link into the package it points at
+	if err := detachHardLink(slotPath); err != nil {
+		logger.DebugFn("Warning: Could not detach hard link: %v", err)
+	}
+
+	if opacity <= 0 || opacity > 100 {
+		opacity = 100
+	}
+
+	if opacity == 100 {
+		err = CopyFile(srcPath, slotPath)
+	} else {
+		err = writeOverlayWithOpacity(srcPath, slotPath, opacity)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing active overlay: %w", err)
+	}
+
+	// The slot is ours, so cleanups may remove it like any other applied overlay
+	recordManagedFile(slotPath)
+	if err := saveManagedLedger(); err != nil {
+		logger.DebugFn("Warning: Could not save managed files ledger: %v", err)
+	}
+
+	logger.DebugFn("Selected overlay variant for %s: %s at %d%%", systemTag, fileName, opacity)
+
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if config.OverlayVariants == nil {
+		config.OverlayVariants = make(map[string]OverlayVariantChoice)
+	}
+	config.OverlayVariants[systemTag] = OverlayVariantChoice{File: fileName, Opacity: opacity}
+	return SaveConfig(config)
+}
+
+// SetOverlayOpacity changes a system's overlay opacity, rewriting the active slot when a
+// variant has been picked
+func SetOverlayOpacity(systemTag string, opacity int) error {
+	choice := GetOverlayVariantChoice(systemTag)
+	if choice.File != "" {
+		return SelectOverlayVariant(systemTag, choice.File, opacity)
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+	if config.OverlayVariants == nil {
+		config.OverlayVariants = make(map[string]OverlayVariantChoice)
+	}
+	config.OverlayVariants[systemTag] = OverlayVariantChoice{Opacity: opacity}
+	return SaveConfig(config)
+}
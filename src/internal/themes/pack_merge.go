@@ -0,0 +1,266 @@
+// src/internal/themes/pack_merge.go
+// Merges two installed wallpaper or icon packs into a new pack
+
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// MergeChoice says which file to keep when both packs have a different file at the same path
+type MergeChoice int
+
+const (
+	MergeKeepFirst  MergeChoice = iota // Keep the first pack's file
+	MergeKeepSecond                    // Keep the second pack's file
+	MergeKeepBoth                      // Keep the first pack's file, the second's goes to Alternates
+)
+
+// mergeAlternatesDir holds the files set aside by MergeKeepBoth. Applies and manifest updates
+// don't look in it, so an alternate only shows once it is moved over the file it replaces.
+const mergeAlternatesDir = "Alternates"
+
+// MergeablePackTypes are the component types MergeComponentPacks accepts
+var MergeablePackTypes = []string{ComponentWallpaper, ComponentIcon}
+
+// MergedPackName returns the name of the pack merged from first and second, e.g.
+// "Consoles+Arcade.icon"
+func MergedPackName(componentType, first, second string) string {
+	ext := ComponentExtension[componentType]
+	return strings.TrimSuffix(first, ext) + "+" + strings.TrimSuffix(second, ext) + ext
+}
+
+// packFiles lists a pack's files relative to its folder, leaving out its manifest and preview
+func packFiles(packPath string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(packPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(packPath, path)
+		if err != nil {
+			return err
+		}
+		if rel == "manifest.json" || rel == "preview.png" {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// alternateName returns where MergeKeepBoth puts the second pack's copy of rel
+func alternateName(rel, second string) string {
+	ext := filepath.Ext(rel)
+	tag := strings.TrimSuffix(second, filepath.Ext(second))
+	return filepath.Join(mergeAlternatesDir, strings.TrimSuffix(rel, ext)+" ["+tag+"]"+ext)
+}
+
+// MergeComponentPacks combines two installed packs of the same type into a new installed pack
+// and returns its name. Files only one pack has are copied as they are; files both packs have
+// with the same content are copied once. For every other file both packs have, resolve is
+// asked which to keep. The merged pack gets a fresh manifest built from its content.
+func MergeComponentPacks(componentType, first, second string, resolve func(rel string) MergeChoice) (name string, err error) {
+	name = MergedPackName(componentType, first, second)
+	defer func() { recordOperation("Merged packs", name, err) }()
+	defer logging.BeginOperation("merge packs", name)()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	supported := false
+	for _, t := range MergeablePackTypes {
+		if t == componentType {
+			supported = true
+		}
+	}
+	if !supported {
+		return "", fmt.Errorf("%s packs can't be merged", componentType)
+	}
+	if first == second {
+		return "", fmt.Errorf("pick two different packs to merge")
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	componentsDir := filepath.Join(cwd, "Components", ComponentDirectory[componentType])
+	firstPath := filepath.Join(componentsDir, first)
+	secondPath := filepath.Join(componentsDir, second)
+	mergedPath := filepath.Join(componentsDir, name)
+
+	if _, err := os.Stat(mergedPath); err == nil {
+		return "", fmt.Errorf("%s is already installed", name)
+	}
+
+	firstFiles, err := packFiles(firstPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", first, err)
+	}
+	secondFiles, err := packFiles(secondPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", second, err)
+	}
+
+	inFirst := make(map[string]bool)
+	for _, rel := range firstFiles {
+		inFirst[rel] = true
+	}
+
+	// Assemble next to the packs, so an interrupted merge never shows up as installed
+	tempPath := filepath.Join(componentsDir, ".merge-"+name)
+	if err := os.RemoveAll(tempPath); err != nil {
+		return "", fmt.Errorf("error clearing previous merge: %w", err)
+	}
+	defer os.RemoveAll(tempPath)
+
+	copyTo := func(src, rel string) error {
+		dst := filepath.Join(tempPath, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", rel, err)
+		}
+		if err := CopyFile(src, dst); err != nil {
+			return fmt.Errorf("error copying %s: %w", rel, err)
+		}
+		return nil
+	}
+
+	// Files taken from the second pack, whose original names come from its manifest
+	fromSecond := make(map[string]bool)
+
+	for _, rel := range firstFiles {
+		if err := copyTo(filepath.Join(firstPath, rel), rel); err != nil {
+			return "", err
+		}
+	}
+
+	conflicts := 0
+	for _, rel := range secondFiles {
+		src := filepath.Join(secondPath, rel)
+		if !inFirst[rel] {
+			if err := copyTo(src, rel); err != nil {
+				return "", err
+			}
+			fromSecond[rel] = true
+			continue
+		}
+
+		want, err := hashFile(filepath.Join(firstPath, rel))
+		if err != nil {
+			return "", fmt.Errorf("error reading %s: %w", rel, err)
+		}
+		got, err := hashFile(src)
+		if err != nil {
+			return "", fmt.Errorf("error reading %s: %w", rel, err)
+		}
+		if bytes.Equal(got, want) {
+			continue
+		}
+
+		conflicts++
+		switch resolve(filepath.ToSlash(rel)) {
+		case MergeKeepSecond:
+			if err := copyTo(src, rel); err != nil {
+				return "", err
+			}
+			fromSecond[rel] = true
+			logger.DebugFn("Kept %s from %s", rel, second)
+		case MergeKeepBoth:
+			alternate := alternateName(rel, second)
+			if err := copyTo(src, alternate); err != nil {
+				return "", err
+			}
+			logger.DebugFn("Kept both copies of %s, %s's as %s", rel, second, alternate)
+		default:
+			logger.DebugFn("Kept %s from %s", rel, first)
+		}
+	}
+
+	// The first pack's preview stands in until a new one is generated
+	if _, err := os.Stat(filepath.Join(firstPath, "preview.png")); err == nil {
+		if err := copyTo(filepath.Join(firstPath, "preview.png"), "preview.png"); err != nil {
+			logger.DebugFn("Warning: Could not copy preview: %v", err)
+		}
+	}
+
+	manifestObj, err := CreateComponentManifest(componentType, name)
+	if err != nil {
+		return "", err
+	}
+	info := GetComponentInfo(manifestObj)
+	info.Author = mergedAuthor(firstPath, secondPath)
+	info.FileNames = mergedFileNames(firstPath, secondPath, fromSecond)
+	if err := WriteComponentManifest(tempPath, manifestObj); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tempPath, mergedPath); err != nil {
+		return "", fmt.Errorf("error installing merged pack: %w", err)
+	}
+	if err := UpdateComponentManifest(mergedPath); err != nil {
+		logger.DebugFn("Warning: Error updating manifest of %s: %v", name, err)
+	}
+
+	logger.DebugFn("Merged %s and %s into %s (%d conflicts)", first, second, name, conflicts)
+	return name, nil
+}
+
+// mergedAuthor credits the authors of both packs
+func mergedAuthor(firstPath, secondPath string) string {
+	var authors []string
+	for _, path := range []string{firstPath, secondPath} {
+		manifestObj, err := LoadComponentManifest(path)
+		if err != nil {
+			continue
+		}
+		author := GetComponentInfo(manifestObj).Author
+		if author != "" && (len(authors) == 0 || authors[0] != author) {
+			authors = append(authors, author)
+		}
+	}
+	return strings.Join(authors, " & ")
+}
+
+// mergedFileNames carries over the original names of files renamed to be FAT32-safe, from
+// whichever pack each file came from
+func mergedFileNames(firstPath, secondPath string, fromSecond map[string]bool) map[string]string {
+	names := make(map[string]string)
+	copyNames := func(packPath string, keep func(rel string) bool) {
+		manifestObj, err := LoadComponentManifest(packPath)
+		if err != nil {
+			return
+		}
+		for rel, original := range GetComponentInfo(manifestObj).FileNames {
+			if keep(filepath.FromSlash(rel)) {
+				names[rel] = original
+			}
+		}
+	}
+
+	copyNames(firstPath, func(rel string) bool { return !fromSecond[rel] })
+	copyNames(secondPath, func(rel string) bool { return fromSecond[rel] })
+
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
@@ -0,0 +1,189 @@
+// src/internal/themes/patch.go
+// Patch packages: theme updates holding only the files that changed since a base version
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ThemePatch marks a theme package as a patch over an installed base version. The package
+// holds the full manifest of the new version but only the files that were added or changed.
+type ThemePatch struct {
+	BaseVersion string   `json:"base_version"`      // Version the patch must be applied over
+	Removed     []string `json:"removed,omitempty"` // Theme paths deleted since the base version
+}
+
+// CatalogPatch points to a patch package that updates a catalog theme from an older version
+type CatalogPatch struct {
+	BaseVersion string `json:"base_version"`
+	URL         string `json:"URL"`
+	SHA256      string `json:"sha256,omitempty"` // Checked before the patch is applied
+}
+
+// IsPatchPackage reports whether the theme package at themePath is a patch
+func IsPatchPackage(themePath string) bool {
+	manifest, err := ValidateTheme(themePath, &Logger{DebugFn: logging.LogDebug})
+	return err == nil && manifest.Patch != nil
+}
+
+// copyTree copies every file under src into dst, keeping the layout
+func copyTree(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		return CopyFile(path, target)
+	})
+}
+
+// insidePackage resolves a theme path from a patch manifest, refusing paths that leave the package
+func insidePackage(packagePath, themePath string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(themePath))
+	if filepath.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %s is outside the package", themePath)
+	}
+	return filepath.Join(packagePath, cleaned), nil
+}
+
+// ApplyThemePatch merges a patch package over the installed theme at themePath. The patched
+// theme is built in the cache and only swapped in once complete; the old version is archived.
+func ApplyThemePatch(patchPath, themePath string, logger *Logger) error {
+	patchManifest, err := ValidateTheme(patchPath, logger)
+	if err != nil {
+		return fmt.Errorf("error reading patch: %w", err)
+	}
+	if patchManifest.Patch == nil {
+		return fmt.Errorf("%s is not a patch package", filepath.Base(patchPath))
+	}
+
+	baseManifest, err := ValidateTheme(themePath, logger)
+	if err != nil {
+		return fmt.Errorf("error reading installed theme: %w", err)
+	}
+
+	baseVersion := patchManifest.Patch.BaseVersion
+	if CompareVersions(baseManifest.ThemeInfo.Version, baseVersion) != 0 {
+		return fmt.Errorf("patch needs version %s but version %s is installed",
+			baseVersion, defaultString(baseManifest.ThemeInfo.Version, "unknown"))
+	}
+
+	logger.DebugFn("Patching %s from %s to %s", filepath.Base(themePath), baseVersion, patchManifest.ThemeInfo.Version)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	stagingPath := filepath.Join(cwd, ".cache", filepath.Base(themePath)+".patching")
+	os.RemoveAll(stagingPath)
+
+	if err := copyTree(themePath, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return fmt.Errorf("error copying installed theme: %w", err)
+	}
+
+	// Overlay the changed files, the manifest is written separately below
+	err = filepath.Walk(patchPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(patchPath, path)
+		if err != nil || rel == "manifest.json" {
+			return err
+		}
+		return CopyFile(path, filepath.Join(stagingPath, rel))
+	})
+	if err != nil {
+		os.RemoveAll(stagingPath)
+		return fmt.Errorf("error applying patch files: %w", err)
+	}
+
+	for _, removed := range patchManifest.Patch.Removed {
+		removedPath, err := insidePackage(stagingPath, removed)
+		if err != nil {
+			logger.DebugFn("Warning: Skipping removal: %v", err)
+			continue
+		}
+		if err := os.RemoveAll(removedPath); err != nil {
+			logger.DebugFn("Warning: Could not remove %s: %v", removed, err)
+		}
+	}
+
+	// The patched theme is a full package again
+	patchManifest.Patch = nil
+	if err := saveManifest(stagingPath, patchManifest, logger); err != nil {
+		os.RemoveAll(stagingPath)
+		return err
+	}
+
+	beginTrashBatch()
+	if err := archiveInstalledPackage(themePath, logger); err != nil {
+		os.RemoveAll(stagingPath)
+		return fmt.Errorf("error replacing installed theme: %w", err)
+	}
+
+	if err := os.Rename(stagingPath, themePath); err != nil {
+		return fmt.Errorf("error installing patched theme: %w", err)
+	}
+
+	logger.DebugFn("Patched %s to version %s", filepath.Base(themePath), patchManifest.ThemeInfo.Version)
+	return nil
+}
+
+// downloadThemePatch downloads a catalog patch and applies it over the installed theme
+func downloadThemePatch(patch CatalogPatch, themePath, cacheDir string) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	name := filepath.Base(themePath)
+	zipPath := filepath.Join(cacheDir, name+".patch.zip")
+	patchPath := filepath.Join(cacheDir, name+".patch")
+	defer os.RemoveAll(patchPath)
+
+	logger.DebugFn("Downloading patch for %s from %s", name, patch.URL)
+	if err := downloadPackage(patch.URL, zipPath, patch.SHA256); err != nil {
+		return fmt.Errorf("error downloading patch: %w", err)
+	}
+
+	os.RemoveAll(patchPath)
+	err := extractZipFile(zipPath, patchPath)
+	os.Remove(zipPath)
+	if err != nil {
+		return fmt.Errorf("error extracting patch: %w", err)
+	}
+
+	return ApplyThemePatch(patchPath, themePath, logger)
+}
+
+// catalogPatchFor returns the catalog patch that updates an installed theme, if there is one
+func catalogPatchFor(item CatalogItemInfo, themePath string) (CatalogPatch, bool) {
+	manifest, err := ValidateTheme(themePath, &Logger{DebugFn: logging.LogDebug})
+	if err != nil {
+		return CatalogPatch{}, false
+	}
+
+	for _, patch := range item.Patches {
+		if patch.URL != "" && CompareVersions(patch.BaseVersion, manifest.ThemeInfo.Version) == 0 {
+			return patch, true
+		}
+	}
+	return CatalogPatch{}, false
+}
@@ -0,0 +1,63 @@
+// src/internal/themes/pin.go
+// Button sequence PINs protecting restores and deletions
+
+package themes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ErrWrongPIN is returned when an entered PIN doesn't match the stored one
+var ErrWrongPIN = errors.New("wrong PIN")
+
+// hashPIN returns the hash a button sequence PIN is stored as
+func hashPIN(pin []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(pin, "-")))
+	return hex.EncodeToString(sum[:])
+}
+
+// HasProtectionPIN reports whether rollbacks, restores and discarding edits ask for a PIN
+func HasProtectionPIN() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load PIN setting: %v", err)
+		return false
+	}
+	return config.ProtectPIN != ""
+}
+
+// SetProtectionPIN stores the PIN protected operations ask for; an empty PIN removes it
+func SetProtectionPIN(pin []string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.ProtectPIN = ""
+	if len(pin) > 0 {
+		config.ProtectPIN = hashPIN(pin)
+	}
+	logging.LogDebug("Protection PIN set: %t", config.ProtectPIN != "")
+	return SaveConfig(config)
+}
+
+// CheckProtectionPIN returns ErrWrongPIN if pin doesn't match the stored PIN. It passes
+// when no PIN is set.
+func CheckProtectionPIN(pin []string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	if config.ProtectPIN != "" && hashPIN(pin) != config.ProtectPIN {
+		logging.LogDebug("Wrong PIN entered for a protected operation")
+		return ErrWrongPIN
+	}
+	return nil
+}
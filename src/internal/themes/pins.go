@@ -0,0 +1,200 @@
+// src/internal/themes/pins.go
+// User-pinned files that theme and component applies must never touch
+
+package themes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// errPinnedFile is returned when a cleanup or copy hits a pinned destination
+var errPinnedFile = errors.New("file is pinned")
+
+// pinnedState tracks pinned destinations for the apply in progress
+var pinnedState struct {
+	paths   map[string]bool
+	skipped map[string]bool
+}
+
+// beginPinnedApply reloads the pinned paths from config and clears the skip report
+func beginPinnedApply() {
+	pinnedState.paths = make(map[string]bool)
+	pinnedState.skipped = make(map[string]bool)
+
+	for _, path := range GetPinnedPaths() {
+		pinnedState.paths[filepath.Clean(path)] = true
+	}
+
+	if len(pinnedState.paths) > 0 {
+		logging.LogDebug("Loaded %d pinned files", len(pinnedState.paths))
+	}
+}
+
+// isPinnedDestination reports whether an apply should leave this path alone
+func isPinnedDestination(path string) bool {
+	if pinnedState.paths == nil {
+		beginPinnedApply()
+	}
+
+	if !pinnedState.paths[filepath.Clean(path)] {
+		return false
+	}
+
+	pinnedState.skipped[filepath.Clean(path)] = true
+	return true
+}
+
+// removeThemeFile moves a file to the trash unless the user pinned it or the manager didn't write it
+func removeThemeFile(path string) error {
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+	if isPinnedDestination(path) {
+		return fmt.Errorf("%s: %w", path, errPinnedFile)
+	}
+	if !canCleanupFile(path) {
+		return fmt.Errorf("%s (%s): %w", path, ClassifyMediaFile(path), errNotManaged)
+	}
+	if cleanupPreview != nil {
+		cleanupPreview[filepath.Clean(path)] = true
+		return nil
+	}
+	if err := moveToTrash(path); err != nil {
+		return err
+	}
+	forgetManagedFile(path)
+	return nil
+}
+
+// GetPinnedSkips returns the pinned files that were left untouched by the last apply
+func GetPinnedSkips() []string {
+	var skipped []string
+	for path := range pinnedState.skipped {
+		skipped = append(skipped, path)
+	}
+	sort.Strings(skipped)
+	return skipped
+}
+
+// pinnedSummary returns a line for apply messages describing skipped pinned files
+func pinnedSummary() string {
+	count := len(pinnedState.skipped)
+	if count == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n%d pinned file(s) left untouched", count)
+}
+
+// GetPinnedPaths returns the pinned paths stored in the configuration
+func GetPinnedPaths() []string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load pinned files: %v", err)
+		return nil
+	}
+	return config.PinnedPaths
+}
+
+// TogglePinnedPath pins a path if it isn't pinned yet, otherwise unpins it
+func TogglePinnedPath(path string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	path = filepath.Clean(path)
+
+	var updated []string
+	found := false
+	for _, pinned := range config.PinnedPaths {
+		if filepath.Clean(pinned) == path {
+			found = true
+			continue
+		}
+		updated = append(updated, pinned)
+	}
+
+	if !found {
+		updated = append(updated, path)
+		logging.LogDebug("Pinned file: %s", path)
+	} else {
+		logging.LogDebug("Unpinned file: %s", path)
+	}
+
+	config.PinnedPaths = updated
+	return SaveConfig(config)
+}
+
+// ListPinnableFiles returns the wallpapers, icons and overlays currently on the device
+func ListPinnableFiles() ([]string, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	// Directories that hold themed media
+	mediaDirs := []string{
+		filepath.Join(systemPaths.Root, ".media"),
+		filepath.Join(systemPaths.Roms, ".media"),
+		filepath.Join(systemPaths.RecentlyPlayed, ".media"),
+		filepath.Join(filepath.Dir(systemPaths.Tools), ".media"),
+		filepath.Join(systemPaths.Tools, ".media"),
+		filepath.Join(systemPaths.Root, "Collections", ".media"),
+	}
+
+	for _, sys := range systemPaths.Systems {
+		mediaDirs = append(mediaDirs, sys.MediaPath)
+	}
+
+	// Tool, collection and overlay folders
+	parentDirs := []string{
+		systemPaths.Tools,
+		filepath.Join(systemPaths.Root, "Collections"),
+	}
+	for _, parent := range parentDirs {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				mediaDirs = append(mediaDirs, filepath.Join(parent, entry.Name(), ".media"))
+			}
+		}
+	}
+
+	overlaysDir := filepath.Join(systemPaths.Root, "Overlays")
+	if entries, err := os.ReadDir(overlaysDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				mediaDirs = append(mediaDirs, filepath.Join(overlaysDir, entry.Name()))
+			}
+		}
+	}
+
+	// Root wallpaper lives outside any .media directory
+	var files []string
+	rootBg := filepath.Join(systemPaths.Root, "bg.png")
+	if _, err := os.Stat(rootBg); err == nil {
+		files = append(files, rootBg)
+	}
+
+	for _, dir := range mediaDirs {
+		matches, err := filepath.Glob(filepath.Join(dir, "*.png"))
+		if err != nil {
+			continue
+		}
+		files = append(files, matches...)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
@@ -0,0 +1,279 @@
+// src/internal/themes/preview_stamp.go
+// Stamps exported previews with author credit: PNG text metadata and an optional corner watermark
+
+package themes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"nextui-themes/internal/logging"
+)
+
+// pngSignature starts every PNG file
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1A, '\n'}
+
+// previewTextKeys are the metadata keywords written by stampPreview; older values are replaced
+var previewTextKeys = map[string]bool{"Title": true, "Author": true, "Software": true}
+
+// watermarkGlyphs is a 5x7 bitmap font for the watermark and setup summaries; each row uses
+// the low five bits
+var watermarkGlyphs = map[rune][7]byte{
+	'A':  {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C':  {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D':  {0b11100, 0b10010, 0b10001, 0b10001, 0b10001, 0b10010, 0b11100},
+	'E':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F':  {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G':  {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H':  {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I':  {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J':  {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K':  {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L':  {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M':  {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N':  {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q':  {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R':  {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S':  {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T':  {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V':  {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W':  {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X':  {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y':  {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'0':  {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1':  {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2':  {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3':  {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4':  {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5':  {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6':  {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7':  {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8':  {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9':  {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'.':  {0, 0, 0, 0, 0, 0b01100, 0b01100},
+	'-':  {0, 0, 0, 0b11111, 0, 0, 0},
+	'_':  {0, 0, 0, 0, 0, 0, 0b11111},
+	'\'': {0b01100, 0b00100, 0b01000, 0, 0, 0, 0},
+	'&':  {0b01100, 0b10010, 0b10100, 0b01000, 0b10101, 0b10010, 0b01101},
+	':':  {0, 0b01100, 0b01100, 0, 0b01100, 0b01100, 0},
+	',':  {0, 0, 0, 0, 0b01100, 0b00100, 0b01000},
+	'/':  {0b00001, 0b00010, 0b00010, 0b00100, 0b01000, 0b01000, 0b10000},
+	'(':  {0b00010, 0b00100, 0b01000, 0b01000, 0b01000, 0b00100, 0b00010},
+	')':  {0b01000, 0b00100, 0b00010, 0b00010, 0b00010, 0b00100, 0b01000},
+	'#':  {0b01010, 0b01010, 0b11111, 0b01010, 0b11111, 0b01010, 0b01010},
+	'+':  {0, 0b00100, 0b00100, 0b11111, 0b00100, 0b00100, 0},
+}
+
+// GetPreviewWatermarkSetting reports whether exported previews get a corner watermark
+func GetPreviewWatermarkSetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load watermark setting: %v", err)
+		return false
+	}
+	return config.PreviewWatermark
+}
+
+// SetPreviewWatermarkSetting turns the preview watermark on or off
+func SetPreviewWatermarkSetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.PreviewWatermark = enabled
+	return SaveConfig(config)
+}
+
+// drawWatermark writes "BY AUTHOR" into the bottom-right corner, scaled to the image
+// and drawn with a shadow so it stays readable on any background
+func drawWatermark(img draw.Image, author string) {
+	text := "BY " + strings.ToUpper(author)
+	bounds := img.Bounds()
+
+	// Text about 1/40 of the image height, never smaller than the raw font
+	scale := bounds.Dy() / 280
+	if scale < 1 {
+		scale = 1
+	}
+	advance := 6 * scale
+	width := utf8.RuneCountInString(text)*advance - scale
+	margin := 4 * scale
+
+	originX := bounds.Max.X - width - margin
+	originY := bounds.Max.Y - 7*scale - margin
+	if originX < bounds.Min.X {
+		originX = bounds.Min.X + margin
+	}
+
+	shadow := image.NewUniform(color.NRGBA{0, 0, 0, 160})
+	ink := image.NewUniform(color.NRGBA{255, 255, 255, 200})
+
+	for _, layer := range []struct {
+		src    image.Image
+		offset int
+	}{{shadow, scale}, {ink, 0}} {
+		x := originX
+		for _, r := range text {
+			glyph, ok := watermarkGlyphs[r]
+			if ok {
+				for row, bits := range glyph {
+					for col := 0; col < 5; col++ {
+						if bits&(1<<uint(4-col)) == 0 {
+							continue
+						}
+						px := x + col*scale + layer.offset
+						py := originY + row*scale + layer.offset
+						draw.Draw(img, image.Rect(px, py, px+scale, py+scale), layer.src, image.Point{}, draw.Over)
+					}
+				}
+			}
+			x += advance
+		}
+	}
+}
+
+// pngTextChunk builds a tEXt chunk, or an iTXt chunk when the text isn't Latin-1
+func pngTextChunk(keyword, text string) []byte {
+	var data bytes.Buffer
+	chunkType := "tEXt"
+
+	latin1 := true
+	for _, r := range text {
+		if r > 0xFF {
+			latin1 = false
+			break
+		}
+	}
+
+	data.WriteString(keyword)
+	data.WriteByte(0)
+	if latin1 {
+		for _, r := range text {
+			data.WriteByte(byte(r))
+		}
+	} else {
+		// iTXt: no compression, empty language tag and translated keyword, UTF-8 text
+		chunkType = "iTXt"
+		data.Write([]byte{0, 0, 0, 0})
+		data.WriteString(text)
+	}
+
+	chunk := make([]byte, 8, 12+data.Len())
+	binary.BigEndian.PutUint32(chunk[0:4], uint32(data.Len()))
+	copy(chunk[4:8], chunkType)
+	chunk = append(chunk, data.Bytes()...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	return chunk
+}
+
+// withPNGText returns a PNG with the given text metadata placed right after its header,
+// replacing any earlier values for the same keywords
+func withPNGText(data []byte, fields [][2]string) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, fmt.Errorf("not a PNG file")
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+
+	for pos := len(pngSignature); pos+8 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		body := data[pos+8 : pos+8+length]
+
+		keep := true
+		if chunkType == "tEXt" || chunkType == "iTXt" {
+			if keyword, _, found := bytes.Cut(body, []byte{0}); found && previewTextKeys[string(keyword)] {
+				keep = false
+			}
+		}
+		if keep {
+			out.Write(data[pos:end])
+		}
+
+		if chunkType == "IHDR" {
+			for _, field := range fields {
+				if field[1] != "" {
+					out.Write(pngTextChunk(field[0], field[1]))
+				}
+			}
+		}
+		pos = end
+	}
+
+	return out.Bytes(), nil
+}
+
+// stampPreview credits the author in a package's preview.png: the name, author and exporting
+// version go into the PNG metadata, and a small watermark is drawn when enabled in settings.
+// Packages without a preview (or with the blank placeholder) are left alone.
+func stampPreview(packagePath, name, author string, logger *Logger) {
+	previewPath := filepath.Join(packagePath, "preview.png")
+	info, err := os.Stat(previewPath)
+	if err != nil || info.Size() == 0 {
+		return
+	}
+
+	data, err := os.ReadFile(previewPath)
+	if err != nil {
+		logger.DebugFn("Warning: Could not read preview to stamp: %v", err)
+		return
+	}
+
+	if author != "" && GetPreviewWatermarkSetting() {
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			logger.DebugFn("Warning: Could not decode preview for watermark: %v", err)
+		} else {
+			canvas := image.NewNRGBA(img.Bounds())
+			draw.Draw(canvas, canvas.Bounds(), img, img.Bounds().Min, draw.Src)
+			drawWatermark(canvas, author)
+
+			var encoded bytes.Buffer
+			if err := png.Encode(&encoded, canvas); err != nil {
+				logger.DebugFn("Warning: Could not encode watermarked preview: %v", err)
+			} else {
+				data = encoded.Bytes()
+			}
+		}
+	}
+
+	stamped, err := withPNGText(data, [][2]string{
+		{"Title", strings.TrimSuffix(name, filepath.Ext(name))},
+		{"Author", author},
+		{"Software", GetVersionString()},
+	})
+	if err != nil {
+		logger.DebugFn("Warning: Could not add metadata to preview: %v", err)
+		return
+	}
+
+	// The preview may be a hard link or copy of an applied wallpaper; replace the file, never write through
+	os.Remove(previewPath)
+	if err := os.WriteFile(previewPath, stamped, 0644); err != nil {
+		logger.DebugFn("Warning: Could not write stamped preview: %v", err)
+		return
+	}
+
+	logger.DebugFn("Stamped preview of %s with author credit", name)
+}
@@ -0,0 +1,70 @@
+// src/internal/themes/provenance.go
+// Where an installed component package came from
+
+package themes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Origins of a component package
+const (
+	ProvenanceDownloaded    = "downloaded"
+	ProvenanceDeconstructed = "deconstructed"
+	ProvenanceExported      = "exported"
+)
+
+// Provenance records how a component package was made or obtained. It is written to the
+// package's manifest, so it travels with the package when it is moved or shared.
+type Provenance struct {
+	Origin string    `json:"origin"`           // One of the Provenance* origins
+	Theme  string    `json:"theme,omitempty"`  // Theme a deconstructed package came from
+	Source string    `json:"source,omitempty"` // Catalog entry a download came from
+	Date   time.Time `json:"date"`
+}
+
+// recordProvenance stamps a component package's manifest with where it came from
+func recordProvenance(componentPath string, provenance Provenance, logger *Logger) {
+	if componentPath == "" {
+		return
+	}
+
+	manifest, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		logger.DebugFn("Warning: Could not record provenance of %s: %v", componentPath, err)
+		return
+	}
+	info := GetComponentInfo(manifest)
+	if info == nil {
+		return
+	}
+
+	provenance.Date = time.Now()
+	info.Provenance = &provenance
+	if err := WriteComponentManifest(componentPath, manifest); err != nil {
+		logger.DebugFn("Warning: Could not record provenance of %s: %v", componentPath, err)
+	}
+}
+
+// DescribeProvenance sums up where a package came from for galleries, e.g.
+// "from Retro.theme", or "" when it isn't known
+func DescribeProvenance(provenance *Provenance) string {
+	if provenance == nil {
+		return ""
+	}
+
+	switch provenance.Origin {
+	case ProvenanceDownloaded:
+		return "downloaded"
+	case ProvenanceDeconstructed:
+		if provenance.Theme != "" {
+			return fmt.Sprintf("from %s", provenance.Theme)
+		}
+		return "deconstructed"
+	case ProvenanceExported:
+		return "exported from device"
+	}
+	return strings.ToLower(provenance.Origin)
+}
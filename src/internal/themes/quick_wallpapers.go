@@ -0,0 +1,140 @@
+// src/internal/themes/quick_wallpapers.go
+// Swaps the wallpaper of one slot for any image from an installed wallpaper pack
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// ListQuickWallpaperSources returns the images of every installed wallpaper pack, as
+// <pack>/<path in pack>
+func ListQuickWallpaperSources() ([]string, error) {
+	return ListSwapSources(ComponentWallpaper)
+}
+
+// QuickWallpaperSourcePath returns the file of a quick wallpaper source
+func QuickWallpaperSourcePath(source string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	componentsDir := filepath.Join(cwd, "Components", ComponentDirectory[ComponentWallpaper])
+	sourcePath := filepath.Join(componentsDir, filepath.FromSlash(source))
+	if !strings.HasPrefix(sourcePath, componentsDir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid wallpaper source: %s", source)
+	}
+	return sourcePath, nil
+}
+
+// GetWallpaperOverrides returns the quick wallpaper picked for each slot since the last
+// wallpaper apply
+func GetWallpaperOverrides() map[string]string {
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load wallpaper overrides: %v", err)
+		return nil
+	}
+	return manifest.WallpaperOverrides
+}
+
+// installQuickWallpaper copies a pack image over a slot's wallpaper, moving the old one to the trash
+func installQuickWallpaper(slot, source string, systemPaths *system.SystemPaths) error {
+	sourcePath, err := QuickWallpaperSourcePath(source)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(sourcePath); err != nil {
+		return fmt.Errorf("wallpaper %s is no longer installed", source)
+	}
+
+	dstPath, err := wallpaperSlotPath(slot, false, systemPaths)
+	if err != nil {
+		return err
+	}
+	for _, pinned := range GetPinnedPaths() {
+		if filepath.Clean(pinned) == filepath.Clean(dstPath) {
+			return fmt.Errorf("the wallpaper of %s is pinned, unpin it in Settings first", slot)
+		}
+	}
+
+	if _, err := os.Stat(dstPath); err == nil {
+		if err := moveToTrash(dstPath); err != nil {
+			return fmt.Errorf("error replacing wallpaper: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	if err := CopyFile(sourcePath, dstPath); err != nil {
+		return fmt.Errorf("error copying wallpaper: %w", err)
+	}
+
+	logging.LogDebug("Quick wallpaper for %s: %s -> %s", slot, source, dstPath)
+	return nil
+}
+
+// SetQuickWallpaper puts an image from an installed wallpaper pack on one slot and records
+// the override in the global manifest, so a reapply puts it back
+func SetQuickWallpaper(slot, source string) (err error) {
+	defer func() { recordOperation("Quick wallpaper", slot+": "+source, err) }()
+
+	if err := CheckKioskMode("changing a wallpaper"); err != nil {
+		return err
+	}
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	beginTrashBatch()
+	if err := installQuickWallpaper(slot, source, systemPaths); err != nil {
+		return err
+	}
+
+	return UpdateGlobalManifest(func(manifest *GlobalManifest) error {
+		if manifest.WallpaperOverrides == nil {
+			manifest.WallpaperOverrides = make(map[string]string)
+		}
+		manifest.WallpaperOverrides[slot] = source
+		return nil
+	})
+}
+
+// reapplyWallpaperOverrides puts recorded quick wallpapers back after a reapply and records
+// them again, since the wallpaper apply before them cleared the record
+func reapplyWallpaperOverrides(overrides map[string]string) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	kept := make(map[string]string)
+	for slot, source := range overrides {
+		if err := installQuickWallpaper(slot, source, systemPaths); err != nil {
+			logging.LogDebug("Warning: Could not reapply quick wallpaper for %s: %v", slot, err)
+			continue
+		}
+		kept[slot] = source
+	}
+
+	return UpdateGlobalManifest(func(manifest *GlobalManifest) error {
+		manifest.WallpaperOverrides = kept
+		return nil
+	})
+}
@@ -0,0 +1,107 @@
+// src/internal/themes/reapply.go
+// Replays the theme and components recorded in the global manifest
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+)
+
+// reapplyComponentOrder is the order recorded components are replayed in when no theme is applied
+var reapplyComponentOrder = []string{
+	ComponentWallpaper,
+	ComponentIcon,
+	ComponentFont,
+	ComponentOverlay,
+	ComponentGameArt,
+	ComponentAccent,
+	ComponentLED,
+	ComponentShader,
+}
+
+// ReapplyStep is one package to apply when replaying the current setup
+type ReapplyStep struct {
+	Type string `json:"type"` // "theme" or a component type
+	Name string `json:"name"` // Package folder name
+	Path string `json:"path"`
+}
+
+// CurrentSetup returns the packages to apply, in order, to restore the recorded setup: the
+// current theme followed by the components applied over it. Recorded packages that are no
+// longer installed are returned as missing.
+func CurrentSetup() ([]ReapplyStep, []string, error) {
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	var steps []ReapplyStep
+	var missing []string
+
+	add := func(step ReapplyStep) {
+		if _, err := os.Stat(step.Path); err != nil {
+			logging.LogDebug("Warning: Recorded %s %s is no longer installed", step.Type, step.Name)
+			missing = append(missing, step.Name)
+			return
+		}
+		steps = append(steps, step)
+	}
+
+	componentTypes := reapplyComponentOrder
+	if manifest.CurrentTheme != "" {
+		add(ReapplyStep{
+			Type: "theme",
+			Name: manifest.CurrentTheme,
+			Path: filepath.Join(cwd, "Themes", manifest.CurrentTheme),
+		})
+		componentTypes = manifest.AppliedSinceTheme
+	}
+
+	for _, componentType := range componentTypes {
+		name, err := GetAppliedComponent(componentType)
+		if err != nil || name == "" {
+			continue
+		}
+		add(ReapplyStep{
+			Type: componentType,
+			Name: name,
+			Path: filepath.Join(cwd, "Components", ComponentDirectory[componentType], name),
+		})
+	}
+
+	return steps, missing, nil
+}
+
+// ReapplyCurrentSetup applies every step of the current setup in order, stopping at the first
+// failure or once ctx is done, then puts back the quick wallpapers picked since
+func ReapplyCurrentSetup(ctx context.Context, steps []ReapplyStep) error {
+	var overrides map[string]string
+	if manifest, err := LoadGlobalManifest(); err == nil {
+		overrides = manifest.WallpaperOverrides
+	}
+
+	for _, step := range steps {
+		logging.LogDebug("Reapplying %s %s", step.Type, step.Name)
+
+		var err error
+		if step.Type == "theme" {
+			err = ImportThemeContext(ctx, step.Name)
+		} else {
+			err = ImportComponentContext(ctx, step.Path)
+		}
+		if err != nil {
+			return fmt.Errorf("error reapplying %s: %w", step.Name, err)
+		}
+	}
+	return reapplyWallpaperOverrides(overrides)
+}
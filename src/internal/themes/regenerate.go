@@ -0,0 +1,98 @@
+// src/internal/themes/regenerate.go
+// Rebuilds the manifests of every installed package in one pass
+
+package themes
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// RegenerateThemeManifest rebuilds a theme's media mappings from the files in the package.
+// Existing mappings are dropped first so destinations that moved in a NextUI update are
+// picked up fresh; theme info, settings, accents and LEDs are kept.
+func RegenerateThemeManifest(themePath string, systemPaths *system.SystemPaths, logger *Logger) error {
+	if HasLegacyLayout(themePath) {
+		if _, err := MigrateLegacyLayout(themePath, logger); err != nil {
+			logger.DebugFn("Warning: Could not migrate legacy theme layout: %v", err)
+		}
+	}
+
+	manifest, err := ValidateTheme(themePath, logger)
+	if err != nil {
+		return fmt.Errorf("error loading theme manifest: %w", err)
+	}
+
+	manifest.PathMappings.Wallpapers = nil
+	manifest.PathMappings.Icons = nil
+	manifest.PathMappings.Overlays = nil
+	manifest.PathMappings.Fonts = make(map[string]PathMapping)
+	manifest.PathMappings.GameArt = make(map[string]PathMapping)
+
+	manifest.Content.Wallpapers.Present = false
+	manifest.Content.Wallpapers.Count = 0
+	manifest.Content.Icons.Present = false
+	manifest.Content.Icons.SystemCount = 0
+	manifest.Content.Icons.ToolCount = 0
+	manifest.Content.Icons.CollectionCount = 0
+	manifest.Content.Overlays.Present = false
+	manifest.Content.Overlays.Systems = nil
+	manifest.Content.Fonts.Present = false
+	manifest.Content.Fonts.OGReplaced = false
+	manifest.Content.Fonts.NextReplaced = false
+	manifest.Content.GameArt.Present = false
+	manifest.Content.GameArt.PlaceholderReplaced = false
+	manifest.Content.GameArt.FrameReplaced = false
+
+	return UpdateManifestFromThemeContent(themePath, manifest, systemPaths, logger)
+}
+
+// RegenerateAllManifests rebuilds the manifest of every package in Themes/ and Components/.
+// progress is called before each package; the names of packages that failed are returned.
+func RegenerateAllManifests(progress func(current, total int, name string)) ([]string, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+	defer logging.LogMemoryUsage("manifest regeneration")
+
+	packages, err := ListInstalledPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	// One pass for every package, so system paths and shared folders are read once
+	defer beginScanPass()()
+
+	systemPaths, err := scanSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	logger.DebugFn("Regenerating manifests for %d packages", len(packages))
+
+	var failed []string
+	for i, pkg := range packages {
+		name := filepath.Base(pkg)
+		if progress != nil {
+			progress(i+1, len(packages), name)
+		}
+
+		var err error
+		if filepath.Ext(pkg) == ".theme" {
+			err = RegenerateThemeManifest(pkg, systemPaths, logger)
+		} else {
+			err = UpdateComponentManifest(pkg)
+		}
+
+		if err != nil {
+			logger.DebugFn("Warning: Could not regenerate manifest for %s: %v", name, err)
+			failed = append(failed, name)
+		}
+	}
+
+	logger.DebugFn("Manifest regeneration finished, %d of %d failed", len(failed), len(packages))
+	return failed, nil
+}
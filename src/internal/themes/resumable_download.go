@@ -0,0 +1,212 @@
+// src/internal/themes/resumable_download.go
+// Package downloads that resume where a dropped connection left off and are checked
+// against the catalog's SHA-256 before use
+
+package themes
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// downloadAttempts is how many times a package download is tried before giving up
+const downloadAttempts = 5
+
+// downloadRetryDelay is the wait before the first retry, doubled after every failed attempt
+const downloadRetryDelay = 2 * time.Second
+
+// errChecksumMismatch is returned when a finished download doesn't match the catalog
+var errChecksumMismatch = errors.New("download is corrupt, its checksum doesn't match the catalog")
+
+// downloadPackage downloads url to localPath, retrying dropped connections from where
+// they stopped. The bytes received so far are kept in localPath + ".part", so a download
+// interrupted by closing the app also resumes next time. With a checksum, the SHA-256 of
+// the finished file must match it before it is moved to localPath.
+func downloadPackage(url, localPath, checksum string) error {
+	return downloadPackageContext(context.Background(), url, localPath, checksum)
+}
+
+// downloadPackageContext is downloadPackage, aborting the transfer once ctx is done. The
+// partial file is kept so a later download resumes it.
+func downloadPackageContext(ctx context.Context, url, localPath, checksum string) error {
+	var want []byte
+	if checksum != "" {
+		decoded, err := hex.DecodeString(strings.TrimSpace(checksum))
+		if err != nil || len(decoded) != 32 {
+			return fmt.Errorf("invalid SHA-256 in catalog: %s", checksum)
+		}
+		want = decoded
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", filepath.Dir(localPath), err)
+	}
+
+	partPath := localPath + ".part"
+	delay := downloadRetryDelay
+	var lastErr error
+
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		if attempt > 1 {
+			logging.LogDebug("Retrying download of %s in %s (attempt %d of %d): %v", url, delay, attempt, downloadAttempts, lastErr)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		lastErr = downloadPart(ctx, url, partPath)
+		if lastErr == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// Client errors won't go away by asking again
+		var status httpStatusError
+		if errors.As(lastErr, &status) && status.code >= 400 && status.code < 500 {
+			break
+		}
+	}
+	if lastErr != nil {
+		if strings.Contains(lastErr.Error(), "timeout") || strings.Contains(lastErr.Error(), "deadline") {
+			return fmt.Errorf("download timed out - try again to resume it: %w", lastErr)
+		}
+		return fmt.Errorf("download error: %w", lastErr)
+	}
+
+	if want != nil {
+		got, err := hashFile(partPath)
+		if err != nil {
+			return fmt.Errorf("error checking download: %w", err)
+		}
+		if !bytes.Equal(got, want) {
+			// Starting over is the only way to get a good copy
+			logging.LogDebug("Checksum mismatch for %s: got %x, want %x", url, got, want)
+			removePartialDownload(partPath)
+			return errChecksumMismatch
+		}
+		logging.LogDebug("Verified checksum of %s", filepath.Base(localPath))
+	}
+
+	os.Remove(partPath + ".etag")
+	if err := os.Rename(partPath, localPath); err != nil {
+		return fmt.Errorf("error saving download: %w", err)
+	}
+	return nil
+}
+
+// httpStatusError is an unexpected HTTP response status
+type httpStatusError struct {
+	code   int
+	status string
+}
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("HTTP error: %s", e.status)
+}
+
+// downloadPart fetches whatever partPath is still missing. The server's ETag (or
+// Last-Modified date) is kept next to the partial file and sent back with the range, so a
+// package that changed on the server since is downloaded from the start.
+func downloadPart(ctx context.Context, url, partPath string) error {
+	validatorPath := partPath + ".etag"
+
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if validator, err := os.ReadFile(validatorPath); err == nil && len(validator) > 0 {
+			req.Header.Set("If-Range", string(validator))
+		}
+		logging.LogDebug("Resuming download of %s at %s", url, FormatSize(offset))
+	}
+
+	client := &http.Client{
+		Timeout: 5 * time.Minute,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+
+	case http.StatusOK:
+		// The server sent everything, either because it can't do ranges or because the
+		// package changed
+		if offset > 0 {
+			logging.LogDebug("Server sent the whole file, restarting download of %s", url)
+		}
+		flags |= os.O_TRUNC
+
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is already complete, or bigger than the package is now; the
+		// checksum decides which when there is one
+		if offset > 0 && resp.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			return nil
+		}
+		removePartialDownload(partPath)
+		return errors.New("partial download no longer matches the package, restarting")
+
+	default:
+		return httpStatusError{code: resp.StatusCode, status: resp.Status}
+	}
+
+	// A fresh start remembers which version of the package this is
+	if resp.StatusCode == http.StatusOK {
+		validator := resp.Header.Get("ETag")
+		if validator == "" {
+			validator = resp.Header.Get("Last-Modified")
+		}
+		if err := os.WriteFile(validatorPath, []byte(validator), 0644); err != nil {
+			logging.LogDebug("Warning: Could not save download validator: %v", err)
+		}
+	}
+
+	out, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating local file: %w", err)
+	}
+
+	// Whatever arrived before a drop stays in the partial file for the next attempt
+	_, copyErr := streamCopy(out, resp.Body)
+	if err := out.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if copyErr != nil && copyErr != io.EOF {
+		return copyErr
+	}
+	return nil
+}
+
+// removePartialDownload throws away a partial download and its validator
+func removePartialDownload(partPath string) {
+	os.Remove(partPath)
+	os.Remove(partPath + ".etag")
+}
@@ -0,0 +1,96 @@
+// src/internal/themes/sanitize.go
+// FAT32-safe file names for exported packages, with the original names kept in the manifest
+
+package themes
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPackageFileNameBytes caps generated file names. FAT32 allows 255 UTF-16 units;
+// the headroom keeps zips and nested paths on other systems happy.
+const maxPackageFileNameBytes = 200
+
+// fat32InvalidChars can't appear in FAT32 or exFAT file names
+const fat32InvalidChars = `"*/:<>?\|`
+
+// reservedDOSNames can't be used as file names on Windows, whatever the extension
+var reservedDOSNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SafeFileName returns name with characters FAT32 can't store replaced by "_", trailing dots
+// and spaces removed, reserved device names avoided and the length capped. Long names keep
+// their system tag and get a short hash so they stay unique. Safe names come back unchanged.
+func SafeFileName(name string) string {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	base = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(fat32InvalidChars, r) {
+			return '_'
+		}
+		return r
+	}, base)
+
+	base = strings.TrimRight(base, ". ")
+	if base == "" {
+		base = "_"
+	}
+	if reservedDOSNames[strings.ToUpper(base)] {
+		base += "_"
+	}
+
+	if len(base)+len(ext) > maxPackageFileNameBytes {
+		// Keep the " (TAG)" or " (TAG)-list" ending, the mapping updaters read the tag from it
+		var tag string
+		if i := strings.LastIndex(base, " ("); i >= 0 && strings.Contains(base[i:], ")") && len(base)-i < maxPackageFileNameBytes/2 {
+			tag = base[i:]
+			base = base[:i]
+		}
+
+		sum := sha1.Sum([]byte(name))
+		suffix := "~" + hex.EncodeToString(sum[:4]) + tag
+
+		if keep := maxPackageFileNameBytes - len(ext) - len(suffix); len(base) > keep {
+			base = base[:keep]
+			// Don't cut a multi-byte character in half
+			for len(base) > 0 && !utf8.ValidString(base) {
+				base = base[:len(base)-1]
+			}
+		}
+		base = strings.TrimRight(base, ". ") + suffix
+	}
+
+	return base + ext
+}
+
+// safePackagePath returns the package path dir/fileName with the name made FAT32-safe, and
+// records the original name in names when sanitizing changed it
+func safePackagePath(names *map[string]string, dir, fileName string) string {
+	safeName := SafeFileName(fileName)
+	packagePath := dir + "/" + safeName
+
+	if safeName != fileName {
+		if *names == nil {
+			*names = make(map[string]string)
+		}
+		(*names)[packagePath] = fileName
+	}
+
+	return packagePath
+}
+
+// originalFileName returns the name a package file had before it was sanitized on export
+func originalFileName(names map[string]string, packagePath string) string {
+	if original, ok := names[filepath.ToSlash(packagePath)]; ok {
+		return original
+	}
+	return filepath.Base(packagePath)
+}
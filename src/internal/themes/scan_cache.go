@@ -0,0 +1,145 @@
+// src/internal/themes/scan_cache.go
+// Caches directory listings during a manifest update pass so each folder is read once
+
+package themes
+
+import (
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// scanDir is one cached directory listing
+type scanDir struct {
+	entries []os.DirEntry
+	byName  map[string]os.DirEntry
+}
+
+// scanCache holds what a manifest update pass has already read from disk
+type scanCache struct {
+	dirs    map[string]*scanDir
+	missing map[string]bool // Skip list of paths known not to exist
+
+	systemPaths    *system.SystemPaths
+	systemPathsErr error
+	systemPathsSet bool
+
+	reads int // Directories actually read
+	hits  int // Stats and reads answered from the cache
+}
+
+// scanPass is the update pass in progress, nil outside of one
+var scanPass *scanCache
+
+// beginScanPass starts caching for a manifest update and returns the function that ends it.
+// When a pass is already running (e.g. while regenerating every manifest), the outer pass
+// keeps going and the returned function does nothing.
+func beginScanPass() func() {
+	if scanPass != nil {
+		return func() {}
+	}
+
+	scanPass = &scanCache{
+		dirs:    make(map[string]*scanDir),
+		missing: make(map[string]bool),
+	}
+
+	return func() {
+		logging.LogDebug("Scan cache: %d directories read, %d lookups answered from cache",
+			scanPass.reads, scanPass.hits)
+		scanPass = nil
+	}
+}
+
+// notExist builds the error os.Stat and os.ReadDir return for a missing path
+func notExist(op, path string) error {
+	return &os.PathError{Op: op, Path: path, Err: os.ErrNotExist}
+}
+
+// readScanDir returns a directory listing, from the cache when this pass already read it
+func readScanDir(dir string) (*scanDir, error) {
+	dir = filepath.Clean(dir)
+
+	if cached, ok := scanPass.dirs[dir]; ok {
+		scanPass.hits++
+		return cached, nil
+	}
+	if scanPass.missing[dir] {
+		scanPass.hits++
+		return nil, notExist("open", dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	scanPass.reads++
+	if err != nil {
+		if os.IsNotExist(err) {
+			scanPass.missing[dir] = true
+		}
+		return nil, err
+	}
+
+	cached := &scanDir{entries: entries, byName: make(map[string]os.DirEntry, len(entries))}
+	for _, entry := range entries {
+		cached.byName[entry.Name()] = entry
+	}
+	scanPass.dirs[dir] = cached
+	return cached, nil
+}
+
+// scanReadDir works like os.ReadDir, but reads each directory only once per update pass
+func scanReadDir(dir string) ([]os.DirEntry, error) {
+	if scanPass == nil {
+		return os.ReadDir(dir)
+	}
+
+	cached, err := readScanDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return cached.entries, nil
+}
+
+// scanStat works like os.Stat, but answers from the parent directory's cached listing,
+// so checking every file in a folder costs a single directory read
+func scanStat(path string) (os.FileInfo, error) {
+	if scanPass == nil {
+		return os.Stat(path)
+	}
+
+	path = filepath.Clean(path)
+	parent, err := readScanDir(filepath.Dir(path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, notExist("stat", path)
+		}
+		return os.Stat(path)
+	}
+
+	entry, ok := parent.byName[filepath.Base(path)]
+	if !ok {
+		return nil, notExist("stat", path)
+	}
+
+	// Directory entries describe links themselves, os.Stat follows them
+	if entry.Type()&os.ModeSymlink != 0 {
+		return os.Stat(path)
+	}
+	return entry.Info()
+}
+
+// scanSystemPaths returns the device's system paths, looked up once per update pass
+func scanSystemPaths() (*system.SystemPaths, error) {
+	if scanPass == nil {
+		return system.GetSystemPaths()
+	}
+
+	if !scanPass.systemPathsSet {
+		scanPass.systemPaths, scanPass.systemPathsErr = system.GetSystemPaths()
+		scanPass.systemPathsSet = true
+	} else {
+		scanPass.hits++
+	}
+	return scanPass.systemPaths, scanPass.systemPathsErr
+}
@@ -0,0 +1,295 @@
+// src/internal/themes/seasonal.go
+// Seasonal themes: a theme applied automatically between two dates each year, then reverted
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// SeasonalSchedule applies a theme from Start to End, both included, every year
+type SeasonalSchedule struct {
+	Theme string `json:"theme"` // Theme folder name, e.g. Halloween.theme
+	Start string `json:"start"` // First day as MM-DD
+	End   string `json:"end"`   // Last day as MM-DD; earlier than Start for windows over the new year
+}
+
+// SeasonalRun records a seasonal theme applied by its schedule, in the global manifest
+type SeasonalRun struct {
+	SeasonalSchedule
+	Previous []ReapplyStep `json:"previous,omitempty"` // Setup before the seasonal theme, restored when it ends
+}
+
+// SeasonalChange is what the schedules call for today: revert the running seasonal theme,
+// apply a new one, or both when one window ends as the next starts
+type SeasonalChange struct {
+	Revert *SeasonalRun
+	Apply  *SeasonalSchedule
+}
+
+// parseSeasonalDate returns the month and day of a MM-DD date
+func parseSeasonalDate(date string) (time.Month, int, error) {
+	parsed, err := time.Parse("01-02", date)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid date '%s', expected MM-DD", date)
+	}
+	return parsed.Month(), parsed.Day(), nil
+}
+
+// seasonalDay turns a date into a number that sorts by day of the year
+func seasonalDay(month time.Month, day int) int {
+	return int(month)*100 + day
+}
+
+// Active reports whether now falls within the schedule's window
+func (s SeasonalSchedule) Active(now time.Time) bool {
+	startMonth, startDay, err := parseSeasonalDate(s.Start)
+	if err != nil {
+		return false
+	}
+	endMonth, endDay, err := parseSeasonalDate(s.End)
+	if err != nil {
+		return false
+	}
+
+	today := seasonalDay(now.Month(), now.Day())
+	start := seasonalDay(startMonth, startDay)
+	end := seasonalDay(endMonth, endDay)
+	if start <= end {
+		return today >= start && today <= end
+	}
+	// The window wraps over the new year, e.g. Dec 20 to Jan 6
+	return today >= start || today <= end
+}
+
+// FormatSeasonalDate returns a MM-DD date as e.g. "Oct 24"
+func FormatSeasonalDate(date string) string {
+	month, day, err := parseSeasonalDate(date)
+	if err != nil {
+		return date
+	}
+	return fmt.Sprintf("%s %d", month.String()[:3], day)
+}
+
+// Label returns the schedule as shown in lists, e.g. "Halloween.theme: Oct 24 - Nov 1"
+func (s SeasonalSchedule) Label() string {
+	return fmt.Sprintf("%s: %s - %s", s.Theme, FormatSeasonalDate(s.Start), FormatSeasonalDate(s.End))
+}
+
+// GetSeasonalSchedules returns the configured seasonal themes, in priority order
+func GetSeasonalSchedules() []SeasonalSchedule {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load seasonal themes: %v", err)
+		return nil
+	}
+	return config.SeasonalThemes
+}
+
+// AddSeasonalSchedule adds a seasonal theme. Where windows overlap, the one added first wins.
+func AddSeasonalSchedule(schedule SeasonalSchedule) error {
+	if _, _, err := parseSeasonalDate(schedule.Start); err != nil {
+		return err
+	}
+	if _, _, err := parseSeasonalDate(schedule.End); err != nil {
+		return err
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.SeasonalThemes = append(config.SeasonalThemes, schedule)
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	logging.LogDebug("Added seasonal theme %s", schedule.Label())
+	updateSeasonalBootHook()
+	return nil
+}
+
+// RemoveSeasonalSchedule removes the seasonal theme at index. A seasonal theme it applied
+// is reverted on the next check.
+func RemoveSeasonalSchedule(index int) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	if index < 0 || index >= len(config.SeasonalThemes) {
+		return fmt.Errorf("no seasonal theme at position %d", index+1)
+	}
+
+	removed := config.SeasonalThemes[index]
+	config.SeasonalThemes = append(config.SeasonalThemes[:index], config.SeasonalThemes[index+1:]...)
+	if err := SaveConfig(config); err != nil {
+		return err
+	}
+
+	logging.LogDebug("Removed seasonal theme %s", removed.Label())
+	updateSeasonalBootHook()
+	return nil
+}
+
+// updateSeasonalBootHook keeps the seasonal check in auto.sh while any schedule exists or a
+// seasonal theme still has to be reverted
+func updateSeasonalBootHook() {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	needed := len(GetSeasonalSchedules()) > 0
+	if global, err := LoadGlobalManifest(); err == nil && global.Seasonal != nil {
+		needed = true
+	}
+
+	setBootHook("seasonal", "--check-seasonal", needed, logger)
+}
+
+// DueSeasonalChange returns what the schedules call for at now, or nil when the device
+// already matches them. Schedules whose theme is no longer installed are skipped.
+func DueSeasonalChange(now time.Time) *SeasonalChange {
+	global, err := LoadGlobalManifest()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load global manifest for seasonal themes: %v", err)
+		return nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		logging.LogDebug("Warning: Could not check seasonal themes: %v", err)
+		return nil
+	}
+
+	var active *SeasonalSchedule
+	for _, schedule := range GetSeasonalSchedules() {
+		if !schedule.Active(now) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(cwd, "Themes", schedule.Theme)); err != nil {
+			logging.LogDebug("Warning: Seasonal theme %s is no longer installed", schedule.Theme)
+			continue
+		}
+		schedule := schedule
+		active = &schedule
+		break
+	}
+
+	run := global.Seasonal
+	if run == nil && active == nil {
+		return nil
+	}
+	if run != nil && active != nil && run.SeasonalSchedule == *active {
+		return nil
+	}
+
+	return &SeasonalChange{Revert: run, Apply: active}
+}
+
+// Message describes the change while it runs
+func (c *SeasonalChange) Message() string {
+	switch {
+	case c.Apply != nil:
+		return fmt.Sprintf("Applying seasonal theme %s...", c.Apply.Theme)
+	case c.Revert != nil:
+		return fmt.Sprintf("%s is over, restoring your theme...", c.Revert.Theme)
+	}
+	return ""
+}
+
+// RunSeasonalChange reverts and applies seasonal themes as the change calls for. The setup
+// from before a seasonal theme is only restored while that theme is still applied, so a
+// theme the user picked in the meantime is kept.
+func RunSeasonalChange(ctx context.Context, change *SeasonalChange) error {
+	// The owner set up the schedule, so it runs even in kiosk mode
+	scheduledApply = true
+	defer func() { scheduledApply = false }()
+
+	if change.Revert != nil {
+		if err := revertSeasonalTheme(ctx, change.Revert); err != nil {
+			return err
+		}
+	}
+
+	if change.Apply != nil {
+		// Record what to restore before the seasonal theme replaces it
+		previous, _, err := CurrentSetup()
+		if err != nil {
+			return err
+		}
+
+		logging.LogDebug("Applying seasonal theme %s", change.Apply.Label())
+		if err := ImportThemeContext(ctx, change.Apply.Theme); err != nil {
+			return fmt.Errorf("error applying seasonal theme %s: %w", change.Apply.Theme, err)
+		}
+
+		if err := UpdateGlobalManifest(func(global *GlobalManifest) error {
+			global.Seasonal = &SeasonalRun{
+				SeasonalSchedule: *change.Apply,
+				Previous:         previous,
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	updateSeasonalBootHook()
+	return nil
+}
+
+// revertSeasonalTheme restores the setup recorded before a seasonal theme and forgets the run
+func revertSeasonalTheme(ctx context.Context, run *SeasonalRun) error {
+	global, err := LoadGlobalManifest()
+	if err != nil {
+		return err
+	}
+
+	if global.CurrentTheme != run.Theme {
+		logging.LogDebug("Seasonal theme %s was replaced by the user, nothing to revert", run.Theme)
+	} else {
+		// Packages deleted since can't be restored, skip them like Reapply Current Setup does
+		var steps []ReapplyStep
+		for _, step := range run.Previous {
+			if _, err := os.Stat(step.Path); err != nil {
+				logging.LogDebug("Warning: %s %s is no longer installed, not restoring it", step.Type, step.Name)
+				continue
+			}
+			steps = append(steps, step)
+		}
+
+		if len(steps) == 0 {
+			logging.LogDebug("Nothing was applied before seasonal theme %s, leaving it applied", run.Theme)
+		} else {
+			logging.LogDebug("Reverting seasonal theme %s", run.Theme)
+			if err := ReapplyCurrentSetup(ctx, steps); err != nil {
+				return fmt.Errorf("error reverting seasonal theme %s: %w", run.Theme, err)
+			}
+		}
+
+	}
+
+	// Update the manifest as the reapply left it, with the restored packages recorded
+	return UpdateGlobalManifest(func(global *GlobalManifest) error {
+		global.Seasonal = nil
+		return nil
+	})
+}
+
+// CheckSeasonalThemes applies or reverts seasonal themes for today without any UI. Run from
+// NextUI's auto.sh with --check-seasonal.
+func CheckSeasonalThemes() error {
+	change := DueSeasonalChange(time.Now())
+	if change == nil {
+		return nil
+	}
+	return RunSeasonalChange(context.Background(), change)
+}
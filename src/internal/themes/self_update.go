@@ -0,0 +1,237 @@
+// src/internal/themes/self_update.go
+// Updates the Theme Manager pak itself from its GitHub releases. The new build is staged
+// next to the running one and launch.sh swaps it in the next time the pak starts.
+
+package themes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// StagedUpdateDir is the folder, inside the pak, holding an update launch.sh installs on
+// the next launch
+const StagedUpdateDir = ".update"
+
+// checksumSuffix names the asset published next to a release's zip with its SHA-256, as
+// written by sha256sum
+const checksumSuffix = ".sha256"
+
+// PakInfo is the part of the pak's pak.json the updater needs
+type PakInfo struct {
+	Version         string `json:"version"`
+	RepoURL         string `json:"repo_url"`
+	ReleaseFilename string `json:"release_filename"`
+}
+
+// LoadPakInfo reads pak.json from the pak's folder
+func LoadPakInfo() (*PakInfo, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cwd, "pak.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading pak.json: %w", err)
+	}
+
+	var info PakInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("error parsing pak.json: %w", err)
+	}
+	return &info, nil
+}
+
+// PakRelease is a release of the Theme Manager pak
+type PakRelease struct {
+	Version     string
+	DownloadURL string
+	Size        int64
+	Checksum    string // SHA-256 of the zip, published with the release
+}
+
+// CheckForPakUpdate asks GitHub for the latest release of the pak and returns it when it
+// is newer than the running version, nil when the pak is up to date
+func CheckForPakUpdate() (*PakRelease, error) {
+	pak, err := LoadPakInfo()
+	if err != nil {
+		return nil, err
+	}
+	if pak.RepoURL == "" || pak.ReleaseFilename == "" {
+		return nil, fmt.Errorf("pak.json doesn't say where releases are published")
+	}
+
+	repo := strings.TrimSuffix(strings.TrimPrefix(pak.RepoURL, "https://github.com/"), ".git")
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
+	logging.LogDebug("Checking for a Theme Manager update at %s", apiURL)
+
+	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error checking for updates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error checking for updates: HTTP error: %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name   string `json:"name"`
+			URL    string `json:"browser_download_url"`
+			Size   int64  `json:"size"`
+			Digest string `json:"digest"`
+		} `json:"assets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("error parsing release: %w", err)
+	}
+
+	latest := strings.TrimPrefix(release.TagName, "v")
+	logging.LogDebug("Latest Theme Manager release: %s, running: %s", latest, pak.Version)
+	if latest == "" || CompareVersions(latest, pak.Version) <= 0 {
+		return nil, nil
+	}
+
+	var found *PakRelease
+	checksumURL := ""
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case pak.ReleaseFilename:
+			found = &PakRelease{Version: latest, DownloadURL: asset.URL, Size: asset.Size}
+			// GitHub publishes a digest for assets uploaded since 2025
+			if digest, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+				found.Checksum = digest
+			}
+		case pak.ReleaseFilename + checksumSuffix:
+			checksumURL = asset.URL
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("release %s has no %s to download", release.TagName, pak.ReleaseFilename)
+	}
+
+	// The checksum file the release was built with wins over GitHub's digest
+	if checksumURL != "" {
+		checksum, err := fetchReleaseChecksum(client, checksumURL)
+		if err != nil {
+			return nil, err
+		}
+		found.Checksum = checksum
+	}
+	return found, nil
+}
+
+// fetchReleaseChecksum downloads a sha256sum line and returns the checksum in it
+func fetchReleaseChecksum(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("error downloading checksum: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error downloading checksum: HTTP error: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("error downloading checksum: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("release checksum file is empty")
+	}
+	if decoded, err := hex.DecodeString(fields[0]); err != nil || len(decoded) != sha256.Size {
+		return "", fmt.Errorf("release checksum is not a SHA-256: %s", fields[0])
+	}
+	return fields[0], nil
+}
+
+// StagePakUpdate downloads a release and unpacks it into StagedUpdateDir. The folder only
+// appears once the whole build is unpacked and checked, so launch.sh never installs half
+// an update. Releases without a checksum, or whose zip doesn't match it, are refused:
+// the update replaces the executable the device runs.
+func StagePakUpdate(release *PakRelease) (err error) {
+	defer func() { recordOperation("Staged Theme Manager update", release.Version, err) }()
+
+	if release.Checksum == "" {
+		return fmt.Errorf("release %s has no published checksum, not installing it", release.Version)
+	}
+
+	if err := CheckKioskMode("updating Theme Manager"); err != nil {
+		return err
+	}
+	if err := CheckBatteryForOperation("updating Theme Manager"); err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	cacheDir := filepath.Join(cwd, ".cache")
+	zipPath := filepath.Join(cacheDir, fmt.Sprintf("theme-manager-%s.pak.zip", release.Version))
+	if err := downloadPackage(release.DownloadURL, zipPath, release.Checksum); err != nil {
+		return fmt.Errorf("error downloading update: %w", err)
+	}
+	defer os.Remove(zipPath)
+
+	unpackPath := filepath.Join(cacheDir, "update")
+	os.RemoveAll(unpackPath)
+	if err := extractZipFile(zipPath, unpackPath); err != nil {
+		os.RemoveAll(unpackPath)
+		return fmt.Errorf("error unpacking update: %w", err)
+	}
+
+	for _, required := range []string{"theme-manager", "launch.sh", "pak.json"} {
+		if _, err := os.Stat(filepath.Join(unpackPath, required)); err != nil {
+			os.RemoveAll(unpackPath)
+			return fmt.Errorf("update is missing %s", required)
+		}
+	}
+
+	stagedPath := filepath.Join(cwd, StagedUpdateDir)
+	os.RemoveAll(stagedPath)
+	if err := movePackage(unpackPath, stagedPath); err != nil {
+		return fmt.Errorf("error staging update: %w", err)
+	}
+
+	logging.LogDebug("Staged Theme Manager %s in %s", release.Version, stagedPath)
+	return nil
+}
+
+// HasStagedPakUpdate reports whether an update is waiting for the next launch
+func HasStagedPakUpdate() bool {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(cwd, StagedUpdateDir, "theme-manager"))
+	return err == nil
+}
@@ -0,0 +1,141 @@
+// src/internal/themes/settings_snapshot.go
+// Optional snapshot of other NextUI settings files, exported and restored with a theme
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// sharedSettingsDir holds the settings files NextUI shares between its apps
+const sharedSettingsDir = "/mnt/SDCARD/.userdata/shared"
+
+// snapshotManagedFiles are settings files themes already carry as accents and LEDs
+var snapshotManagedFiles = map[string]bool{
+	"minuisettings.txt":     true,
+	"ledsettings_brick.txt": true,
+}
+
+// GetSettingsAllowlist returns the shared settings files included in theme exports
+func GetSettingsAllowlist() []string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load settings allowlist: %v", err)
+		return nil
+	}
+	return config.SettingsAllowlist
+}
+
+// isSettingsFileAllowed reports whether a shared settings file is on the allowlist
+func isSettingsFileAllowed(name string) bool {
+	for _, allowed := range GetSettingsAllowlist() {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleSettingsAllowlist adds a shared settings file to the allowlist, or removes it if already there
+func ToggleSettingsAllowlist(name string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	var updated []string
+	found := false
+	for _, allowed := range config.SettingsAllowlist {
+		if allowed == name {
+			found = true
+			continue
+		}
+		updated = append(updated, allowed)
+	}
+
+	if !found {
+		updated = append(updated, name)
+		logging.LogDebug("Added %s to the settings allowlist", name)
+	} else {
+		logging.LogDebug("Removed %s from the settings allowlist", name)
+	}
+
+	config.SettingsAllowlist = updated
+	return SaveConfig(config)
+}
+
+// ListSharedSettingsFiles returns the settings files that can be added to the allowlist
+func ListSharedSettingsFiles() ([]string, error) {
+	entries, err := os.ReadDir(sharedSettingsDir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading shared settings: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || snapshotManagedFiles[name] {
+			continue
+		}
+		files = append(files, name)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// exportSettingsSnapshot copies the allowlisted shared settings files into the theme's
+// Settings folder and maps them back to where they came from
+func exportSettingsSnapshot(themePath string, manifest *ThemeManifest, logger *Logger) {
+	allowlist := GetSettingsAllowlist()
+	if len(allowlist) == 0 {
+		return
+	}
+
+	if manifest.PathMappings.Settings == nil {
+		manifest.PathMappings.Settings = make(map[string]PathMapping)
+	}
+
+	manifest.Content.Settings.SnapshotFiles = nil
+	for _, name := range allowlist {
+		// Only plain file names, the allowlist can be edited by hand
+		if name != filepath.Base(name) || snapshotManagedFiles[name] {
+			logger.DebugFn("Warning: Skipping settings allowlist entry %s", name)
+			continue
+		}
+
+		srcPath := filepath.Join(sharedSettingsDir, name)
+		if _, err := os.Stat(srcPath); err != nil {
+			logger.DebugFn("Settings file %s not found, skipping", name)
+			continue
+		}
+
+		themeRelPath := filepath.Join("Settings", name)
+		if err := CopyFile(srcPath, filepath.Join(themePath, themeRelPath)); err != nil {
+			logger.DebugFn("Warning: Could not export settings file %s: %v", name, err)
+			continue
+		}
+
+		manifest.PathMappings.Settings[name] = PathMapping{
+			ThemePath:  themeRelPath,
+			SystemPath: srcPath,
+		}
+		manifest.Content.Settings.SnapshotFiles = append(manifest.Content.Settings.SnapshotFiles, name)
+		logger.DebugFn("Exported settings file %s", name)
+	}
+}
+
+// isSnapshotRestoreAllowed reports whether a theme may write a settings mapping. Files in the
+// shared settings folder are only restored when they are on the user's own allowlist.
+func isSnapshotRestoreAllowed(mapping PathMapping) bool {
+	if filepath.Dir(mapping.SystemPath) != sharedSettingsDir {
+		return true
+	}
+	return isSettingsFileAllowed(filepath.Base(mapping.SystemPath))
+}
@@ -0,0 +1,233 @@
+// src/internal/themes/setup.go
+// Setups: themes that also carry the device's collections layout
+
+package themes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// setupLayoutFile is the file in a theme package that makes it a setup
+const setupLayoutFile = "setup.json"
+
+// setupTagPattern extracts the system tag from a ROM folder name
+var setupTagPattern = regexp.MustCompile(`\((.*?)\)`)
+
+// CollectionLayout is one collection in a setup: its name, which also sets where NextUI
+// lists it, and the ROMs in it by path. The ROMs themselves are never included.
+type CollectionLayout struct {
+	Name    string   `json:"name"`
+	Entries []string `json:"entries"` // ROM paths as written in the collection list, e.g. /Roms/Game Boy (GB)/Tetris.gb
+}
+
+// SetupLayout is the setup.json of a setup
+type SetupLayout struct {
+	Collections []CollectionLayout `json:"collections"`
+}
+
+// SetupApplyResult describes what recreating a setup's collections did
+type SetupApplyResult struct {
+	Collections int      // Collections written
+	Entries     int      // ROM entries written
+	Missing     []string // Entries left out because the ROM isn't on this device
+}
+
+// readDeviceCollectionLayout captures the collections on the device, in the order NextUI lists them
+func readDeviceCollectionLayout() (*SetupLayout, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	collections, err := ListDeviceCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &SetupLayout{Collections: []CollectionLayout{}}
+	for _, name := range collections {
+		data, err := os.ReadFile(filepath.Join(systemPaths.Root, "Collections", name+".txt"))
+		if err != nil {
+			// A media folder without a list isn't a collection NextUI shows
+			logging.LogDebug("Skipping collection folder without a list: %s", name)
+			continue
+		}
+
+		collection := CollectionLayout{Name: name, Entries: []string{}}
+		for _, line := range strings.Split(string(data), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				collection.Entries = append(collection.Entries, line)
+			}
+		}
+		layout.Collections = append(layout.Collections, collection)
+	}
+	return layout, nil
+}
+
+// ExportSetup exports the current theme and adds the device's collections layout to it
+func ExportSetup() error {
+	themePath, err := ExportThemeContext(context.Background())
+	if err != nil {
+		return err
+	}
+	lastExportPath = themePath
+
+	layout, err := readDeviceCollectionLayout()
+	if err != nil {
+		return fmt.Errorf("error reading collections: %w", err)
+	}
+
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding collections layout: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(themePath, setupLayoutFile), data, 0644); err != nil {
+		return fmt.Errorf("error writing collections layout: %w", err)
+	}
+
+	logging.LogDebug("Exported setup %s with %d collections", filepath.Base(themePath), len(layout.Collections))
+	return nil
+}
+
+// LoadSetupLayout reads the collections layout of a theme, or returns nil when it isn't a setup
+func LoadSetupLayout(themePath string) (*SetupLayout, error) {
+	data, err := os.ReadFile(filepath.Join(themePath, setupLayoutFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", setupLayoutFile, err)
+	}
+
+	var layout SetupLayout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", setupLayoutFile, err)
+	}
+	return &layout, nil
+}
+
+// IsSetup reports whether an installed theme carries a collections layout
+func IsSetup(themePath string) bool {
+	_, err := os.Stat(filepath.Join(themePath, setupLayoutFile))
+	return err == nil
+}
+
+// resolveSetupEntry finds a collection entry on this device. ROMs in a system folder with a
+// different name but the same tag are matched too, and the entry rewritten to point at them.
+func resolveSetupEntry(entry string, systemPaths *system.SystemPaths) (string, bool) {
+	if _, err := os.Stat(filepath.Join(systemPaths.Root, entry)); err == nil {
+		return entry, true
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(filepath.ToSlash(entry), "/"), "/", 3)
+	if len(parts) < 3 || parts[0] != "Roms" {
+		return "", false
+	}
+
+	matches := setupTagPattern.FindStringSubmatch(parts[1])
+	if len(matches) < 2 {
+		return "", false
+	}
+	sys, ok := systemPaths.SystemForTag(matches[1])
+	if !ok || sys.Root != systemPaths.Root {
+		return "", false
+	}
+
+	resolved := "/" + filepath.ToSlash(filepath.Join("Roms", sys.Name, parts[2]))
+	if _, err := os.Stat(filepath.Join(systemPaths.Root, resolved)); err != nil {
+		return "", false
+	}
+	return resolved, true
+}
+
+// ApplySetupCollections recreates the collections of an installed setup. Each collection's
+// list is written with the entries whose ROMs are on this device; a collection with the same
+// name is replaced and its old list moved to the trash. Other collections are left alone.
+func ApplySetupCollections(themeName string) (result *SetupApplyResult, err error) {
+	defer func() { recordOperation("Applied setup collections", themeName, err) }()
+
+	if err := CheckKioskMode("recreating collections"); err != nil {
+		return nil, err
+	}
+	if err := CheckStorageWritable(); err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	layout, err := LoadSetupLayout(filepath.Join(cwd, "Themes", themeName))
+	if err != nil {
+		return nil, err
+	}
+	if layout == nil {
+		return nil, fmt.Errorf("%s is not a setup", themeName)
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	collectionsDir := filepath.Join(systemPaths.Root, "Collections")
+	if err := os.MkdirAll(collectionsDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating collections directory: %w", err)
+	}
+
+	beginTrashBatch()
+	result = &SetupApplyResult{}
+	for _, collection := range layout.Collections {
+		if strings.TrimSpace(collection.Name) == "" {
+			continue
+		}
+		name := strings.TrimSuffix(SafeFileName(collection.Name+".txt"), ".txt")
+
+		var lines []string
+		for _, entry := range collection.Entries {
+			resolved, ok := resolveSetupEntry(entry, systemPaths)
+			if !ok {
+				result.Missing = append(result.Missing, entry)
+				continue
+			}
+			lines = append(lines, resolved)
+		}
+
+		listPath := filepath.Join(collectionsDir, name+".txt")
+		if _, err := os.Stat(listPath); err == nil {
+			if err := moveToTrash(listPath); err != nil {
+				return result, fmt.Errorf("error replacing collection %s: %w", name, err)
+			}
+		}
+
+		content := strings.Join(lines, "\n")
+		if len(lines) > 0 {
+			content += "\n"
+		}
+		if err := os.WriteFile(listPath, []byte(content), 0644); err != nil {
+			return result, fmt.Errorf("error writing collection %s: %w", name, err)
+		}
+		if err := os.MkdirAll(filepath.Join(collectionsDir, name, ".media"), 0755); err != nil {
+			logging.LogDebug("Warning: Could not create media folder for collection %s: %v", name, err)
+		}
+
+		result.Collections++
+		result.Entries += len(lines)
+	}
+
+	logging.LogDebug("Recreated %d collections from %s (%d entries, %d missing)",
+		result.Collections, themeName, result.Entries, len(result.Missing))
+	for _, entry := range result.Missing {
+		logging.LogDebug("Missing ROM for setup collection: %s", entry)
+	}
+	return result, nil
+}
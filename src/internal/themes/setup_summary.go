@@ -0,0 +1,263 @@
+// src/internal/themes/setup_summary.go
+// Exports a summary of the applied setup as a text file and an image to keep or share
+
+package themes
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// summaryWidth and summaryHeight are the size of the summary image, the Brick's screen
+const (
+	summaryWidth  = 1024
+	summaryHeight = 768
+)
+
+// SetupSummaryPackage is one applied theme or component in a setup summary
+type SetupSummaryPackage struct {
+	Type    string // "Theme" or a component folder such as "Icons"
+	Name    string
+	Author  string
+	Version string
+}
+
+// SetupSummary describes the setup currently applied to the device
+type SetupSummary struct {
+	Theme    string
+	Packages []SetupSummaryPackage
+	Palette  []string // Accent colors color1 to color6 as set on the device, "" when unset
+}
+
+// Credits returns the distinct authors of the applied packages, in the order they appear
+func (s *SetupSummary) Credits() []string {
+	seen := make(map[string]bool)
+	var authors []string
+	for _, pkg := range s.Packages {
+		if pkg.Author == "" || seen[strings.ToLower(pkg.Author)] {
+			continue
+		}
+		seen[strings.ToLower(pkg.Author)] = true
+		authors = append(authors, pkg.Author)
+	}
+	return authors
+}
+
+// Lines renders the summary as text, one line per entry
+func (s *SetupSummary) Lines() []string {
+	theme := s.Theme
+	if theme == "" {
+		theme = "none, components only"
+	}
+
+	lines := []string{"NextUI setup: " + theme, ""}
+	for _, pkg := range s.Packages {
+		line := fmt.Sprintf("%s: %s", pkg.Type, pkg.Name)
+		if pkg.Version != "" {
+			line += " v" + pkg.Version
+		}
+		if pkg.Author != "" {
+			line += " by " + pkg.Author
+		}
+		lines = append(lines, line)
+	}
+
+	var palette []string
+	for i, c := range s.Palette {
+		if c != "" {
+			palette = append(palette, fmt.Sprintf("color%d %s", i+1, c))
+		}
+	}
+	if len(palette) > 0 {
+		lines = append(lines, "", "Palette: "+strings.Join(palette, ", "))
+	}
+
+	if credits := s.Credits(); len(credits) > 0 {
+		lines = append(lines, "", "Credits: "+strings.Join(credits, ", "))
+	}
+	return lines
+}
+
+// summaryPackage reads the name, author and version of an installed package
+func summaryPackage(typ, packagePath string) SetupSummaryPackage {
+	pkg := SetupSummaryPackage{Type: typ, Name: strings.TrimSuffix(filepath.Base(packagePath), filepath.Ext(packagePath))}
+
+	if typ == "Theme" {
+		manifest, err := ValidateTheme(packagePath, &Logger{DebugFn: logging.LogDebug})
+		if err != nil {
+			logging.LogDebug("Warning: Could not read theme manifest for summary: %v", err)
+			return pkg
+		}
+		pkg.Author = manifest.ThemeInfo.Author
+		pkg.Version = manifest.ThemeInfo.Version
+		return pkg
+	}
+
+	manifest, err := LoadComponentManifest(packagePath)
+	if err != nil {
+		logging.LogDebug("Warning: Could not read component manifest for summary: %v", err)
+		return pkg
+	}
+	if info := GetComponentInfo(manifest); info != nil {
+		pkg.Author = info.Author
+		pkg.Version = info.Version
+	}
+	return pkg
+}
+
+// GetSetupSummary collects the applied theme, the components applied over it and the
+// device's accent colors
+func GetSetupSummary() (*SetupSummary, error) {
+	manifest, err := LoadGlobalManifest()
+	if err != nil {
+		return nil, fmt.Errorf("error loading global manifest: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	summary := &SetupSummary{Theme: strings.TrimSuffix(manifest.CurrentTheme, ".theme")}
+	if manifest.CurrentTheme != "" {
+		summary.Packages = append(summary.Packages, summaryPackage("Theme", filepath.Join(cwd, "Themes", manifest.CurrentTheme)))
+	}
+
+	for _, kind := range ComponentKinds() {
+		name, err := GetAppliedComponent(kind.Type())
+		if err != nil || name == "" {
+			continue
+		}
+		summary.Packages = append(summary.Packages, summaryPackage(kind.Directory(), filepath.Join(cwd, "Components", kind.Directory(), name)))
+	}
+
+	for i := 1; i <= 6; i++ {
+		summary.Palette = append(summary.Palette, currentAccentColor(fmt.Sprintf("color%d", i)))
+	}
+	return summary, nil
+}
+
+// parseSummaryColor parses an accent color as set on the device
+func parseSummaryColor(value string) (color.NRGBA, bool) {
+	c, err := ParseColor(value)
+	return c, err == nil
+}
+
+// drawSummaryText writes a line of text with the watermark font, cut off at the image edge
+func drawSummaryText(img draw.Image, text string, x, y, scale int, ink image.Image) {
+	advance := 6 * scale
+	for _, r := range strings.ToUpper(text) {
+		if x+5*scale > img.Bounds().Max.X {
+			return
+		}
+		if glyph, ok := watermarkGlyphs[r]; ok {
+			for row, bits := range glyph {
+				for col := 0; col < 5; col++ {
+					if bits&(1<<uint(4-col)) == 0 {
+						continue
+					}
+					px := x + col*scale
+					py := y + row*scale
+					draw.Draw(img, image.Rect(px, py, px+scale, py+scale), ink, image.Point{}, draw.Src)
+				}
+			}
+		}
+		x += advance
+	}
+}
+
+// renderSetupSummary draws the summary as an image: the text on a dark background with a
+// swatch for each accent color
+func renderSetupSummary(summary *SetupSummary) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, summaryWidth, summaryHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.NRGBA{24, 24, 28, 255}), image.Point{}, draw.Src)
+
+	const margin = 32
+	ink := image.NewUniform(color.NRGBA{240, 240, 240, 255})
+	dim := image.NewUniform(color.NRGBA{150, 150, 160, 255})
+
+	drawSummaryText(img, summary.Lines()[0], margin, margin, 4, ink)
+
+	y := margin + 56
+	for _, pkg := range summary.Packages {
+		drawSummaryText(img, pkg.Type, margin, y, 3, dim)
+		line := pkg.Name
+		if pkg.Version != "" {
+			line += " v" + pkg.Version
+		}
+		if pkg.Author != "" {
+			line += " by " + pkg.Author
+		}
+		drawSummaryText(img, line, margin+220, y, 3, ink)
+		y += 36
+	}
+
+	// One swatch per accent color that is set, labeled with its value
+	swatchY := summaryHeight - margin - 170
+	x := margin
+	for i, value := range summary.Palette {
+		c, ok := parseSummaryColor(value)
+		if !ok {
+			continue
+		}
+		draw.Draw(img, image.Rect(x-2, swatchY-2, x+142, swatchY+82), ink, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(x, swatchY, x+140, swatchY+80), image.NewUniform(c), image.Point{}, draw.Src)
+		drawSummaryText(img, fmt.Sprintf("color%d", i+1), x, swatchY+92, 2, dim)
+		drawSummaryText(img, value, x, swatchY+112, 2, ink)
+		x += 160
+	}
+
+	if credits := summary.Credits(); len(credits) > 0 {
+		drawSummaryText(img, "Credits: "+strings.Join(credits, ", "), margin, summaryHeight-margin-14, 2, dim)
+	}
+	return img
+}
+
+// ExportSetupSummary writes a summary of the applied setup to the export folder as
+// Setup_<date>.txt and Setup_<date>.png, returning the path of the image
+func ExportSetupSummary() (string, error) {
+	summary, err := GetSetupSummary()
+	if err != nil {
+		return "", err
+	}
+	if len(summary.Packages) == 0 {
+		return "", fmt.Errorf("no theme or component has been applied yet")
+	}
+
+	exportsDir, err := GetExportsDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(exportsDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating export folder: %w", err)
+	}
+
+	base := filepath.Join(exportsDir, "Setup_"+time.Now().Format("20060102_150405"))
+
+	text := strings.Join(summary.Lines(), "\n") + "\n"
+	if err := os.WriteFile(base+".txt", []byte(text), 0644); err != nil {
+		return "", fmt.Errorf("error writing setup summary: %w", err)
+	}
+
+	file, err := os.Create(base + ".png")
+	if err != nil {
+		return "", fmt.Errorf("error creating setup summary image: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, renderSetupSummary(summary)); err != nil {
+		return "", fmt.Errorf("error writing setup summary image: %w", err)
+	}
+
+	logging.LogDebug("Exported setup summary to %s", base)
+	return base + ".png", nil
+}
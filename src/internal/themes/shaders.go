@@ -0,0 +1,475 @@
+// src/internal/themes/shaders.go
+// Shader/filter components: per-system video settings written to minarch's per-core config
+
+package themes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+	"nextui-themes/internal/ui"
+)
+
+// minarchUserdataPath returns the platform's userdata folder, which holds one folder per core,
+// named "<TAG>-<core>", each with its minarch.cfg
+func minarchUserdataPath() string {
+	return filepath.Join(storageRoot, ".userdata", system.DetectPlatform(storageRoot))
+}
+
+// minarchConfigName is the per-core settings file minarch reads when a game starts
+const minarchConfigName = "minarch.cfg"
+
+// shaderOptionPrefixes are the minarch options a shader component may set. Anything else
+// in minarch.cfg (controls, save states, core options) is never touched.
+var shaderOptionPrefixes = []string{
+	"minarch_screen_",
+	"minarch_shader",
+	"minarch_nrofshaders",
+}
+
+// ShaderManifest for .shd component packages
+type ShaderManifest struct {
+	ComponentInfo ComponentInfo                `json:"component_info"`
+	Systems       map[string]map[string]string `json:"systems"` // System tag -> minarch option -> value
+}
+
+// isShaderOption reports whether a minarch option is a video filter or shader setting
+func isShaderOption(key string) bool {
+	for _, prefix := range shaderOptionPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// minarchCoreDirs returns the per-core settings folders of a system. They only exist
+// once a game of the system has been launched, so a system may have none yet.
+func minarchCoreDirs(systemTag string) []string {
+	matches, err := filepath.Glob(filepath.Join(minarchUserdataPath(), systemTag+"-*"))
+	if err != nil {
+		return nil
+	}
+
+	var dirs []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			dirs = append(dirs, match)
+		}
+	}
+	return dirs
+}
+
+// readMinarchOptions parses the "key = value" lines of a minarch.cfg
+func readMinarchOptions(configPath string) (map[string]string, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make(map[string]string)
+	for _, line := range strings.Split(string(content), "\n") {
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		options[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return options, nil
+}
+
+// writeMinarchOptions sets options in a minarch.cfg, keeping every other line as it was
+func writeMinarchOptions(configPath string, options map[string]string) error {
+	original, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error reading %s: %w", configPath, err)
+	}
+
+	var lines []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(original), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if value, ok := options[key]; ok && len(parts) == 2 {
+			line = fmt.Sprintf("%s = %s", key, value)
+			seen[key] = true
+		}
+		lines = append(lines, line)
+	}
+
+	// Options the file didn't have yet go at the end, in a stable order
+	var missing []string
+	for key := range options {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		lines = append(lines, fmt.Sprintf("%s = %s", key, options[key]))
+	}
+
+	return os.WriteFile(configPath, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// getShaderBackupDir returns where the original minarch.cfg files are kept
+func getShaderBackupDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, ".shader_backup"), nil
+}
+
+// backupMinarchConfig saves a core's minarch.cfg before the first shader apply touches it.
+// Later applies leave the backup alone, so restoring always returns to the user's own
+// settings. A core without a config gets a ".missing" marker instead.
+func backupMinarchConfig(coreDir string) error {
+	backupDir, err := getShaderBackupDir()
+	if err != nil {
+		return err
+	}
+
+	coreName := filepath.Base(coreDir)
+	backupPath := filepath.Join(backupDir, coreName+".cfg")
+	markerPath := filepath.Join(backupDir, coreName+".missing")
+
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(markerPath); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return fmt.Errorf("error creating shader backup directory: %w", err)
+	}
+
+	configPath := filepath.Join(coreDir, minarchConfigName)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return os.WriteFile(markerPath, nil, 0644)
+	}
+
+	return CopyFile(configPath, backupPath)
+}
+
+// HasShaderBackups reports whether any core settings can be restored
+func HasShaderBackups() bool {
+	backupDir, err := getShaderBackupDir()
+	if err != nil {
+		return false
+	}
+	entries, err := os.ReadDir(backupDir)
+	return err == nil && len(entries) > 0
+}
+
+// RestoreShaderBackups puts back the minarch.cfg files saved before the first shader apply
+// and returns how many cores were restored
+func RestoreShaderBackups() (int, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckKioskMode("restoring video settings"); err != nil {
+		return 0, err
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return 0, err
+	}
+
+	backupDir, err := getShaderBackupDir()
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := os.ReadDir(backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("error reading shader backups: %w", err)
+	}
+
+	userdataPath := minarchUserdataPath()
+	restored := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		coreName := strings.TrimSuffix(strings.TrimSuffix(name, ".cfg"), ".missing")
+		configPath := filepath.Join(userdataPath, coreName, minarchConfigName)
+
+		if strings.HasSuffix(name, ".missing") {
+			// The core had no settings of its own before
+			if err := os.Remove(configPath); err != nil && !os.IsNotExist(err) {
+				logger.DebugFn("Warning: Could not remove %s: %v", configPath, err)
+				continue
+			}
+		} else if err := CopyFile(filepath.Join(backupDir, name), configPath); err != nil {
+			logger.DebugFn("Warning: Could not restore %s: %v", configPath, err)
+			continue
+		}
+
+		os.Remove(filepath.Join(backupDir, name))
+		restored++
+	}
+
+	// Keep the folder if anything failed to restore, so it can be retried
+	os.Remove(backupDir)
+
+	if err := UpdateAppliedComponent(ComponentShader, ""); err != nil {
+		logger.DebugFn("Warning: Failed to update global manifest: %v", err)
+	}
+
+	logger.DebugFn("Restored %d core settings from shader backups", restored)
+	return restored, nil
+}
+
+// DiscardShaderBackups deletes the minarch.cfg files saved before the first shader apply, so
+// the settings shaders changed can no longer be restored
+func DiscardShaderBackups() (err error) {
+	defer func() { recordOperation("Discarded shader backups", "", err) }()
+
+	if err := CheckKioskMode("discarding video settings backups"); err != nil {
+		return err
+	}
+
+	backupDir, err := getShaderBackupDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(backupDir); err != nil {
+		return fmt.Errorf("error discarding shader backups: %w", err)
+	}
+
+	logging.LogDebug("Discarded shader backups")
+	return nil
+}
+
+// ImportShaders applies a shader component's settings to every core of each system it covers,
+// stopping between systems once ctx is done
+func ImportShaders(ctx context.Context, componentPath string) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting shader import: %s", componentPath)
+
+	// Load the component manifest
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		return fmt.Errorf("error loading shader manifest: %w", err)
+	}
+
+	// Ensure it's the right type
+	manifest, ok := manifestObj.(*ShaderManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for shader component")
+	}
+
+	excluded := loadExcludedSystems()
+
+	var tags []string
+	for tag := range manifest.Systems {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	appliedCores := 0
+	var unsupported []string
+
+	for _, tag := range tags {
+		if err := applyCancelled(ctx); err != nil {
+			return err
+		}
+
+		if isTagExcluded(tag, excluded) {
+			logger.DebugFn("Skipping excluded system: %s", tag)
+			continue
+		}
+
+		options := make(map[string]string)
+		for key, value := range manifest.Systems[tag] {
+			if isShaderOption(key) {
+				options[key] = value
+			} else {
+				logger.DebugFn("Warning: Ignoring non-video option %s for %s", key, tag)
+			}
+		}
+		if len(options) == 0 {
+			continue
+		}
+
+		coreDirs := minarchCoreDirs(tag)
+		if len(coreDirs) == 0 {
+			// minarch creates the folder on first launch; nothing to theme until then
+			logger.DebugFn("No minarch settings for %s yet, skipping", tag)
+			unsupported = append(unsupported, tag)
+			continue
+		}
+
+		for _, coreDir := range coreDirs {
+			if err := backupMinarchConfig(coreDir); err != nil {
+				// Never change settings we couldn't back up
+				logger.DebugFn("Warning: Could not back up %s, skipping: %v", coreDir, err)
+				continue
+			}
+
+			if err := writeMinarchOptions(filepath.Join(coreDir, minarchConfigName), options); err != nil {
+				logger.DebugFn("Warning: Could not write shader settings for %s: %v", coreDir, err)
+				continue
+			}
+
+			logger.DebugFn("Applied %d video settings to %s", len(options), filepath.Base(coreDir))
+			appliedCores++
+		}
+	}
+
+	// Update global manifest to track this component
+	componentName := filepath.Base(componentPath)
+	if err := UpdateAppliedComponent(ComponentShader, componentName); err != nil {
+		logger.DebugFn("Warning: Failed to update global manifest: %v", err)
+	}
+
+	logger.DebugFn("Shader import completed: %s", componentPath)
+
+	message := fmt.Sprintf("Video settings from '%s' applied to %d cores!", manifest.ComponentInfo.Name, appliedCores)
+	if len(unsupported) > 0 {
+		message += fmt.Sprintf(" Launch a game once to enable: %s", strings.Join(unsupported, ", "))
+	}
+	ui.ShowMessage(message, "3")
+
+	return nil
+}
+
+// ExportShaders exports the video settings of every core as a shader component
+func ExportShaders(name string) (err error) {
+	defer func() { recordOperation("Exported shaders", name, err) }()
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	logger.DebugFn("Starting shader export: %s", name)
+
+	// Create export directory path with .shd extension
+	if !strings.HasSuffix(name, ComponentExtension[ComponentShader]) {
+		name = name + ComponentExtension[ComponentShader]
+	}
+
+	exportPath, err := exportPackagePath(name)
+	if err != nil {
+		return err
+	}
+
+	manifestObj, err := CreateMinimalComponentManifest(ComponentShader, name, "")
+	if err != nil {
+		return fmt.Errorf("error creating shader manifest: %w", err)
+	}
+
+	shaderManifest := manifestObj.(*ShaderManifest)
+	shaderManifest.ComponentInfo.License = getAppliedComponentLicense(ComponentShader)
+
+	userdataPath := minarchUserdataPath()
+	entries, err := os.ReadDir(userdataPath)
+	if err != nil {
+		return fmt.Errorf("error reading minarch settings: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.Contains(entry.Name(), "-") {
+			continue
+		}
+
+		options, err := readMinarchOptions(filepath.Join(userdataPath, entry.Name(), minarchConfigName))
+		if err != nil {
+			continue
+		}
+
+		// Folders are named "<TAG>-<core>"; the first core of a system wins
+		tag := strings.SplitN(entry.Name(), "-", 2)[0]
+		if _, exists := shaderManifest.Systems[tag]; exists {
+			continue
+		}
+
+		video := make(map[string]string)
+		for key, value := range options {
+			if isShaderOption(key) {
+				video[key] = value
+			}
+		}
+		if len(video) > 0 {
+			shaderManifest.Systems[tag] = video
+			logger.DebugFn("Exported %d video settings for %s", len(video), tag)
+		}
+	}
+
+	if len(shaderManifest.Systems) == 0 {
+		return fmt.Errorf("no saved video settings found in %s", userdataPath)
+	}
+
+	// Create export directory
+	if err := os.MkdirAll(exportPath, 0755); err != nil {
+		return fmt.Errorf("error creating directory %s: %w", exportPath, err)
+	}
+
+	if err := WriteComponentManifest(exportPath, shaderManifest); err != nil {
+		return fmt.Errorf("error writing shader manifest: %w", err)
+	}
+
+	logger.DebugFn("Shader export completed: %s", exportPath)
+	ui.ShowMessage(fmt.Sprintf("Video settings for %d systems exported to %s", len(shaderManifest.Systems), name), "3")
+
+	return nil
+}
+
+// UpdateShaderManifest validates a shader component's manifest
+func UpdateShaderManifest(componentPath string, logger *Logger) error {
+	logger.DebugFn("Updating shader manifest for: %s", componentPath)
+
+	// Get the component name from the path
+	componentName := filepath.Base(componentPath)
+
+	// Load existing manifest to preserve component_info
+	manifestObj, err := LoadComponentManifest(componentPath)
+	if err != nil {
+		// If manifest doesn't exist, create a new one
+		manifestObj, err = CreateComponentManifest(ComponentShader, componentName)
+		if err != nil {
+			return fmt.Errorf("error creating shader manifest: %w", err)
+		}
+	}
+
+	shaderManifest, ok := manifestObj.(*ShaderManifest)
+	if !ok {
+		return fmt.Errorf("invalid manifest type for shader component")
+	}
+
+	// Always update component name to match the directory name
+	shaderManifest.ComponentInfo.Name = componentName
+
+	if shaderManifest.Systems == nil {
+		shaderManifest.Systems = make(map[string]map[string]string)
+	}
+
+	// Settings are stored in the manifest itself; just drop anything that isn't video related
+	for tag, options := range shaderManifest.Systems {
+		for key := range options {
+			if !isShaderOption(key) {
+				logger.DebugFn("Warning: Removing non-video option %s for %s", key, tag)
+				delete(options, key)
+			}
+		}
+	}
+
+	// Write updated manifest
+	return WriteComponentManifest(componentPath, shaderManifest)
+}
@@ -0,0 +1,116 @@
+// src/internal/themes/sleep_wallpaper.go
+// Optional wallpaper for the suspend screen, on platforms whose firmware shows one
+
+package themes
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// sleepWallpaperFile is the sleep wallpaper's name in a .bg pack, and in a theme's Wallpapers folder
+const sleepWallpaperFile = "Sleep.png"
+
+// sleepWallpaperCandidates are where firmware reads the image it shows while the device is
+// suspended, relative to the SD card root. NextUI on the Brick shows none at the time of
+// writing. A location only counts once the firmware ships its own image there, so Theme
+// Manager never creates a file nothing reads.
+var sleepWallpaperCandidates = []string{
+	".system/res/sleep.png",
+}
+
+// sleepWallpaperBackup returns where the firmware's own sleep image is kept while a theme's is applied
+func sleepWallpaperBackup(path string) string {
+	return strings.TrimSuffix(path, ".png") + ".backup.png"
+}
+
+// SleepWallpaperPath returns where this device reads its suspend screen image, and false when
+// the platform doesn't show one
+func SleepWallpaperPath(systemPaths *system.SystemPaths) (string, bool) {
+	for _, candidate := range sleepWallpaperCandidates {
+		path := filepath.Join(systemPaths.Root, filepath.FromSlash(candidate))
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		if _, err := os.Stat(sleepWallpaperBackup(path)); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// applySleepWallpaper copies a package's sleep wallpaper over the firmware's, keeping a backup
+// of the original the first time. packageFile is the manifest entry, empty when there is none.
+func applySleepWallpaper(packagePath, packageFile string, systemPaths *system.SystemPaths, logger *Logger) {
+	if packageFile == "" {
+		return
+	}
+
+	path, ok := SleepWallpaperPath(systemPaths)
+	if !ok {
+		logger.DebugFn("This device has no suspend screen image, skipping sleep wallpaper")
+		return
+	}
+
+	backup := sleepWallpaperBackup(path)
+	if _, err := os.Stat(backup); os.IsNotExist(err) {
+		if err := CopyFile(path, backup); err != nil {
+			logger.DebugFn("Warning: Could not back up the firmware sleep image, skipping sleep wallpaper: %v", err)
+			return
+		}
+	}
+
+	srcPath := filepath.Join(packagePath, filepath.FromSlash(packageFile))
+	if err := copyMappedFile(srcPath, path, logger); err != nil {
+		logger.DebugFn("Warning: Failed to copy sleep wallpaper: %v", err)
+		return
+	}
+	logger.DebugFn("Applied sleep wallpaper: %s", path)
+}
+
+// restoreSleepWallpaper puts the firmware's own sleep image back, if a package replaced it
+func restoreSleepWallpaper(systemPaths *system.SystemPaths, logger *Logger) {
+	path, ok := SleepWallpaperPath(systemPaths)
+	if !ok {
+		return
+	}
+
+	backup := sleepWallpaperBackup(path)
+	if _, err := os.Stat(backup); err != nil {
+		return
+	}
+
+	// Remove first, the applied image may be a hard link to the package
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logger.DebugFn("Warning: Could not remove sleep wallpaper: %v", err)
+		return
+	}
+	if err := CopyFile(backup, path); err != nil {
+		logger.DebugFn("Warning: Could not restore the firmware sleep image: %v", err)
+		return
+	}
+	forgetManagedFile(path)
+	logger.DebugFn("Restored the firmware sleep image: %s", path)
+}
+
+// exportSleepWallpaper copies the applied sleep wallpaper into a package and returns its
+// manifest entry, or "" when the device shows the firmware's own image or none
+func exportSleepWallpaper(packagePath, packageFile string, systemPaths *system.SystemPaths, logger *Logger) string {
+	path, ok := SleepWallpaperPath(systemPaths)
+	if !ok {
+		return ""
+	}
+	if _, err := os.Stat(sleepWallpaperBackup(path)); err != nil {
+		return ""
+	}
+
+	if err := CopyFile(path, filepath.Join(packagePath, filepath.FromSlash(packageFile))); err != nil {
+		logger.DebugFn("Warning: Could not copy sleep wallpaper: %v", err)
+		return ""
+	}
+	logger.DebugFn("Exported sleep wallpaper to %s", packageFile)
+	return packageFile
+}
@@ -0,0 +1,234 @@
+// src/internal/themes/slideshow.go
+// Wallpaper slideshows: several wallpapers per system in a .bg pack, rotated per apply or per boot
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// Slideshow rotation policies
+const (
+	SlideshowPerApply = "apply" // The next wallpaper is shown every time the pack is applied (the default)
+	SlideshowPerBoot  = "boot"  // The next wallpaper is shown every time the device starts
+)
+
+// slideshowDir is the folder of a .bg pack holding one subfolder of wallpapers per system,
+// named like the system's ROM folder, e.g. Slideshows/Game Boy Advance (GBA)/*.png
+const slideshowDir = "Slideshows"
+
+// SlideshowState records the wallpaper a system's slideshow is showing, in the global manifest
+type SlideshowState struct {
+	Package    string   `json:"package"`     // Wallpaper package folder name
+	Policy     string   `json:"policy"`      // SlideshowPerApply or SlideshowPerBoot
+	Files      []string `json:"files"`       // Wallpapers in rotation order, relative to the package
+	Active     int      `json:"active"`      // Index of the wallpaper on the device
+	SystemPath string   `json:"system_path"` // Where the active wallpaper is written
+}
+
+// slideshowPolicy returns a pack's rotation policy, defaulting to per apply
+func slideshowPolicy(policy string) string {
+	if policy == SlideshowPerBoot {
+		return SlideshowPerBoot
+	}
+	return SlideshowPerApply
+}
+
+// scanSlideshows lists the slideshow wallpapers of a .bg pack by system tag, sorted by file name
+func scanSlideshows(componentPath string, logger *Logger) map[string][]string {
+	slideshows := make(map[string][]string)
+
+	entries, err := scanReadDir(filepath.Join(componentPath, slideshowDir))
+	if err != nil {
+		return slideshows
+	}
+
+	re := regexp.MustCompile(`\((.*?)\)`)
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		matches := re.FindStringSubmatch(entry.Name())
+		if len(matches) < 2 {
+			logger.DebugFn("Warning: Slideshow folder has no system tag: %s", entry.Name())
+			continue
+		}
+
+		files, err := scanReadDir(filepath.Join(componentPath, slideshowDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		var wallpapers []string
+		for _, file := range files {
+			if file.IsDir() || strings.HasPrefix(file.Name(), ".") || !strings.HasSuffix(strings.ToLower(file.Name()), ".png") {
+				continue
+			}
+			wallpapers = append(wallpapers, filepath.ToSlash(filepath.Join(slideshowDir, entry.Name(), file.Name())))
+		}
+		sort.Strings(wallpapers)
+
+		if len(wallpapers) > 0 {
+			slideshows[matches[1]] = wallpapers
+			logger.DebugFn("Added slideshow of %d wallpapers for %s", len(wallpapers), matches[1])
+		}
+	}
+
+	return slideshows
+}
+
+// applySlideshows shows the next wallpaper of each of a pack's slideshows and records them.
+// Reapplying the same pack moves every slideshow one wallpaper on.
+func applySlideshows(componentPath string, manifest *WallpaperManifest, systemPaths *system.SystemPaths, logger *Logger) {
+	if len(manifest.Content.Slideshows) == 0 {
+		return
+	}
+
+	packageName := filepath.Base(componentPath)
+	policy := slideshowPolicy(manifest.Content.SlideshowPolicy)
+	excluded := loadExcludedSystems()
+
+	// Cleanup already stopped the previous slideshows; keep only their positions
+	previous := previousSlideshows
+	previousSlideshows = nil
+
+	states := make(map[string]SlideshowState)
+	for tag, files := range manifest.Content.Slideshows {
+		if isTagExcluded(tag, excluded) {
+			logger.DebugFn("Skipping slideshow for excluded system: %s", tag)
+			continue
+		}
+
+		sys, ok := systemPaths.SystemForTag(tag)
+		if !ok {
+			logger.DebugFn("Skipping slideshow for system not on this device: %s", tag)
+			continue
+		}
+
+		state := SlideshowState{
+			Package:    packageName,
+			Policy:     policy,
+			Files:      files,
+			SystemPath: filepath.Join(sys.MediaPath, "bg.png"),
+		}
+		if last, ok := previous[tag]; ok && last.Package == packageName {
+			state.Active = (last.Active + 1) % len(files)
+		}
+
+		srcPath := filepath.Join(componentPath, filepath.FromSlash(files[state.Active]))
+		err := copyMappedFile(srcPath, state.SystemPath, logger)
+		reportCopyResult(reportWallpapers, err)
+		if err != nil {
+			logger.DebugFn("Warning: Failed to copy slideshow wallpaper: %v", err)
+			continue
+		}
+
+		logger.DebugFn("Slideshow for %s showing %d of %d: %s", tag, state.Active+1, len(files), files[state.Active])
+		states[tag] = state
+	}
+
+	if err := UpdateGlobalManifest(func(global *GlobalManifest) error {
+		global.Slideshows = states
+		return nil
+	}); err != nil {
+		logger.DebugFn("Warning: Could not record slideshows: %v", err)
+		return
+	}
+
+	updateSlideshowBootHook(states, logger)
+}
+
+// previousSlideshows holds the slideshows stopped by the last wallpaper cleanup, so
+// reapplying the same pack continues where it left off
+var previousSlideshows map[string]SlideshowState
+
+// stopSlideshows forgets the running slideshows and removes the boot hook. Called when
+// wallpapers are cleaned up, so a later boot can't put an old pack's wallpaper back.
+func stopSlideshows(logger *Logger) {
+	stopped := false
+	if err := UpdateGlobalManifest(func(global *GlobalManifest) error {
+		if len(global.Slideshows) == 0 {
+			return errGlobalManifestUnchanged
+		}
+		previousSlideshows = global.Slideshows
+		global.Slideshows = nil
+		stopped = true
+		return nil
+	}); err != nil {
+		logger.DebugFn("Warning: Could not clear slideshows: %v", err)
+	}
+	if stopped {
+		updateSlideshowBootHook(nil, logger)
+	}
+}
+
+// RotateSlideshowsOnBoot moves every per-boot slideshow one wallpaper on. Run from NextUI's
+// auto.sh with --rotate-slideshows.
+func RotateSlideshowsOnBoot() error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	rotated := 0
+	err = UpdateGlobalManifest(func(global *GlobalManifest) error {
+		for tag, state := range global.Slideshows {
+			if state.Policy != SlideshowPerBoot || len(state.Files) == 0 {
+				continue
+			}
+
+			next := (state.Active + 1) % len(state.Files)
+			srcPath := filepath.Join(cwd, "Components", ComponentDirectory[ComponentWallpaper], state.Package, filepath.FromSlash(state.Files[next]))
+			if err := copyMappedFile(srcPath, state.SystemPath, logger); err != nil {
+				logger.DebugFn("Warning: Could not rotate slideshow for %s: %v", tag, err)
+				continue
+			}
+
+			state.Active = next
+			global.Slideshows[tag] = state
+			rotated++
+		}
+
+		if rotated == 0 {
+			return errGlobalManifestUnchanged
+		}
+		return nil
+	})
+	if err != nil || rotated == 0 {
+		return err
+	}
+
+	if err := saveManagedLedger(); err != nil {
+		logger.DebugFn("Warning: Could not save managed files ledger: %v", err)
+	}
+
+	logger.DebugFn("Rotated %d slideshows", rotated)
+	return nil
+}
+
+// updateSlideshowBootHook adds the rotation line to auto.sh while any slideshow rotates per
+// boot, and removes it otherwise
+func updateSlideshowBootHook(states map[string]SlideshowState, logger *Logger) {
+	needed := false
+	for _, state := range states {
+		if state.Policy == SlideshowPerBoot {
+			needed = true
+			break
+		}
+	}
+
+	setBootHook("slideshow", "--rotate-slideshows", needed, logger)
+}
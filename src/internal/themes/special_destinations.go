@@ -0,0 +1,156 @@
+// src/internal/themes/special_destinations.go
+// Registry of the NextUI surfaces that aren't ROM systems or collections, e.g. Root and Tools
+
+package themes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// specialDestinationsJSON lists where the special wallpapers and icons go on the device.
+// A new NextUI surface only needs an entry here.
+//
+//go:embed special_destinations.json
+var specialDestinationsJSON []byte
+
+// SpecialDestination is a wallpaper or icon with a fixed place on the device
+type SpecialDestination struct {
+	Name       string `json:"name"`                  // File name in the package, without .png
+	Base       string `json:"base"`                  // root, recently_played, tools or tools_parent
+	Path       string `json:"path"`                  // Relative to the base folder, {platform} is the platform folder name
+	SystemName string `json:"system_name,omitempty"` // Defaults to Name
+	Type       string `json:"type"`                  // WallpaperType or IconType metadata
+}
+
+// specialDestinations is the parsed registry, loaded on first use
+var specialDestinations struct {
+	Wallpapers []SpecialDestination `json:"wallpapers"`
+	Icons      []SpecialDestination `json:"icons"`
+}
+
+var specialDestinationsOnce sync.Once
+
+// loadSpecialDestinations parses the embedded registry once
+func loadSpecialDestinations() {
+	specialDestinationsOnce.Do(func() {
+		if err := json.Unmarshal(specialDestinationsJSON, &specialDestinations); err != nil {
+			logging.LogDebug("Error parsing special destinations: %v", err)
+		}
+	})
+}
+
+// SpecialWallpapers returns the wallpapers that go to fixed places, such as Root and Tools
+func SpecialWallpapers() []SpecialDestination {
+	loadSpecialDestinations()
+	return specialDestinations.Wallpapers
+}
+
+// SpecialIcons returns the icons that go to fixed places, such as Recently Played
+func SpecialIcons() []SpecialDestination {
+	loadSpecialDestinations()
+	return specialDestinations.Icons
+}
+
+// findSpecialDestination looks up an entry by its package file name, with or without .png
+func findSpecialDestination(destinations []SpecialDestination, fileName string) (SpecialDestination, bool) {
+	name := strings.TrimSuffix(fileName, ".png")
+	for _, destination := range destinations {
+		if destination.Name == name {
+			return destination, true
+		}
+	}
+	return SpecialDestination{}, false
+}
+
+// isSpecialIconFile reports whether a file name on the device belongs to a special icon
+func isSpecialIconFile(fileName string, systemPaths *system.SystemPaths) bool {
+	for _, destination := range SpecialIcons() {
+		if filepath.Base(destination.SystemPath(systemPaths)) == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// FileName returns the name of the file in the package, e.g. "Recently Played.png"
+func (d SpecialDestination) FileName() string {
+	return d.Name + ".png"
+}
+
+// SystemPath resolves where the file goes on this device
+func (d SpecialDestination) SystemPath(systemPaths *system.SystemPaths) string {
+	var base string
+	switch d.Base {
+	case "recently_played":
+		base = systemPaths.RecentlyPlayed
+	case "tools":
+		base = systemPaths.Tools
+	case "tools_parent":
+		// The Tools path includes the platform folder, e.g. Tools/tg5040
+		base = filepath.Dir(systemPaths.Tools)
+	default:
+		base = systemPaths.Root
+	}
+	path := strings.ReplaceAll(d.Path, "{platform}", systemPaths.Platform)
+	return filepath.Join(base, filepath.FromSlash(path))
+}
+
+// Metadata returns the path mapping metadata, with the type stored under typeKey
+// ("WallpaperType" or "IconType")
+func (d SpecialDestination) Metadata(typeKey string) map[string]string {
+	systemName := d.SystemName
+	if systemName == "" {
+		systemName = d.Name
+	}
+	return map[string]string{
+		"SystemName": systemName,
+		typeKey:      d.Type,
+	}
+}
+
+// exportSpecialDestinations copies the special files present on the device into the package
+// under dir and returns their mappings, with the type stored under typeKey
+func exportSpecialDestinations(destinations []SpecialDestination, packagePath, dir, typeKey string, systemPaths *system.SystemPaths, logger *Logger) []PathMapping {
+	var mappings []PathMapping
+	for _, special := range destinations {
+		srcPath := special.SystemPath(systemPaths)
+		if _, err := os.Stat(srcPath); err != nil {
+			continue
+		}
+
+		packageFile := dir + "/" + special.FileName()
+		destPath := filepath.Join(packagePath, filepath.FromSlash(packageFile))
+		if err := CopyFile(srcPath, destPath); err != nil {
+			logger.DebugFn("Warning: Could not copy %s: %v", srcPath, err)
+			continue
+		}
+
+		mappings = append(mappings, PathMapping{
+			ThemePath:  packageFile,
+			SystemPath: srcPath,
+			Metadata:   special.Metadata(typeKey),
+		})
+		logger.DebugFn("Exported %s to %s", special.Name, destPath)
+	}
+	return mappings
+}
+
+// cleanupSpecialDestinations removes the special files Theme Manager wrote to the device
+func cleanupSpecialDestinations(destinations []SpecialDestination, systemPaths *system.SystemPaths, logger *Logger) {
+	for _, special := range destinations {
+		path := special.SystemPath(systemPaths)
+		if err := removeThemeFile(path); err != nil && !os.IsNotExist(err) {
+			logger.DebugFn("Warning: Could not remove %s: %v", path, err)
+		} else if err == nil {
+			logger.DebugFn("Removed %s: %s", special.Name, path)
+		}
+	}
+}
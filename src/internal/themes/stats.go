@@ -0,0 +1,99 @@
+// src/internal/themes/stats.go
+// Contents breakdown and disk usage for installed packages
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// statsLargestFiles is how many of the biggest files are reported
+const statsLargestFiles = 5
+
+// PackageFile is a file inside a package and its size
+type PackageFile struct {
+	Path string // Relative to the package
+	Size int64
+}
+
+// PackageStats summarizes what a package contains and how much space it takes
+type PackageStats struct {
+	Wallpapers int
+	Icons      int
+	Overlays   int
+	Fonts      int
+	Other      int
+	TotalSize  int64
+	Largest    []PackageFile
+}
+
+// GetPackageStats walks a package and counts its files by section
+func GetPackageStats(packagePath string) (*PackageStats, error) {
+	stats := &PackageStats{}
+	var files []PackageFile
+
+	err := filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(packagePath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Sections are the top-level folders of a theme
+		switch strings.SplitN(rel, "/", 2)[0] {
+		case "Wallpapers":
+			stats.Wallpapers++
+		case "Icons":
+			stats.Icons++
+		case "Overlays":
+			stats.Overlays++
+		case "Fonts":
+			stats.Fonts++
+		default:
+			stats.Other++
+		}
+
+		stats.TotalSize += info.Size()
+		files = append(files, PackageFile{Path: rel, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading package %s: %w", packagePath, err)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Size > files[j].Size
+	})
+	if len(files) > statsLargestFiles {
+		files = files[:statsLargestFiles]
+	}
+	stats.Largest = files
+
+	return stats, nil
+}
+
+// FormatSize renders a byte count for display, e.g. "1.4 MB"
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGT"[exp])
+}
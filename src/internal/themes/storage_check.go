@@ -0,0 +1,76 @@
+// src/internal/themes/storage_check.go
+// Detects an unmounted or read-only SD card before an apply starts writing
+
+package themes
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// storageRoot is the mount point of the SD card
+const storageRoot = "/mnt/SDCARD"
+
+// errStorageReadOnly is returned when the SD card can't be written to
+var errStorageReadOnly = errors.New("the SD card is read-only")
+
+// mountOptions returns the options of the mount that holds path, from /proc/mounts
+func mountOptions(path string) (mountPoint string, options []string, err error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	// The longest mount point that prefixes path is the one holding it
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		point := fields[1]
+		if path != point && !strings.HasPrefix(path, strings.TrimSuffix(point, "/")+"/") {
+			continue
+		}
+		if len(point) > len(mountPoint) {
+			mountPoint = point
+			options = strings.Split(fields[3], ",")
+		}
+	}
+
+	return mountPoint, options, scanner.Err()
+}
+
+// CheckStorageWritable makes sure the SD card is mounted and writable, returning
+// a single error with advice instead of letting every file copy fail on its own
+func CheckStorageWritable() error {
+	if _, err := os.Stat(storageRoot); err != nil {
+		return fmt.Errorf("the SD card is not mounted at %s. Restart the device and try again", storageRoot)
+	}
+
+	if mountPoint, options, err := mountOptions(storageRoot); err == nil {
+		for _, option := range options {
+			if option == "ro" {
+				return fmt.Errorf("%w (%s is mounted read-only). This usually means the card needs a filesystem check: "+
+					"power off, run a disk check on the card from a computer, then try again", errStorageReadOnly, mountPoint)
+			}
+		}
+	}
+
+	// The mount table can't show a card whose write-protect switch is on, so also try a write
+	probe, err := os.CreateTemp(storageRoot, ".theme-manager-write-test-*")
+	if err != nil {
+		return fmt.Errorf("%w (%v). Check the card's lock switch and free space, or run a disk check on it from a computer",
+			errStorageReadOnly, err)
+	}
+	probe.Close()
+	os.Remove(filepath.Clean(probe.Name()))
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// src/internal/themes/storage_roots.go
+// Theming ROM folders split across several storage roots, such as a second SD card
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// storageMirrors maps a file written for a system on the SD card to the same file for the
+// systems with its tag on the other storage roots, for the apply in progress
+var storageMirrors struct {
+	media map[string][]string // .media folder of an SD card system to the other roots' .media folders
+	icons map[string][]string // Icon path of an SD card system to the other roots' icon paths
+}
+
+// GetStorageRoots returns the extra storage roots themed with the SD card
+func GetStorageRoots() []string {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load storage roots: %v", err)
+		return nil
+	}
+	return config.StorageRoots
+}
+
+// ToggleStorageRoot adds a storage root to those themed with the SD card, or removes it
+func ToggleStorageRoot(root string) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	var updated []string
+	found := false
+	for _, existing := range config.StorageRoots {
+		if filepath.Clean(existing) == filepath.Clean(root) {
+			found = true
+			continue
+		}
+		updated = append(updated, existing)
+	}
+	if !found {
+		updated = append(updated, root)
+		logging.LogDebug("Added storage root: %s", root)
+	} else {
+		logging.LogDebug("Removed storage root: %s", root)
+	}
+
+	config.StorageRoots = updated
+	return SaveConfig(config)
+}
+
+// CandidateStorageRoots returns the mounted storage with a Roms folder, plus the configured
+// roots, which are kept even while their card isn't inserted
+func CandidateStorageRoots() []string {
+	seen := make(map[string]bool)
+	var roots []string
+	for _, root := range GetStorageRoots() {
+		if !seen[filepath.Clean(root)] {
+			seen[filepath.Clean(root)] = true
+			roots = append(roots, root)
+		}
+	}
+
+	for _, root := range ExternalStorageRoots() {
+		if seen[filepath.Clean(root)] {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(root, "Roms")); err == nil && info.IsDir() {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// beginStorageMirrors works out, for a new apply, which systems on other storage roots get a
+// copy of the files written for the SD card's systems with the same tag
+func beginStorageMirrors() {
+	storageMirrors.media = nil
+	storageMirrors.icons = nil
+	if len(system.StorageRoots()) == 0 {
+		return
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil || len(systemPaths.RomsDirs) < 2 {
+		return
+	}
+
+	storageMirrors.media = make(map[string][]string)
+	storageMirrors.icons = make(map[string][]string)
+	excluded := loadExcludedSystems()
+	for _, primary := range systemPaths.Systems {
+		if primary.Root != systemPaths.Root || primary.Tag == "" {
+			continue
+		}
+		for _, other := range systemPaths.Systems {
+			if other.Root == systemPaths.Root || !system.SameName(other.Tag, primary.Tag) || isTagExcluded(other.Tag, excluded) {
+				continue
+			}
+			storageMirrors.media[primary.MediaPath] = append(storageMirrors.media[primary.MediaPath], other.MediaPath)
+			storageMirrors.icons[primary.IconPath] = append(storageMirrors.icons[primary.IconPath], other.IconPath)
+		}
+	}
+}
+
+// storageMirrorPaths returns where else a file applied to dstPath goes on other storage roots
+func storageMirrorPaths(dstPath string) []string {
+	if len(storageMirrors.icons) == 0 {
+		return nil
+	}
+
+	dstPath = filepath.Clean(dstPath)
+	if icons, ok := storageMirrors.icons[dstPath]; ok {
+		return icons
+	}
+
+	var mirrors []string
+	for _, media := range storageMirrors.media[filepath.Dir(dstPath)] {
+		mirrors = append(mirrors, filepath.Join(media, filepath.Base(dstPath)))
+	}
+	return mirrors
+}
+
+// mirrorToStorageRoots copies a file just applied to a system on the SD card to the systems
+// with the same tag on the other storage roots
+func mirrorToStorageRoots(srcPath, dstPath string, logger *Logger) {
+	for _, mirror := range storageMirrorPaths(dstPath) {
+		if err := copyMappedFileOnce(srcPath, mirror, logger); err != nil {
+			logger.DebugFn("Warning: Could not apply %s to other storage: %v", filepath.Base(dstPath), err)
+		}
+	}
+}
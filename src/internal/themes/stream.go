@@ -0,0 +1,31 @@
+// src/internal/themes/stream.go
+// Fixed-buffer streaming used by every copy and hash so files are never read whole
+
+package themes
+
+import (
+	"io"
+	"sync"
+)
+
+// streamBufferSize is the buffer each copy streams through; memory use stays flat
+// no matter how large the file is
+const streamBufferSize = 64 * 1024
+
+// streamBuffers reuses copy buffers across the thousands of files a batch operation touches
+var streamBuffers = sync.Pool{
+	New: func() any {
+		buffer := make([]byte, streamBufferSize)
+		return &buffer
+	},
+}
+
+// streamCopy copies src to dst through a pooled fixed-size buffer
+func streamCopy(dst io.Writer, src io.Reader) (int64, error) {
+	buffer := streamBuffers.Get().(*[]byte)
+	defer streamBuffers.Put(buffer)
+
+	// Hide ReadFrom and WriteTo so io.CopyBuffer always uses our buffer instead
+	// of falling back to a fresh allocation for every file
+	return io.CopyBuffer(struct{ io.Writer }{dst}, struct{ io.Reader }{src}, *buffer)
+}
@@ -0,0 +1,90 @@
+// src/internal/themes/strict.go
+// Strict mode: turns warnings logged during import and export into hard errors
+
+package themes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// errStrictWarning stops a strict operation at the first warning it logs
+var errStrictWarning = errors.New("strict mode stopped at a warning")
+
+// strictFlag is set for the session by the --strict command line flag
+var strictFlag bool
+
+// EnableStrictMode turns strict mode on for this session regardless of the saved setting
+func EnableStrictMode() {
+	strictFlag = true
+}
+
+// IsStrictMode reports whether warnings should fail imports and exports
+func IsStrictMode() bool {
+	if strictFlag {
+		return true
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load strict mode setting: %v", err)
+		return false
+	}
+	return config.StrictMode
+}
+
+// SetStrictMode stores the strict mode setting
+func SetStrictMode(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.StrictMode = enabled
+	return SaveConfig(config)
+}
+
+// strictStopped returns an error once an operation run by RunStrict has logged a warning.
+// Applies and exports check it between steps along with their context, so they stop before
+// writing more to the device or publishing the package.
+func strictStopped() error {
+	if warning := logging.FirstCapturedWarning(); warning != "" {
+		return fmt.Errorf("%w: %s", errStrictWarning, warning)
+	}
+	return nil
+}
+
+// RunStrict runs an import or export operation and, in strict mode, fails it at the first
+// warning it logs. A package the operation exported is removed, so nothing that failed
+// certification is left to publish.
+func RunStrict(operation func() error) error {
+	if !IsStrictMode() {
+		return operation()
+	}
+
+	previousExport := lastExportPath
+	logging.BeginWarningCapture()
+	err := operation()
+	warnings := logging.EndWarningCapture()
+
+	if len(warnings) > 0 && lastExportPath != "" && lastExportPath != previousExport {
+		if removeErr := os.RemoveAll(lastExportPath); removeErr != nil {
+			logging.LogDebug("Error removing export that failed strict mode %s: %v", lastExportPath, removeErr)
+		}
+		lastExportPath = previousExport
+	}
+
+	if err != nil && !errors.Is(err, errStrictWarning) {
+		return err
+	}
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	logging.LogDebug("Strict mode: %d warnings turned into errors", len(warnings))
+	return fmt.Errorf("strict mode found %d warnings:\n%s", len(warnings), strings.Join(warnings, "\n"))
+}
@@ -0,0 +1,439 @@
+// src/internal/themes/submit.go
+// Submits exported packages to the community catalog, by upload or a pre-filled issue link
+
+package themes
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// Size of previews generated for packages that don't have one
+const (
+	submissionPreviewWidth  = 1024
+	submissionPreviewHeight = 768
+)
+
+// SubmissionInfo is the manifest metadata sent along with a submission
+type SubmissionInfo struct {
+	Name    string
+	Type    string // "theme" or a component type
+	Author  string
+	Version string
+}
+
+// SubmitResult tells the user where their submission went
+type SubmitResult struct {
+	Uploaded bool   // The package was uploaded to the intake endpoint
+	Link     string // Where to follow the submission, or where to finish it when not uploaded
+	Issues   int    // Lint issues found in the package
+}
+
+// GetSubmitSettings returns the intake endpoint and token set in config.json, if any
+func GetSubmitSettings() (endpoint string, token string) {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load submit settings: %v", err)
+		return "", ""
+	}
+	return config.SubmitEndpoint, config.SubmitToken
+}
+
+// ListExportedPackages returns the theme and component packages in the export folder
+func ListExportedPackages() ([]string, error) {
+	exportsDir, err := GetExportsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(exportsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading exports: %w", err)
+	}
+
+	known := map[string]bool{".theme": true}
+	for _, ext := range ComponentExtension {
+		known[ext] = true
+	}
+
+	var packages []string
+	for _, entry := range entries {
+		if entry.IsDir() && known[filepath.Ext(entry.Name())] {
+			packages = append(packages, entry.Name())
+		}
+	}
+
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// readSubmissionInfo reads the name, type, author and version from a package manifest
+func readSubmissionInfo(packagePath string, logger *Logger) (*SubmissionInfo, error) {
+	if filepath.Ext(packagePath) == ".theme" {
+		manifest, err := ValidateTheme(packagePath, logger)
+		if err != nil {
+			return nil, err
+		}
+		return &SubmissionInfo{
+			Name:    manifest.ThemeInfo.Name,
+			Type:    "theme",
+			Author:  manifest.ThemeInfo.Author,
+			Version: manifest.ThemeInfo.Version,
+		}, nil
+	}
+
+	manifestObj, err := LoadComponentManifest(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := GetComponentInfo(manifestObj)
+	if info == nil {
+		return nil, fmt.Errorf("unsupported manifest in %s", packagePath)
+	}
+	return &SubmissionInfo{
+		Name:    info.Name,
+		Type:    info.Type,
+		Author:  info.Author,
+		Version: info.Version,
+	}, nil
+}
+
+// packageImages returns up to limit PNG images of a package, preview excluded, in a stable order
+func packageImages(packagePath string, limit int) []string {
+	var images []string
+	filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
+		if len(images) >= limit {
+			return filepath.SkipAll
+		}
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if info.Name() != "preview.png" && strings.HasSuffix(strings.ToLower(info.Name()), ".png") {
+			images = append(images, path)
+		}
+		return nil
+	})
+	return images
+}
+
+// ensureSubmissionPreview builds a preview for packages without a real preview.png.
+// Exports without one (or with the blank placeholder) get a collage of up to four of
+// their images, written into stagingDir so the export itself is left alone.
+// Returns the path of the new preview, or "" when none was built.
+func ensureSubmissionPreview(packagePath, stagingDir string, logger *Logger) (string, error) {
+	if info, err := os.Stat(filepath.Join(packagePath, "preview.png")); err == nil && info.Size() > 0 {
+		return "", nil
+	}
+
+	images := packageImages(packagePath, 4)
+	if len(images) == 0 {
+		// LED, accent and shader packages have nothing to show
+		logger.DebugFn("No images to build a preview from in %s", packagePath)
+		return "", nil
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, submissionPreviewWidth, submissionPreviewHeight))
+	cols := 1
+	if len(images) > 1 {
+		cols = 2
+	}
+	rows := (len(images) + cols - 1) / cols
+	tileW, tileH := submissionPreviewWidth/cols, submissionPreviewHeight/rows
+
+	for i, path := range images {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		img, err := png.Decode(file)
+		file.Close()
+		if err != nil {
+			logger.DebugFn("Warning: Could not decode %s for the preview: %v", path, err)
+			continue
+		}
+
+		// Nearest-neighbour scaling is plenty for a catalog thumbnail
+		tile := image.Rect((i%cols)*tileW, (i/cols)*tileH, (i%cols+1)*tileW, (i/cols+1)*tileH)
+		src := img.Bounds()
+		for y := tile.Min.Y; y < tile.Max.Y; y++ {
+			sy := src.Min.Y + (y-tile.Min.Y)*src.Dy()/tileH
+			for x := tile.Min.X; x < tile.Max.X; x++ {
+				sx := src.Min.X + (x-tile.Min.X)*src.Dx()/tileW
+				canvas.Set(x, y, img.At(sx, sy))
+			}
+		}
+	}
+
+	// Tiles keep the alpha of their source; flatten onto black like the device does
+	out := image.NewNRGBA(canvas.Bounds())
+	draw.Draw(out, out.Bounds(), image.Black, image.Point{}, draw.Src)
+	draw.Draw(out, out.Bounds(), canvas, image.Point{}, draw.Over)
+
+	previewPath := filepath.Join(stagingDir, "preview.png")
+	file, err := os.Create(previewPath)
+	if err != nil {
+		return "", fmt.Errorf("error creating preview: %w", err)
+	}
+	defer file.Close()
+
+	if err := png.Encode(file, out); err != nil {
+		return "", fmt.Errorf("error encoding preview: %w", err)
+	}
+
+	logger.DebugFn("Generated preview from %d images for %s", len(images), filepath.Base(packagePath))
+	return previewPath, nil
+}
+
+// submissionIssueLink builds a link that opens a pre-filled submission on the catalog repository
+func submissionIssueLink(packageName string, info *SubmissionInfo) string {
+	query := url.Values{}
+	query.Set("title", fmt.Sprintf("Submission: %s", packageName))
+	query.Set("body", fmt.Sprintf("Type: %s\nAuthor: %s\nVersion: %s", info.Type, info.Author, info.Version))
+	query.Set("labels", "submission")
+
+	return fmt.Sprintf("%s/issues/new?%s", DefaultRepoURL, query.Encode())
+}
+
+// checkSubmitEndpoint makes sure the submit token only ever travels over https
+func checkSubmitEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("submit_endpoint must be an https:// address, not %q", endpoint)
+	}
+	return nil
+}
+
+// uploadSubmission streams the zipped package, its preview and metadata to the intake endpoint.
+// previewPath overrides the package's own preview.png when set.
+func uploadSubmission(endpoint, token, packagePath, previewPath string, info *SubmissionInfo) (string, error) {
+	if err := checkSubmitEndpoint(endpoint); err != nil {
+		return "", err
+	}
+
+	replacements := map[string]string{}
+	if previewPath == "" {
+		previewPath = filepath.Join(packagePath, "preview.png")
+	} else {
+		replacements["preview.png"] = previewPath
+	}
+
+	body, pipe := io.Pipe()
+	form := multipart.NewWriter(pipe)
+
+	// Zip straight into the request so large themes never sit in memory or on disk twice
+	go func() {
+		err := func() error {
+			fields := map[string]string{
+				"name":    info.Name,
+				"type":    info.Type,
+				"author":  info.Author,
+				"version": info.Version,
+			}
+			for key, value := range fields {
+				if err := form.WriteField(key, value); err != nil {
+					return err
+				}
+			}
+
+			manifest, err := os.ReadFile(filepath.Join(packagePath, "manifest.json"))
+			if err != nil {
+				return err
+			}
+			part, err := form.CreateFormFile("manifest", "manifest.json")
+			if err != nil {
+				return err
+			}
+			if _, err := part.Write(manifest); err != nil {
+				return err
+			}
+
+			if preview, err := os.Open(previewPath); err == nil {
+				part, err := form.CreateFormFile("preview", "preview.png")
+				if err == nil {
+					_, err = streamCopy(part, preview)
+				}
+				preview.Close()
+				if err != nil {
+					return err
+				}
+			}
+
+			part, err = form.CreateFormFile("package", filepath.Base(packagePath)+".zip")
+			if err != nil {
+				return err
+			}
+			archive := zip.NewWriter(part)
+			if err := addPackageToZipWith(archive, packagePath, replacements); err != nil {
+				return err
+			}
+			if err := archive.Close(); err != nil {
+				return err
+			}
+			return form.Close()
+		}()
+		pipe.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	if err != nil {
+		body.Close()
+		return "", fmt.Errorf("error creating submission request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := &http.Client{
+		Timeout: 10 * time.Minute,
+		// Never follow the intake onto plain http with the token attached
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("refusing to follow redirect to %s", req.URL.Redacted())
+			}
+			return nil
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		body.Close()
+		return "", fmt.Errorf("upload error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", fmt.Errorf("the catalog rejected your submit token (%s)", resp.Status)
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return "", fmt.Errorf("HTTP error: %s", resp.Status)
+	}
+
+	// The intake may answer with a link to follow the submission
+	var reply struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		logging.LogDebug("Submission reply had no tracking link: %v", err)
+	}
+	return reply.URL, nil
+}
+
+// SubmitPackage submits an exported package to the community catalog. With an intake
+// endpoint and token in config.json it is uploaded; otherwise the result links to a
+// pre-filled submission to finish on a phone or computer.
+func SubmitPackage(packageName string) (*SubmitResult, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	exportsDir, err := GetExportsDir()
+	if err != nil {
+		return nil, err
+	}
+	packagePath := filepath.Join(exportsDir, packageName)
+
+	endpoint, token := GetSubmitSettings()
+	if endpoint != "" {
+		if err := checkSubmitEndpoint(endpoint); err != nil {
+			return nil, err
+		}
+		if token == "" {
+			return nil, fmt.Errorf("set submit_token in config.json to upload to %s", endpoint)
+		}
+	}
+
+	// A generated preview is staged outside the export and only copied in once the
+	// submission went through, so a failed lint or upload leaves the export untouched
+	stagingDir, err := os.MkdirTemp("", "submit-preview-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating preview staging folder: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	generated, err := ensureSubmissionPreview(packagePath, stagingDir, logger)
+	if err != nil {
+		logger.DebugFn("Warning: Could not generate preview: %v", err)
+	}
+
+	// Point out packaging mistakes before the catalog's review does. LED and shader
+	// packages legitimately lack a preview, so issues are reported rather than fatal.
+	issues, err := LintPackage(packagePath)
+	if err != nil {
+		return nil, err
+	}
+	if generated != "" {
+		issues = withoutMissingPreview(issues)
+	}
+	if len(issues) > 0 {
+		logger.DebugFn("Lint found %d issues in %s:\n%s", len(issues), packageName, FormatLintReport(issues))
+	}
+
+	info, err := readSubmissionInfo(packagePath, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error reading package manifest: %w", err)
+	}
+
+	// Exported previews are already stamped; credit the one generated here too
+	if generated != "" {
+		stampPreview(stagingDir, info.Name, info.Author, logger)
+	}
+
+	if endpoint == "" {
+		logger.DebugFn("No submit endpoint set, using a pre-filled submission link")
+		keepSubmissionPreview(generated, packagePath)
+		return &SubmitResult{Link: submissionIssueLink(packageName, info), Issues: len(issues)}, nil
+	}
+
+	if err := CheckBatteryForOperation("uploading a submission"); err != nil {
+		return nil, err
+	}
+
+	logger.DebugFn("Uploading %s to %s", packageName, endpoint)
+	link, err := uploadSubmission(endpoint, token, packagePath, generated, info)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.DebugFn("Submitted %s", packageName)
+	keepSubmissionPreview(generated, packagePath)
+	return &SubmitResult{Uploaded: true, Link: link, Issues: len(issues)}, nil
+}
+
+// withoutMissingPreview drops the missing preview issue for packages submitted with a generated one
+func withoutMissingPreview(issues []LintIssue) []LintIssue {
+	var kept []LintIssue
+	for _, issue := range issues {
+		if issue.Problem != "preview.png is missing" {
+			kept = append(kept, issue)
+		}
+	}
+	return kept
+}
+
+// keepSubmissionPreview copies a generated preview into the export it was submitted with
+func keepSubmissionPreview(previewPath, packagePath string) {
+	if previewPath == "" {
+		return
+	}
+	if err := CopyFile(previewPath, filepath.Join(packagePath, "preview.png")); err != nil {
+		logging.LogDebug("Warning: Could not keep the generated preview: %v", err)
+	}
+}
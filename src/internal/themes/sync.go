@@ -5,9 +5,9 @@ package themes
 
 import (
 	"archive/zip"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -43,11 +43,18 @@ type CatalogData struct {
 
 // CatalogItemInfo represents an item in the catalog
 type CatalogItemInfo struct {
-	PreviewPath  string `json:"preview_path"`
-	ManifestPath string `json:"manifest_path"`
-	Author       string `json:"author"`
-	Description  string `json:"description"`
-	URL          string `json:"URL"` // Added URL field for ZIP download
+	PreviewPath  string   `json:"preview_path"`
+	ManifestPath string   `json:"manifest_path"`
+	Author       string   `json:"author"`
+	Description  string   `json:"description"`
+	URL          string   `json:"URL"` // Added URL field for ZIP download
+	Tags         []string `json:"tags,omitempty"`
+
+	// SHA-256 of the package at URL, checked before a download is installed
+	SHA256 string `json:"sha256,omitempty"`
+
+	// Smaller downloads that update an installed older version of a theme
+	Patches []CatalogPatch `json:"patches,omitempty"`
 }
 
 // SyncOptions contains options for syncing
@@ -89,20 +96,36 @@ func SetRepoBranch(branch string) {
 
 // SyncThemeCatalog syncs the theme catalog from the repository
 func SyncThemeCatalog(options SyncOptions) error {
+	return SyncThemeCatalogContext(context.Background(), options)
+}
+
+// SyncThemeCatalogContext syncs the theme catalog, giving up once ctx is done. A sync
+// stopped halfway keeps the previews and manifests it already downloaded.
+func SyncThemeCatalogContext(ctx context.Context, options SyncOptions) (err error) {
+	defer func() { recordOperation("Synced catalog", "", err) }()
+	defer logging.BeginOperation("sync catalog", options.RepoURL)()
 	logging.LogDebug("Starting theme catalog sync from %s", options.RepoURL)
 
+	if err := CheckBatteryForOperation("syncing the catalog"); err != nil {
+		return err
+	}
+
 	// Create directory structure if it doesn't exist
-	err := createSyncDirectoryStructure(options.LocalDirPath)
+	err = createSyncDirectoryStructure(options.LocalDirPath)
 	if err != nil {
 		return fmt.Errorf("error creating directory structure: %w", err)
 	}
 
 	// First, try to use the HTTP method which is more efficient for this use case
-	if err := syncCatalogViaHTTP(options); err != nil {
+	if err := syncCatalogViaHTTP(ctx, options); err != nil {
+		// Falling back to Git would only run into the same deadline
+		if ctx.Err() != nil {
+			return fmt.Errorf("catalog sync stopped: %w", err)
+		}
 		logging.LogDebug("HTTP sync failed, falling back to Git: %v", err)
 
 		// If HTTP method fails, fall back to Git
-		if err := syncCatalogViaGit(options); err != nil {
+		if err := syncCatalogViaGit(ctx, options); err != nil {
 			return fmt.Errorf("git sync failed: %w", err)
 		}
 	}
@@ -134,11 +157,9 @@ func createSyncDirectoryStructure(basePath string) error {
 	// Create Components directory and subdirectories
 	componentsDir := filepath.Join(catalogDir, "Components")
 
-	// Component types
-	componentTypes := []string{"Wallpapers", "Icons", "Accents", "LEDs", "Fonts", "Overlays"}
-
 	// Create directories for each component type
-	for _, compDirName := range componentTypes {
+	for _, kind := range ComponentKinds() {
+		compDirName := kind.Directory()
 		compDir := filepath.Join(componentsDir, compDirName)
 		if err := os.MkdirAll(filepath.Join(compDir, "previews"), 0755); err != nil {
 			return fmt.Errorf("error creating %s/previews directory: %w", compDirName, err)
@@ -158,7 +179,7 @@ func createSyncDirectoryStructure(basePath string) error {
 }
 
 // syncCatalogViaHTTP downloads catalog data using HTTP(S) requests - more efficient for small files
-func syncCatalogViaHTTP(options SyncOptions) error {
+func syncCatalogViaHTTP(ctx context.Context, options SyncOptions) error {
 	// Base URL for raw content
 	baseURL := options.RepoURL
 	if strings.Contains(baseURL, "github.com") {
@@ -177,7 +198,7 @@ func syncCatalogViaHTTP(options SyncOptions) error {
 
 	// Download the catalog file
 	localCatalogPath := filepath.Join(options.LocalDirPath, "Catalog", "catalog.json")
-	if err := downloadFile(catalogURL, localCatalogPath); err != nil {
+	if err := downloadFileContext(ctx, catalogURL, localCatalogPath); err != nil {
 		return fmt.Errorf("error downloading catalog.json: %w", err)
 	}
 
@@ -187,51 +208,49 @@ func syncCatalogViaHTTP(options SyncOptions) error {
 		return fmt.Errorf("error parsing catalog.json: %w", err)
 	}
 
-	// Download all previews and manifests for themes
-	for _, themeInfo := range catalog.Themes {
-		// Download preview
-		if themeInfo.PreviewPath != "" {
-			previewURL := fmt.Sprintf("%s/%s", baseURL, themeInfo.PreviewPath)
-			localPreviewPath := filepath.Join(options.LocalDirPath, themeInfo.PreviewPath)
-			if err := downloadFile(previewURL, localPreviewPath); err != nil {
-				logging.LogDebug("Warning: Error downloading preview %s: %v", previewURL, err)
-			}
+	// Only previews and manifests that changed since the last sync are downloaded when
+	// the catalog publishes an index; otherwise everything is
+	index, err := fetchCatalogIndex(ctx, baseURL, options.LocalDirPath)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
 		}
+		logging.LogDebug("No catalog index, downloading every preview and manifest: %v", err)
+	}
+	synced := loadSyncedCatalogIndex(options.LocalDirPath)
+	defer saveSyncedCatalogIndex(options.LocalDirPath, synced)
 
-		// Download manifest
-		if themeInfo.ManifestPath != "" {
-			manifestURL := fmt.Sprintf("%s/%s", baseURL, themeInfo.ManifestPath)
-			localManifestPath := filepath.Join(options.LocalDirPath, themeInfo.ManifestPath)
-			if err := downloadFile(manifestURL, localManifestPath); err != nil {
-				logging.LogDebug("Warning: Error downloading manifest %s: %v", manifestURL, err)
-			}
+	downloaded, skipped := 0, 0
+	for _, relPath := range catalogFilePaths(catalog) {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
-	}
 
-	// Download all previews and manifests for components
-	for compType, items := range catalog.Components {
-		logging.LogDebug("Processing component type: %s", compType)
-		for _, compInfo := range items {
-			// Download preview
-			if compInfo.PreviewPath != "" {
-				previewURL := fmt.Sprintf("%s/%s", baseURL, compInfo.PreviewPath)
-				localPreviewPath := filepath.Join(options.LocalDirPath, compInfo.PreviewPath)
-				if err := downloadFile(previewURL, localPreviewPath); err != nil {
-					logging.LogDebug("Warning: Error downloading preview %s: %v", previewURL, err)
-				}
+		localPath := filepath.Join(options.LocalDirPath, relPath)
+		version := index.version(relPath)
+		if version != "" && synced.Files[relPath] == version {
+			if _, err := os.Stat(localPath); err == nil {
+				skipped++
+				continue
 			}
+		}
 
-			// Download manifest
-			if compInfo.ManifestPath != "" {
-				manifestURL := fmt.Sprintf("%s/%s", baseURL, compInfo.ManifestPath)
-				localManifestPath := filepath.Join(options.LocalDirPath, compInfo.ManifestPath)
-				if err := downloadFile(manifestURL, localManifestPath); err != nil {
-					logging.LogDebug("Warning: Error downloading manifest %s: %v", manifestURL, err)
-				}
-			}
+		fileURL := fmt.Sprintf("%s/%s", baseURL, relPath)
+		if err := downloadFileContext(ctx, fileURL, localPath); err != nil {
+			logging.LogDebug("Warning: Error downloading %s: %v", fileURL, err)
+			delete(synced.Files, relPath)
+			continue
+		}
+		downloaded++
+
+		if version != "" {
+			synced.Files[relPath] = version
+		} else {
+			delete(synced.Files, relPath)
 		}
 	}
 
+	logging.LogDebug("Catalog files downloaded: %d, unchanged: %d", downloaded, skipped)
 	return nil
 }
 
@@ -239,6 +258,11 @@ func syncCatalogViaHTTP(options SyncOptions) error {
 // Increases timeout and adds better error handling
 
 func downloadFile(url string, localPath string) error {
+	return downloadFileContext(context.Background(), url, localPath)
+}
+
+// downloadFileContext downloads url to localPath, aborting the transfer once ctx is done
+func downloadFileContext(ctx context.Context, url string, localPath string) error {
 	// Create the directory structure for the file
 	dir := filepath.Dir(localPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -251,7 +275,11 @@ func downloadFile(url string, localPath string) error {
 	}
 
 	// Download the file
-	resp, err := client.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("download error: %w", err)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		// Provide more specific error message for timeout
 		if strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "deadline") {
@@ -274,7 +302,7 @@ func downloadFile(url string, localPath string) error {
 	defer out.Close()
 
 	// Copy the content
-	_, err = io.Copy(out, resp.Body)
+	_, err = streamCopy(out, resp.Body)
 
 	if err != nil {
 		// Clean up partial downloads on error
@@ -302,8 +330,23 @@ func parseCatalogJSON(path string) (*CatalogData, error) {
 	return &catalog, nil
 }
 
+// LoadCatalog reads the synced catalog.json from the Catalog directory
+func LoadCatalog() (*CatalogData, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	catalog, err := parseCatalogJSON(filepath.Join(cwd, "Catalog", "catalog.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing catalog.json: %w", err)
+	}
+
+	return catalog, nil
+}
+
 // syncCatalogViaGit syncs the theme catalog using Git
-func syncCatalogViaGit(options SyncOptions) error {
+func syncCatalogViaGit(ctx context.Context, options SyncOptions) error {
 	logging.LogDebug("Syncing theme catalog via Git from %s", options.RepoURL)
 
 	repoPath := filepath.Join(options.LocalDirPath, ".git")
@@ -312,7 +355,7 @@ func syncCatalogViaGit(options SyncOptions) error {
 	// Check if repo already exists
 	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
 		// Clone the repository
-		r, _ = git.PlainClone(options.LocalDirPath, false, &git.CloneOptions{
+		r, _ = git.PlainCloneContext(ctx, options.LocalDirPath, false, &git.CloneOptions{
 			URL:           options.RepoURL,
 			Progress:      nil,
 			ReferenceName: plumbing.NewBranchReferenceName(options.Branch),
@@ -333,7 +376,7 @@ func syncCatalogViaGit(options SyncOptions) error {
 			return fmt.Errorf("error getting worktree: %w", err)
 		}
 
-		err = w.Pull(&git.PullOptions{
+		err = w.PullContext(ctx, &git.PullOptions{
 			RemoteName:    "origin",
 			ReferenceName: plumbing.NewBranchReferenceName(options.Branch),
 			SingleBranch:  true,
@@ -350,7 +393,19 @@ func syncCatalogViaGit(options SyncOptions) error {
 
 // DownloadThemePackage downloads a specific theme package from the repository
 func DownloadThemePackage(themeName string) error {
-	logging.LogDebug("Downloading theme package: %s", themeName)
+	return DownloadThemePackageAs(themeName, themeName, false)
+}
+
+// DownloadThemePackageAs downloads a catalog theme into Themes/localName. An existing
+// theme with that name is skipped, or moved to the trash when overwrite is set; the
+// download never merges into it.
+func DownloadThemePackageAs(themeName, localName string, overwrite bool) (err error) {
+	defer func() { recordOperation("Downloaded theme", localName, err) }()
+	logging.LogDebug("Downloading theme package: %s as %s (overwrite: %v)", themeName, localName, overwrite)
+
+	if err := CheckBatteryForOperation("downloading a theme"); err != nil {
+		return err
+	}
 
 	// Get current directory
 	cwd, err := os.Getwd()
@@ -359,10 +414,12 @@ func DownloadThemePackage(themeName string) error {
 	}
 
 	// Check if the theme already exists locally
-	localThemePath := filepath.Join(cwd, "Themes", themeName)
-	if _, err := os.Stat(localThemePath); err == nil {
-		logging.LogDebug("Theme '%s' already exists locally, skipping download", themeName)
-		return nil
+	localThemePath := filepath.Join(cwd, "Themes", localName)
+	for _, path := range []string{localThemePath, localThemePath + ".zip"} {
+		if _, err := os.Stat(path); err == nil && !overwrite {
+			logging.LogDebug("Theme '%s' already exists locally, skipping download", localName)
+			return nil
+		}
 	}
 
 	// Path to catalog.json
@@ -394,11 +451,27 @@ func DownloadThemePackage(themeName string) error {
 		return fmt.Errorf("error creating cache directory: %w", err)
 	}
 
+	// Updating an installed version the catalog has a patch for only needs the changed files
+	if overwrite {
+		if patch, ok := catalogPatchFor(themeInfo, localThemePath); ok {
+			err := downloadThemePatch(patch, localThemePath, cacheDir)
+			if err == nil {
+				message := fmt.Sprintf("Theme '%s' updated successfully!", localName)
+				if added := deconstructDownloadedTheme(localThemePath, localName); added > 0 {
+					message = fmt.Sprintf("Theme '%s' updated, %d new component packages added!", localName, added)
+				}
+				ui.ShowMessage(message, "2")
+				return nil
+			}
+			logging.LogDebug("Warning: Patch update failed, downloading the full theme: %v", err)
+		}
+	}
+
 	// Create a temporary file for the ZIP
 	zipPath := filepath.Join(cacheDir, fmt.Sprintf("%s.zip", themeName))
 
 	// Download the ZIP file
-	if err := downloadFile(themeInfo.URL, zipPath); err != nil {
+	if err := downloadPackage(themeInfo.URL, zipPath, themeInfo.SHA256); err != nil {
 		return fmt.Errorf("error downloading theme ZIP: %w", err)
 	}
 
@@ -408,8 +481,11 @@ func DownloadThemePackage(themeName string) error {
 		return fmt.Errorf("error creating Themes directory: %w", err)
 	}
 
-	// Extract the ZIP file
-	if err := extractZipFile(zipPath, localThemePath); err != nil {
+	// Extract next to the cache first so a failed download leaves any local theme untouched
+	stagingPath := filepath.Join(cacheDir, localName)
+	os.RemoveAll(stagingPath)
+	if err := extractZipFile(zipPath, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
 		return fmt.Errorf("error extracting theme ZIP: %w", err)
 	}
 
@@ -418,12 +494,46 @@ func DownloadThemePackage(themeName string) error {
 		logging.LogDebug("Warning: Failed to remove temporary ZIP file: %v", err)
 	}
 
-	ui.ShowMessage(fmt.Sprintf("Theme '%s' downloaded successfully!", themeName), "2")
+	// Deconstruct while the theme is still a folder
+	added := deconstructDownloadedTheme(stagingPath, localName)
+
+	// Zipped downloads are installed as a single .theme.zip
+	stagedPath, installPath := stagingPath, localThemePath
+	if GetZipThemesSetting() {
+		archivePath, err := archiveThemeFolder(stagingPath, &Logger{DebugFn: logging.LogDebug})
+		if err != nil {
+			os.RemoveAll(stagingPath)
+			return fmt.Errorf("error zipping theme: %w", err)
+		}
+		stagedPath, installPath = archivePath, localThemePath+".zip"
+	}
+
+	// The replaced theme, zipped or not, is kept as a previous version
+	for _, path := range []string{localThemePath, localThemePath + ".zip"} {
+		if _, err := os.Stat(path); err == nil {
+			beginTrashBatch()
+			if err := archiveInstalledPackage(path, &Logger{DebugFn: logging.LogDebug}); err != nil {
+				os.RemoveAll(stagedPath)
+				return fmt.Errorf("error replacing local theme: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(stagedPath, installPath); err != nil {
+		return fmt.Errorf("error installing theme: %w", err)
+	}
+
+	message := fmt.Sprintf("Theme '%s' downloaded successfully!", localName)
+	if added > 0 {
+		message = fmt.Sprintf("Theme '%s' downloaded, %d component packages added!", localName, added)
+	}
+	ui.ShowMessage(message, "2")
 	return nil
 }
 
 // DownloadComponentPackage downloads a specific component package from the repository
-func DownloadComponentPackage(componentType, componentName string) error {
+func DownloadComponentPackage(componentType, componentName string) (err error) {
+	defer func() { recordOperation("Downloaded component", componentName, err) }()
 	logging.LogDebug("Downloading component package: %s - %s", componentType, componentName)
 
 	// Get current directory
@@ -450,12 +560,15 @@ func DownloadComponentPackage(componentType, componentName string) error {
 
 	// Map component type to catalog key
 	componentTypeMap := map[string]string{
-		"Wallpapers": "wallpapers",
-		"Icons":      "icons",
-		"Accents":    "accents",
-		"LEDs":       "leds",
-		"Fonts":      "fonts",
-		"Overlays":   "overlays",
+		"Wallpapers":  "wallpapers",
+		"Icons":       "icons",
+		"Accents":     "accents",
+		"LEDs":        "leds",
+		"Fonts":       "fonts",
+		"Overlays":    "overlays",
+		"GameArt":     "gameart",
+		"Shaders":     "shaders",
+		"Collections": "collections",
 	}
 
 	catalogType := componentTypeMap[componentType]
@@ -492,7 +605,7 @@ func DownloadComponentPackage(componentType, componentName string) error {
 	zipPath := filepath.Join(cacheDir, fmt.Sprintf("%s.zip", componentName))
 
 	// Download the ZIP file
-	if err := downloadFile(componentInfo.URL, zipPath); err != nil {
+	if err := downloadPackage(componentInfo.URL, zipPath, componentInfo.SHA256); err != nil {
 		return fmt.Errorf("error downloading component ZIP: %w", err)
 	}
 
@@ -512,6 +625,8 @@ func DownloadComponentPackage(componentType, componentName string) error {
 		logging.LogDebug("Warning: Failed to remove temporary ZIP file: %v", err)
 	}
 
+	recordProvenance(localComponentPath, Provenance{Origin: ProvenanceDownloaded, Source: componentName}, &Logger{DebugFn: logging.LogDebug})
+
 	ui.ShowMessage(fmt.Sprintf("%s component '%s' downloaded successfully!", componentType, componentName), "2")
 	return nil
 }
@@ -641,7 +756,7 @@ func extractZipFile(zipPath, destDir string) error {
 		}
 
 		// Copy the content
-		_, err = io.Copy(outFile, rc)
+		_, err = streamCopy(outFile, rc)
 		outFile.Close()
 		rc.Close()
 		if err != nil {
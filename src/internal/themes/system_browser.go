@@ -0,0 +1,93 @@
+// src/internal/themes/system_browser.go
+// Lists the systems detected on the device with the themed assets each one has
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/system"
+)
+
+// SystemStatus is a detected ROM system and which of its themed assets are present
+type SystemStatus struct {
+	System         system.SystemInfo
+	IconPath       string // Where the system's icon is read from, Roms/.media/<folder>.png
+	Icon           bool
+	Background     bool
+	ListBackground bool
+	Excluded       bool // The system is excluded from theming
+	SharedTag      bool // Another ROM folder has the same tag, so tag-based files only reach the first
+}
+
+// fileExists reports whether a regular file exists at path
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// ListSystemStatus returns every ROM system detected on the device, in folder order, with the
+// icon, wallpaper and list wallpaper it currently has
+func ListSystemStatus() ([]SystemStatus, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	excluded := loadExcludedSystems()
+	tagCount := make(map[string]int) // Per storage root, since each card has its own folders
+	for _, sys := range systemPaths.Systems {
+		if sys.Tag != "" {
+			tagCount[sys.Root+"|"+system.NameKey(sys.Tag)]++
+		}
+	}
+
+	statuses := make([]SystemStatus, 0, len(systemPaths.Systems))
+	for _, sys := range systemPaths.Systems {
+		statuses = append(statuses, SystemStatus{
+			System:         sys,
+			IconPath:       sys.IconPath,
+			Icon:           fileExists(sys.IconPath),
+			Background:     fileExists(filepath.Join(sys.MediaPath, "bg.png")),
+			ListBackground: fileExists(filepath.Join(sys.MediaPath, "bglist.png")),
+			Excluded:       isTagExcluded(sys.Tag, excluded),
+			SharedTag:      sys.Tag != "" && tagCount[sys.Root+"|"+system.NameKey(sys.Tag)] > 1,
+		})
+	}
+	return statuses, nil
+}
+
+// FormatSystemStatus renders the details of a system for display
+func FormatSystemStatus(status SystemStatus) string {
+	yesNo := func(ok bool) string {
+		if ok {
+			return "yes"
+		}
+		return "no"
+	}
+
+	var b strings.Builder
+	b.WriteString(status.System.Name)
+	if status.System.Tag != "" {
+		fmt.Fprintf(&b, "\nTag: %s", status.System.Tag)
+	} else {
+		b.WriteString("\nTag: none, files for a system tag can't reach it")
+	}
+	if len(system.StorageRoots()) > 0 {
+		fmt.Fprintf(&b, "\nStorage: %s", status.System.Root)
+	}
+	fmt.Fprintf(&b, "\nMedia: %s", status.System.MediaPath)
+	fmt.Fprintf(&b, "\nIcon: %s (%s)", yesNo(status.Icon), status.IconPath)
+	fmt.Fprintf(&b, "\nWallpaper: %s", yesNo(status.Background))
+	fmt.Fprintf(&b, "\nList wallpaper: %s", yesNo(status.ListBackground))
+	if status.Excluded {
+		b.WriteString("\nExcluded from theming")
+	}
+	if status.SharedTag {
+		b.WriteString("\nAnother folder has the same tag")
+	}
+	return b.String()
+}
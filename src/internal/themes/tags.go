@@ -0,0 +1,189 @@
+// src/internal/themes/tags.go
+// Tag helpers for browsing and editing theme categories
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// DefaultThemeTags are the suggested tags offered when editing a theme
+var DefaultThemeTags = []string{
+	"dark",
+	"light",
+	"retro",
+	"minimal",
+	"amoled",
+	"colorful",
+}
+
+// NormalizeTag lowercases and trims a tag so tags compare consistently
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes a tag list, dropping blanks and duplicates
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+
+	for _, tag := range tags {
+		normalized := NormalizeTag(tag)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+
+	return result
+}
+
+// HasTag reports whether a tag list contains the given tag
+func HasTag(tags []string, tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range tags {
+		if NormalizeTag(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// GetInstalledThemeTags returns the tags of every installed theme, keyed by theme name
+func GetInstalledThemeTags() map[string][]string {
+	result := make(map[string][]string)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return result
+	}
+
+	themesDir := filepath.Join(cwd, "Themes")
+	entries, err := os.ReadDir(themesDir)
+	if err != nil {
+		return result
+	}
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".theme") {
+			continue
+		}
+
+		manifest, err := ValidateTheme(filepath.Join(themesDir, entry.Name()), logger)
+		if err != nil {
+			continue
+		}
+
+		result[entry.Name()] = NormalizeTags(manifest.ThemeInfo.Tags)
+	}
+
+	return result
+}
+
+// CollectThemeTags returns every tag used by installed themes and the catalog, sorted
+func CollectThemeTags(catalog *CatalogData) []string {
+	seen := make(map[string]bool)
+
+	for _, tags := range GetInstalledThemeTags() {
+		for _, tag := range tags {
+			seen[tag] = true
+		}
+	}
+
+	if catalog != nil {
+		for _, info := range catalog.Themes {
+			for _, tag := range NormalizeTags(info.Tags) {
+				seen[tag] = true
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// SetThemeTags replaces the tags of an installed theme and saves its manifest
+func SetThemeTags(themeName string, tags []string) error {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	themePath := filepath.Join(cwd, "Themes", themeName)
+	manifest, err := ValidateTheme(themePath, logger)
+	if err != nil {
+		return fmt.Errorf("error loading theme manifest: %w", err)
+	}
+
+	manifest.ThemeInfo.Tags = NormalizeTags(tags)
+	logger.DebugFn("Setting tags for theme %s: %v", themeName, manifest.ThemeInfo.Tags)
+
+	return saveManifest(themePath, manifest, logger)
+}
+
+// GetThemeTags returns the tags of a single installed theme
+func GetThemeTags(themeName string) []string {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+
+	manifest, err := ValidateTheme(filepath.Join(cwd, "Themes", themeName), logger)
+	if err != nil {
+		return nil
+	}
+
+	return NormalizeTags(manifest.ThemeInfo.Tags)
+}
+
+// ToggleThemeTag adds the tag to a theme if missing, or removes it if present
+func ToggleThemeTag(themeName string, tag string) error {
+	tags := GetThemeTags(themeName)
+	tag = NormalizeTag(tag)
+
+	var updated []string
+	if HasTag(tags, tag) {
+		for _, t := range tags {
+			if t != tag {
+				updated = append(updated, t)
+			}
+		}
+	} else {
+		updated = append(tags, tag)
+	}
+
+	return SetThemeTags(themeName, updated)
+}
+
+// IsLocallyAuthoredTheme reports whether an installed theme did not come from the catalog
+func IsLocallyAuthoredTheme(themeName string, catalog *CatalogData) bool {
+	if catalog == nil {
+		return true
+	}
+	_, fromCatalog := catalog.Themes[themeName]
+	return !fromCatalog
+}
@@ -0,0 +1,289 @@
+// src/internal/themes/theme_archive.go
+// Reads and writes themes packaged as a single .theme.zip archive instead of a folder
+
+package themes
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// ThemeArchiveExt is the extension of a zipped theme, e.g. "Retro.theme.zip"
+const ThemeArchiveExt = ".theme.zip"
+
+// IsThemeArchive reports whether a file name is a zipped theme
+func IsThemeArchive(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ThemeArchiveExt)
+}
+
+// GetZipThemesSetting reports whether exports and downloads are kept as .theme.zip archives
+func GetZipThemesSetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load zip themes setting: %v", err)
+		return false
+	}
+	return config.ZipThemes
+}
+
+// SetZipThemesSetting stores whether exports and downloads are kept as .theme.zip archives
+func SetZipThemesSetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.ZipThemes = enabled
+	return SaveConfig(config)
+}
+
+// CreateThemeArchive zips a theme folder into a .theme.zip next to it and returns the
+// archive's path. The folder is left in place. The archive is written under a temporary
+// name first, so an interrupted write never replaces a good archive.
+func CreateThemeArchive(themePath string) (string, error) {
+	archivePath := themePath + ".zip"
+	tempPath := filepath.Join(filepath.Dir(themePath), "."+filepath.Base(archivePath)+".tmp")
+
+	file, err := os.Create(tempPath)
+	if err != nil {
+		return "", fmt.Errorf("error creating archive: %w", err)
+	}
+
+	archive := zip.NewWriter(file)
+	err = addPackageToZip(archive, themePath)
+	if err == nil {
+		err = archive.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("error writing archive: %w", err)
+	}
+
+	if err := os.Rename(tempPath, archivePath); err != nil {
+		os.Remove(tempPath)
+		return "", fmt.Errorf("error naming archive: %w", err)
+	}
+	return archivePath, nil
+}
+
+// archiveThemeFolder replaces a theme folder with a .theme.zip of it and returns the
+// archive's path
+func archiveThemeFolder(themePath string, logger *Logger) (string, error) {
+	archivePath, err := CreateThemeArchive(themePath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.RemoveAll(themePath); err != nil {
+		logger.DebugFn("Warning: Could not remove %s after zipping it: %v", themePath, err)
+	}
+	logger.DebugFn("Zipped %s into %s", filepath.Base(themePath), filepath.Base(archivePath))
+	return archivePath, nil
+}
+
+// unzipPreviousExport turns a zipped previous export back into a folder, so a re-export
+// compares against it and archives it like any other
+func unzipPreviousExport(exportPath string, logger *Logger) {
+	archivePath := exportPath + ".zip"
+	if _, err := os.Stat(exportPath); err == nil {
+		return
+	}
+	if _, err := os.Stat(archivePath); err != nil {
+		return
+	}
+
+	if err := extractZipFile(archivePath, exportPath); err != nil {
+		os.RemoveAll(exportPath)
+		logger.DebugFn("Warning: Could not unpack previous export %s: %v", archivePath, err)
+		return
+	}
+	if err := os.Remove(archivePath); err != nil {
+		logger.DebugFn("Warning: Could not remove %s: %v", archivePath, err)
+	}
+}
+
+// unpackThemeArchive extracts a .theme.zip into a temporary folder under .cache and returns
+// the theme folder, named like the archive without .zip, and a cleanup that removes it
+func unpackThemeArchive(archivePath string, logger *Logger) (string, func(), error) {
+	noop := func() {}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", noop, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	cacheDir := filepath.Join(cwd, ".cache")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", noop, fmt.Errorf("error creating cache directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(cacheDir, "unpacked-")
+	if err != nil {
+		return "", noop, fmt.Errorf("error creating temporary folder: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	// Named like the theme, so a single root folder in the archive is stripped
+	themePath := filepath.Join(tempDir, strings.TrimSuffix(filepath.Base(archivePath), ".zip"))
+	if err := extractZipFile(archivePath, themePath); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("error unpacking %s: %w", filepath.Base(archivePath), err)
+	}
+
+	logger.DebugFn("Unpacked %s to %s", filepath.Base(archivePath), themePath)
+	return themePath, cleanup, nil
+}
+
+// UnpackThemeArchive extracts an installed .theme.zip into a theme folder next to it and
+// sends the archive to the trash. Returns the new folder's name.
+func UnpackThemeArchive(archivePath string) (name string, err error) {
+	defer func() { recordOperation("Unpacked theme", filepath.Base(archivePath), err) }()
+
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckStorageWritable(); err != nil {
+		return "", err
+	}
+
+	themePath := strings.TrimSuffix(archivePath, ".zip")
+	name = filepath.Base(themePath)
+	if _, err := os.Stat(themePath); err == nil {
+		return "", fmt.Errorf("%s is already installed as a folder", name)
+	}
+
+	unpacked, cleanup, err := unpackThemeArchive(archivePath, logger)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	if _, err := ValidateTheme(unpacked, logger); err != nil {
+		return "", fmt.Errorf("%s is not a valid theme: %w", filepath.Base(archivePath), err)
+	}
+
+	if err := movePackage(unpacked, themePath); err != nil {
+		return "", err
+	}
+
+	beginTrashBatch()
+	if err := moveToTrash(archivePath); err != nil {
+		logger.DebugFn("Warning: Could not remove %s: %v", archivePath, err)
+	}
+	return name, nil
+}
+
+// readThemeArchiveFile returns a file at the top of the theme inside an archive, e.g.
+// "preview.png", whether or not the archive wraps the theme in a root folder
+func readThemeArchiveFile(archivePath, name string) ([]byte, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening archive: %w", err)
+	}
+	defer reader.Close()
+
+	for _, file := range reader.File {
+		parts := strings.Split(strings.TrimPrefix(file.Name, "/"), "/")
+		if parts[len(parts)-1] != name || len(parts) > 2 || strings.Contains(file.Name, "__MACOSX") {
+			continue
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer src.Close()
+		return io.ReadAll(src)
+	}
+	return nil, os.ErrNotExist
+}
+
+// ThemeArchiveAuthor returns the author named in a zipped theme's manifest, or ""
+func ThemeArchiveAuthor(archivePath string) string {
+	data, err := readThemeArchiveFile(archivePath, ManifestJSON)
+	for _, name := range []string{ManifestYAML, manifestYML} {
+		if err == nil {
+			break
+		}
+		if data, err = readThemeArchiveFile(archivePath, name); err == nil {
+			data, err = manifestYAMLToJSON(data)
+		}
+	}
+	if err != nil {
+		return ""
+	}
+
+	var manifest ThemeManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ""
+	}
+	return manifest.ThemeInfo.Author
+}
+
+// ThemeArchivePreview extracts a zipped theme's preview into .cache so galleries can show
+// it, and returns its path, or "" when the archive has none. The extracted preview is
+// reused until the archive changes.
+func ThemeArchivePreview(archivePath string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+
+	previewPath := filepath.Join(cwd, ".cache", "previews", filepath.Base(archivePath)+".png")
+	archiveInfo, err := os.Stat(archivePath)
+	if err != nil {
+		return ""
+	}
+	if info, err := os.Stat(previewPath); err == nil && !info.ModTime().Before(archiveInfo.ModTime()) {
+		return previewPath
+	}
+
+	data, err := readThemeArchiveFile(archivePath, "preview.png")
+	if err != nil {
+		return ""
+	}
+	if err := os.MkdirAll(filepath.Dir(previewPath), 0755); err != nil {
+		logging.LogDebug("Warning: Could not create preview cache: %v", err)
+		return ""
+	}
+	if err := os.WriteFile(previewPath, data, 0644); err != nil {
+		logging.LogDebug("Warning: Could not extract preview of %s: %v", archivePath, err)
+		return ""
+	}
+	return previewPath
+}
+
+// unzipThemeArchives extracts every .theme.zip in a folder into a theme folder next to it,
+// so bulk imports pick the themes up like any other. Extracted archives are removed.
+func unzipThemeArchives(folder string, result *BulkImportResult, logger *Logger) {
+	archives, _ := filepath.Glob(filepath.Join(folder, "*"+ThemeArchiveExt))
+	for _, archivePath := range archives {
+		name := filepath.Base(archivePath)
+		themePath := strings.TrimSuffix(archivePath, ".zip")
+		if _, err := os.Stat(themePath); err == nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %s is in the way", name, filepath.Base(themePath)))
+			continue
+		}
+
+		if err := extractZipFile(archivePath, themePath); err != nil {
+			os.RemoveAll(themePath)
+			logger.DebugFn("Warning: Could not unpack %s: %v", name, err)
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: could not be unpacked", name))
+			continue
+		}
+		if err := os.Remove(archivePath); err != nil {
+			logger.DebugFn("Warning: Could not remove %s: %v", archivePath, err)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+// src/internal/themes/trash.go
+// Recoverable deletes: cleanups move files into a .trash directory inside the pak
+
+package themes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+const (
+	// trashMaxBytes caps the total size of the trash, oldest batches go first
+	trashMaxBytes = 64 * 1024 * 1024
+
+	// trashMaxAge is how long a batch is kept before it expires
+	trashMaxAge = 14 * 24 * time.Hour
+
+	// trashBatchFormat names one batch directory per apply
+	trashBatchFormat = "20060102-150405"
+
+	// trashManagedList lists the files of a batch that were in the managed ledger, so a
+	// restore hands them back to the manager
+	trashManagedList = ".managed.json"
+)
+
+// trashBatch is the batch directory name for the apply in progress
+var trashBatch string
+
+// getTrashDir returns the .trash directory inside the pak
+func getTrashDir() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, ".trash"), nil
+}
+
+// beginTrashBatch starts a new trash batch for the apply in progress
+func beginTrashBatch() {
+	trashBatch = time.Now().Format(trashBatchFormat)
+}
+
+// moveToTrash moves a file into the current trash batch, keeping its original path
+// so it can be restored by copying it back
+func moveToTrash(path string) error {
+	if trashBatch == "" {
+		beginTrashBatch()
+	}
+
+	trashDir, err := getTrashDir()
+	if err != nil {
+		return err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("error resolving path: %w", err)
+	}
+
+	loadManagedLedger()
+	managed := managedLedger.paths[filepath.Clean(absPath)]
+
+	trashPath := filepath.Join(trashDir, trashBatch, absPath)
+	if err := os.MkdirAll(filepath.Dir(trashPath), 0755); err != nil {
+		return fmt.Errorf("error creating trash directory: %w", err)
+	}
+
+	// Rename is instant when the pak lives on the same card, fall back to copying otherwise
+	if err := os.Rename(absPath, trashPath); err != nil {
+		if err := CopyFile(absPath, trashPath); err != nil {
+			return fmt.Errorf("error moving file to trash: %w", err)
+		}
+		if err := os.Remove(absPath); err != nil {
+			return fmt.Errorf("error removing trashed file: %w", err)
+		}
+	}
+
+	if managed {
+		batchDir := filepath.Join(trashDir, trashBatch)
+		if err := writeTrashManagedList(batchDir, append(readTrashManagedList(batchDir), absPath)); err != nil {
+			logging.LogDebug("Warning: Could not note managed file in trash: %v", err)
+		}
+	}
+
+	logging.LogDebug("Moved to trash: %s -> %s", absPath, trashPath)
+	return nil
+}
+
+// readTrashManagedList returns the managed files noted in a batch
+func readTrashManagedList(batchDir string) []string {
+	data, err := os.ReadFile(filepath.Join(batchDir, trashManagedList))
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		logging.LogDebug("Warning: Could not parse managed files of %s: %v", batchDir, err)
+		return nil
+	}
+	return paths
+}
+
+// writeTrashManagedList saves the managed files noted in a batch
+func writeTrashManagedList(batchDir string, paths []string) error {
+	data, err := json.MarshalIndent(paths, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(batchDir, trashManagedList), data, 0644)
+}
+
+// trashBatchInfo describes one batch directory in the trash
+type trashBatchInfo struct {
+	path    string
+	created time.Time
+	size    int64
+}
+
+// listTrashBatches returns the trash batches sorted from oldest to newest
+func listTrashBatches(trashDir string) ([]trashBatchInfo, error) {
+	entries, err := os.ReadDir(trashDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading trash directory: %w", err)
+	}
+
+	var batches []trashBatchInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		batch := trashBatchInfo{path: filepath.Join(trashDir, entry.Name())}
+
+		created, err := time.ParseInLocation(trashBatchFormat, entry.Name(), time.Local)
+		if err != nil {
+			info, infoErr := entry.Info()
+			if infoErr != nil {
+				continue
+			}
+			created = info.ModTime()
+		}
+		batch.created = created
+
+		filepath.Walk(batch.path, func(_ string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				batch.size += info.Size()
+			}
+			return nil
+		})
+
+		batches = append(batches, batch)
+	}
+
+	sort.Slice(batches, func(i, j int) bool {
+		return batches[i].created.Before(batches[j].created)
+	})
+
+	return batches, nil
+}
+
+// pruneTrash removes expired batches and then the oldest batches until the trash fits its size cap
+func pruneTrash(logger *Logger) {
+	trashDir, err := getTrashDir()
+	if err != nil {
+		logger.DebugFn("Warning: Could not locate trash: %v", err)
+		return
+	}
+
+	batches, err := listTrashBatches(trashDir)
+	if err != nil {
+		logger.DebugFn("Warning: Could not list trash: %v", err)
+		return
+	}
+
+	var total int64
+	for _, batch := range batches {
+		total += batch.size
+	}
+
+	cutoff := time.Now().Add(-trashMaxAge)
+	for _, batch := range batches {
+		if !batch.created.Before(cutoff) && total <= trashMaxBytes {
+			continue
+		}
+
+		// Never drop the batch from the apply that just ran
+		if filepath.Base(batch.path) == trashBatch {
+			continue
+		}
+
+		if err := os.RemoveAll(batch.path); err != nil {
+			logger.DebugFn("Warning: Could not purge trash batch %s: %v", batch.path, err)
+			continue
+		}
+
+		total -= batch.size
+		logger.DebugFn("Purged trash batch: %s (%d bytes)", batch.path, batch.size)
+	}
+}
+
+// EmptyTrash removes every batch in the trash and returns how many there were. The files in
+// it can't be restored afterwards.
+func EmptyTrash() (count int, err error) {
+	defer func() { recordOperation("Emptied trash", "", err) }()
+
+	if err := CheckKioskMode("emptying the trash"); err != nil {
+		return 0, err
+	}
+
+	trashDir, err := getTrashDir()
+	if err != nil {
+		return 0, err
+	}
+
+	batches, err := listTrashBatches(trashDir)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, batch := range batches {
+		if err := os.RemoveAll(batch.path); err != nil {
+			return count, fmt.Errorf("error emptying trash: %w", err)
+		}
+		count++
+	}
+
+	logging.LogDebug("Emptied trash: %d batches", count)
+	return count, nil
+}
+
+// TrashBatch is one apply's worth of removed files, as listed for restoring
+type TrashBatch struct {
+	Name    string    // Batch directory name, passed to RestoreTrashBatch
+	Created time.Time // When the files were removed
+	Files   int       // Number of files in the batch
+	Size    int64     // Total size of the files in bytes
+}
+
+// ListTrash returns the batches in the trash, newest first
+func ListTrash() ([]TrashBatch, error) {
+	trashDir, err := getTrashDir()
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := listTrashBatches(trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []TrashBatch
+	for i := len(batches) - 1; i >= 0; i-- {
+		files := len(trashedFiles(batches[i].path))
+		if files == 0 {
+			continue
+		}
+		list = append(list, TrashBatch{
+			Name:    filepath.Base(batches[i].path),
+			Created: batches[i].created,
+			Files:   files,
+			Size:    batches[i].size,
+		})
+	}
+	return list, nil
+}
+
+// trashedFiles returns the files of a batch mapped to the path they were removed from
+func trashedFiles(batchDir string) map[string]string {
+	files := make(map[string]string)
+	filepath.Walk(batchDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(batchDir, path)
+		if err != nil || rel == trashManagedList {
+			return nil
+		}
+		files[path] = string(filepath.Separator) + rel
+		return nil
+	})
+	return files
+}
+
+// RestoreTrashBatch moves the files of a trash batch back to where they were removed from.
+// Files that were managed get their ledger entry back. Files whose original path is taken
+// again are left in the trash and counted as skipped.
+func RestoreTrashBatch(name string) (restored int, skipped int, err error) {
+	defer func() { recordOperation("Restored trash", name, err) }()
+
+	if err := CheckKioskMode("restoring from the trash"); err != nil {
+		return 0, 0, err
+	}
+
+	trashDir, err := getTrashDir()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return 0, 0, fmt.Errorf("invalid trash batch: %q", name)
+	}
+	batchDir := filepath.Join(trashDir, name)
+	if _, err := os.Stat(batchDir); err != nil {
+		return 0, 0, fmt.Errorf("error reading trash batch: %w", err)
+	}
+
+	managed := make(map[string]bool)
+	for _, path := range readTrashManagedList(batchDir) {
+		managed[filepath.Clean(path)] = true
+	}
+
+	// Keep the ledger entries of whatever made it back, even when a later file fails
+	defer func() {
+		if restored == 0 {
+			return
+		}
+		if err := saveManagedLedger(); err != nil {
+			logging.LogDebug("Warning: Could not save managed files ledger: %v", err)
+		}
+	}()
+
+	for trashPath, original := range trashedFiles(batchDir) {
+		if _, err := os.Lstat(original); err == nil {
+			logging.LogDebug("Warning: Not restoring %s, the path is taken again", original)
+			skipped++
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(original), 0755); err != nil {
+			return restored, skipped, fmt.Errorf("error creating directory for %s: %w", original, err)
+		}
+		if err := os.Rename(trashPath, original); err != nil {
+			if err := CopyFile(trashPath, original); err != nil {
+				return restored, skipped, fmt.Errorf("error restoring %s: %w", original, err)
+			}
+			os.Remove(trashPath)
+		}
+
+		if managed[filepath.Clean(original)] {
+			recordManagedFile(original)
+		}
+		restored++
+		logging.LogDebug("Restored from trash: %s -> %s", trashPath, original)
+	}
+
+	// Drop the batch once nothing is left in it
+	if skipped == 0 {
+		if err := os.RemoveAll(batchDir); err != nil {
+			logging.LogDebug("Warning: Could not remove restored trash batch %s: %v", batchDir, err)
+		}
+	}
+
+	logging.LogDebug("Restored trash batch %s: %d files, %d skipped", name, restored, skipped)
+	return restored, skipped, nil
+}
@@ -0,0 +1,225 @@
+// src/internal/themes/upload_server.go
+// Small web server for uploading wallpapers from a phone on the same network
+
+package themes
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// uploadServerPort is the port the upload page is served on
+const uploadServerPort = 8642
+
+// maxWallpaperUploadBytes caps the size of one uploaded image
+const maxWallpaperUploadBytes = 32 * 1024 * 1024
+
+// uploadTokenBytes is the length of the random token that has to be in every request. The
+// server listens on the whole network, the token keeps out everyone who didn't scan the code.
+const uploadTokenBytes = 16
+
+// uploadPage is the page a phone opens: a photo picker, the slot, and how to fit the photo
+var uploadPage = template.Must(template.New("upload").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1">
+<title>NextUI Wallpaper Upload</title>
+<style>body{font-family:sans-serif;margin:1.5em;max-width:32em}label,select,input,button{display:block;margin:.6em 0;font-size:1.1em}.msg{padding:.6em;background:#eee}</style>
+</head><body>
+<h1>Wallpaper Upload</h1>
+{{if .Message}}<p class="msg">{{.Message}}</p>{{end}}
+<form method="post" action="upload" enctype="multipart/form-data">
+<label>Photo <input type="file" name="image" accept="image/jpeg,image/png,image/gif" required></label>
+<label>Install to <select name="slot">{{range .Slots}}<option{{if eq . $.Slot}} selected{{end}}>{{.}}</option>{{end}}</select></label>
+<label><input type="checkbox" name="list" value="1"{{if .List}} checked{{end}}> List wallpaper (systems only)</label>
+<label>Fit <select name="mode">{{range .Modes}}<option value="{{.Value}}"{{if eq .Value $.Mode}} selected{{end}}>{{.Label}}</option>{{end}}</select></label>
+<button type="submit">Upload</button>
+</form>
+</body></html>
+`))
+
+// uploadFitModes describes the fit modes on the upload page
+var uploadFitModes = []struct{ Value, Label string }{
+	{WallpaperFitCrop, "Crop to fill the screen"},
+	{WallpaperFitFit, "Fit the whole photo, black bars"},
+	{WallpaperFitCenter, "Center at original size"},
+}
+
+// uploadPageData fills the upload page; the last choices are kept for the next photo
+type uploadPageData struct {
+	Message string
+	Slots   []string
+	Slot    string
+	Mode    string
+	List    bool
+	Modes   []struct{ Value, Label string }
+}
+
+// WallpaperUploadServer serves the upload page until it is stopped
+type WallpaperUploadServer struct {
+	URL    string // Address to open on the phone
+	server *http.Server
+}
+
+// localIPv4 returns the device's address on the local network
+func localIPv4() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", fmt.Errorf("error reading network interfaces: %w", err)
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil {
+			return ip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("not connected to a network, connect to Wi-Fi first")
+}
+
+// renderUploadPage writes the upload page with a message about the last upload
+func renderUploadPage(w http.ResponseWriter, status int, data uploadPageData) {
+	slots, err := ListWallpaperSlots()
+	if err != nil {
+		logging.LogDebug("Warning: Could not list wallpaper slots: %v", err)
+	}
+	data.Slots = slots
+	data.Modes = uploadFitModes
+	if data.Mode == "" {
+		data.Mode = WallpaperFitCrop
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	if err := uploadPage.Execute(w, data); err != nil {
+		logging.LogDebug("Warning: Could not render upload page: %v", err)
+	}
+}
+
+// handleWallpaperUpload installs one uploaded photo
+func handleWallpaperUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "./", http.StatusSeeOther)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxWallpaperUploadBytes+1024*1024)
+	data := uploadPageData{
+		Slot: r.FormValue("slot"),
+		Mode: r.FormValue("mode"),
+		List: r.FormValue("list") == "1",
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		data.Message = "No photo received, or it is larger than " + strconv.Itoa(maxWallpaperUploadBytes/(1024*1024)) + " MB."
+		renderUploadPage(w, http.StatusBadRequest, data)
+		return
+	}
+	defer file.Close()
+
+	photo, err := io.ReadAll(io.LimitReader(file, maxWallpaperUploadBytes))
+	if err != nil {
+		data.Message = fmt.Sprintf("Error reading the photo: %v", err)
+		renderUploadPage(w, http.StatusBadRequest, data)
+		return
+	}
+
+	logging.LogDebug("Received wallpaper upload %s (%d bytes) for %s", header.Filename, len(photo), data.Slot)
+	if err := InstallUploadedWallpaper(photo, data.Slot, data.Mode, data.List); err != nil {
+		logging.LogDebug("Error installing uploaded wallpaper: %v", err)
+		data.Message = fmt.Sprintf("Error: %v", err)
+		renderUploadPage(w, http.StatusUnprocessableEntity, data)
+		return
+	}
+
+	data.Message = fmt.Sprintf("Installed %s as the wallpaper of %s.", header.Filename, data.Slot)
+	if data.List {
+		data.Message = fmt.Sprintf("Installed %s as the list wallpaper of %s.", header.Filename, data.Slot)
+	}
+	renderUploadPage(w, http.StatusOK, data)
+}
+
+// uploadHandler serves the upload page below /<token>/ and refuses every request without
+// the session's token
+func uploadHandler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given, page, found := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if !found || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			logging.LogDebug("Refused upload request without the session token from %s", r.RemoteAddr)
+			http.Error(w, "Scan the QR code on the device to upload wallpapers.", http.StatusForbidden)
+			return
+		}
+
+		switch page {
+		case "":
+			renderUploadPage(w, http.StatusOK, uploadPageData{})
+		case "upload":
+			handleWallpaperUpload(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// StartWallpaperUpload starts serving the upload page on the local network. Every session
+// gets a new random token, which is part of the URL and QR code.
+func StartWallpaperUpload() (*WallpaperUploadServer, error) {
+	if err := CheckKioskMode("uploading wallpapers"); err != nil {
+		return nil, err
+	}
+
+	ip, err := localIPv4()
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, uploadTokenBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("error creating upload token: %w", err)
+	}
+	token := hex.EncodeToString(secret)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", uploadServerPort))
+	if err != nil {
+		return nil, fmt.Errorf("error starting upload server: %w", err)
+	}
+
+	upload := &WallpaperUploadServer{
+		URL:    fmt.Sprintf("http://%s:%d/%s/", ip, uploadServerPort, token),
+		server: &http.Server{Handler: uploadHandler(token), ReadHeaderTimeout: 30 * time.Second},
+	}
+	go func() {
+		if err := upload.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logging.LogDebug("Upload server stopped: %v", err)
+		}
+	}()
+
+	logging.LogDebug("Wallpaper upload server listening on port %d", uploadServerPort)
+	return upload, nil
+}
+
+// Stop shuts the upload server down, letting an upload in progress finish. Uploads still
+// going after a few seconds are cut off, the port is always closed when Stop returns.
+func (s *WallpaperUploadServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		logging.LogDebug("Warning: Could not stop upload server cleanly: %v", err)
+		s.server.Close()
+	}
+	logging.LogDebug("Wallpaper upload server stopped")
+}
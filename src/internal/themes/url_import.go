@@ -0,0 +1,169 @@
+// src/internal/themes/url_import.go
+// Imports packages from direct links listed in urls.txt, without going through the catalog
+
+package themes
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// urlListName is the file in the pak directory that lists links to import, one per line
+const urlListName = "urls.txt"
+
+// importedURLPrefix marks links in urls.txt that were already downloaded
+const importedURLPrefix = "# imported: "
+
+// GetURLListPath returns the path of urls.txt in the Theme Manager directory
+func GetURLListPath() (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, urlListName), nil
+}
+
+// ReadImportURLs returns the links in urls.txt that haven't been imported yet.
+// Blank lines and lines starting with # are ignored; a missing file means no links.
+func ReadImportURLs() ([]string, error) {
+	listPath, err := GetURLListPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(listPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", urlListName, err)
+	}
+	defer file.Close()
+
+	var urls []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			urls = append(urls, line)
+		}
+	}
+
+	return urls, scanner.Err()
+}
+
+// markURLsImported comments out downloaded links in urls.txt so they aren't fetched again
+func markURLsImported(done map[string]bool) error {
+	listPath, err := GetURLListPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(listPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", urlListName, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if done[strings.TrimSpace(line)] {
+			lines[i] = importedURLPrefix + strings.TrimSpace(line)
+		}
+	}
+
+	return os.WriteFile(listPath, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// packageNameFromURL derives a package folder name from the last part of a link,
+// e.g. "Retro.theme" from ".../Retro.theme.zip"
+func packageNameFromURL(link *url.URL, index int) string {
+	name, err := url.PathUnescape(path.Base(link.Path))
+	if err != nil || name == "." || name == "/" {
+		name = ""
+	}
+	name = SafeFileName(strings.TrimSuffix(name, ".zip"))
+	if name == "" || name == "_" {
+		name = fmt.Sprintf("download_%d", index+1)
+	}
+	return name
+}
+
+// ImportFromURLs downloads each linked archive, then validates and installs the packages
+// like Import from Folder does. Links that downloaded are marked as imported in urls.txt;
+// failed ones stay so they can be retried.
+func ImportFromURLs(urls []string) (*BulkImportResult, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckBatteryForOperation("downloading packages"); err != nil {
+		return nil, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	// Every archive is extracted into a staging folder that is then imported as a whole
+	stagingDir := filepath.Join(cwd, ".cache", "url-import")
+	os.RemoveAll(stagingDir)
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating staging folder: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	var failed []string
+	downloaded := make(map[string]bool)
+
+	for i, rawURL := range urls {
+		link, err := url.Parse(rawURL)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+			logger.DebugFn("Warning: Not a web link: %s", rawURL)
+			failed = append(failed, fmt.Sprintf("%s: not an http(s) link", rawURL))
+			continue
+		}
+
+		name := packageNameFromURL(link, i)
+		zipPath := filepath.Join(cwd, ".cache", name+".zip")
+
+		logger.DebugFn("Downloading %s to %s", rawURL, zipPath)
+		if err := downloadFile(rawURL, zipPath); err != nil {
+			logger.DebugFn("Warning: Could not download %s: %v", rawURL, err)
+			failed = append(failed, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+
+		err = extractZipFile(zipPath, filepath.Join(stagingDir, name))
+		os.Remove(zipPath)
+		if err != nil {
+			logger.DebugFn("Warning: Could not extract %s: %v", rawURL, err)
+			failed = append(failed, fmt.Sprintf("%s: not a zip archive", name))
+			continue
+		}
+
+		downloaded[rawURL] = true
+	}
+
+	result, err := ImportFromFolder(stagingDir)
+	if err != nil {
+		return nil, err
+	}
+	result.Skipped = append(failed, result.Skipped...)
+
+	if len(downloaded) > 0 {
+		if err := markURLsImported(downloaded); err != nil {
+			logger.DebugFn("Warning: Could not update %s: %v", urlListName, err)
+		}
+	}
+
+	logger.DebugFn("URL import finished: %d imported, %d skipped", len(result.Imported), len(result.Skipped))
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// src/internal/themes/verify_report.go
+// Cross-checks manifest content counts against the files an apply actually wrote
+
+package themes
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Content kinds tracked by the verification report
+const (
+	reportWallpapers = "Wallpapers"
+	reportIcons      = "Icons"
+)
+
+// applyReport counts what the apply in progress wrote and intentionally skipped
+var applyReport struct {
+	written map[string]int
+	skipped map[string]int // Pinned files and excluded systems
+
+	converted []string // Animated wallpapers applied as a still frame
+	rejected  []string // Animated wallpapers left out
+
+	unsupported []string // Settings left out because the device can't use them
+}
+
+// beginApplyReport clears the counts for a new apply
+func beginApplyReport() {
+	applyReport.written = make(map[string]int)
+	applyReport.skipped = make(map[string]int)
+	applyReport.converted = nil
+	applyReport.rejected = nil
+	applyReport.unsupported = nil
+}
+
+// reportSkipped counts a file the apply deliberately left alone
+func reportSkipped(kind string) {
+	if applyReport.skipped == nil {
+		beginApplyReport()
+	}
+	applyReport.skipped[kind]++
+}
+
+// reportCopyResult counts the outcome of a copyMappedFile call
+func reportCopyResult(kind string, err error) {
+	if applyReport.written == nil {
+		beginApplyReport()
+	}
+
+	switch {
+	case err == nil:
+		applyReport.written[kind]++
+	case errors.Is(err, errPinnedFile):
+		applyReport.skipped[kind]++
+	}
+}
+
+// verifyApplyCounts compares the manifest's expected counts with what was written
+// and returns one line per discrepancy
+func verifyApplyCounts(expected map[string]int, logger *Logger) []string {
+	var discrepancies []string
+
+	for _, kind := range []string{reportWallpapers, reportIcons} {
+		want, ok := expected[kind]
+		if !ok {
+			continue
+		}
+
+		written := applyReport.written[kind]
+		skipped := applyReport.skipped[kind]
+		logger.DebugFn("Verification: %s expected %d, written %d, skipped %d", kind, want, written, skipped)
+
+		switch {
+		case written+skipped < want:
+			discrepancies = append(discrepancies,
+				fmt.Sprintf("%s: only %d of %d written", kind, written, want-skipped))
+		case written+skipped > want:
+			discrepancies = append(discrepancies,
+				fmt.Sprintf("%s: %d written but manifest lists %d", kind, written, want))
+		}
+	}
+
+	if corrupt := len(writeVerify.corrupt); corrupt > 0 {
+		discrepancies = append(discrepancies,
+			fmt.Sprintf("%d files failed read-back verification, your SD card may be failing", corrupt))
+	}
+
+	for _, line := range discrepancies {
+		logger.DebugFn("Warning: Verification discrepancy - %s", line)
+	}
+
+	return discrepancies
+}
+
+// verificationSummary returns a line for apply messages describing discrepancies
+func verificationSummary(discrepancies []string) string {
+	if len(discrepancies) == 0 {
+		return ""
+	}
+	return "\nCheck the log, some files are missing:\n" + strings.Join(discrepancies, "\n")
+}
@@ -0,0 +1,294 @@
+// src/internal/themes/versions.go
+// Keeps previous versions of installed packages when they are replaced, with rollback
+
+package themes
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// versionsRootName is the folder inside the pak holding previous versions of installed packages
+const versionsRootName = "Versions"
+
+// Keep-versions values stored in the config; positive values are a count
+const (
+	KeepVersionsDefault = 0  // Use defaultKeepVersions
+	KeepVersionsOff     = -1 // Replaced packages go to the trash instead
+)
+
+// defaultKeepVersions is how many versions are kept when the user hasn't picked a number
+const defaultKeepVersions = 3
+
+// KeepVersionsPresets are the counts offered in settings
+var KeepVersionsPresets = []int{KeepVersionsDefault, 1, 5, 10, KeepVersionsOff}
+
+// PackageVersion is one archived version of an installed package
+type PackageVersion struct {
+	ID      string    // Folder name in the versions archive, also the time it was archived
+	Version string    // Version from the package manifest, if it has one
+	Saved   time.Time // When it was replaced
+}
+
+// GetKeepVersionsSetting returns the user's keep-versions choice
+func GetKeepVersionsSetting() int {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load keep versions setting: %v", err)
+		return KeepVersionsDefault
+	}
+	return config.KeepVersions
+}
+
+// SetKeepVersionsSetting stores the user's keep-versions choice
+func SetKeepVersionsSetting(count int) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.KeepVersions = count
+	return SaveConfig(config)
+}
+
+// KeepVersionsCount resolves a keep-versions setting to a count, 0 meaning off
+func KeepVersionsCount(setting int) int {
+	switch setting {
+	case KeepVersionsDefault:
+		return defaultKeepVersions
+	case KeepVersionsOff:
+		return 0
+	default:
+		return setting
+	}
+}
+
+// packageVersionsDir returns where previous versions of a library package are kept,
+// e.g. Versions/Themes/Retro.theme for Themes/Retro.theme
+func packageVersionsDir(packagePath string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(cwd, packagePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%s is not in the library", packagePath)
+	}
+
+	return filepath.Join(cwd, versionsRootName, rel), nil
+}
+
+// archiveInstalledPackage moves an installed package that is about to be replaced into the
+// versions archive, dropping the oldest versions beyond the kept count. With versions turned
+// off the package goes to the current trash batch as before.
+func archiveInstalledPackage(packagePath string, logger *Logger) error {
+	keep := KeepVersionsCount(GetKeepVersionsSetting())
+	if keep == 0 {
+		return moveToTrash(packagePath)
+	}
+
+	versionsDir, err := packageVersionsDir(packagePath)
+	if err != nil {
+		return err
+	}
+
+	// Versions are named by the time they were replaced and always sort after older ones,
+	// even when several are archived within a second
+	archivedAt := time.Now()
+	if versions, _ := ListPackageVersions(packagePath); len(versions) > 0 && !archivedAt.After(versions[0].Saved.Add(time.Second-1)) {
+		archivedAt = versions[0].Saved.Add(time.Second)
+	}
+	id := archivedAt.Format(trashBatchFormat)
+	versionPath := filepath.Join(versionsDir, id, filepath.Base(packagePath))
+
+	if err := movePackage(packagePath, versionPath); err != nil {
+		return fmt.Errorf("error archiving %s: %w", filepath.Base(packagePath), err)
+	}
+	logger.DebugFn("Archived previous version of %s as %s", filepath.Base(packagePath), id)
+
+	versions, err := ListPackageVersions(packagePath)
+	if err != nil {
+		return nil
+	}
+	for len(versions) > keep {
+		oldest := versions[len(versions)-1]
+		if err := os.RemoveAll(filepath.Join(versionsDir, oldest.ID)); err != nil {
+			logger.DebugFn("Warning: Could not remove old version %s: %v", oldest.ID, err)
+		}
+		versions = versions[:len(versions)-1]
+	}
+
+	return nil
+}
+
+// packageVersion reads the version from a package manifest, theme or component
+func packageVersion(packagePath string) string {
+	if filepath.Ext(packagePath) == ".theme" {
+		if manifest, err := ValidateTheme(packagePath, &Logger{DebugFn: logging.LogDebug}); err == nil {
+			return manifest.ThemeInfo.Version
+		}
+		return ""
+	}
+
+	if manifestObj, err := LoadComponentManifest(packagePath); err == nil {
+		if info := GetComponentInfo(manifestObj); info != nil {
+			return info.Version
+		}
+	}
+	return ""
+}
+
+// ListPackageVersions returns the archived versions of an installed package, newest first
+func ListPackageVersions(packagePath string) ([]PackageVersion, error) {
+	versionsDir, err := packageVersionsDir(packagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(versionsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading versions: %w", err)
+	}
+
+	var versions []PackageVersion
+	for _, entry := range entries {
+		saved, err := time.ParseInLocation(trashBatchFormat, entry.Name(), time.Local)
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+
+		versions = append(versions, PackageVersion{
+			ID:      entry.Name(),
+			Version: packageVersion(filepath.Join(versionsDir, entry.Name(), filepath.Base(packagePath))),
+			Saved:   saved,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].ID > versions[j].ID
+	})
+	return versions, nil
+}
+
+// ListVersionedPackages returns the library paths, relative to the Theme Manager
+// directory, of every package with archived versions
+func ListVersionedPackages() ([]string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+	root := filepath.Join(cwd, versionsRootName)
+
+	var packages []string
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if !info.IsDir() || path == root {
+			return nil
+		}
+
+		// Version folders are named by time; their parent is the package
+		if _, err := time.Parse(trashBatchFormat, info.Name()); err == nil {
+			rel, _ := filepath.Rel(root, filepath.Dir(path))
+			if len(packages) == 0 || packages[len(packages)-1] != rel {
+				packages = append(packages, rel)
+			}
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading versions: %w", err)
+	}
+
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// RestorePackageVersion rolls an installed package back to an archived version. The
+// version being replaced is archived in turn, so a rollback can itself be undone.
+func RestorePackageVersion(packagePath, id string) (err error) {
+	defer func() { recordOperation("Rolled back", filepath.Base(packagePath)+" to "+id, err) }()
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	if err := CheckKioskMode("rolling back a package"); err != nil {
+		return err
+	}
+
+	versionsDir, err := packageVersionsDir(packagePath)
+	if err != nil {
+		return err
+	}
+
+	versionPath := filepath.Join(versionsDir, id, filepath.Base(packagePath))
+	if _, err := os.Stat(versionPath); err != nil {
+		return fmt.Errorf("version %s of %s not found", id, filepath.Base(packagePath))
+	}
+
+	// Take the version out first so archiving the current one can't prune it
+	stagingPath := filepath.Join(filepath.Dir(versionsDir), "."+filepath.Base(packagePath)+".restoring")
+	os.RemoveAll(stagingPath)
+	if err := os.Rename(versionPath, stagingPath); err != nil {
+		return fmt.Errorf("error reading version %s: %w", id, err)
+	}
+	os.RemoveAll(filepath.Join(versionsDir, id))
+
+	if _, err := os.Stat(packagePath); err == nil {
+		beginTrashBatch()
+		if err := archiveInstalledPackage(packagePath, logger); err != nil {
+			// Put the version back where it was
+			os.MkdirAll(filepath.Dir(versionPath), 0755)
+			os.Rename(stagingPath, versionPath)
+			return fmt.Errorf("error archiving current version: %w", err)
+		}
+	}
+
+	if err := movePackage(stagingPath, packagePath); err != nil {
+		return fmt.Errorf("error restoring version %s: %w", id, err)
+	}
+
+	logger.DebugFn("Restored %s to the version archived at %s", filepath.Base(packagePath), id)
+	return nil
+}
+
+// PurgePackageVersions removes the previous versions of every installed package and returns
+// how many packages had any. The installed packages themselves are left alone.
+func PurgePackageVersions() (count int, err error) {
+	defer func() { recordOperation("Purged versions", "", err) }()
+
+	if err := CheckKioskMode("purging package versions"); err != nil {
+		return 0, err
+	}
+
+	packages, err := ListVersionedPackages()
+	if err != nil {
+		return 0, err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return 0, fmt.Errorf("error getting current directory: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(cwd, versionsRootName)); err != nil {
+		return 0, fmt.Errorf("error purging versions: %w", err)
+	}
+
+	logging.LogDebug("Purged previous versions of %d packages", len(packages))
+	return len(packages), nil
+}
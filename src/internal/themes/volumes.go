@@ -0,0 +1,298 @@
+// src/internal/themes/volumes.go
+// Splits large exports into numbered archive volumes and reassembles them on import
+
+package themes
+
+import (
+	"archive/zip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// VolumeSizePresets are the volume sizes in MB offered in settings; 0 never splits
+var VolumeSizePresets = []int{0, 8, 25, 100}
+
+// volumeSuffix is the extension of the first volume; later ones count up (.002, .003, ...).
+// The numbering matches 7-Zip, so volumes can also be joined on a computer.
+const volumeSuffix = ".zip.001"
+
+// GetVolumeSizeSetting returns the export volume size in MB, 0 when splitting is off
+func GetVolumeSizeSetting() int {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load volume size setting: %v", err)
+		return 0
+	}
+	return config.VolumeSizeMB
+}
+
+// SetVolumeSizeSetting stores the export volume size in MB
+func SetVolumeSizeSetting(sizeMB int) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.VolumeSizeMB = sizeMB
+	return SaveConfig(config)
+}
+
+// volumeWriter writes a stream across numbered files of at most size bytes each
+type volumeWriter struct {
+	base    string
+	size    int64
+	current *os.File
+	written int64
+	paths   []string
+}
+
+// Write fills the current volume and starts a new one whenever it is full
+func (w *volumeWriter) Write(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		if w.current == nil || w.written >= w.size {
+			if err := w.next(); err != nil {
+				return total, err
+			}
+		}
+
+		chunk := p
+		if remaining := w.size - w.written; int64(len(chunk)) > remaining {
+			chunk = chunk[:remaining]
+		}
+
+		n, err := w.current.Write(chunk)
+		total += n
+		w.written += int64(n)
+		if err != nil {
+			return total, err
+		}
+		p = p[n:]
+	}
+	return total, nil
+}
+
+// next closes the current volume and opens the following one
+func (w *volumeWriter) next() error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("%s.zip.%03d", w.base, len(w.paths)+1)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating volume %s: %w", path, err)
+	}
+
+	w.current = file
+	w.written = 0
+	w.paths = append(w.paths, path)
+	return nil
+}
+
+// Close closes the volume being written
+func (w *volumeWriter) Close() error {
+	if w.current == nil {
+		return nil
+	}
+	err := w.current.Close()
+	w.current = nil
+	return err
+}
+
+// directorySize returns the total size of the files in a directory tree
+func directorySize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// addPackageToZip writes every file of a package folder into an archive under the folder's name
+func addPackageToZip(archive *zip.Writer, packagePath string) error {
+	return addPackageToZipWith(archive, packagePath, nil)
+}
+
+// addPackageToZipWith is addPackageToZip with some files taken from elsewhere: replacements
+// maps a path inside the package, such as "preview.png", to the file written in its place.
+// Replacements the package doesn't have are added.
+func addPackageToZipWith(archive *zip.Writer, packagePath string, replacements map[string]string) error {
+	rootName := filepath.Base(packagePath)
+	replaced := make(map[string]bool)
+
+	err := filepath.Walk(packagePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(packagePath, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if replacement, ok := replacements[rel]; ok {
+			replaced[rel] = true
+			return addFileToZip(archive, replacement, rootName+"/"+rel)
+		}
+		return addFileToZip(archive, path, rootName+"/"+rel)
+	})
+	if err != nil {
+		return err
+	}
+
+	for rel, replacement := range replacements {
+		if !replaced[rel] {
+			if err := addFileToZip(archive, replacement, rootName+"/"+rel); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addFileToZip writes one file into an archive under name
+func addFileToZip(archive *zip.Writer, path, name string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+
+	entry, err := archive.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = streamCopy(entry, src)
+	return err
+}
+
+// SplitPackage zips a package folder into volumes of at most volumeSizeMB next to it.
+// It returns the volume paths, or nil if the package already fits in one volume.
+func SplitPackage(packagePath string, volumeSizeMB int, logger *Logger) ([]string, error) {
+	volumeSize := int64(volumeSizeMB) * 1024 * 1024
+	if volumeSize <= 0 || directorySize(packagePath) <= volumeSize {
+		return nil, nil
+	}
+
+	logger.DebugFn("Splitting %s into %d MB volumes", packagePath, volumeSizeMB)
+
+	writer := &volumeWriter{base: packagePath, size: volumeSize}
+	archive := zip.NewWriter(writer)
+	rootName := filepath.Base(packagePath)
+
+	err := addPackageToZip(archive, packagePath)
+	if err == nil {
+		err = archive.Close()
+	}
+	if closeErr := writer.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		for _, path := range writer.paths {
+			os.Remove(path)
+		}
+		return nil, fmt.Errorf("error writing volumes: %w", err)
+	}
+
+	logger.DebugFn("Wrote %d volumes for %s", len(writer.paths), rootName)
+	return writer.paths, nil
+}
+
+// findVolumeSets returns the first volume of every split archive in a folder
+func findVolumeSets(folder string) []string {
+	matches, _ := filepath.Glob(filepath.Join(folder, "*"+volumeSuffix))
+	sort.Strings(matches)
+	return matches
+}
+
+// ReassembleVolumes joins a split archive, starting from its .zip.001 volume, and
+// extracts the package into the same folder. It returns the extracted package path.
+func ReassembleVolumes(firstVolume string, logger *Logger) (string, error) {
+	base := strings.TrimSuffix(firstVolume, volumeSuffix)
+	packagePath := base
+
+	if _, err := os.Stat(packagePath); err == nil {
+		return "", fmt.Errorf("%s already exists", filepath.Base(packagePath))
+	}
+
+	// Collect consecutive volumes; a gap means the set is incomplete
+	var volumes []string
+	for i := 1; ; i++ {
+		path := fmt.Sprintf("%s.zip.%03d", base, i)
+		if _, err := os.Stat(path); err != nil {
+			break
+		}
+		volumes = append(volumes, path)
+	}
+
+	if next, _ := filepath.Glob(base + ".zip.[0-9][0-9][0-9]"); len(next) != len(volumes) {
+		return "", fmt.Errorf("volumes of %s are missing or out of sequence", filepath.Base(base))
+	}
+
+	logger.DebugFn("Reassembling %d volumes of %s", len(volumes), filepath.Base(base))
+
+	zipPath := base + ".zip"
+	joined, err := os.Create(zipPath)
+	if err != nil {
+		return "", fmt.Errorf("error creating joined archive: %w", err)
+	}
+
+	for _, path := range volumes {
+		part, err := os.Open(path)
+		if err != nil {
+			joined.Close()
+			os.Remove(zipPath)
+			return "", fmt.Errorf("error opening volume %s: %w", filepath.Base(path), err)
+		}
+		_, err = streamCopy(joined, part)
+		part.Close()
+		if err != nil {
+			joined.Close()
+			os.Remove(zipPath)
+			return "", fmt.Errorf("error joining volume %s: %w", filepath.Base(path), err)
+		}
+	}
+
+	if err := joined.Close(); err != nil {
+		os.Remove(zipPath)
+		return "", fmt.Errorf("error writing joined archive: %w", err)
+	}
+	defer os.Remove(zipPath)
+
+	if err := extractZipFile(zipPath, packagePath); err != nil {
+		os.RemoveAll(packagePath)
+		return "", fmt.Errorf("error extracting joined archive: %w", err)
+	}
+
+	for _, path := range volumes {
+		if err := os.Remove(path); err != nil {
+			logger.DebugFn("Warning: Could not remove volume %s: %v", path, err)
+		}
+	}
+
+	return packagePath, nil
+}
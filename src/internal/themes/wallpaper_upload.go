@@ -0,0 +1,378 @@
+// src/internal/themes/wallpaper_upload.go
+// Turns uploaded photos into wallpapers at the device's resolution and installs them to one slot
+
+package themes
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // Decoders for uploads
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// maxWallpaperPixels is the largest photo that is decoded, about 36 megapixels. Decoding
+// needs several bytes per pixel, bigger images (or ones made to look small when zipped)
+// could run the device out of memory.
+const maxWallpaperPixels = 36 * 1000 * 1000
+
+// How an uploaded image is made to fit the screen
+const (
+	WallpaperFitCrop   = "crop"   // Fill the screen, cutting off the edges that don't fit
+	WallpaperFitFit    = "fit"    // Show the whole image, with black bars where it doesn't fill the screen
+	WallpaperFitCenter = "center" // Keep the image's own size, centered on black
+)
+
+// WallpaperFitModes lists the fit modes in the order they are offered
+var WallpaperFitModes = []string{WallpaperFitCrop, WallpaperFitFit, WallpaperFitCenter}
+
+// jpegOrientation returns the EXIF orientation of a JPEG (1 to 8), or 1 when it has none.
+// Phone cameras store photos sideways and rely on this tag to show them upright.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		size := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if marker == 0xDA || size < 2 || pos+2+size > len(data) {
+			// Image data starts, no EXIF block before it
+			return 1
+		}
+
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + size
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag from the first IFD of a TIFF block
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientedSource returns the size of an image once its EXIF orientation is applied, and a
+// function mapping a pixel of the upright image back to the stored one
+func orientedSource(bounds image.Rectangle, orientation int) (int, int, func(x, y int) (int, int)) {
+	w, h := bounds.Dx(), bounds.Dy()
+	switch orientation {
+	case 2:
+		return w, h, func(x, y int) (int, int) { return w - 1 - x, y }
+	case 3:
+		return w, h, func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case 4:
+		return w, h, func(x, y int) (int, int) { return x, h - 1 - y }
+	case 5:
+		return h, w, func(x, y int) (int, int) { return y, x }
+	case 6:
+		return h, w, func(x, y int) (int, int) { return y, h - 1 - x }
+	case 7:
+		return h, w, func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case 8:
+		return h, w, func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return w, h, func(x, y int) (int, int) { return x, y }
+	}
+}
+
+// ConvertWallpaper decodes an uploaded JPEG, PNG or GIF, turns it upright and fits it to the
+// screen with the given mode. Downscaling averages every source pixel under each screen
+// pixel, so photos stay smooth instead of aliased.
+func ConvertWallpaper(data []byte, mode string) (*image.NRGBA, error) {
+	// Check the size in the header before decoding anything
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image, upload a JPEG, PNG or GIF: %w", err)
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+	if int64(config.Width)*int64(config.Height) > maxWallpaperPixels {
+		return nil, fmt.Errorf("image is %dx%d, the largest is %d megapixels", config.Width, config.Height, maxWallpaperPixels/1000000)
+	}
+
+	decoded, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image, upload a JPEG, PNG or GIF: %w", err)
+	}
+
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+
+	// Pixels are read from the decoded image as it is, a copy would need as much memory again
+	bounds := decoded.Bounds()
+	pixel := rgbSampler(decoded)
+	ow, oh, sourcePixel := orientedSource(bounds, orientation)
+	if ow == 0 || oh == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+	screenWidth, screenHeight := system.ScreenSize()
+
+	// Size of the image on screen
+	dw, dh := ow, oh
+	switch mode {
+	case WallpaperFitCrop, WallpaperFitFit:
+		scaleW := float64(screenWidth) / float64(ow)
+		scaleH := float64(screenHeight) / float64(oh)
+		scale := scaleW
+		if (mode == WallpaperFitCrop) == (scaleH > scaleW) {
+			scale = scaleH
+		}
+		dw, dh = int(float64(ow)*scale+0.5), int(float64(oh)*scale+0.5)
+	case WallpaperFitCenter:
+	default:
+		return nil, fmt.Errorf("unknown fit mode: %s", mode)
+	}
+	if dw < 1 {
+		dw = 1
+	}
+	if dh < 1 {
+		dh = 1
+	}
+	offX, offY := (screenWidth-dw)/2, (screenHeight-dh)/2
+
+	// Black where the image doesn't reach, for the bars of fit and center
+	out := image.NewNRGBA(image.Rect(0, 0, screenWidth, screenHeight))
+	draw.Draw(out, out.Bounds(), image.Black, image.Point{}, draw.Src)
+	for y := 0; y < screenHeight; y++ {
+		dy := y - offY
+		if dy < 0 || dy >= dh {
+			continue
+		}
+		sy0, sy1 := dy*oh/dh, (dy+1)*oh/dh
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+
+		for x := 0; x < screenWidth; x++ {
+			dx := x - offX
+			if dx < 0 || dx >= dw {
+				continue
+			}
+			sx0, sx1 := dx*ow/dw, (dx+1)*ow/dw
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			// Premultiplied sums, which flattens transparency onto black like the device does
+			var r, g, b, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					px, py := sourcePixel(sx, sy)
+					pr, pg, pb := pixel(bounds.Min.X+px, bounds.Min.Y+py)
+					r += pr
+					g += pg
+					b += pb
+					n++
+				}
+			}
+
+			i := out.PixOffset(x, y)
+			out.Pix[i] = uint8(r / n)
+			out.Pix[i+1] = uint8(g / n)
+			out.Pix[i+2] = uint8(b / n)
+		}
+	}
+	return out, nil
+}
+
+// rgbSampler returns a function reading the premultiplied 8-bit color of a pixel. The
+// formats the decoders produce are read directly, which is much faster than At() on a
+// 12 megapixel photo.
+func rgbSampler(img image.Image) func(x, y int) (uint32, uint32, uint32) {
+	switch src := img.(type) {
+	case *image.YCbCr:
+		return func(x, y int) (uint32, uint32, uint32) {
+			yi, ci := src.YOffset(x, y), src.COffset(x, y)
+			r, g, b := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+			return uint32(r), uint32(g), uint32(b)
+		}
+	case *image.RGBA:
+		return func(x, y int) (uint32, uint32, uint32) {
+			i := src.PixOffset(x, y)
+			return uint32(src.Pix[i]), uint32(src.Pix[i+1]), uint32(src.Pix[i+2])
+		}
+	case *image.NRGBA:
+		return func(x, y int) (uint32, uint32, uint32) {
+			i := src.PixOffset(x, y)
+			a := uint32(src.Pix[i+3])
+			return uint32(src.Pix[i]) * a / 255, uint32(src.Pix[i+1]) * a / 255, uint32(src.Pix[i+2]) * a / 255
+		}
+	case *image.Gray:
+		return func(x, y int) (uint32, uint32, uint32) {
+			v := uint32(src.Pix[src.PixOffset(x, y)])
+			return v, v, v
+		}
+	default:
+		return func(x, y int) (uint32, uint32, uint32) {
+			r, g, b, _ := img.At(x, y).RGBA()
+			return r >> 8, g >> 8, b >> 8
+		}
+	}
+}
+
+// ListWallpaperSlots returns where an uploaded wallpaper can be installed: the fixed places
+// such as Root, then every ROM system that isn't excluded from theming
+func ListWallpaperSlots() ([]string, error) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return nil, fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	var slots []string
+	for _, special := range SpecialWallpapers() {
+		slots = append(slots, special.Name)
+	}
+
+	excluded := loadExcludedSystems()
+	for _, sys := range systemPaths.Systems {
+		if !isTagExcluded(sys.Tag, excluded) {
+			slots = append(slots, sys.Name)
+		}
+	}
+	return slots, nil
+}
+
+// wallpaperSlotPath returns the file a slot's wallpaper is written to. Only systems have
+// list wallpapers.
+func wallpaperSlotPath(slot string, list bool, systemPaths *system.SystemPaths) (string, error) {
+	if special, ok := findSpecialDestination(SpecialWallpapers(), slot+".png"); ok {
+		if list {
+			return "", fmt.Errorf("%s has no list wallpaper", slot)
+		}
+		return special.SystemPath(systemPaths), nil
+	}
+
+	for _, sys := range systemPaths.Systems {
+		if sys.Name != slot {
+			continue
+		}
+		if list {
+			return filepath.Join(sys.MediaPath, "bglist.png"), nil
+		}
+		return filepath.Join(sys.MediaPath, "bg.png"), nil
+	}
+	return "", fmt.Errorf("unknown wallpaper slot: %s", slot)
+}
+
+// InstallUploadedWallpaper converts an uploaded image and installs it as the wallpaper, or
+// list wallpaper, of one slot. The wallpaper it replaces goes to the trash; pinned wallpapers
+// are left alone.
+func InstallUploadedWallpaper(data []byte, slot, mode string, list bool) (err error) {
+	defer func() { recordOperation("Installed uploaded wallpaper", slot, err) }()
+
+	if err := CheckKioskMode("installing a wallpaper"); err != nil {
+		return err
+	}
+	if err := CheckStorageWritable(); err != nil {
+		return err
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	dstPath, err := wallpaperSlotPath(slot, list, systemPaths)
+	if err != nil {
+		return err
+	}
+	for _, pinned := range GetPinnedPaths() {
+		if filepath.Clean(pinned) == filepath.Clean(dstPath) {
+			return fmt.Errorf("the wallpaper of %s is pinned, unpin it in Settings first", slot)
+		}
+	}
+
+	img, err := ConvertWallpaper(data, mode)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+
+	// Write next to the destination first so a failed upload never leaves half a wallpaper
+	tmpPath := dstPath + ".upload"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("error creating wallpaper: %w", err)
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("error encoding wallpaper: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing wallpaper: %w", err)
+	}
+
+	if _, err := os.Stat(dstPath); err == nil {
+		beginTrashBatch()
+		if err := moveToTrash(dstPath); err != nil {
+			os.Remove(tmpPath)
+			return fmt.Errorf("error replacing wallpaper: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error installing wallpaper: %w", err)
+	}
+
+	kind := "wallpaper"
+	if list {
+		kind = "list wallpaper"
+	}
+	logging.LogDebug("Installed uploaded %s for %s (%s) at %s", kind, slot, mode, dstPath)
+	return nil
+}
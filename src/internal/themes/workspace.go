@@ -0,0 +1,279 @@
+// src/internal/themes/workspace.go
+// Workspace mode: edit a copy of an installed theme, swap in files from components and save it as a new version
+
+package themes
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+)
+
+// workspaceDirName is the folder inside the pak that holds themes being edited
+const workspaceDirName = "Workspace"
+
+// workspaceKind describes which files of a theme can be swapped for a component type
+type workspaceKind struct {
+	themeDir   string   // Folder inside the theme holding these files
+	extensions []string // File extensions that can be swapped
+}
+
+// workspaceKinds lists the component types whose files can be swapped into a workspace
+var workspaceKinds = map[string]workspaceKind{
+	ComponentWallpaper: {themeDir: "Wallpapers", extensions: []string{".png"}},
+	ComponentIcon:      {themeDir: "Icons", extensions: []string{".png"}},
+	ComponentFont:      {themeDir: "Fonts", extensions: []string{".ttf", ".otf"}},
+}
+
+// GetWorkspacePath returns the working copy of an installed theme
+func GetWorkspacePath(themeName string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("error getting current directory: %w", err)
+	}
+	return filepath.Join(cwd, workspaceDirName, themeName), nil
+}
+
+// HasWorkspace reports whether a theme has unsaved edits in the workspace
+func HasWorkspace(themeName string) bool {
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(workspacePath)
+	return err == nil
+}
+
+// OpenWorkspace copies an installed theme into the workspace for editing. A workspace
+// left from an earlier session is reused so unsaved edits aren't lost.
+func OpenWorkspace(themeName string) (string, error) {
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(workspacePath); err == nil {
+		logging.LogDebug("Resuming workspace for %s", themeName)
+		return workspacePath, nil
+	}
+
+	themePath := filepath.Join(filepath.Dir(filepath.Dir(workspacePath)), "Themes", themeName)
+	if _, err := ValidateTheme(themePath, &Logger{DebugFn: logging.LogDebug}); err != nil {
+		return "", err
+	}
+
+	logging.LogDebug("Opening workspace for %s", themeName)
+	if err := copyTree(themePath, workspacePath); err != nil {
+		os.RemoveAll(workspacePath)
+		return "", fmt.Errorf("error copying theme to the workspace: %w", err)
+	}
+
+	return workspacePath, nil
+}
+
+// hasKindExtension reports whether a file can be swapped for the given kind
+func hasKindExtension(kind workspaceKind, name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, allowed := range kind.extensions {
+		if ext == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// listKindFiles returns the files under root that can be swapped for a kind, relative to base
+func listKindFiles(root, base string, kind workspaceKind) []string {
+	var files []string
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		if info.Name() == "preview.png" || strings.Contains(info.Name(), ".backup") || !hasKindExtension(kind, info.Name()) {
+			return nil
+		}
+		if rel, err := filepath.Rel(base, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+
+	sort.Strings(files)
+	return files
+}
+
+// ListWorkspaceSlots returns the theme files of a component type that can be swapped,
+// relative to the workspace, e.g. "Wallpapers/SystemWallpapers/Root.png"
+func ListWorkspaceSlots(themeName, componentType string) ([]string, error) {
+	kind, ok := workspaceKinds[componentType]
+	if !ok {
+		return nil, fmt.Errorf("%s files can't be swapped", componentType)
+	}
+
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return nil, err
+	}
+
+	return listKindFiles(filepath.Join(workspacePath, kind.themeDir), workspacePath, kind), nil
+}
+
+// ListSwapSources returns files from installed components of a type that can replace a
+// workspace file, as "<component>/<file>"
+func ListSwapSources(componentType string) ([]string, error) {
+	kind, ok := workspaceKinds[componentType]
+	if !ok {
+		return nil, fmt.Errorf("%s files can't be swapped", componentType)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	componentsDir := filepath.Join(cwd, "Components", ComponentDirectory[componentType])
+	entries, err := os.ReadDir(componentsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading %s: %w", componentsDir, err)
+	}
+
+	var sources []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ComponentExtension[componentType]) {
+			sources = append(sources, listKindFiles(filepath.Join(componentsDir, entry.Name()), componentsDir, kind)...)
+		}
+	}
+
+	return sources, nil
+}
+
+// SwapWorkspaceFile replaces a workspace file with a file from an installed component
+func SwapWorkspaceFile(themeName, componentType, slot, source string) error {
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return err
+	}
+
+	slotPath, err := insidePackage(workspacePath, slot)
+	if err != nil {
+		return err
+	}
+
+	componentsDir := filepath.Join(filepath.Dir(filepath.Dir(workspacePath)), "Components", ComponentDirectory[componentType])
+	sourcePath, err := insidePackage(componentsDir, source)
+	if err != nil {
+		return err
+	}
+
+	logging.LogDebug("Swapping %s in workspace %s for %s", slot, themeName, source)
+
+	// A preview made from the swapped file gets the new file too
+	previewPath := filepath.Join(workspacePath, "preview.png")
+	previewHash, previewErr := hashFile(previewPath)
+	slotHash, slotErr := hashFile(slotPath)
+	if previewErr == nil && slotErr == nil && bytes.Equal(previewHash, slotHash) {
+		if err := CopyFile(sourcePath, previewPath); err != nil {
+			logging.LogDebug("Warning: Could not update preview: %v", err)
+		}
+	}
+
+	return CopyFile(sourcePath, slotPath)
+}
+
+// BumpVersion increments the last number of a version, e.g. "1.2.0" becomes "1.2.1".
+// Versions that don't end in a number get ".1" appended.
+func BumpVersion(version string) string {
+	if version == "" {
+		return "1.0.1"
+	}
+
+	parts := strings.Split(version, ".")
+	last := len(parts) - 1
+	if n, err := strconv.Atoi(parts[last]); err == nil {
+		parts[last] = strconv.Itoa(n + 1)
+		return strings.Join(parts, ".")
+	}
+
+	return version + ".1"
+}
+
+// SaveWorkspace regenerates the workspace theme's manifest and preview and replaces the
+// installed theme with it under a new version. The previous version is archived.
+// Returns the new version.
+func SaveWorkspace(themeName string) (string, error) {
+	logger := &Logger{
+		DebugFn: logging.LogDebug,
+	}
+
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return "", err
+	}
+	themePath := filepath.Join(filepath.Dir(filepath.Dir(workspacePath)), "Themes", themeName)
+
+	manifest, err := ValidateTheme(workspacePath, logger)
+	if err != nil {
+		return "", err
+	}
+
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		return "", fmt.Errorf("error getting system paths: %w", err)
+	}
+
+	// Bump the version and record what was swapped since the installed version
+	if err := addChangelogEntry(themePath, workspacePath, manifest, logger); err != nil {
+		logger.DebugFn("Warning: Could not compare with installed version: %v", err)
+		manifest.ThemeInfo.Version = BumpVersion(manifest.ThemeInfo.Version)
+	}
+	if err := UpdateManifestFromThemeContent(workspacePath, manifest, systemPaths, logger); err != nil {
+		return "", fmt.Errorf("error regenerating manifest: %w", err)
+	}
+
+	// Themes without a preview get a collage of their images
+	if _, err := ensureSubmissionPreview(workspacePath, workspacePath, logger); err != nil {
+		logger.DebugFn("Warning: Could not generate preview: %v", err)
+	}
+
+	// Stamp author credit into the preview
+	stampPreview(workspacePath, manifest.ThemeInfo.Name, manifest.ThemeInfo.Author, logger)
+
+	beginTrashBatch()
+	if _, err := os.Stat(themePath); err == nil {
+		if err := archiveInstalledPackage(themePath, logger); err != nil {
+			return "", fmt.Errorf("error replacing installed theme: %w", err)
+		}
+	}
+
+	if err := movePackage(workspacePath, themePath); err != nil {
+		return "", fmt.Errorf("error saving theme: %w", err)
+	}
+
+	logger.DebugFn("Saved workspace for %s as version %s", themeName, manifest.ThemeInfo.Version)
+	return manifest.ThemeInfo.Version, nil
+}
+
+// DiscardWorkspace throws away the edits made to a theme
+func DiscardWorkspace(themeName string) error {
+	if err := CheckKioskMode("discarding a workspace"); err != nil {
+		return err
+	}
+
+	workspacePath, err := GetWorkspacePath(themeName)
+	if err != nil {
+		return err
+	}
+
+	logging.LogDebug("Discarding workspace for %s", themeName)
+	return os.RemoveAll(workspacePath)
+}
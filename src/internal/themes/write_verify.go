@@ -0,0 +1,99 @@
+// src/internal/themes/write_verify.go
+// Optional read-back verification of copied files for flaky SD cards
+
+package themes
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+
+	"nextui-themes/internal/logging"
+)
+
+// writeVerifyRetries is how many times a corrupt copy is redone before giving up
+const writeVerifyRetries = 2
+
+// writeVerify tracks read-back verification for the apply in progress
+var writeVerify struct {
+	enabled bool
+	corrupt []string
+}
+
+// GetVerifyWritesSetting reports whether copied files are read back and checked
+func GetVerifyWritesSetting() bool {
+	config, err := LoadConfig()
+	if err != nil {
+		logging.LogDebug("Warning: Could not load verify writes setting: %v", err)
+		return false
+	}
+	return config.VerifyWrites
+}
+
+// SetVerifyWritesSetting stores the verify writes setting
+func SetVerifyWritesSetting(enabled bool) error {
+	config, err := LoadConfig()
+	if err != nil {
+		return fmt.Errorf("error loading config: %w", err)
+	}
+
+	config.VerifyWrites = enabled
+	return SaveConfig(config)
+}
+
+// beginWriteVerify reads the setting and clears the corrupt file list for a new apply
+func beginWriteVerify() {
+	writeVerify.enabled = GetVerifyWritesSetting()
+	writeVerify.corrupt = nil
+}
+
+// hashFile returns the SHA-256 of a file's contents
+func hashFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := streamCopy(hash, file); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// verifyCopiedFile re-reads a copy and compares it with the source, redoing the
+// copy up to writeVerifyRetries times before reporting it as corrupt
+func verifyCopiedFile(srcPath, dstPath string, logger *Logger) error {
+	if !writeVerify.enabled {
+		return nil
+	}
+
+	want, err := hashFile(srcPath)
+	if err != nil {
+		return fmt.Errorf("error hashing source file: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		got, err := hashFile(dstPath)
+		if err == nil && bytes.Equal(got, want) {
+			if attempt > 0 {
+				logger.DebugFn("Copy of %s verified after %d retries", dstPath, attempt)
+			}
+			return nil
+		}
+
+		if attempt == writeVerifyRetries {
+			break
+		}
+
+		logger.DebugFn("Warning: Read-back of %s does not match, copying again", dstPath)
+		if err := CopyFile(srcPath, dstPath); err != nil {
+			logger.DebugFn("Warning: Retry copy of %s failed: %v", dstPath, err)
+		}
+	}
+
+	writeVerify.corrupt = append(writeVerify.corrupt, dstPath)
+	return fmt.Errorf("%s is still corrupt after %d retries", dstPath, writeVerifyRetries)
+}
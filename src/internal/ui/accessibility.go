@@ -0,0 +1,45 @@
+// src/internal/ui/accessibility.go
+// Large-text, high-contrast mode for the manager's own lists and messages
+
+package ui
+
+import "strconv"
+
+// accessibleFontSize is the message text size used in accessible mode
+const accessibleFontSize = 36
+
+// accessibleUI holds the accessible mode state; backgroundColor is nil when the mode is off
+var accessibleUI struct {
+	backgroundColor func() string
+}
+
+// SetAccessibleUI turns accessible mode on with a function returning the background color
+// to draw behind the manager's screens, or off when backgroundColor is nil. The color is
+// looked up on every screen so it follows accent changes made while the manager runs.
+func SetAccessibleUI(backgroundColor func() string) {
+	accessibleUI.backgroundColor = backgroundColor
+}
+
+// IsAccessibleUI reports whether accessible mode is on
+func IsAccessibleUI() bool {
+	return accessibleUI.backgroundColor != nil
+}
+
+// accessibleListArgs returns the minui-list arguments for accessible mode: a plain background
+// that contrasts with the list text, without the battery and brightness indicators
+func accessibleListArgs() []string {
+	if accessibleUI.backgroundColor == nil {
+		return nil
+	}
+	return []string{"--background-color", accessibleUI.backgroundColor(), "--hide-hardware-group"}
+}
+
+// presenterArgs adds the accessible mode arguments to a minui-presenter message
+func presenterArgs(args ...string) []string {
+	if accessibleUI.backgroundColor == nil {
+		return args
+	}
+	return append(args,
+		"--background-color", accessibleUI.backgroundColor(),
+		"--font-size-default", strconv.Itoa(accessibleFontSize))
+}
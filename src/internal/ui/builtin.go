@@ -0,0 +1,786 @@
+// src/internal/ui/builtin.go
+// Built-in front-end drawing straight to the framebuffer, with checklists, thumbnail grids and progress bars
+
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// BuiltinFrontend is the name of the built-in front-end
+const BuiltinFrontend = "builtin"
+
+// builtinFontPaths are NextUI's fonts, the first one found draws the text
+var builtinFontPaths = []string{
+	"/mnt/SDCARD/.system/res/font1.ttf",
+	"/mnt/SDCARD/.system/res/font2.ttf",
+}
+
+// Grid layout of thumbnail galleries
+const (
+	gridColumns = 4
+	gridRows    = 3
+)
+
+// button is a button of the device
+type button int
+
+const (
+	buttonNone button = iota
+	buttonUp
+	buttonDown
+	buttonLeft
+	buttonRight
+	buttonA
+	buttonB
+	buttonX
+	buttonY
+	buttonL
+	buttonR
+	buttonSelect
+	buttonStart
+	buttonMenu
+)
+
+// isDirection reports whether a button is part of the d-pad, which repeats while held
+func (b button) isDirection() bool {
+	return b == buttonUp || b == buttonDown || b == buttonLeft || b == buttonRight
+}
+
+// hint is a button label shown in the footer, e.g. "A" "SELECT"
+type hint struct {
+	button string
+	label  string
+}
+
+// builtinFrontend draws every screen itself on the framebuffer
+type builtinFrontend struct {
+	mu     sync.Mutex // Held while a frame is drawn
+	fb     *framebuffer
+	input  *inputReader
+	font   *ttFont
+	canvas *image.RGBA
+	thumbs map[thumbKey]*image.RGBA
+}
+
+// thumbKey identifies an image scaled to a size
+type thumbKey struct {
+	path string
+	size image.Point
+}
+
+// palette holds the colors of a frame
+type palette struct {
+	background color.Color
+	text       color.Color
+	highlight  color.Color // Behind the selected row
+	selected   color.Color // Text of the selected row
+	dim        color.Color // Hints, empty checkboxes and the progress bar track
+}
+
+func init() {
+	RegisterFrontend(BuiltinFrontend, newBuiltinFrontend)
+}
+
+// newBuiltinFrontend opens the framebuffer, the input devices and NextUI's font. The
+// framebuffer and font can be overridden with THEME_MANAGER_FB and THEME_MANAGER_FONT.
+func newBuiltinFrontend() (Frontend, error) {
+	fontPaths := builtinFontPaths
+	if path := os.Getenv("THEME_MANAGER_FONT"); path != "" {
+		fontPaths = []string{path}
+	}
+
+	var font *ttFont
+	var fontErr error
+	for _, path := range fontPaths {
+		if font, fontErr = readTTFont(path); fontErr == nil {
+			break
+		}
+	}
+	if font == nil {
+		return nil, fmt.Errorf("error loading font: %w", fontErr)
+	}
+
+	fbPath := os.Getenv("THEME_MANAGER_FB")
+	if fbPath == "" {
+		fbPath = "/dev/fb0"
+	}
+	fb, err := openFramebuffer(fbPath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening framebuffer: %w", err)
+	}
+
+	input, err := openInput()
+	if err != nil {
+		fb.close()
+		return nil, fmt.Errorf("error opening input: %w", err)
+	}
+
+	size := fb.size()
+	logging.LogDebug("Built-in front-end on a %dx%d framebuffer", size.X, size.Y)
+
+	return &builtinFrontend{
+		fb:     fb,
+		input:  input,
+		font:   font,
+		canvas: image.NewRGBA(image.Rectangle{Max: size}),
+		thumbs: make(map[thumbKey]*image.RGBA),
+	}, nil
+}
+
+// Name identifies the front-end
+func (f *builtinFrontend) Name() string {
+	return BuiltinFrontend
+}
+
+// colors returns the palette, following accessible mode's background when it is on
+func (f *builtinFrontend) colors() palette {
+	p := palette{
+		background: color.Black,
+		text:       color.White,
+		highlight:  color.White,
+		selected:   color.Black,
+		dim:        color.Gray{Y: 0x60},
+	}
+
+	if accessibleUI.backgroundColor != nil && parseHexColor(accessibleUI.backgroundColor()) == (color.RGBA{255, 255, 255, 255}) {
+		p.background, p.text = color.White, color.Black
+		p.highlight, p.selected = color.Black, color.White
+		p.dim = color.Gray{Y: 0x90}
+	}
+	return p
+}
+
+// parseHexColor reads "#RRGGBB", returning black for anything else
+func parseHexColor(value string) color.RGBA {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(value, "#")) != 6 {
+		return color.RGBA{0, 0, 0, 255}
+	}
+	return color.RGBA{uint8(rgb >> 16), uint8(rgb >> 8), uint8(rgb), 255}
+}
+
+// Text and layout sizes, relative to the screen height so every device looks the same
+func (f *builtinFrontend) unit() int {
+	u := f.canvas.Bounds().Dy() / 24
+	if IsAccessibleUI() {
+		u = u * 4 / 3
+	}
+	return u
+}
+
+func (f *builtinFrontend) textSize() int  { return f.unit() * 9 / 10 }
+func (f *builtinFrontend) titleSize() int { return f.unit() }
+func (f *builtinFrontend) hintSize() int  { return f.unit() * 2 / 3 }
+func (f *builtinFrontend) rowHeight() int { return f.unit() * 3 / 2 }
+func (f *builtinFrontend) margin() int    { return f.unit() }
+
+// headerHeight and footerHeight frame the content area of every screen
+func (f *builtinFrontend) headerHeight() int { return f.unit() * 2 }
+func (f *builtinFrontend) footerHeight() int { return f.unit() * 2 }
+
+// present writes the canvas to the screen
+func (f *builtinFrontend) present() {
+	if err := f.fb.show(f.canvas); err != nil {
+		logging.LogDebug("Error drawing frame: %v", err)
+	}
+}
+
+// frame draws one screen: the background, a title and footer hints around whatever
+// content draws, then shows it
+func (f *builtinFrontend) frame(title string, hints []hint, content func(area image.Rectangle, p palette)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p := f.colors()
+	bounds := f.canvas.Bounds()
+	draw.Draw(f.canvas, bounds, image.NewUniform(p.background), image.Point{}, draw.Src)
+
+	area := image.Rect(bounds.Min.X, bounds.Min.Y+f.headerHeight(), bounds.Max.X, bounds.Max.Y-f.footerHeight())
+	if title == "" {
+		area.Min.Y = bounds.Min.Y
+	}
+	content(area, p)
+
+	if title != "" {
+		size := f.titleSize()
+		text := f.font.truncate(title, size, bounds.Dx()-2*f.margin())
+		f.font.drawText(f.canvas, f.margin(), (f.headerHeight()-f.font.lineHeight(size))/2, text, size, p.text)
+	}
+	f.drawHints(hints, p)
+
+	f.present()
+}
+
+// drawHints draws button hints as pills in the bottom right corner
+func (f *builtinFrontend) drawHints(hints []hint, p palette) {
+	size := f.hintSize()
+	bounds := f.canvas.Bounds()
+	height := f.font.lineHeight(size) + f.unit()/3
+	y := bounds.Max.Y - (f.footerHeight()+height)/2
+	x := bounds.Max.X - f.margin()
+
+	for i := len(hints) - 1; i >= 0; i-- {
+		h := hints[i]
+		labelWidth := f.font.measure(h.label, size)
+		buttonWidth := f.font.measure(h.button, size) + height/2
+		x -= labelWidth
+		f.font.drawText(f.canvas, x, y+(height-f.font.lineHeight(size))/2, h.label, size, p.text)
+		x -= buttonWidth + f.unit()/4
+		pill := image.Rect(x, y, x+buttonWidth, y+height)
+		fillRoundRect(f.canvas, pill, height/2, p.highlight)
+		f.font.drawText(f.canvas, x+height/4, y+(height-f.font.lineHeight(size))/2, h.button, size, p.selected)
+		x -= f.unit() / 2
+	}
+}
+
+// drawCentered draws wrapped text centered in an area
+func (f *builtinFrontend) drawCentered(area image.Rectangle, text string, size int, c color.Color) {
+	lines := f.font.wrap(text, size, area.Dx()-2*f.margin())
+	lineHeight := f.font.lineHeight(size)
+	y := area.Min.Y + (area.Dy()-len(lines)*lineHeight)/2
+	for _, line := range lines {
+		x := area.Min.X + (area.Dx()-f.font.measure(line, size))/2
+		f.font.drawText(f.canvas, x, y, line, size, c)
+		y += lineHeight
+	}
+}
+
+// drawRows draws a scrolling list of rows with the selected one highlighted. label draws
+// each row's text and may add to it, e.g. a checkbox.
+func (f *builtinFrontend) drawRows(area image.Rectangle, p palette, count, selected int, label func(i int, row image.Rectangle, c color.Color)) {
+	rowHeight := f.rowHeight()
+	visible := area.Dy() / rowHeight
+	if visible < 1 {
+		visible = 1
+	}
+	top := 0
+	if selected >= visible {
+		top = selected - visible + 1
+	}
+
+	for i := top; i < count && i < top+visible; i++ {
+		y := area.Min.Y + (i-top)*rowHeight
+		row := image.Rect(area.Min.X+f.margin()/2, y, area.Max.X-f.margin()/2, y+rowHeight)
+		c := p.text
+		if i == selected {
+			fillRoundRect(f.canvas, row, rowHeight/2, p.highlight)
+			c = p.selected
+		}
+		label(i, row, c)
+	}
+
+	// Scroll bar when the list doesn't fit
+	if count > visible {
+		track := image.Rect(area.Max.X-f.margin()/3, area.Min.Y, area.Max.X-f.margin()/6, area.Min.Y+visible*rowHeight)
+		fillRect(f.canvas, track, p.dim)
+		thumbHeight := track.Dy() * visible / count
+		thumbTop := track.Min.Y + track.Dy()*top/count
+		fillRect(f.canvas, image.Rect(track.Min.X, thumbTop, track.Max.X, thumbTop+thumbHeight), p.text)
+	}
+}
+
+// rowText draws the text of a list row, vertically centered
+func (f *builtinFrontend) rowText(row image.Rectangle, x int, text string, c color.Color) {
+	size := f.textSize()
+	text = f.font.truncate(text, size, row.Max.X-x-f.margin()/2)
+	f.font.drawText(f.canvas, x, row.Min.Y+(row.Dy()-f.font.lineHeight(size))/2, text, size, c)
+}
+
+// moveSelection moves a list selection for a direction or page button, wrapping around
+// at the ends of the list. It reports false for other buttons.
+func moveSelection(selected, count, page int, b button) (int, bool) {
+	if count == 0 {
+		return 0, false
+	}
+	switch b {
+	case buttonUp:
+		selected--
+	case buttonDown:
+		selected++
+	case buttonL:
+		selected -= page
+		if selected < 0 {
+			selected = 0
+		}
+	case buttonR:
+		selected += page
+		if selected >= count {
+			selected = count - 1
+		}
+	default:
+		return selected, false
+	}
+	return (selected + count) % count, true
+}
+
+// List shows the items one per row; A picks the selected one, B goes back
+func (f *builtinFrontend) List(items string, format string, title string, options ListOptions) (string, int) {
+	var lines []string
+	for _, line := range strings.Split(items, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	cancelText := options.CancelText
+	if cancelText == "" {
+		cancelText = "BACK"
+	}
+	hints := []hint{{"B", cancelText}, {"A", "SELECT"}}
+
+	page := (f.canvas.Bounds().Dy() - f.headerHeight() - f.footerHeight()) / f.rowHeight()
+	selected := 0
+	f.input.drain()
+	for {
+		f.frame(title, hints, func(area image.Rectangle, p palette) {
+			if len(lines) == 0 {
+				f.drawCentered(area, "Nothing to show", f.textSize(), p.dim)
+				return
+			}
+			f.drawRows(area, p, len(lines), selected, func(i int, row image.Rectangle, c color.Color) {
+				f.rowText(row, row.Min.X+f.margin()/2, lines[i], c)
+			})
+		})
+
+		b := <-f.input.buttons()
+		if next, moved := moveSelection(selected, len(lines), page, b); moved {
+			selected = next
+			continue
+		}
+		switch b {
+		case buttonA:
+			if len(lines) > 0 {
+				return lines[selected], 0
+			}
+		case buttonB:
+			return "", 2
+		}
+	}
+}
+
+// MultiSelect shows the items with checkboxes; A ticks, Start confirms, B goes back
+func (f *builtinFrontend) MultiSelect(items []string, checked []bool, title string) ([]bool, int) {
+	ticked := make([]bool, len(items))
+	copy(ticked, checked)
+
+	hints := []hint{{"B", "BACK"}, {"A", "TICK"}, {"START", "DONE"}}
+	page := (f.canvas.Bounds().Dy() - f.headerHeight() - f.footerHeight()) / f.rowHeight()
+	selected := 0
+	f.input.drain()
+	for {
+		count := 0
+		for _, t := range ticked {
+			if t {
+				count++
+			}
+		}
+
+		f.frame(fmt.Sprintf("%s (%d/%d)", title, count, len(items)), hints, func(area image.Rectangle, p palette) {
+			f.drawRows(area, p, len(items), selected, func(i int, row image.Rectangle, c color.Color) {
+				box := f.textSize() * 3 / 4
+				x := row.Min.X + row.Dy()/2
+				square := image.Rect(x, row.Min.Y+(row.Dy()-box)/2, x+box, row.Min.Y+(row.Dy()+box)/2)
+				strokeRect(f.canvas, square, box/8+1, c)
+				if ticked[i] {
+					fillRect(f.canvas, square.Inset(box/4), c)
+				}
+				f.rowText(row, square.Max.X+box/2, items[i], c)
+			})
+		})
+
+		b := <-f.input.buttons()
+		if next, moved := moveSelection(selected, len(items), page, b); moved {
+			selected = next
+			continue
+		}
+		switch b {
+		case buttonA:
+			if len(items) > 0 {
+				ticked[selected] = !ticked[selected]
+			}
+		case buttonStart:
+			return ticked, 0
+		case buttonB:
+			return nil, 2
+		}
+	}
+}
+
+// Message shows a message until it times out or A or B dismisses it. "-1" waits for a button.
+func (f *builtinFrontend) Message(message string, timeout string) {
+	f.frame("", nil, func(area image.Rectangle, p palette) {
+		f.drawCentered(area, message, f.textSize(), p.text)
+	})
+
+	var expired <-chan time.Time
+	if seconds, err := strconv.Atoi(timeout); err == nil && seconds >= 0 {
+		timer := time.NewTimer(time.Duration(seconds) * time.Second)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	f.input.drain()
+	for {
+		select {
+		case <-expired:
+			return
+		case b := <-f.input.buttons():
+			if b == buttonA || b == buttonB {
+				return
+			}
+		}
+	}
+}
+
+// builtinScreen is a screen drawn in the background, closed by its buttons or by Close
+type builtinScreen struct {
+	done   chan int
+	stop   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// openScreen starts watching the buttons for a screen that is already drawn. exitCode
+// returns the code a button closes the screen with, or false to keep it open.
+func (f *builtinFrontend) openScreen(exitCode func(b button) (int, bool)) *builtinScreen {
+	s := &builtinScreen{
+		done:   make(chan int, 1),
+		stop:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+
+	f.input.drain()
+	go func() {
+		defer close(s.exited)
+		for {
+			select {
+			case <-s.stop:
+				return
+			case b := <-f.input.buttons():
+				if code, ok := exitCode(b); ok {
+					s.done <- code
+					return
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// Done receives the exit code once a button closes the screen
+func (s *builtinScreen) Done() <-chan int {
+	return s.done
+}
+
+// Close stops watching the buttons
+func (s *builtinScreen) Close() {
+	s.once.Do(func() { close(s.stop) })
+	<-s.exited
+}
+
+// OpenMessage draws a message that stays until it is closed, or B cancels it
+func (f *builtinFrontend) OpenMessage(message string, cancellable bool) (ScreenHandle, error) {
+	var hints []hint
+	if cancellable {
+		hints = []hint{{"B", "CANCEL"}}
+	}
+	f.frame("", hints, func(area image.Rectangle, p palette) {
+		f.drawCentered(area, message, f.textSize(), p.text)
+	})
+
+	return f.openScreen(func(b button) (int, bool) {
+		return 2, cancellable && b == buttonB
+	}), nil
+}
+
+// OpenGalleryItem draws an image over the whole screen with its text on a pill at the top
+func (f *builtinFrontend) OpenGalleryItem(text string, imagePath string, options GalleryOptions) (ScreenHandle, error) {
+	hints := []hint{{"Y", "PREV"}, {"X", "NEXT"}, {"B", "BACK"}, {"A", "SELECT"}}
+	if options.ToggleText != "" {
+		hints = append([]hint{{"R", options.ToggleText}}, hints...)
+	}
+	f.frame("", hints, func(area image.Rectangle, p palette) {
+		bounds := f.canvas.Bounds()
+		if img := f.thumbnail(imagePath, bounds.Size()); img != nil {
+			draw.Draw(f.canvas, bounds, img, image.Point{}, draw.Src)
+		}
+
+		size := f.textSize()
+		label := f.font.truncate(text, size, bounds.Dx()-4*f.margin())
+		height := f.rowHeight()
+		width := f.font.measure(label, size) + height
+		pill := image.Rect(f.margin(), f.margin()/2, f.margin()+width, f.margin()/2+height)
+		fillRoundRect(f.canvas, pill, height/2, p.highlight)
+		f.font.drawText(f.canvas, pill.Min.X+height/2, pill.Min.Y+(height-f.font.lineHeight(size))/2, label, size, p.selected)
+	})
+
+	return f.openScreen(func(b button) (int, bool) {
+		switch b {
+		case buttonA:
+			return 0, true
+		case buttonB:
+			return 2, true
+		case buttonX, buttonRight:
+			return 4, true
+		case buttonY, buttonLeft:
+			return 5, true
+		case buttonR:
+			return GalleryToggleCode, options.ToggleText != ""
+		}
+		return 0, false
+	}), nil
+}
+
+// Grid shows the gallery as pages of thumbnails with the selected item's text in the title
+func (f *builtinFrontend) Grid(items []GalleryItem, title string, selected int, changed func() bool) (int, int) {
+	hints := []hint{{"B", "BACK"}, {"A", "SELECT"}}
+	perPage := gridColumns * gridRows
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	f.input.drain()
+	for {
+		if selected < 0 || selected >= len(items) {
+			selected = 0
+		}
+
+		heading := fmt.Sprintf("%s: %s (%d/%d)", title, items[selected].Text, selected+1, len(items))
+		f.frame(heading, hints, func(area image.Rectangle, p palette) {
+			gap := f.unit() / 2
+			cellWidth := (area.Dx() - gap*(gridColumns+1)) / gridColumns
+			cellHeight := (area.Dy() - gap*(gridRows+1)) / gridRows
+			first := selected / perPage * perPage
+
+			for i := first; i < len(items) && i < first+perPage; i++ {
+				col, row := (i-first)%gridColumns, (i-first)/gridColumns
+				x := area.Min.X + gap + col*(cellWidth+gap)
+				y := area.Min.Y + gap + row*(cellHeight+gap)
+				cell := image.Rect(x, y, x+cellWidth, y+cellHeight)
+
+				if i == selected {
+					fillRect(f.canvas, cell.Inset(-gap/3), p.highlight)
+				}
+				fillRect(f.canvas, cell, p.dim)
+				if thumb := f.thumbnail(items[i].BackgroundImage, cell.Size()); thumb != nil {
+					draw.Draw(f.canvas, cell, thumb, image.Point{}, draw.Src)
+				} else {
+					f.drawCentered(cell, items[i].Text, f.hintSize(), p.text)
+				}
+			}
+		})
+
+		select {
+		case <-ticker.C:
+			if changed != nil && changed() {
+				return selected, GalleryRefreshCode
+			}
+
+		case b := <-f.input.buttons():
+			switch b {
+			case buttonLeft:
+				selected = (selected - 1 + len(items)) % len(items)
+			case buttonRight:
+				selected = (selected + 1) % len(items)
+			case buttonUp:
+				selected = (selected - gridColumns + len(items)) % len(items)
+			case buttonDown:
+				selected = (selected + gridColumns) % len(items)
+			case buttonL:
+				selected = (selected - perPage + len(items)) % len(items)
+			case buttonR:
+				selected = (selected + perPage) % len(items)
+			case buttonA:
+				return selected, 0
+			case buttonB:
+				return -1, 2
+			}
+		}
+	}
+}
+
+// builtinProgress is a progress screen redrawn with every update
+type builtinProgress struct {
+	*builtinScreen
+	frontend *builtinFrontend
+	message  string
+}
+
+// OpenProgress draws a progress screen with a bar, updated as the operation reports progress
+func (f *builtinFrontend) OpenProgress(message string, cancellable bool) (ProgressHandle, error) {
+	screen := &builtinProgress{frontend: f, message: message}
+	screen.Update(Progress{}, false)
+	screen.builtinScreen = f.openScreen(func(b button) (int, bool) {
+		return 2, cancellable && b == buttonB
+	})
+	return screen, nil
+}
+
+// Update redraws the bar, the step being worked on and the cancel hint
+func (s *builtinProgress) Update(progress Progress, cancelling bool) {
+	f := s.frontend
+
+	hints := []hint{{"B", "CANCEL"}}
+	step := progress.Step
+	if cancelling {
+		hints, step = nil, "Cancelling..."
+	}
+
+	f.frame("", hints, func(area image.Rectangle, p palette) {
+		size := f.textSize()
+		lineHeight := f.font.lineHeight(size)
+		center := area.Min.Y + area.Dy()/2
+
+		f.drawCentered(image.Rect(area.Min.X, area.Min.Y, area.Max.X, center-lineHeight), s.message, size, p.text)
+
+		bar := image.Rect(area.Min.X+3*f.margin(), center-f.unit()/3, area.Max.X-3*f.margin(), center+f.unit()/3)
+		fillRoundRect(f.canvas, bar, bar.Dy()/2, p.dim)
+		if progress.Total > 0 {
+			done := progress.Done
+			if done > progress.Total {
+				done = progress.Total
+			}
+			filled := bar
+			filled.Max.X = bar.Min.X + bar.Dx()*done/progress.Total
+			if filled.Dx() > 0 {
+				fillRoundRect(f.canvas, filled, bar.Dy()/2, p.text)
+			}
+			percent := fmt.Sprintf("%d%%", done*100/progress.Total)
+			f.font.drawText(f.canvas, area.Min.X+(area.Dx()-f.font.measure(percent, size))/2, bar.Max.Y+lineHeight/2, percent, size, p.text)
+		}
+
+		if step != "" {
+			step = f.font.truncate(step, size, area.Dx()-2*f.margin())
+			x := area.Min.X + (area.Dx()-f.font.measure(step, size))/2
+			f.font.drawText(f.canvas, x, bar.Max.Y+2*lineHeight, step, size, p.dim)
+		}
+	})
+}
+
+// thumbnail returns an image scaled to cover size, cropping what doesn't fit, or nil when
+// it can't be read. Scaled images are kept for the session.
+func (f *builtinFrontend) thumbnail(path string, size image.Point) *image.RGBA {
+	if path == "" {
+		return nil
+	}
+	key := thumbKey{path, size}
+	if thumb, ok := f.thumbs[key]; ok {
+		return thumb
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		f.thumbs[key] = nil
+		return nil
+	}
+	src, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		logging.LogDebug("Could not decode %s: %v", path, err)
+		f.thumbs[key] = nil
+		return nil
+	}
+
+	thumb := scaleToCover(src, size)
+	f.thumbs[key] = thumb
+	return thumb
+}
+
+// scaleToCover scales an image to fill size, keeping its aspect ratio and cropping the
+// overflow evenly. Each output pixel averages the source pixels it covers.
+func scaleToCover(src image.Image, size image.Point) *image.RGBA {
+	dst := image.NewRGBA(image.Rectangle{Max: size})
+	sb := src.Bounds()
+	if sb.Empty() || size.X <= 0 || size.Y <= 0 {
+		return dst
+	}
+
+	// The source area that maps onto the output, centered
+	scale := float64(sb.Dx()) / float64(size.X)
+	if s := float64(sb.Dy()) / float64(size.Y); s < scale {
+		scale = s
+	}
+	offX := float64(sb.Min.X) + (float64(sb.Dx())-scale*float64(size.X))/2
+	offY := float64(sb.Min.Y) + (float64(sb.Dy())-scale*float64(size.Y))/2
+
+	for y := 0; y < size.Y; y++ {
+		y0 := int(offY + float64(y)*scale)
+		y1 := int(offY + float64(y+1)*scale)
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < size.X; x++ {
+			x0 := int(offX + float64(x)*scale)
+			x1 := int(offX + float64(x+1)*scale)
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1 && sy < sb.Max.Y; sy++ {
+				for sx := x0; sx < x1 && sx < sb.Max.X; sx++ {
+					cr, cg, cb, _ := src.At(sx, sy).RGBA()
+					r, g, b, n = r+cr>>8, g+cg>>8, b+cb>>8, n+1
+				}
+			}
+			if n > 0 {
+				i := y*dst.Stride + x*4
+				dst.Pix[i], dst.Pix[i+1], dst.Pix[i+2], dst.Pix[i+3] = uint8(r/n), uint8(g/n), uint8(b/n), 255
+			}
+		}
+	}
+	return dst
+}
+
+// fillRect fills a rectangle with a color
+func fillRect(dst draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(dst, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// strokeRect draws the outline of a rectangle
+func strokeRect(dst draw.Image, r image.Rectangle, width int, c color.Color) {
+	fillRect(dst, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+width), c)
+	fillRect(dst, image.Rect(r.Min.X, r.Max.Y-width, r.Max.X, r.Max.Y), c)
+	fillRect(dst, image.Rect(r.Min.X, r.Min.Y, r.Min.X+width, r.Max.Y), c)
+	fillRect(dst, image.Rect(r.Max.X-width, r.Min.Y, r.Max.X, r.Max.Y), c)
+}
+
+// fillRoundRect fills a rectangle with rounded corners, a pill when radius is half its height
+func fillRoundRect(dst draw.Image, r image.Rectangle, radius int, c color.Color) {
+	if radius*2 > r.Dy() {
+		radius = r.Dy() / 2
+	}
+	if radius*2 > r.Dx() {
+		radius = r.Dx() / 2
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		// How far the corner curves in on this row
+		inset := 0
+		dy := 0
+		switch {
+		case y < r.Min.Y+radius:
+			dy = r.Min.Y + radius - y
+		case y >= r.Max.Y-radius:
+			dy = y - (r.Max.Y - radius) + 1
+		}
+		if dy > 0 {
+			for inset < radius && (radius-inset)*(radius-inset)+dy*dy > radius*radius {
+				inset++
+			}
+		}
+		fillRect(dst, image.Rect(r.Min.X+inset, y, r.Max.X-inset, y+1), c)
+	}
+}
@@ -0,0 +1,54 @@
+// src/internal/ui/checklist.go
+// Lists where several items can be ticked at once
+
+package ui
+
+import (
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// checklistDone is the entry that confirms a checklist on front-ends without checkboxes
+const checklistDone = "Done"
+
+// DisplayChecklist lets the user tick any of the items, starting from checked. It returns
+// which are ticked with exit code 0, or exit code 2 when the user backed out.
+func DisplayChecklist(items []string, checked []bool, title string) ([]bool, int) {
+	logging.LogDebug("Displaying checklist with %d items and title: %s", len(items), title)
+
+	if multi, ok := CurrentFrontend().(MultiSelectFrontend); ok {
+		return multi.MultiSelect(items, checked, title)
+	}
+
+	// Without checkboxes, picking an item toggles it and "Done" confirms
+	ticked := make([]bool, len(items))
+	copy(ticked, checked)
+
+	for {
+		var lines []string
+		for i, item := range items {
+			if ticked[i] {
+				lines = append(lines, "[x] "+item)
+			} else {
+				lines = append(lines, "[ ] "+item)
+			}
+		}
+		lines = append(lines, checklistDone)
+
+		selection, exitCode := DisplayMinUiList(strings.Join(lines, "\n"), "text", title)
+		if exitCode != 0 {
+			return nil, exitCode
+		}
+		if selection == checklistDone {
+			return ticked, 0
+		}
+
+		for i, line := range lines[:len(items)] {
+			if line == selection {
+				ticked[i] = !ticked[i]
+				break
+			}
+		}
+	}
+}
@@ -4,13 +4,7 @@
 package ui
 
 import (
-	"bytes"
-	"fmt"
 	"nextui-themes/internal/logging"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 	"time"
 )
 
@@ -26,31 +20,17 @@ type Selection struct {
 func ShowMessageWithOperation(message string, operation func() error) error {
 	logging.LogDebug("Showing message with operation: %s", message)
 
-	// Get current directory
-	cwd, err := os.Getwd()
+	// The message stays up until it is closed
+	screen, err := CurrentFrontend().OpenMessage(message, false)
 	if err != nil {
-		logging.LogDebug("Error getting current directory: %v", err)
+		logging.LogDebug("Error showing message: %v", err)
 		return err
 	}
 
-	// Use explicit path to minui-presenter
-	minuiPresenterPath := filepath.Join(cwd, "minui-presenter")
-
-	// Start presenter with negative timeout (will stay until killed)
-	cmd := exec.Command(minuiPresenterPath, "--message", message, "--timeout", "-1")
-
-	// Start in background
-	if err := cmd.Start(); err != nil {
-		logging.LogDebug("Error starting minui-presenter: %v", err)
-		return err
-	}
-
-	// Ensure the process gets killed when we're done
+	// Ensure the message gets closed when we're done
 	defer func() {
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-			logging.LogDebug("Killed minui-presenter process")
-		}
+		screen.Close()
+		logging.LogDebug("Closed message screen")
 	}()
 
 	// Run the operation
@@ -63,112 +43,74 @@ func ShowMessageWithOperation(message string, operation func() error) error {
 	return operationErr
 }
 
-// DisplayMinUiList displays a list of items using minui-list
-func DisplayMinUiList(list string, format string, title string, extraArgs ...string) (string, int) {
-	logging.LogDebug("Displaying minui-list with title: %s", title)
-	logging.LogDebug("minui-list content: %s", list)
-
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		logging.LogDebug("Error getting current directory: %v", err)
-		return "", 1
-	}
-
-	// Create a temporary file for the list content
-	tempFile, err := os.CreateTemp("", "minui-list-input-*")
-	if err != nil {
-		logging.LogDebug("ERROR: Failed to create temp input file: %v", err)
-		return "", 1
-	}
-	inputPath := tempFile.Name()
-	defer os.Remove(inputPath)
-
-	// Write the list content to the temp file
-	if _, err := tempFile.WriteString(list); err != nil {
-		logging.LogDebug("ERROR: Failed to write to temp input file: %v", err)
-		tempFile.Close()
-		return "", 1
-	}
-	tempFile.Close()
+// ShowMessageWithProgress works like ShowMessageWithOperation, but hands the operation
+// an update function that replaces the message, e.g. to show which item is in progress
+func ShowMessageWithProgress(message string, operation func(update func(string)) error) error {
+	logging.LogDebug("Showing message with progress: %s", message)
 
-	// Create a temporary file for the output
-	tempOutFile, err := os.CreateTemp("", "minui-list-output-*")
-	if err != nil {
-		logging.LogDebug("ERROR: Failed to create temp output file: %v", err)
-		return "", 1
-	}
-	outputPath := tempOutFile.Name()
-	tempOutFile.Close()
-	defer os.Remove(outputPath)
+	frontend := CurrentFrontend()
 
-	// Build the command arguments
-	args := []string{"--format", format, "--title", title, "--file", inputPath, "--write-location", outputPath}
+	var screen ScreenHandle = noScreen{}
+	show := func(text string) {
+		// Replace the previous message so only one is on screen
+		screen.Close()
 
-	if extraArgs != nil {
-		args = append(args, extraArgs...)
+		opened, err := frontend.OpenMessage(text, false)
+		if err != nil {
+			logging.LogDebug("Error showing message: %v", err)
+			opened = noScreen{}
+		}
+		screen = opened
 	}
 
-	logging.LogDebug("minui-list args: %v", args)
+	show(message)
 
-	// Use explicit path to minui-list
-	minuiListPath := filepath.Join(cwd, "minui-list")
-	cmd := exec.Command(minuiListPath, args...)
+	// Ensure the last message gets closed when we're done
+	defer func() {
+		screen.Close()
+		logging.LogDebug("Closed message screen")
+	}()
 
-	var stderrbuf bytes.Buffer
-	cmd.Stderr = &stderrbuf
+	// Run the operation
+	operationErr := operation(show)
 
-	// Run the command
-	err = cmd.Run()
-	exitCode := 0
-	if err != nil {
-		exitCode = cmd.ProcessState.ExitCode()
-		logging.LogDebug("minui-list error: %v", err)
-	}
+	// Small delay to make sure the last message is visible for at least a moment
+	time.Sleep(500 * time.Millisecond)
 
-	errValue := stderrbuf.String()
-	if errValue != "" {
-		logging.LogDebug("stderr: %s", errValue)
-	}
+	return operationErr
+}
 
-	// Read the selection from the output file
-	var outValue string
-	if exitCode == 0 {
-		selectionBytes, err := os.ReadFile(outputPath)
-		if err != nil {
-			logging.LogDebug("ERROR: Failed to read selection from output file: %v", err)
-		} else {
-			outValue = strings.TrimSpace(string(selectionBytes))
-			logging.LogDebug("Selection read from file: '%s'", outValue)
+// DisplayMinUiList displays a list of items, one per line, through the active front-end.
+// extraArgs takes minui-list's "--cancel-text" flag; other front-ends get it as ListOptions.
+func DisplayMinUiList(list string, format string, title string, extraArgs ...string) (string, int) {
+	logging.LogDebug("Displaying list with title: %s", title)
+	logging.LogDebug("List content: %s", list)
+
+	var options ListOptions
+	for i := 0; i+1 < len(extraArgs); i += 2 {
+		switch extraArgs[i] {
+		case "--cancel-text":
+			options.CancelText = extraArgs[i+1]
+		default:
+			logging.LogDebug("Warning: Ignoring unknown list option %s", extraArgs[i])
 		}
 	}
 
-	logging.LogDebug("minui-list output: '%s', exit code: %d", outValue, exitCode)
+	outValue, exitCode := CurrentFrontend().List(list, format, title, options)
+
+	logging.LogDebug("List output: '%s', exit code: %d", outValue, exitCode)
 	return outValue, exitCode
 }
 
-// ShowMessage displays a message using minui-presenter
+// ShowMessage displays a message through the active front-end
 func ShowMessage(message string, timeout string) {
 	logging.LogDebug("Showing message: %s (timeout: %s)", message, timeout)
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		logging.LogDebug("Error getting current directory: %v", err)
+	// A progress screen owns the display, show the message once it closes
+	if deferProgressMessage(message) {
+		logging.LogDebug("Progress screen active, deferring message")
 		return
 	}
 
-	// Use explicit path to minui-presenter
-	minuiPresenterPath := filepath.Join(cwd, "minui-presenter")
-
-	args := []string{"--message", message, "--timeout", timeout}
-	cmd := exec.Command(minuiPresenterPath, args...)
-	err = cmd.Run()
-
-	if err != nil {
-		logging.LogDebug("minui-presenter error: %v", err)
-		if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() != 124 {
-			fmt.Printf("Failed to run minui-presenter: %v\n", err)
-		}
-	}
+	CurrentFrontend().Message(message, timeout)
 }
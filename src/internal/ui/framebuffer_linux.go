@@ -0,0 +1,126 @@
+// src/internal/ui/framebuffer_linux.go
+// Linux framebuffer output for the built-in front-end
+
+//go:build linux
+
+package ui
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Framebuffer ioctls from linux/fb.h
+const (
+	fbioGetVScreenInfo = 0x4600
+	fbioGetFScreenInfo = 0x4602
+)
+
+// framebuffer is an open framebuffer device with the layout of its pixels
+type framebuffer struct {
+	file   *os.File
+	width  int
+	height int
+	bpp    int   // Bytes per pixel, 2 or 4
+	stride int   // Bytes per line
+	start  int64 // Offset of the visible screen in the device
+	red    uint32
+	green  uint32
+	blue   uint32
+	buf    []byte
+}
+
+// fbIoctl reads a screen info struct into buf
+func fbIoctl(file *os.File, request uintptr, buf []byte) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), request, uintptr(unsafe.Pointer(&buf[0])))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// openFramebuffer opens a framebuffer device, e.g. /dev/fb0, and reads its pixel layout
+func openFramebuffer(path string) (*framebuffer, error) {
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// struct fb_var_screeninfo, 160 bytes of 32-bit fields
+	vinfo := make([]byte, 160)
+	if err := fbIoctl(file, fbioGetVScreenInfo, vinfo); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error reading screen info: %w", err)
+	}
+	field := func(i int) uint32 { return binary.LittleEndian.Uint32(vinfo[i*4:]) }
+
+	// struct fb_fix_screeninfo; line_length follows an unsigned long, so where it sits
+	// depends on the word size
+	finfo := make([]byte, 80)
+	if err := fbIoctl(file, fbioGetFScreenInfo, finfo); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error reading screen layout: %w", err)
+	}
+	lineLengthAt := (16 + int(unsafe.Sizeof(uintptr(0))) + 16 + 6 + 3) &^ 3
+
+	fb := &framebuffer{
+		file:   file,
+		width:  int(field(0)),
+		height: int(field(1)),
+		bpp:    int(field(6)) / 8,
+		stride: int(binary.LittleEndian.Uint32(finfo[lineLengthAt:])),
+		red:    field(8),
+		green:  field(11),
+		blue:   field(14),
+	}
+	xoffset, yoffset := int(field(4)), int(field(5))
+	fb.start = int64(yoffset*fb.stride + xoffset*fb.bpp)
+
+	if fb.bpp != 2 && fb.bpp != 4 {
+		file.Close()
+		return nil, fmt.Errorf("unsupported framebuffer depth: %d bits", fb.bpp*8)
+	}
+	if fb.width == 0 || fb.height == 0 || fb.stride < fb.width*fb.bpp {
+		file.Close()
+		return nil, fmt.Errorf("unsupported framebuffer size %dx%d", fb.width, fb.height)
+	}
+
+	fb.buf = make([]byte, fb.stride*fb.height)
+	return fb, nil
+}
+
+// size returns the screen size in pixels
+func (fb *framebuffer) size() image.Point {
+	return image.Pt(fb.width, fb.height)
+}
+
+// show writes a frame the size of the screen to the display
+func (fb *framebuffer) show(img *image.RGBA) error {
+	for y := 0; y < fb.height; y++ {
+		src := img.Pix[y*img.Stride:]
+		dst := fb.buf[y*fb.stride:]
+		for x := 0; x < fb.width; x++ {
+			r, g, b := uint32(src[x*4]), uint32(src[x*4+1]), uint32(src[x*4+2])
+			if fb.bpp == 2 {
+				// RGB565
+				pixel := (r>>3)<<fb.red | (g>>2)<<fb.green | (b>>3)<<fb.blue
+				binary.LittleEndian.PutUint16(dst[x*2:], uint16(pixel))
+				continue
+			}
+			pixel := r<<fb.red | g<<fb.green | b<<fb.blue | 0xFF000000
+			binary.LittleEndian.PutUint32(dst[x*4:], pixel)
+		}
+	}
+
+	_, err := fb.file.WriteAt(fb.buf, fb.start)
+	return err
+}
+
+// close releases the device
+func (fb *framebuffer) close() error {
+	return fb.file.Close()
+}
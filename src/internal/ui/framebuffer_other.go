@@ -0,0 +1,27 @@
+// src/internal/ui/framebuffer_other.go
+// The built-in front-end draws on the Linux framebuffer only
+
+//go:build !linux
+
+package ui
+
+import (
+	"errors"
+	"image"
+)
+
+// errNoFramebuffer is returned on systems without a Linux framebuffer
+var errNoFramebuffer = errors.New("the built-in front-end needs a Linux framebuffer")
+
+type framebuffer struct{}
+
+func openFramebuffer(path string) (*framebuffer, error) { return nil, errNoFramebuffer }
+func (fb *framebuffer) size() image.Point               { return image.Point{} }
+func (fb *framebuffer) show(img *image.RGBA) error      { return errNoFramebuffer }
+func (fb *framebuffer) close() error                    { return nil }
+
+type inputReader struct{}
+
+func openInput() (*inputReader, error)         { return nil, errNoFramebuffer }
+func (in *inputReader) buttons() <-chan button { return nil }
+func (in *inputReader) drain()                 {}
@@ -0,0 +1,149 @@
+// src/internal/ui/frontend.go
+// Front-end interface the manager draws its screens through, picked at startup
+
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/logging"
+)
+
+// DefaultFrontend is the front-end used unless another one is picked
+const DefaultFrontend = "minui"
+
+// Frontend draws the manager's lists, messages and galleries. The display functions of
+// this package go through the active front-end, so screens don't know which one runs.
+// Exit codes follow minui-list and minui-presenter: 0 selected, 2 back, 4 action (X),
+// 5 inaction (Y).
+type Frontend interface {
+	// Name identifies the front-end, e.g. "minui"
+	Name() string
+
+	// List shows a list of items, one per line, and returns the chosen line and the exit code
+	List(items string, format string, title string, options ListOptions) (string, int)
+
+	// Message shows a message for timeout seconds, or until dismissed when "-1" with a
+	// button, and returns once it has closed
+	Message(message string, timeout string)
+
+	// OpenMessage shows a message in the background until it is closed. With cancellable,
+	// a cancel button closes it with exit code 2.
+	OpenMessage(message string, cancellable bool) (ScreenHandle, error)
+
+	// OpenGalleryItem shows one gallery image in the background, with select, back,
+	// next (X) and previous (Y) buttons
+	OpenGalleryItem(text string, image string, options GalleryOptions) (ScreenHandle, error)
+}
+
+// GalleryOptions are the optional parts of a gallery item
+type GalleryOptions struct {
+	// ToggleText labels a shoulder button that closes the item with GalleryToggleCode,
+	// e.g. to switch what the gallery shows. No button when empty.
+	ToggleText string
+}
+
+// MultiSelectFrontend is a front-end that can tick several items of one list
+type MultiSelectFrontend interface {
+	// MultiSelect shows the items with checkboxes, starting from checked, and returns
+	// which are ticked with exit code 0, or exit code 2 when backed out
+	MultiSelect(items []string, checked []bool, title string) ([]bool, int)
+}
+
+// ThumbnailFrontend is a front-end that can show a whole gallery as a grid of thumbnails
+type ThumbnailFrontend interface {
+	// Grid shows the items starting at selected and returns the chosen index with exit
+	// code 0, or exit code 2 when backed out. changed is polled while the grid is up;
+	// when it reports true the grid closes with GalleryRefreshCode and the index it was on.
+	Grid(items []GalleryItem, title string, selected int, changed func() bool) (int, int)
+}
+
+// ProgressFrontend is a front-end with a progress screen that updates in place
+type ProgressFrontend interface {
+	// OpenProgress shows a progress screen in the background. With cancellable, a cancel
+	// button closes it with exit code 2.
+	OpenProgress(message string, cancellable bool) (ProgressHandle, error)
+}
+
+// ProgressHandle is a progress screen a front-end opened in the background
+type ProgressHandle interface {
+	ScreenHandle
+
+	// Update redraws the screen with the latest progress
+	Update(progress Progress, cancelling bool)
+}
+
+// ListOptions are the optional parts of a list
+type ListOptions struct {
+	CancelText string // Label of the back button, e.g. "QUIT"
+}
+
+// ScreenHandle is a screen a front-end opened in the background
+type ScreenHandle interface {
+	// Done receives the exit code once the screen closes by itself
+	Done() <-chan int
+
+	// Close closes the screen if it is still open and waits until it has
+	Close()
+}
+
+// noScreen stands in for a screen that could not be opened. It never closes by itself.
+type noScreen struct{}
+
+func (noScreen) Done() <-chan int { return nil }
+func (noScreen) Close()           {}
+
+// frontends are the front-ends that can be picked, by name
+var frontends = map[string]func() (Frontend, error){
+	DefaultFrontend: newMinUIFrontend,
+}
+
+// activeFrontend draws every screen
+var activeFrontend Frontend
+
+// RegisterFrontend makes a front-end available to SetFrontend. open is called when it is
+// picked and fails when the front-end can't run on this device.
+func RegisterFrontend(name string, open func() (Frontend, error)) {
+	frontends[name] = open
+}
+
+// Frontends returns the names of the available front-ends, sorted
+func Frontends() []string {
+	names := make([]string, 0, len(frontends))
+	for name := range frontends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetFrontend switches every screen to the named front-end
+func SetFrontend(name string) error {
+	open, ok := frontends[name]
+	if !ok {
+		return fmt.Errorf("unknown front-end '%s', expected one of: %s", name, strings.Join(Frontends(), ", "))
+	}
+
+	frontend, err := open()
+	if err != nil {
+		return fmt.Errorf("error starting front-end '%s': %w", name, err)
+	}
+
+	logging.LogDebug("Using front-end: %s", frontend.Name())
+	activeFrontend = frontend
+	return nil
+}
+
+// CurrentFrontend returns the front-end drawing the screens, the default one until
+// another is picked
+func CurrentFrontend() Frontend {
+	if activeFrontend == nil {
+		if err := SetFrontend(DefaultFrontend); err != nil {
+			logging.LogDebug("Error starting default front-end: %v", err)
+			activeFrontend = &minUIFrontend{dir: "."}
+		}
+	}
+	return activeFrontend
+}
@@ -0,0 +1,94 @@
+// src/internal/ui/headless.go
+// Front-end for running commands from a shell, which prints messages instead of drawing them
+
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"nextui-themes/internal/logging"
+)
+
+// HeadlessFrontend prints messages to standard output and backs out of every list, so
+// operations run from a script or over SSH never wait for a button
+const HeadlessFrontend = "headless"
+
+func init() {
+	RegisterFrontend(HeadlessFrontend, func() (Frontend, error) {
+		return &headlessFrontend{out: os.Stdout}, nil
+	})
+}
+
+// headlessFrontend writes what would be on screen to out
+type headlessFrontend struct {
+	out io.Writer
+}
+
+// Name identifies the front-end
+func (f *headlessFrontend) Name() string {
+	return HeadlessFrontend
+}
+
+// List backs out straight away: nobody is there to choose, so questions get their
+// cautious answer
+func (f *headlessFrontend) List(items string, format string, title string, options ListOptions) (string, int) {
+	logging.LogDebug("Headless: backing out of list '%s'", title)
+	return "", 2
+}
+
+// Message prints the message and returns without waiting
+func (f *headlessFrontend) Message(message string, timeout string) {
+	fmt.Fprintln(f.out, message)
+}
+
+// OpenMessage prints the message; the screen stays "open" until it is closed
+func (f *headlessFrontend) OpenMessage(message string, cancellable bool) (ScreenHandle, error) {
+	fmt.Fprintln(f.out, message)
+	return noScreen{}, nil
+}
+
+// OpenGalleryItem closes the item straight away, like backing out of it
+func (f *headlessFrontend) OpenGalleryItem(text string, image string, options GalleryOptions) (ScreenHandle, error) {
+	logging.LogDebug("Headless: backing out of gallery item '%s'", text)
+	done := make(chan int, 1)
+	done <- 2
+	return closedScreen(done), nil
+}
+
+// closedScreen is a screen that has already closed with the code in its channel
+type closedScreen chan int
+
+func (s closedScreen) Done() <-chan int { return s }
+func (s closedScreen) Close()           {}
+
+// OpenProgress prints the message, then a line whenever the progress reaches another tenth
+func (f *headlessFrontend) OpenProgress(message string, cancellable bool) (ProgressHandle, error) {
+	fmt.Fprintln(f.out, message)
+	return &headlessProgress{out: f.out, printed: -1}, nil
+}
+
+// headlessProgress prints progress as it passes each tenth of the total
+type headlessProgress struct {
+	noScreen
+	out     io.Writer
+	printed int // Last tenth printed
+}
+
+// Update prints the progress when it has moved on by a tenth since the last line
+func (p *headlessProgress) Update(progress Progress, cancelling bool) {
+	if progress.Total <= 0 {
+		return
+	}
+	done := progress.Done
+	if done > progress.Total {
+		done = progress.Total
+	}
+	tenth := done * 10 / progress.Total
+	if tenth == p.printed {
+		return
+	}
+	p.printed = tenth
+	fmt.Fprintf(p.out, "%d%% (%d/%d)\n", tenth*10, done, progress.Total)
+}
@@ -0,0 +1,197 @@
+// src/internal/ui/input_linux.go
+// Reads the device's buttons from the Linux input devices for the built-in front-end
+
+//go:build linux
+
+package ui
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+
+	"nextui-themes/internal/logging"
+)
+
+// Input event types and codes from linux/input-event-codes.h
+const (
+	evKey     = 0x01
+	evAbs     = 0x03
+	absHat0X  = 0x10
+	absHat0Y  = 0x11
+	keyUp     = 103
+	keyLeft   = 105
+	keyRight  = 106
+	keyDown   = 108
+	keyEnter  = 28
+	keyEsc    = 1
+	keySpace  = 57
+	keyPageUp = 104
+	keyPageDn = 109
+)
+
+// Held directions repeat after inputRepeatDelay, every inputRepeatInterval
+const (
+	inputRepeatDelay    = 400 * time.Millisecond
+	inputRepeatInterval = 80 * time.Millisecond
+)
+
+// evdevButtons maps key codes to buttons. The gamepad codes are the ones the TrimUI
+// controllers report; arrow keys, Enter and Escape help when trying the UI with a keyboard.
+var evdevButtons = map[uint16]button{
+	0x131:     buttonA,      // BTN_EAST
+	0x130:     buttonB,      // BTN_SOUTH
+	0x133:     buttonX,      // BTN_NORTH
+	0x134:     buttonY,      // BTN_WEST
+	0x136:     buttonL,      // BTN_TL
+	0x137:     buttonR,      // BTN_TR
+	0x13a:     buttonSelect, // BTN_SELECT
+	0x13b:     buttonStart,  // BTN_START
+	0x13c:     buttonMenu,   // BTN_MODE
+	0x220:     buttonUp,     // BTN_DPAD_UP
+	0x221:     buttonDown,   // BTN_DPAD_DOWN
+	0x222:     buttonLeft,   // BTN_DPAD_LEFT
+	0x223:     buttonRight,  // BTN_DPAD_RIGHT
+	keyUp:     buttonUp,
+	keyDown:   buttonDown,
+	keyLeft:   buttonLeft,
+	keyRight:  buttonRight,
+	keyEnter:  buttonA,
+	keyEsc:    buttonB,
+	keySpace:  buttonStart,
+	keyPageUp: buttonL,
+	keyPageDn: buttonR,
+}
+
+// inputReader turns the events of every input device into button presses
+type inputReader struct {
+	files   []*os.File
+	presses chan button
+	held    chan heldButton
+}
+
+// heldButton is a direction pressed (down) or released on a device
+type heldButton struct {
+	button button
+	down   bool
+}
+
+// openInput opens every /dev/input/event* device that can be read
+func openInput() (*inputReader, error) {
+	paths, _ := filepath.Glob("/dev/input/event*")
+	in := &inputReader{
+		presses: make(chan button, 16),
+		held:    make(chan heldButton, 16),
+	}
+
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			logging.LogDebug("Skipping input device %s: %v", path, err)
+			continue
+		}
+		in.files = append(in.files, file)
+		go in.read(file)
+	}
+	if len(in.files) == 0 {
+		return nil, fmt.Errorf("no readable input devices")
+	}
+
+	go in.repeat()
+	return in, nil
+}
+
+// read decodes the events of one device: struct input_event is a timeval of two longs,
+// then the type, code and value
+func (in *inputReader) read(file *os.File) {
+	word := int(unsafe.Sizeof(uintptr(0)))
+	event := make([]byte, 2*word+8)
+	for {
+		if _, err := file.Read(event); err != nil {
+			return
+		}
+		kind := binary.LittleEndian.Uint16(event[2*word:])
+		code := binary.LittleEndian.Uint16(event[2*word+2:])
+		value := int32(binary.LittleEndian.Uint32(event[2*word+4:]))
+
+		switch kind {
+		case evKey:
+			b, ok := evdevButtons[code]
+			if !ok || value == 2 {
+				// Keyboards repeat by themselves, held directions are repeated below
+				continue
+			}
+			if b.isDirection() {
+				in.held <- heldButton{b, value == 1}
+			} else if value == 1 {
+				in.presses <- b
+			}
+
+		case evAbs:
+			// D-pads reported as a hat: -1, 0 or 1 per axis
+			var negative, positive button
+			switch code {
+			case absHat0X:
+				negative, positive = buttonLeft, buttonRight
+			case absHat0Y:
+				negative, positive = buttonUp, buttonDown
+			default:
+				continue
+			}
+			in.held <- heldButton{negative, value < 0}
+			in.held <- heldButton{positive, value > 0}
+		}
+	}
+}
+
+// repeat sends a press when a direction goes down and repeats it while it is held
+func (in *inputReader) repeat() {
+	var current button
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		select {
+		case h := <-in.held:
+			switch {
+			case h.down && h.button != current:
+				current = h.button
+				in.presses <- current
+				timer.Reset(inputRepeatDelay)
+			case !h.down && h.button == current:
+				current = buttonNone
+				timer.Stop()
+			}
+
+		case <-timer.C:
+			if current != buttonNone {
+				// Drop repeats the screen hasn't caught up with
+				select {
+				case in.presses <- current:
+				default:
+				}
+				timer.Reset(inputRepeatInterval)
+			}
+		}
+	}
+}
+
+// buttons returns the channel button presses arrive on
+func (in *inputReader) buttons() <-chan button {
+	return in.presses
+}
+
+// drain drops presses that arrived while nothing was listening, such as the one that
+// opened the current screen
+func (in *inputReader) drain() {
+	for {
+		select {
+		case <-in.presses:
+		default:
+			return
+		}
+	}
+}
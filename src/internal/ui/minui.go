@@ -0,0 +1,284 @@
+// src/internal/ui/minui.go
+// The default front-end, which runs the minui-list and minui-presenter tools shipped with the pak
+
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// minUIFrontend runs minui-list and minui-presenter from the pak's folder
+type minUIFrontend struct {
+	dir string // Folder holding the minui-list and minui-presenter binaries
+}
+
+// newMinUIFrontend finds the tools in the current directory, where launch.sh starts the manager
+func newMinUIFrontend() (Frontend, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error getting current directory: %w", err)
+	}
+	return &minUIFrontend{dir: cwd}, nil
+}
+
+// Name identifies the front-end
+func (f *minUIFrontend) Name() string {
+	return DefaultFrontend
+}
+
+func (f *minUIFrontend) listPath() string {
+	return filepath.Join(f.dir, "minui-list")
+}
+
+func (f *minUIFrontend) presenterPath() string {
+	return filepath.Join(f.dir, "minui-presenter")
+}
+
+// List runs minui-list with the items written to a temporary file
+func (f *minUIFrontend) List(items string, format string, title string, options ListOptions) (string, int) {
+	// Create a temporary file for the list content
+	tempFile, err := os.CreateTemp("", "minui-list-input-*")
+	if err != nil {
+		logging.LogDebug("ERROR: Failed to create temp input file: %v", err)
+		return "", 1
+	}
+	inputPath := tempFile.Name()
+	defer os.Remove(inputPath)
+
+	// Write the list content to the temp file
+	if _, err := tempFile.WriteString(items); err != nil {
+		logging.LogDebug("ERROR: Failed to write to temp input file: %v", err)
+		tempFile.Close()
+		return "", 1
+	}
+	tempFile.Close()
+
+	// Create a temporary file for the output
+	tempOutFile, err := os.CreateTemp("", "minui-list-output-*")
+	if err != nil {
+		logging.LogDebug("ERROR: Failed to create temp output file: %v", err)
+		return "", 1
+	}
+	outputPath := tempOutFile.Name()
+	tempOutFile.Close()
+	defer os.Remove(outputPath)
+
+	// Build the command arguments
+	args := []string{"--format", format, "--title", title, "--file", inputPath, "--write-location", outputPath}
+	args = append(args, accessibleListArgs()...)
+
+	if options.CancelText != "" {
+		args = append(args, "--cancel-text", options.CancelText)
+	}
+
+	logging.LogDebug("minui-list args: %v", args)
+
+	cmd := exec.Command(f.listPath(), args...)
+
+	var stderrbuf bytes.Buffer
+	cmd.Stderr = &stderrbuf
+
+	// Run the command
+	err = cmd.Run()
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		logging.LogDebug("minui-list error: %v", err)
+	}
+
+	errValue := stderrbuf.String()
+	if errValue != "" {
+		logging.LogDebug("stderr: %s", errValue)
+	}
+
+	// Read the selection from the output file
+	var outValue string
+	if exitCode == 0 {
+		selectionBytes, err := os.ReadFile(outputPath)
+		if err != nil {
+			logging.LogDebug("ERROR: Failed to read selection from output file: %v", err)
+		} else {
+			outValue = strings.TrimSpace(string(selectionBytes))
+			logging.LogDebug("Selection read from file: '%s'", outValue)
+		}
+	}
+
+	return outValue, exitCode
+}
+
+// Message runs minui-presenter until the message times out or is dismissed
+func (f *minUIFrontend) Message(message string, timeout string) {
+	args := presenterArgs("--message", message, "--timeout", timeout)
+	cmd := exec.Command(f.presenterPath(), args...)
+	err := cmd.Run()
+
+	if err != nil {
+		logging.LogDebug("minui-presenter error: %v", err)
+		if cmd.ProcessState != nil && cmd.ProcessState.ExitCode() != 124 {
+			fmt.Printf("Failed to run minui-presenter: %v\n", err)
+		}
+	}
+}
+
+// OpenMessage starts minui-presenter with a message that stays until it is closed
+func (f *minUIFrontend) OpenMessage(message string, cancellable bool) (ScreenHandle, error) {
+	args := presenterArgs("--message", message, "--timeout", "-1")
+	if cancellable {
+		args = append(args, "--cancel-text", "CANCEL", "--cancel-show")
+	}
+	return startPresenter(exec.Command(f.presenterPath(), args...), nil)
+}
+
+// OpenGalleryItem starts minui-presenter showing one image from a temporary JSON file
+func (f *minUIFrontend) OpenGalleryItem(text string, image string, options GalleryOptions) (ScreenHandle, error) {
+	// Create JSON with single item
+	jsonData := map[string]interface{}{
+		"items": []map[string]interface{}{
+			{
+				"text":             text,
+				"background_image": image,
+				"show_pill":        true,
+				"alignment":        "top",
+			},
+		},
+		"selected": 0,
+	}
+
+	// Convert to JSON
+	jsonBytes, err := json.MarshalIndent(jsonData, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling JSON: %w", err)
+	}
+
+	// Create a temporary file for the JSON
+	tempFile, err := os.CreateTemp("", "gallery-item-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	jsonPath := tempFile.Name()
+
+	// Write JSON to temporary file
+	if _, err := tempFile.Write(jsonBytes); err != nil {
+		tempFile.Close()
+		os.Remove(jsonPath)
+		return nil, fmt.Errorf("failed to write to temp file: %w", err)
+	}
+	tempFile.Close()
+
+	// minui-presenter has four buttons, so a toggle takes over PREV; NEXT still wraps around
+	inactionButton, inactionText := "Y", "PREV"
+	if options.ToggleText != "" {
+		inactionButton, inactionText = "R1", options.ToggleText
+	}
+
+	// Select and back, X for the next item and Y for the previous one
+	args := []string{
+		"--file", jsonPath,
+		"--confirm-text", "SELECT",
+		"--confirm-show",
+		"--cancel-text", "BACK",
+		"--cancel-show",
+		"--action-button", "X",
+		"--action-text", "NEXT",
+		"--action-show",
+		"--inaction-button", inactionButton,
+		"--inaction-text", inactionText,
+		"--inaction-show",
+	}
+
+	screen, err := startPresenter(exec.Command(f.presenterPath(), args...), func() { os.Remove(jsonPath) })
+	if err != nil || options.ToggleText == "" {
+		return screen, err
+	}
+	return remapExitCode(screen, 5, GalleryToggleCode), nil
+}
+
+// remappedScreen passes a screen's exit code on, with one code swapped for another
+type remappedScreen struct {
+	ScreenHandle
+	done chan int
+}
+
+// remapExitCode reports exit code from of a screen as to
+func remapExitCode(screen ScreenHandle, from, to int) ScreenHandle {
+	r := &remappedScreen{ScreenHandle: screen, done: make(chan int, 1)}
+	go func() {
+		code := <-screen.Done()
+		if code == from {
+			code = to
+		}
+		r.done <- code
+	}()
+	return r
+}
+
+// Done receives the remapped exit code once the screen closes by itself
+func (r *remappedScreen) Done() <-chan int {
+	return r.done
+}
+
+// presenterProcess is one running minui-presenter
+type presenterProcess struct {
+	cmd    *exec.Cmd
+	exited chan int // Receives the exit code when the presenter closes
+	done   chan int // Passes the exit code on to whoever waits for the screen
+	stderr bytes.Buffer
+}
+
+// startPresenter starts a presenter in the background; cleanup runs once it has exited
+func startPresenter(cmd *exec.Cmd, cleanup func()) (ScreenHandle, error) {
+	p := &presenterProcess{
+		cmd:    cmd,
+		exited: make(chan int, 1),
+		done:   make(chan int, 1),
+	}
+	cmd.Stderr = &p.stderr
+	// Don't hang on the stderr pipe once the presenter is killed
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Start(); err != nil {
+		if cleanup != nil {
+			cleanup()
+		}
+		return nil, fmt.Errorf("error starting minui-presenter: %w", err)
+	}
+
+	go func() {
+		cmd.Wait()
+		if cleanup != nil {
+			cleanup()
+		}
+		if stderr := p.stderr.String(); stderr != "" {
+			logging.LogDebug("stderr: %s", stderr)
+		}
+		code := cmd.ProcessState.ExitCode()
+		p.exited <- code
+		p.done <- code
+	}()
+
+	return p, nil
+}
+
+// Done receives the exit code once the presenter closes by itself
+func (p *presenterProcess) Done() <-chan int {
+	return p.done
+}
+
+// Close kills the presenter if it is still running and waits for it to exit
+func (p *presenterProcess) Close() {
+	p.cmd.Process.Kill()
+	<-p.exited
+	p.exited <- -1
+}
@@ -1,15 +1,12 @@
 // src/internal/ui/presenter.go
-// Utilities for using minui-presenter
+// Image galleries shown one item at a time
 
 package ui
 
 import (
-	"bytes"
-	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
+	"sync"
+	"time"
 
 	"nextui-themes/internal/logging"
 )
@@ -20,8 +17,35 @@ type GalleryItem struct {
 	BackgroundImage string
 }
 
-// DisplayImageGallery displays a gallery of images using minui-presenter
-func DisplayImageGallery(items []GalleryItem, title string) (string, int) {
+// galleryPreviewDelay debounces live previews so fast scrolling doesn't thrash the hardware
+const galleryPreviewDelay = 400 * time.Millisecond
+
+// galleryResume remembers the item each gallery was showing when it closed for a refresh
+var galleryResume = make(map[string]string)
+
+// GalleryToggleCode is returned by galleries with a toggle button when it is pressed
+const GalleryToggleCode = 6
+
+// DisplayImageGallery displays a gallery of images, one at a time.
+// If watchDirs are given, the gallery closes with GalleryRefreshCode when one of them changes.
+func DisplayImageGallery(items []GalleryItem, title string, watchDirs ...string) (string, int) {
+	return displayGallery(items, title, nil, GalleryOptions{}, watchDirs...)
+}
+
+// DisplayImageGalleryWithPreview displays a gallery and calls preview for the item
+// under the cursor once it has been shown for galleryPreviewDelay
+func DisplayImageGalleryWithPreview(items []GalleryItem, title string, preview func(item GalleryItem), watchDirs ...string) (string, int) {
+	return displayGallery(items, title, preview, GalleryOptions{}, watchDirs...)
+}
+
+// DisplayImageGalleryWithToggle displays a gallery with a shoulder button labelled
+// toggleText, which closes it with GalleryToggleCode so the caller can switch what it shows
+func DisplayImageGalleryWithToggle(items []GalleryItem, title string, toggleText string) (string, int) {
+	return displayGallery(items, title, nil, GalleryOptions{ToggleText: toggleText})
+}
+
+// displayGallery shows the items one at a time, or as a grid on front-ends that have one
+func displayGallery(items []GalleryItem, title string, preview func(item GalleryItem), options GalleryOptions, watchDirs ...string) (string, int) {
 	logging.LogDebug("Displaying image gallery with %d items and title: %s", len(items), title)
 
 	if len(items) == 0 {
@@ -30,15 +54,58 @@ func DisplayImageGallery(items []GalleryItem, title string) (string, int) {
 		return "", 1
 	}
 
-	// Get current directory
-	cwd, err := os.Getwd()
-	if err != nil {
-		logging.LogDebug("Error getting current directory: %v", err)
-		return "", 1
+	// Keep track of which item we're showing, resuming where a refresh left off
+	currentIndex := 0
+	if resumeText, ok := galleryResume[title]; ok {
+		delete(galleryResume, title)
+		for i, item := range items {
+			if item.Text == resumeText {
+				currentIndex = i
+				break
+			}
+		}
 	}
 
-	// Keep track of which item we're showing
-	currentIndex := 0
+	var watcher *DirWatcher
+	if len(watchDirs) > 0 {
+		watcher = NewDirWatcher(watchDirs...)
+	}
+
+	// Front-ends with a thumbnail grid show the whole gallery at once. Live previews need
+	// one item on screen at a time and the grid pages with the shoulder buttons, so
+	// galleries with either keep the single-item view.
+	if grid, ok := CurrentFrontend().(ThumbnailFrontend); ok && preview == nil && options.ToggleText == "" {
+		var changed func() bool
+		if watcher != nil {
+			changed = watcher.Changed
+		}
+
+		index, exitCode := grid.Grid(items, title, currentIndex, changed)
+		switch exitCode {
+		case 0:
+			logging.LogDebug("User selected item: %s (index: %d)", items[index].Text, index)
+			return items[index].Text, 0
+		case GalleryRefreshCode:
+			logging.LogDebug("Watched directory changed, refreshing gallery: %s", title)
+			galleryResume[title] = items[index].Text
+			return "", GalleryRefreshCode
+		}
+		return "", exitCode
+	}
+
+	// Debounced preview of the current item, always settled before returning
+	var previewMu sync.Mutex
+	var previewTimer *time.Timer
+	previewShown := -1
+	galleryClosed := false
+	defer func() {
+		if previewTimer != nil {
+			previewTimer.Stop()
+		}
+		previewMu.Lock()
+		galleryClosed = true
+		previewMu.Unlock()
+	}()
 
 	for {
 		// Ensure index is valid
@@ -51,81 +118,36 @@ func DisplayImageGallery(items []GalleryItem, title string) (string, int) {
 		// Get current item
 		currentItem := items[currentIndex]
 
-		// Create JSON with single item
-		jsonData := map[string]interface{}{
-			"items": []map[string]interface{}{
-				{
-					"text":             fmt.Sprintf("%s (%d/%d)", currentItem.Text, currentIndex+1, len(items)),
-					"background_image": currentItem.BackgroundImage,
-					"show_pill":        true,
-					"alignment":        "top",
-				},
-			},
-			"selected": 0,
-		}
-
-		// Convert to JSON
-		jsonBytes, err := json.MarshalIndent(jsonData, "", "  ")
-		if err != nil {
-			logging.LogDebug("Error marshaling JSON: %v", err)
-			return "", 1
+		if preview != nil {
+			if previewTimer != nil {
+				previewTimer.Stop()
+			}
+			previewIndex := currentIndex
+			previewTimer = time.AfterFunc(galleryPreviewDelay, func() {
+				previewMu.Lock()
+				defer previewMu.Unlock()
+				if galleryClosed || previewShown == previewIndex {
+					return
+				}
+				previewShown = previewIndex
+				preview(items[previewIndex])
+			})
 		}
 
-		// Create a temporary file for the JSON
-		tempFile, err := os.CreateTemp("", "gallery-item-*.json")
+		// Show this item with navigation buttons
+		screen, err := CurrentFrontend().OpenGalleryItem(
+			fmt.Sprintf("%s (%d/%d)", currentItem.Text, currentIndex+1, len(items)),
+			currentItem.BackgroundImage, options)
 		if err != nil {
-			logging.LogDebug("ERROR: Failed to create temp file: %v", err)
-			return "", 1
-		}
-		jsonPath := tempFile.Name()
-		defer os.Remove(jsonPath)
-
-		// Write JSON to temporary file
-		if _, err := tempFile.Write(jsonBytes); err != nil {
-			logging.LogDebug("ERROR: Failed to write to temp file: %v", err)
-			tempFile.Close()
+			logging.LogDebug("Error showing gallery item: %v", err)
 			return "", 1
 		}
-		tempFile.Close()
-
-		// Execute minui-presenter for this item with navigation buttons
-		args := []string{
-			"--file", jsonPath,
-			"--confirm-text", "SELECT",
-			"--confirm-show",
-			"--cancel-text", "BACK",
-			"--cancel-show",
-		}
-
-		// Add action button for "next" navigation
-		args = append(args,
-			"--action-button", "X",
-			"--action-text", "NEXT",
-			"--action-show")
-
-		// Add inaction button for "previous" navigation
-		args = append(args,
-			"--inaction-button", "Y",
-			"--inaction-text", "PREV",
-			"--inaction-show")
 
-		minuiPresenterPath := filepath.Join(cwd, "minui-presenter")
-		cmd := exec.Command(minuiPresenterPath, args...)
-
-		var stderr bytes.Buffer
-		cmd.Stderr = &stderr
-
-		// Run minui-presenter
-		err = cmd.Run()
-		exitCode := 0
-		if err != nil {
-			exitCode = cmd.ProcessState.ExitCode()
-		}
-
-		// Log stderr output if any
-		stderrOutput := stderr.String()
-		if stderrOutput != "" {
-			logging.LogDebug("stderr: %s", stderrOutput)
+		exitCode, refreshed := waitGalleryItem(screen, watcher)
+		if refreshed {
+			logging.LogDebug("Watched directory changed, refreshing gallery: %s", title)
+			galleryResume[title] = currentItem.Text
+			return "", GalleryRefreshCode
 		}
 
 		logging.LogDebug("Exit code: %d for item %d: %s", exitCode, currentIndex, currentItem.Text)
@@ -148,6 +170,10 @@ func DisplayImageGallery(items []GalleryItem, title string) (string, int) {
 			logging.LogDebug("User pressed PREV")
 			currentIndex--
 
+		case GalleryToggleCode: // Shoulder button - the caller switches what the gallery shows
+			logging.LogDebug("User pressed %s", options.ToggleText)
+			return "", GalleryToggleCode
+
 		case 124, 130, 143: // Special exit codes
 			return "", exitCode
 
@@ -157,3 +183,27 @@ func DisplayImageGallery(items []GalleryItem, title string) (string, int) {
 		}
 	}
 }
+
+// waitGalleryItem waits for a gallery item to close and returns its exit code. With a
+// watcher, the item is closed early and refreshed is true when a watched directory changes.
+func waitGalleryItem(screen ScreenHandle, watcher *DirWatcher) (exitCode int, refreshed bool) {
+	if watcher == nil {
+		return <-screen.Done(), false
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case exitCode := <-screen.Done():
+			return exitCode, false
+
+		case <-ticker.C:
+			if watcher.Changed() {
+				screen.Close()
+				return 0, true
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// src/internal/ui/progress.go
+// Animated progress screen for operations running in the background
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"nextui-themes/internal/logging"
+)
+
+// Progress is an update sent by a background operation
+type Progress struct {
+	Done  int    // Steps finished so far
+	Total int    // Total steps, 0 when unknown
+	Step  string // What is being worked on
+}
+
+// ErrCancelled is returned by operations the user cancelled from the progress screen
+var ErrCancelled = errors.New("cancelled by user")
+
+// progressFrameInterval is how often the progress screen may redraw. Reopened screens
+// only redraw when there is new progress, so each redraw is at most this far apart.
+const progressFrameInterval = 500 * time.Millisecond
+
+// progressBarWidth is the number of cells in the progress bar
+const progressBarWidth = 20
+
+// Spinner frames, advanced on every redraw so repeated steps still show movement
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// progressScreen holds messages shown by the operation while the progress screen is up.
+// They would fight with the progress screen for the display, so they are shown afterwards.
+var progressScreen struct {
+	sync.Mutex
+	active  bool
+	pending []string
+}
+
+// deferProgressMessage queues a message for after the progress screen when one is up
+func deferProgressMessage(message string) bool {
+	progressScreen.Lock()
+	defer progressScreen.Unlock()
+
+	if !progressScreen.active {
+		return false
+	}
+	progressScreen.pending = append(progressScreen.pending, message)
+	return true
+}
+
+// renderProgress builds the progress screen text for a spinner frame
+func renderProgress(message string, progress Progress, frame int, cancelling bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s", spinnerFrames[frame%len(spinnerFrames)], message)
+
+	if progress.Total > 0 {
+		done := progress.Done
+		if done > progress.Total {
+			done = progress.Total
+		}
+		filled := done * progressBarWidth / progress.Total
+		fmt.Fprintf(&b, "\n[%s%s] %d%%",
+			strings.Repeat("#", filled), strings.Repeat(".", progressBarWidth-filled),
+			done*100/progress.Total)
+	}
+
+	switch {
+	case cancelling:
+		b.WriteString("\nCancelling...")
+	case progress.Step != "":
+		b.WriteString("\n" + progress.Step)
+	}
+
+	return b.String()
+}
+
+// openProgressScreen shows a progress frame, with a cancel button unless cancellable is false
+func openProgressScreen(frontend Frontend, text string, cancellable bool) ScreenHandle {
+	screen, err := frontend.OpenMessage(text, cancellable)
+	if err != nil {
+		logging.LogDebug("Error showing progress: %v", err)
+		// Nothing to wait for; a screen that never closes keeps the operation running
+		return noScreen{}
+	}
+	return screen
+}
+
+// ShowProgress runs an operation in the background while an animated progress screen
+// shows the updates it sends. Pressing B closes the cancel channel; the operation should
+// stop as soon as it notices and return ErrCancelled.
+func ShowProgress(message string, operation func(progress chan<- Progress, cancel <-chan struct{}) error) error {
+	logging.LogDebug("Showing progress: %s", message)
+
+	frontend := CurrentFrontend()
+
+	progressScreen.Lock()
+	progressScreen.active = true
+	progressScreen.pending = nil
+	progressScreen.Unlock()
+
+	// Buffered so the operation never waits on a redraw
+	progress := make(chan Progress, 64)
+	cancel := make(chan struct{})
+	done := make(chan error, 1)
+
+	go func() {
+		done <- operation(progress, cancel)
+	}()
+
+	var latest Progress
+	frame := 0
+	cancelling := false
+
+	// Front-ends with their own progress screen update it in place instead of reopening it
+	var live ProgressHandle
+	if progressFrontend, ok := frontend.(ProgressFrontend); ok {
+		handle, err := progressFrontend.OpenProgress(message, true)
+		if err != nil {
+			logging.LogDebug("Error showing progress: %v", err)
+		} else {
+			live = handle
+		}
+	}
+
+	// Other front-ends show a message that is reopened to redraw it, so the same presenter
+	// stays up until the progress or cancel state actually changes
+	var screen ScreenHandle = live
+	drawn, drawnCancelling := latest, cancelling
+	if live == nil {
+		screen = openProgressScreen(frontend, renderProgress(message, latest, frame, cancelling), true)
+	}
+
+	ticker := time.NewTicker(progressFrameInterval)
+	defer ticker.Stop()
+
+	var operationErr error
+	for running := true; running; {
+		select {
+		case operationErr = <-done:
+			screen.Close()
+			running = false
+
+		case update := <-progress:
+			latest = update
+			if live != nil {
+				live.Update(latest, cancelling)
+			}
+
+		case exitCode := <-screen.Done():
+			// The screen only closes by itself when the user pressed cancel
+			if exitCode == 2 && !cancelling {
+				logging.LogDebug("User cancelled: %s", message)
+				cancelling = true
+				close(cancel)
+			}
+			if live != nil {
+				live.Update(latest, cancelling)
+				continue
+			}
+			drawn, drawnCancelling = latest, cancelling
+			screen = openProgressScreen(frontend, renderProgress(message, latest, frame, cancelling), !cancelling)
+
+		case <-ticker.C:
+			if live != nil || (latest == drawn && cancelling == drawnCancelling) {
+				continue
+			}
+			frame++
+			drawn, drawnCancelling = latest, cancelling
+			screen.Close()
+			screen = openProgressScreen(frontend, renderProgress(message, latest, frame, cancelling), !cancelling)
+		}
+	}
+
+	progressScreen.Lock()
+	progressScreen.active = false
+	pending := progressScreen.pending
+	progressScreen.pending = nil
+	progressScreen.Unlock()
+
+	for _, text := range pending {
+		ShowMessage(text, "3")
+	}
+
+	return operationErr
+}
@@ -0,0 +1,503 @@
+// src/internal/ui/qrcode.go
+// Minimal QR code encoder (byte mode, low error correction, versions 1-10) for on-screen links
+
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"nextui-themes/internal/logging"
+)
+
+// qrVersion describes the codeword layout of one QR version at error correction level L
+type qrVersion struct {
+	codewords int   // Total codewords in the symbol
+	eccLen    int   // Error correction codewords per block
+	blocks    int   // Number of blocks
+	align     []int // Alignment pattern centers
+}
+
+// qrVersions holds versions 1-10; index 0 is version 1. Up to 271 bytes fit, plenty for a link.
+var qrVersions = []qrVersion{
+	{26, 7, 1, nil},
+	{44, 10, 1, []int{6, 18}},
+	{70, 15, 1, []int{6, 22}},
+	{100, 20, 1, []int{6, 26}},
+	{134, 26, 1, []int{6, 30}},
+	{172, 18, 2, []int{6, 34}},
+	{196, 20, 2, []int{6, 22, 38}},
+	{242, 24, 2, []int{6, 24, 42}},
+	{292, 30, 2, []int{6, 26, 46}},
+	{346, 18, 4, []int{6, 28, 50}},
+}
+
+// qrCode is an encoded symbol; modules[y][x] is true for dark
+type qrCode struct {
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+// gfMul multiplies two elements of GF(256) with the QR polynomial 0x11D
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the Reed-Solomon generator polynomial of the given degree
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = gfMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+// rsRemainder returns the error correction codewords for a block of data
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMul(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// qrDataCodewords builds the padded data codewords for text in byte mode
+func qrDataCodewords(text []byte, version int, capacity int) []byte {
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>uint(i))&1 == 1)
+		}
+	}
+
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+
+	appendBits(0x4, 4) // Byte mode
+	appendBits(len(text), countBits)
+	for _, b := range text {
+		appendBits(int(b), 8)
+	}
+
+	// Terminator, then pad to a whole byte
+	for i := 0; i < 4 && len(bits) < capacity*8; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+
+	data := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << uint(7-j)
+			}
+		}
+		data = append(data, b)
+	}
+
+	// Alternating pad bytes fill the rest
+	for pad := byte(0xEC); len(data) < capacity; pad ^= 0xEC ^ 0x11 {
+		data = append(data, pad)
+	}
+	return data
+}
+
+// qrInterleave splits data into blocks, adds error correction and interleaves the result
+func qrInterleave(data []byte, v qrVersion) []byte {
+	shortBlocks := v.blocks - v.codewords%v.blocks
+	shortLen := v.codewords/v.blocks - v.eccLen
+	divisor := rsDivisor(v.eccLen)
+
+	var dataBlocks, eccBlocks [][]byte
+	offset := 0
+	for i := 0; i < v.blocks; i++ {
+		length := shortLen
+		if i >= shortBlocks {
+			length++
+		}
+		block := data[offset : offset+length]
+		offset += length
+		dataBlocks = append(dataBlocks, block)
+		eccBlocks = append(eccBlocks, rsRemainder(block, divisor))
+	}
+
+	result := make([]byte, 0, v.codewords)
+	for i := 0; i <= shortLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < v.eccLen; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// setFunction places a function module, which masking and data placement skip
+func (q *qrCode) setFunction(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns places the finder, timing and alignment patterns
+func (q *qrCode) drawFunctionPatterns(version int, v qrVersion) {
+	for i := 0; i < q.size; i++ {
+		q.setFunction(6, i, i%2 == 0)
+		q.setFunction(i, 6, i%2 == 0)
+	}
+
+	finder := func(cx, cy int) {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := cx+dx, cy+dy
+				if x < 0 || y < 0 || x >= q.size || y >= q.size {
+					continue
+				}
+				dist := max(qrAbs(dx), qrAbs(dy))
+				q.setFunction(x, y, dist != 2 && dist != 4)
+			}
+		}
+	}
+	finder(3, 3)
+	finder(q.size-4, 3)
+	finder(3, q.size-4)
+
+	last := len(v.align) - 1
+	for i, cx := range v.align {
+		for j, cy := range v.align {
+			// Skip the three corners taken by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFunction(cx+dx, cy+dy, max(qrAbs(dx), qrAbs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; the real bits are drawn once the mask is chosen
+	q.drawFormatBits(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 == 1
+			a, b := q.size-11+i%3, i/3
+			q.setFunction(a, b, dark)
+			q.setFunction(b, a, dark)
+		}
+	}
+}
+
+// drawFormatBits places the error correction level (L) and mask in both format areas
+func (q *qrCode) drawFormatBits(mask int) {
+	data := 1<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFunction(8, i, bit(i))
+	}
+	q.setFunction(8, 7, bit(6))
+	q.setFunction(8, 8, bit(7))
+	q.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFunction(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFunction(8, q.size-15+i, bit(i))
+	}
+	q.setFunction(8, q.size-8, true) // Always dark
+}
+
+// drawCodewords places the data in the zigzag order, leaving function modules alone
+func (q *qrCode) drawCodewords(data []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.size - 1 - vert
+				}
+				if q.isFunction[y][x] {
+					continue
+				}
+				// Remainder bits past the data stay light
+				if i < len(data)*8 {
+					q.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask flips the data modules selected by one of the eight mask patterns
+func (q *qrCode) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !q.isFunction[y][x] {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores how hard the symbol is to scan; the mask with the lowest score is used
+func (q *qrCode) penalty() int {
+	score := 0
+	at := func(x, y int, horizontal bool) bool {
+		if horizontal {
+			return q.modules[y][x]
+		}
+		return q.modules[x][y]
+	}
+
+	finderLike := []bool{true, false, true, true, true, false, true}
+	for _, horizontal := range []bool{true, false} {
+		for y := 0; y < q.size; y++ {
+			run := 1
+			for x := 1; x <= q.size; x++ {
+				if x < q.size && at(x, y, horizontal) == at(x-1, y, horizontal) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					score += 3 + run - 5
+				}
+				run = 1
+			}
+
+			// Finder-like patterns with four light modules on one side
+			for x := 0; x+7 <= q.size; x++ {
+				match := true
+				for k, dark := range finderLike {
+					if at(x+k, y, horizontal) != dark {
+						match = false
+						break
+					}
+				}
+				if !match {
+					continue
+				}
+				lightBefore, lightAfter := x >= 4, x+11 <= q.size
+				for k := 1; k <= 4; k++ {
+					if lightBefore && at(x-k, y, horizontal) {
+						lightBefore = false
+					}
+					if lightAfter && at(x+6+k, y, horizontal) {
+						lightAfter = false
+					}
+				}
+				if lightBefore || lightAfter {
+					score += 40
+				}
+			}
+		}
+	}
+
+	dark := 0
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.size && y+1 < q.size {
+				c := q.modules[y][x]
+				if q.modules[y][x+1] == c && q.modules[y+1][x] == c && q.modules[y+1][x+1] == c {
+					score += 3
+				}
+			}
+		}
+	}
+
+	total := q.size * q.size
+	score += qrAbs(dark*100/total-50) / 5 * 10
+	return score
+}
+
+// encodeQR encodes text into the smallest version that fits
+func encodeQR(text string) (*qrCode, error) {
+	data := []byte(text)
+
+	for index, v := range qrVersions {
+		version := index + 1
+		capacity := v.codewords - v.eccLen*v.blocks
+		countBits := 8
+		if version >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(data)*8 > capacity*8 {
+			continue
+		}
+
+		codewords := qrInterleave(qrDataCodewords(data, version, capacity), v)
+
+		size := 17 + 4*version
+		q := &qrCode{size: size}
+		q.modules = make([][]bool, size)
+		q.isFunction = make([][]bool, size)
+		for y := range q.modules {
+			q.modules[y] = make([]bool, size)
+			q.isFunction[y] = make([]bool, size)
+		}
+
+		q.drawFunctionPatterns(version, v)
+		q.drawCodewords(codewords)
+
+		bestMask, bestScore := 0, -1
+		for mask := 0; mask < 8; mask++ {
+			q.applyMask(mask)
+			q.drawFormatBits(mask)
+			if score := q.penalty(); bestScore < 0 || score < bestScore {
+				bestMask, bestScore = mask, score
+			}
+			q.applyMask(mask) // Masks are their own inverse
+		}
+		q.applyMask(bestMask)
+		q.drawFormatBits(bestMask)
+
+		return q, nil
+	}
+
+	return nil, fmt.Errorf("text too long for a QR code (%d bytes)", len(data))
+}
+
+// qrAbs returns the absolute value of an int
+func qrAbs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// WriteQRCode renders text as a QR code PNG sized for the screen, with a white border
+func WriteQRCode(text string, outputPath string, pixelSize int) error {
+	q, err := encodeQR(text)
+	if err != nil {
+		return err
+	}
+
+	// Four light modules of quiet zone on every side
+	const border = 4
+	scale := pixelSize / (q.size + 2*border)
+	if scale < 1 {
+		scale = 1
+	}
+	dim := (q.size + 2*border) * scale
+
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	for y := 0; y < dim; y++ {
+		for x := 0; x < dim; x++ {
+			mx, my := x/scale-border, y/scale-border
+			shade := color.Gray{Y: 255}
+			if mx >= 0 && my >= 0 && mx < q.size && my < q.size && q.modules[my][mx] {
+				shade = color.Gray{Y: 0}
+			}
+			img.SetGray(x, y, shade)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("error creating QR code directory: %w", err)
+	}
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("error creating QR code image: %w", err)
+	}
+	defer file.Close()
+
+	return png.Encode(file, img)
+}
+
+// ShowQRCode shows a link as a QR code so it can be opened on a phone
+func ShowQRCode(link string, caption string) {
+	logging.LogDebug("Showing QR code for: %s", link)
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		logging.LogDebug("Error getting current directory: %v", err)
+		return
+	}
+
+	imagePath := filepath.Join(cwd, ".cache", "qrcode.png")
+	if err := WriteQRCode(link, imagePath, 480); err != nil {
+		logging.LogDebug("Error creating QR code: %v", err)
+		ShowMessage(fmt.Sprintf("Open this link on your phone: %s", link), "10")
+		return
+	}
+	defer os.Remove(imagePath)
+
+	DisplayImageGallery([]GalleryItem{{Text: caption, BackgroundImage: imagePath}}, caption)
+}
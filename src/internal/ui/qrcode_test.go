@@ -0,0 +1,496 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Reference values from ISO/IEC 18004. The encoder only writes error correction level L.
+
+// qrReferenceBlocks is the error correction codewords per block and the data codewords of
+// each block at level L, by version
+var qrReferenceBlocks = map[int]struct {
+	ecc    int
+	blocks []int
+}{
+	1:  {7, []int{19}},
+	2:  {10, []int{34}},
+	3:  {15, []int{55}},
+	4:  {20, []int{80}},
+	5:  {26, []int{108}},
+	6:  {18, []int{68, 68}},
+	7:  {20, []int{78, 78}},
+	8:  {24, []int{97, 97}},
+	9:  {30, []int{116, 116}},
+	10: {18, []int{68, 68, 69, 69}},
+}
+
+// qrReferenceCapacity is how many bytes fit in each version at level L
+var qrReferenceCapacity = []int{17, 32, 53, 78, 106, 134, 154, 192, 230, 271}
+
+// qrReferenceAlignment is the row and column of alignment pattern centers, by version
+var qrReferenceAlignment = map[int][]int{
+	2: {6, 18}, 3: {6, 22}, 4: {6, 26}, 5: {6, 30}, 6: {6, 34},
+	7: {6, 22, 38}, 8: {6, 24, 42}, 9: {6, 26, 46}, 10: {6, 28, 50},
+}
+
+// qrReferenceRemainder is the number of modules left over after the codewords, by version
+var qrReferenceRemainder = map[int]int{1: 0, 2: 7, 3: 7, 4: 7, 5: 7, 6: 7, 7: 0, 8: 0, 9: 0, 10: 0}
+
+// qrReferenceFormat is the masked format information of level L, by mask
+var qrReferenceFormat = []string{
+	"111011111000100", "111001011110011", "111110110101010", "111100010011101",
+	"110011000101111", "110001100011000", "110110001000001", "110100101110110",
+}
+
+// qrReferenceVersion is the version information of versions 7 and up
+var qrReferenceVersion = map[int]string{
+	7:  "000111110010010100",
+	8:  "001000010110111100",
+	9:  "001001101010011001",
+	10: "001010010011010011",
+}
+
+// gfExp and gfLog are antilog and log tables of GF(256), built apart from gfMul to check it
+var gfExp, gfLog = func() ([512]byte, [256]int) {
+	var exp [512]byte
+	var log [256]int
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = byte(x), byte(x)
+		log[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	return exp, log
+}()
+
+// rsSyndromesZero reports whether a block of data and error correction codewords is a valid
+// Reed-Solomon codeword: the polynomial it forms has roots at α^0 to α^(ecc-1)
+func rsSyndromesZero(block []byte, ecc int) bool {
+	for i := 0; i < ecc; i++ {
+		var s byte
+		for _, b := range block {
+			if s != 0 {
+				s = gfExp[gfLog[s]+i]
+			}
+			s ^= b
+		}
+		if s != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// qrReferenceFunction reports whether a module is part of a function pattern, worked out
+// from the standard rather than from the encoder
+func qrReferenceFunction(version, x, y int) bool {
+	size := 17 + 4*version
+	switch {
+	case x < 9 && y < 9, x >= size-8 && y < 9, x < 9 && y >= size-8:
+		return true // Finders, separators and format information
+	case x == 6 || y == 6:
+		return true // Timing patterns
+	case version >= 7 && x >= size-11 && x < size-8 && y < 6, version >= 7 && y >= size-11 && y < size-8 && x < 6:
+		return true // Version information
+	}
+	centers := qrReferenceAlignment[version]
+	for _, cy := range centers {
+		for _, cx := range centers {
+			if (cx == 6 && cy == 6) || (cx == 6 && cy == size-7) || (cx == size-7 && cy == 6) {
+				continue
+			}
+			if qrAbs(x-cx) <= 2 && qrAbs(y-cy) <= 2 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// qrReferenceMask reports whether mask flips the module at row i, column j
+func qrReferenceMask(mask, i, j int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return (i*j)%2+(i*j)%3 == 0
+	case 6:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	case 7:
+		return ((i+j)%2+(i*j)%3)%2 == 0
+	}
+	return false
+}
+
+// readBits reads modules at (x, y) positions as a string of 0s and 1s
+func (q *qrCode) readBits(positions [][2]int) string {
+	var b strings.Builder
+	for _, p := range positions {
+		if q.modules[p[1]][p[0]] {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// formatPositions returns both copies of the format information, most significant bit first
+func formatPositions(size int) ([][2]int, [][2]int) {
+	first := [][2]int{{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8}, {8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0}}
+	var second [][2]int
+	for i := 0; i < 7; i++ {
+		second = append(second, [2]int{8, size - 1 - i})
+	}
+	for i := 0; i < 8; i++ {
+		second = append(second, [2]int{size - 8 + i, 8})
+	}
+	return first, second
+}
+
+// decodeQR reads the text back from a symbol using only the standard, checking every
+// structure along the way
+func decodeQR(t *testing.T, q *qrCode) string {
+	t.Helper()
+
+	version := (q.size - 17) / 4
+	if version < 1 || version > 10 || q.size != 17+4*version {
+		t.Fatalf("symbol size %d isn't a version 1-10 size", q.size)
+	}
+
+	// Finder patterns, timing patterns and the dark module
+	for _, corner := range [][2]int{{0, 0}, {q.size - 7, 0}, {0, q.size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(qrAbs(dx-3), qrAbs(dy-3))
+				if q.modules[corner[1]+dy][corner[0]+dx] != (ring != 2) {
+					t.Fatalf("finder pattern at %v is wrong at (%d, %d)", corner, dx, dy)
+				}
+			}
+		}
+	}
+	for i := 8; i < q.size-8; i++ {
+		if q.modules[6][i] != (i%2 == 0) || q.modules[i][6] != (i%2 == 0) {
+			t.Fatalf("timing pattern is wrong at %d", i)
+		}
+	}
+	if !q.modules[q.size-8][8] {
+		t.Fatal("dark module is light")
+	}
+
+	// Format information: both copies the same, level L, with a valid mask
+	first, second := formatPositions(q.size)
+	format := q.readBits(first)
+	if other := q.readBits(second); other != format {
+		t.Fatalf("format copies differ: %s and %s", format, other)
+	}
+	mask := -1
+	for m, want := range qrReferenceFormat {
+		if format == want {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %s isn't level L with any mask", format)
+	}
+
+	if want, ok := qrReferenceVersion[version]; ok {
+		var topRight, bottomLeft [][2]int
+		for i := 17; i >= 0; i-- {
+			topRight = append(topRight, [2]int{q.size - 11 + i%3, i / 3})
+			bottomLeft = append(bottomLeft, [2]int{i / 3, q.size - 11 + i%3})
+		}
+		if got := q.readBits(topRight); got != want {
+			t.Errorf("top right version information = %s, want %s", got, want)
+		}
+		if got := q.readBits(bottomLeft); got != want {
+			t.Errorf("bottom left version information = %s, want %s", got, want)
+		}
+	}
+
+	// Data modules in the zigzag order, unmasked
+	var bits []bool
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		upward := ((q.size-1-right)/2)%2 == 0
+		if right < 6 {
+			upward = ((q.size-2-right)/2)%2 == 0
+		}
+		for vert := 0; vert < q.size; vert++ {
+			y := vert
+			if upward {
+				y = q.size - 1 - vert
+			}
+			for _, x := range []int{right, right - 1} {
+				if !qrReferenceFunction(version, x, y) {
+					bits = append(bits, q.modules[y][x] != qrReferenceMask(mask, y, x))
+				}
+			}
+		}
+	}
+
+	layout := qrReferenceBlocks[version]
+	total := 0
+	for _, n := range layout.blocks {
+		total += n + layout.ecc
+	}
+	if len(bits) != total*8+qrReferenceRemainder[version] {
+		t.Fatalf("%d data modules, want %d codewords and %d remainder bits", len(bits), total, qrReferenceRemainder[version])
+	}
+	for i, bit := range bits[total*8:] {
+		if bit {
+			t.Errorf("remainder bit %d is dark", i)
+		}
+	}
+
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for j := 0; j < 8; j++ {
+			if bits[i*8+j] {
+				codewords[i] |= 1 << (7 - j)
+			}
+		}
+	}
+
+	// Deinterleave into blocks and check each is a valid Reed-Solomon codeword
+	blocks := make([][]byte, len(layout.blocks))
+	at := 0
+	for i := 0; i < layout.blocks[len(layout.blocks)-1]; i++ {
+		for b, n := range layout.blocks {
+			if i < n {
+				blocks[b] = append(blocks[b], codewords[at])
+				at++
+			}
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		data = append(data, block...)
+	}
+	for i := 0; i < layout.ecc; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[at])
+			at++
+		}
+	}
+	for b, block := range blocks {
+		if !rsSyndromesZero(block, layout.ecc) {
+			t.Fatalf("block %d fails its error correction check", b)
+		}
+	}
+
+	// Byte mode segment, terminator and pad codewords
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if data[(pos+i)/8]>>(7-(pos+i)%8)&1 == 1 {
+				v |= 1
+			}
+		}
+		pos += n
+		return v
+	}
+	if mode := read(4); mode != 0x4 {
+		t.Fatalf("mode = %04b, want byte mode", mode)
+	}
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	text := make([]byte, read(countBits))
+	if pos+len(text)*8 > len(data)*8 {
+		t.Fatalf("character count %d runs past the data", len(text))
+	}
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+
+	if rest := len(data)*8 - pos; rest > 0 {
+		if terminator := read(min(rest, 4)); terminator != 0 {
+			t.Errorf("terminator = %b, want zeros", terminator)
+		}
+	}
+	if pos%8 != 0 && read(8-pos%8) != 0 {
+		t.Error("bits padding the last codeword aren't zero")
+	}
+	for i, pad := 0, byte(0xEC); pos/8 < len(data); i, pad = i+1, pad^0xEC^0x11 {
+		if got := byte(read(8)); got != pad {
+			t.Fatalf("pad codeword %d = %#x, want %#x", i, got, pad)
+		}
+	}
+	return string(text)
+}
+
+func TestEncodeQRVersions(t *testing.T) {
+	for index, capacity := range qrReferenceCapacity {
+		version := index + 1
+		smallest := 1
+		if index > 0 {
+			smallest = qrReferenceCapacity[index-1] + 1
+		}
+
+		// The shortest and longest text of each version, with every byte value
+		for _, length := range []int{smallest, capacity} {
+			text := make([]byte, length)
+			for i := range text {
+				text[i] = byte(i*37 + version)
+			}
+
+			t.Run(fmt.Sprintf("version %d, %d bytes", version, length), func(t *testing.T) {
+				q, err := encodeQR(string(text))
+				if err != nil {
+					t.Fatalf("encodeQR: %v", err)
+				}
+				if q.size != 17+4*version {
+					t.Fatalf("encoded at size %d, want version %d (size %d)", q.size, version, 17+4*version)
+				}
+				if got := decodeQR(t, q); got != string(text) {
+					t.Errorf("decoded %q, want %q", got, text)
+				}
+			})
+		}
+	}
+
+	if _, err := encodeQR(strings.Repeat("x", qrReferenceCapacity[len(qrReferenceCapacity)-1]+1)); err == nil {
+		t.Error("expected an error for text longer than version 10 holds")
+	}
+}
+
+func TestEncodeQRLinks(t *testing.T) {
+	for _, link := range []string{
+		"",
+		"https://github.com/Leviathanium/NextUI-Themes",
+		"https://example.com/thème?q=ポケモン&x=1",
+	} {
+		q, err := encodeQR(link)
+		if err != nil {
+			t.Fatalf("encodeQR(%q): %v", link, err)
+		}
+		if got := decodeQR(t, q); got != link {
+			t.Errorf("decoded %q, want %q", got, link)
+		}
+	}
+}
+
+func TestQRFormatBits(t *testing.T) {
+	for mask, want := range qrReferenceFormat {
+		q := &qrCode{size: 21}
+		q.modules = make([][]bool, q.size)
+		q.isFunction = make([][]bool, q.size)
+		for y := range q.modules {
+			q.modules[y] = make([]bool, q.size)
+			q.isFunction[y] = make([]bool, q.size)
+		}
+		q.drawFormatBits(mask)
+
+		first, second := formatPositions(q.size)
+		if got := q.readBits(first); got != want {
+			t.Errorf("mask %d: format information = %s, want %s", mask, got, want)
+		}
+		if got := q.readBits(second); got != want {
+			t.Errorf("mask %d: second format information = %s, want %s", mask, got, want)
+		}
+	}
+}
+
+func TestQRVersionLayout(t *testing.T) {
+	for index, v := range qrVersions {
+		version := index + 1
+		reference := qrReferenceBlocks[version]
+
+		dataCodewords := 0
+		for _, n := range reference.blocks {
+			dataCodewords += n
+		}
+		if v.eccLen != reference.ecc || v.blocks != len(reference.blocks) ||
+			v.codewords != dataCodewords+reference.ecc*len(reference.blocks) {
+			t.Errorf("version %d layout = %+v, want %d blocks of %v data and %d error correction codewords",
+				version, v, len(reference.blocks), reference.blocks, reference.ecc)
+		}
+		if fmt.Sprint(v.align) != fmt.Sprint(qrReferenceAlignment[version]) {
+			t.Errorf("version %d alignment = %v, want %v", version, v.align, qrReferenceAlignment[version])
+		}
+	}
+}
+
+func TestReedSolomon(t *testing.T) {
+	// Generator polynomial of degree 7, α^0 + α^87 x + α^229 x^2 + ... without the leading term
+	if got, want := rsDivisor(7), []byte{127, 122, 154, 164, 11, 68, 117}; !bytes.Equal(got, want) {
+		t.Errorf("rsDivisor(7) = %v, want %v", got, want)
+	}
+
+	// The data codewords of "HELLO WORLD" at 1-M and their error correction codewords
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+
+	for x := 0; x < 256; x++ {
+		for y := 0; y < 256; y++ {
+			want := byte(0)
+			if x != 0 && y != 0 {
+				want = gfExp[gfLog[x]+gfLog[y]]
+			}
+			if got := gfMul(byte(x), byte(y)); got != want {
+				t.Fatalf("gfMul(%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestWriteQRCode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "qrcode.png")
+	if err := WriteQRCode("https://example.com", path, 480); err != nil {
+		t.Fatalf("WriteQRCode: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	img, err := png.Decode(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Version 2 with four modules of quiet zone on each side, scaled to fit 480 pixels
+	q, _ := encodeQR("https://example.com")
+	scale := 480 / (q.size + 8)
+	if bounds := img.Bounds(); bounds.Dx() != (q.size+8)*scale || bounds.Dy() != bounds.Dx() {
+		t.Fatalf("image is %v, want %d pixels square", bounds, (q.size+8)*scale)
+	}
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			r, _, _, _ := img.At((x+4)*scale+scale/2, (y+4)*scale+scale/2).RGBA()
+			if dark := r == 0; dark != q.modules[y][x] {
+				t.Fatalf("pixel of module (%d, %d) doesn't match it", x, y)
+			}
+		}
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("quiet zone is dark")
+	}
+}
@@ -0,0 +1,222 @@
+// src/internal/ui/screens/about_screens.go
+// About screen and the audit trail of recent operations
+
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// pakVersion returns the version in the pak's pak.json, or "unknown"
+func pakVersion() string {
+	pak, err := themes.LoadPakInfo()
+	if err != nil {
+		logging.LogDebug("Could not read pak.json: %v", err)
+		return "unknown"
+	}
+	if pak.Version == "" {
+		return "unknown"
+	}
+	return pak.Version
+}
+
+// AboutScreen shows the version and links to the audit trail, systems browser and updater
+func AboutScreen() (string, int) {
+	menu := []string{
+		"Operation History",
+		"Systems",
+		"Update Theme Manager",
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", fmt.Sprintf("Theme Manager v%s", pakVersion()))
+}
+
+// HandleAbout opens the selected About entry
+func HandleAbout(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleAbout called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		switch selection {
+		case "Operation History":
+			return app.Screens.AuditTrail
+		case "Systems":
+			return app.Screens.Systems
+		case "Update Theme Manager":
+			updateThemeManager()
+		}
+		return app.Screens.About
+
+	case 1, 2:
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.About
+}
+
+// updateThemeManager checks for a newer release and, once confirmed, stages it to be
+// installed the next time the pak launches
+func updateThemeManager() {
+	if themes.HasStagedPakUpdate() {
+		ui.ShowMessage("An update is already downloaded. Quit and reopen Theme Manager to install it.", "3")
+		return
+	}
+
+	var release *themes.PakRelease
+	err := ui.ShowMessageWithOperation("Checking for updates...", func() error {
+		var err error
+		release, err = themes.CheckForPakUpdate()
+		return err
+	})
+	if err != nil {
+		logging.LogDebug("Error checking for updates: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	if release == nil {
+		ui.ShowMessage(fmt.Sprintf("Theme Manager v%s is up to date.", pakVersion()), "3")
+		return
+	}
+
+	options := []string{
+		"Yes",
+		"No",
+	}
+	title := fmt.Sprintf("Download v%s (%s)?", release.Version, themes.FormatSize(release.Size))
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", title)
+	if exitCode != 0 || result != "Yes" {
+		return
+	}
+
+	err = ui.ShowMessageWithOperation(fmt.Sprintf("Downloading Theme Manager v%s...", release.Version), func() error {
+		return themes.StagePakUpdate(release)
+	})
+	if err != nil {
+		logging.LogDebug("Error staging update: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.ShowMessage(fmt.Sprintf("Theme Manager v%s is ready. Quit and reopen Theme Manager to finish updating.", release.Version), "4")
+}
+
+// AuditTrailScreen lists the recent operations and their outcomes, newest first
+func AuditTrailScreen() (string, int) {
+	entries, err := themes.LoadAuditLog()
+	if err != nil {
+		logging.LogDebug("Error loading audit log: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(entries) == 0 {
+		ui.ShowMessage("No operations recorded yet. Applies, exports, downloads and rollbacks are listed here.", "3")
+		return "", 1
+	}
+
+	labels := make([]string, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		labels = append(labels, themes.FormatAuditEntry(entries[i]))
+	}
+
+	return ui.DisplayMinUiList(strings.Join(labels, "\n"), "text", "Operation History")
+}
+
+// HandleAuditTrail shows why the selected operation failed
+func HandleAuditTrail(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleAuditTrail called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		entries, err := themes.LoadAuditLog()
+		if err != nil {
+			logging.LogDebug("Error loading audit log: %v", err)
+			return app.Screens.AuditTrail
+		}
+
+		for i := len(entries) - 1; i >= 0; i-- {
+			if themes.FormatAuditEntry(entries[i]) != selection {
+				continue
+			}
+			if entries[i].Error != "" {
+				ui.ShowMessage(fmt.Sprintf("%s\n%s", selection, entries[i].Error), "5")
+			}
+			break
+		}
+		return app.Screens.AuditTrail
+
+	case 1, 2:
+		return app.Screens.About
+	}
+
+	return app.Screens.AuditTrail
+}
+
+// systemStatusLabel is the list entry of a system: its folder and which assets it has
+func systemStatusLabel(status themes.SystemStatus) string {
+	mark := func(name string, ok bool) string {
+		if ok {
+			return name
+		}
+		return "-"
+	}
+	return fmt.Sprintf("%s  [%s %s %s]", status.System.Name,
+		mark("icon", status.Icon), mark("bg", status.Background), mark("list", status.ListBackground))
+}
+
+// SystemsScreen lists the ROM systems detected on the device and their themed assets
+func SystemsScreen() (string, int) {
+	statuses, err := themes.ListSystemStatus()
+	if err != nil {
+		logging.LogDebug("Error listing systems: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(statuses) == 0 {
+		ui.ShowMessage("No ROM folders found in Roms.", "3")
+		return "", 1
+	}
+
+	labels := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		labels = append(labels, systemStatusLabel(status))
+	}
+
+	return ui.DisplayMinUiList(strings.Join(labels, "\n"), "text", fmt.Sprintf("Systems (%d)", len(statuses)))
+}
+
+// HandleSystems shows the tag, paths and assets of the selected system
+func HandleSystems(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleSystems called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		statuses, err := themes.ListSystemStatus()
+		if err != nil {
+			logging.LogDebug("Error listing systems: %v", err)
+			return app.Screens.About
+		}
+
+		for _, status := range statuses {
+			if systemStatusLabel(status) == selection {
+				details := themes.FormatSystemStatus(status)
+				logging.LogDebug("System details:\n%s", details)
+				ui.ShowMessage(details, "5")
+				break
+			}
+		}
+		return app.Screens.Systems
+
+	case 1, 2:
+		return app.Screens.About
+	}
+
+	return app.Screens.Systems
+}
@@ -4,7 +4,9 @@
 package screens
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"nextui-themes/internal/app"
 	"nextui-themes/internal/logging"
@@ -20,15 +22,9 @@ import (
 )
 
 func ComponentsMenuScreen() (string, int) {
-	// Updated menu without "Deconstruct..."
-	menu := []string{
-		"Wallpapers",
-		"Icons",
-		"Accents",
-		"Overlays",
-		"LEDs",
-		"Fonts",
-		// "Deconstruct..." option has been removed
+	var menu []string
+	for _, kind := range themes.ComponentKinds() {
+		menu = append(menu, kind.Directory())
 	}
 
 	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Components")
@@ -67,6 +63,39 @@ func ComponentOptionsScreen() (string, int) {
 		"Installed", // Browse locally installed components
 		"Download",  // Browse and download components from catalog
 		"Export",
+		"Check Compatibility",
+		"Preview Changes",
+	}
+
+	// Overlays can switch between the variants installed for a system
+	if componentType == "Overlays" {
+		menu = append(menu, "Variants")
+	}
+
+	// Photos can be sent from a phone and installed as one system's wallpaper, any installed
+	// pack's image put on a single slot, and a darker copy of a pack generated
+	if componentType == "Wallpapers" {
+		menu = append(menu, "Upload from Phone", "Quick Wallpapers", "Make Night Variant")
+	}
+
+	// Two wallpaper or icon packs can be combined into one
+	if mergeableType(componentType) != "" {
+		menu = append(menu, "Merge Packs")
+	}
+
+	// Icons and wallpapers can get a shadow, outline or dimming when applied
+	if componentType == "Icons" || componentType == "Wallpapers" {
+		menu = append(menu, "Image Effects")
+	}
+
+	// Icon packs can be cut down, or matched to the device's ROM folder names
+	if componentType == "Icons" {
+		menu = append(menu, "Extract Systems", "Remap Icons")
+	}
+
+	// Shader applies back up each core's settings, which can be put back from here
+	if componentType == "Shaders" && themes.HasShaderBackups() {
+		menu = append(menu, "Restore Original Settings", "Discard Original Settings")
 	}
 
 	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", componentType)
@@ -84,14 +113,121 @@ func HandleComponentOptions(selection string, exitCode int) app.Screen {
 		// Process based on selected option and component type
 		componentType := app.GetSelectedComponentType()
 
+		if selection == "Upload from Phone" {
+			runWallpaperUpload()
+			return app.Screens.ComponentOptions
+		}
+
+		if selection == "Quick Wallpapers" {
+			return app.Screens.QuickWallpapers
+		}
+
+		// Any installed pack can be checked against this device before applying
+		if selection == "Check Compatibility" {
+			packs := installedPacks(componentType, themes.ComponentExtension[componentTypeForMenu(componentType)])
+			if len(packs) == 0 {
+				ui.ShowMessage(fmt.Sprintf("No installed %s components found.", componentType), "3")
+				return app.Screens.ComponentOptions
+			}
+			pack, code := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", "Check Which Pack?")
+			if code == 0 && pack != "" {
+				showCompatibility(filepath.Join(app.GetWorkingDir(), "Components", componentType, pack))
+			}
+			return app.Screens.ComponentOptions
+		}
+
+		// Or dry run, listing every file an apply would change
+		if selection == "Preview Changes" {
+			packs := installedPacks(componentType, themes.ComponentExtension[componentTypeForMenu(componentType)])
+			if len(packs) == 0 {
+				ui.ShowMessage(fmt.Sprintf("No installed %s components found.", componentType), "3")
+				return app.Screens.ComponentOptions
+			}
+			pack, code := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", "Preview Which Pack?")
+			if code == 0 && pack != "" {
+				showApplyPreview(filepath.Join(app.GetWorkingDir(), "Components", componentType, pack))
+			}
+			return app.Screens.ComponentOptions
+		}
+
 		// If this is overlays, go to system selection first
 		if componentType == "Overlays" {
 			// Clear any previously selected system tag
 			app.SetSelectedSystemTag("")
+
+			// Exports can bundle several systems, so they get a multi-select screen
+			if selection == "Export" {
+				app.ClearSelectedExportSystems()
+				return app.Screens.OverlayExportSystems
+			}
 			return app.Screens.OverlaySystemSelection // New screen for system selection
+		} else if componentType == "Collections" && selection == "Export" {
+			// A collection bundle holds one collection, picked before exporting
+			app.SetSelectedCollection("")
+			return app.Screens.CollectionSelection
 		} else {
 			// For other component types, use existing flow
 			switch selection {
+			case "Restore Original Settings":
+				if !confirmProtectionPIN("restore") {
+					return app.Screens.ComponentOptions
+				}
+				restored, err := themes.RestoreShaderBackups()
+				if err != nil {
+					logging.LogDebug("Error restoring shader backups: %v", err)
+					ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				} else {
+					ui.ShowMessage(fmt.Sprintf("Restored video settings for %d cores", restored), "3")
+				}
+				return app.Screens.ComponentOptions
+			case "Discard Original Settings":
+				options := []string{
+					"Yes",
+					"No",
+				}
+				result, promptCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text",
+					"Delete the saved video settings? The shader settings can't be undone afterwards.")
+				if promptCode != 0 || result != "Yes" {
+					return app.Screens.ComponentOptions
+				}
+				if !confirmProtectionPIN("delete backups") {
+					return app.Screens.ComponentOptions
+				}
+				if err := themes.DiscardShaderBackups(); err != nil {
+					logging.LogDebug("Error discarding shader backups: %v", err)
+					ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				} else {
+					ui.ShowMessage("Saved video settings deleted", "2")
+				}
+				return app.Screens.ComponentOptions
+			case "Extract Systems":
+				if !pickIconPack("Extract from Which Pack?") {
+					return app.Screens.ComponentOptions
+				}
+				return app.Screens.IconSubset
+			case "Remap Icons":
+				if !pickIconPack("Remap Which Pack?") {
+					return app.Screens.ComponentOptions
+				}
+				return app.Screens.IconRemap
+			case "Merge Packs":
+				mergePacks(componentType)
+				return app.Screens.ComponentOptions
+			case "Make Night Variant":
+				makeNightVariant()
+				return app.Screens.ComponentOptions
+			case "Image Effects":
+				packs := installedPacks(componentType, themes.ComponentExtension[componentTypeForMenu(componentType)])
+				if len(packs) == 0 {
+					ui.ShowMessage(fmt.Sprintf("No installed %s components found.", componentType), "3")
+					return app.Screens.ComponentOptions
+				}
+				pack, code := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", "Effects for Which Pack?")
+				if code != 0 || pack == "" {
+					return app.Screens.ComponentOptions
+				}
+				app.SetSelectedEffectsPack(pack)
+				return app.Screens.ImageEffects
 			case "Installed":
 				return app.Screens.InstalledComponents
 			case "Download":
@@ -109,6 +245,357 @@ func HandleComponentOptions(selection string, exitCode int) app.Screen {
 	return app.Screens.ComponentOptions
 }
 
+// installedPacks lists the installed packs of a component menu, e.g. "Icons" and ".icon"
+func installedPacks(menuType, ext string) []string {
+	entries, err := os.ReadDir(filepath.Join(app.GetWorkingDir(), "Components", menuType))
+	if err != nil {
+		logging.LogDebug("Error reading components directory: %v", err)
+	}
+
+	var packs []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ext) && !strings.HasPrefix(entry.Name(), ".") {
+			packs = append(packs, entry.Name())
+		}
+	}
+	return packs
+}
+
+// componentTypeForMenu returns the component type of a menu, e.g. "icon" for "Icons"
+func componentTypeForMenu(menuType string) string {
+	return themes.ComponentTypeForDirectory(menuType)
+}
+
+// mergeableType returns the component type of a menu's packs when they can be merged, or ""
+func mergeableType(menuType string) string {
+	componentType := componentTypeForMenu(menuType)
+	for _, mergeable := range themes.MergeablePackTypes {
+		if mergeable == componentType {
+			return componentType
+		}
+	}
+	return ""
+}
+
+// showCompatibility checks how much of an installed package would land on this device and
+// shows the report
+func showCompatibility(packagePath string) {
+	var report *themes.CompatibilityReport
+	err := ui.ShowMessageWithOperation("Checking compatibility...", func() error {
+		var err error
+		report, err = themes.CheckCompatibility(packagePath)
+		return err
+	})
+	if err != nil {
+		logging.LogDebug("Error checking compatibility: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.DisplayMinUiList(themes.FormatCompatibilityReport(report), "text", fmt.Sprintf("Compatibility: %s", filepath.Base(packagePath)))
+}
+
+// showApplyPreview lists the files applying an installed package would create, overwrite
+// or remove, without applying it
+func showApplyPreview(packagePath string) {
+	var preview *themes.ApplyPreview
+	previewApply := func() error {
+		var err error
+		preview, err = themes.PreviewApply(packagePath)
+		return err
+	}
+	err := ui.ShowMessageWithOperation("Previewing changes...", func() error {
+		// Include the list wallpapers the next apply is set to make
+		if app.GetGenerateListWallpapers() {
+			return themes.WithGeneratedListWallpapers(previewApply)
+		}
+		return previewApply()
+	})
+	if err != nil {
+		logging.LogDebug("Error previewing apply: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.DisplayMinUiList(themes.FormatApplyPreview(preview), "text", fmt.Sprintf("Changes: %s", filepath.Base(packagePath)))
+}
+
+// mergePacks asks for two installed packs and merges them into a new one, asking which file
+// to keep wherever both packs have a different one
+func mergePacks(menuType string) {
+	componentType := mergeableType(menuType)
+
+	packs := installedPacks(menuType, themes.ComponentExtension[componentType])
+	if len(packs) < 2 {
+		ui.ShowMessage(fmt.Sprintf("Install at least two %s packs to merge them", strings.ToLower(menuType)), "3")
+		return
+	}
+
+	first, exitCode := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", "Merge Which Pack?")
+	if exitCode != 0 || first == "" {
+		return
+	}
+
+	var others []string
+	for _, pack := range packs {
+		if pack != first {
+			others = append(others, pack)
+		}
+	}
+	second, exitCode := ui.DisplayMinUiList(strings.Join(others, "\n"), "text", fmt.Sprintf("Merge %s With", first))
+	if exitCode != 0 || second == "" {
+		return
+	}
+
+	// A choice made "for All" answers every remaining conflict
+	keepFirst := "Keep " + first
+	keepSecond := "Keep " + second
+	sticky := -1
+	resolve := func(rel string) themes.MergeChoice {
+		if sticky >= 0 {
+			return themes.MergeChoice(sticky)
+		}
+
+		options := []string{
+			keepFirst,
+			keepSecond,
+			"Keep Both",
+			keepFirst + " for All",
+			keepSecond + " for All",
+		}
+		choice, code := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", fmt.Sprintf("Both packs have %s", rel))
+		if code != 0 {
+			return themes.MergeKeepFirst
+		}
+
+		switch choice {
+		case keepSecond:
+			return themes.MergeKeepSecond
+		case "Keep Both":
+			return themes.MergeKeepBoth
+		case keepFirst + " for All":
+			sticky = int(themes.MergeKeepFirst)
+		case keepSecond + " for All":
+			sticky = int(themes.MergeKeepSecond)
+			return themes.MergeKeepSecond
+		}
+		return themes.MergeKeepFirst
+	}
+
+	merged, err := themes.MergeComponentPacks(componentType, first, second, resolve)
+	if err != nil {
+		logging.LogDebug("Error merging packs: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.ShowMessage(fmt.Sprintf("Merged into %s. Apply it from Installed.", merged), "3")
+}
+
+// makeNightVariant asks for an installed wallpaper pack and generates its night variant
+func makeNightVariant() {
+	packs := installedPacks("Wallpapers", themes.ComponentExtension[themes.ComponentWallpaper])
+	if len(packs) == 0 {
+		ui.ShowMessage("No installed Wallpapers components found.", "3")
+		return
+	}
+
+	pack, exitCode := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", "Night Variant of Which Pack?")
+	if exitCode != 0 || pack == "" {
+		return
+	}
+
+	var name string
+	err := ui.ShowMessageWithOperation(fmt.Sprintf("Darkening %s...", pack), func() error {
+		var genErr error
+		name, genErr = themes.GenerateNightVariant(pack)
+		return genErr
+	})
+	if err != nil {
+		logging.LogDebug("Error generating night variant: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.ShowMessage(fmt.Sprintf("Created %s. Apply it from Installed.", name), "3")
+}
+
+// pickIconPack asks for an installed icon pack and selects it, returning false when none was picked
+func pickIconPack(title string) bool {
+	packs := installedPacks("Icons", themes.ComponentExtension[themes.ComponentIcon])
+	if len(packs) == 0 {
+		ui.ShowMessage("No installed Icons components found.", "3")
+		return false
+	}
+
+	pack, exitCode := ui.DisplayMinUiList(strings.Join(packs, "\n"), "text", title)
+	if exitCode != 0 || pack == "" {
+		return false
+	}
+
+	app.SetSelectedIconPack(pack)
+	return true
+}
+
+// iconAssignmentItem returns the remap list label of an icon, e.g. "GB.png -> Game Boy (GB)"
+func iconAssignmentItem(assignment themes.IconAssignment) string {
+	target := assignment.Target
+	if target == "" {
+		target = "unmatched"
+	}
+	return fmt.Sprintf("%s -> %s", assignment.Icon, target)
+}
+
+// IconRemapScreen lists every system icon of a pack next to the ROM folder it will be applied to
+func IconRemapScreen() (string, int) {
+	pack := app.GetSelectedIconPack()
+
+	assignments, err := themes.ListIconAssignments(pack)
+	if err != nil {
+		logging.LogDebug("Error listing icons of %s: %v", pack, err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(assignments) == 0 {
+		ui.ShowMessage(fmt.Sprintf("%s has no system icons", pack), "3")
+		return "", 1
+	}
+
+	// Unmatched icons first, they are the ones that need a folder
+	var unmatched, matched []string
+	for _, assignment := range assignments {
+		if assignment.Target == "" {
+			unmatched = append(unmatched, iconAssignmentItem(assignment))
+		} else {
+			matched = append(matched, iconAssignmentItem(assignment))
+		}
+	}
+
+	title := fmt.Sprintf("Remap %s (%d unmatched)", pack, len(unmatched))
+	return ui.DisplayMinUiList(strings.Join(append(unmatched, matched...), "\n"), "text", title)
+}
+
+// HandleIconRemap asks for the ROM folder the selected icon should be applied to and renames it
+func HandleIconRemap(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleIconRemap called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		icon, _, found := strings.Cut(selection, " -> ")
+		if !found || icon == "" {
+			return app.Screens.IconRemap
+		}
+
+		systemPaths, err := system.GetSystemPaths()
+		if err != nil {
+			logging.LogDebug("Error getting system paths: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.IconRemap
+		}
+
+		var folders []string
+		for _, sys := range systemPaths.Systems {
+			if sys.Tag != "" {
+				folders = append(folders, sys.Name)
+			}
+		}
+		sort.Strings(folders)
+
+		folder, code := ui.DisplayMinUiList(strings.Join(folders, "\n"), "text", fmt.Sprintf("Apply %s to", icon))
+		if code != 0 || folder == "" {
+			return app.Screens.IconRemap
+		}
+
+		if err := themes.ReassignIcon(app.GetSelectedIconPack(), icon, folder); err != nil {
+			logging.LogDebug("Error remapping icon: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.IconRemap
+
+	case 1, 2:
+		// User pressed cancel or back
+		app.SetSelectedIconPack("")
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.IconRemap
+}
+
+// IconSubsetScreen lets the user tick the system icons to extract from an icon pack
+func IconSubsetScreen() (string, int) {
+	pack := app.GetSelectedIconPack()
+
+	icons, err := themes.ListPackSystemIcons(pack)
+	if err != nil {
+		logging.LogDebug("Error listing icons of %s: %v", pack, err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(icons) == 0 {
+		ui.ShowMessage(fmt.Sprintf("%s has no system icons", pack), "3")
+		return "", 1
+	}
+
+	selected := make(map[string]bool)
+	for _, icon := range app.GetSelectedSubsetIcons() {
+		selected[icon] = true
+	}
+
+	sort.Strings(icons)
+	menu := []string{fmt.Sprintf("Extract Selected (%d)", len(selected))}
+	for _, icon := range icons {
+		if selected[icon] {
+			menu = append(menu, "[x] "+icon)
+		} else {
+			menu = append(menu, "[ ] "+icon)
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", fmt.Sprintf("Extract from %s", pack))
+}
+
+// HandleIconSubset toggles icons in the selection and extracts them into a new pack
+func HandleIconSubset(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleIconSubset called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if strings.HasPrefix(selection, "Extract Selected") {
+			icons := app.GetSelectedSubsetIcons()
+			if len(icons) == 0 {
+				ui.ShowMessage("Select at least one system to extract", "2")
+				return app.Screens.IconSubset
+			}
+
+			name, err := themes.ExtractIconSubset(app.GetSelectedIconPack(), icons)
+			if err != nil {
+				logging.LogDebug("Error extracting icons: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				return app.Screens.IconSubset
+			}
+
+			ui.ShowMessage(fmt.Sprintf("Extracted %d icons into %s", len(icons), name), "3")
+			app.SetSelectedIconPack("")
+			return app.Screens.ComponentOptions
+		}
+
+		icon := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		if icon != "" {
+			app.ToggleSelectedSubsetIcon(icon)
+		}
+		return app.Screens.IconSubset
+
+	case 1, 2:
+		// User pressed cancel or back
+		app.SetSelectedIconPack("")
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.IconSubset
+}
+
 // Modified OverlaySystemSelectionScreen function to fix duplicated system tags
 func OverlaySystemSelectionScreen() (string, int) {
 	logging.LogDebug("Showing overlay system selection screen")
@@ -168,6 +655,8 @@ func HandleOverlaySystemSelection(selection string, exitCode int) app.Screen {
 					return app.Screens.DownloadComponents
 				case "Export":
 					return app.Screens.ExportComponent
+				case "Variants":
+					return app.Screens.OverlayVariants
 				}
 			}
 		}
@@ -182,6 +671,207 @@ func HandleOverlaySystemSelection(selection string, exitCode int) app.Screen {
 	return app.Screens.OverlaySystemSelection
 }
 
+// OverlayExportSystemsScreen lets the user pick which systems' overlays go into an export
+func OverlayExportSystemsScreen() (string, int) {
+	tags, err := themes.ListOverlaySystems()
+	if err != nil {
+		logging.LogDebug("Error listing overlay systems: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(tags) == 0 {
+		logging.LogDebug("No systems with overlays found")
+		ui.ShowMessage("No overlays found to export", "3")
+		return "", 1
+	}
+
+	// Show full system names where the device knows them
+	names := make(map[string]string)
+	if systemPaths, err := system.GetSystemPaths(); err == nil {
+		for _, sys := range systemPaths.Systems {
+			names[sys.Tag] = sys.Name
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, tag := range app.GetSelectedExportSystems() {
+		selected[tag] = true
+	}
+
+	var systemList []string
+	for _, tag := range tags {
+		name := names[tag]
+		if name == "" {
+			name = tag
+		}
+
+		if selected[tag] {
+			systemList = append(systemList, "[x] "+name)
+		} else {
+			systemList = append(systemList, "[ ] "+name)
+		}
+	}
+
+	// Sort by system name, ignoring the checkbox prefix
+	sort.Slice(systemList, func(i, j int) bool {
+		return systemList[i][4:] < systemList[j][4:]
+	})
+
+	menu := append([]string{
+		fmt.Sprintf("Export Selected (%d)", len(selected)),
+		"Export All Systems",
+	}, systemList...)
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Select Overlays to Export")
+}
+
+// HandleOverlayExportSystems toggles systems in the export selection and starts the export
+func HandleOverlayExportSystems(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleOverlayExportSystems called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if strings.HasPrefix(selection, "Export Selected") {
+			if len(app.GetSelectedExportSystems()) == 0 {
+				ui.ShowMessage("Select at least one system to export", "2")
+				return app.Screens.OverlayExportSystems
+			}
+			return app.Screens.ExportComponent
+		}
+
+		if selection == "Export All Systems" {
+			app.ClearSelectedExportSystems()
+			return app.Screens.ExportComponent
+		}
+
+		// Extract system tag from selection "[x] System Name (TAG)", or "[x] TAG"
+		tag := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		re := regexp.MustCompile(`\((.*?)\)`)
+		if matches := re.FindStringSubmatch(tag); len(matches) >= 2 {
+			tag = matches[1]
+		}
+
+		if tag != "" {
+			app.ToggleSelectedExportSystem(tag)
+		}
+		return app.Screens.OverlayExportSystems
+
+	case 1, 2:
+		// User pressed cancel or back
+		app.ClearSelectedExportSystems()
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.OverlayExportSystems
+}
+
+// overlayVariantItem returns the picker label of an overlay variant
+func overlayVariantItem(variant themes.OverlayVariant, selected bool) string {
+	label := variant.Label
+	if variant.Opacity != "" {
+		label = fmt.Sprintf("%s (%s%%)", label, strings.TrimSuffix(variant.Opacity, "%"))
+	}
+
+	if selected {
+		return "[x] " + label
+	}
+	return "[ ] " + label
+}
+
+// overlayOpacityLabel returns the opacity entry of the variant picker
+func overlayOpacityLabel(opacity int) string {
+	return fmt.Sprintf("Opacity: %d%%", opacity)
+}
+
+// cycleOverlayOpacity moves a system's overlay opacity to the next preset
+func cycleOverlayOpacity(systemTag string, current int) {
+	next := themes.OverlayOpacityPresets[0]
+	for i, opacity := range themes.OverlayOpacityPresets {
+		if opacity == current {
+			next = themes.OverlayOpacityPresets[(i+1)%len(themes.OverlayOpacityPresets)]
+			break
+		}
+	}
+
+	if err := themes.SetOverlayOpacity(systemTag, next); err != nil {
+		logging.LogDebug("Error setting overlay opacity: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// OverlayVariantsScreen lists the overlay variants installed for the selected system
+func OverlayVariantsScreen() (string, int) {
+	systemTag := app.GetSelectedSystemTag()
+
+	variants, err := themes.ListOverlayVariants(systemTag)
+	if err != nil {
+		logging.LogDebug("Error listing overlay variants: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(variants) == 0 {
+		logging.LogDebug("No overlay variants found for %s", systemTag)
+		ui.ShowMessage(fmt.Sprintf("No overlays installed for %s", systemTag), "3")
+		return "", 1
+	}
+
+	choice := themes.GetOverlayVariantChoice(systemTag)
+
+	menu := []string{overlayOpacityLabel(choice.Opacity)}
+	for _, variant := range variants {
+		menu = append(menu, overlayVariantItem(variant, variant.File == choice.File))
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text",
+		fmt.Sprintf("Overlay Variant for %s", systemTag))
+}
+
+// HandleOverlayVariants applies the picked variant or cycles the overlay opacity
+func HandleOverlayVariants(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleOverlayVariants called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	systemTag := app.GetSelectedSystemTag()
+
+	switch exitCode {
+	case 0:
+		choice := themes.GetOverlayVariantChoice(systemTag)
+
+		if strings.HasPrefix(selection, "Opacity:") {
+			cycleOverlayOpacity(systemTag, choice.Opacity)
+			return app.Screens.OverlayVariants
+		}
+
+		variants, err := themes.ListOverlayVariants(systemTag)
+		if err != nil {
+			logging.LogDebug("Error listing overlay variants: %v", err)
+			return app.Screens.OverlayVariants
+		}
+
+		for _, variant := range variants {
+			if overlayVariantItem(variant, true) != selection && overlayVariantItem(variant, false) != selection {
+				continue
+			}
+
+			if err := themes.SelectOverlayVariant(systemTag, variant.File, choice.Opacity); err != nil {
+				logging.LogDebug("Error selecting overlay variant: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			} else {
+				ui.ShowMessage(fmt.Sprintf("%s overlay set to %s", systemTag, variant.Label), "2")
+			}
+			break
+		}
+		return app.Screens.OverlayVariants
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.OverlaySystemSelection
+	}
+
+	return app.Screens.OverlayVariants
+}
+
 // Complete InstalledComponentsScreen function with system tag filtering
 func InstalledComponentsScreen() (string, int) {
 	componentType := app.GetSelectedComponentType()
@@ -219,21 +909,7 @@ func InstalledComponentsScreen() (string, int) {
 	}
 
 	// Filter for component directories with appropriate extension
-	componentExt := ""
-	switch componentType {
-	case "Wallpapers":
-		componentExt = ".bg"
-	case "Icons":
-		componentExt = ".icon"
-	case "Accents":
-		componentExt = ".acc"
-	case "LEDs":
-		componentExt = ".led"
-	case "Fonts":
-		componentExt = ".font"
-	case "Overlays":
-		componentExt = ".over"
-	}
+	componentExt := themes.ComponentExtension[themes.ComponentTypeForDirectory(componentType)]
 
 	var componentList []string
 	for _, entry := range entries {
@@ -269,27 +945,26 @@ func InstalledComponentsScreen() (string, int) {
 
 	// Get preview images for gallery display
 	previewImages := make([]ui.GalleryItem, 0, len(componentList))
+	itemNames := make(map[string]string, len(componentList))
 	for _, compName := range componentList {
 		compPath := filepath.Join(componentsDir, compName)
 		previewPath := filepath.Join(compPath, "preview.png")
-		manifestPath := filepath.Join(compPath, "manifest.json")
 
 		// Default text in case manifest can't be read
 		text := compName
 
-		// Try to read manifest for author info
-		if fileExists(manifestPath) {
-			if data, err := os.ReadFile(manifestPath); err == nil {
-				var manifest map[string]interface{}
-				if err := json.Unmarshal(data, &manifest); err == nil {
-					if compInfo, ok := manifest["component_info"].(map[string]interface{}); ok {
-						if author, ok := compInfo["author"].(string); ok {
-							text = fmt.Sprintf("%s by %s", compName, author)
-						}
-					}
+		// Try to read manifest for author info and where the package came from
+		if manifest, err := themes.LoadComponentManifest(compPath); err == nil {
+			if info := themes.GetComponentInfo(manifest); info != nil {
+				if info.Author != "" {
+					text = fmt.Sprintf("%s by %s", compName, info.Author)
+				}
+				if origin := themes.DescribeProvenance(info.Provenance); origin != "" {
+					text = fmt.Sprintf("%s (%s)", text, origin)
 				}
 			}
 		}
+		itemNames[text] = compName
 
 		// Create gallery item with or without preview image
 		if fileExists(previewPath) {
@@ -310,12 +985,27 @@ func InstalledComponentsScreen() (string, int) {
 	if systemTag != "" {
 		title = fmt.Sprintf("Installed %s for %s", componentType, systemTag)
 	}
-	selection, exitCode := ui.DisplayImageGallery(previewImages, title)
 
-	// Extract component name from selection (remove author info)
+	// LED packs are previewed live on the hardware while browsing
+	var preview func(ui.GalleryItem)
+	if componentType == "LEDs" {
+		preview = previewLEDItem(func(compName string) string {
+			// Packs without an author have nothing to split their provenance off at
+			if name, ok := itemNames[compName]; ok {
+				compName = name
+			}
+			return filepath.Join(componentsDir, compName, "manifest.json")
+		})
+	}
+
+	selection, exitCode := ui.DisplayImageGalleryWithPreview(previewImages, title, preview, componentsDir)
+	if exitCode != 0 {
+		themes.RevertLEDPreview()
+	}
+
+	// Extract component name from selection (remove author and provenance info)
 	if selection != "" {
-		parts := strings.Split(selection, " by ")
-		selection = parts[0]
+		selection = itemNames[selection]
 	}
 
 	logging.LogDebug("Gallery selection: %s, exit code: %d", selection, exitCode)
@@ -334,17 +1024,35 @@ func HandleInstalledComponents(selection string, exitCode int) app.Screen {
 			// Import/apply the selected component
 			componentPath := filepath.Join(app.GetWorkingDir(), "Components", componentType, selection)
 
-			importErr := ui.ShowMessageWithOperation(
+			// Let the user check the accent colors before committing them
+			if componentType == "Accents" && !previewAccents(componentPath, selection) {
+				return app.Screens.ComponentOptions
+			}
+
+			importFunc, ok := componentImporter(componentType, componentPath)
+			if !ok {
+				return app.Screens.ComponentOptions
+			}
+
+			importErr := themes.RunApplyWithProgress(
 				fmt.Sprintf("Applying %s component '%s'...", componentType, selection),
-				func() error {
-					return themes.ImportComponent(componentPath)
+				func(ctx context.Context) error {
+					return themes.RunStrict(func() error {
+						return importFunc(ctx)
+					})
 				},
 			)
 
-			if importErr != nil {
+			if errors.Is(importErr, ui.ErrCancelled) {
+				logging.LogDebug("Component apply cancelled")
+				themes.RevertLEDPreview()
+				ui.ShowMessage("Apply cancelled. Files copied so far stay applied, anything removed is in the trash.", "3")
+			} else if importErr != nil {
 				logging.LogDebug("Error importing component: %v", importErr)
+				themes.RevertLEDPreview()
 				ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
 			} else {
+				themes.CommitLEDPreview()
 				ui.ShowMessage(fmt.Sprintf("%s component applied successfully!", componentType), "2")
 			}
 		}
@@ -401,17 +1109,11 @@ func DownloadComponentsScreen() (string, int) {
 		return "", 1
 	}
 
-	// Map component type to catalog key
-	componentTypeMap := map[string]string{
-		"Wallpapers": "wallpapers",
-		"Icons":      "icons",
-		"Accents":    "accents",
-		"LEDs":       "leds",
-		"Fonts":      "fonts",
-		"Overlays":   "overlays",
+	// Catalog keys are the lowercase Components folder names
+	catalogType := ""
+	if themes.ComponentTypeForDirectory(componentType) != "" {
+		catalogType = strings.ToLower(componentType)
 	}
-
-	catalogType := componentTypeMap[componentType]
 	if catalogType == "" {
 		logging.LogDebug("Unknown component type: %s", componentType)
 		ui.ShowMessage(fmt.Sprintf("Unknown component type: %s", componentType), "3")
@@ -443,7 +1145,7 @@ func DownloadComponentsScreen() (string, int) {
 						if content, hasContent := manifest["content"].(map[string]interface{}); hasContent {
 							if systems, hasSystems := content["systems"].([]interface{}); hasSystems {
 								for _, sys := range systems {
-									if sysTag, ok := sys.(string); ok && sysTag == systemTag {
+									if sysTag, ok := sys.(string); ok && system.SameName(sysTag, systemTag) {
 										filteredComponents[compName] = compInfo
 										break
 									}
@@ -479,8 +1181,8 @@ func DownloadComponentsScreen() (string, int) {
 		// Get preview path - relative path in catalog needs to be converted to absolute
 		previewPath := filepath.Join(cwd, compInfo.PreviewPath)
 
-		// Skip LEDs which don't have preview images
-		if componentType == "LEDs" && (previewPath == "" || !fileExists(previewPath)) {
+		// Skip LEDs and shaders which don't have preview images
+		if (componentType == "LEDs" || componentType == "Shaders") && (previewPath == "" || !fileExists(previewPath)) {
 			// Just use the component name as text with installed indicator
 			text := fmt.Sprintf("%s by %s", compName, compInfo.Author)
 			if alreadyInstalled {
@@ -513,7 +1215,19 @@ func DownloadComponentsScreen() (string, int) {
 	if systemTag != "" {
 		title = fmt.Sprintf("Download %s for %s", componentType, systemTag)
 	}
-	selection, exitCode := ui.DisplayImageGallery(previewImages, title)
+
+	// LED packs are previewed live on the hardware using their catalog manifest
+	var preview func(ui.GalleryItem)
+	if componentType == "LEDs" {
+		preview = previewLEDItem(func(compName string) string {
+			return filepath.Join(cwd, components[compName].ManifestPath)
+		})
+	}
+
+	selection, exitCode := ui.DisplayImageGalleryWithPreview(previewImages, title, preview)
+	if exitCode != 0 {
+		themes.RevertLEDPreview()
+	}
 
 	logging.LogDebug("Gallery selection: %s, exit code: %d", selection, exitCode)
 
@@ -547,6 +1261,7 @@ func HandleDownloadComponents(selection string, exitCode int) app.Screen {
 				// Download the component package if not already installed
 				if err := themes.DownloadComponentPackage(componentType, selection); err != nil {
 					logging.LogDebug("Error downloading component: %v", err)
+					themes.RevertLEDPreview()
 					ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
 					return app.Screens.ComponentOptions
 				}
@@ -556,6 +1271,9 @@ func HandleDownloadComponents(selection string, exitCode int) app.Screen {
 
 			// Prompt user if they want to apply this component now
 			message := fmt.Sprintf("Apply %s '%s' now?", componentType, selection)
+			if license := themes.GetComponentLicense(localComponentPath); license != "" {
+				message = fmt.Sprintf("%s\nLicense: %s", message, license)
+			}
 			options := []string{
 				"Yes",
 				"No",
@@ -567,19 +1285,40 @@ func HandleDownloadComponents(selection string, exitCode int) app.Screen {
 				// Import/apply the selected component with operation message
 				componentPath := filepath.Join(app.GetWorkingDir(), "Components", componentType, selection)
 
-				importErr := ui.ShowMessageWithOperation(
+				// Let the user check the accent colors before committing them
+				if componentType == "Accents" && !previewAccents(componentPath, selection) {
+					return app.Screens.ComponentOptions
+				}
+
+				importFunc, ok := componentImporter(componentType, componentPath)
+				if !ok {
+					return app.Screens.ComponentOptions
+				}
+
+				importErr := themes.RunApplyWithProgress(
 					fmt.Sprintf("Applying %s component '%s'...", componentType, selection),
-					func() error {
-						return themes.ImportComponent(componentPath)
+					func(ctx context.Context) error {
+						return themes.RunStrict(func() error {
+							return importFunc(ctx)
+						})
 					},
 				)
 
-				if importErr != nil {
+				if errors.Is(importErr, ui.ErrCancelled) {
+					logging.LogDebug("Component apply cancelled")
+					themes.RevertLEDPreview()
+					ui.ShowMessage("Apply cancelled. Files copied so far stay applied, anything removed is in the trash.", "3")
+				} else if importErr != nil {
 					logging.LogDebug("Error importing component: %v", importErr)
+					themes.RevertLEDPreview()
 					ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
 				} else {
+					themes.CommitLEDPreview()
 					ui.ShowMessage(fmt.Sprintf("%s component applied successfully!", componentType), "2")
 				}
+			} else {
+				// Not applied, so put the previous LEDs back
+				themes.RevertLEDPreview()
 			}
 		}
 		return app.Screens.ComponentOptions
@@ -592,6 +1331,148 @@ func HandleDownloadComponents(selection string, exitCode int) app.Screen {
 	return app.Screens.DownloadComponents
 }
 
+// runWallpaperUpload serves the upload page while its QR code is on screen
+func runWallpaperUpload() {
+	server, err := themes.StartWallpaperUpload()
+	if err != nil {
+		logging.LogDebug("Error starting wallpaper upload: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	defer server.Stop()
+
+	ui.ShowQRCode(server.URL, fmt.Sprintf("Scan or open %s on your phone, press B when done", server.URL))
+}
+
+// componentImporter returns the apply function for a component. Collection bundles ask which
+// collection to apply to first; false means the user backed out.
+func componentImporter(componentType, componentPath string) (func(ctx context.Context) error, bool) {
+	if componentType != "Collections" {
+		return func(ctx context.Context) error {
+			return themes.ImportComponentContext(ctx, componentPath)
+		}, true
+	}
+
+	collection, ok := pickCollectionTarget(componentPath)
+	if !ok {
+		return nil, false
+	}
+	return func(ctx context.Context) error {
+		return themes.ImportCollectionTo(ctx, componentPath, collection)
+	}, true
+}
+
+// pickCollectionTarget asks which collection a bundle goes to, offering the one it was
+// exported from first
+func pickCollectionTarget(componentPath string) (string, bool) {
+	collections, err := themes.ListDeviceCollections()
+	if err != nil {
+		logging.LogDebug("Error listing collections: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", false
+	}
+
+	var options []string
+	if defaultCollection := themes.GetCollectionBundleDefault(componentPath); defaultCollection != "" {
+		options = append(options, defaultCollection)
+	}
+	for _, collection := range collections {
+		if len(options) == 0 || collection != options[0] {
+			options = append(options, collection)
+		}
+	}
+
+	if len(options) == 0 {
+		ui.ShowMessage("No collections found. Create a collection in NextUI first.", "3")
+		return "", false
+	}
+
+	selection, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", "Apply to Collection")
+	if exitCode != 0 || selection == "" {
+		return "", false
+	}
+	return selection, true
+}
+
+// CollectionSelectionScreen lists the collections on the device for a collection bundle export
+func CollectionSelectionScreen() (string, int) {
+	collections, err := themes.ListDeviceCollections()
+	if err != nil {
+		logging.LogDebug("Error listing collections: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(collections) == 0 {
+		ui.ShowMessage("No collections found on the device", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(collections, "\n"), "text", "Select Collection to Export")
+}
+
+// HandleCollectionSelection stores the picked collection and exports it
+func HandleCollectionSelection(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleCollectionSelection called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection != "" {
+			app.SetSelectedCollection(selection)
+			return app.Screens.ExportComponent
+		}
+		return app.Screens.ComponentOptions
+
+	case 1, 2:
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.CollectionSelection
+}
+
+// previewLEDItem returns a gallery callback that shows the LED pack under the cursor on the hardware
+func previewLEDItem(manifestPath func(compName string) string) func(ui.GalleryItem) {
+	return func(item ui.GalleryItem) {
+		compName := strings.TrimPrefix(item.Text, "[Installed] ")
+		compName = strings.Split(compName, " by ")[0]
+
+		if err := themes.PreviewLEDManifest(manifestPath(compName)); err != nil {
+			logging.LogDebug("Warning: Could not preview LEDs for %s: %v", compName, err)
+		}
+	}
+}
+
+// previewAccents swaps in an accent component's colors and asks whether to keep them.
+// minui-list draws with the NextUI accent settings, so the prompt itself is the sample.
+// The original colors are restored unless the user chooses Apply.
+func previewAccents(componentPath string, name string) bool {
+	if err := themes.BeginAccentPreview(componentPath); err != nil {
+		logging.LogDebug("Error starting accent preview: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return false
+	}
+
+	kept := false
+	defer func() {
+		if !kept {
+			themes.RestoreAccentPreview()
+		}
+	}()
+
+	options := []string{
+		"Apply",
+		"Revert",
+	}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", fmt.Sprintf("Previewing accents '%s'", name))
+
+	if exitCode == 0 && result == "Apply" {
+		kept = true
+		themes.CommitAccentPreview()
+	}
+
+	return kept
+}
+
 // Modified ExportComponentScreen function to properly display success messages
 func ExportComponentScreen() (string, int) {
 	componentType := app.GetSelectedComponentType()
@@ -601,33 +1482,49 @@ func ExportComponentScreen() (string, int) {
 	timestamp := time.Now().Format("20060102_150405")
 	var exportName string
 
-	if componentType == "Overlays" && systemTag != "" {
+	exportSystems := app.GetSelectedExportSystems()
+	collection := app.GetSelectedCollection()
+
+	if componentType == "Overlays" && len(exportSystems) > 0 {
+		// Name small selections after their systems, larger ones by count
+		systemsPart := fmt.Sprintf("%d-systems", len(exportSystems))
+		if len(exportSystems) <= 3 {
+			systemsPart = strings.Join(exportSystems, "-")
+		}
+		exportName = fmt.Sprintf("%s_%s_%s", strings.ToLower(componentType), systemsPart, timestamp)
+	} else if componentType == "Overlays" && systemTag != "" {
 		// Include system tag in export name for system-specific overlay exports
 		exportName = fmt.Sprintf("%s_%s_%s", strings.ToLower(componentType), systemTag, timestamp)
+	} else if componentType == "Collections" && collection != "" {
+		exportName = fmt.Sprintf("%s_%s_%s", strings.ToLower(componentType), collection, timestamp)
 	} else {
 		exportName = fmt.Sprintf("%s_%s", strings.ToLower(componentType), timestamp)
 	}
 
-	// Map component type to export function
-	exportFunctions := map[string]func(string) error{
-		"Wallpapers": themes.ExportWallpapers,
-		"Icons":      themes.ExportIcons,
-		"Accents":    themes.ExportAccents,
-		"Fonts":      themes.ExportFonts,
-		"LEDs":       themes.ExportLEDs,
-	}
-
 	// For overlays with a system tag, use the new function
 	var exportFunc func(string) error
 	var exportErr error
 
 	if componentType == "Overlays" {
-		if systemTag != "" {
+		if len(exportSystems) > 0 {
+			// Export only the systems picked on the selection screen
+			exportErr = ui.ShowMessageWithOperation(
+				fmt.Sprintf("Exporting %s component for %d systems...", componentType, len(exportSystems)),
+				func() error {
+					return themes.RunStrict(func() error {
+						return themes.ExportOverlaysForSystems(exportName, exportSystems)
+					})
+				},
+			)
+			app.ClearSelectedExportSystems()
+		} else if systemTag != "" {
 			// Use system-specific overlay export function
 			exportErr = ui.ShowMessageWithOperation(
 				fmt.Sprintf("Exporting %s component for system %s...", componentType, systemTag),
 				func() error {
-					return themes.ExportOverlaysForSystem(exportName, systemTag)
+					return themes.RunStrict(func() error {
+						return themes.ExportOverlaysForSystem(exportName, systemTag)
+					})
 				},
 			)
 		} else {
@@ -635,13 +1532,30 @@ func ExportComponentScreen() (string, int) {
 			exportErr = ui.ShowMessageWithOperation(
 				fmt.Sprintf("Exporting %s component...", componentType),
 				func() error {
-					return themes.ExportOverlays(exportName)
+					return themes.RunStrict(func() error {
+						return themes.ExportOverlays(exportName)
+					})
 				},
 			)
 		}
+	} else if componentType == "Collections" {
+		if collection == "" {
+			return "", 1
+		}
+		exportErr = ui.ShowMessageWithOperation(
+			fmt.Sprintf("Exporting collection %s...", collection),
+			func() error {
+				return themes.RunStrict(func() error {
+					return themes.ExportCollection(exportName, collection)
+				})
+			},
+		)
+		app.SetSelectedCollection("")
 	} else {
 		// Get the export function for other component types
-		exportFunc, _ = exportFunctions[componentType]
+		if kind, ok := themes.LookupComponentKind(themes.ComponentTypeForDirectory(componentType)); ok {
+			exportFunc = kind.Export
+		}
 		if exportFunc == nil {
 			logging.LogDebug("Unknown component type: %s", componentType)
 			ui.ShowMessage(fmt.Sprintf("Unknown component type: %s", componentType), "3")
@@ -652,7 +1566,9 @@ func ExportComponentScreen() (string, int) {
 		exportErr = ui.ShowMessageWithOperation(
 			fmt.Sprintf("Exporting %s component...", componentType),
 			func() error {
-				return exportFunc(exportName)
+				return themes.RunStrict(func() error {
+					return exportFunc(exportName)
+				})
 			},
 		)
 	}
@@ -664,11 +1580,16 @@ func ExportComponentScreen() (string, int) {
 	}
 
 	// Show success message
-	if componentType == "Overlays" && systemTag != "" {
+	if componentType == "Overlays" && len(exportSystems) > 0 {
+		ui.ShowMessage(fmt.Sprintf("%s component for %s exported successfully!", componentType, strings.Join(exportSystems, ", ")), "3")
+	} else if componentType == "Overlays" && systemTag != "" {
 		ui.ShowMessage(fmt.Sprintf("%s component for system %s exported successfully!", componentType, systemTag), "3")
+	} else if componentType == "Collections" {
+		ui.ShowMessage(fmt.Sprintf("Collection %s exported successfully!", collection), "3")
 	} else {
 		ui.ShowMessage(fmt.Sprintf("%s component exported successfully!", componentType), "3")
 	}
+	offerExportCopy()
 
 	// Return to component options screen
 	return "", 0
@@ -704,7 +1625,7 @@ func supportsSystem(overlayPath string, systemTag string) bool {
 
 	if manifest, ok := manifestObj.(*themes.OverlayManifest); ok {
 		for _, tag := range manifest.Content.Systems {
-			if tag == systemTag {
+			if system.SameName(tag, systemTag) {
 				return true
 			}
 		}
@@ -79,7 +79,7 @@ func DeconstructionScreen() (string, int) {
 	}
 
 	// Use DisplayImageGallery from presenter.go to display a gallery of preview images
-	selection, exitCode := ui.DisplayImageGallery(previewImages, "Select Theme to Deconstruct")
+	selection, exitCode := ui.DisplayImageGallery(previewImages, "Select Theme to Deconstruct", themesDir)
 
 	logging.LogDebug("Gallery selection: %s, exit code: %d", selection, exitCode)
 	return selection, exitCode
@@ -133,7 +133,9 @@ func HandleDeconstructConfirm(selection string, exitCode int) app.Screen {
 			deconstructErr := ui.ShowMessageWithOperation(
 				fmt.Sprintf("Deconstructing theme '%s'...", themeName),
 				func() error {
-					return themes.DeconstructTheme(themeName)
+					return themes.RunStrict(func() error {
+						return themes.DeconstructTheme(themeName)
+					})
 				},
 			)
 
@@ -0,0 +1,102 @@
+// src/internal/ui/screens/image_effects_screens.go
+// Screen for picking the shadow, outline and dimming added to a component's images
+
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// effectLevelName returns how a shadow or outline strength is shown
+func effectLevelName(level string) string {
+	switch level {
+	case themes.EffectLight:
+		return "Light"
+	case themes.EffectStrong:
+		return "Strong"
+	default:
+		return "Off"
+	}
+}
+
+// nextEffectLevel returns the strength after current in cycling order
+func nextEffectLevel(current string) string {
+	for i, level := range themes.EffectLevels {
+		if level == current {
+			return themes.EffectLevels[(i+1)%len(themes.EffectLevels)]
+		}
+	}
+	return themes.EffectLevels[0]
+}
+
+// effectDimLabel returns the dimming entry of the effects screen
+func effectDimLabel(dim int) string {
+	if dim <= 0 {
+		return "Dimming: Off"
+	}
+	return fmt.Sprintf("Dimming: %d%%", dim)
+}
+
+// nextEffectDim returns the dimming preset after current in cycling order
+func nextEffectDim(current int) int {
+	for i, dim := range themes.EffectDimPresets {
+		if dim == current {
+			return themes.EffectDimPresets[(i+1)%len(themes.EffectDimPresets)]
+		}
+	}
+	return themes.EffectDimPresets[0]
+}
+
+// ImageEffectsScreen shows the effects picked for the selected pack
+func ImageEffectsScreen() (string, int) {
+	pack := app.GetSelectedEffectsPack()
+	effects := themes.GetImageEffects(pack)
+
+	menu := []string{
+		"Shadow: " + effectLevelName(effects.Shadow),
+		"Outline: " + effectLevelName(effects.Outline),
+		effectDimLabel(effects.Dim),
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", fmt.Sprintf("Effects for %s", pack))
+}
+
+// HandleImageEffects cycles the selected effect to its next strength
+func HandleImageEffects(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleImageEffects called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		pack := app.GetSelectedEffectsPack()
+		effects := themes.GetImageEffects(pack)
+
+		switch {
+		case strings.HasPrefix(selection, "Shadow:"):
+			effects.Shadow = nextEffectLevel(effects.Shadow)
+		case strings.HasPrefix(selection, "Outline:"):
+			effects.Outline = nextEffectLevel(effects.Outline)
+		case strings.HasPrefix(selection, "Dimming:"):
+			effects.Dim = nextEffectDim(effects.Dim)
+		default:
+			return app.Screens.ImageEffects
+		}
+
+		if err := themes.SetImageEffects(pack, effects); err != nil {
+			logging.LogDebug("Error saving image effects: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.ImageEffects
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.ImageEffects
+}
@@ -0,0 +1,63 @@
+// src/internal/ui/screens/kiosk_screens.go
+// Turning kiosk mode on and off
+
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// enableKioskMode asks whether to protect kiosk mode with a PIN and turns it on
+func enableKioskMode() bool {
+	options := []string{"Lock with PIN", "Lock without PIN"}
+	choice, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", "Kiosk Mode: browse only")
+	if exitCode != 0 {
+		return false
+	}
+
+	var pin []string
+	if choice == "Lock with PIN" {
+		var ok bool
+		if pin, ok = promptNewPIN(); !ok {
+			return false
+		}
+	}
+
+	if err := themes.EnableKioskMode(pin); err != nil {
+		logging.LogDebug("Error enabling kiosk mode: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return false
+	}
+
+	ui.ShowMessage("Kiosk mode is on. Themes can be browsed and previewed but not applied.", "3")
+	return true
+}
+
+// leaveKioskMode asks for the PIN, if there is one, and turns kiosk mode off
+func leaveKioskMode() {
+	var pin []string
+	if themes.KioskHasPIN() {
+		var ok bool
+		if pin, ok = promptPIN("Enter PIN"); !ok {
+			return
+		}
+	}
+
+	if err := themes.DisableKioskMode(pin); err != nil {
+		if errors.Is(err, themes.ErrWrongPIN) {
+			ui.ShowMessage("Wrong PIN.", "2")
+			return
+		}
+		logging.LogDebug("Error disabling kiosk mode: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.ShowMessage("Kiosk mode is off.", "2")
+}
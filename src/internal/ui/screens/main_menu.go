@@ -4,23 +4,46 @@
 package screens
 
 import (
-	"os"
+	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"nextui-themes/internal/app"
 	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
 	"nextui-themes/internal/ui"
 )
 
+// kioskMenu is the main menu while kiosk mode is on: browsing, previews and the way out
+var kioskMenu = []string{
+	"Installed Themes",
+	"Components",
+	"About",
+	"Leave Kiosk Mode",
+}
+
 func MainMenuScreen() (string, int) {
+	if themes.GetKioskMode() {
+		return ui.DisplayMinUiList(strings.Join(kioskMenu, "\n"), "text", "NextUI Theme Manager (Kiosk)", "--cancel-text", "QUIT")
+	}
+
 	// Updated menu items with "Deconstruct" added
 	menu := []string{
 		"Installed Themes",
+		"Reapply Current Setup",
 		"Download Themes",
+		"Search Catalog",
+		"Browse by Tag",
 		"Sync Catalog",
+		"Import from Folder",
+		"Import from URL",
 		"Components",
 		"Deconstruct", // Added the Deconstruct option to main menu (without ellipsis)
 		"Export",
+		"Submit to Catalog",
+		"Settings",
+		"About",
 	}
 
 	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "NextUI Theme Manager", "--cancel-text", "QUIT")
@@ -37,14 +60,39 @@ func HandleMainMenu(selection string, exitCode int) app.Screen {
 			logging.LogDebug("Selected Installed Themes")
 			return app.Screens.InstalledThemes
 
+		case "Reapply Current Setup":
+			logging.LogDebug("Selected Reapply Current Setup")
+			reapplyCurrentSetup()
+			return app.Screens.MainMenu
+
 		case "Download Themes":
 			logging.LogDebug("Selected Download Themes")
+			app.SetCatalogQuery("")
+			app.SetCatalogContent("")
 			return app.Screens.DownloadThemes
 
+		case "Search Catalog":
+			logging.LogDebug("Selected Search Catalog")
+			app.SetCatalogQuery("")
+			app.SetCatalogContent("")
+			return app.Screens.CatalogSearch
+
+		case "Browse by Tag":
+			logging.LogDebug("Selected Browse by Tag")
+			return app.Screens.ThemeTags
+
 		case "Sync Catalog":
 			logging.LogDebug("Selected Sync Catalog")
 			return app.Screens.SyncCatalog
 
+		case "Import from Folder":
+			logging.LogDebug("Selected Import from Folder")
+			return app.Screens.ImportFolder
+
+		case "Import from URL":
+			logging.LogDebug("Selected Import from URL")
+			return app.Screens.ImportURL
+
 		case "Components":
 			logging.LogDebug("Selected Components")
 			return app.Screens.ComponentsMenu
@@ -57,6 +105,23 @@ func HandleMainMenu(selection string, exitCode int) app.Screen {
 			logging.LogDebug("Selected Export")
 			return app.Screens.ThemeExport
 
+		case "Submit to Catalog":
+			logging.LogDebug("Selected Submit to Catalog")
+			return app.Screens.SubmitPackage
+
+		case "Settings":
+			logging.LogDebug("Selected Settings")
+			return app.Screens.SettingsMenu
+
+		case "About":
+			logging.LogDebug("Selected About")
+			return app.Screens.About
+
+		case "Leave Kiosk Mode":
+			logging.LogDebug("Selected Leave Kiosk Mode")
+			leaveKioskMode()
+			return app.Screens.MainMenu
+
 		default:
 			logging.LogDebug("Unknown selection: %s", selection)
 			return app.Screens.MainMenu
@@ -65,8 +130,78 @@ func HandleMainMenu(selection string, exitCode int) app.Screen {
 	case 1, 2:
 		// User pressed cancel or back
 		logging.LogDebug("User cancelled/exited")
-		os.Exit(0)
+		app.Exit(0)
 	}
 
 	return app.Screens.MainMenu
 }
+
+// reapplyCurrentSetup applies the recorded theme and components again, e.g. after a
+// NextUI update reset the device's media
+func reapplyCurrentSetup() {
+	steps, missing, err := themes.CurrentSetup()
+	if err != nil {
+		logging.LogDebug("Error reading current setup: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	if len(steps) == 0 {
+		if len(missing) > 0 {
+			ui.ShowMessage(fmt.Sprintf("The recorded packages are no longer installed:\n%s", strings.Join(missing, "\n")), "5")
+		} else {
+			ui.ShowMessage("Nothing has been applied yet.", "3")
+		}
+		return
+	}
+
+	importErr := themes.RunApplyWithProgress("Reapplying current setup...", func(ctx context.Context) error {
+		return themes.RunStrict(func() error {
+			return themes.ReapplyCurrentSetup(ctx, steps)
+		})
+	})
+
+	switch {
+	case errors.Is(importErr, ui.ErrCancelled):
+		logging.LogDebug("Reapply cancelled")
+		ui.ShowMessage("Apply cancelled. Files copied so far stay applied, anything removed is in the trash.", "3")
+	case importErr != nil:
+		logging.LogDebug("Error reapplying current setup: %v", importErr)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
+	case len(missing) > 0:
+		ui.ShowMessage(fmt.Sprintf("Reapplied %d packages, but these are no longer installed:\n%s", len(steps), strings.Join(missing, "\n")), "5")
+	default:
+		ui.ShowMessage(fmt.Sprintf("Reapplied %d packages!", len(steps)), "3")
+	}
+}
+
+// OfferReapplyAfterSystemUpdate asks to reapply the current setup when NextUI was updated
+// since the last run, as updates tend to overwrite fonts and settings
+func OfferReapplyAfterSystemUpdate() {
+	update := themes.CheckSystemUpdate()
+	if update == nil {
+		return
+	}
+	defer themes.AcknowledgeSystemUpdate(update)
+
+	if steps, _, err := themes.CurrentSetup(); err != nil || len(steps) == 0 {
+		return
+	}
+
+	title := "NextUI was updated. Reapply your theme and fonts?"
+	if update.Version != "" {
+		title = fmt.Sprintf("NextUI was updated to %s. Reapply your theme and fonts?", update.Version)
+	}
+
+	options := []string{
+		"Reapply",
+		"Not Now",
+	}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", title)
+	if exitCode != 0 || result != "Reapply" {
+		logging.LogDebug("Reapply after system update declined")
+		return
+	}
+
+	reapplyCurrentSetup()
+}
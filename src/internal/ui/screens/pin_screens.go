@@ -0,0 +1,103 @@
+// src/internal/ui/screens/pin_screens.go
+// Entering button sequence PINs and asking for them before protected operations
+
+package screens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// pinLength is how many buttons a PIN is made of
+const pinLength = 4
+
+// pinButtons are the buttons a PIN can be made of
+var pinButtons = []string{"Up", "Down", "Left", "Right", "A", "B", "X", "Y"}
+
+// promptPIN asks for a button sequence one button at a time. False means the user backed out.
+func promptPIN(title string) ([]string, bool) {
+	var pin []string
+	for len(pin) < pinLength {
+		progress := strings.Repeat("*", len(pin)) + strings.Repeat("-", pinLength-len(pin))
+		selection, exitCode := ui.DisplayMinUiList(strings.Join(pinButtons, "\n"), "text", fmt.Sprintf("%s %s", title, progress))
+		if exitCode != 0 {
+			return nil, false
+		}
+		pin = append(pin, selection)
+	}
+	return pin, true
+}
+
+// promptNewPIN asks for a new PIN twice. False means the user backed out or the two didn't match.
+func promptNewPIN() ([]string, bool) {
+	pin, ok := promptPIN("New PIN")
+	if !ok {
+		return nil, false
+	}
+	confirm, ok := promptPIN("Repeat PIN")
+	if !ok {
+		return nil, false
+	}
+	if strings.Join(confirm, "-") != strings.Join(pin, "-") {
+		ui.ShowMessage("The PINs don't match, nothing was changed.", "3")
+		return nil, false
+	}
+	return pin, true
+}
+
+// confirmProtectionPIN asks for the protection PIN before a protected operation. It passes
+// straight through when no PIN is set.
+func confirmProtectionPIN(operation string) bool {
+	if !themes.HasProtectionPIN() {
+		return true
+	}
+
+	pin, ok := promptPIN(fmt.Sprintf("PIN to %s", operation))
+	if !ok {
+		return false
+	}
+
+	if err := themes.CheckProtectionPIN(pin); err != nil {
+		if errors.Is(err, themes.ErrWrongPIN) {
+			ui.ShowMessage("Wrong PIN.", "2")
+			return false
+		}
+		logging.LogDebug("Error checking PIN: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return false
+	}
+	return true
+}
+
+// protectionPINLabel returns the settings menu entry showing whether protected operations ask for a PIN
+func protectionPINLabel() string {
+	if themes.HasProtectionPIN() {
+		return "[x] PIN for Restores"
+	}
+	return "[ ] PIN for Restores"
+}
+
+// toggleProtectionPIN sets a new protection PIN, or removes it once the current one is entered
+func toggleProtectionPIN() {
+	var pin []string
+	if themes.HasProtectionPIN() {
+		if !confirmProtectionPIN("remove PIN") {
+			return
+		}
+	} else {
+		var ok bool
+		if pin, ok = promptNewPIN(); !ok {
+			return
+		}
+	}
+
+	if err := themes.SetProtectionPIN(pin); err != nil {
+		logging.LogDebug("Error setting protection PIN: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
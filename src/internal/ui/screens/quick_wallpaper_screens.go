@@ -0,0 +1,128 @@
+// src/internal/ui/screens/quick_wallpaper_screens.go
+// Screens for swapping one slot's wallpaper for an image from any installed wallpaper pack
+
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// quickWallpaperLabels returns the wallpaper slots as listed, slots with a quick wallpaper
+// showing the pack it came from, and the slot behind each label
+func quickWallpaperLabels() ([]string, map[string]string, error) {
+	slots, err := themes.ListWallpaperSlots()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	overrides := themes.GetWallpaperOverrides()
+	labels := make([]string, 0, len(slots))
+	slotForLabel := make(map[string]string, len(slots))
+	for _, slot := range slots {
+		label := slot
+		if source, ok := overrides[slot]; ok {
+			pack := strings.SplitN(source, "/", 2)[0]
+			label = fmt.Sprintf("%s [%s]", slot, strings.TrimSuffix(pack, ".bg"))
+		}
+		labels = append(labels, label)
+		slotForLabel[label] = slot
+	}
+	return labels, slotForLabel, nil
+}
+
+// QuickWallpapersScreen lists every wallpaper slot: Root, Recently Played, Tools,
+// Collections and each system
+func QuickWallpapersScreen() (string, int) {
+	labels, _, err := quickWallpaperLabels()
+	if err != nil {
+		logging.LogDebug("Error listing wallpaper slots: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(labels, "\n"), "text", "Quick Wallpapers")
+}
+
+// HandleQuickWallpapers opens the image picker for the selected slot
+func HandleQuickWallpapers(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleQuickWallpapers called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		_, slotForLabel, err := quickWallpaperLabels()
+		if err != nil {
+			logging.LogDebug("Error listing wallpaper slots: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.ComponentOptions
+		}
+
+		slot, ok := slotForLabel[selection]
+		if !ok {
+			return app.Screens.QuickWallpapers
+		}
+		app.SetSelectedWallpaperSlot(slot)
+		return app.Screens.QuickWallpaperSource
+
+	case 1, 2:
+		return app.Screens.ComponentOptions
+	}
+
+	return app.Screens.QuickWallpapers
+}
+
+// QuickWallpaperSourceScreen shows the images of every installed wallpaper pack to pick from
+func QuickWallpaperSourceScreen() (string, int) {
+	slot := app.GetSelectedWallpaperSlot()
+
+	sources, err := themes.ListQuickWallpaperSources()
+	if err != nil {
+		logging.LogDebug("Error listing wallpaper pack images: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+	if len(sources) == 0 {
+		ui.ShowMessage("No installed wallpaper packs found.", "3")
+		return "", 1
+	}
+
+	items := make([]ui.GalleryItem, 0, len(sources))
+	for _, source := range sources {
+		sourcePath, err := themes.QuickWallpaperSourcePath(source)
+		if err != nil {
+			continue
+		}
+		items = append(items, ui.GalleryItem{Text: source, BackgroundImage: sourcePath})
+	}
+
+	return ui.DisplayImageGallery(items, fmt.Sprintf("Wallpaper for %s", slot))
+}
+
+// HandleQuickWallpaperSource puts the picked image on the selected slot
+func HandleQuickWallpaperSource(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleQuickWallpaperSource called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		slot := app.GetSelectedWallpaperSlot()
+		if err := themes.SetQuickWallpaper(slot, selection); err != nil {
+			logging.LogDebug("Error setting quick wallpaper: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.QuickWallpaperSource
+		}
+
+		ui.ShowMessage(fmt.Sprintf("%s now uses %s", slot, filepath.Base(selection)), "2")
+		return app.Screens.QuickWallpapers
+
+	case 1, 2:
+		return app.Screens.QuickWallpapers
+	}
+
+	return app.Screens.QuickWallpaperSource
+}
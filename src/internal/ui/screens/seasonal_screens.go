@@ -0,0 +1,184 @@
+// src/internal/ui/screens/seasonal_screens.go
+// Seasonal theme schedules and the launch check that applies and reverts them
+
+package screens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// addSeasonalLabel is the list entry that starts adding a schedule
+const addSeasonalLabel = "Add Seasonal Theme"
+
+// RunSeasonalThemes applies or reverts seasonal themes for today when the pak starts, in case
+// the device wasn't booted since their dates came up
+func RunSeasonalThemes() {
+	change := themes.DueSeasonalChange(time.Now())
+	if change == nil {
+		return
+	}
+
+	err := themes.RunApplyWithProgress(change.Message(), func(ctx context.Context) error {
+		return themes.RunSeasonalChange(ctx, change)
+	})
+
+	switch {
+	case errors.Is(err, ui.ErrCancelled):
+		logging.LogDebug("Seasonal theme change cancelled")
+		ui.ShowMessage("Seasonal theme change cancelled, it will be tried again next time.", "3")
+	case err != nil:
+		logging.LogDebug("Error changing seasonal theme: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// SeasonalThemesScreen lists the seasonal theme schedules
+func SeasonalThemesScreen() (string, int) {
+	var options []string
+	for _, schedule := range themes.GetSeasonalSchedules() {
+		options = append(options, schedule.Label())
+	}
+	options = append(options, addSeasonalLabel)
+
+	return ui.DisplayMinUiList(strings.Join(options, "\n"), "text", "Seasonal Themes")
+}
+
+// HandleSeasonalThemes adds a schedule or offers to remove the selected one
+func HandleSeasonalThemes(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleSeasonalThemes called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == addSeasonalLabel {
+			addSeasonalTheme()
+			return app.Screens.SeasonalThemes
+		}
+
+		for i, schedule := range themes.GetSeasonalSchedules() {
+			if schedule.Label() == selection {
+				removeSeasonalTheme(i, schedule)
+				break
+			}
+		}
+		return app.Screens.SeasonalThemes
+
+	case 1, 2:
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.SeasonalThemes
+}
+
+// addSeasonalTheme asks for a theme and its first and last day, then saves the schedule
+func addSeasonalTheme() {
+	themeDirs, err := filepath.Glob(filepath.Join(app.GetWorkingDir(), "Themes", "*.theme"))
+	if err != nil || len(themeDirs) == 0 {
+		ui.ShowMessage("No themes found in Themes directory", "3")
+		return
+	}
+
+	var themeNames []string
+	for _, dir := range themeDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			themeNames = append(themeNames, filepath.Base(dir))
+		}
+	}
+
+	theme, exitCode := ui.DisplayMinUiList(strings.Join(themeNames, "\n"), "text", "Seasonal Theme")
+	if exitCode != 0 || theme == "" {
+		return
+	}
+
+	start, ok := pickSeasonalDate(fmt.Sprintf("Apply %s from", theme))
+	if !ok {
+		return
+	}
+	end, ok := pickSeasonalDate(fmt.Sprintf("Apply %s from %s until", theme, themes.FormatSeasonalDate(start)))
+	if !ok {
+		return
+	}
+
+	schedule := themes.SeasonalSchedule{
+		Theme: theme,
+		Start: start,
+		End:   end,
+	}
+	if err := themes.AddSeasonalSchedule(schedule); err != nil {
+		logging.LogDebug("Error adding seasonal theme: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	// Apply right away when today is already in the window
+	RunSeasonalThemes()
+}
+
+// pickSeasonalDate asks for a month and a day and returns them as MM-DD
+func pickSeasonalDate(title string) (string, bool) {
+	var months []string
+	for month := time.January; month <= time.December; month++ {
+		months = append(months, month.String())
+	}
+
+	monthName, exitCode := ui.DisplayMinUiList(strings.Join(months, "\n"), "text", title)
+	if exitCode != 0 || monthName == "" {
+		return "", false
+	}
+
+	month := time.January
+	for i, name := range months {
+		if name == monthName {
+			month = time.Month(i + 1)
+		}
+	}
+
+	// A leap year, so February 29 can be picked
+	daysInMonth := time.Date(2024, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	var days []string
+	for day := 1; day <= daysInMonth; day++ {
+		days = append(days, strconv.Itoa(day))
+	}
+
+	dayText, exitCode := ui.DisplayMinUiList(strings.Join(days, "\n"), "text", fmt.Sprintf("%s %s", title, monthName))
+	if exitCode != 0 || dayText == "" {
+		return "", false
+	}
+
+	day, err := strconv.Atoi(dayText)
+	if err != nil {
+		return "", false
+	}
+	return fmt.Sprintf("%02d-%02d", int(month), day), true
+}
+
+// removeSeasonalTheme confirms and removes a schedule, reverting its theme if it is applied
+func removeSeasonalTheme(index int, schedule themes.SeasonalSchedule) {
+	options := []string{
+		"Yes",
+		"No",
+	}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", fmt.Sprintf("Remove %s?", schedule.Label()))
+	if exitCode != 0 || result != "Yes" {
+		return
+	}
+
+	if err := themes.RemoveSeasonalSchedule(index); err != nil {
+		logging.LogDebug("Error removing seasonal theme: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	RunSeasonalThemes()
+}
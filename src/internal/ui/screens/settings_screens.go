@@ -0,0 +1,1080 @@
+// src/internal/ui/screens/settings_screens.go
+// Implementation of the settings menu and its sub-screens
+
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/system"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// sdcardRoot is stripped from paths to keep long file lists readable
+const sdcardRoot = "/mnt/SDCARD"
+
+// SettingsMenuScreen displays the settings menu
+func SettingsMenuScreen() (string, int) {
+	menu := []string{
+		"Pinned Files",
+		"Excluded Systems",
+		"Storage Roots",
+		"List Dimming",
+		iconMaskLabel(),
+		animatedPolicyLabel(),
+		"Settings Snapshot",
+		"Font Subsetting",
+		accessibleUILabel(),
+		strictModeLabel(),
+		"Lint Packages",
+		volumeSizeLabel(),
+		exportFolderLabel(),
+		zipThemesLabel(),
+		autoDeconstructLabel(),
+		hardLinkLabel(),
+		verifyWritesLabel(),
+		batteryGuardLabel(),
+		cleanupPolicyLabel(),
+		previewWatermarkLabel(),
+		logFormatLabel(),
+		keepVersionsLabel(),
+		"Package Versions",
+		"Purge Old Versions",
+		"Restore from Trash",
+		"Empty Trash",
+		"Seasonal Themes",
+		"Find Duplicates",
+		"Regenerate Manifests",
+		"Migrate Legacy Themes",
+		"Fix Collection Structure",
+		"Kiosk Mode",
+		protectionPINLabel(),
+	}
+
+	title := "Settings"
+	if summary := themes.LastApplySummary(); summary != "" {
+		title = fmt.Sprintf("Settings (%s)", summary)
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", title)
+}
+
+// accessibleUILabel returns the settings menu entry showing whether accessible mode is on
+func accessibleUILabel() string {
+	if themes.GetAccessibleUISetting() {
+		return "[x] Large Text & High Contrast"
+	}
+	return "[ ] Large Text & High Contrast"
+}
+
+// strictModeLabel returns the settings menu entry showing whether strict mode is on
+func strictModeLabel() string {
+	if themes.IsStrictMode() {
+		return "[x] Strict Mode"
+	}
+	return "[ ] Strict Mode"
+}
+
+// batteryGuardLabel returns the settings menu entry showing the battery guard threshold
+func batteryGuardLabel() string {
+	if threshold := themes.BatteryGuardThreshold(themes.GetBatteryGuardSetting()); threshold > 0 {
+		return fmt.Sprintf("Battery Guard: %d%%", threshold)
+	}
+	return "Battery Guard: Off"
+}
+
+// cycleBatteryGuard advances the battery guard to the next preset
+func cycleBatteryGuard() {
+	current := themes.GetBatteryGuardSetting()
+	next := themes.BatteryGuardPresets[0]
+	for i, setting := range themes.BatteryGuardPresets {
+		if setting == current {
+			next = themes.BatteryGuardPresets[(i+1)%len(themes.BatteryGuardPresets)]
+			break
+		}
+	}
+
+	if err := themes.SetBatteryGuardSetting(next); err != nil {
+		logging.LogDebug("Error saving battery guard: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// keepVersionsLabel returns the settings menu entry showing how many previous versions are kept
+func keepVersionsLabel() string {
+	if count := themes.KeepVersionsCount(themes.GetKeepVersionsSetting()); count > 0 {
+		return fmt.Sprintf("Keep Versions: %d", count)
+	}
+	return "Keep Versions: Off"
+}
+
+// cycleKeepVersions advances the number of kept versions to the next preset
+func cycleKeepVersions() {
+	current := themes.GetKeepVersionsSetting()
+	next := themes.KeepVersionsPresets[0]
+	for i, setting := range themes.KeepVersionsPresets {
+		if setting == current {
+			next = themes.KeepVersionsPresets[(i+1)%len(themes.KeepVersionsPresets)]
+			break
+		}
+	}
+
+	if err := themes.SetKeepVersionsSetting(next); err != nil {
+		logging.LogDebug("Error saving keep versions setting: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// cleanupPolicyLabel returns the settings menu entry showing the cleanup policy
+func cleanupPolicyLabel() string {
+	if themes.GetCleanupPolicy() == themes.CleanupMerge {
+		return "Overlay Cleanup: Merge"
+	}
+	return "Overlay Cleanup: Replace All"
+}
+
+// cycleCleanupPolicy advances the cleanup policy to the next one
+func cycleCleanupPolicy() {
+	current := themes.GetCleanupPolicy()
+	next := themes.CleanupPolicies[0]
+	for i, policy := range themes.CleanupPolicies {
+		if policy == current {
+			next = themes.CleanupPolicies[(i+1)%len(themes.CleanupPolicies)]
+			break
+		}
+	}
+
+	if err := themes.SetCleanupPolicy(next); err != nil {
+		logging.LogDebug("Error saving cleanup policy: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// iconMaskLabel returns the settings menu entry showing the shape applied icons are cut to
+func iconMaskLabel() string {
+	switch themes.GetIconMaskSetting() {
+	case themes.IconMaskCircle:
+		return "Icon Shape: Circle"
+	case themes.IconMaskSquircle:
+		return "Icon Shape: Squircle"
+	case themes.IconMaskRounded:
+		return "Icon Shape: Rounded Square"
+	default:
+		return "Icon Shape: As Packaged"
+	}
+}
+
+// cycleIconMask advances the icon shape to the next one
+func cycleIconMask() {
+	current := themes.GetIconMaskSetting()
+	next := themes.IconMasks[0]
+	for i, mask := range themes.IconMasks {
+		if mask == current {
+			next = themes.IconMasks[(i+1)%len(themes.IconMasks)]
+			break
+		}
+	}
+
+	if err := themes.SetIconMaskSetting(next); err != nil {
+		logging.LogDebug("Error saving icon shape: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// animatedPolicyLabel returns the settings menu entry showing what applies do with animated wallpapers
+func animatedPolicyLabel() string {
+	if themes.GetAnimatedPolicy() == themes.AnimatedReject {
+		return "Animated Wallpapers: Leave Out"
+	}
+	return "Animated Wallpapers: Still Frame"
+}
+
+// cycleAnimatedPolicy advances the animated wallpaper policy to the next one
+func cycleAnimatedPolicy() {
+	current := themes.GetAnimatedPolicy()
+	next := themes.AnimatedPolicies[0]
+	for i, policy := range themes.AnimatedPolicies {
+		if policy == current {
+			next = themes.AnimatedPolicies[(i+1)%len(themes.AnimatedPolicies)]
+			break
+		}
+	}
+
+	if err := themes.SetAnimatedPolicy(next); err != nil {
+		logging.LogDebug("Error saving animated wallpaper policy: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// logFormatLabel returns the settings menu entry showing the log format
+func logFormatLabel() string {
+	switch themes.GetLogFormatSetting() {
+	case logging.LogFormatJSON:
+		return "Log Format: JSON"
+	case logging.LogFormatBoth:
+		return "Log Format: Text + JSON"
+	default:
+		return "Log Format: Text"
+	}
+}
+
+// cycleLogFormat advances the log format to the next one
+func cycleLogFormat() {
+	current := themes.GetLogFormatSetting()
+	next := logging.LogFormats[0]
+	for i, format := range logging.LogFormats {
+		if format == current {
+			next = logging.LogFormats[(i+1)%len(logging.LogFormats)]
+			break
+		}
+	}
+
+	if err := themes.SetLogFormatSetting(next); err != nil {
+		logging.LogDebug("Error saving log format: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// previewWatermarkLabel returns the settings menu entry showing whether exported previews are watermarked
+func previewWatermarkLabel() string {
+	if themes.GetPreviewWatermarkSetting() {
+		return "[x] Watermark Previews"
+	}
+	return "[ ] Watermark Previews"
+}
+
+// verifyWritesLabel returns the settings menu entry showing whether writes are verified
+func verifyWritesLabel() string {
+	if themes.GetVerifyWritesSetting() {
+		return "[x] Verify Writes"
+	}
+	return "[ ] Verify Writes"
+}
+
+// zipThemesLabel returns the settings menu entry showing whether themes are kept zipped
+func zipThemesLabel() string {
+	if themes.GetZipThemesSetting() {
+		return "[x] Zip Exported & Downloaded Themes"
+	}
+	return "[ ] Zip Exported & Downloaded Themes"
+}
+
+// autoDeconstructLabel returns the settings menu entry showing whether downloads are deconstructed
+func autoDeconstructLabel() string {
+	if themes.GetAutoDeconstructSetting() {
+		return "[x] Deconstruct Downloaded Themes"
+	}
+	return "[ ] Deconstruct Downloaded Themes"
+}
+
+// hardLinkLabel returns the settings menu entry showing whether hard-link apply is on
+func hardLinkLabel() string {
+	if themes.GetHardLinkSetting() {
+		return "[x] Save Space with Hard Links"
+	}
+	return "[ ] Save Space with Hard Links"
+}
+
+// volumeSizeLabel returns the settings menu entry showing the export volume size
+func volumeSizeLabel() string {
+	if size := themes.GetVolumeSizeSetting(); size > 0 {
+		return fmt.Sprintf("Split Exports: %d MB", size)
+	}
+	return "Split Exports: Off"
+}
+
+// cycleVolumeSize advances the export volume size to the next preset
+func cycleVolumeSize() {
+	current := themes.GetVolumeSizeSetting()
+	next := themes.VolumeSizePresets[0]
+	for i, size := range themes.VolumeSizePresets {
+		if size == current {
+			next = themes.VolumeSizePresets[(i+1)%len(themes.VolumeSizePresets)]
+			break
+		}
+	}
+
+	if err := themes.SetVolumeSizeSetting(next); err != nil {
+		logging.LogDebug("Error saving volume size: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// exportFolderLabel returns the settings menu entry showing where exports are written
+func exportFolderLabel() string {
+	if dir := themes.GetExportDirSetting(); dir != "" {
+		return "Export Folder: " + dir
+	}
+	return "Export Folder: Pak"
+}
+
+// cycleExportFolder advances the export folder through the pak and any mounted external storage
+func cycleExportFolder() {
+	folders := []string{""}
+	for _, root := range themes.ExternalStorageRoots() {
+		folders = append(folders, themes.ExternalExportDir(root))
+	}
+
+	current := themes.GetExportDirSetting()
+	next := folders[0]
+	for i, folder := range folders {
+		if folder == current {
+			next = folders[(i+1)%len(folders)]
+			break
+		}
+	}
+
+	if len(folders) == 1 && current == "" {
+		ui.ShowMessage("No USB drive or other storage found to export to.", "3")
+		return
+	}
+
+	if err := themes.SetExportDirSetting(next); err != nil {
+		logging.LogDebug("Error saving export folder: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	}
+}
+
+// HandleSettingsMenu processes the settings menu selection
+func HandleSettingsMenu(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleSettingsMenu called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		switch selection {
+		case "Pinned Files":
+			return app.Screens.PinnedFiles
+		case "Excluded Systems":
+			return app.Screens.ExcludedSystems
+		case "Storage Roots":
+			return app.Screens.StorageRoots
+		case "Kiosk Mode":
+			if enableKioskMode() {
+				return app.Screens.MainMenu
+			}
+		case protectionPINLabel():
+			toggleProtectionPIN()
+		case "List Dimming":
+			return app.Screens.ListScrim
+		case "Settings Snapshot":
+			return app.Screens.SettingsSnapshot
+		case "Font Subsetting":
+			return app.Screens.FontSubset
+		case "Lint Packages":
+			return app.Screens.LintPackages
+		case volumeSizeLabel():
+			cycleVolumeSize()
+		case exportFolderLabel():
+			cycleExportFolder()
+		case batteryGuardLabel():
+			cycleBatteryGuard()
+		case cleanupPolicyLabel():
+			cycleCleanupPolicy()
+		case iconMaskLabel():
+			cycleIconMask()
+		case animatedPolicyLabel():
+			cycleAnimatedPolicy()
+		case accessibleUILabel():
+			if err := themes.SetAccessibleUISetting(!themes.GetAccessibleUISetting()); err != nil {
+				logging.LogDebug("Error saving accessible mode setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case logFormatLabel():
+			cycleLogFormat()
+		case keepVersionsLabel():
+			cycleKeepVersions()
+		case "Package Versions":
+			return app.Screens.PackageVersions
+		case "Purge Old Versions":
+			purgePackageVersions()
+		case "Restore from Trash":
+			restoreTrash()
+		case "Empty Trash":
+			emptyTrash()
+		case "Seasonal Themes":
+			return app.Screens.SeasonalThemes
+		case previewWatermarkLabel():
+			if err := themes.SetPreviewWatermarkSetting(!themes.GetPreviewWatermarkSetting()); err != nil {
+				logging.LogDebug("Error saving preview watermark setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case verifyWritesLabel():
+			if err := themes.SetVerifyWritesSetting(!themes.GetVerifyWritesSetting()); err != nil {
+				logging.LogDebug("Error saving verify writes setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case zipThemesLabel():
+			if err := themes.SetZipThemesSetting(!themes.GetZipThemesSetting()); err != nil {
+				logging.LogDebug("Error saving zip themes setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case autoDeconstructLabel():
+			if err := themes.SetAutoDeconstructSetting(!themes.GetAutoDeconstructSetting()); err != nil {
+				logging.LogDebug("Error saving auto deconstruct setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case hardLinkLabel():
+			if err := themes.SetHardLinkSetting(!themes.GetHardLinkSetting()); err != nil {
+				logging.LogDebug("Error saving hard link setting: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		case "Find Duplicates":
+			findDuplicates()
+		case "Regenerate Manifests":
+			regenerateManifests()
+		case "Migrate Legacy Themes":
+			migrateLegacyThemes()
+		case "Fix Collection Structure":
+			fixCollectionStructure()
+		case strictModeLabel():
+			if err := themes.SetStrictMode(!themes.IsStrictMode()); err != nil {
+				logging.LogDebug("Error saving strict mode: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			}
+		}
+		return app.Screens.SettingsMenu
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.SettingsMenu
+}
+
+// restoreTrash lists the applies with files in the trash and puts the picked one's files back
+func restoreTrash() {
+	batches, err := themes.ListTrash()
+	if err != nil {
+		logging.LogDebug("Error listing trash: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	if len(batches) == 0 {
+		ui.ShowMessage("The trash is empty.", "3")
+		return
+	}
+
+	var items []string
+	byItem := make(map[string]themes.TrashBatch)
+	for _, batch := range batches {
+		item := fmt.Sprintf("%s (%d files)", batch.Created.Format("2006-01-02 15:04:05"), batch.Files)
+		items = append(items, item)
+		byItem[item] = batch
+	}
+
+	selection, code := ui.DisplayMinUiList(strings.Join(items, "\n"), "text", "Restore which apply?")
+	if code != 0 {
+		return
+	}
+	batch, ok := byItem[selection]
+	if !ok {
+		return
+	}
+
+	restored, skipped, err := themes.RestoreTrashBatch(batch.Name)
+	if err != nil {
+		logging.LogDebug("Error restoring trash: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	if skipped > 0 {
+		ui.ShowMessage(fmt.Sprintf("Restored %d files. %d were left in the trash because their place is taken again.", restored, skipped), "5")
+		return
+	}
+	ui.ShowMessage(fmt.Sprintf("Restored %d files.", restored), "3")
+}
+
+// emptyTrash permanently deletes the files earlier applies moved to the trash, after the user
+// confirms and enters the protection PIN
+func emptyTrash() {
+	options := []string{
+		"Yes",
+		"No",
+	}
+	result, promptCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text",
+		"Empty the trash? Files removed by earlier applies can't be recovered afterwards.")
+	if promptCode != 0 || result != "Yes" {
+		return
+	}
+	if !confirmProtectionPIN("empty the trash") {
+		return
+	}
+
+	count, err := themes.EmptyTrash()
+	if err != nil {
+		logging.LogDebug("Error emptying trash: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	ui.ShowMessage(fmt.Sprintf("Trash emptied (%d applies).", count), "3")
+}
+
+// regenerateManifests rebuilds every installed package's manifest with a progress message
+func regenerateManifests() {
+	var failed []string
+	err := ui.ShowMessageWithProgress("Regenerating manifests...", func(update func(string)) error {
+		var err error
+		failed, err = themes.RegenerateAllManifests(func(current, total int, name string) {
+			update(fmt.Sprintf("Regenerating manifests (%d/%d)\n%s", current, total, name))
+		})
+		return err
+	})
+
+	switch {
+	case err != nil:
+		logging.LogDebug("Error regenerating manifests: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	case len(failed) > 0:
+		ui.ShowMessage(fmt.Sprintf("Manifests regenerated, but %d failed:\n%s", len(failed), strings.Join(failed, "\n")), "5")
+	default:
+		ui.ShowMessage("All manifests regenerated successfully!", "3")
+	}
+}
+
+// findDuplicates scans the library for identical files and lists where they are
+func findDuplicates() {
+	var report *themes.DuplicateReport
+	err := ui.ShowMessageWithProgress("Looking for duplicates...", func(update func(string)) error {
+		var err error
+		report, err = themes.FindDuplicateAssets(func(current, total int, name string) {
+			update(fmt.Sprintf("Looking for duplicates (%d/%d)\n%s", current, total, name))
+		})
+		return err
+	})
+	if err != nil {
+		logging.LogDebug("Error finding duplicates: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	logging.LogDebug("Duplicate report:\n%s", themes.FormatDuplicateReport(report))
+
+	if len(report.Groups) == 0 {
+		ui.ShowMessage(fmt.Sprintf("No duplicates found in %d files.", report.Scanned), "3")
+		return
+	}
+
+	// Packages sharing the most come first, they are the best candidates to consolidate
+	var lines []string
+	for _, overlap := range report.Overlaps {
+		lines = append(lines, fmt.Sprintf("%s + %s: %d files, %s",
+			overlap.First, overlap.Second, overlap.Files, themes.FormatSize(overlap.Size)))
+	}
+	for _, group := range report.Groups {
+		lines = append(lines, fmt.Sprintf("%d copies of %s (%s each)",
+			len(group.Files), filepath.Base(group.Files[0]), themes.FormatSize(group.Size)))
+		for _, file := range group.Files {
+			lines = append(lines, "   "+file)
+		}
+	}
+
+	ui.DisplayMinUiList(strings.Join(lines, "\n"), "text",
+		fmt.Sprintf("%d duplicates, %s wasted", len(report.Groups), themes.FormatSize(report.Wasted)))
+}
+
+// migrateLegacyThemes converts installed themes still using the legacy wallpaper layout
+func migrateLegacyThemes() {
+	var migrated int
+	var failed []string
+	err := ui.ShowMessageWithOperation("Migrating legacy themes...", func() error {
+		var err error
+		migrated, failed, err = themes.MigrateAllLegacyThemes()
+		return err
+	})
+
+	switch {
+	case err != nil:
+		logging.LogDebug("Error migrating legacy themes: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	case len(failed) > 0:
+		ui.ShowMessage(fmt.Sprintf("Migrated %d themes, but %d failed:\n%s", migrated, len(failed), strings.Join(failed, "\n")), "5")
+	case migrated == 0:
+		ui.ShowMessage("No themes use the legacy layout.", "3")
+	default:
+		ui.ShowMessage(fmt.Sprintf("Migrated %d themes to the current layout!", migrated), "3")
+	}
+}
+
+// fixCollectionStructure lists what is wrong in the Collections folder and repairs it on confirmation
+func fixCollectionStructure() {
+	issues, err := themes.AuditCollectionStructure()
+	if err != nil {
+		logging.LogDebug("Error auditing collections: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	if len(issues) == 0 {
+		ui.ShowMessage("All collections are set up correctly.", "3")
+		return
+	}
+
+	repairable := 0
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, fmt.Sprintf("%s: %s", issue.Collection, issue.Problem))
+		lines = append(lines, "   "+issue.Fix)
+		if issue.CanRepair() {
+			repairable++
+		}
+	}
+	ui.DisplayMinUiList(strings.Join(lines, "\n"), "text", fmt.Sprintf("%d collection problems", len(issues)))
+
+	if repairable == 0 {
+		ui.ShowMessage("None of these can be fixed automatically.", "3")
+		return
+	}
+
+	options := []string{
+		"Yes",
+		"No",
+	}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", fmt.Sprintf("Fix %d problems now?", repairable))
+	if exitCode != 0 || result != "Yes" {
+		return
+	}
+
+	var fixed int
+	var remaining []themes.CollectionIssue
+	err = ui.ShowMessageWithOperation("Fixing collections...", func() error {
+		var err error
+		fixed, remaining, err = themes.RepairCollectionStructure()
+		return err
+	})
+
+	switch {
+	case err != nil:
+		logging.LogDebug("Error repairing collections: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+	case len(remaining) > 0:
+		var left []string
+		for _, issue := range remaining {
+			left = append(left, fmt.Sprintf("%s: %s", issue.Collection, issue.Fix))
+		}
+		ui.ShowMessage(fmt.Sprintf("Fixed %d problems, %d left:\n%s", fixed, len(remaining), strings.Join(left, "\n")), "5")
+	default:
+		ui.ShowMessage(fmt.Sprintf("Fixed %d problems!", fixed), "3")
+	}
+}
+
+// PinnedFilesScreen lists device media files and lets the user pin or unpin them
+func PinnedFilesScreen() (string, int) {
+	files, err := themes.ListPinnableFiles()
+	if err != nil {
+		logging.LogDebug("Error listing pinnable files: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	// Keep pinned files listed even if they are currently missing
+	pinned := make(map[string]bool)
+	for _, path := range themes.GetPinnedPaths() {
+		pinned[filepath.Clean(path)] = true
+	}
+
+	listed := make(map[string]bool)
+	for _, file := range files {
+		listed[file] = true
+	}
+	for path := range pinned {
+		if !listed[path] {
+			files = append(files, path)
+		}
+	}
+
+	if len(files) == 0 {
+		logging.LogDebug("No pinnable files found")
+		ui.ShowMessage("No wallpapers, icons or overlays found.", "3")
+		return "", 1
+	}
+
+	var menu []string
+	for _, file := range files {
+		display := strings.TrimPrefix(file, sdcardRoot+"/")
+		if pinned[file] {
+			menu = append(menu, "[x] "+display)
+		} else {
+			menu = append(menu, "[ ] "+display)
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Pinned Files")
+}
+
+// HandlePinnedFiles toggles the pin on the selected file
+func HandlePinnedFiles(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandlePinnedFiles called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		display := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		if display == "" {
+			return app.Screens.PinnedFiles
+		}
+
+		path := display
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(sdcardRoot, display)
+		}
+
+		if err := themes.TogglePinnedPath(path); err != nil {
+			logging.LogDebug("Error toggling pinned file: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.PinnedFiles
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.PinnedFiles
+}
+
+// ExcludedSystemsScreen lists installed systems and lets the user exclude them from theming
+func ExcludedSystemsScreen() (string, int) {
+	systemPaths, err := system.GetSystemPaths()
+	if err != nil {
+		logging.LogDebug("Error getting system paths: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	excluded := make(map[string]bool)
+	for _, tag := range themes.GetExcludedSystems() {
+		excluded[system.NameKey(tag)] = true
+	}
+
+	var menu []string
+	for _, sys := range systemPaths.Systems {
+		if sys.Tag == "" {
+			continue
+		}
+
+		if excluded[system.NameKey(sys.Tag)] {
+			menu = append(menu, "[x] "+sys.Name)
+		} else {
+			menu = append(menu, "[ ] "+sys.Name)
+		}
+	}
+
+	if len(menu) == 0 {
+		logging.LogDebug("No systems with tags found")
+		ui.ShowMessage("No systems with tags found", "3")
+		return "", 1
+	}
+
+	// Sort by system name, ignoring the checkbox prefix
+	sort.Slice(menu, func(i, j int) bool {
+		return menu[i][4:] < menu[j][4:]
+	})
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Excluded Systems")
+}
+
+// HandleExcludedSystems toggles exclusion for the selected system
+func HandleExcludedSystems(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleExcludedSystems called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		// Extract system tag from selection "System Name (TAG)"
+		re := regexp.MustCompile(`\((.*?)\)`)
+		matches := re.FindStringSubmatch(selection)
+		if len(matches) < 2 {
+			return app.Screens.ExcludedSystems
+		}
+
+		if err := themes.ToggleExcludedSystem(matches[1]); err != nil {
+			logging.LogDebug("Error toggling excluded system: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.ExcludedSystems
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.ExcludedSystems
+}
+
+// StorageRootsScreen lists mounted storage with a Roms folder, ticked when it is themed with
+// the SD card
+func StorageRootsScreen() (string, int) {
+	roots := themes.CandidateStorageRoots()
+	if len(roots) == 0 {
+		ui.ShowMessage("No other storage with a Roms folder is connected.", "3")
+		return "", 1
+	}
+
+	themed := make(map[string]bool)
+	for _, root := range themes.GetStorageRoots() {
+		themed[root] = true
+	}
+
+	var menu []string
+	for _, root := range roots {
+		if themed[root] {
+			menu = append(menu, "[x] "+root)
+		} else {
+			menu = append(menu, "[ ] "+root)
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Storage Roots")
+}
+
+// HandleStorageRoots toggles whether the selected storage is themed with the SD card
+func HandleStorageRoots(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleStorageRoots called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		root := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		if err := themes.ToggleStorageRoot(root); err != nil {
+			logging.LogDebug("Error toggling storage root: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.StorageRoots
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.StorageRoots
+}
+
+// FontSubsetScreen lets the author tick the character ranges exported fonts keep
+func FontSubsetScreen() (string, int) {
+	kept := make(map[string]bool)
+	for _, key := range themes.GetFontSubset() {
+		kept[key] = true
+	}
+
+	var menu []string
+	for _, r := range themes.FontSubsetRanges {
+		if kept[r.Key] {
+			menu = append(menu, "[x] "+r.Label)
+		} else {
+			menu = append(menu, "[ ] "+r.Label)
+		}
+	}
+
+	title := "Font Subsetting (Off)"
+	if len(kept) > 0 {
+		title = "Font Subsetting (Basic Latin + Ticked)"
+	}
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", title)
+}
+
+// HandleFontSubset toggles the selected character range
+func HandleFontSubset(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleFontSubset called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		label := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		for _, r := range themes.FontSubsetRanges {
+			if r.Label == label {
+				if err := themes.ToggleFontSubsetRange(r.Key); err != nil {
+					logging.LogDebug("Error saving font subset: %v", err)
+					ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				}
+			}
+		}
+		return app.Screens.FontSubset
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.FontSubset
+}
+
+// SettingsSnapshotScreen lists the shared NextUI settings files that can be exported with themes
+func SettingsSnapshotScreen() (string, int) {
+	files, err := themes.ListSharedSettingsFiles()
+	if err != nil {
+		logging.LogDebug("Error listing settings files: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(files) == 0 {
+		ui.ShowMessage("No other settings files found.", "3")
+		return "", 1
+	}
+
+	allowed := make(map[string]bool)
+	for _, name := range themes.GetSettingsAllowlist() {
+		allowed[name] = true
+	}
+
+	var menu []string
+	for _, name := range files {
+		if allowed[name] {
+			menu = append(menu, "[x] "+name)
+		} else {
+			menu = append(menu, "[ ] "+name)
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Settings Snapshot")
+}
+
+// HandleSettingsSnapshot adds or removes the selected file from the settings allowlist
+func HandleSettingsSnapshot(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleSettingsSnapshot called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if len(selection) <= 4 {
+			return app.Screens.SettingsSnapshot
+		}
+
+		if err := themes.ToggleSettingsAllowlist(selection[4:]); err != nil {
+			logging.LogDebug("Error toggling settings allowlist: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.SettingsSnapshot
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.SettingsSnapshot
+}
+
+// listScrimLabel returns the menu text for a list dimming value
+func listScrimLabel(opacity int) string {
+	switch opacity {
+	case themes.ListScrimThemeDefault:
+		return "Theme default"
+	case themes.ListScrimOff:
+		return "Off"
+	default:
+		return fmt.Sprintf("%d%%", opacity)
+	}
+}
+
+// ListScrimScreen lets the user choose how much list wallpapers are dimmed
+func ListScrimScreen() (string, int) {
+	current := themes.GetListScrimSetting()
+
+	var menu []string
+	for _, opacity := range themes.ListScrimPresets {
+		if opacity == current {
+			menu = append(menu, "[x] "+listScrimLabel(opacity))
+		} else {
+			menu = append(menu, "[ ] "+listScrimLabel(opacity))
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "List Dimming")
+}
+
+// HandleListScrim stores the selected list dimming level
+func HandleListScrim(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleListScrim called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		label := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		for _, opacity := range themes.ListScrimPresets {
+			if listScrimLabel(opacity) != label {
+				continue
+			}
+
+			if err := themes.SetListScrimSetting(opacity); err != nil {
+				logging.LogDebug("Error saving list dimming: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			} else {
+				ui.ShowMessage("List dimming applies to the next theme or wallpaper you apply.", "3")
+			}
+			break
+		}
+		return app.Screens.SettingsMenu
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.ListScrim
+}
+
+// LintPackagesScreen lists installed packages that can be checked for manifest mistakes
+func LintPackagesScreen() (string, int) {
+	packages, err := themes.ListInstalledPackages()
+	if err != nil {
+		logging.LogDebug("Error listing packages: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(packages) == 0 {
+		ui.ShowMessage("No installed themes or components to check.", "3")
+		return "", 1
+	}
+
+	cwd, _ := os.Getwd()
+	var menu []string
+	for _, pkg := range packages {
+		if rel, err := filepath.Rel(cwd, pkg); err == nil {
+			pkg = rel
+		}
+		menu = append(menu, pkg)
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", "Lint Packages")
+}
+
+// HandleLintPackages lints the selected package and shows the numbered fix list
+func HandleLintPackages(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleLintPackages called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		cwd, _ := os.Getwd()
+		issues, err := themes.LintPackage(filepath.Join(cwd, selection))
+		if err != nil {
+			logging.LogDebug("Error linting package: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.LintPackages
+		}
+
+		logging.LogDebug("Lint report for %s:\n%s", selection, themes.FormatLintReport(issues))
+
+		if len(issues) == 0 {
+			ui.ShowMessage(fmt.Sprintf("No problems found in %s", filepath.Base(selection)), "3")
+			return app.Screens.LintPackages
+		}
+
+		var lines []string
+		for i, issue := range issues {
+			lines = append(lines, fmt.Sprintf("%d. %s", i+1, issue.Problem), "   Fix: "+issue.Fix)
+		}
+		ui.DisplayMinUiList(strings.Join(lines, "\n"), "text",
+			fmt.Sprintf("%d problems in %s", len(issues), filepath.Base(selection)))
+		return app.Screens.LintPackages
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.LintPackages
+}
@@ -0,0 +1,81 @@
+// src/internal/ui/screens/submit_screens.go
+// Screens for submitting exported packages to the community catalog
+
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// SubmitPackageScreen lists the exported packages that can be submitted
+func SubmitPackageScreen() (string, int) {
+	packages, err := themes.ListExportedPackages()
+	if err != nil {
+		logging.LogDebug("Error listing exported packages: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(packages) == 0 {
+		logging.LogDebug("No exported packages to submit")
+		ui.ShowMessage("Nothing to submit yet. Export a theme or component first.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(packages, "\n"), "text", "Submit to Catalog")
+}
+
+// HandleSubmitPackage submits the selected package and shows where to follow it
+func HandleSubmitPackage(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleSubmitPackage called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.SubmitPackage
+		}
+
+		var result *themes.SubmitResult
+		submitErr := ui.ShowMessageWithOperation(
+			fmt.Sprintf("Preparing %s for submission...", selection),
+			func() error {
+				var err error
+				result, err = themes.SubmitPackage(selection)
+				return err
+			},
+		)
+
+		if submitErr != nil {
+			logging.LogDebug("Error submitting package: %v", submitErr)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", submitErr), "3")
+			return app.Screens.SubmitPackage
+		}
+
+		if result.Issues > 0 {
+			ui.ShowMessage(fmt.Sprintf("Note: %d packaging issues found, see Lint Packages in Settings", result.Issues), "3")
+		}
+
+		if result.Uploaded {
+			ui.ShowMessage(fmt.Sprintf("%s submitted to the catalog!", selection), "3")
+			if result.Link != "" {
+				ui.ShowQRCode(result.Link, "Scan to follow your submission")
+			}
+		} else {
+			// No intake configured: finish the submission on a phone, attaching the zipped package
+			ui.ShowQRCode(result.Link, "Scan to submit, then attach the zipped package")
+		}
+		return app.Screens.SubmitPackage
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.SubmitPackage
+}
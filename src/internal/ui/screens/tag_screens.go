@@ -0,0 +1,211 @@
+// src/internal/ui/screens/tag_screens.go
+// Implementation of tag-based theme browsing and tag editing screens
+
+package screens
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// ThemeTagsScreen displays every tag used by installed and catalog themes
+func ThemeTagsScreen() (string, int) {
+	// Catalog is optional here, installed themes can carry tags too
+	catalog, err := themes.LoadCatalog()
+	if err != nil {
+		logging.LogDebug("Catalog not available for tag browsing: %v", err)
+	}
+
+	tags := themes.CollectThemeTags(catalog)
+	if len(tags) == 0 {
+		logging.LogDebug("No theme tags found")
+		ui.ShowMessage("No tagged themes found.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(tags, "\n"), "text", "Browse by Tag")
+}
+
+// HandleThemeTags processes the tag selection
+func HandleThemeTags(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleThemeTags called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection != "" {
+			app.SetSelectedTag(selection)
+			return app.Screens.ThemesByTag
+		}
+		return app.Screens.MainMenu
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.ThemeTags
+}
+
+// ThemesByTagScreen displays installed and catalog themes carrying the selected tag
+func ThemesByTagScreen() (string, int) {
+	tag := app.GetSelectedTag()
+
+	// Get current directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		logging.LogDebug("Error getting current directory: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	var previewImages []ui.GalleryItem
+
+	// Installed themes first
+	installed := themes.GetInstalledThemeTags()
+	for themeName, tags := range installed {
+		if !themes.HasTag(tags, tag) {
+			continue
+		}
+
+		previewPath := filepath.Join(cwd, "Themes", themeName, "preview.png")
+		if !fileExists(previewPath) {
+			previewPath = ""
+		}
+
+		previewImages = append(previewImages, ui.GalleryItem{
+			Text:            "[Installed] " + themeName,
+			BackgroundImage: previewPath,
+		})
+	}
+
+	// Then catalog themes that aren't installed yet
+	if catalog, err := themes.LoadCatalog(); err == nil {
+		for themeName, themeInfo := range catalog.Themes {
+			if _, isInstalled := installed[themeName]; isInstalled {
+				continue
+			}
+			if !themes.HasTag(themeInfo.Tags, tag) {
+				continue
+			}
+
+			previewImages = append(previewImages, ui.GalleryItem{
+				Text:            fmt.Sprintf("%s by %s", themeName, themeInfo.Author),
+				BackgroundImage: filepath.Join(cwd, themeInfo.PreviewPath),
+			})
+		}
+	}
+
+	if len(previewImages) == 0 {
+		logging.LogDebug("No themes found for tag: %s", tag)
+		ui.ShowMessage(fmt.Sprintf("No themes tagged '%s' found.", tag), "3")
+		return "", 1
+	}
+
+	selection, exitCode := ui.DisplayImageGallery(previewImages, fmt.Sprintf("Themes tagged '%s'", tag))
+
+	logging.LogDebug("Gallery selection: %s, exit code: %d", selection, exitCode)
+
+	// Extract theme name from selection (remove author info and installed indicator)
+	if selection != "" {
+		selection = strings.TrimPrefix(selection, "[Installed] ")
+		parts := strings.Split(selection, " by ")
+		selection = parts[0]
+	}
+
+	return selection, exitCode
+}
+
+// HandleThemesByTag processes the theme selection from the tag gallery
+func HandleThemesByTag(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleThemesByTag called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.ThemeTags
+		}
+
+		// Download the theme first if it only exists in the catalog
+		localThemePath := filepath.Join(app.GetWorkingDir(), "Themes", selection)
+		if !fileExists(localThemePath) {
+			downloadErr := ui.ShowMessageWithOperation(
+				fmt.Sprintf("Downloading theme '%s'...", selection),
+				func() error {
+					return themes.DownloadThemePackage(selection)
+				},
+			)
+
+			if downloadErr != nil {
+				logging.LogDebug("Error downloading theme: %v", downloadErr)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", downloadErr), "3")
+				return app.Screens.ThemesByTag
+			}
+		}
+
+		app.SetSelectedTheme(selection)
+		return app.Screens.ThemeImportConfirm
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.ThemeTags
+	}
+
+	return app.Screens.ThemesByTag
+}
+
+// ThemeTagEditScreen lets the user toggle tags on a locally authored theme
+func ThemeTagEditScreen() (string, int) {
+	themeName := app.GetSelectedTheme()
+	current := themes.GetThemeTags(themeName)
+
+	// Offer the suggested tags plus any custom tags already on the theme
+	options := append([]string{}, themes.DefaultThemeTags...)
+	for _, tag := range current {
+		if !themes.HasTag(options, tag) {
+			options = append(options, tag)
+		}
+	}
+
+	var menu []string
+	for _, tag := range options {
+		if themes.HasTag(current, tag) {
+			menu = append(menu, "[x] "+tag)
+		} else {
+			menu = append(menu, "[ ] "+tag)
+		}
+	}
+
+	return ui.DisplayMinUiList(strings.Join(menu, "\n"), "text", fmt.Sprintf("Tags for %s", themeName))
+}
+
+// HandleThemeTagEdit toggles the selected tag and redisplays the tag editor
+func HandleThemeTagEdit(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleThemeTagEdit called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		tag := strings.TrimPrefix(strings.TrimPrefix(selection, "[x] "), "[ ] ")
+		if tag == "" {
+			return app.Screens.ThemeTagEdit
+		}
+
+		if err := themes.ToggleThemeTag(app.GetSelectedTheme(), tag); err != nil {
+			logging.LogDebug("Error updating theme tags: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		}
+		return app.Screens.ThemeTagEdit
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.ThemeImportConfirm
+	}
+
+	return app.Screens.ThemeTagEdit
+}
@@ -4,7 +4,9 @@
 package screens
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"nextui-themes/internal/app"
 	"nextui-themes/internal/logging"
@@ -43,11 +45,13 @@ func InstalledThemesScreen() (string, int) {
 		return "", 1
 	}
 
-	// Filter for theme directories
+	// Filter for theme directories and zipped themes
 	var themeList []string
 	for _, entry := range entries {
 		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".theme") {
 			themeList = append(themeList, entry.Name())
+		} else if !entry.IsDir() && themes.IsThemeArchive(entry.Name()) {
+			themeList = append(themeList, entry.Name())
 		}
 	}
 
@@ -67,6 +71,18 @@ func InstalledThemesScreen() (string, int) {
 		// Default text in case manifest can't be read
 		text := themeName
 
+		// Zipped themes show the preview and author from inside the archive
+		if themes.IsThemeArchive(themeName) {
+			if author := themes.ThemeArchiveAuthor(themePath); author != "" {
+				text = fmt.Sprintf("%s by %s", themeName, author)
+			}
+			previewImages = append(previewImages, ui.GalleryItem{
+				Text:            text,
+				BackgroundImage: themes.ThemeArchivePreview(themePath),
+			})
+			continue
+		}
+
 		// Try to read manifest for author info
 		if fileExists(manifestPath) {
 			if data, err := os.ReadFile(manifestPath); err == nil {
@@ -96,7 +112,7 @@ func InstalledThemesScreen() (string, int) {
 	}
 
 	// Use DisplayImageGallery to display a gallery of preview images
-	selection, exitCode := ui.DisplayImageGallery(previewImages, "Installed Themes")
+	selection, exitCode := ui.DisplayImageGallery(previewImages, "Installed Themes", themesDir)
 
 	// Extract theme name from selection (remove author info)
 	if selection != "" {
@@ -172,34 +188,83 @@ func DownloadThemesScreen() (string, int) {
 		return "", 1
 	}
 
-	// Get preview images
-	previewImages := make([]ui.GalleryItem, 0, len(catalog.Themes))
-	for themeName, themeInfo := range catalog.Themes {
-		// Check if theme already exists locally
-		localThemePath := filepath.Join(cwd, "Themes", themeName)
-		alreadyInstalled := fileExists(localThemePath)
+	query := app.GetCatalogQuery()
+	content := app.GetCatalogContent()
+	searching := query != "" || (content != "" && content != themes.ContentFilterAny)
+
+	// R1 switches between all, installed and not installed themes without leaving the gallery
+	var selection string
+	var exitCode int
+	for {
+		filter := app.GetCatalogFilter()
+		if filter == "" {
+			filter = catalogFilters[0]
+		}
+
+		// Get preview images
+		previewImages := make([]ui.GalleryItem, 0, len(catalog.Themes))
+		for themeName, themeInfo := range catalog.Themes {
+			// Check if theme already exists locally, as a folder or zipped
+			localThemePath := filepath.Join(cwd, "Themes", themeName)
+			alreadyInstalled := fileExists(localThemePath) || fileExists(localThemePath+".zip")
+
+			if (filter == catalogFilterInstalled && !alreadyInstalled) ||
+				(filter == catalogFilterNotInstalled && alreadyInstalled) {
+				continue
+			}
+
+			// Searches narrow the gallery to matching names and authors, and content
+			if !themes.CatalogThemeMatches(themeName, themeInfo, query) ||
+				!themes.CatalogThemeHasContent(themeInfo, content) {
+				continue
+			}
+
+			// Get preview path - relative path in catalog needs to be converted to absolute
+			previewPath := filepath.Join(cwd, themeInfo.PreviewPath)
+
+			// Create text with installed indicator if needed
+			text := fmt.Sprintf("%s by %s", themeName, themeInfo.Author)
+			if alreadyInstalled {
+				text = "[Installed] " + text
+			}
+
+			// Create a GalleryItem for this theme
+			previewItem := ui.GalleryItem{
+				Text:            text,
+				BackgroundImage: previewPath,
+			}
 
-		// Get preview path - relative path in catalog needs to be converted to absolute
-		previewPath := filepath.Join(cwd, themeInfo.PreviewPath)
+			previewImages = append(previewImages, previewItem)
+		}
 
-		// Create text with installed indicator if needed
-		text := fmt.Sprintf("%s by %s", themeName, themeInfo.Author)
-		if alreadyInstalled {
-			text = "[Installed] " + text
+		next := nextCatalogFilter(filter)
+		if len(previewImages) == 0 {
+			if filter == catalogFilterAll {
+				// Only a search can leave every view empty
+				ui.ShowMessage("No catalog themes match the search", "2")
+				return "", 2
+			}
+			ui.ShowMessage(fmt.Sprintf("No %s themes in the catalog", strings.ToLower(filter)), "2")
+			app.SetCatalogFilter(next)
+			continue
 		}
 
-		// Create a GalleryItem for this theme
-		previewItem := ui.GalleryItem{
-			Text:            text,
-			BackgroundImage: previewPath,
+		title := "Download Themes"
+		if searching {
+			title = fmt.Sprintf("Search: %s", describeCatalogSearch(query, content))
+		}
+		if filter != catalogFilterAll {
+			title = fmt.Sprintf("%s (%s)", title, filter)
 		}
 
-		previewImages = append(previewImages, previewItem)
+		// Show the previews with R1 labelled after the next view
+		selection, exitCode = ui.DisplayImageGalleryWithToggle(previewImages, title, strings.ToUpper(next))
+		if exitCode != ui.GalleryToggleCode {
+			break
+		}
+		app.SetCatalogFilter(next)
 	}
 
-	// Use DisplayImageGallery to display a gallery of preview images
-	selection, exitCode := ui.DisplayImageGallery(previewImages, "Download Themes")
-
 	logging.LogDebug("Gallery selection: %s, exit code: %d", selection, exitCode)
 
 	// Extract theme name from selection (remove author info and installed indicator)
@@ -215,6 +280,25 @@ func DownloadThemesScreen() (string, int) {
 	return selection, exitCode
 }
 
+// Views of the download gallery, cycled with R1
+const (
+	catalogFilterAll          = "All"
+	catalogFilterInstalled    = "Installed"
+	catalogFilterNotInstalled = "Not Installed"
+)
+
+var catalogFilters = []string{catalogFilterAll, catalogFilterInstalled, catalogFilterNotInstalled}
+
+// nextCatalogFilter returns the view R1 switches the download gallery to
+func nextCatalogFilter(filter string) string {
+	for i, f := range catalogFilters {
+		if f == filter {
+			return catalogFilters[(i+1)%len(catalogFilters)]
+		}
+	}
+	return catalogFilters[0]
+}
+
 // HandleDownloadThemes processes the theme download selection
 func HandleDownloadThemes(selection string, exitCode int) app.Screen {
 	logging.LogDebug("HandleDownloadThemes called with selection: '%s', exitCode: %d", selection, exitCode)
@@ -223,16 +307,20 @@ func HandleDownloadThemes(selection string, exitCode int) app.Screen {
 	case 0:
 		// User selected a theme
 		if selection != "" {
-			// Check if theme already exists locally
+			// A local theme with the same name may be a different theme or another version
+			localName, overwrite, download := resolveThemeCollision(selection)
+			if localName == "" {
+				return app.Screens.DownloadThemes
+			}
+
 			cwd := app.GetWorkingDir()
-			localThemePath := filepath.Join(cwd, "Themes", selection)
+			localThemePath := filepath.Join(cwd, "Themes", localName)
 
-			if !fileExists(localThemePath) {
-				// Download the theme package if not already installed
+			if download {
 				downloadErr := ui.ShowMessageWithOperation(
 					fmt.Sprintf("Downloading theme '%s'...", selection),
 					func() error {
-						return themes.DownloadThemePackage(selection)
+						return themes.DownloadThemePackageAs(selection, localName, overwrite)
 					},
 				)
 
@@ -243,13 +331,17 @@ func HandleDownloadThemes(selection string, exitCode int) app.Screen {
 				}
 
 				// Show success message briefly
-				ui.ShowMessage(fmt.Sprintf("Theme '%s' downloaded successfully!", selection), "2")
+				ui.ShowMessage(fmt.Sprintf("Theme '%s' downloaded successfully!", localName), "2")
 			} else {
 				logging.LogDebug("Theme '%s' already installed, skipping download", selection)
 			}
 
 			// Prompt user if they want to apply this theme now
-			message := fmt.Sprintf("Apply theme '%s' now?", selection)
+			message := fmt.Sprintf("Apply theme '%s' now?", localName)
+			logger := &themes.Logger{DebugFn: logging.LogDebug}
+			if manifest, err := themes.ValidateTheme(localThemePath, logger); err == nil && manifest.ThemeInfo.License != "" {
+				message = fmt.Sprintf("%s\nLicense: %s", message, manifest.ThemeInfo.License)
+			}
 			options := []string{
 				"Yes",
 				"No",
@@ -258,31 +350,181 @@ func HandleDownloadThemes(selection string, exitCode int) app.Screen {
 
 			if promptCode == 0 && result == "Yes" {
 				// Apply the theme using the new function
-				importErr := ui.ShowMessageWithOperation(
-					fmt.Sprintf("Applying theme '%s'...", selection),
-					func() error {
-						return themes.ImportTheme(selection)
+				importErr := themes.RunApplyWithProgress(
+					fmt.Sprintf("Applying theme '%s'...", localName),
+					func(ctx context.Context) error {
+						return themes.RunStrict(func() error {
+							return themes.ImportThemeContext(ctx, localName)
+						})
 					},
 				)
 
-				if importErr != nil {
+				if errors.Is(importErr, ui.ErrCancelled) {
+					logging.LogDebug("Theme apply cancelled")
+					ui.ShowMessage("Apply cancelled. Files copied so far stay applied, anything removed is in the trash.", "3")
+				} else if importErr != nil {
 					logging.LogDebug("Error importing theme: %v", importErr)
 					ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
 				} else {
-					ui.ShowMessage(fmt.Sprintf("Theme '%s' applied successfully!", selection), "2")
+					ui.ShowMessage(fmt.Sprintf("Theme '%s' applied successfully!", localName), "2")
 				}
 			}
 		}
 		return app.Screens.MainMenu
 
 	case 1, 2:
-		// User pressed cancel or back
+		// Back from search results goes back to refining the search
+		if app.GetCatalogQuery() != "" || app.GetCatalogContent() != "" {
+			return app.Screens.CatalogSearch
+		}
 		return app.Screens.MainMenu
 	}
 
 	return app.Screens.DownloadThemes
 }
 
+// Entries of the catalog search screen besides the characters to type
+const (
+	searchShowResults = "Show Results"
+	searchDelete      = "Delete Last Character"
+	searchClear       = "Clear"
+	searchSpace       = "[space]"
+
+	// Picking the content entry cycles through themes.ContentFilters
+	searchContentPrefix = "Content: "
+)
+
+// describeCatalogSearch sums up a search for titles, e.g. "retro, Has Icons"
+func describeCatalogSearch(query, content string) string {
+	var parts []string
+	if query != "" {
+		parts = append(parts, query)
+	}
+	if content != "" && content != themes.ContentFilterAny {
+		parts = append(parts, content)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CatalogSearchScreen builds a search one character at a time, optionally limited to themes
+// with some content. Only characters that still match a catalog theme's name or author are
+// offered, so a few presses are enough.
+func CatalogSearchScreen() (string, int) {
+	catalog, err := themes.LoadCatalog()
+	if err != nil {
+		logging.LogDebug("Error loading catalog: %v", err)
+		ui.ShowMessage("No theme catalog found. Please sync catalog first.", "3")
+		return "", 1
+	}
+
+	query := app.GetCatalogQuery()
+	content := app.GetCatalogContent()
+	if content == "" {
+		content = themes.ContentFilterAny
+	}
+	matches := themes.SearchCatalogThemes(catalog, query, content)
+
+	options := []string{
+		fmt.Sprintf("%s (%d)", searchShowResults, len(matches)),
+		searchContentPrefix + content,
+	}
+	if query != "" {
+		options = append(options, searchDelete, searchClear)
+	}
+	for _, char := range themes.CatalogSearchNextChars(catalog, query, content) {
+		if char == " " {
+			char = searchSpace
+		}
+		options = append(options, char)
+	}
+
+	title := "Search Catalog"
+	if query != "" {
+		title = fmt.Sprintf("Search: %s_", query)
+	}
+	return ui.DisplayMinUiList(strings.Join(options, "\n"), "text", title)
+}
+
+// HandleCatalogSearch adds the picked character to the search or shows the results
+func HandleCatalogSearch(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleCatalogSearch called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		query := app.GetCatalogQuery()
+		switch {
+		case strings.HasPrefix(selection, searchShowResults):
+			return app.Screens.DownloadThemes
+		case strings.HasPrefix(selection, searchContentPrefix):
+			app.SetCatalogContent(themes.NextContentFilter(strings.TrimPrefix(selection, searchContentPrefix)))
+		case selection == searchDelete:
+			runes := []rune(query)
+			app.SetCatalogQuery(string(runes[:len(runes)-1]))
+		case selection == searchClear:
+			app.SetCatalogQuery("")
+		case selection == searchSpace:
+			app.SetCatalogQuery(query + " ")
+		default:
+			app.SetCatalogQuery(query + selection)
+		}
+		return app.Screens.CatalogSearch
+
+	case 1, 2:
+		app.SetCatalogQuery("")
+		app.SetCatalogContent("")
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.CatalogSearch
+}
+
+// resolveThemeCollision picks the local name a catalog theme is downloaded to. When a local
+// theme already has that name and isn't the same release, the user chooses to keep both
+// (download under a new name), overwrite the local theme, or cancel. An empty name means cancel;
+// download is false when the catalog release is already installed.
+func resolveThemeCollision(themeName string) (localName string, overwrite bool, download bool) {
+	collision, err := themes.FindThemeCollision(themeName)
+	if err != nil {
+		logging.LogDebug("Error checking for theme name collision: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", false, false
+	}
+	if collision == nil {
+		return themeName, false, true
+	}
+	if collision.SameRelease() {
+		return themeName, false, false
+	}
+
+	freeName, err := themes.FreeThemeName(themeName)
+	if err != nil {
+		logging.LogDebug("Error finding a free theme name: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", false, false
+	}
+
+	keepBoth := fmt.Sprintf("Keep Both (as '%s')", freeName)
+	options := []string{
+		keepBoth,
+		"Overwrite Local Theme",
+		"Cancel",
+	}
+	message := fmt.Sprintf("'%s' is already installed\n%s", themeName, collision.Describe())
+
+	choice, code := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", message)
+	if code != 0 {
+		return "", false, false
+	}
+
+	switch choice {
+	case keepBoth:
+		return freeName, false, true
+	case "Overwrite Local Theme":
+		return themeName, true, true
+	}
+	return "", false, false
+}
+
 // SyncCatalogScreen displays the sync catalog screen
 func SyncCatalogScreen() (string, int) {
 	// Simple confirmation message
@@ -364,11 +606,13 @@ func ThemeImportScreen() (string, int) {
 		return "", 1
 	}
 
-	// Filter for theme directories
+	// Filter for theme directories and zipped themes
 	var themesList []string
 	for _, entry := range entries {
 		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".theme") {
 			themesList = append(themesList, entry.Name())
+		} else if !entry.IsDir() && themes.IsThemeArchive(entry.Name()) {
+			themesList = append(themesList, entry.Name())
 		}
 	}
 
@@ -405,9 +649,48 @@ func ThemeImportConfirmScreen() (string, int) {
 	themeName := app.GetSelectedTheme()
 	message := fmt.Sprintf("Apply theme '%s'?", themeName)
 
+	// Show the theme's license if it declares one
+	themePath := filepath.Join(app.GetWorkingDir(), "Themes", themeName)
+	logger := &themes.Logger{DebugFn: logging.LogDebug}
+	manifest, err := themes.ValidateTheme(themePath, logger)
+	if err == nil && manifest.ThemeInfo.License != "" {
+		message = fmt.Sprintf("%s\nLicense: %s", message, manifest.ThemeInfo.License)
+	}
+
+	// Zipped themes can be applied as they are, everything else needs them unpacked
+	if themes.IsThemeArchive(themeName) {
+		return ui.DisplayMinUiList(strings.Join([]string{"Yes", "No", "Preview Changes", "Unpack"}, "\n"), "text", message)
+	}
+
 	options := []string{
 		"Yes",
 		"No",
+		"Apply Parts",
+		"Details",
+		"Check Compatibility",
+		"Preview Changes",
+		"Edit",
+	}
+
+	// Themes with wallpapers but no list wallpapers can have them made while applying
+	if err == nil && themes.ThemeLacksListWallpapers(manifest) {
+		options = append(options, generateListWallpapersLabel())
+	}
+
+	// Setups can also bring their collections along
+	if themes.IsSetup(themePath) {
+		options = append(options, "Recreate Collections")
+	}
+
+	// Offer rollback when downloads or edits replaced earlier versions
+	if versions, _ := themes.ListPackageVersions(themePath); len(versions) > 0 {
+		options = append(options, "Versions")
+	}
+
+	// Tags can only be edited on themes that weren't downloaded from the catalog
+	catalog, _ := themes.LoadCatalog()
+	if themes.IsLocallyAuthoredTheme(themeName, catalog) {
+		options = append(options, "Edit Tags")
 	}
 
 	return ui.DisplayMinUiList(strings.Join(options, "\n"), "text", message)
@@ -419,24 +702,91 @@ func HandleThemeImportConfirm(selection string, exitCode int) app.Screen {
 
 	switch exitCode {
 	case 0:
-		if selection == "Yes" {
-			// Import the selected theme
-			themeName := app.GetSelectedTheme()
+		if selection == "Edit Tags" {
+			return app.Screens.ThemeTagEdit
+		}
 
-			// Use ShowMessageWithOperation for better user feedback
-			importErr := ui.ShowMessageWithOperation(
-				fmt.Sprintf("Applying theme '%s'...", themeName),
-				func() error {
-					return themes.ImportTheme(themeName)
-				},
-			)
+		if selection == "Details" {
+			return app.Screens.ThemeStats
+		}
 
-			if importErr != nil {
-				logging.LogDebug("Error importing theme: %v", importErr)
-				ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
-			} else {
-				ui.ShowMessage(fmt.Sprintf("Theme '%s' applied successfully!", themeName), "3")
+		if selection == "Edit" {
+			return app.Screens.WorkspaceMenu
+		}
+
+		if selection == generateListWallpapersLabel() {
+			app.SetGenerateListWallpapers(!app.GetGenerateListWallpapers())
+			return app.Screens.ThemeImportConfirm
+		}
+
+		if selection == "Check Compatibility" {
+			showCompatibility(filepath.Join(app.GetWorkingDir(), "Themes", app.GetSelectedTheme()))
+			return app.Screens.ThemeImportConfirm
+		}
+
+		if selection == "Preview Changes" {
+			showApplyPreview(filepath.Join(app.GetWorkingDir(), "Themes", app.GetSelectedTheme()))
+			return app.Screens.ThemeImportConfirm
+		}
+
+		if selection == "Versions" {
+			app.SetSelectedVersionPackage(filepath.Join("Themes", app.GetSelectedTheme()))
+			return app.Screens.VersionList
+		}
+
+		if selection == "Unpack" {
+			themePath := filepath.Join(app.GetWorkingDir(), "Themes", app.GetSelectedTheme())
+			name, err := themes.UnpackThemeArchive(themePath)
+			if err != nil {
+				logging.LogDebug("Error unpacking theme: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				return app.Screens.ThemeImportConfirm
 			}
+			app.SetSelectedTheme(name)
+			ui.ShowMessage(fmt.Sprintf("Unpacked '%s', the archive is in the trash", name), "2")
+			return app.Screens.ThemeImportConfirm
+		}
+
+		if selection == "Recreate Collections" {
+			recreateSetupCollections(app.GetSelectedTheme())
+			return app.Screens.ThemeImportConfirm
+		}
+
+		if selection == "Apply Parts" {
+			// Everything starts ticked, the user unticks what to keep as it is
+			checked := make([]bool, len(themes.ThemeParts))
+			for i := range checked {
+				checked[i] = true
+			}
+
+			ticked, code := ui.DisplayChecklist(themes.ThemeParts, checked, "Parts to apply")
+			if code != 0 {
+				return app.Screens.ThemeImportConfirm
+			}
+
+			var parts []string
+			for i, part := range themes.ThemeParts {
+				if ticked[i] {
+					parts = append(parts, part)
+				}
+			}
+			if len(parts) == 0 {
+				ui.ShowMessage("Nothing picked to apply", "2")
+				return app.Screens.ThemeImportConfirm
+			}
+
+			themeName := app.GetSelectedTheme()
+			applySelectedTheme(themeName, func(ctx context.Context) error {
+				return themes.ImportThemePartsContext(ctx, themeName, parts)
+			})
+		}
+
+		if selection == "Yes" {
+			// Import the selected theme
+			themeName := app.GetSelectedTheme()
+			applySelectedTheme(themeName, func(ctx context.Context) error {
+				return themes.ImportThemeContext(ctx, themeName)
+			})
 		}
 		// Return to main menu
 		return app.Screens.MainMenu
@@ -449,12 +799,93 @@ func HandleThemeImportConfirm(selection string, exitCode int) app.Screen {
 	return app.Screens.ThemeImportConfirm
 }
 
+// generateListWallpapersLabel shows whether the apply makes the list wallpapers the theme lacks
+func generateListWallpapersLabel() string {
+	if app.GetGenerateListWallpapers() {
+		return "[x] Make Missing List Wallpapers"
+	}
+	return "[ ] Make Missing List Wallpapers"
+}
+
+// applySelectedTheme runs a theme apply in the background behind a progress screen the
+// user can cancel, then reports how it went
+func applySelectedTheme(themeName string, apply func(ctx context.Context) error) {
+	// The toggle only covers this apply
+	if app.GetGenerateListWallpapers() {
+		app.SetGenerateListWallpapers(false)
+		themeApply := apply
+		apply = func(ctx context.Context) error {
+			return themes.WithGeneratedListWallpapers(func() error {
+				return themeApply(ctx)
+			})
+		}
+	}
+
+	importErr := themes.RunApplyWithProgress(
+		fmt.Sprintf("Applying theme '%s'...", themeName),
+		func(ctx context.Context) error {
+			return themes.RunStrict(func() error {
+				return apply(ctx)
+			})
+		},
+	)
+
+	if errors.Is(importErr, ui.ErrCancelled) {
+		logging.LogDebug("Theme apply cancelled")
+		ui.ShowMessage("Apply cancelled. Files copied so far stay applied, anything removed is in the trash.", "3")
+	} else if importErr != nil {
+		logging.LogDebug("Error importing theme: %v", importErr)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
+	} else {
+		ui.ShowMessage(fmt.Sprintf("Theme '%s' applied successfully!", themeName), "3")
+	}
+}
+
+// ThemeStatsScreen shows what the selected theme contains and how much space it uses
+func ThemeStatsScreen() (string, int) {
+	themeName := app.GetSelectedTheme()
+	themePath := filepath.Join(app.GetWorkingDir(), "Themes", themeName)
+
+	stats, err := themes.GetPackageStats(themePath)
+	if err != nil {
+		logging.LogDebug("Error getting theme stats: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	lines := []string{
+		fmt.Sprintf("Total size: %s", themes.FormatSize(stats.TotalSize)),
+		fmt.Sprintf("Wallpapers: %d", stats.Wallpapers),
+		fmt.Sprintf("Icons: %d", stats.Icons),
+		fmt.Sprintf("Overlays: %d", stats.Overlays),
+		fmt.Sprintf("Fonts: %d", stats.Fonts),
+		fmt.Sprintf("Other files: %d", stats.Other),
+		"Largest files:",
+	}
+	for _, file := range stats.Largest {
+		lines = append(lines, fmt.Sprintf("  %s (%s)", file.Path, themes.FormatSize(file.Size)))
+	}
+
+	return ui.DisplayMinUiList(strings.Join(lines, "\n"), "text", fmt.Sprintf("Details: %s", themeName))
+}
+
+// HandleThemeStats returns to the theme's apply confirmation
+func HandleThemeStats(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleThemeStats called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	// The stats are read-only, any button goes back
+	return app.Screens.ThemeImportConfirm
+}
+
 // ThemeExportScreen displays the theme export confirmation
 func ThemeExportScreen() (string, int) {
 	// Simple confirmation message
-	message := "Export current theme settings?\nThis will create a theme package in the Exports directory."
+	message := "Export current theme settings?\nThis will create a theme package in your export folder."
 	options := []string{
 		"Yes",
+		"Changes Only",
+		"With Collections",
+		"Setup Summary",
 		"No",
 	}
 
@@ -467,12 +898,22 @@ func HandleThemeExport(selection string, exitCode int) app.Screen {
 
 	switch exitCode {
 	case 0:
-		if selection == "Yes" {
+		if selection == "Yes" || selection == "With Collections" {
+			if !confirmExportLicenses() {
+				return app.Screens.MainMenu
+			}
+
+			export := themes.ExportTheme
+			if selection == "With Collections" {
+				// A setup: the theme plus the names, order and contents of the collections
+				export = themes.ExportSetup
+			}
+
 			// Perform theme export with operation message
 			exportErr := ui.ShowMessageWithOperation(
 				"Exporting current theme...",
 				func() error {
-					return themes.ExportTheme()
+					return themes.RunStrict(export)
 				},
 			)
 
@@ -481,7 +922,12 @@ func HandleThemeExport(selection string, exitCode int) app.Screen {
 				ui.ShowMessage(fmt.Sprintf("Error: %s", exportErr), "3")
 			} else {
 				ui.ShowMessage("Theme exported successfully!", "3")
+				offerExportCopy()
 			}
+		} else if selection == "Changes Only" {
+			exportDiffTheme()
+		} else if selection == "Setup Summary" {
+			exportSetupSummary()
 		}
 		// Return to main menu
 		return app.Screens.MainMenu
@@ -493,3 +939,271 @@ func HandleThemeExport(selection string, exitCode int) app.Screen {
 
 	return app.Screens.ThemeExport
 }
+
+// confirmExportLicenses asks before exporting applied components whose license forbids sharing
+// them, and reports whether the export should go ahead
+func confirmExportLicenses() bool {
+	restricted := themes.RestrictedExportLicenses()
+	if len(restricted) == 0 {
+		return true
+	}
+
+	message := fmt.Sprintf("These components may not be redistributed:\n%s\nExport anyway?",
+		strings.Join(restricted, "\n"))
+	options := []string{"Yes", "No"}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", message)
+	return exitCode == 0 && result == "Yes"
+}
+
+// recreateSetupCollections asks before writing a setup's collections over those with the same names
+func recreateSetupCollections(themeName string) {
+	options := []string{"Yes", "No"}
+	result, exitCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text",
+		"Replace collections with the same names as this setup's?")
+	if exitCode != 0 || result != "Yes" {
+		return
+	}
+
+	setup, err := themes.ApplySetupCollections(themeName)
+	if err != nil {
+		logging.LogDebug("Error recreating collections: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	message := fmt.Sprintf("Recreated %d collections with %d games.", setup.Collections, setup.Entries)
+	if len(setup.Missing) > 0 {
+		message += fmt.Sprintf(" %d games aren't on this device and were left out.", len(setup.Missing))
+	}
+	ui.ShowMessage(message, "4")
+}
+
+// exportSetupSummary writes a text and image summary of the applied setup to the export folder
+func exportSetupSummary() {
+	imagePath, err := themes.ExportSetupSummary()
+	if err != nil {
+		logging.LogDebug("Error exporting setup summary: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+
+	ui.ShowMessage(fmt.Sprintf("Saved %s and a text copy to the export folder.", filepath.Base(imagePath)), "3")
+}
+
+// exportDiffTheme asks for the installed theme to compare against, listing the applied
+// theme first, and exports only what the device changed on top of it
+func exportDiffTheme() {
+	themeDirs, err := filepath.Glob(filepath.Join(app.GetWorkingDir(), "Themes", "*.theme"))
+	if err != nil || len(themeDirs) == 0 {
+		ui.ShowMessage("No themes found in Themes directory", "3")
+		return
+	}
+
+	current, _ := themes.GetAppliedComponent("theme")
+
+	var themeNames []string
+	for _, dir := range themeDirs {
+		name := filepath.Base(dir)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		if name == current {
+			themeNames = append([]string{name}, themeNames...)
+		} else {
+			themeNames = append(themeNames, name)
+		}
+	}
+
+	base, exitCode := ui.DisplayMinUiList(strings.Join(themeNames, "\n"), "text", "Export Changes Since")
+	if exitCode != 0 || base == "" {
+		return
+	}
+	if !confirmExportLicenses() {
+		return
+	}
+
+	exportErr := ui.ShowMessageWithOperation(
+		"Exporting changes...",
+		func() error {
+			return themes.RunStrict(func() error {
+				return themes.ExportDiffTheme(base)
+			})
+		},
+	)
+	if exportErr != nil {
+		logging.LogDebug("Error exporting diff theme: %v", exportErr)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", exportErr), "3")
+		return
+	}
+	offerExportCopy()
+}
+
+// offerExportCopy offers to copy the package just exported to a USB drive or other
+// mounted storage, so big packages don't have to be moved off the SD card by hand
+func offerExportCopy() {
+	packagePath := themes.LastExportPath()
+	if packagePath == "" {
+		return
+	}
+
+	options := []string{"Done"}
+	for _, root := range themes.ExternalStorageRoots() {
+		if !strings.HasPrefix(packagePath, root+"/") {
+			options = append(options, "Copy to "+root)
+		}
+	}
+	if len(options) == 1 {
+		return
+	}
+
+	message := fmt.Sprintf("Exported %s", filepath.Base(packagePath))
+	choice, code := ui.DisplayMinUiList(strings.Join(options, "\n"), "text", message)
+	if code != 0 || !strings.HasPrefix(choice, "Copy to ") {
+		return
+	}
+
+	root := strings.TrimPrefix(choice, "Copy to ")
+	var destDir string
+	copyErr := ui.ShowMessageWithOperation(
+		fmt.Sprintf("Copying to %s...", root),
+		func() error {
+			var err error
+			destDir, err = themes.CopyExportTo(packagePath, root)
+			return err
+		},
+	)
+
+	if copyErr != nil {
+		logging.LogDebug("Error copying export: %v", copyErr)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", copyErr), "3")
+		return
+	}
+	ui.ShowMessage(fmt.Sprintf("Copied to %s", destDir), "3")
+}
+
+// ImportFolderScreen lets the user pick a folder to bulk import packages from
+func ImportFolderScreen() (string, int) {
+	folders := themes.ListDropFolderCandidates()
+	if len(folders) == 0 {
+		ui.ShowMessage("No folders available to import from.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(folders, "\n"), "text", "Import from Folder")
+}
+
+// HandleImportFolder imports every package found in the selected folder
+func HandleImportFolder(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleImportFolder called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.ImportFolder
+		}
+
+		var result *themes.BulkImportResult
+		importErr := ui.ShowMessageWithOperation(
+			fmt.Sprintf("Importing packages from %s...", filepath.Base(selection)),
+			func() error {
+				var err error
+				result, err = themes.ImportFromFolder(selection)
+				return err
+			},
+		)
+
+		if importErr != nil {
+			logging.LogDebug("Error importing from folder: %v", importErr)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
+			return app.Screens.ImportFolder
+		}
+
+		showImportResult(result, "No themes or components found in that folder.")
+		return app.Screens.MainMenu
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.ImportFolder
+}
+
+// showImportResult summarizes a bulk import, listing why any packages were skipped
+func showImportResult(result *themes.BulkImportResult, emptyMessage string) {
+	switch {
+	case len(result.Imported) == 0 && len(result.Skipped) == 0:
+		ui.ShowMessage(emptyMessage, "3")
+	case len(result.Skipped) > 0:
+		ui.ShowMessage(fmt.Sprintf("Imported %d packages. Skipped %d:\n%s",
+			len(result.Imported), len(result.Skipped), strings.Join(result.Skipped, "\n")), "5")
+	default:
+		ui.ShowMessage(fmt.Sprintf("Imported %d packages!", len(result.Imported)), "3")
+	}
+}
+
+// ImportURLScreen asks to download the links listed in urls.txt
+func ImportURLScreen() (string, int) {
+	urls, err := themes.ReadImportURLs()
+	if err != nil {
+		logging.LogDebug("Error reading import links: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(urls) == 0 {
+		ui.ShowMessage("No links to import. Add links to .theme or component zips, one per line, to urls.txt in the Theme-Manager.pak folder.", "5")
+		return "", 1
+	}
+
+	options := []string{
+		fmt.Sprintf("Download %d links", len(urls)),
+		"Cancel",
+	}
+
+	return ui.DisplayMinUiList(strings.Join(options, "\n"), "text", "Import from URL")
+}
+
+// HandleImportURL downloads and installs the packages linked in urls.txt
+func HandleImportURL(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleImportURL called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if !strings.HasPrefix(selection, "Download") {
+			return app.Screens.MainMenu
+		}
+
+		urls, err := themes.ReadImportURLs()
+		if err != nil {
+			logging.LogDebug("Error reading import links: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.MainMenu
+		}
+
+		var result *themes.BulkImportResult
+		importErr := ui.ShowMessageWithOperation(
+			fmt.Sprintf("Downloading %d packages...", len(urls)),
+			func() error {
+				var err error
+				result, err = themes.ImportFromURLs(urls)
+				return err
+			},
+		)
+
+		if importErr != nil {
+			logging.LogDebug("Error importing from links: %v", importErr)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", importErr), "3")
+			return app.Screens.MainMenu
+		}
+
+		showImportResult(result, "No themes or components found at those links.")
+		return app.Screens.MainMenu
+
+	case 1, 2:
+		// User pressed cancel or back
+		return app.Screens.MainMenu
+	}
+
+	return app.Screens.MainMenu
+}
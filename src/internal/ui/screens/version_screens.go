@@ -0,0 +1,161 @@
+// src/internal/ui/screens/version_screens.go
+// Screens for browsing previous versions of installed packages and rolling back
+
+package screens
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// versionLabel describes an archived version in the version list
+func versionLabel(version themes.PackageVersion) string {
+	saved := version.Saved.Format("2006-01-02 15:04")
+	if version.Version == "" {
+		return fmt.Sprintf("Replaced %s", saved)
+	}
+	return fmt.Sprintf("v%s (replaced %s)", version.Version, saved)
+}
+
+// PackageVersionsScreen lists the installed packages that have previous versions
+func PackageVersionsScreen() (string, int) {
+	packages, err := themes.ListVersionedPackages()
+	if err != nil {
+		logging.LogDebug("Error listing package versions: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(packages) == 0 {
+		ui.ShowMessage("No previous versions kept yet. They are saved when a download or edit replaces an installed package.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(packages, "\n"), "text", "Package Versions")
+}
+
+// HandlePackageVersions shows the versions of the selected package
+func HandlePackageVersions(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandlePackageVersions called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.PackageVersions
+		}
+		app.SetSelectedVersionPackage(selection)
+		return app.Screens.VersionList
+
+	case 1, 2:
+		return app.Screens.SettingsMenu
+	}
+
+	return app.Screens.PackageVersions
+}
+
+// VersionListScreen lists the previous versions of the selected package, newest first
+func VersionListScreen() (string, int) {
+	packagePath := filepath.Join(app.GetWorkingDir(), app.GetSelectedVersionPackage())
+
+	versions, err := themes.ListPackageVersions(packagePath)
+	if err != nil {
+		logging.LogDebug("Error listing versions: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(versions) == 0 {
+		ui.ShowMessage("No previous versions of this package.", "3")
+		return "", 1
+	}
+
+	labels := make([]string, 0, len(versions))
+	for _, version := range versions {
+		labels = append(labels, versionLabel(version))
+	}
+
+	return ui.DisplayMinUiList(strings.Join(labels, "\n"), "text", filepath.Base(packagePath))
+}
+
+// HandleVersionList rolls the package back to the selected version after confirming
+func HandleVersionList(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleVersionList called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		packagePath := filepath.Join(app.GetWorkingDir(), app.GetSelectedVersionPackage())
+		name := filepath.Base(packagePath)
+
+		versions, err := themes.ListPackageVersions(packagePath)
+		if err != nil {
+			logging.LogDebug("Error listing versions: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.VersionList
+		}
+
+		for _, version := range versions {
+			if versionLabel(version) != selection {
+				continue
+			}
+
+			options := []string{
+				"Yes",
+				"No",
+			}
+			result, promptCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text",
+				fmt.Sprintf("Roll '%s' back to %s?", name, selection))
+			if promptCode != 0 || result != "Yes" {
+				return app.Screens.VersionList
+			}
+			if !confirmProtectionPIN("roll back") {
+				return app.Screens.VersionList
+			}
+
+			if err := themes.RestorePackageVersion(packagePath, version.ID); err != nil {
+				logging.LogDebug("Error restoring version: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				return app.Screens.VersionList
+			}
+
+			ui.ShowMessage(fmt.Sprintf("'%s' rolled back to %s.", name, selection), "3")
+			return app.Screens.VersionList
+		}
+		return app.Screens.VersionList
+
+	case 1, 2:
+		return app.Screens.PackageVersions
+	}
+
+	return app.Screens.VersionList
+}
+
+// purgePackageVersions deletes the previous versions of every installed package, after the
+// user confirms and enters the protection PIN
+func purgePackageVersions() {
+	options := []string{
+		"Yes",
+		"No",
+	}
+	result, promptCode := ui.DisplayMinUiList(strings.Join(options, "\n"), "text",
+		"Delete all previous package versions? Packages can't be rolled back afterwards.")
+	if promptCode != 0 || result != "Yes" {
+		return
+	}
+	if !confirmProtectionPIN("purge versions") {
+		return
+	}
+
+	count, err := themes.PurgePackageVersions()
+	if err != nil {
+		logging.LogDebug("Error purging package versions: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return
+	}
+	ui.ShowMessage(fmt.Sprintf("Deleted the previous versions of %d packages.", count), "3")
+}
@@ -0,0 +1,178 @@
+// src/internal/ui/screens/workspace_screens.go
+// Screens for editing an installed theme in the workspace
+
+package screens
+
+import (
+	"fmt"
+	"strings"
+
+	"nextui-themes/internal/app"
+	"nextui-themes/internal/logging"
+	"nextui-themes/internal/themes"
+	"nextui-themes/internal/ui"
+)
+
+// workspaceSwapOptions maps the workspace menu entries to the component type they swap
+var workspaceSwapOptions = map[string]string{
+	"Swap Wallpaper": themes.ComponentWallpaper,
+	"Swap Icon":      themes.ComponentIcon,
+	"Swap Font":      themes.ComponentFont,
+}
+
+// WorkspaceMenuScreen opens the selected theme in the workspace and shows the edit actions
+func WorkspaceMenuScreen() (string, int) {
+	themeName := app.GetSelectedTheme()
+
+	if _, err := themes.OpenWorkspace(themeName); err != nil {
+		logging.LogDebug("Error opening workspace: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	options := []string{
+		"Swap Wallpaper",
+		"Swap Icon",
+		"Swap Font",
+		"Save as New Version",
+		"Discard Changes",
+	}
+
+	return ui.DisplayMinUiList(strings.Join(options, "\n"), "text", fmt.Sprintf("Editing %s", themeName))
+}
+
+// HandleWorkspaceMenu runs the selected workspace action
+func HandleWorkspaceMenu(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleWorkspaceMenu called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	themeName := app.GetSelectedTheme()
+
+	switch exitCode {
+	case 0:
+		if componentType, ok := workspaceSwapOptions[selection]; ok {
+			app.SetSelectedComponentType(componentType)
+			return app.Screens.WorkspaceSlots
+		}
+
+		switch selection {
+		case "Save as New Version":
+			var version string
+			saveErr := ui.ShowMessageWithOperation(
+				fmt.Sprintf("Saving '%s'...", themeName),
+				func() error {
+					var err error
+					version, err = themes.SaveWorkspace(themeName)
+					return err
+				},
+			)
+
+			if saveErr != nil {
+				logging.LogDebug("Error saving workspace: %v", saveErr)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", saveErr), "3")
+				return app.Screens.WorkspaceMenu
+			}
+
+			ui.ShowMessage(fmt.Sprintf("Saved '%s' as version %s!", themeName, version), "3")
+			return app.Screens.InstalledThemes
+
+		case "Discard Changes":
+			if !confirmProtectionPIN("discard") {
+				return app.Screens.WorkspaceMenu
+			}
+			if err := themes.DiscardWorkspace(themeName); err != nil {
+				logging.LogDebug("Error discarding workspace: %v", err)
+				ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+				return app.Screens.WorkspaceMenu
+			}
+
+			ui.ShowMessage("Changes discarded.", "2")
+			return app.Screens.InstalledThemes
+		}
+
+	case 1, 2:
+		// Edits stay in the workspace until saved or discarded
+		return app.Screens.ThemeImportConfirm
+	}
+
+	return app.Screens.WorkspaceMenu
+}
+
+// WorkspaceSlotsScreen lists the workspace files of the chosen type
+func WorkspaceSlotsScreen() (string, int) {
+	slots, err := themes.ListWorkspaceSlots(app.GetSelectedTheme(), app.GetSelectedComponentType())
+	if err != nil {
+		logging.LogDebug("Error listing workspace files: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(slots) == 0 {
+		ui.ShowMessage("This theme has no files of that kind to swap.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(slots, "\n"), "text", "Select File to Replace")
+}
+
+// HandleWorkspaceSlots remembers the file to replace and moves on to picking its replacement
+func HandleWorkspaceSlots(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleWorkspaceSlots called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.WorkspaceSlots
+		}
+		app.SetSelectedWorkspaceSlot(selection)
+		return app.Screens.WorkspaceSource
+
+	case 1, 2:
+		return app.Screens.WorkspaceMenu
+	}
+
+	return app.Screens.WorkspaceSlots
+}
+
+// WorkspaceSourceScreen lists files from installed components that can replace the chosen file
+func WorkspaceSourceScreen() (string, int) {
+	sources, err := themes.ListSwapSources(app.GetSelectedComponentType())
+	if err != nil {
+		logging.LogDebug("Error listing swap sources: %v", err)
+		ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+		return "", 1
+	}
+
+	if len(sources) == 0 {
+		ui.ShowMessage("No installed components of that kind to swap from.", "3")
+		return "", 1
+	}
+
+	return ui.DisplayMinUiList(strings.Join(sources, "\n"), "text", "Replace With")
+}
+
+// HandleWorkspaceSource copies the chosen component file over the workspace file
+func HandleWorkspaceSource(selection string, exitCode int) app.Screen {
+	logging.LogDebug("HandleWorkspaceSource called with selection: '%s', exitCode: %d", selection, exitCode)
+
+	switch exitCode {
+	case 0:
+		if selection == "" {
+			return app.Screens.WorkspaceSource
+		}
+
+		slot := app.GetSelectedWorkspaceSlot()
+		if err := themes.SwapWorkspaceFile(app.GetSelectedTheme(), app.GetSelectedComponentType(), slot, selection); err != nil {
+			logging.LogDebug("Error swapping workspace file: %v", err)
+			ui.ShowMessage(fmt.Sprintf("Error: %s", err), "3")
+			return app.Screens.WorkspaceSource
+		}
+
+		ui.ShowMessage(fmt.Sprintf("Replaced %s", slot), "2")
+		return app.Screens.WorkspaceSlots
+
+	case 1, 2:
+		return app.Screens.WorkspaceSlots
+	}
+
+	return app.Screens.WorkspaceSource
+}
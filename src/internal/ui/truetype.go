@@ -0,0 +1,695 @@
+// src/internal/ui/truetype.go
+// Minimal TrueType renderer for the built-in front-end: glyph outlines, metrics and anti-aliased rasterizing
+
+package ui
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ttSupersample is how many samples per pixel each axis of a glyph is rasterized with
+const ttSupersample = 4
+
+// ttMaxComponents caps the parts a composite glyph is resolved into, as components can
+// refer to each other many times over in a broken font
+const ttMaxComponents = 256
+
+// ttFont is a TrueType font with the tables needed to draw text
+type ttFont struct {
+	data             []byte
+	tables           map[string][]byte
+	unitsPerEm       float64
+	ascent           float64
+	descent          float64
+	numHMetrics      int
+	indexToLocFormat int16
+	cmap             []byte
+	cmapFormat       uint16
+
+	// Rasterized glyphs by glyph and pixel size
+	cache map[ttGlyphKey]*ttGlyph
+}
+
+// ttGlyphKey identifies a rasterized glyph
+type ttGlyphKey struct {
+	glyph uint16
+	size  int
+}
+
+// ttGlyph is a rasterized glyph: its coverage mask, with bounds relative to the pen
+// position on the baseline, and how far the pen moves on
+type ttGlyph struct {
+	mask    *image.Alpha
+	advance int
+}
+
+// ttPoint is a point of a glyph outline in font units
+type ttPoint struct {
+	x, y    float64
+	onCurve bool
+}
+
+// readTTFont reads a .ttf file with glyf outlines. CFF-flavored OpenType fonts aren't supported.
+func readTTFont(path string) (*ttFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTTFont(data)
+}
+
+// parseTTFont reads the tables of font data
+func parseTTFont(data []byte) (*ttFont, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("not a font file")
+	}
+
+	offset := uint32(0)
+	if string(data[:4]) == "ttcf" {
+		if len(data) < 16 {
+			return nil, fmt.Errorf("truncated font collection")
+		}
+		offset = binary.BigEndian.Uint32(data[12:16])
+	}
+	if int(offset)+12 > len(data) {
+		return nil, fmt.Errorf("truncated font file")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(data[offset+4:]))
+	dir := data[offset+12:]
+	if len(dir) < numTables*16 {
+		return nil, fmt.Errorf("truncated font table directory")
+	}
+
+	font := &ttFont{data: data, tables: make(map[string][]byte), cache: make(map[ttGlyphKey]*ttGlyph)}
+	for i := 0; i < numTables; i++ {
+		record := dir[i*16:]
+		start := binary.BigEndian.Uint32(record[8:])
+		length := binary.BigEndian.Uint32(record[12:])
+		if uint64(start)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("font table %s runs past the end of the file", record[:4])
+		}
+		font.tables[string(record[:4])] = data[start : start+length]
+	}
+
+	for _, tag := range []string{"head", "hhea", "hmtx", "loca", "glyf", "cmap"} {
+		if font.tables[tag] == nil {
+			return nil, fmt.Errorf("font has no %s table", tag)
+		}
+	}
+
+	head, hhea := font.tables["head"], font.tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 {
+		return nil, fmt.Errorf("truncated font header")
+	}
+	font.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	font.indexToLocFormat = int16(binary.BigEndian.Uint16(head[50:]))
+	font.ascent = float64(int16(binary.BigEndian.Uint16(hhea[4:])))
+	font.descent = float64(int16(binary.BigEndian.Uint16(hhea[6:])))
+	font.numHMetrics = int(binary.BigEndian.Uint16(hhea[34:]))
+	if font.unitsPerEm == 0 || font.numHMetrics == 0 {
+		return nil, fmt.Errorf("invalid font header")
+	}
+
+	if err := font.findCmap(); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+// findCmap picks the font's Unicode character map, preferring full Unicode (format 12)
+// subtables over BMP-only ones (format 4)
+func (f *ttFont) findCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return fmt.Errorf("font has no character map")
+	}
+
+	numTables := int(binary.BigEndian.Uint16(cmap[2:]))
+	for i := 0; i < numTables && 4+i*8+8 <= len(cmap); i++ {
+		record := cmap[4+i*8:]
+		platform := binary.BigEndian.Uint16(record)
+		encoding := binary.BigEndian.Uint16(record[2:])
+		offset := int(binary.BigEndian.Uint32(record[4:]))
+		if offset+2 > len(cmap) {
+			continue
+		}
+		if platform != 0 && !(platform == 3 && (encoding == 1 || encoding == 10)) {
+			continue
+		}
+
+		format := binary.BigEndian.Uint16(cmap[offset:])
+		if (format == 12 || format == 4) && format > f.cmapFormat {
+			f.cmap, f.cmapFormat = cmap[offset:], format
+		}
+	}
+	if f.cmap == nil {
+		return fmt.Errorf("font has no Unicode character map")
+	}
+	return nil
+}
+
+// glyphIndex returns the glyph for a character, 0 (the missing glyph) when the font has none
+func (f *ttFont) glyphIndex(r rune) uint16 {
+	sub := f.cmap
+	switch f.cmapFormat {
+	case 4:
+		if r > 0xFFFF || len(sub) < 14 {
+			return 0
+		}
+		segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+		ends := 14
+		starts := 16 + segCount*2
+		deltas := starts + segCount*2
+		rangeOffsets := deltas + segCount*2
+		if len(sub) < rangeOffsets+segCount*2 {
+			return 0
+		}
+
+		i := sort.Search(segCount, func(i int) bool {
+			return rune(binary.BigEndian.Uint16(sub[ends+i*2:])) >= r
+		})
+		if i == segCount {
+			return 0
+		}
+		first := rune(binary.BigEndian.Uint16(sub[starts+i*2:]))
+		if r < first {
+			return 0
+		}
+
+		delta := binary.BigEndian.Uint16(sub[deltas+i*2:])
+		rangeOffset := int(binary.BigEndian.Uint16(sub[rangeOffsets+i*2:]))
+		if rangeOffset == 0 {
+			return uint16(r) + delta
+		}
+		at := rangeOffsets + i*2 + rangeOffset + int(r-first)*2
+		if at+2 > len(sub) {
+			return 0
+		}
+		if g := binary.BigEndian.Uint16(sub[at:]); g != 0 {
+			return g + delta
+		}
+		return 0
+
+	case 12:
+		if len(sub) < 16 {
+			return 0
+		}
+		groups := int64(binary.BigEndian.Uint32(sub[12:]))
+		if int64(len(sub)) < 16+groups*12 {
+			return 0
+		}
+		i := sort.Search(int(groups), func(i int) bool {
+			return rune(binary.BigEndian.Uint32(sub[16+i*12+4:])) >= r
+		})
+		if i == int(groups) {
+			return 0
+		}
+		group := sub[16+i*12:]
+		first := rune(binary.BigEndian.Uint32(group))
+		if r < first {
+			return 0
+		}
+		return uint16(binary.BigEndian.Uint32(group[8:]) + uint32(r-first))
+	}
+	return 0
+}
+
+// advanceWidth returns how far the pen moves after a glyph, in font units
+func (f *ttFont) advanceWidth(glyph uint16) float64 {
+	hmtx := f.tables["hmtx"]
+	i := int(glyph)
+	if i >= f.numHMetrics {
+		i = f.numHMetrics - 1
+	}
+	if len(hmtx) < i*4+2 {
+		return 0
+	}
+	return float64(binary.BigEndian.Uint16(hmtx[i*4:]))
+}
+
+// glyphData returns a glyph's entry in the glyf table, nil for empty glyphs like space
+func (f *ttFont) glyphData(glyph uint16) []byte {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+	var start, end int
+	i := int(glyph)
+	if f.indexToLocFormat == 0 {
+		if len(loca) < i*2+4 {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint16(loca[i*2:])) * 2
+		end = int(binary.BigEndian.Uint16(loca[i*2+2:])) * 2
+	} else {
+		if len(loca) < i*4+8 {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint32(loca[i*4:]))
+		end = int(binary.BigEndian.Uint32(loca[i*4+4:]))
+	}
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+// glyphContours returns the outline of a glyph as contours of points in font units.
+// Composite glyphs are resolved into their parts, depth and components limit runaway
+// references: components is how many parts may still be resolved.
+func (f *ttFont) glyphContours(glyph uint16, depth int, components *int) [][]ttPoint {
+	data := f.glyphData(glyph)
+	if len(data) < 10 || depth > 8 {
+		return nil
+	}
+
+	numContours := int(int16(binary.BigEndian.Uint16(data)))
+	if numContours < 0 {
+		return f.compositeContours(data[10:], depth, components)
+	}
+	return simpleContours(data[10:], numContours)
+}
+
+// simpleContours decodes the points of a simple glyph
+func simpleContours(data []byte, numContours int) [][]ttPoint {
+	if len(data) < numContours*2+2 {
+		return nil
+	}
+
+	ends := make([]int, numContours)
+	for i := range ends {
+		ends[i] = int(binary.BigEndian.Uint16(data[i*2:]))
+	}
+	if numContours == 0 {
+		return nil
+	}
+	numPoints := ends[numContours-1] + 1
+
+	pos := numContours * 2
+	instructions := int(binary.BigEndian.Uint16(data[pos:]))
+	pos += 2 + instructions
+
+	// Flags, with repeats expanded
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		if pos >= len(data) {
+			return nil
+		}
+		flag := data[pos]
+		pos++
+		flags = append(flags, flag)
+		if flag&0x08 != 0 {
+			if pos >= len(data) {
+				return nil
+			}
+			for repeat := int(data[pos]); repeat > 0 && len(flags) < numPoints; repeat-- {
+				flags = append(flags, flag)
+			}
+			pos++
+		}
+	}
+
+	// Coordinates are deltas, short (one byte with a sign flag) or long (two bytes)
+	readCoords := func(shortFlag, sameFlag byte) []float64 {
+		coords := make([]float64, numPoints)
+		value := 0
+		for i, flag := range flags {
+			switch {
+			case flag&shortFlag != 0:
+				if pos >= len(data) {
+					return nil
+				}
+				delta := int(data[pos])
+				pos++
+				if flag&sameFlag == 0 {
+					delta = -delta
+				}
+				value += delta
+			case flag&sameFlag == 0:
+				if pos+2 > len(data) {
+					return nil
+				}
+				value += int(int16(binary.BigEndian.Uint16(data[pos:])))
+				pos += 2
+			}
+			coords[i] = float64(value)
+		}
+		return coords
+	}
+	xs := readCoords(0x02, 0x10)
+	ys := readCoords(0x04, 0x20)
+	if xs == nil || ys == nil {
+		return nil
+	}
+
+	contours := make([][]ttPoint, 0, numContours)
+	start := 0
+	for _, end := range ends {
+		if end < start || end >= numPoints {
+			return contours
+		}
+		contour := make([]ttPoint, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			contour = append(contour, ttPoint{x: xs[i], y: ys[i], onCurve: flags[i]&0x01 != 0})
+		}
+		contours = append(contours, contour)
+		start = end + 1
+	}
+	return contours
+}
+
+// compositeContours resolves a composite glyph from its components, each moved and scaled
+func (f *ttFont) compositeContours(data []byte, depth int, components *int) [][]ttPoint {
+	var contours [][]ttPoint
+	pos := 0
+	for {
+		if pos+4 > len(data) || *components <= 0 {
+			return contours
+		}
+		*components--
+		flags := binary.BigEndian.Uint16(data[pos:])
+		component := binary.BigEndian.Uint16(data[pos+2:])
+		pos += 4
+
+		// Only x/y offsets are supported, point matching is rare in UI fonts
+		var dx, dy float64
+		if flags&0x0001 != 0 {
+			if pos+4 > len(data) {
+				return contours
+			}
+			dx = float64(int16(binary.BigEndian.Uint16(data[pos:])))
+			dy = float64(int16(binary.BigEndian.Uint16(data[pos+2:])))
+			pos += 4
+		} else {
+			if pos+2 > len(data) {
+				return contours
+			}
+			dx = float64(int8(data[pos]))
+			dy = float64(int8(data[pos+1]))
+			pos += 2
+		}
+
+		f2dot14 := func() float64 {
+			v := float64(int16(binary.BigEndian.Uint16(data[pos:]))) / 16384
+			pos += 2
+			return v
+		}
+		a, b, c, d := 1.0, 0.0, 0.0, 1.0
+		switch {
+		case flags&0x0008 != 0 && pos+2 <= len(data):
+			a = f2dot14()
+			d = a
+		case flags&0x0040 != 0 && pos+4 <= len(data):
+			a = f2dot14()
+			d = f2dot14()
+		case flags&0x0080 != 0 && pos+8 <= len(data):
+			a, b, c, d = f2dot14(), f2dot14(), f2dot14(), f2dot14()
+		}
+
+		for _, contour := range f.glyphContours(component, depth+1, components) {
+			moved := make([]ttPoint, len(contour))
+			for i, p := range contour {
+				moved[i] = ttPoint{x: a*p.x + c*p.y + dx, y: b*p.x + d*p.y + dy, onCurve: p.onCurve}
+			}
+			contours = append(contours, moved)
+		}
+
+		if flags&0x0020 == 0 {
+			return contours
+		}
+	}
+}
+
+// ttEdge is a line segment of a flattened outline, in supersampled pixels
+type ttEdge struct {
+	x0, y0, x1, y1 float64
+}
+
+// flattenContour turns a contour of on- and off-curve points into line segments.
+// Two off-curve points in a row imply an on-curve point halfway between them.
+func flattenContour(contour []ttPoint, scale float64, edges []ttEdge) []ttEdge {
+	n := len(contour)
+	if n < 2 {
+		return edges
+	}
+
+	// Start on an on-curve point, or halfway between two off-curve ones
+	startIdx := -1
+	for i, p := range contour {
+		if p.onCurve {
+			startIdx = i
+			break
+		}
+	}
+	var start ttPoint
+	if startIdx >= 0 {
+		start = contour[startIdx]
+	} else {
+		startIdx = 0
+		a, b := contour[0], contour[1%n]
+		start = ttPoint{x: (a.x + b.x) / 2, y: (a.y + b.y) / 2, onCurve: true}
+	}
+
+	// Font units have y up, pixels have y down
+	px := func(p ttPoint) (float64, float64) { return p.x * scale, -p.y * scale }
+	lineTo := func(from, to ttPoint) {
+		x0, y0 := px(from)
+		x1, y1 := px(to)
+		edges = append(edges, ttEdge{x0, y0, x1, y1})
+	}
+	curveTo := func(from, ctrl, to ttPoint) {
+		x0, y0 := px(from)
+		cx, cy := px(ctrl)
+		x1, y1 := px(to)
+		steps := int(math.Hypot(x1-x0, y1-y0)/4) + 2
+		if steps > 32 {
+			steps = 32
+		}
+		prevX, prevY := x0, y0
+		for i := 1; i <= steps; i++ {
+			t := float64(i) / float64(steps)
+			mt := 1 - t
+			x := mt*mt*x0 + 2*mt*t*cx + t*t*x1
+			y := mt*mt*y0 + 2*mt*t*cy + t*t*y1
+			edges = append(edges, ttEdge{prevX, prevY, x, y})
+			prevX, prevY = x, y
+		}
+	}
+
+	current := start
+	var ctrl *ttPoint
+	for k := 1; k <= n; k++ {
+		p := contour[(startIdx+k)%n]
+		if k == n {
+			p = start
+		}
+
+		switch {
+		case p.onCurve && ctrl == nil:
+			lineTo(current, p)
+			current = p
+		case p.onCurve:
+			curveTo(current, *ctrl, p)
+			current, ctrl = p, nil
+		case ctrl == nil:
+			q := p
+			ctrl = &q
+		default:
+			mid := ttPoint{x: (ctrl.x + p.x) / 2, y: (ctrl.y + p.y) / 2, onCurve: true}
+			curveTo(current, *ctrl, mid)
+			q := p
+			current, ctrl = mid, &q
+		}
+	}
+	if ctrl != nil {
+		curveTo(current, *ctrl, start)
+	}
+	return edges
+}
+
+// rasterize fills edges with the non-zero winding rule into a coverage mask, sampling
+// each pixel ttSupersample times along each axis
+func rasterize(edges []ttEdge, bounds image.Rectangle) *image.Alpha {
+	mask := image.NewAlpha(bounds)
+	width := bounds.Dx()
+	if width <= 0 || bounds.Dy() <= 0 {
+		return mask
+	}
+
+	type crossing struct {
+		x       float64
+		winding int
+	}
+	coverage := make([]int, width)
+	var crossings []crossing
+	maxCoverage := ttSupersample * ttSupersample
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for i := range coverage {
+			coverage[i] = 0
+		}
+
+		for sy := 0; sy < ttSupersample; sy++ {
+			sampleY := float64(y) + (float64(sy)+0.5)/ttSupersample
+
+			crossings = crossings[:0]
+			for _, e := range edges {
+				winding := 1
+				y0, y1, x0, x1 := e.y0, e.y1, e.x0, e.x1
+				if y0 > y1 {
+					y0, y1, x0, x1 = y1, y0, x1, x0
+					winding = -1
+				}
+				if sampleY < y0 || sampleY >= y1 {
+					continue
+				}
+				x := x0 + (sampleY-y0)/(y1-y0)*(x1-x0)
+				crossings = append(crossings, crossing{x, winding})
+			}
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			// Fill the samples between crossings where the winding number isn't zero
+			winding := 0
+			for i := 0; i+1 < len(crossings); i++ {
+				winding += crossings[i].winding
+				if winding == 0 {
+					continue
+				}
+				from := crossings[i].x - float64(bounds.Min.X)
+				to := crossings[i+1].x - float64(bounds.Min.X)
+				first := int(math.Ceil(from*ttSupersample - 0.5))
+				last := int(math.Ceil(to*ttSupersample-0.5)) - 1
+				if first < 0 {
+					first = 0
+				}
+				if last >= width*ttSupersample {
+					last = width*ttSupersample - 1
+				}
+				for s := first; s <= last; s++ {
+					coverage[s/ttSupersample]++
+				}
+			}
+		}
+
+		row := mask.Pix[(y-bounds.Min.Y)*mask.Stride:]
+		for x, c := range coverage {
+			row[x] = uint8(c * 255 / maxCoverage)
+		}
+	}
+	return mask
+}
+
+// glyph returns a glyph rasterized at a pixel size (the em height), from the cache when
+// it was drawn before
+func (f *ttFont) glyph(index uint16, size int) *ttGlyph {
+	key := ttGlyphKey{index, size}
+	if g, ok := f.cache[key]; ok {
+		return g
+	}
+
+	scale := float64(size) / f.unitsPerEm
+	g := &ttGlyph{advance: int(math.Round(f.advanceWidth(index) * scale))}
+
+	var edges []ttEdge
+	components := ttMaxComponents
+	for _, contour := range f.glyphContours(index, 0, &components) {
+		edges = flattenContour(contour, scale, edges)
+	}
+	if len(edges) > 0 {
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, e := range edges {
+			minX = math.Min(minX, math.Min(e.x0, e.x1))
+			maxX = math.Max(maxX, math.Max(e.x0, e.x1))
+			minY = math.Min(minY, math.Min(e.y0, e.y1))
+			maxY = math.Max(maxY, math.Max(e.y0, e.y1))
+		}
+		bounds := image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+
+		// Outlines far outside the em square only come from broken fonts, and would need
+		// huge masks
+		bounds = bounds.Intersect(image.Rect(-4*size, -4*size, 4*size, 4*size))
+		g.mask = rasterize(edges, bounds)
+	}
+
+	f.cache[key] = g
+	return g
+}
+
+// lineHeight returns the distance between baselines of lines of text at a pixel size
+func (f *ttFont) lineHeight(size int) int {
+	return int(math.Ceil((f.ascent - f.descent) * float64(size) / f.unitsPerEm))
+}
+
+// ascentPixels returns how far text rises above its baseline at a pixel size
+func (f *ttFont) ascentPixels(size int) int {
+	return int(math.Round(f.ascent * float64(size) / f.unitsPerEm))
+}
+
+// measure returns the width of a line of text at a pixel size
+func (f *ttFont) measure(text string, size int) int {
+	width := 0
+	for _, r := range text {
+		width += f.glyph(f.glyphIndex(r), size).advance
+	}
+	return width
+}
+
+// drawText draws a line of text with the top of its line box at (x, y)
+func (f *ttFont) drawText(dst draw.Image, x, y int, text string, size int, c color.Color) {
+	src := image.NewUniform(c)
+	baseline := y + f.ascentPixels(size)
+	for _, r := range text {
+		g := f.glyph(f.glyphIndex(r), size)
+		if g.mask != nil {
+			at := g.mask.Bounds().Add(image.Pt(x, baseline))
+			draw.DrawMask(dst, at, src, image.Point{}, g.mask, g.mask.Bounds().Min, draw.Over)
+		}
+		x += g.advance
+	}
+}
+
+// truncate shortens text with an ellipsis so it fits in width pixels
+func (f *ttFont) truncate(text string, size int, width int) string {
+	if f.measure(text, size) <= width {
+		return text
+	}
+	ellipsis := "..."
+	for len(text) > 0 {
+		_, n := utf8.DecodeLastRuneInString(text)
+		text = text[:len(text)-n]
+		if trimmed := strings.TrimRight(text, " "); f.measure(trimmed+ellipsis, size) <= width {
+			return trimmed + ellipsis
+		}
+	}
+	return ellipsis
+}
+
+// wrap breaks text into lines at most width pixels wide, at spaces where possible and
+// at the line breaks already in it
+func (f *ttFont) wrap(text string, size int, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if f.measure(candidate, size) <= width || line == "" {
+				line = candidate
+				continue
+			}
+			lines = append(lines, line)
+			line = word
+		}
+		lines = append(lines, f.truncate(line, size, width))
+	}
+	return lines
+}
@@ -0,0 +1,360 @@
+package ui
+
+import (
+	"encoding/binary"
+	"image"
+	"image/color"
+	"os"
+	"testing"
+
+	"nextui-themes/internal/testfont"
+)
+
+// sampleFonts returns the sample font in each of the layouts fonts come in
+func sampleFonts() map[string]*testfont.Font {
+	fonts := make(map[string]*testfont.Font)
+	for _, variant := range []struct {
+		name                             string
+		longLoca, rangeOffsets, format12 bool
+	}{
+		{"short loca", false, false, true},
+		{"long loca", true, false, true},
+		{"range offsets", false, true, true},
+		{"format 4 only", false, false, false},
+	} {
+		font := testfont.Sample()
+		font.LongLoca, font.RangeOffsets, font.Format12 = variant.longLoca, variant.rangeOffsets, variant.format12
+		fonts[variant.name] = font
+	}
+	return fonts
+}
+
+// exerciseFont draws every glyph of a font, and a few past its end, as the front-end would
+func exerciseFont(font *ttFont) {
+	numGlyphs := len(font.tables["loca"]) / 2
+	for g := 0; g < numGlyphs+2 && g <= 0xFFFF; g++ {
+		font.glyph(uint16(g), 24)
+	}
+
+	text := "AÁ é Ж O🎮 \x00￿"
+	canvas := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	font.drawText(canvas, -8, -4, text, 20, color.White)
+	font.wrap(text, 12, 40)
+	font.truncate(text, 12, 30)
+	font.lineHeight(16)
+}
+
+func TestParseTTFont(t *testing.T) {
+	for name, sample := range sampleFonts() {
+		t.Run(name, func(t *testing.T) {
+			font, err := parseTTFont(sample.Bytes())
+			if err != nil {
+				t.Fatalf("parseTTFont: %v", err)
+			}
+
+			for r, want := range sample.Chars {
+				if r > 0xFFFF && !sample.Format12 {
+					want = testfont.NotDef
+				}
+				if got := font.glyphIndex(r); got != want {
+					t.Errorf("glyphIndex(%U) = %d, want %d", r, got, want)
+				}
+			}
+			for _, r := range []rune{0, 'C', 'z', 0xFFFF, 0x10FFFF} {
+				if got := font.glyphIndex(r); got != testfont.NotDef {
+					t.Errorf("glyphIndex(%U) = %d, want the missing glyph", r, got)
+				}
+			}
+
+			for g, glyph := range sample.Glyphs {
+				if got := font.advanceWidth(uint16(g)); got != float64(glyph.Advance) {
+					t.Errorf("advanceWidth(%d) = %v, want %d", g, got, glyph.Advance)
+				}
+			}
+			if got := font.lineHeight(100); got != 100 {
+				t.Errorf("lineHeight(100) = %d, want 100", got)
+			}
+		})
+	}
+}
+
+func TestGlyphContours(t *testing.T) {
+	sample := testfont.Sample()
+	font, err := parseTTFont(sample.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Simple glyphs decode to the points they were written with
+	for _, g := range []uint16{testfont.NotDef, testfont.LetterA, testfont.LetterO, testfont.Gamepad} {
+		components := ttMaxComponents
+		contours := font.glyphContours(g, 0, &components)
+		want := sample.Glyphs[g].Contours
+		if len(contours) != len(want) {
+			t.Fatalf("glyph %d has %d contours, want %d", g, len(contours), len(want))
+		}
+		for i, contour := range contours {
+			if len(contour) != len(want[i]) {
+				t.Fatalf("glyph %d contour %d has %d points, want %d", g, i, len(contour), len(want[i]))
+			}
+			for j, p := range contour {
+				w := want[i][j]
+				if p != (ttPoint{float64(w.X), float64(w.Y), w.OnCurve}) {
+					t.Errorf("glyph %d point %d.%d = %+v, want %+v", g, i, j, p, w)
+				}
+			}
+		}
+	}
+
+	// Composites are their parts, moved and scaled
+	components := ttMaxComponents
+	contours := font.glyphContours(testfont.LetterEAcute, 0, &components)
+	if len(contours) != 2 {
+		t.Fatalf("composite has %d contours, want 2", len(contours))
+	}
+	if got := contours[0][2]; got != (ttPoint{225, 525, false}) {
+		t.Errorf("scaled point = %+v, want {225 525 false}", got)
+	}
+	if got := contours[1][1]; got != (ttPoint{300, 750, true}) {
+		t.Errorf("moved point = %+v, want {300 750 true}", got)
+	}
+
+	if g := font.glyph(testfont.Space, 24); g.mask != nil || g.advance != 6 {
+		t.Errorf("space glyph = %+v, want no mask and an advance of 6", g)
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	font, err := parseTTFont(testfont.Sample().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := font.measure("AO A", 20); got != 12+12+5+12 {
+		t.Errorf("measure = %d, want %d", got, 12+12+5+12)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	font.drawText(canvas, 0, 0, "A", 100, color.White)
+
+	// The legs of the A are drawn, the hole above its bar isn't
+	baseline := font.ascentPixels(100)
+	if c := canvas.RGBAAt(3, baseline-1); c.A != 255 {
+		t.Errorf("left leg of the A isn't drawn: %+v", c)
+	}
+	if c := canvas.RGBAAt(29, baseline-27); c.A != 0 {
+		t.Errorf("hole of the A is drawn over: %+v", c)
+	}
+	if c := canvas.RGBAAt(80, 50); c.A != 0 {
+		t.Errorf("drawn past the A: %+v", c)
+	}
+}
+
+func TestParseTTFontMalformed(t *testing.T) {
+	valid := testfont.Sample().Bytes()
+
+	// withTable rebuilds the sample font with a table changed
+	withTable := func(tag string, change func(table []byte) []byte) []byte {
+		font, err := parseTTFont(valid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tables := make(map[string][]byte)
+		for tag, table := range font.tables {
+			tables[tag] = append([]byte(nil), table...)
+		}
+		if table := change(tables[tag]); table != nil {
+			tables[tag] = table
+		} else {
+			delete(tables, tag)
+		}
+		return testfont.Assemble(tables)
+	}
+	withGlyph := func(glyph testfont.Glyph) []byte {
+		sample := testfont.Sample()
+		sample.Glyphs[testfont.LetterA] = glyph
+		return sample.Bytes()
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"empty", nil, true},
+		{"too short", valid[:11], true},
+		{"truncated directory", valid[:20], true},
+		{"truncated collection", []byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x01"), true},
+		{"collection offset past the end", append([]byte("ttcf\x00\x01\x00\x00\x00\x00\x00\x01\xff\xff\xff\xf0"), valid...), true},
+		{"no glyf table", withTable("glyf", func([]byte) []byte { return nil }), true},
+		{"no cmap table", withTable("cmap", func([]byte) []byte { return nil }), true},
+		{"short head", withTable("head", func(head []byte) []byte { return head[:20] }), true},
+		{"zero units per em", withTable("head", func(head []byte) []byte {
+			binary.BigEndian.PutUint16(head[18:], 0)
+			return head
+		}), true},
+		{"no horizontal metrics", withTable("hhea", func(hhea []byte) []byte {
+			binary.BigEndian.PutUint16(hhea[34:], 0)
+			return hhea
+		}), true},
+		{"no Unicode cmap", withTable("cmap", func(cmap []byte) []byte {
+			for i := 0; i < int(binary.BigEndian.Uint16(cmap[2:])); i++ {
+				binary.BigEndian.PutUint16(cmap[4+i*8:], 1)
+			}
+			return cmap
+		}), true},
+		{"cmap offsets past the end", withTable("cmap", func(cmap []byte) []byte {
+			binary.BigEndian.PutUint32(cmap[8:], 0xFFFFFFF0)
+			return cmap
+		}), false},
+		{"too many cmap segments", withTable("cmap", func(cmap []byte) []byte {
+			format4 := int(binary.BigEndian.Uint32(cmap[8:]))
+			binary.BigEndian.PutUint16(cmap[format4+6:], 0xFFFE)
+			return cmap
+		}), false},
+		{"too many cmap groups", withTable("cmap", func(cmap []byte) []byte {
+			format12 := int(binary.BigEndian.Uint32(cmap[24:]))
+			binary.BigEndian.PutUint32(cmap[format12+12:], 0xFFFFFFFF)
+			return cmap
+		}), false},
+		{"more metrics than glyphs", withTable("hhea", func(hhea []byte) []byte {
+			binary.BigEndian.PutUint16(hhea[34:], 0xFFFF)
+			return hhea
+		}), false},
+		{"loca past glyf", withTable("loca", func(loca []byte) []byte {
+			for i := 2; i < len(loca); i += 2 {
+				binary.BigEndian.PutUint16(loca[i:], 0xFFFF)
+			}
+			return loca
+		}), false},
+		{"short loca", withTable("loca", func(loca []byte) []byte { return loca[:3] }), false},
+		{"long loca flag on a short loca", withTable("head", func(head []byte) []byte {
+			binary.BigEndian.PutUint16(head[50:], 1)
+			return head
+		}), false},
+		{"composite cycle", withGlyph(testfont.Glyph{Advance: 600, Components: []testfont.Component{
+			{Glyph: testfont.LetterA}, {Glyph: testfont.LetterA, DX: 10},
+		}}), false},
+		{"huge outline", withGlyph(testfont.Glyph{Advance: 600, Contours: [][]testfont.Point{
+			testfont.Box(-32768, -32768, 32767, 32767),
+		}}), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := parseTTFont(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTTFont: %v", err)
+			}
+			exerciseFont(font)
+		})
+	}
+}
+
+func TestHugeOutlineMaskIsBounded(t *testing.T) {
+	sample := testfont.Sample()
+	sample.UnitsPerEm = 16
+	sample.Glyphs[testfont.LetterA] = testfont.Glyph{Advance: 600, Contours: [][]testfont.Point{
+		testfont.Box(-32768, -32768, 32767, 32767),
+	}}
+	font, err := parseTTFont(sample.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := font.glyph(testfont.LetterA, 40)
+	if g.mask == nil {
+		t.Fatal("expected a mask")
+	}
+	if bounds := g.mask.Bounds(); bounds.Dx() > 8*40 || bounds.Dy() > 8*40 {
+		t.Errorf("mask is %v, want it limited to a few ems", bounds)
+	}
+}
+
+func TestComponentsAreLimited(t *testing.T) {
+	// Each level refers to the one below ten times, which would be 10^8 parts unchecked
+	sample := testfont.Sample()
+	sample.Glyphs = sample.Glyphs[:1]
+	for level := 1; level <= 9; level++ {
+		var parts []testfont.Component
+		for i := 0; i < 10; i++ {
+			parts = append(parts, testfont.Component{Glyph: uint16(level - 1), DX: int16(i)})
+		}
+		sample.Glyphs = append(sample.Glyphs, testfont.Glyph{Advance: 500, Components: parts})
+	}
+	sample.Chars = map[rune]uint16{'A': 9}
+
+	font, err := parseTTFont(sample.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	components := ttMaxComponents
+	if contours := font.glyphContours(9, 0, &components); len(contours) > ttMaxComponents*2 {
+		t.Errorf("resolved %d contours, want at most %d", len(contours), ttMaxComponents*2)
+	}
+}
+
+func TestParseTTFontCorrupted(t *testing.T) {
+	for name, sample := range sampleFonts() {
+		valid := sample.Bytes()
+		t.Run(name, func(t *testing.T) {
+			// Every truncation and every byte set to its extremes either fails to parse or
+			// draws without panicking
+			for n := 0; n < len(valid); n++ {
+				if font, err := parseTTFont(valid[:n]); err == nil {
+					exerciseFont(font)
+				}
+			}
+			for i := range valid {
+				for _, b := range []byte{0x00, 0x7F, 0x80, 0xFF} {
+					data := append([]byte(nil), valid...)
+					data[i] = b
+					if font, err := parseTTFont(data); err == nil {
+						exerciseFont(font)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinFonts(t *testing.T) {
+	for _, path := range builtinFontPaths {
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		t.Run(path, func(t *testing.T) {
+			font, err := readTTFont(path)
+			if err != nil {
+				t.Fatalf("readTTFont: %v", err)
+			}
+			if font.glyphIndex('A') == 0 {
+				t.Error("font has no glyph for A")
+			}
+			exerciseFont(font)
+		})
+	}
+}
+
+func FuzzParseTTFont(f *testing.F) {
+	for _, sample := range sampleFonts() {
+		f.Add(sample.Bytes())
+	}
+	for _, path := range builtinFontPaths {
+		if data, err := os.ReadFile(path); err == nil {
+			f.Add(data)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if font, err := parseTTFont(data); err == nil {
+			exerciseFont(font)
+		}
+	})
+}
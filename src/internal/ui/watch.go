@@ -0,0 +1,57 @@
+// src/internal/ui/watch.go
+// Polls directories for changes so galleries can pick up packages copied in over USB
+
+package ui
+
+import (
+	"os"
+	"time"
+)
+
+// watchPollInterval is how often watched directories are checked; inotify isn't
+// reliable on the SD card's filesystem, so directory mtimes are polled instead
+const watchPollInterval = time.Second
+
+// GalleryRefreshCode is returned by galleries when a watched directory changed
+// and the screen should rebuild its items
+const GalleryRefreshCode = 10
+
+// DirWatcher detects entries being added to or removed from a set of directories
+type DirWatcher struct {
+	dirs   []string
+	mtimes map[string]time.Time
+}
+
+// NewDirWatcher snapshots the current state of the given directories
+func NewDirWatcher(dirs ...string) *DirWatcher {
+	w := &DirWatcher{
+		dirs:   dirs,
+		mtimes: make(map[string]time.Time),
+	}
+	for _, dir := range dirs {
+		w.mtimes[dir] = dirModTime(dir)
+	}
+	return w
+}
+
+// Changed reports whether any directory changed since the snapshot or the last call
+func (w *DirWatcher) Changed() bool {
+	changed := false
+	for _, dir := range w.dirs {
+		mtime := dirModTime(dir)
+		if !mtime.Equal(w.mtimes[dir]) {
+			w.mtimes[dir] = mtime
+			changed = true
+		}
+	}
+	return changed
+}
+
+// dirModTime returns a directory's modification time, or the zero time if it is missing
+func dirModTime(dir string) time.Time {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}